@@ -0,0 +1,116 @@
+// Package aircraft provides reference data about common aircraft types.
+// Planes in the fleet only store a free-text model name (e.g. "Airbus A320"),
+// so this catalogue is used to derive physical characteristics such as
+// wingspan, floor space and the ICAO aerodrome reference code needed for
+// stand and hangar planning, as well as the typical cruise performance used
+// for block time and emission estimates.
+package aircraft
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Type describes the characteristics of one aircraft type.
+type Type struct {
+	Name      string   // Canonical type name (e.g. "Airbus A320")
+	ICAO      string   // ICAO type designator (e.g. "A320")
+	IATA      string   // IATA type code as used in schedules (e.g. "320")
+	WingspanM float64  // Wingspan in meters
+	LengthM   float64  // Overall length in meters
+	CruiseKt  float64  // Typical cruise speed in knots
+	FuelKgH   float64  // Typical fuel burn at cruise in kg per hour
+	Seats     int      // Typical number of seats in a two-class layout
+	aliases   []string // Normalized model name fragments identifying the type
+}
+
+// CodeLetter returns the ICAO aerodrome reference code letter (A-F) of the
+// type, which is determined by its wingspan and used to size stands and gates.
+func (t Type) CodeLetter() string {
+	switch {
+	case t.WingspanM < 15:
+		return "A"
+	case t.WingspanM < 24:
+		return "B"
+	case t.WingspanM < 36:
+		return "C"
+	case t.WingspanM < 52:
+		return "D"
+	case t.WingspanM < 65:
+		return "E"
+	default:
+		return "F"
+	}
+}
+
+// sqFtPerSqM converts square meters to square feet.
+const sqFtPerSqM = 10.7639
+
+// FootprintSqFt returns the floor space in square feet the type needs when
+// parked, approximated by the rectangle spanned by wingspan and length.
+func (t Type) FootprintSqFt() float64 {
+	return t.WingspanM * t.LengthM * sqFtPerSqM
+}
+
+// catalogue lists the supported aircraft types. More specific variants must
+// have longer aliases than the generic family entries so they win the lookup.
+var catalogue = []Type{
+	{Name: "Airbus A318", ICAO: "A318", IATA: "318", WingspanM: 34.1, LengthM: 31.4, CruiseKt: 447, FuelKgH: 2200, Seats: 132, aliases: []string{"a318"}},
+	{Name: "Airbus A319", ICAO: "A319", IATA: "319", WingspanM: 35.8, LengthM: 33.8, CruiseKt: 447, FuelKgH: 2300, Seats: 140, aliases: []string{"a319"}},
+	{Name: "Airbus A320", ICAO: "A320", IATA: "320", WingspanM: 35.8, LengthM: 37.6, CruiseKt: 447, FuelKgH: 2500, Seats: 165, aliases: []string{"a320"}},
+	{Name: "Airbus A321", ICAO: "A321", IATA: "321", WingspanM: 35.8, LengthM: 44.5, CruiseKt: 447, FuelKgH: 2900, Seats: 200, aliases: []string{"a321"}},
+	{Name: "Airbus A330", ICAO: "A333", IATA: "333", WingspanM: 60.3, LengthM: 63.7, CruiseKt: 470, FuelKgH: 5800, Seats: 290, aliases: []string{"a330"}},
+	{Name: "Airbus A350", ICAO: "A359", IATA: "359", WingspanM: 64.8, LengthM: 66.8, CruiseKt: 488, FuelKgH: 5800, Seats: 315, aliases: []string{"a350"}},
+	{Name: "Airbus A380", ICAO: "A388", IATA: "388", WingspanM: 79.8, LengthM: 72.7, CruiseKt: 488, FuelKgH: 11000, Seats: 525, aliases: []string{"a380"}},
+	{Name: "Boeing 737", WingspanM: 35.8, LengthM: 39.5, CruiseKt: 453, FuelKgH: 2600, Seats: 175, aliases: []string{"737", "b737"}},
+	{Name: "Boeing 737-700", ICAO: "B737", IATA: "73G", WingspanM: 35.8, LengthM: 33.6, CruiseKt: 453, FuelKgH: 2400, Seats: 140, aliases: []string{"737700"}},
+	{Name: "Boeing 737-800", ICAO: "B738", IATA: "738", WingspanM: 35.8, LengthM: 39.5, CruiseKt: 453, FuelKgH: 2600, Seats: 175, aliases: []string{"737800"}},
+	{Name: "Boeing 737 MAX 8", ICAO: "B38M", IATA: "7M8", WingspanM: 35.9, LengthM: 39.5, CruiseKt: 453, FuelKgH: 2200, Seats: 175, aliases: []string{"737max", "737max8"}},
+	{Name: "Boeing 747", ICAO: "B744", IATA: "744", WingspanM: 64.4, LengthM: 70.7, CruiseKt: 490, FuelKgH: 10200, Seats: 416, aliases: []string{"747", "b747"}},
+	{Name: "Boeing 747-8", ICAO: "B748", IATA: "748", WingspanM: 68.4, LengthM: 76.3, CruiseKt: 493, FuelKgH: 9600, Seats: 410, aliases: []string{"7478", "747800"}},
+	{Name: "Boeing 767", ICAO: "B763", IATA: "763", WingspanM: 47.6, LengthM: 54.9, CruiseKt: 459, FuelKgH: 4800, Seats: 240, aliases: []string{"767"}},
+	{Name: "Boeing 777", ICAO: "B772", IATA: "772", WingspanM: 60.9, LengthM: 63.7, CruiseKt: 490, FuelKgH: 6800, Seats: 314, aliases: []string{"777"}},
+	{Name: "Boeing 777-300ER", ICAO: "B77W", IATA: "77W", WingspanM: 64.8, LengthM: 73.9, CruiseKt: 490, FuelKgH: 7500, Seats: 365, aliases: []string{"777300", "77w"}},
+	{Name: "Boeing 787", ICAO: "B789", IATA: "789", WingspanM: 60.1, LengthM: 62.8, CruiseKt: 488, FuelKgH: 5400, Seats: 290, aliases: []string{"787"}},
+	{Name: "Embraer E190", ICAO: "E190", IATA: "E90", WingspanM: 28.7, LengthM: 36.2, CruiseKt: 447, FuelKgH: 1800, Seats: 100, aliases: []string{"e190", "embraer190"}},
+	{Name: "Bombardier CRJ900", ICAO: "CRJ9", IATA: "CR9", WingspanM: 24.9, LengthM: 36.2, CruiseKt: 447, FuelKgH: 1600, Seats: 88, aliases: []string{"crj900", "crj9"}},
+	{Name: "ATR 72", ICAO: "AT76", IATA: "AT7", WingspanM: 27.1, LengthM: 27.2, CruiseKt: 275, FuelKgH: 750, Seats: 70, aliases: []string{"atr72"}},
+	{Name: "De Havilland Dash 8-400", ICAO: "DH8D", IATA: "DH4", WingspanM: 28.4, LengthM: 32.8, CruiseKt: 360, FuelKgH: 1100, Seats: 78, aliases: []string{"q400", "dash8"}},
+}
+
+// Lookup finds the aircraft type matching a free-text model name such as
+// "Boeing 777-300ER" or an ICAO/IATA type code such as "A320" or "77W".
+// The second return value is false if the model is unknown.
+func Lookup(model string) (Type, bool) {
+	key := normalize(model)
+	if key == "" {
+		return Type{}, false
+	}
+
+	var best Type
+	bestLen := 0
+	for _, t := range catalogue {
+		if key == normalize(t.ICAO) || key == normalize(t.IATA) {
+			return t, true
+		}
+		for _, alias := range t.aliases {
+			if len(alias) > bestLen && strings.Contains(key, alias) {
+				best = t
+				bestLen = len(alias)
+			}
+		}
+	}
+
+	return best, bestLen > 0
+}
+
+// normalize lowercases a model name and strips everything but letters and digits.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,255 @@
+// Package apis captures the travel documents of passengers for Advance
+// Passenger Information (APIS). Documents are encrypted at rest, checked at
+// check-in for international flights and exported per flight as UN/EDIFACT
+// PAXLST message for the border authorities.
+package apis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+const ticketConfirmed = "CONFIRMED"
+
+var (
+	// ErrCheckInClosed is returned when a ticket cannot be checked in anymore.
+	ErrCheckInClosed = errors.New("check-in is not possible")
+	// ErrDocumentRequired is returned when an international flight is checked
+	// in without a travel document that is valid on the day of arrival.
+	ErrDocumentRequired = errors.New("a valid travel document is required for international flights")
+	// ErrUnknownDocument is returned for documents that do not belong to the passenger.
+	ErrUnknownDocument = errors.New("travel document not found")
+	// ErrFlightFull is returned when all seats of an overbooked flight are
+	// checked in; the passenger has to see the staff at the gate.
+	ErrFlightFull = errors.New("all seats of the flight are checked in, please see the gate staff")
+)
+
+var (
+	documentNumberPattern = regexp.MustCompile(`^[A-Z0-9]{5,20}$`)
+	countryCodePattern    = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Service manages travel documents and the check-in that depends on them.
+type Service struct {
+	db     database.Database
+	cipher database.FieldCipher
+}
+
+// NewService creates a service encrypting documents with the DefaultCipher.
+func NewService(db database.Database) *Service {
+	return &Service{db: db, cipher: DefaultCipher()}
+}
+
+// Documents returns the travel documents of a user and their companions,
+// oldest first.
+func (s *Service) Documents(userID string) ([]models.TravelDocument, error) {
+	documents, err := s.db.GetTravelDocuments(userID, s.cipher)
+	if documents == nil {
+		documents = []models.TravelDocument{}
+	}
+	return documents, err
+}
+
+// TravellerDocuments returns the travel documents of one traveller, oldest
+// first: the user's own if companionID is empty, else the companion's.
+func (s *Service) TravellerDocuments(userID, companionID string) ([]models.TravelDocument, error) {
+	documents, err := s.Documents(userID)
+	if err != nil {
+		return nil, err
+	}
+	own := []models.TravelDocument{}
+	for _, document := range documents {
+		if document.CompanionID == companionID {
+			own = append(own, document)
+		}
+	}
+	return own, nil
+}
+
+// Document returns a travel document of a user, or nil if it does not exist
+// or belongs to someone else.
+func (s *Service) Document(userID, id string) (*models.TravelDocument, error) {
+	document, err := s.db.GetTravelDocumentByID(id, s.cipher)
+	if err != nil || document == nil || document.AirportUserID != userID {
+		return nil, err
+	}
+	return document, nil
+}
+
+// Create stores a new travel document.
+func (s *Service) Create(document *models.TravelDocument) error {
+	return s.db.CreateTravelDocument(document, s.cipher)
+}
+
+// Update replaces the attributes of a travel document.
+func (s *Service) Update(document models.TravelDocument) error {
+	return s.db.UpdateTravelDocument(document, s.cipher)
+}
+
+// Delete removes a travel document.
+func (s *Service) Delete(id string) error {
+	return s.db.DeleteTravelDocument(id)
+}
+
+// Validate normalizes a travel document to upper case and checks its
+// fields. It returns a message describing the first invalid field, or "".
+func Validate(document *models.TravelDocument, now time.Time) string {
+	document.Type = strings.ToUpper(strings.TrimSpace(document.Type))
+	document.Number = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(document.Number), " ", ""))
+	document.Nationality = strings.ToUpper(strings.TrimSpace(document.Nationality))
+	document.IssuingCountry = strings.ToUpper(strings.TrimSpace(document.IssuingCountry))
+	document.Sex = strings.ToUpper(strings.TrimSpace(document.Sex))
+	y, m, d := document.ExpiryDate.Date()
+	document.ExpiryDate = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	switch {
+	case document.Type != "P" && document.Type != "I":
+		return "Type must be 'P' (passport) or 'I' (identity card)"
+	case !documentNumberPattern.MatchString(document.Number):
+		return "Document number must consist of 5 to 20 letters and digits"
+	case !countryCodePattern.MatchString(document.Nationality):
+		return "Nationality must be an ISO 3166-1 alpha-3 code like 'DEU'"
+	case !countryCodePattern.MatchString(document.IssuingCountry):
+		return "Issuing country must be an ISO 3166-1 alpha-3 code like 'DEU'"
+	case document.Sex != "M" && document.Sex != "F" && document.Sex != "X":
+		return "Sex must be 'M', 'F' or 'X'"
+	case document.ExpiryDate.Before(dateOf(now)):
+		return "Travel document has expired"
+	}
+	return ""
+}
+
+// IsInternational reports whether a flight between two airports crosses a
+// border. Flights between airports of unknown country count as
+// international, as documents cannot be waived for them.
+func IsInternational(from, to models.Airport) bool {
+	return from.Country == "" || !strings.EqualFold(strings.TrimSpace(from.Country), strings.TrimSpace(to.Country))
+}
+
+// ValidFor reports whether a document is still valid on the day the flight
+// arrives.
+func ValidFor(document models.TravelDocument, flight models.Flight) bool {
+	return !dateOf(document.ExpiryDate).Before(dateOf(flight.ScheduledArrival))
+}
+
+// CheckIn checks in a confirmed ticket. International flights require a
+// travel document of the traveller that is valid on arrival: the one given
+// by documentID, or else the first valid one on file. Companions travel
+// with the documents their account keeps for them. Guest passengers of a
+// booking have no documents on file and can only check in for domestic
+// flights. Overbooked flights can only be checked in up to the seats of the
+// plane. The document used is recorded with the ticket and returned;
+// domestic flights may return nil.
+func (s *Service) CheckIn(ticket models.Ticket, documentID string, now time.Time) (*models.TravelDocument, error) {
+	if ticket.Status != ticketConfirmed {
+		return nil, fmt.Errorf("%w: ticket is %s", ErrCheckInClosed, strings.ToLower(strings.ReplaceAll(ticket.Status, "_", " ")))
+	}
+
+	flight, err := s.db.GetFlightByID(ticket.Flight)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case flight.ID == "":
+		return nil, fmt.Errorf("%w: flight %s does not exist", ErrCheckInClosed, ticket.Flight)
+	case flight.StatusID == models.FlightStatusCancelled:
+		return nil, fmt.Errorf("%w: flight is cancelled", ErrCheckInClosed)
+	case flight.ActualDeparture != nil || !flight.ScheduledDeparture.After(now):
+		return nil, fmt.Errorf("%w: flight has departed", ErrCheckInClosed)
+	}
+
+	if err := s.checkSeatLeft(flight); err != nil {
+		return nil, err
+	}
+
+	international := IsInternational(s.db.GetAirportByID(flight.From), s.db.GetAirportByID(flight.To))
+	traveller, companion := travellerOf(ticket)
+
+	var document *models.TravelDocument
+	if documentID != "" {
+		document, err = s.Document(traveller, documentID)
+		if err != nil {
+			return nil, err
+		}
+		if document == nil || document.CompanionID != companion {
+			return nil, ErrUnknownDocument
+		}
+		if international && !ValidFor(*document, flight) {
+			return nil, fmt.Errorf("%w: document expires before the flight arrives", ErrDocumentRequired)
+		}
+	} else if international && traveller != "" {
+		document, err = s.validDocument(traveller, companion, flight)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if international && document == nil {
+		return nil, ErrDocumentRequired
+	}
+
+	var id string
+	if document != nil {
+		id = document.ID
+	}
+	updated, err := s.db.CheckInTicket(ticket.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: ticket is no longer confirmed", ErrCheckInClosed)
+	}
+	return document, nil
+}
+
+// checkSeatLeft returns ErrFlightFull if every seat of the flight's plane is
+// taken by a checked-in passenger. Flights without a plane are not limited.
+func (s *Service) checkSeatLeft(flight models.Flight) error {
+	plane, err := s.db.GetPlaneByID(flight.PlaneID)
+	if err != nil || plane == nil {
+		return err
+	}
+	checkedIn, err := s.db.GetFlightCheckedInCount(flight.ID)
+	if err != nil {
+		return err
+	}
+	if checkedIn >= plane.Seats {
+		return ErrFlightFull
+	}
+	return nil
+}
+
+// travellerOf returns the account and companion whose documents a ticket is
+// checked in with. Both are empty for guest passengers.
+func travellerOf(ticket models.Ticket) (userID, companionID string) {
+	if ticket.CompanionID != "" {
+		return ticket.AirportUserID, ticket.CompanionID
+	}
+	return ticket.TravellerID(), ""
+}
+
+// validDocument returns the first document of a traveller valid for a
+// flight, or nil if there is none.
+func (s *Service) validDocument(userID, companionID string, flight models.Flight) (*models.TravelDocument, error) {
+	documents, err := s.TravellerDocuments(userID, companionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range documents {
+		if ValidFor(documents[i], flight) {
+			return &documents[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// dateOf returns the calendar date of t in UTC.
+func dateOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,147 @@
+package apis
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mindenairport/models"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Date(2025, 11, 3, 18, 0, 0, 0, time.UTC)
+	valid := func() models.TravelDocument {
+		return models.TravelDocument{
+			Type: " p ", Number: "c01x 00t47", Nationality: "deu", IssuingCountry: "deu", Sex: "f",
+			ExpiryDate: time.Date(2030, 1, 1, 15, 0, 0, 0, time.UTC),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(d *models.TravelDocument)
+		wantMsg string
+	}{
+		{"valid", func(d *models.TravelDocument) {}, ""},
+		{"identity card", func(d *models.TravelDocument) { d.Type = "I" }, ""},
+		{"unknown type", func(d *models.TravelDocument) { d.Type = "V" }, "Type must be"},
+		{"shortest number", func(d *models.TravelDocument) { d.Number = "12345" }, ""},
+		{"number too short", func(d *models.TravelDocument) { d.Number = "1234" }, "Document number"},
+		{"longest number", func(d *models.TravelDocument) { d.Number = strings.Repeat("A", 20) }, ""},
+		{"number too long", func(d *models.TravelDocument) { d.Number = strings.Repeat("A", 21) }, "Document number"},
+		{"number with hyphen", func(d *models.TravelDocument) { d.Number = "C01-X00" }, "Document number"},
+		{"alpha-2 nationality", func(d *models.TravelDocument) { d.Nationality = "DE" }, "Nationality"},
+		{"numeric issuing country", func(d *models.TravelDocument) { d.IssuingCountry = "276" }, "Issuing country"},
+		{"unspecified sex", func(d *models.TravelDocument) { d.Sex = "X" }, ""},
+		{"unknown sex", func(d *models.TravelDocument) { d.Sex = "U" }, "Sex must be"},
+		{"expires today", func(d *models.TravelDocument) { d.ExpiryDate = time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC) }, ""},
+		{"expired yesterday", func(d *models.TravelDocument) { d.ExpiryDate = time.Date(2025, 11, 2, 23, 59, 0, 0, time.UTC) }, "expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := valid()
+			tt.modify(&document)
+			msg := Validate(&document, now)
+			if (tt.wantMsg == "") != (msg == "") || !strings.Contains(msg, tt.wantMsg) {
+				t.Errorf("Validate() = %q, want %q", msg, tt.wantMsg)
+			}
+		})
+	}
+
+	document := valid()
+	Validate(&document, now)
+	if document.Type != "P" || document.Number != "C01X00T47" || document.Nationality != "DEU" || document.Sex != "F" {
+		t.Errorf("Validate() did not normalize the document: %+v", document)
+	}
+	if !document.ExpiryDate.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ExpiryDate = %v, want the date only", document.ExpiryDate)
+	}
+}
+
+func TestValidFor(t *testing.T) {
+	expiry := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		arrival time.Time
+		want    bool
+	}{
+		{"arrives the day before", time.Date(2025, 11, 2, 23, 59, 0, 0, time.UTC), true},
+		{"arrives on the expiry date", time.Date(2025, 11, 3, 23, 59, 0, 0, time.UTC), true},
+		{"arrives the day after", time.Date(2025, 11, 4, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := models.TravelDocument{ExpiryDate: expiry}
+			if got := ValidFor(document, models.Flight{ScheduledArrival: tt.arrival}); got != tt.want {
+				t.Errorf("ValidFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsInternational(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"DE", "DE", false},
+		{"de ", "DE", false},
+		{"DE", "AT", true},
+		{"", "", true},
+		{"DE", "", true},
+	}
+
+	for _, tt := range tests {
+		got := IsInternational(models.Airport{Country: tt.from}, models.Airport{Country: tt.to})
+		if got != tt.want {
+			t.Errorf("IsInternational(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCipher(t *testing.T) {
+	if _, err := NewCipher(make([]byte, 16)); err == nil {
+		t.Error("NewCipher() accepted a 16 byte key")
+	}
+
+	c, err := NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	first, err := c.Encrypt("C01X00T47")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	second, _ := c.Encrypt("C01X00T47")
+	if first == second {
+		t.Error("Encrypt() returned the same ciphertext twice")
+	}
+	if plain, err := c.Decrypt(first); err != nil || plain != "C01X00T47" {
+		t.Errorf("Decrypt() = %q, %v", plain, err)
+	}
+
+	other, _ := NewCipher([]byte(strings.Repeat("k", 32)))
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(first, cipherPrefix))
+	sealed[len(sealed)-1] ^= 1
+	tampered := cipherPrefix + base64.StdEncoding.EncodeToString(sealed)
+	for name, ciphertext := range map[string]string{
+		"no prefix":     strings.TrimPrefix(first, cipherPrefix),
+		"not base64":    cipherPrefix + "%%%",
+		"too short":     cipherPrefix + "AAAA",
+		"tampered":      tampered,
+		"empty":         "",
+		"only a prefix": cipherPrefix,
+	} {
+		if _, err := c.Decrypt(ciphertext); !errors.Is(err, ErrCiphertext) {
+			t.Errorf("Decrypt(%s) error = %v, want ErrCiphertext", name, err)
+		}
+	}
+	if _, err := other.Decrypt(first); !errors.Is(err, ErrCiphertext) {
+		t.Errorf("Decrypt() with another key error = %v, want ErrCiphertext", err)
+	}
+}
@@ -0,0 +1,107 @@
+package apis
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// cipherPrefix marks values encrypted with the current scheme, so the key
+// or algorithm can be changed later without guessing the format.
+const cipherPrefix = "v1:"
+
+// ErrCiphertext is returned for stored values that cannot be decrypted.
+var ErrCiphertext = errors.New("invalid encrypted value")
+
+// Cipher encrypts personal data with AES-256-GCM. Every value gets a random
+// nonce, so equal document numbers do not produce equal ciphertexts.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a 32 byte key.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+var (
+	defaultCipher     *Cipher
+	defaultCipherOnce sync.Once
+)
+
+// DefaultCipher returns the cipher configured by DOCUMENT_ENCRYPTION_KEY, a
+// base64 encoded 32 byte key. Falls back to a fixed development key if the
+// variable is not set, and exits if it is set but invalid.
+//
+// Security Note: In production, always set DOCUMENT_ENCRYPTION_KEY and keep
+// it; documents encrypted with a lost key cannot be read anymore.
+func DefaultCipher() *Cipher {
+	defaultCipherOnce.Do(func() {
+		var key []byte
+		if encoded := os.Getenv("DOCUMENT_ENCRYPTION_KEY"); encoded != "" {
+			var err error
+			key, err = base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				log.Fatalf("Invalid DOCUMENT_ENCRYPTION_KEY: %v", err)
+			}
+		} else {
+			log.Println("DOCUMENT_ENCRYPTION_KEY is not set, using the development key for travel documents")
+			sum := sha256.Sum256([]byte("mindenairport-development-document-key"))
+			key = sum[:]
+		}
+
+		c, err := NewCipher(key)
+		if err != nil {
+			log.Fatalf("Invalid DOCUMENT_ENCRYPTION_KEY: %v", err)
+		}
+		defaultCipher = c
+	})
+	return defaultCipher
+}
+
+// Encrypt returns the base64 encoded nonce and ciphertext of a value.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return cipherPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the value of a ciphertext created by Encrypt.
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, cipherPrefix) {
+		return "", ErrCiphertext
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, cipherPrefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrCiphertext
+	}
+
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrCiphertext
+	}
+	return string(plaintext), nil
+}
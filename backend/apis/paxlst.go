@@ -0,0 +1,172 @@
+package apis
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mindenairport/manifest"
+	"mindenairport/models"
+	"mindenairport/timezone"
+)
+
+const (
+	paxlstSender = "MINDENAIRPORT"
+
+	// DefaultRecipient is the interchange recipient if none is requested.
+	DefaultRecipient = "APIS"
+)
+
+// Paxlst builds the UN/EDIFACT PAXLST (D.02B, IATA implementation) message
+// of a flight's manifest. Each passenger is reported with the document shown
+// at check-in, or else the first document on file that is valid for the
+// flight. Passengers without one are left out and listed as missing.
+func (s *Service) Paxlst(m *models.Manifest, recipient string, now time.Time) (*models.Paxlst, error) {
+	flight, err := s.db.GetFlightByID(m.FlightID)
+	if err != nil {
+		return nil, err
+	}
+
+	localizer := timezone.NewLocalizer(s.db)
+	origin, destination := localizer.Location(flight.From), localizer.Location(flight.To)
+	if origin == nil {
+		origin = time.UTC
+	}
+	if destination == nil {
+		destination = time.UTC
+	}
+
+	passengers := make([]reportedPassenger, 0, len(m.Passengers))
+	for _, p := range m.Passengers {
+		document, err := s.passengerDocument(p, flight)
+		if err != nil {
+			return nil, err
+		}
+		passengers = append(passengers, reportedPassenger{ManifestPassenger: p, document: document})
+	}
+
+	return buildPaxlst(flight, origin, destination, passengers, recipient, now), nil
+}
+
+// reportedPassenger is a passenger of a PAXLST message with the document
+// the passenger is reported with, or nil if there is none.
+type reportedPassenger struct {
+	models.ManifestPassenger
+	document *models.TravelDocument
+}
+
+// buildPaxlst writes the PAXLST interchange of a flight. Departure and
+// arrival are given in the local time of origin and destination.
+func buildPaxlst(flight models.Flight, origin, destination *time.Location, passengers []reportedPassenger, recipient string, now time.Time) *models.Paxlst {
+	number := flight.FlightNumber
+	if number == "" {
+		number = flight.ID
+	}
+	departure := flight.ScheduledDeparture.In(origin)
+	arrival := flight.ScheduledArrival.In(destination)
+
+	result := &models.Paxlst{
+		FlightID:  flight.ID,
+		Missing:   []string{},
+		Reference: now.UTC().Format("0601021504"),
+		Recipient: recipient,
+	}
+
+	// Segments from UNH to UNT, which are counted in UNT
+	segments := []string{
+		segment("UNH", "1", "PAXLST:D:02B:UN:IATA", escape(number)+departure.Format("060102"), "01:F"),
+		segment("BGM", "745"),
+		segment("TDT", "20", escape(number), "", "", escape(carrier(flight.FlightNumber))),
+		segment("LOC", "125", escape(flight.From)),
+		segment("DTM", "189:"+departure.Format("0601021504")+":201"),
+		segment("LOC", "87", escape(flight.To)),
+		segment("DTM", "232:"+arrival.Format("0601021504")+":201"),
+	}
+
+	for _, p := range passengers {
+		document := p.document
+		if document == nil {
+			result.Missing = append(result.Missing, p.TicketID)
+			continue
+		}
+
+		reference := p.Locator
+		if reference == "" {
+			reference = p.TicketID
+		}
+
+		result.Passengers++
+		segments = append(segments,
+			segment("NAD", "FL", "", "", escape(manifest.ASCIIName(p.LastName))+":"+escape(manifest.ASCIIName(p.FirstName))),
+			segment("ATT", "2", "", document.Sex),
+		)
+		if p.BirthDate != nil {
+			segments = append(segments, segment("DTM", "329:"+p.BirthDate.Format("060102")))
+		}
+		segments = append(segments,
+			segment("LOC", "178", escape(flight.From)),
+			segment("LOC", "179", escape(flight.To)),
+			segment("NAT", "2", document.Nationality),
+			segment("RFF", "AVF:"+escape(reference)),
+			segment("DOC", document.Type+":110:111", escape(document.Number)),
+			segment("DTM", "36:"+document.ExpiryDate.Format("060102")),
+			segment("LOC", "91", document.IssuingCountry),
+		)
+	}
+
+	segments = append(segments, segment("CNT", fmt.Sprintf("42:%d", result.Passengers)))
+	segments = append(segments, segment("UNT", fmt.Sprint(len(segments)+1), "1"))
+
+	interchange := []string{
+		"UNA:+.? '",
+		segment("UNB", "UNOA:4", paxlstSender, escape(recipient), now.UTC().Format("060102:1504"), result.Reference),
+	}
+	interchange = append(interchange, segments...)
+	interchange = append(interchange, segment("UNZ", "1", result.Reference))
+
+	result.Content = strings.Join(interchange, "\n")
+	return result
+}
+
+// passengerDocument returns the document a passenger is reported with, or
+// nil if there is none that is valid for the flight. Guest passengers
+// without an account have no documents on file.
+func (s *Service) passengerDocument(p models.ManifestPassenger, flight models.Flight) (*models.TravelDocument, error) {
+	if p.AirportUserID == "" {
+		return nil, nil
+	}
+	if p.TravelDocumentID != "" {
+		document, err := s.Document(p.AirportUserID, p.TravelDocumentID)
+		if err != nil {
+			return nil, err
+		}
+		if document != nil && document.CompanionID == p.CompanionID && ValidFor(*document, flight) {
+			return document, nil
+		}
+	}
+	return s.validDocument(p.AirportUserID, p.CompanionID, flight)
+}
+
+// carrier returns the airline designator of a flight number, e.g. "LH" for
+// "LH100", or "" if the flight has no number.
+func carrier(flightNumber string) string {
+	if len(flightNumber) < 3 {
+		return ""
+	}
+	return flightNumber[:2]
+}
+
+// segment joins the data elements of an EDIFACT segment and terminates it.
+// Trailing empty elements are dropped.
+func segment(tag string, elements ...string) string {
+	for len(elements) > 0 && elements[len(elements)-1] == "" {
+		elements = elements[:len(elements)-1]
+	}
+	return strings.Join(append([]string{tag}, elements...), "+") + "'"
+}
+
+// escape prefixes the EDIFACT separators in a value with the release
+// character.
+func escape(value string) string {
+	return strings.NewReplacer("?", "??", "+", "?+", ":", "?:", "'", "?'").Replace(value)
+}
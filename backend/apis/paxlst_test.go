@@ -0,0 +1,190 @@
+package apis
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"mindenairport/models"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		tag      string
+		elements []string
+		want     string
+	}{
+		{"BGM", []string{"745"}, "BGM+745'"},
+		{"TDT", []string{"20", "LH100", "", "", "LH"}, "TDT+20+LH100+++LH'"},
+		{"TDT", []string{"20", "4711", "", "", ""}, "TDT+20+4711'"},
+		{"UNS", nil, "UNS'"},
+		{"UNS", []string{"", ""}, "UNS'"},
+	}
+
+	for _, tt := range tests {
+		if got := segment(tt.tag, tt.elements...); got != tt.want {
+			t.Errorf("segment(%q, %q) = %q, want %q", tt.tag, tt.elements, got, tt.want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"MUELLER", "MUELLER"},
+		{"A+B", "A?+B"},
+		{"10:30", "10?:30"},
+		{"O'NEIL", "O?'NEIL"},
+		{"WHY?", "WHY??"},
+		{"?+:'", "???+?:?'"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := escape(tt.value); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCarrier(t *testing.T) {
+	tests := map[string]string{"LH100": "LH", "U2123": "U2", "LH1": "LH", "LH": "", "": ""}
+	for number, want := range tests {
+		if got := carrier(number); got != want {
+			t.Errorf("carrier(%q) = %q, want %q", number, got, want)
+		}
+	}
+}
+
+// paxlstSegments returns the segments of an interchange without the UNA
+// service string advice.
+func paxlstSegments(t *testing.T, content string) []string {
+	t.Helper()
+	lines := strings.Split(content, "\n")
+	if lines[0] != "UNA:+.? '" {
+		t.Fatalf("interchange starts with %q, want the UNA service string advice", lines[0])
+	}
+	return lines[1:]
+}
+
+func TestBuildPaxlst(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available:", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone data not available:", err)
+	}
+
+	flight := models.Flight{
+		ID: "f1", FlightNumber: "LH400", From: "FRA", To: "JFK",
+		ScheduledDeparture: time.Date(2025, 11, 3, 9, 15, 0, 0, time.UTC),
+		ScheduledArrival:   time.Date(2025, 11, 3, 17, 50, 0, 0, time.UTC),
+	}
+	birth := time.Date(1980, 2, 29, 0, 0, 0, 0, time.UTC)
+	passport := &models.TravelDocument{
+		Type: "P", Number: "C01X00T47", Nationality: "DEU", IssuingCountry: "DEU", Sex: "F",
+		ExpiryDate: time.Date(2031, 5, 31, 0, 0, 0, 0, time.UTC),
+	}
+	passengers := []reportedPassenger{
+		{ManifestPassenger: models.ManifestPassenger{TicketID: "t1", Locator: "ABC123",
+			FirstName: "Jürgen", LastName: "O'Brien-Müller", BirthDate: &birth}, document: passport},
+		{ManifestPassenger: models.ManifestPassenger{TicketID: "t2", FirstName: "Guest", LastName: "Without"}},
+		{ManifestPassenger: models.ManifestPassenger{TicketID: "t3", FirstName: "Ann", LastName: "Lee"}, document: passport},
+	}
+	now := time.Date(2025, 11, 2, 22, 5, 0, 0, berlin)
+
+	result := buildPaxlst(flight, berlin, newYork, passengers, "USCBP", now)
+
+	if result.Passengers != 2 {
+		t.Errorf("Passengers = %d, want 2", result.Passengers)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "t2" {
+		t.Errorf("Missing = %v, want [t2]", result.Missing)
+	}
+	if result.Reference != "2511022105" || result.Recipient != "USCBP" {
+		t.Errorf("Reference, Recipient = %q, %q", result.Reference, result.Recipient)
+	}
+
+	segments := paxlstSegments(t, result.Content)
+	for _, want := range []string{
+		"UNB+UNOA:4+MINDENAIRPORT+USCBP+251102:2105+2511022105'",
+		"UNH+1+PAXLST:D:02B:UN:IATA+LH400251103+01:F'",
+		"TDT+20+LH400+++LH'",
+		"DTM+189:2511031015:201'", // 09:15 UTC in Frankfurt
+		"DTM+232:2511031250:201'", // 17:50 UTC in New York
+		"NAD+FL+++OBRIENMUELLER:JUERGEN'",
+		"DTM+329:800229'",
+		"RFF+AVF:ABC123'",
+		"RFF+AVF:t3'",
+		"DOC+P:110:111+C01X00T47'",
+		"DTM+36:310531'",
+		"CNT+42:2'",
+		"UNZ+1+2511022105'",
+	} {
+		found := false
+		for _, s := range segments {
+			if s == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("segment %q missing in\n%s", want, result.Content)
+		}
+	}
+
+	// UNT counts the segments from UNH to UNT, both included
+	unh, unt := -1, -1
+	for i, s := range segments {
+		switch {
+		case strings.HasPrefix(s, "UNH+"):
+			unh = i
+		case strings.HasPrefix(s, "UNT+"):
+			unt = i
+		}
+	}
+	if unh < 0 || unt < unh {
+		t.Fatalf("UNH at %d, UNT at %d", unh, unt)
+	}
+	if want := "UNT+" + strconv.Itoa(unt-unh+1) + "+1'"; segments[unt] != want {
+		t.Errorf("trailer = %q, want %q", segments[unt], want)
+	}
+	if segments[len(segments)-1] != "UNZ+1+2511022105'" {
+		t.Errorf("interchange ends with %q", segments[len(segments)-1])
+	}
+}
+
+func TestBuildPaxlstWithoutPassengers(t *testing.T) {
+	flight := models.Flight{
+		ID: "f-0001", From: "MIN", To: "FRA",
+		ScheduledDeparture: time.Date(2025, 12, 31, 23, 30, 0, 0, time.UTC),
+		ScheduledArrival:   time.Date(2026, 1, 1, 0, 40, 0, 0, time.UTC),
+	}
+
+	result := buildPaxlst(flight, time.UTC, time.UTC, nil, DefaultRecipient, flight.ScheduledDeparture)
+
+	if result.Passengers != 0 || len(result.Missing) != 0 || result.Missing == nil {
+		t.Errorf("Passengers, Missing = %d, %#v, want 0 and an empty list", result.Passengers, result.Missing)
+	}
+
+	segments := paxlstSegments(t, result.Content)
+	for _, want := range []string{
+		"UNH+1+PAXLST:D:02B:UN:IATA+f-0001251231+01:F'", // Flights without number use their ID
+		"TDT+20+f-0001'",
+		"DTM+232:2601010040:201'",
+		"CNT+42:0'",
+		"UNT+9+1'",
+	} {
+		if !strings.Contains(result.Content, want) {
+			t.Errorf("segment %q missing in\n%s", want, result.Content)
+		}
+	}
+	if len(segments) != 11 {
+		t.Errorf("interchange has %d segments, want UNB, 9 message segments and UNZ", len(segments))
+	}
+}
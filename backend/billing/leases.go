@@ -0,0 +1,148 @@
+// Package billing calculates the concession invoices of retail leases and
+// aggregates them into revenue reports. Amounts are in USD and rounded to
+// cents.
+package billing
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"mindenairport/models"
+)
+
+// MonthLayout is the format of billing months (e.g. "2025-09").
+const MonthLayout = "2006-01"
+
+// ParseMonth parses a billing month and returns its first day in loc.
+func ParseMonth(month string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(MonthLayout, month, loc)
+}
+
+// MonthStart returns the first day of the month containing t.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// roundCents rounds an amount to whole cents.
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// day returns the calendar day of t as midnight in loc.
+func day(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// daysBetween returns the number of calendar days from a to b. Rounding
+// absorbs daylight saving time changes.
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// ActiveDays returns the number of days of the month starting at month that
+// are covered by the lease. Start and end date of the lease are inclusive.
+func ActiveDays(lease models.Lease, month time.Time) int {
+	loc := month.Location()
+	first := month
+	last := month.AddDate(0, 1, -1)
+
+	if start := day(lease.StartDate, loc); start.After(first) {
+		first = start
+	}
+	if lease.EndDate != nil {
+		if end := day(*lease.EndDate, loc); end.Before(last) {
+			last = end
+		}
+	}
+	if last.Before(first) {
+		return 0
+	}
+	return daysBetween(first, last) + 1
+}
+
+// LeaseInvoice calculates the invoice of a lease for the month starting at
+// month. The fixed rent is prorated for months the lease covers only in
+// part; the turnover rent is only charged once the shop has reported its
+// turnover. The second value is false if the lease does not cover the month.
+func LeaseInvoice(lease models.Lease, month time.Time, turnover *float64) (models.LeaseInvoice, bool) {
+	active := ActiveDays(lease, month)
+	if active == 0 {
+		return models.LeaseInvoice{}, false
+	}
+
+	daysInMonth := daysBetween(month, month.AddDate(0, 1, 0))
+	invoice := models.LeaseInvoice{
+		LeaseID:   lease.ID,
+		ShopID:    lease.ShopID,
+		ShopName:  lease.ShopName,
+		Month:     month.Format(MonthLayout),
+		AreaSqFt:  lease.AreaSqFt,
+		FixedRent: roundCents(lease.AreaSqFt * lease.RentPerSqFt * float64(active) / float64(daysInMonth)),
+		Turnover:  turnover,
+	}
+	if turnover != nil {
+		invoice.TurnoverRent = roundCents(*turnover * lease.TurnoverPercent / 100)
+	}
+	invoice.Total = roundCents(invoice.FixedRent + invoice.TurnoverRent)
+
+	return invoice, true
+}
+
+// ConcessionRevenue sums up the invoices of the months from..to (YYYY-MM,
+// inclusive) per month and per shop. Shops are ordered by revenue, highest
+// first.
+func ConcessionRevenue(from, to string, invoices []models.LeaseInvoice) models.ConcessionRevenue {
+	report := models.ConcessionRevenue{
+		From:   from,
+		To:     to,
+		Months: []models.RevenueByMonth{},
+		Shops:  []models.RevenueByShop{},
+	}
+
+	months := make(map[string]int)
+	shops := make(map[string]int)
+	for _, invoice := range invoices {
+		i, ok := months[invoice.Month]
+		if !ok {
+			i = len(report.Months)
+			months[invoice.Month] = i
+			report.Months = append(report.Months, models.RevenueByMonth{Month: invoice.Month})
+		}
+		report.Months[i].FixedRent += invoice.FixedRent
+		report.Months[i].TurnoverRent += invoice.TurnoverRent
+		report.Months[i].Total += invoice.Total
+
+		j, ok := shops[invoice.ShopID]
+		if !ok {
+			j = len(report.Shops)
+			shops[invoice.ShopID] = j
+			report.Shops = append(report.Shops, models.RevenueByShop{ShopID: invoice.ShopID, ShopName: invoice.ShopName})
+		}
+		report.Shops[j].Invoices++
+		report.Shops[j].Total += invoice.Total
+
+		report.FixedRent += invoice.FixedRent
+		report.TurnoverRent += invoice.TurnoverRent
+		report.Total += invoice.Total
+	}
+
+	// Summing rounded amounts can leave float noise behind
+	for i := range report.Months {
+		report.Months[i].FixedRent = roundCents(report.Months[i].FixedRent)
+		report.Months[i].TurnoverRent = roundCents(report.Months[i].TurnoverRent)
+		report.Months[i].Total = roundCents(report.Months[i].Total)
+	}
+	for i := range report.Shops {
+		report.Shops[i].Total = roundCents(report.Shops[i].Total)
+	}
+	report.FixedRent = roundCents(report.FixedRent)
+	report.TurnoverRent = roundCents(report.TurnoverRent)
+	report.Total = roundCents(report.Total)
+
+	sort.Slice(report.Months, func(i, j int) bool { return report.Months[i].Month < report.Months[j].Month })
+	sort.SliceStable(report.Shops, func(i, j int) bool { return report.Shops[i].Total > report.Shops[j].Total })
+
+	return report
+}
@@ -0,0 +1,239 @@
+// Package boarding handles oversold flights at the gate. When more
+// passengers hold a ticket than the plane has seats, passengers can
+// volunteer to give up their seat, and staff deny boarding to volunteers or,
+// if there are not enough, to other passengers. Every denial records the
+// compensation paid, by default the amount due under Regulation (EC) No
+// 261/2004 for the distance of the flight.
+package boarding
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/geo"
+	"mindenairport/models"
+)
+
+const (
+	// Currency of all compensation amounts.
+	Currency = "EUR"
+
+	statusVolunteer = "VOLUNTEER"
+	statusDenied    = "DENIED"
+
+	ticketConfirmed = "CONFIRMED"
+	ticketCheckedIn = "CHECKED_IN"
+)
+
+var (
+	// ErrBoardingClosed is returned for flights that were cancelled or have departed.
+	ErrBoardingClosed = errors.New("flight is no longer boarding")
+	// ErrNotOversold is returned when a flight has a seat for every passenger.
+	ErrNotOversold = errors.New("flight is not oversold")
+	// ErrNoSeat is returned for tickets that were cancelled or already denied boarding.
+	ErrNoSeat = errors.New("ticket holds no seat")
+	// ErrAlreadyVolunteered is returned when a ticket volunteers twice.
+	ErrAlreadyVolunteered = errors.New("ticket already volunteered")
+	// ErrNotVolunteer is returned when a ticket that did not volunteer, or was
+	// already denied boarding, withdraws.
+	ErrNotVolunteer = errors.New("ticket has no open volunteer offer")
+	// ErrUnknownTicket is returned for tickets that are not on the flight.
+	ErrUnknownTicket = errors.New("ticket not found on this flight")
+	// ErrCompensationRequired is returned when no compensation is given and
+	// none can be derived, because the distance of the flight is unknown.
+	ErrCompensationRequired = errors.New("compensation is required, the distance of the flight is unknown")
+)
+
+// Compensation returns the compensation in EUR due to a passenger denied
+// boarding against their will on a flight of the given great-circle
+// distance, following Article 7 of Regulation (EC) No 261/2004.
+func Compensation(distanceKm float64) float64 {
+	switch {
+	case distanceKm <= 1500:
+		return 250
+	case distanceKm <= 3500:
+		return 400
+	default:
+		return 600
+	}
+}
+
+// Service records volunteers and denied boardings.
+type Service struct {
+	db database.Database
+}
+
+// NewService creates a denied boarding service.
+func NewService(db database.Database) *Service {
+	return &Service{db: db}
+}
+
+// Status returns the seats, booked and checked-in passengers of a flight
+// with its volunteers and denied boardings. It returns nil if the flight
+// does not exist.
+func (s *Service) Status(flightID string) (*models.BoardingStatus, error) {
+	flight, err := s.db.GetFlightByID(flightID)
+	if err != nil || flight.ID == "" {
+		return nil, err
+	}
+
+	status := &models.BoardingStatus{
+		FlightID:   flight.ID,
+		Currency:   Currency,
+		Volunteers: []models.DeniedBoarding{},
+		Denied:     []models.DeniedBoarding{},
+	}
+
+	plane, err := s.db.GetPlaneByID(flight.PlaneID)
+	if err != nil {
+		return nil, err
+	}
+	if plane != nil {
+		status.Seats = plane.Seats
+	}
+	if status.Booked, err = s.db.GetFlightTicketCount(flight.ID); err != nil {
+		return nil, err
+	}
+	if status.CheckedIn, err = s.db.GetFlightCheckedInCount(flight.ID); err != nil {
+		return nil, err
+	}
+	if plane != nil {
+		status.Oversold = max(status.Booked-status.Seats, 0)
+	}
+
+	if distance, err := geo.Distance(s.db.GetAirportByID(flight.From), s.db.GetAirportByID(flight.To)); err == nil {
+		amount := Compensation(distance)
+		status.DefaultCompensation = &amount
+	}
+
+	records, err := s.db.GetDeniedBoardingByFlight(flight.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Status == statusDenied {
+			status.Denied = append(status.Denied, record)
+		} else {
+			status.Volunteers = append(status.Volunteers, record)
+		}
+	}
+	return status, nil
+}
+
+// Volunteer records that the passenger of a ticket is willing to give up
+// their seat on an oversold flight, optionally for the compensation they ask
+// for.
+func (s *Service) Volunteer(ticket models.Ticket, requested *float64, now time.Time) (*models.DeniedBoarding, error) {
+	if ticket.Status != ticketConfirmed && ticket.Status != ticketCheckedIn {
+		return nil, ErrNoSeat
+	}
+	status, err := s.openStatus(ticket.Flight, now)
+	if err != nil {
+		return nil, err
+	}
+	if status.Oversold == 0 {
+		return nil, ErrNotOversold
+	}
+
+	existing, err := s.db.GetDeniedBoardingByTicket(ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyVolunteered
+	}
+
+	record := &models.DeniedBoarding{
+		TicketID:              ticket.ID,
+		FlightID:              ticket.Flight,
+		RequestedCompensation: requested,
+		Currency:              Currency,
+		CreatedAt:             now.UTC(),
+	}
+	if err := s.db.CreateBoardingVolunteer(record); err != nil {
+		return nil, err
+	}
+	return s.db.GetDeniedBoardingByTicket(ticket.ID)
+}
+
+// Withdraw takes back the volunteer offer of a ticket before staff accepted it.
+func (s *Service) Withdraw(ticket models.Ticket) error {
+	deleted, err := s.db.DeleteBoardingVolunteer(ticket.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotVolunteer
+	}
+	return nil
+}
+
+// Deny denies the passenger of a ticket boarding an oversold flight. The
+// denial is voluntary if the passenger volunteered. Without an amount in the
+// request, volunteers receive what they asked for and everyone else the
+// amount due by distance (see Compensation).
+func (s *Service) Deny(flightID string, req models.DeniedBoardingRequest, staffID string, now time.Time) (*models.DeniedBoarding, error) {
+	ticket, err := s.db.GetTicketByID(req.TicketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.ID == "" || ticket.Flight != flightID {
+		return nil, ErrUnknownTicket
+	}
+	if ticket.Status != ticketConfirmed && ticket.Status != ticketCheckedIn {
+		return nil, ErrNoSeat
+	}
+
+	status, err := s.openStatus(flightID, now)
+	if err != nil {
+		return nil, err
+	}
+	if status.Oversold == 0 {
+		return nil, ErrNotOversold
+	}
+
+	volunteer, err := s.db.GetDeniedBoardingByTicket(ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var compensation float64
+	switch {
+	case req.Compensation != nil:
+		compensation = *req.Compensation
+	case volunteer != nil && volunteer.RequestedCompensation != nil:
+		compensation = *volunteer.RequestedCompensation
+	case status.DefaultCompensation != nil:
+		compensation = *status.DefaultCompensation
+	default:
+		return nil, ErrCompensationRequired
+	}
+
+	denied, err := s.db.DenyBoarding(ticket, compensation, Currency, req.Note, staffID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !denied {
+		return nil, ErrNoSeat
+	}
+	return s.db.GetDeniedBoardingByTicket(ticket.ID)
+}
+
+// openStatus returns the boarding status of a flight that still boards.
+func (s *Service) openStatus(flightID string, now time.Time) (*models.BoardingStatus, error) {
+	flight, err := s.db.GetFlightByID(flightID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case flight.ID == "":
+		return nil, fmt.Errorf("%w: flight %s does not exist", ErrBoardingClosed, flightID)
+	case flight.StatusID == models.FlightStatusCancelled:
+		return nil, fmt.Errorf("%w: flight is cancelled", ErrBoardingClosed)
+	case flight.ActualDeparture != nil || !flight.ScheduledDeparture.After(now):
+		return nil, fmt.Errorf("%w: flight has departed", ErrBoardingClosed)
+	}
+	return s.Status(flightID)
+}
@@ -0,0 +1,684 @@
+// Package booking manages booking records (PNR): one or more passengers on
+// one or more flights, booked together by an account and identified by a
+// six character locator. Cancellation and check-in act on all tickets of a
+// booking, and guests can look a booking up by locator and last name.
+// Flights may be overbooked by a percentage of their seats; once they are
+// full, passengers queue on a waitlist and are offered freed seats. New
+// bookings hold their seats at the fare of each flight until they are paid.
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"mindenairport/apis"
+	"mindenairport/database"
+	"mindenairport/manifest"
+	"mindenairport/models"
+	"mindenairport/notifications"
+)
+
+const (
+	// maxTravelClass is the highest ID of the travel classes in the reference data.
+	maxTravelClass = 8
+
+	// locatorAttempts bounds the retries when a new locator is already taken.
+	locatorAttempts = 10
+
+	// MaxOverbookingPercent is the highest overbooking percentage a flight
+	// or airline can be given.
+	MaxOverbookingPercent = 50
+
+	// Currency of all fares and bookings.
+	Currency = "EUR"
+
+	bookingHeld      = "HELD"
+	bookingConfirmed = "CONFIRMED"
+	bookingCancelled = "CANCELLED"
+
+	ticketHeld      = "HELD"
+	ticketConfirmed = "CONFIRMED"
+	ticketCheckedIn = "CHECKED_IN"
+	ticketCancelled = "CANCELLED"
+)
+
+var (
+	// ErrFlightNotBookable is returned for flights that do not exist, are
+	// cancelled or have departed.
+	ErrFlightNotBookable = errors.New("flight cannot be booked")
+	// ErrSoldOut is returned when a flight has fewer seats left than passengers.
+	ErrSoldOut = errors.New("not enough seats left")
+	// ErrBookingClosed is returned when a booking has nothing left to cancel.
+	ErrBookingClosed = errors.New("booking cannot be cancelled")
+	// ErrCheckedIn is returned when a booking with checked-in passengers is cancelled.
+	ErrCheckedIn = errors.New("passengers of the booking are already checked in")
+	// ErrUnknownFlight is returned when a check-in names a flight that is not
+	// part of the booking.
+	ErrUnknownFlight = errors.New("flight is not part of the booking")
+	// ErrUnknownCompanion is returned when a passenger names a companion that
+	// does not belong to the booking account.
+	ErrUnknownCompanion = errors.New("companion not found")
+	// ErrNoFare is returned when a flight has no fare for the requested travel class.
+	ErrNoFare = errors.New("no fare for this travel class")
+	// ErrNotHeld is returned when a booking that is not held is confirmed or expired.
+	ErrNotHeld = errors.New("booking is not held")
+)
+
+// Service creates bookings and acts on all of their tickets.
+type Service struct {
+	db         database.Database
+	passengers *apis.Service
+	notifier   *notifications.Service
+
+	OfferWindow time.Duration // How long a waitlist offer holds the freed seats
+	HoldWindow  time.Duration // How long a new booking holds its seats until it is paid
+
+	// mu serializes the seat check and the issue of tickets and waitlist
+	// offers, so concurrent requests cannot both take the last seats of a
+	// flight.
+	mu sync.Mutex
+}
+
+// NewService creates a booking service checking passengers in through the
+// given APIS service and sending waitlist offers through the notifier. The
+// offer and hold windows are read from the environment:
+//
+//	WAITLIST_OFFER_MINUTES  (default 120)
+//	PAYMENT_HOLD_MINUTES    (default 30)
+func NewService(db database.Database, passengers *apis.Service, notifier *notifications.Service) *Service {
+	return &Service{
+		db:          db,
+		passengers:  passengers,
+		notifier:    notifier,
+		OfferWindow: time.Duration(intFromEnv("WAITLIST_OFFER_MINUTES", 120)) * time.Minute,
+		HoldWindow:  time.Duration(intFromEnv("PAYMENT_HOLD_MINUTES", 30)) * time.Minute,
+	}
+}
+
+// Validate normalizes the passengers of a booking request and checks it.
+// It returns a message describing the first problem, or "".
+func Validate(req *models.BookingRequest) string {
+	self := 0
+	companions := make(map[string]bool)
+	for i := range req.Passengers {
+		p := &req.Passengers[i]
+		p.FirstName = strings.TrimSpace(p.FirstName)
+		p.LastName = strings.TrimSpace(p.LastName)
+		p.CompanionID = strings.TrimSpace(p.CompanionID)
+		if p.Self && p.CompanionID != "" {
+			return fmt.Sprintf("Passenger %d cannot be the account holder and a companion", i+1)
+		}
+		if p.Self {
+			self++
+			continue
+		}
+		if p.CompanionID != "" {
+			if companions[p.CompanionID] {
+				return fmt.Sprintf("Companion %s is listed more than once", p.CompanionID)
+			}
+			companions[p.CompanionID] = true
+			continue
+		}
+		if p.FirstName == "" || p.LastName == "" {
+			return fmt.Sprintf("Passenger %d needs a first and last name", i+1)
+		}
+		if manifest.ASCIIName(p.LastName) == "" {
+			return fmt.Sprintf("Last name of passenger %d must contain letters", i+1)
+		}
+	}
+	if self > 1 {
+		return "Only one passenger can be the account holder"
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range req.Flights {
+		if seen[f.FlightID] {
+			return "Flight " + f.FlightID + " is listed more than once"
+		}
+		seen[f.FlightID] = true
+		if f.TravelClass > maxTravelClass {
+			return fmt.Sprintf("Travel class must be between 1 and %d", maxTravelClass)
+		}
+	}
+	return ""
+}
+
+// Create books a validated request for a user: every passenger on every
+// flight. Companions must belong to the user; their names and birth date
+// come from the profile. Flights must be scheduled, not yet departed, have
+// a fare for the travel class and a seat left for each passenger within
+// their overbooking limit. The booking is held until it is paid, but no
+// longer than the first departure; bookings that cost nothing are
+// confirmed right away.
+func (s *Service) Create(user models.AirportUser, req models.BookingRequest, now time.Time) (*models.Booking, error) {
+	passengers, err := s.passengersFor(user, req.Passengers)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(user.ID, passengers, req.Flights, now, "")
+}
+
+// passengersFor resolves the passengers of a request into booking
+// passengers, taking names from the account or companion profile.
+func (s *Service) passengersFor(user models.AirportUser, requested []models.BookingPassengerRequest) ([]models.BookingPassenger, error) {
+	var passengers []models.BookingPassenger
+	for _, p := range requested {
+		passenger := models.BookingPassenger{FirstName: p.FirstName, LastName: p.LastName, BirthDate: p.BirthDate}
+		switch {
+		case p.Self:
+			birthDate := user.Birthdate
+			passenger = models.BookingPassenger{
+				FirstName:     user.FirstName,
+				LastName:      user.LastName,
+				BirthDate:     &birthDate,
+				AirportUserID: user.ID,
+			}
+		case p.CompanionID != "":
+			companion, err := s.db.GetCompanionByID(p.CompanionID)
+			if err != nil {
+				return nil, err
+			}
+			if companion == nil || companion.AirportUserID != user.ID {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownCompanion, p.CompanionID)
+			}
+			birthDate := companion.BirthDate
+			passenger = models.BookingPassenger{
+				FirstName:   companion.FirstName,
+				LastName:    companion.LastName,
+				BirthDate:   &birthDate,
+				CompanionID: companion.ID,
+			}
+		}
+		passengers = append(passengers, passenger)
+	}
+	return passengers, nil
+}
+
+// create books passengers on flights. Seats held by the waitlist offer
+// offerID are available to the booking. The caller must hold s.mu.
+func (s *Service) create(userID string, passengers []models.BookingPassenger, flights []models.BookingFlightRequest, now time.Time, offerID string) (*models.Booking, error) {
+	holdExpiresAt := now.UTC().Add(s.HoldWindow)
+	booking := &models.Booking{
+		AirportUserID: userID,
+		Status:        bookingHeld,
+		CreatedAt:     now.UTC(),
+		HoldExpiresAt: &holdExpiresAt,
+		Passengers:    passengers,
+	}
+
+	fares := make(map[string]float64)
+	total := 0.0
+	for _, f := range flights {
+		flight, err := s.checkFlight(f.FlightID, len(passengers), now, offerID)
+		if err != nil {
+			return nil, err
+		}
+		if flight.ScheduledDeparture.Before(holdExpiresAt) {
+			holdExpiresAt = flight.ScheduledDeparture
+		}
+
+		price, err := s.fare(f.FlightID, f.TravelClass)
+		if err != nil {
+			return nil, err
+		}
+		fares[f.FlightID] = price
+		total += price * float64(len(passengers))
+	}
+
+	locator, err := s.newLocator()
+	if err != nil {
+		return nil, err
+	}
+	booking.Locator = locator
+
+	if err := s.db.CreateBooking(booking, flights, fares); err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		if _, err := s.db.ConfirmBooking(booking.ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(booking.Locator)
+}
+
+// fare returns the price of a ticket on a flight in a travel class, or
+// ErrNoFare.
+func (s *Service) fare(flightID string, travelClass int) (float64, error) {
+	fares, err := s.db.GetFlightFares(flightID)
+	if err != nil {
+		return 0, err
+	}
+	for _, fare := range fares {
+		if fare.TravelClassID == travelClass {
+			return fare.Price, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: flight %s has no fare for travel class %d", ErrNoFare, flightID, travelClass)
+}
+
+// ValidateFares checks the fares of a flight. It returns a message
+// describing the first problem, or "".
+func ValidateFares(req models.FaresRequest) string {
+	seen := make(map[int]bool)
+	for _, fare := range req.Fares {
+		if fare.TravelClass > maxTravelClass {
+			return fmt.Sprintf("Travel class must be between 1 and %d", maxTravelClass)
+		}
+		if seen[fare.TravelClass] {
+			return fmt.Sprintf("Travel class %d is listed more than once", fare.TravelClass)
+		}
+		seen[fare.TravelClass] = true
+	}
+	return ""
+}
+
+// Fares returns the fares of a flight by travel class.
+func (s *Service) Fares(flightID string) ([]models.Fare, error) {
+	fares, err := s.db.GetFlightFares(flightID)
+	if err != nil {
+		return nil, err
+	}
+	if fares == nil {
+		fares = []models.Fare{}
+	}
+	for i := range fares {
+		fares[i].Currency = Currency
+	}
+	return fares, nil
+}
+
+// checkFlight returns the flight unless it cannot be booked for the given
+// number of passengers. Seats held by the waitlist offer offerID count as
+// free.
+func (s *Service) checkFlight(flightID string, passengers int, now time.Time, offerID string) (models.Flight, error) {
+	flight, err := s.bookableFlight(flightID, now)
+	if err != nil {
+		return flight, err
+	}
+
+	availability, err := s.availability(flight.ID, now, offerID)
+	if err != nil {
+		return flight, err
+	}
+	if availability == nil {
+		return flight, fmt.Errorf("%w: flight %s has no aircraft assigned", ErrFlightNotBookable, flightID)
+	}
+	if availability.Available < passengers {
+		return flight, fmt.Errorf("%w on flight %s: %d free", ErrSoldOut, flightID, availability.Available)
+	}
+	return flight, nil
+}
+
+// bookableFlight returns a flight that exists, is not cancelled and has not
+// departed, or ErrFlightNotBookable.
+func (s *Service) bookableFlight(flightID string, now time.Time) (models.Flight, error) {
+	flight, err := s.db.GetFlightByID(flightID)
+	if err != nil {
+		return flight, err
+	}
+	switch {
+	case flight.ID == "":
+		return flight, fmt.Errorf("%w: flight %s does not exist", ErrFlightNotBookable, flightID)
+	case flight.StatusID == models.FlightStatusCancelled:
+		return flight, fmt.Errorf("%w: flight %s is cancelled", ErrFlightNotBookable, flightID)
+	case flight.ActualDeparture != nil || !flight.ScheduledDeparture.After(now):
+		return flight, fmt.Errorf("%w: flight %s has departed", ErrFlightNotBookable, flightID)
+	}
+	return flight, nil
+}
+
+// Capacity returns the number of tickets that can be sold for a plane with
+// the given seats when overbooking by percent, rounded down.
+func Capacity(seats int, percent float64) int {
+	return seats + int(math.Floor(float64(seats)*percent/100))
+}
+
+// Availability returns the overbooking limit of a flight with the tickets
+// sold, the seats held by waitlist offers at now and the passengers waiting.
+// It returns nil if the flight does not exist or has no plane.
+func (s *Service) Availability(flightID string, now time.Time) (*models.Overbooking, error) {
+	availability, err := s.availability(flightID, now, "")
+	if err != nil || availability == nil {
+		return nil, err
+	}
+
+	entries, err := s.db.GetFlightWaitlist(flightID)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Status == waitlistWaiting {
+			availability.Waiting += entry.Seats
+		}
+	}
+	return availability, nil
+}
+
+// availability returns the overbooking limit of a flight with the tickets
+// sold and the seats held by waitlist offers other than offerID.
+func (s *Service) availability(flightID string, now time.Time, offerID string) (*models.Overbooking, error) {
+	availability, err := s.db.GetFlightOverbooking(flightID)
+	if err != nil || availability == nil {
+		return nil, err
+	}
+
+	switch {
+	case availability.FlightPercent != nil:
+		availability.Percent = *availability.FlightPercent
+	case availability.AirlinePercent != nil:
+		availability.Percent = *availability.AirlinePercent
+	}
+	availability.Capacity = Capacity(availability.Seats, availability.Percent)
+
+	if availability.Sold, err = s.db.GetFlightTicketCount(flightID); err != nil {
+		return nil, err
+	}
+	if availability.Held, err = s.db.GetFlightHeldSeats(flightID, now, offerID); err != nil {
+		return nil, err
+	}
+	availability.Available = max(availability.Capacity-availability.Sold-availability.Held, 0)
+	return availability, nil
+}
+
+// newLocator returns a locator no booking uses yet.
+func (s *Service) newLocator() (string, error) {
+	for i := 0; i < locatorAttempts; i++ {
+		locator, err := NewLocator()
+		if err != nil {
+			return "", err
+		}
+		existing, err := s.db.GetBookingByLocator(locator)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return locator, nil
+		}
+	}
+	return "", errors.New("no free booking locator found")
+}
+
+// Get returns a booking with its passengers and tickets, or nil if there is
+// no booking with the locator.
+func (s *Service) Get(locator string) (*models.Booking, error) {
+	booking, err := s.db.GetBookingByLocator(locator)
+	if err != nil || booking == nil {
+		return nil, err
+	}
+	if err := s.load(booking); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// ForUser returns the bookings a user made or travels on, newest first.
+func (s *Service) ForUser(userID string) ([]models.Booking, error) {
+	bookings, err := s.db.GetBookingsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if bookings == nil {
+		bookings = []models.Booking{}
+	}
+	for i := range bookings {
+		if err := s.load(&bookings[i]); err != nil {
+			return nil, err
+		}
+	}
+	return bookings, nil
+}
+
+// load reads the passengers and tickets of a booking.
+func (s *Service) load(booking *models.Booking) error {
+	passengers, err := s.db.GetBookingPassengers(booking.ID)
+	if err != nil {
+		return err
+	}
+	tickets, err := s.db.GetBookingTickets(booking.ID)
+	if err != nil {
+		return err
+	}
+
+	booking.Passengers = passengers
+	if booking.Passengers == nil {
+		booking.Passengers = []models.BookingPassenger{}
+	}
+	booking.Tickets = tickets
+	if booking.Tickets == nil {
+		booking.Tickets = []models.Ticket{}
+	}
+
+	booking.Currency = Currency
+	booking.Total = 0
+	for _, ticket := range booking.Tickets {
+		if ticket.Status != ticketCancelled {
+			booking.Total += ticket.Price
+		}
+	}
+	booking.Total = math.Round(booking.Total*100) / 100
+	return nil
+}
+
+// CanAccess reports whether a user made a booking or travels on it.
+func CanAccess(booking *models.Booking, userID string) bool {
+	if booking.AirportUserID == userID {
+		return true
+	}
+	for _, p := range booking.Passengers {
+		if p.AirportUserID != "" && p.AirportUserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the booking with a locator if one of its passengers has the
+// last name, compared in the transliterated form airline systems use, so
+// "Müller" matches "MUELLER". Account IDs and birth dates are removed, as
+// the caller is not signed in. It returns nil if nothing matches.
+func (s *Service) Lookup(locator, lastName string) (*models.Booking, error) {
+	locator = NormalizeLocator(locator)
+	name := manifest.ASCIIName(lastName)
+	if locator == "" || name == "" {
+		return nil, nil
+	}
+
+	booking, err := s.Get(locator)
+	if err != nil || booking == nil {
+		return nil, err
+	}
+
+	for _, p := range booking.Passengers {
+		if manifest.ASCIIName(p.LastName) == name {
+			return guestView(booking), nil
+		}
+	}
+	return nil, nil
+}
+
+// guestView removes the data of a booking that only its passengers may see.
+func guestView(booking *models.Booking) *models.Booking {
+	booking.AirportUserID = ""
+	for i := range booking.Passengers {
+		booking.Passengers[i].AirportUserID = ""
+		booking.Passengers[i].BirthDate = nil
+		booking.Passengers[i].CompanionID = ""
+	}
+	for i := range booking.Tickets {
+		booking.Tickets[i].AirportUserID = ""
+		booking.Tickets[i].PassengerUserID = ""
+		booking.Tickets[i].CompanionID = ""
+	}
+	return booking
+}
+
+// Cancel cancels all held and confirmed tickets of a booking on flights that
+// have not departed, and then the booking. Tickets of flown flights are
+// kept. A booking with checked-in passengers on an upcoming flight is not
+// cancelled at all; they have to be offloaded at the airport first.
+func (s *Service) Cancel(booking *models.Booking, now time.Time) error {
+	if booking.Status == bookingCancelled {
+		return fmt.Errorf("%w: booking is already cancelled", ErrBookingClosed)
+	}
+
+	var open []models.Ticket
+	for _, ticket := range booking.Tickets {
+		if ticket.Status != ticketHeld && ticket.Status != ticketConfirmed && ticket.Status != ticketCheckedIn {
+			continue
+		}
+		departed, err := s.departed(ticket.Flight, now)
+		if err != nil {
+			return err
+		}
+		if departed {
+			continue
+		}
+		if ticket.Status == ticketCheckedIn {
+			return ErrCheckedIn
+		}
+		open = append(open, ticket)
+	}
+	if len(open) == 0 {
+		return fmt.Errorf("%w: all flights have departed", ErrBookingClosed)
+	}
+
+	return s.cancelTickets(booking, open, now)
+}
+
+// cancelTickets cancels tickets of a booking and then the booking, and
+// offers the freed seats to the waitlists.
+func (s *Service) cancelTickets(booking *models.Booking, tickets []models.Ticket, now time.Time) error {
+	for _, ticket := range tickets {
+		if _, err := s.db.CancelTicket(ticket.ID); err != nil {
+			return err
+		}
+	}
+	if err := s.db.UpdateBookingStatus(booking.ID, bookingCancelled); err != nil {
+		return err
+	}
+	booking.Status = bookingCancelled
+
+	// The freed seats go to the waitlists; the cancellation stands even if
+	// no offer could be made
+	released := make(map[string]bool)
+	for _, ticket := range tickets {
+		if released[ticket.Flight] {
+			continue
+		}
+		released[ticket.Flight] = true
+		if err := s.ReleaseSeats(ticket.Flight, now); err != nil {
+			log.Printf("Error offering freed seats of flight %s to the waitlist: %v", ticket.Flight, err)
+		}
+	}
+	return nil
+}
+
+// Confirm confirms a held booking and its tickets once it is paid.
+func (s *Service) Confirm(booking *models.Booking) error {
+	confirmed, err := s.db.ConfirmBooking(booking.ID)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("%w: booking is %s", ErrNotHeld, strings.ToLower(booking.Status))
+	}
+	booking.Status = bookingConfirmed
+	booking.HoldExpiresAt = nil
+	return nil
+}
+
+// ExpiredHolds returns the held bookings whose hold has expired at now,
+// with their passengers and tickets.
+func (s *Service) ExpiredHolds(now time.Time) ([]models.Booking, error) {
+	bookings, err := s.db.GetExpiredHolds(now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range bookings {
+		if err := s.load(&bookings[i]); err != nil {
+			return nil, err
+		}
+	}
+	return bookings, nil
+}
+
+// ExpireHold cancels a held booking that was not paid in time and offers
+// its seats to the waitlists.
+func (s *Service) ExpireHold(booking *models.Booking, now time.Time) error {
+	if booking.Status != bookingHeld {
+		return fmt.Errorf("%w: booking is %s", ErrNotHeld, strings.ToLower(booking.Status))
+	}
+
+	var held []models.Ticket
+	for _, ticket := range booking.Tickets {
+		if ticket.Status == ticketHeld {
+			held = append(held, ticket)
+		}
+	}
+	return s.cancelTickets(booking, held, now)
+}
+
+// departed reports whether a flight has left. Flights that no longer exist
+// count as departed, so their tickets are left alone.
+func (s *Service) departed(flightID string, now time.Time) (bool, error) {
+	flight, err := s.db.GetFlightByID(flightID)
+	if err != nil {
+		return false, err
+	}
+	return flight.ID == "" || flight.ActualDeparture != nil || !flight.ScheduledDeparture.After(now), nil
+}
+
+// CheckIn checks in all confirmed tickets of a booking, or only those on
+// flightID if it is set. Each passenger travels with their first travel
+// document valid for the flight. Tickets that cannot be checked in do not
+// stop the others; the outcome of every ticket is returned.
+func (s *Service) CheckIn(booking *models.Booking, flightID string, now time.Time) ([]models.BookingCheckIn, error) {
+	var results []models.BookingCheckIn
+	for _, ticket := range booking.Tickets {
+		if flightID != "" && ticket.Flight != flightID {
+			continue
+		}
+		if ticket.Status == ticketCancelled {
+			continue
+		}
+
+		result := models.BookingCheckIn{
+			TicketID:    ticket.ID,
+			PassengerID: ticket.PassengerID,
+			FlightID:    ticket.Flight,
+			Status:      ticket.Status,
+		}
+		if ticket.Status == ticketConfirmed {
+			document, err := s.passengers.CheckIn(ticket, "", now)
+			switch {
+			case errors.Is(err, apis.ErrCheckInClosed), errors.Is(err, apis.ErrDocumentRequired), errors.Is(err, apis.ErrFlightFull):
+				result.Error = err.Error()
+			case err != nil:
+				return nil, err
+			default:
+				result.Status = ticketCheckedIn
+				if document != nil {
+					result.TravelDocumentID = document.ID
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	if flightID != "" && len(results) == 0 {
+		return nil, ErrUnknownFlight
+	}
+	if results == nil {
+		results = []models.BookingCheckIn{}
+	}
+	return results, nil
+}
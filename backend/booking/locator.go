@@ -0,0 +1,40 @@
+package booking
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// locatorAlphabet leaves out 0, 1, I and O, which are easily confused when
+// a locator is read out or typed in. Its 32 letters map bytes without bias.
+const locatorAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// locatorLength is the length of a booking locator.
+const locatorLength = 6
+
+// NewLocator returns a random booking locator like "K7PX2M".
+func NewLocator() (string, error) {
+	b := make([]byte, locatorLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = locatorAlphabet[int(b[i])%len(locatorAlphabet)]
+	}
+	return string(b), nil
+}
+
+// NormalizeLocator returns a locator as entered by a user in upper case
+// without surrounding spaces, or "" if it cannot be a locator.
+func NormalizeLocator(locator string) string {
+	locator = strings.ToUpper(strings.TrimSpace(locator))
+	if len(locator) != locatorLength {
+		return ""
+	}
+	for i := 0; i < len(locator); i++ {
+		if strings.IndexByte(locatorAlphabet, locator[i]) < 0 {
+			return ""
+		}
+	}
+	return locator
+}
@@ -0,0 +1,339 @@
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"mindenairport/models"
+)
+
+const (
+	waitlistWaiting   = "WAITING"
+	waitlistOffered   = "OFFERED"
+	waitlistAccepted  = "ACCEPTED"
+	waitlistDeclined  = "DECLINED"
+	waitlistExpired   = "EXPIRED"
+	waitlistCancelled = "CANCELLED"
+)
+
+var (
+	// ErrSeatsAvailable is returned when passengers join the waitlist of a
+	// flight that can still be booked for them.
+	ErrSeatsAvailable = errors.New("flight still has seats, book it directly")
+	// ErrOfferClosed is returned when a waitlist entry has no open offer to accept.
+	ErrOfferClosed = errors.New("waitlist entry has no open offer")
+	// ErrWaitlistClosed is returned when a waitlist entry that was already
+	// accepted, declined, expired or cancelled is withdrawn.
+	ErrWaitlistClosed = errors.New("waitlist entry is closed")
+)
+
+// ValidateWaitlist normalizes and checks a waitlist request like a booking
+// request for its single flight. It returns a message describing the first
+// problem, or "".
+func ValidateWaitlist(req *models.WaitlistRequest) string {
+	return Validate(&models.BookingRequest{
+		Passengers: req.Passengers,
+		Flights:    []models.BookingFlightRequest{{FlightID: req.FlightID, TravelClass: req.TravelClass}},
+	})
+}
+
+// JoinWaitlist puts the passengers of a validated request on the waitlist
+// of a flight that cannot be booked for them because it is full. The
+// passengers are resolved again when the entry is booked, so profile
+// changes until then apply.
+func (s *Service) JoinWaitlist(user models.AirportUser, req models.WaitlistRequest, now time.Time) (*models.WaitlistEntry, error) {
+	if _, err := s.passengersFor(user, req.Passengers); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.checkFlight(req.FlightID, len(req.Passengers), now, "")
+	switch {
+	case err == nil:
+		return nil, ErrSeatsAvailable
+	case !errors.Is(err, ErrSoldOut):
+		return nil, err
+	}
+
+	entry := &models.WaitlistEntry{
+		FlightID:      req.FlightID,
+		AirportUserID: user.ID,
+		TravelClass:   req.TravelClass,
+		Status:        waitlistWaiting,
+		CreatedAt:     now.UTC(),
+		Passengers:    req.Passengers,
+	}
+	if err := s.db.CreateWaitlistEntry(entry); err != nil {
+		return nil, err
+	}
+	return s.WaitlistEntry(entry.ID)
+}
+
+// WaitlistEntry returns a waitlist entry with its passengers and place in
+// the queue, or nil if it does not exist.
+func (s *Service) WaitlistEntry(id string) (*models.WaitlistEntry, error) {
+	entry, err := s.db.GetWaitlistEntryByID(id)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	queue, err := s.db.GetFlightWaitlist(entry.FlightID)
+	if err != nil {
+		return nil, err
+	}
+	entry.Position = positionIn(queue, entry.ID)
+
+	if err := s.loadWaitlistPassengers(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// WaitlistForUser returns the waitlist entries of a user, newest first.
+func (s *Service) WaitlistForUser(userID string) ([]models.WaitlistEntry, error) {
+	entries, err := s.db.GetWaitlistByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.WaitlistEntry{}
+	}
+
+	queues := make(map[string][]models.WaitlistEntry)
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Status == waitlistWaiting {
+			queue, ok := queues[entry.FlightID]
+			if !ok {
+				if queue, err = s.db.GetFlightWaitlist(entry.FlightID); err != nil {
+					return nil, err
+				}
+				queues[entry.FlightID] = queue
+			}
+			entry.Position = positionIn(queue, entry.ID)
+		}
+		if err := s.loadWaitlistPassengers(entry); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// FlightWaitlist returns all waitlist entries of a flight in queue order.
+func (s *Service) FlightWaitlist(flightID string) ([]models.WaitlistEntry, error) {
+	entries, err := s.db.GetFlightWaitlist(flightID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.WaitlistEntry{}
+	}
+	for i := range entries {
+		entries[i].Position = positionIn(entries, entries[i].ID)
+		if err := s.loadWaitlistPassengers(&entries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// positionIn returns the place of a waiting entry among the waiting entries
+// of a queue, counting from 1, or 0 if it is not waiting.
+func positionIn(queue []models.WaitlistEntry, id string) int {
+	position := 0
+	for _, entry := range queue {
+		if entry.Status != waitlistWaiting {
+			continue
+		}
+		position++
+		if entry.ID == id {
+			return position
+		}
+	}
+	return 0
+}
+
+// loadWaitlistPassengers reads the passengers of a waitlist entry.
+func (s *Service) loadWaitlistPassengers(entry *models.WaitlistEntry) error {
+	passengers, err := s.db.GetWaitlistPassengers(entry.ID)
+	if err != nil {
+		return err
+	}
+	entry.Passengers = passengers
+	if entry.Passengers == nil {
+		entry.Passengers = []models.BookingPassengerRequest{}
+	}
+	return nil
+}
+
+// AcceptOffer books the passengers of a waitlist entry on the seats offered
+// to it. The offer must not have expired.
+func (s *Service) AcceptOffer(user models.AirportUser, entry *models.WaitlistEntry, now time.Time) (*models.Booking, error) {
+	passengers, err := s.passengersFor(user, entry.Passengers)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Reload the entry, a concurrent request may have changed it
+	current, err := s.db.GetWaitlistEntryByID(entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.Status != waitlistOffered || !current.OfferExpiresAt.After(now) {
+		return nil, ErrOfferClosed
+	}
+
+	flights := []models.BookingFlightRequest{{FlightID: current.FlightID, TravelClass: current.TravelClass}}
+	booking, err := s.create(user.ID, passengers, flights, now, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	current.Status = waitlistAccepted
+	current.BookingID = booking.ID
+	if err := s.db.UpdateWaitlistEntry(*current); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// Withdraw takes a waitlist entry off the queue. An open offer is declined
+// and its seats are offered to the next passengers waiting.
+func (s *Service) Withdraw(entry *models.WaitlistEntry, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.db.GetWaitlistEntryByID(entry.ID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return ErrWaitlistClosed
+	}
+
+	switch current.Status {
+	case waitlistWaiting:
+		current.Status = waitlistCancelled
+		return s.db.UpdateWaitlistEntry(*current)
+	case waitlistOffered:
+		current.Status = waitlistDeclined
+		if err := s.db.UpdateWaitlistEntry(*current); err != nil {
+			return err
+		}
+		return s.offerSeats(current.FlightID, now)
+	default:
+		return fmt.Errorf("%w: entry is %s", ErrWaitlistClosed, current.Status)
+	}
+}
+
+// ReleaseSeats offers the free seats of a flight to its waitlist, e.g.
+// after tickets were cancelled or the overbooking limit was raised.
+func (s *Service) ReleaseSeats(flightID string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offerSeats(flightID, now)
+}
+
+// ExpireOffers closes the waitlist entries whose offer expired or whose
+// flight has departed or was cancelled, and offers the seats of expired
+// offers to the next passengers waiting. It returns the number of entries
+// closed.
+func (s *Service) ExpireOffers(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stale, err := s.db.GetStaleWaitlistEntries(now)
+	if err != nil {
+		return 0, err
+	}
+
+	released := make(map[string]bool)
+	for _, entry := range stale {
+		if entry.Status == waitlistOffered {
+			released[entry.FlightID] = true
+		}
+		entry.Status = waitlistExpired
+		if err := s.db.UpdateWaitlistEntry(entry); err != nil {
+			return 0, err
+		}
+	}
+
+	for flightID := range released {
+		if err := s.offerSeats(flightID, now); err != nil {
+			return len(stale), err
+		}
+	}
+	return len(stale), nil
+}
+
+// offerSeats offers the free seats of a bookable flight to its waiting
+// entries in the order they joined. An entry that needs more seats than are
+// free is skipped, so smaller groups behind it can still fly; it keeps its
+// place for the next seats. Offers expire after the offer window, but no
+// later than departure. The caller must hold s.mu.
+func (s *Service) offerSeats(flightID string, now time.Time) error {
+	flight, err := s.bookableFlight(flightID, now)
+	if errors.Is(err, ErrFlightNotBookable) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	availability, err := s.availability(flightID, now, "")
+	if err != nil || availability == nil {
+		return err
+	}
+	free := availability.Available
+	if free == 0 {
+		return nil
+	}
+
+	queue, err := s.db.GetFlightWaitlist(flightID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range queue {
+		if entry.Status != waitlistWaiting || entry.Seats > free {
+			continue
+		}
+
+		offeredAt := now.UTC()
+		expiresAt := offeredAt.Add(s.OfferWindow)
+		if expiresAt.After(flight.ScheduledDeparture) {
+			expiresAt = flight.ScheduledDeparture
+		}
+		entry.Status = waitlistOffered
+		entry.OfferedAt = &offeredAt
+		entry.OfferExpiresAt = &expiresAt
+		if err := s.db.UpdateWaitlistEntry(entry); err != nil {
+			return err
+		}
+		go s.notifier.NotifyWaitlistOffer(entry, flight)
+
+		free -= entry.Seats
+		if free == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// intFromEnv reads a positive integer from an environment variable,
+// falling back to the default if it is unset or invalid.
+func intFromEnv(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
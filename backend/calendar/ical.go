@@ -0,0 +1,191 @@
+// Package calendar writes passengers' trips as iCalendar files (RFC 5545),
+// which calendar apps can import once or subscribe to. Flight times are
+// written in the time zones of the airports, each described by a VTIMEZONE
+// generated from Go's time zone database.
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	productID    = "-//MindenAirport//Trips//EN"
+	maxLineBytes = 75 // Content lines longer than this are folded
+	dateTime     = "20060102T150405"
+)
+
+// Event is a single flight in a calendar. Start and End are written in the
+// location they carry, so a departure in Europe/Berlin and an arrival in
+// Europe/London keep their wall clock times.
+type Event struct {
+	UID          string
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Location     string
+	Description  string
+	Cancelled    bool
+	LastModified time.Time
+}
+
+// Calendar is a collection of events.
+type Calendar struct {
+	Name            string
+	RefreshInterval time.Duration // Suggested polling interval for subscriptions, 0 for one-off exports
+	Events          []Event
+}
+
+// Bytes encodes the calendar as an iCalendar file with CRLF line endings.
+// stamp is written as DTSTAMP of every event.
+func (c Calendar) Bytes(stamp time.Time) []byte {
+	var w writer
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", productID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escape(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		w.line("REFRESH-INTERVAL;VALUE=DURATION", duration(c.RefreshInterval))
+		w.line("X-PUBLISHED-TTL", duration(c.RefreshInterval))
+	}
+
+	for _, zone := range c.zones() {
+		writeTimezone(&w, zone.loc, zone.from, zone.to)
+	}
+
+	for _, e := range c.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", escape(e.UID))
+		w.line("DTSTAMP", stamp.UTC().Format(dateTime)+"Z")
+		w.dateTime("DTSTART", e.Start)
+		w.dateTime("DTEND", e.End)
+		if !e.LastModified.IsZero() {
+			w.line("LAST-MODIFIED", e.LastModified.UTC().Format(dateTime)+"Z")
+		}
+		w.line("SUMMARY", escape(e.Summary))
+		if e.Location != "" {
+			w.line("LOCATION", escape(e.Location))
+		}
+		if e.Description != "" {
+			w.line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Cancelled {
+			w.line("STATUS", "CANCELLED")
+		} else {
+			w.line("STATUS", "CONFIRMED")
+		}
+		w.line("TRANSP", "OPAQUE")
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// zoneRange is a time zone used by the events of a calendar together with
+// the years its definition has to cover.
+type zoneRange struct {
+	loc      *time.Location
+	from, to int
+}
+
+// zones returns the time zones used by the events, ordered by name. UTC is
+// written with the "Z" suffix and needs no VTIMEZONE.
+func (c Calendar) zones() []zoneRange {
+	byName := make(map[string]*zoneRange)
+	add := func(t time.Time) {
+		loc := t.Location()
+		if loc == time.UTC {
+			return
+		}
+		year := t.Year()
+		z, ok := byName[loc.String()]
+		if !ok {
+			byName[loc.String()] = &zoneRange{loc: loc, from: year, to: year}
+			return
+		}
+		if year < z.from {
+			z.from = year
+		}
+		if year > z.to {
+			z.to = year
+		}
+	}
+	for _, e := range c.Events {
+		add(e.Start)
+		add(e.End)
+	}
+
+	zones := make([]zoneRange, 0, len(byName))
+	for _, z := range byName {
+		zones = append(zones, *z)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].loc.String() < zones[j].loc.String() })
+	return zones
+}
+
+// writer builds iCalendar content lines.
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it after 75 octets without splitting
+// UTF-8 characters.
+func (w *writer) line(name, value string) {
+	s := name + ":" + value
+	n := 0
+	for len(s) > 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		if n+size > maxLineBytes {
+			w.buf.WriteString("\r\n ")
+			n = 1
+		}
+		w.buf.WriteString(s[:size])
+		n += size
+		s = s[size:]
+	}
+	w.buf.WriteString("\r\n")
+}
+
+// dateTime writes a DATE-TIME property in the location of t: UTC with the
+// "Z" suffix, any other location as local time with its TZID.
+func (w *writer) dateTime(name string, t time.Time) {
+	if t.Location() == time.UTC {
+		w.line(name, t.Format(dateTime)+"Z")
+		return
+	}
+	w.line(name+";TZID="+t.Location().String(), t.Format(dateTime))
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// duration formats a duration as iCalendar DURATION, e.g. "PT1H".
+func duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	s := "PT"
+	if hours > 0 {
+		s += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 || hours == 0 {
+		s += fmt.Sprintf("%dM", minutes)
+	}
+	return s
+}
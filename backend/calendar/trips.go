@@ -0,0 +1,177 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/timezone"
+)
+
+// Trips turns tickets into calendar events. It caches airports and
+// terminals, so it should only live for one request.
+type Trips struct {
+	db        database.Database
+	localizer *timezone.Localizer
+	airports  map[string]models.Airport
+	terminals map[string]string
+}
+
+// NewTrips creates a builder reading flights, airports and terminals from db.
+func NewTrips(db database.Database) *Trips {
+	return &Trips{
+		db:        db,
+		localizer: timezone.NewLocalizer(db),
+		airports:  make(map[string]models.Airport),
+		terminals: make(map[string]string),
+	}
+}
+
+// Calendar returns a calendar with one event per ticket. Tickets whose
+// flight no longer exists are left out.
+func (b *Trips) Calendar(name string, tickets []models.Ticket) (Calendar, error) {
+	cal := Calendar{Name: name, Events: []Event{}}
+	for _, ticket := range tickets {
+		event, ok, err := b.Event(ticket)
+		if err != nil {
+			return cal, err
+		}
+		if ok {
+			cal.Events = append(cal.Events, event)
+		}
+	}
+	return cal, nil
+}
+
+// Event returns the event of a ticket's flight. Departure and arrival are
+// the actual times once known, otherwise the scheduled ones, each in the
+// time zone of its airport. The booking locator serves as booking reference
+// (the ticket ID for tickets without booking), the ticket ID as UID, so
+// subscribed calendars update the event when the flight changes.
+func (b *Trips) Event(ticket models.Ticket) (Event, bool, error) {
+	flight, err := b.db.GetFlightByID(ticket.Flight)
+	if err != nil {
+		return Event{}, false, err
+	}
+	if flight.ID == "" {
+		return Event{}, false, nil
+	}
+
+	departure, arrival := flight.ScheduledDeparture, flight.ScheduledArrival
+	if flight.ActualDeparture != nil {
+		departure = *flight.ActualDeparture
+	}
+	if flight.ActualArrival != nil {
+		arrival = *flight.ActualArrival
+	}
+
+	terminal, err := b.terminal(flight.TerminalID)
+	if err != nil {
+		return Event{}, false, err
+	}
+
+	origin, destination := b.airport(flight.From), b.airport(flight.To)
+	number := flight.FlightNumber
+	if number == "" {
+		number = flight.ID
+	}
+
+	event := Event{
+		UID:       ticket.ID + "@mindenairport",
+		Start:     departure.In(b.location(flight.From)),
+		End:       arrival.In(b.location(flight.To)),
+		Summary:   fmt.Sprintf("Flight %s %s → %s", number, flight.From, flight.To),
+		Location:  eventLocation(origin, terminal, flight.Gate),
+		Cancelled: ticket.Status == "CANCELLED" || ticket.Status == "DENIED_BOARDING" || flight.StatusID == models.FlightStatusCancelled,
+	}
+
+	description := []string{
+		"Booking reference: " + ticket.Reference(),
+		fmt.Sprintf("Flight: %s from %s to %s", number, airportName(origin), airportName(destination)),
+	}
+	if ticket.PassengerName != "" {
+		description = append(description, "Passenger: "+ticket.PassengerName)
+	}
+	if terminal != "" {
+		description = append(description, "Terminal: "+terminal)
+	}
+	if flight.Gate != "" {
+		description = append(description, "Gate: "+flight.Gate)
+	}
+	if ticket.SeatNumber != "" {
+		description = append(description, "Seat: "+ticket.SeatNumber)
+	}
+	if ticket.TravelClass != "" {
+		description = append(description, "Class: "+ticket.TravelClass)
+	}
+	if flight.BaggageClaim != "" {
+		description = append(description, "Baggage claim: "+flight.BaggageClaim)
+	}
+	event.Description = strings.Join(description, "\n")
+
+	return event, true, nil
+}
+
+// location returns the time zone of an airport, UTC if it has none.
+func (b *Trips) location(airportID string) *time.Location {
+	if loc := b.localizer.Location(airportID); loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// airport returns an airport by ID; unknown airports only carry their ID.
+func (b *Trips) airport(id string) models.Airport {
+	if airport, ok := b.airports[id]; ok {
+		return airport
+	}
+	airport := b.db.GetAirportByID(id)
+	if airport.ID == "" {
+		airport.ID = id
+	}
+	b.airports[id] = airport
+	return airport
+}
+
+// terminal returns the name of a terminal, "" if the flight has none.
+func (b *Trips) terminal(id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+	if name, ok := b.terminals[id]; ok {
+		return name, nil
+	}
+	terminal, err := b.db.GetTerminalByID(id)
+	if err != nil {
+		return "", err
+	}
+	name := id
+	if terminal != nil && terminal.Name != "" {
+		name = terminal.Name
+	}
+	b.terminals[id] = name
+	return name, nil
+}
+
+// eventLocation describes where a flight departs, e.g.
+// "Minden Airport (MIN), Terminal 1, Gate A1".
+func eventLocation(airport models.Airport, terminal, gate string) string {
+	parts := []string{airportName(airport)}
+	if terminal != "" {
+		parts = append(parts, terminal)
+	}
+	if gate != "" {
+		parts = append(parts, "Gate "+gate)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// airportName returns "Name (ID)", or the ID if the airport has no name.
+func airportName(airport models.Airport) string {
+	if airport.Name == "" {
+		return airport.ID
+	}
+	return fmt.Sprintf("%s (%s)", airport.Name, airport.ID)
+}
@@ -0,0 +1,95 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+)
+
+// writeTimezone writes a VTIMEZONE for loc covering the years from to to.
+// Instead of recurrence rules, which Go's time zone database does not
+// expose, every offset change within these years is written as its own
+// STANDARD or DAYLIGHT observance. The offset in effect at the start of the
+// period is written as observance starting in 1970, so calendars that look
+// before the first change still resolve the right offset.
+func writeTimezone(w *writer, loc *time.Location, from, to int) {
+	start := time.Date(from, time.January, 1, 0, 0, 0, 0, time.UTC).Add(-24 * time.Hour)
+	end := time.Date(to+1, time.January, 1, 0, 0, 0, 0, time.UTC).Add(24 * time.Hour)
+
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", loc.String())
+
+	initial := start.In(loc)
+	_, offset := initial.Zone()
+	writeObservance(w, initial, "19700101T000000", offset)
+
+	for _, t := range transitions(loc, start, end) {
+		_, before := t.Add(-time.Second).In(loc).Zone()
+		onset := t.UTC().Add(time.Duration(before) * time.Second).Format(dateTime)
+		writeObservance(w, t.In(loc), onset, before)
+	}
+
+	w.line("END", "VTIMEZONE")
+}
+
+// writeObservance writes the observance in effect at t, which started at the
+// local time onset while offsetFrom was in effect.
+func writeObservance(w *writer, t time.Time, onset string, offsetFrom int) {
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	name, offset := t.Zone()
+
+	w.line("BEGIN", kind)
+	w.line("DTSTART", onset)
+	w.line("TZOFFSETFROM", utcOffset(offsetFrom))
+	w.line("TZOFFSETTO", utcOffset(offset))
+	if name != "" {
+		w.line("TZNAME", escape(name))
+	}
+	w.line("END", kind)
+}
+
+// transitions returns the instants between start and end at which the UTC
+// offset of loc changes. Zones change their offset at most a few times a
+// year, so they are searched day by day and then narrowed down to the second.
+func transitions(loc *time.Location, start, end time.Time) []time.Time {
+	var changes []time.Time
+	_, offset := start.In(loc).Zone()
+	for t := start; t.Before(end); t = t.Add(24 * time.Hour) {
+		next := t.Add(24 * time.Hour)
+		_, nextOffset := next.In(loc).Zone()
+		if nextOffset == offset {
+			continue
+		}
+
+		// The offset changes within (t, next]
+		lo, hi := t, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+			if _, o := mid.In(loc).Zone(); o == offset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		changes = append(changes, hi)
+		offset = nextOffset
+	}
+	return changes
+}
+
+// utcOffset formats an offset in seconds east of UTC as "+HHMM", with
+// seconds only if needed.
+func utcOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	s := fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf("%02d", seconds%60)
+	}
+	return s
+}
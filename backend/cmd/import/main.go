@@ -0,0 +1,142 @@
+// Command import loads airport and airline reference data from the public
+// OurAirports and OpenFlights data files into the MindenAirport database.
+// Records are upserted by IATA code, so the import can be repeated after
+// downloading newer files. Airline schedules in IATA SSIM format are expanded
+// into flights the same way, matched by flight number and date.
+//
+// Usage:
+//
+//	go run ./cmd/import -airports airports.csv -countries countries.csv -timezones airports.dat -dry-run
+//	go run ./cmd/import -airlines airlines.dat
+//	go run ./cmd/import -ssim summer.ssim -dry-run
+//
+// With -dry-run nothing is written and the changes are only reported.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/godror/godror" // Oracle database driver
+
+	"mindenairport/database"
+	"mindenairport/initializers"
+	"mindenairport/models"
+	"mindenairport/planning"
+	"mindenairport/refdata"
+	"mindenairport/schedule"
+)
+
+func main() {
+	var files refdata.AirportFiles
+	flag.StringVar(&files.Airports, "airports", "", "OurAirports airports.csv file")
+	flag.StringVar(&files.Countries, "countries", "", "OurAirports countries.csv file for country names (optional)")
+	flag.StringVar(&files.Timezones, "timezones", "", "OpenFlights airports.dat file for time zones (optional)")
+	airlines := flag.String("airlines", "", "OpenFlights airlines.dat file")
+	ssim := flag.String("ssim", "", "SSIM schedule file")
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	asJSON := flag.Bool("json", false, "print the reports as JSON")
+	flag.Parse()
+
+	if files.Airports == "" && *airlines == "" && *ssim == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Read all files before connecting, so typos fail fast
+	var airportSource refdata.AirportSource
+	var airlineSource refdata.AirlineSource
+	var carriers []schedule.Carrier
+	var err error
+	if files.Airports != "" {
+		if airportSource, err = refdata.ReadAirportFiles(files); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *airlines != "" {
+		if airlineSource, err = refdata.ReadAirlineFile(*airlines); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *ssim != "" {
+		if carriers, err = schedule.ReadSSIMFile(*ssim); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	initializers.LoadEnvs()
+	db := database.CreateConnection()
+	importer := refdata.NewImporter(db)
+
+	var reports []models.ImportReport
+	if files.Airports != "" {
+		report, err := importer.ImportAirports(airportSource, *dryRun)
+		reports = append(reports, report)
+		if err != nil {
+			printReports(reports, *asJSON)
+			log.Fatal(err)
+		}
+	}
+	if *airlines != "" {
+		report, err := importer.ImportAirlines(airlineSource, *dryRun)
+		reports = append(reports, report)
+		if err != nil {
+			printReports(reports, *asJSON)
+			log.Fatal(err)
+		}
+	}
+	// Flights last, since they need the imported airports and airlines
+	if *ssim != "" {
+		report, err := schedule.NewImporter(db, planning.NewPlanner(db)).ImportSSIM(carriers, *dryRun)
+		reports = append(reports, report)
+		if err != nil {
+			printReports(reports, *asJSON)
+			log.Fatal(err)
+		}
+	}
+
+	printReports(reports, *asJSON)
+}
+
+// printReports writes the import reports to stdout, either as JSON or as a
+// diff-like listing with + for new, ~ for changed, ! for skipped and x for
+// conflicting records.
+func printReports(reports []models.ImportReport, asJSON bool) {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	for _, report := range reports {
+		mode := ""
+		if report.DryRun {
+			mode = " (dry run)"
+		}
+		fmt.Printf("%s%s: %d read, %d ignored, %d created, %d updated, %d unchanged, %d skipped, %d conflicts\n",
+			report.Kind, mode, report.Read, report.Ignored, len(report.Created), len(report.Updated),
+			report.Unchanged, len(report.Skipped), len(report.Conflicts))
+
+		for _, c := range report.Created {
+			fmt.Printf("+ %s %s\n", c.ID, c.Name)
+		}
+		for _, c := range report.Updated {
+			fmt.Printf("~ %s %s\n", c.ID, c.Name)
+			for _, f := range c.Changes {
+				fmt.Printf("    %s: %q -> %q\n", f.Field, f.Old, f.New)
+			}
+		}
+		for _, s := range report.Skipped {
+			fmt.Printf("! line %d %s: %s\n", s.Line, s.ID, s.Reason)
+		}
+		for _, s := range report.Conflicts {
+			fmt.Printf("x line %d %s: %s\n", s.Line, s.ID, s.Reason)
+		}
+	}
+}
@@ -3,8 +3,12 @@ package database
 import (
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
 	"log"
 	"mindenairport/models"
+	"strconv"
+
+	"github.com/godror/godror"
 )
 
 func (db Database) GetAirlineByID(id string) models.Airline {
@@ -17,33 +21,20 @@ func (db Database) GetAirlineByID(id string) models.Airline {
 	}
 
 	var cursor driver.Rows
-	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
 	if err != nil {
 		log.Printf("Failed to execute prepared statement: %v", err)
 		return models.Airline{}
 	}
 
 	r := make([]driver.Value, len(cursor.Columns()))
-	err = cursor.Next(r)
-	if err != nil {
-		log.Println(err)
-	}
-
 	defer cursor.Close()
 
 	var airline models.Airline
 
-	for {
-		err := cursor.Next(r)
-		if err != nil {
-			break
-		}
-		var airline models.Airline
-		airline.ID = r[0].(string)
-		airline.Name = r[1].(string)
-		airline.Country = r[2].(string)
-		airline.Logo = r[3].(string)
-		airline.Active = r[4].(int64) == 1
+	err = cursor.Next(r)
+	if err == nil {
+		airline = airlineFromRow(r)
 	}
 
 	return airline
@@ -66,11 +57,6 @@ func (db Database) GetAirlines() []models.Airline {
 	}
 
 	r := make([]driver.Value, len(cursor.Columns()))
-	err = cursor.Next(r)
-	if err != nil {
-		log.Println(err)
-	}
-
 	defer cursor.Close()
 
 	var airlines []models.Airline
@@ -80,14 +66,74 @@ func (db Database) GetAirlines() []models.Airline {
 		if err != nil {
 			break
 		}
-		var airline models.Airline
-		airline.ID = r[0].(string)
-		airline.Name = r[1].(string)
-		airline.Country = r[2].(string)
-		airline.Logo = r[3].(string)
-		airline.Active = r[4].(int64) == 1
-		airlines = append(airlines, airline)
+		airlines = append(airlines, airlineFromRow(r))
 	}
 
 	return airlines
 }
+
+// CreateAirline inserts a new airline. The ID is the airline's IATA code and
+// has to be set by the caller.
+func (db Database) CreateAirline(airline models.Airline) error {
+	query := `BEGIN MindenAirport.CreateAirline(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, airline.ID, airline.ICAO, airline.Name, airline.Country, airline.Logo, boolToNumber(airline.Active))
+	return err
+}
+
+// UpdateAirline replaces all attributes of an existing airline.
+func (db Database) UpdateAirline(airline models.Airline) error {
+	query := `BEGIN MindenAirport.UpdateAirline(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, airline.ID, airline.ICAO, airline.Name, airline.Country, airline.Logo, boolToNumber(airline.Active))
+	return err
+}
+
+// DeleteAirline removes an airline. Airlines that still own planes cannot be deleted.
+func (db Database) DeleteAirline(id string) error {
+	query := `BEGIN MindenAirport.DeleteAirline(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// CountAirlineUsage counts the planes of an airline and the flights operated with them.
+func (db Database) CountAirlineUsage(id string) (planes int, flights int, err error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAirlineUsage(:1, :2); END;`)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	if err := cursor.Next(r); err != nil {
+		return 0, 0, err
+	}
+	planes, _ = strconv.Atoi(r[0].(godror.Number).String())
+	flights, _ = strconv.Atoi(r[1].(godror.Number).String())
+	return planes, flights, nil
+}
+
+// airlineFromRow maps a row of GetAllAirlines and GetAirlineByID onto a models.Airline.
+// Optional columns that are NULL keep their zero value.
+func airlineFromRow(r []driver.Value) models.Airline {
+	var airline models.Airline
+	airline.ID = r[0].(string)
+	airline.Name = r[1].(string)
+	if r[2] != nil {
+		airline.Country = r[2].(string)
+	}
+	if r[3] != nil {
+		airline.Logo = r[3].(string)
+	}
+	// NUMBER(1) may be returned as int64 or godror.Number depending on the driver settings
+	airline.Active = fmt.Sprint(r[4]) == "1"
+	if r[5] != nil {
+		airline.ICAO = r[5].(string)
+	}
+	return airline
+}
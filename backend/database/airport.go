@@ -26,11 +26,6 @@ func (db Database) GetAirports() []models.Airport {
 	}
 
 	r := make([]driver.Value, len(cursor.Columns()))
-	err = cursor.Next(r)
-	if err != nil {
-		log.Println(err) // column count mismatch: we have 10 columns, but given 0 destination
-	}
-
 	defer cursor.Close()
 
 	var airports []models.Airport
@@ -40,16 +35,7 @@ func (db Database) GetAirports() []models.Airport {
 		if err != nil {
 			break
 		}
-		var airport models.Airport
-		airport.ID = r[0].(string)
-		airport.Name = r[1].(string)
-		airport.Country = r[2].(string)
-		airport.City = r[3].(string)
-		airport.Timezone = r[4].(string)
-		airport.Elevation, _ = strconv.ParseFloat(r[5].(godror.Number).String(), 64)
-		airport.Latitude, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
-		airport.Longitude, _ = strconv.ParseFloat(r[8].(godror.Number).String(), 64)
-		airports = append(airports, airport)
+		airports = append(airports, airportFromRow(r))
 	}
 
 	return airports
@@ -76,16 +62,85 @@ func (db Database) GetAirportByID(id string) models.Airport {
 
 	err = cursor.Next(r)
 	if err == nil {
-		airport.ID = r[0].(string)
+		airport = airportFromRow(r)
+	}
+
+	return airport
+}
+
+// CreateAirport inserts a new airport. The ID is the airport's IATA code and
+// has to be set by the caller.
+func (db Database) CreateAirport(airport models.Airport) error {
+	query := `BEGIN MindenAirport.CreateAirport(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10); END;`
+	_, err := db.Exec(query, airport.ID, airport.ICAO, airport.Name, airport.Country, airport.City, airport.Timezone,
+		airport.Elevation, airport.NumberOfTerminal, airport.Latitude, airport.Longitude)
+	return err
+}
+
+// UpdateAirport replaces all attributes of an existing airport.
+func (db Database) UpdateAirport(airport models.Airport) error {
+	query := `BEGIN MindenAirport.UpdateAirport(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10); END;`
+	_, err := db.Exec(query, airport.ID, airport.ICAO, airport.Name, airport.Country, airport.City, airport.Timezone,
+		airport.Elevation, airport.NumberOfTerminal, airport.Latitude, airport.Longitude)
+	return err
+}
+
+// DeleteAirport removes an airport. Airports still used by flights cannot be deleted.
+func (db Database) DeleteAirport(id string) error {
+	query := `BEGIN MindenAirport.DeleteAirport(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// CountAirportFlights counts the flights departing from or arriving at an airport.
+func (db Database) CountAirportFlights(id string) (int, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAirportUsage(:1, :2); END;`)
+	if err != nil {
+		return 0, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return 0, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	if err := cursor.Next(r); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(r[0].(godror.Number).String())
+}
+
+// airportFromRow maps a row of GetAllAirports and GetAirportByID onto a models.Airport.
+// Optional columns that are NULL keep their zero value.
+func airportFromRow(r []driver.Value) models.Airport {
+	var airport models.Airport
+	airport.ID = r[0].(string)
+	if r[1] != nil {
 		airport.Name = r[1].(string)
-		airport.Country = r[2].(string)
-		airport.City = r[3].(string)
+	}
+	airport.Country = r[2].(string)
+	airport.City = r[3].(string)
+	if r[4] != nil {
 		airport.Timezone = r[4].(string)
+	}
+	if r[5] != nil {
 		airport.Elevation, _ = strconv.ParseFloat(r[5].(godror.Number).String(), 64)
+	}
+	if r[6] != nil {
 		airport.NumberOfTerminal, _ = strconv.Atoi(r[6].(godror.Number).String())
+	}
+	if r[7] != nil {
 		airport.Latitude, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
+	}
+	if r[8] != nil {
 		airport.Longitude, _ = strconv.ParseFloat(r[8].(godror.Number).String(), 64)
 	}
-
+	if r[9] != nil {
+		airport.ICAO = r[9].(string)
+	}
 	return airport
 }
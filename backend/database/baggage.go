@@ -41,6 +41,9 @@ func (db Database) GetBaggageByID(id string) (*models.Baggage, error) {
 		if r[7] != nil {
 			baggage.SpecialHandling = r[7].(string)
 		}
+		if r[8] != nil {
+			baggage.CompanionID = r[8].(string)
+		}
 		return &baggage, nil
 	}
 
@@ -81,6 +84,9 @@ func (db Database) GetBaggageByUserID(userID string) ([]models.Baggage, error) {
 		if r[7] != nil {
 			baggage.SpecialHandling = r[7].(string)
 		}
+		if r[8] != nil {
+			baggage.CompanionID = r[8].(string)
+		}
 		baggageList = append(baggageList, baggage)
 	}
 
@@ -121,6 +127,9 @@ func (db Database) GetBaggageByFlightID(flightID string) ([]models.Baggage, erro
 		if r[7] != nil {
 			baggage.SpecialHandling = r[7].(string)
 		}
+		if r[8] != nil {
+			baggage.CompanionID = r[8].(string)
+		}
 		baggageList = append(baggageList, baggage)
 	}
 
@@ -140,7 +149,7 @@ func (db Database) CreateBaggage(baggage models.Baggage) (*models.Baggage, error
 	}
 
 	// Call stored procedure
-	query := `BEGIN MindenAirport.CreateBaggage(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	query := `BEGIN MindenAirport.CreateBaggage(:1, :2, :3, :4, :5, :6, :7, :8, :9); END;`
 	_, err := db.Exec(query,
 		baggage.ID,
 		baggage.AirportUserID,
@@ -150,6 +159,7 @@ func (db Database) CreateBaggage(baggage models.Baggage) (*models.Baggage, error
 		baggage.TrackingNumber,
 		baggage.Status,
 		baggage.SpecialHandling,
+		baggage.CompanionID,
 	)
 
 	if err != nil {
@@ -163,7 +173,7 @@ func (db Database) CreateBaggage(baggage models.Baggage) (*models.Baggage, error
 // UpdateBaggage updates an existing baggage entry
 func (db Database) UpdateBaggage(id string, baggage models.Baggage) (*models.Baggage, error) {
 	// Call stored procedure
-	query := `BEGIN MindenAirport.UpdateBaggage(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	query := `BEGIN MindenAirport.UpdateBaggage(:1, :2, :3, :4, :5, :6, :7, :8, :9); END;`
 	_, err := db.Exec(query,
 		id,
 		baggage.AirportUserID,
@@ -173,6 +183,7 @@ func (db Database) UpdateBaggage(id string, baggage models.Baggage) (*models.Bag
 		baggage.TrackingNumber,
 		baggage.Status,
 		baggage.SpecialHandling,
+		baggage.CompanionID,
 	)
 
 	if err != nil {
@@ -222,6 +233,9 @@ func (db Database) GetBaggageByTrackingNumber(trackingNumber string) (*models.Ba
 		if r[7] != nil {
 			baggage.SpecialHandling = r[7].(string)
 		}
+		if r[8] != nil {
+			baggage.CompanionID = r[8].(string)
+		}
 		return &baggage, nil
 	}
 
@@ -254,6 +268,7 @@ func (db Database) GetAllBaggage(page, limit int) ([]models.Baggage, int, error)
 
 	for cursor.Next() {
 		var baggage models.Baggage
+		var companion sql.NullString
 		err := cursor.Scan(
 			&baggage.ID,
 			&baggage.AirportUserID,
@@ -263,10 +278,12 @@ func (db Database) GetAllBaggage(page, limit int) ([]models.Baggage, int, error)
 			&baggage.TrackingNumber,
 			&baggage.Status,
 			&baggage.SpecialHandling,
+			&companion,
 		)
 		if err != nil {
 			return nil, 0, err
 		}
+		baggage.CompanionID = companion.String
 		baggageList = append(baggageList, baggage)
 	}
 
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetDeniedBoardingByFlight retrieves the volunteers and denied boardings of
+// a flight in the order they were recorded.
+func (db Database) GetDeniedBoardingByFlight(flightID string) ([]models.DeniedBoarding, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetDeniedBoardingByFlight(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var records []models.DeniedBoarding
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		records = append(records, deniedBoardingFromRow(r))
+	}
+
+	return records, nil
+}
+
+// GetDeniedBoardingByTicket retrieves the volunteer or denied boarding
+// record of a ticket.
+//
+// Returns:
+//   - *models.DeniedBoarding: The record if found, nil if not found
+//   - error: Any database error
+func (db Database) GetDeniedBoardingByTicket(ticketID string) (*models.DeniedBoarding, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetDeniedBoardingByTicket(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(ticketID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		record := deniedBoardingFromRow(r)
+		return &record, nil
+	}
+
+	return nil, nil
+}
+
+// deniedBoardingFromRow maps a row of GetDeniedBoardingByFlight or
+// GetDeniedBoardingByTicket onto a models.DeniedBoarding.
+func deniedBoardingFromRow(r []driver.Value) models.DeniedBoarding {
+	var record models.DeniedBoarding
+	record.ID = r[0].(string)
+	record.TicketID = r[1].(string)
+	record.FlightID = r[2].(string)
+	record.Status = r[3].(string)
+	// NUMBER(1) may be returned as int64 or godror.Number depending on the driver settings
+	record.Voluntary = fmt.Sprint(r[4]) == "1"
+	if r[5] != nil {
+		amount, _ := strconv.ParseFloat(r[5].(godror.Number).String(), 64)
+		record.RequestedCompensation = &amount
+	}
+	if r[6] != nil {
+		amount, _ := strconv.ParseFloat(r[6].(godror.Number).String(), 64)
+		record.Compensation = &amount
+	}
+	if r[7] != nil {
+		record.Currency = r[7].(string)
+	}
+	if r[8] != nil {
+		record.Note = r[8].(string)
+	}
+	record.CreatedAt = r[9].(time.Time)
+	if r[10] != nil {
+		decidedAt := r[10].(time.Time)
+		record.DecidedAt = &decidedAt
+	}
+	if r[11] != nil {
+		record.DecidedBy = r[11].(string)
+	}
+	if r[12] != nil {
+		record.PassengerName = r[12].(string)
+	}
+	if r[13] != nil {
+		record.TicketStatus = r[13].(string)
+	}
+	return record
+}
+
+// CreateBoardingVolunteer records that the passenger of a ticket volunteers
+// to give up their seat. The ID and creation time are generated if not set.
+func (db Database) CreateBoardingVolunteer(record *models.DeniedBoarding) error {
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().UTC()
+	}
+	record.Status = "VOLUNTEER"
+	record.Voluntary = true
+
+	var requested interface{}
+	if record.RequestedCompensation != nil {
+		requested = *record.RequestedCompensation
+	}
+
+	query := `BEGIN MindenAirport.CreateBoardingVolunteer(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, record.ID, record.TicketID, record.FlightID, requested, record.Currency, record.CreatedAt)
+	return err
+}
+
+// DeleteBoardingVolunteer withdraws the volunteer of a ticket. It returns
+// false if the ticket did not volunteer or was already denied boarding.
+func (db Database) DeleteBoardingVolunteer(ticketID string) (bool, error) {
+	var deleted int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.DeleteBoardingVolunteer(:1, :2); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(ticketID, sql.Out{Dest: &deleted})
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// DenyBoarding sets a ticket to denied boarding and records the
+// compensation, completing the volunteer record of the ticket if there is
+// one. It returns false if the ticket no longer holds a seat.
+func (db Database) DenyBoarding(ticket models.Ticket, compensation float64, currency, note, staffID string, now time.Time) (bool, error) {
+	var updated int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.DenyBoarding(:1, :2, :3, :4, :5, :6, :7, :8, :9); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(uuid.New().String(), ticket.ID, ticket.Flight, compensation, currency, note, now.UTC(), staffID,
+		sql.Out{Dest: &updated})
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
@@ -0,0 +1,307 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetBookingByLocator retrieves a booking without its passengers and tickets.
+//
+// Returns:
+//   - *models.Booking: The booking if found, nil if not found
+//   - error: Any database error
+func (db Database) GetBookingByLocator(locator string) (*models.Booking, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetBookingByLocator(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(locator, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		booking := bookingFromRow(r)
+		return &booking, nil
+	}
+
+	return nil, nil
+}
+
+// GetBookingsByUserID retrieves the bookings a user made or travels on,
+// newest first, without their passengers and tickets.
+func (db Database) GetBookingsByUserID(userID string) ([]models.Booking, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetBookingsByUserID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(userID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var bookings []models.Booking
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		bookings = append(bookings, bookingFromRow(r))
+	}
+
+	return bookings, nil
+}
+
+// GetExpiredHolds retrieves the held bookings whose hold has expired at now,
+// without their passengers and tickets.
+func (db Database) GetExpiredHolds(now time.Time) ([]models.Booking, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetExpiredHolds(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(now.UTC(), sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var bookings []models.Booking
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		bookings = append(bookings, bookingFromRow(r))
+	}
+
+	return bookings, nil
+}
+
+// bookingFromRow maps a row of GetBookingByLocator, GetBookingsByUserID or
+// GetExpiredHolds onto a models.Booking.
+func bookingFromRow(r []driver.Value) models.Booking {
+	booking := models.Booking{
+		ID:            r[0].(string),
+		Locator:       r[1].(string),
+		AirportUserID: r[2].(string),
+		Status:        r[3].(string),
+		CreatedAt:     r[4].(time.Time),
+	}
+	if r[5] != nil {
+		holdExpiresAt := r[5].(time.Time)
+		booking.HoldExpiresAt = &holdExpiresAt
+	}
+	return booking
+}
+
+// GetBookingPassengers retrieves the passengers of a booking in the order
+// they were booked.
+func (db Database) GetBookingPassengers(bookingID string) ([]models.BookingPassenger, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetBookingPassengers(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(bookingID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var passengers []models.BookingPassenger
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var p models.BookingPassenger
+		p.ID = r[0].(string)
+		p.FirstName = r[1].(string)
+		p.LastName = r[2].(string)
+		if r[3] != nil {
+			birthDate := r[3].(time.Time)
+			p.BirthDate = &birthDate
+		}
+		if r[4] != nil {
+			p.AirportUserID = r[4].(string)
+		}
+		if r[5] != nil {
+			p.CompanionID = r[5].(string)
+		}
+		passengers = append(passengers, p)
+	}
+
+	return passengers, nil
+}
+
+// GetBookingTickets retrieves the tickets of a booking ordered by departure.
+func (db Database) GetBookingTickets(bookingID string) ([]models.Ticket, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetBookingTickets(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(bookingID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var tickets []models.Ticket
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		tickets = append(tickets, ticketFromRow(r))
+	}
+
+	return tickets, nil
+}
+
+// GetFlightTicketCount returns the number of tickets of a flight that are
+// not cancelled.
+func (db Database) GetFlightTicketCount(flightID string) (int, error) {
+	var count int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightTicketCount(:1, :2); END;`)
+	if err != nil {
+		return 0, err
+	}
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &count})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CreateBooking inserts a held booking with its passengers and issues a
+// held ticket for every passenger on every flight at the fare of the flight
+// by ID, all in one transaction. IDs are generated for the booking and
+// passengers if not set.
+func (db Database) CreateBooking(booking *models.Booking, flights []models.BookingFlightRequest, fares map[string]float64) error {
+	if booking.ID == "" {
+		booking.ID = uuid.New().String()
+	}
+	if booking.CreatedAt.IsZero() {
+		booking.CreatedAt = time.Now().UTC()
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var holdExpiresAt interface{}
+	if booking.HoldExpiresAt != nil {
+		holdExpiresAt = *booking.HoldExpiresAt
+	}
+	_, err = tx.Exec(`BEGIN MindenAirport.CreateBooking(:1, :2, :3, :4, :5); END;`,
+		booking.ID, booking.Locator, booking.AirportUserID, booking.CreatedAt, holdExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range booking.Passengers {
+		p := &booking.Passengers[i]
+		if p.ID == "" {
+			p.ID = uuid.New().String()
+		}
+
+		var birthDate interface{}
+		if p.BirthDate != nil {
+			birthDate = *p.BirthDate
+		}
+		_, err = tx.Exec(`BEGIN MindenAirport.AddBookingPassenger(:1, :2, :3, :4, :5, :6, :7, :8); END;`,
+			p.ID, booking.ID, i+1, p.FirstName, p.LastName, birthDate, p.AirportUserID, p.CompanionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, flight := range flights {
+		for _, p := range booking.Passengers {
+			_, err = tx.Exec(`BEGIN MindenAirport.AddBookingTicket(:1, :2, :3, :4, :5, :6, :7, :8); END;`,
+				uuid.New().String(), booking.ID, p.ID, booking.AirportUserID, flight.FlightID, flight.TravelClass,
+				fares[flight.FlightID], booking.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CancelTicket sets a held or confirmed ticket to cancelled. It returns
+// false if the ticket was neither.
+func (db Database) CancelTicket(ticketID string) (bool, error) {
+	var updated int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.CancelTicket(:1, :2); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(ticketID, sql.Out{Dest: &updated})
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+// UpdateBookingStatus sets the status of a booking.
+func (db Database) UpdateBookingStatus(id, status string) error {
+	query := `BEGIN MindenAirport.UpdateBookingStatus(:1, :2); END;`
+	_, err := db.Exec(query, id, status)
+	return err
+}
+
+// ConfirmBooking confirms a held booking and its held tickets. It returns
+// false if the booking was not held.
+func (db Database) ConfirmBooking(id string) (bool, error) {
+	var updated int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.ConfirmBooking(:1, :2); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(id, sql.Out{Dest: &updated})
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"time"
+)
+
+// GetCalendarSubscription retrieves the calendar subscription of a user.
+//
+// Returns:
+//   - *models.CalendarSubscription: The subscription, nil if the user has none
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetCalendarSubscription(userID string) (*models.CalendarSubscription, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetCalendarSubscription(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(userID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		return &models.CalendarSubscription{
+			AirportUserID: r[0].(string),
+			CreatedAt:     r[1].(time.Time),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// GetCalendarSubscriber retrieves the ID of the user a subscription token
+// belongs to, "" if the token is unknown.
+func (db Database) GetCalendarSubscriber(tokenHash string) (string, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetCalendarSubscriber(:1, :2); END;`)
+	if err != nil {
+		return "", err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(tokenHash, sql.Out{Dest: &cursor})
+	if err != nil {
+		return "", err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		return r[0].(string), nil
+	}
+
+	return "", nil
+}
+
+// SaveCalendarSubscription stores the token hash of a user's subscription,
+// replacing and thereby revoking any previous token.
+func (db Database) SaveCalendarSubscription(userID, tokenHash string, createdAt time.Time) error {
+	query := `BEGIN MindenAirport.SaveCalendarSubscription(:1, :2, :3); END;`
+	_, err := db.Exec(query, userID, tokenHash, createdAt)
+	return err
+}
+
+// DeleteCalendarSubscription revokes the calendar subscription of a user.
+func (db Database) DeleteCalendarSubscription(userID string) error {
+	query := `BEGIN MindenAirport.DeleteCalendarSubscription(:1); END;`
+	_, err := db.Exec(query, userID)
+	return err
+}
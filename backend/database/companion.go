@@ -0,0 +1,121 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetCompanions retrieves the companions managed by a user, ordered by name.
+func (db Database) GetCompanions(userID string) ([]models.Companion, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetCompanions(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(userID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var companions []models.Companion
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		companions = append(companions, companionFromRow(r))
+	}
+
+	return companions, nil
+}
+
+// GetCompanionByID retrieves a specific companion.
+//
+// Returns:
+//   - *models.Companion: The companion if found, nil if not found
+//   - error: Any database error
+func (db Database) GetCompanionByID(id string) (*models.Companion, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetCompanionByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		companion := companionFromRow(r)
+		return &companion, nil
+	}
+
+	return nil, nil
+}
+
+// companionFromRow maps a row of GetCompanions or GetCompanionByID onto a
+// models.Companion.
+func companionFromRow(r []driver.Value) models.Companion {
+	return models.Companion{
+		ID:            r[0].(string),
+		AirportUserID: r[1].(string),
+		FirstName:     r[2].(string),
+		LastName:      r[3].(string),
+		BirthDate:     r[4].(time.Time),
+		CreatedAt:     r[5].(time.Time),
+	}
+}
+
+// CreateCompanion inserts a new companion. A new ID is generated if none is set.
+func (db Database) CreateCompanion(companion *models.Companion) error {
+	if companion.ID == "" {
+		companion.ID = uuid.New().String()
+	}
+	if companion.CreatedAt.IsZero() {
+		companion.CreatedAt = time.Now().UTC()
+	}
+
+	query := `BEGIN MindenAirport.CreateCompanion(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, companion.ID, companion.AirportUserID, companion.FirstName, companion.LastName,
+		companion.BirthDate, companion.CreatedAt)
+	return err
+}
+
+// UpdateCompanion replaces the name and birth date of a companion.
+func (db Database) UpdateCompanion(companion models.Companion) error {
+	query := `BEGIN MindenAirport.UpdateCompanion(:1, :2, :3, :4); END;`
+	_, err := db.Exec(query, companion.ID, companion.FirstName, companion.LastName, companion.BirthDate)
+	return err
+}
+
+// DeleteCompanion removes a companion and their travel documents. It returns
+// false if the companion still travels on a flight that has not departed
+// by now.
+func (db Database) DeleteCompanion(id string, now time.Time) (bool, error) {
+	var deleted int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.DeleteCompanion(:1, :2, :3); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(id, now, sql.Out{Dest: &deleted})
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
@@ -0,0 +1,204 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetCrewMembers retrieves all crew members ordered by last name.
+func (db Database) GetCrewMembers() ([]models.CrewMember, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllCrewMembers(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var members []models.CrewMember
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		members = append(members, crewMemberFromRow(r))
+	}
+
+	return members, nil
+}
+
+// GetCrewMemberByID retrieves a specific crew member.
+//
+// Returns:
+//   - *models.CrewMember: The crew member if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetCrewMemberByID(id string) (*models.CrewMember, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetCrewMemberByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		member := crewMemberFromRow(r)
+		return &member, nil
+	}
+
+	return nil, nil
+}
+
+// CreateCrewMember inserts a new crew member. A new ID is generated if none is set.
+func (db Database) CreateCrewMember(member *models.CrewMember) error {
+	if member.ID == "" {
+		member.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateCrewMember(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, member.ID, member.FirstName, member.LastName, member.Role,
+		member.LicenseNumber, member.LicenseExpiry)
+	return err
+}
+
+// UpdateCrewMember replaces all attributes of an existing crew member.
+func (db Database) UpdateCrewMember(member models.CrewMember) error {
+	query := `BEGIN MindenAirport.UpdateCrewMember(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, member.ID, member.FirstName, member.LastName, member.Role,
+		member.LicenseNumber, member.LicenseExpiry)
+	return err
+}
+
+// DeleteCrewMember removes a crew member. Crew members still assigned to
+// flights cannot be deleted.
+func (db Database) DeleteCrewMember(id string) error {
+	query := `BEGIN MindenAirport.DeleteCrewMember(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// GetFlightCrew retrieves the crew assigned to a flight.
+func (db Database) GetFlightCrew(flightID string) ([]models.FlightCrew, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightCrew(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var crew []models.FlightCrew
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		crew = append(crew, models.FlightCrew{
+			ID:           r[0].(string),
+			FlightID:     r[1].(string),
+			CrewMemberID: r[2].(string),
+			Role:         r[3].(string),
+		})
+	}
+
+	return crew, nil
+}
+
+// AssignFlightCrew assigns a crew member to a flight. A new ID is generated if none is set.
+func (db Database) AssignFlightCrew(assignment *models.FlightCrew) error {
+	if assignment.ID == "" {
+		assignment.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateFlightCrew(:1, :2, :3, :4); END;`
+	_, err := db.Exec(query, assignment.ID, assignment.FlightID, assignment.CrewMemberID, assignment.Role)
+	return err
+}
+
+// RemoveFlightCrew removes a crew assignment from a flight.
+func (db Database) RemoveFlightCrew(flightID, assignmentID string) error {
+	query := `BEGIN MindenAirport.DeleteFlightCrew(:1, :2); END;`
+	_, err := db.Exec(query, flightID, assignmentID)
+	return err
+}
+
+// GetCrewDuties retrieves the flights of a crew member departing within
+// [start, end), ordered by departure.
+func (db Database) GetCrewDuties(crewMemberID string, start, end time.Time) ([]models.CrewDuty, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetCrewDuties(:1, :2, :3, :4); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(crewMemberID, start, end, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var duties []models.CrewDuty
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		duties = append(duties, models.CrewDuty{
+			AssignmentID:       r[0].(string),
+			CrewMemberID:       r[1].(string),
+			FlightID:           r[2].(string),
+			Role:               r[3].(string),
+			From:               r[4].(string),
+			To:                 r[5].(string),
+			ScheduledDeparture: utcTimestamp(r[6].(time.Time)),
+			ScheduledArrival:   utcTimestamp(r[7].(time.Time)),
+		})
+	}
+
+	return duties, nil
+}
+
+// crewMemberFromRow maps a row of the crew member procedures onto a models.CrewMember.
+func crewMemberFromRow(r []driver.Value) models.CrewMember {
+	var member models.CrewMember
+	member.ID = r[0].(string)
+	member.FirstName = r[1].(string)
+	member.LastName = r[2].(string)
+	member.Role = r[3].(string)
+	if r[4] != nil {
+		member.LicenseNumber = r[4].(string)
+	}
+	if r[5] != nil {
+		t := r[5].(time.Time)
+		member.LicenseExpiry = &t
+	}
+	return member
+}
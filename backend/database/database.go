@@ -8,8 +8,21 @@ import (
 	"fmt"
 	"log"
 	"os"
+
+	"github.com/godror/godror"
 )
 
+// oraChildRecordFound is ORA-02292, raised when a delete would orphan rows
+// that still reference the deleted record.
+const oraChildRecordFound = 2292
+
+// IsChildRecordError reports whether err is an Oracle integrity constraint
+// violation caused by deleting a record that other rows still reference.
+func IsChildRecordError(err error) bool {
+	oraErr, ok := godror.AsOraErr(err)
+	return ok && oraErr.Code() == oraChildRecordFound
+}
+
 // Database wraps sql.DB to provide additional functionality and easier testing.
 // It serves as the main interface for all database operations in the application.
 type Database struct {
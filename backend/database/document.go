@@ -0,0 +1,196 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"mindenairport/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const documentDateLayout = "2006-01-02"
+
+// FieldCipher encrypts personal data before it is stored and decrypts it
+// when it is read.
+type FieldCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// GetTravelDocuments retrieves the travel documents of a user and their
+// companions, oldest first, decrypting their personal data with cipher.
+func (db Database) GetTravelDocuments(userID string, cipher FieldCipher) ([]models.TravelDocument, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetTravelDocuments(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(userID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var documents []models.TravelDocument
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		document, err := travelDocumentFromRow(r, cipher)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+// GetTravelDocumentByID retrieves a specific travel document.
+//
+// Returns:
+//   - *models.TravelDocument: The document if found, nil if not found
+//   - error: Any database or decryption error
+func (db Database) GetTravelDocumentByID(id string, cipher FieldCipher) (*models.TravelDocument, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetTravelDocumentByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		document, err := travelDocumentFromRow(r, cipher)
+		if err != nil {
+			return nil, err
+		}
+		return &document, nil
+	}
+
+	return nil, nil
+}
+
+// travelDocumentFromRow maps a row of the travel document procedures onto a
+// models.TravelDocument, decrypting the personal data.
+func travelDocumentFromRow(r []driver.Value, cipher FieldCipher) (models.TravelDocument, error) {
+	var document models.TravelDocument
+	document.ID = r[0].(string)
+	document.AirportUserID = r[1].(string)
+	document.Type = r[2].(string)
+	document.CreatedAt = r[8].(time.Time)
+	if r[9] != nil {
+		document.CompanionID = r[9].(string)
+	}
+
+	var expiry string
+	fields := []*string{&document.Number, &document.Nationality, &document.IssuingCountry, &expiry, &document.Sex}
+	for i, field := range fields {
+		value, err := cipher.Decrypt(r[3+i].(string))
+		if err != nil {
+			return document, fmt.Errorf("travel document %s: %w", document.ID, err)
+		}
+		*field = value
+	}
+
+	var err error
+	document.ExpiryDate, err = time.Parse(documentDateLayout, expiry)
+	if err != nil {
+		return document, fmt.Errorf("travel document %s: invalid expiry date: %w", document.ID, err)
+	}
+	return document, nil
+}
+
+// encryptTravelDocument returns the encrypted number, nationality, issuing
+// country, expiry date and sex of a document.
+func encryptTravelDocument(document models.TravelDocument, cipher FieldCipher) ([]interface{}, error) {
+	values := []string{
+		document.Number,
+		document.Nationality,
+		document.IssuingCountry,
+		document.ExpiryDate.Format(documentDateLayout),
+		document.Sex,
+	}
+
+	encrypted := make([]interface{}, len(values))
+	for i, value := range values {
+		ciphertext, err := cipher.Encrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		encrypted[i] = ciphertext
+	}
+	return encrypted, nil
+}
+
+// CreateTravelDocument inserts a new travel document with its personal data
+// encrypted. A new ID is generated if none is set.
+func (db Database) CreateTravelDocument(document *models.TravelDocument, cipher FieldCipher) error {
+	if document.ID == "" {
+		document.ID = uuid.New().String()
+	}
+	if document.CreatedAt.IsZero() {
+		document.CreatedAt = time.Now().UTC()
+	}
+
+	encrypted, err := encryptTravelDocument(*document, cipher)
+	if err != nil {
+		return err
+	}
+
+	query := `BEGIN MindenAirport.CreateTravelDocument(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10); END;`
+	args := append([]interface{}{document.ID, document.AirportUserID, document.Type}, encrypted...)
+	_, err = db.Exec(query, append(args, document.CreatedAt, document.CompanionID)...)
+	return err
+}
+
+// UpdateTravelDocument replaces the attributes of an existing travel document.
+func (db Database) UpdateTravelDocument(document models.TravelDocument, cipher FieldCipher) error {
+	encrypted, err := encryptTravelDocument(document, cipher)
+	if err != nil {
+		return err
+	}
+
+	query := `BEGIN MindenAirport.UpdateTravelDocument(:1, :2, :3, :4, :5, :6, :7); END;`
+	_, err = db.Exec(query, append([]interface{}{document.ID, document.Type}, encrypted...)...)
+	return err
+}
+
+// DeleteTravelDocument removes a travel document.
+func (db Database) DeleteTravelDocument(id string) error {
+	query := `BEGIN MindenAirport.DeleteTravelDocument(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// CheckInTicket sets a confirmed ticket to checked in and records the travel
+// document shown, which may be empty. It returns false if the ticket was not
+// confirmed, e.g. because it was checked in concurrently.
+func (db Database) CheckInTicket(ticketID, documentID string) (bool, error) {
+	var updated int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.CheckInTicket(:1, :2, :3); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(ticketID, documentID, sql.Out{Dest: &updated})
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/godror/godror"
+	"github.com/google/uuid"
 )
 
 // GetFlightByID retrieves a specific flight from the database by its unique identifier.
@@ -24,10 +25,7 @@ import (
 //   - models.Flight: The flight record with all details
 //   - error: Any database error that occurred during retrieval
 func (db Database) GetFlightByID(id string) (models.Flight, error) {
-	stmt, err := db.Prepare(`
-	BEGIN 
-	GetFlightByID(:1, :2); END;
-	`)
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightByID(:1, :2); END;`)
 	if err != nil {
 		return models.Flight{}, fmt.Errorf("error preparing statement: %w", err)
 	}
@@ -45,37 +43,7 @@ func (db Database) GetFlightByID(id string) (models.Flight, error) {
 
 	err = cursor.Next(r)
 	if err == nil {
-		flight.ID = r[0].(string)
-		flight.From = r[1].(string)
-		flight.To = r[2].(string)
-		flight.PilotID = r[3].(string)
-		flight.PlaneID = r[4].(string)
-		if r[5] != nil {
-			flight.TerminalID = r[5].(string)
-		}
-		if r[6] != nil {
-			flight.StatusID, _ = strconv.Atoi(r[6].(godror.Number).String())
-		}
-		if r[7] != nil {
-			flight.ScheduledDeparture = r[7].(time.Time)
-		}
-		if r[8] != nil {
-			t := r[8].(time.Time)
-			flight.ActualDeparture = &t
-		}
-		if r[9] != nil {
-			flight.ScheduledArrival = r[9].(time.Time)
-		}
-		if r[10] != nil {
-			t := r[10].(time.Time)
-			flight.ActualArrival = &t
-		}
-		if r[11] != nil {
-			flight.Gate = r[11].(string)
-		}
-		if r[12] != nil {
-			flight.BaggageClaim = r[12].(string)
-		}
+		flight = flightFromRow(r)
 	}
 
 	return flight, nil
@@ -133,58 +101,63 @@ func (db Database) GetAllFlights(page, limit int) ([]models.Flight, int, error)
 		if err != nil {
 			break
 		}
-		var flight models.Flight
-		flight.ID = r[0].(string)
-		flight.From = r[1].(string)
-		flight.To = r[2].(string)
-		flight.PilotID = r[3].(string)
-		flight.PlaneID = r[4].(string)
-		if r[5] != nil {
-			flight.TerminalID = r[5].(string)
-		}
-		if r[6] != nil {
-			flight.StatusID, _ = strconv.Atoi(r[6].(godror.Number).String())
-		}
-		if r[7] != nil {
-			flight.ScheduledDeparture = r[7].(time.Time)
-		}
-		if r[8] != nil {
-			t := r[8].(time.Time)
-			flight.ActualDeparture = &t
-		}
-		if r[9] != nil {
-			flight.ScheduledArrival = r[9].(time.Time)
-		}
-		if r[10] != nil {
-			t := r[10].(time.Time)
-			flight.ActualArrival = &t
-		}
-		if r[11] != nil {
-			flight.Gate = r[11].(string)
-		}
-		if r[12] != nil {
-			flight.BaggageClaim = r[12].(string)
-		}
-		flightList = append(flightList, flight)
+		flightList = append(flightList, flightFromRow(r))
 	}
 
 	return flightList, total, nil
 }
 
-func (db Database) CreateFlight(flight models.Flight) {
-	query := `BEGIN MindenAirport.CreateFlight(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13); END;`
-	_, err := db.Exec(query, flight.ID, flight.From, flight.To, flight.PilotID, flight.PlaneID, flight.TerminalID, flight.StatusID, flight.ScheduledDeparture, flight.ActualDeparture, flight.ScheduledArrival, flight.ActualArrival, flight.Gate, flight.BaggageClaim)
+// GetFlightsInWindow retrieves all flights whose scheduled departure lies
+// between start and end (both inclusive), ordered by scheduled departure.
+// It is used to check flights for overlapping resource assignments.
+func (db Database) GetFlightsInWindow(start, end time.Time) ([]models.Flight, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightsByDepartureWindow(:1, :2, :3); END;`)
 	if err != nil {
-		log.Fatal("Error calling stored procedure:", err)
+		return nil, err
 	}
-}
 
-func (db Database) UpdateFlight(flight models.Flight) {
-	query := `BEGIN MindenAirport.UpdateFlight(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13); END;`
-	_, err := db.Exec(query, flight.ID, flight.From, flight.To, flight.PilotID, flight.PlaneID, flight.TerminalID, flight.StatusID, flight.ScheduledDeparture, flight.ActualDeparture, flight.ScheduledArrival, flight.ActualArrival, flight.Gate, flight.BaggageClaim)
+	var cursor driver.Rows
+	_, err = stmt.Exec(start, end, sql.Out{Dest: &cursor})
 	if err != nil {
-		log.Fatal("Error calling stored procedure:", err)
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var flightList []models.Flight
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		flightList = append(flightList, flightFromRow(r))
+	}
+
+	return flightList, nil
+}
+
+// CreateFlight inserts a new flight. A new ID is generated if none is set.
+// The flight times are converted to UTC.
+func (db Database) CreateFlight(flight *models.Flight) error {
+	if flight.ID == "" {
+		flight.ID = uuid.New().String()
 	}
+	flightTimesToUTC(flight)
+
+	query := `BEGIN MindenAirport.CreateFlight(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13, :14, :15, :16); END;`
+	_, err := db.Exec(query, flight.ID, flight.From, flight.To, flight.PilotID, flight.PlaneID, flight.TerminalID, flight.StatusID, flight.ScheduledDeparture, flight.ActualDeparture, flight.ScheduledArrival, flight.ActualArrival, flight.Gate, flight.BaggageClaim, flight.FlightNumber, flight.TemplateID, flight.TemplateRevision)
+	return err
+}
+
+// UpdateFlight stores the given flight, replacing all fields of the record with the same ID.
+// The flight times are stored in UTC. The template link is not changed.
+func (db Database) UpdateFlight(flight models.Flight) error {
+	flightTimesToUTC(&flight)
+	query := `BEGIN MindenAirport.UpdateFlight(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13, :14); END;`
+	_, err := db.Exec(query, flight.ID, flight.From, flight.To, flight.PilotID, flight.PlaneID, flight.TerminalID, flight.StatusID, flight.ScheduledDeparture, flight.ActualDeparture, flight.ScheduledArrival, flight.ActualArrival, flight.Gate, flight.BaggageClaim, flight.FlightNumber)
+	return err
 }
 
 func (db Database) DeleteFlight(id string) {
@@ -194,3 +167,98 @@ func (db Database) DeleteFlight(id string) {
 		log.Fatal("Error calling stored procedure:", err)
 	}
 }
+
+// DeleteUnbookedFlight deletes a flight and its crew assignments unless
+// tickets or baggage refer to it. It reports whether the flight was deleted.
+func (db Database) DeleteUnbookedFlight(id string) (bool, error) {
+	var deleted int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.DeleteUnbookedFlight(:1, :2); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(id, sql.Out{Dest: &deleted})
+	if err != nil {
+		return false, err
+	}
+
+	return deleted == 1, nil
+}
+
+// SetFlightTemplateRevision records the revision of its schedule template a flight follows.
+func (db Database) SetFlightTemplateRevision(id string, revision int) error {
+	query := `BEGIN MindenAirport.SetFlightTemplateRevision(:1, :2); END;`
+	_, err := db.Exec(query, id, revision)
+	return err
+}
+
+// flightTimesToUTC converts all times of a flight to UTC, the time zone of
+// the FLIGHT timestamps.
+func flightTimesToUTC(flight *models.Flight) {
+	flight.ScheduledDeparture = flight.ScheduledDeparture.UTC()
+	flight.ScheduledArrival = flight.ScheduledArrival.UTC()
+	if flight.ActualDeparture != nil {
+		t := flight.ActualDeparture.UTC()
+		flight.ActualDeparture = &t
+	}
+	if flight.ActualArrival != nil {
+		t := flight.ActualArrival.UTC()
+		flight.ActualArrival = &t
+	}
+}
+
+// utcTimestamp interprets a timestamp read from the FLIGHT table as UTC.
+// The TIMESTAMP columns carry no time zone, so the driver attaches the
+// session's time zone to the stored wall clock time.
+func utcTimestamp(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// flightFromRow maps a row of the flight procedures onto a models.Flight.
+// All flight procedures return the FLIGHT columns in table order.
+func flightFromRow(r []driver.Value) models.Flight {
+	var flight models.Flight
+	flight.ID = r[0].(string)
+	flight.From = r[1].(string)
+	flight.To = r[2].(string)
+	if r[3] != nil {
+		flight.PilotID = r[3].(string)
+	}
+	flight.PlaneID = r[4].(string)
+	if r[5] != nil {
+		flight.TerminalID = r[5].(string)
+	}
+	if r[6] != nil {
+		flight.StatusID, _ = strconv.Atoi(r[6].(godror.Number).String())
+	}
+	if r[7] != nil {
+		flight.ScheduledDeparture = utcTimestamp(r[7].(time.Time))
+	}
+	if r[8] != nil {
+		t := utcTimestamp(r[8].(time.Time))
+		flight.ActualDeparture = &t
+	}
+	if r[9] != nil {
+		flight.ScheduledArrival = utcTimestamp(r[9].(time.Time))
+	}
+	if r[10] != nil {
+		t := utcTimestamp(r[10].(time.Time))
+		flight.ActualArrival = &t
+	}
+	if r[11] != nil {
+		flight.Gate = r[11].(string)
+	}
+	if r[12] != nil {
+		flight.BaggageClaim = r[12].(string)
+	}
+	if r[13] != nil {
+		flight.FlightNumber = r[13].(string)
+	}
+	if r[14] != nil {
+		flight.TemplateID = r[14].(string)
+	}
+	if r[15] != nil {
+		flight.TemplateRevision, _ = strconv.Atoi(r[15].(godror.Number).String())
+	}
+	return flight
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+)
+
+// GetGates retrieves all gates ordered by terminal and gate designator.
+func (db Database) GetGates() ([]models.Gate, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllGates(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var gates []models.Gate
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		gates = append(gates, gateFromRow(r))
+	}
+
+	return gates, nil
+}
+
+// GetGateByID retrieves a specific gate.
+//
+// Returns:
+//   - *models.Gate: The gate if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetGateByID(id string) (*models.Gate, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetGateByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		gate := gateFromRow(r)
+		return &gate, nil
+	}
+
+	return nil, nil
+}
+
+// CreateGate inserts a new gate.
+func (db Database) CreateGate(gate models.Gate) error {
+	query := `BEGIN MindenAirport.CreateGate(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, gate.ID, gate.TerminalID, gate.MaxAircraftCode, gate.StandType, gate.Area, gate.Status)
+	return err
+}
+
+// UpdateGate replaces all attributes of an existing gate.
+func (db Database) UpdateGate(gate models.Gate) error {
+	query := `BEGIN MindenAirport.UpdateGate(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, gate.ID, gate.TerminalID, gate.MaxAircraftCode, gate.StandType, gate.Area, gate.Status)
+	return err
+}
+
+// DeleteGate removes a gate.
+func (db Database) DeleteGate(id string) error {
+	query := `BEGIN MindenAirport.DeleteGate(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// gateFromRow maps a row of the gate procedures onto a models.Gate.
+func gateFromRow(r []driver.Value) models.Gate {
+	var gate models.Gate
+	gate.ID = r[0].(string)
+	gate.TerminalID = r[1].(string)
+	gate.MaxAircraftCode = r[2].(string)
+	gate.StandType = r[3].(string)
+	gate.Area = r[4].(string)
+	if r[5] != nil {
+		gate.Status = r[5].(string)
+	}
+	return gate
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetHangars retrieves all hangars ordered by ID.
+func (db Database) GetHangars() ([]models.Hangar, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllHangars(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var hangars []models.Hangar
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		hangars = append(hangars, hangarFromRow(r))
+	}
+
+	return hangars, nil
+}
+
+// GetHangarByID retrieves a specific hangar.
+//
+// Returns:
+//   - *models.Hangar: The hangar if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetHangarByID(id string) (*models.Hangar, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetHangarByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		hangar := hangarFromRow(r)
+		return &hangar, nil
+	}
+
+	return nil, nil
+}
+
+// hangarFromRow maps a row of the hangar procedures onto a models.Hangar.
+func hangarFromRow(r []driver.Value) models.Hangar {
+	var hangar models.Hangar
+	hangar.ID = r[0].(string)
+	hangar.PlotID = r[1].(string)
+	if r[2] != nil {
+		hangar.Capacity, _ = strconv.Atoi(r[2].(godror.Number).String())
+	}
+	if r[3] != nil {
+		hangar.SizeSqFt, _ = strconv.ParseFloat(r[3].(godror.Number).String(), 64)
+	}
+	if r[4] != nil {
+		hangar.Status = r[4].(string)
+	}
+	if r[5] != nil {
+		t := r[5].(time.Time)
+		hangar.LastInspection = &t
+	}
+	if r[6] != nil {
+		t := r[6].(time.Time)
+		hangar.NextInspection = &t
+	}
+	return hangar
+}
+
+// CreateHangar inserts a new hangar. A new ID is generated if none is set.
+func (db Database) CreateHangar(hangar *models.Hangar) error {
+	if hangar.ID == "" {
+		hangar.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateHangar(:1, :2, :3, :4, :5, :6, :7); END;`
+	_, err := db.Exec(query, hangar.ID, hangar.PlotID, hangar.Capacity, hangar.SizeSqFt, hangar.Status,
+		hangar.LastInspection, hangar.NextInspection)
+	return err
+}
+
+// UpdateHangar replaces all attributes of an existing hangar.
+func (db Database) UpdateHangar(hangar models.Hangar) error {
+	query := `BEGIN MindenAirport.UpdateHangar(:1, :2, :3, :4, :5, :6, :7); END;`
+	_, err := db.Exec(query, hangar.ID, hangar.PlotID, hangar.Capacity, hangar.SizeSqFt, hangar.Status,
+		hangar.LastInspection, hangar.NextInspection)
+	return err
+}
+
+// DeleteHangar removes a hangar. Hangars with parked planes cannot be deleted.
+func (db Database) DeleteHangar(id string) error {
+	query := `BEGIN MindenAirport.DeleteHangar(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// GetPlanesByHangar retrieves the planes parked in a hangar.
+func (db Database) GetPlanesByHangar(hangarID string) ([]models.Plane, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPlanesByHangar(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(hangarID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var planes []models.Plane
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		planes = append(planes, planeFromRow(r))
+	}
+
+	return planes, nil
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"mindenairport/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godror/godror"
+)
+
+// ClaimIdempotencyKey records the first use of an idempotency key in a
+// scope. It returns false if the key was already used in the scope, unless
+// the request that used it was claimed before staleBefore and never
+// finished; such a claim is taken over.
+func (db Database) ClaimIdempotencyKey(scope, key, requestHash string, now, staleBefore time.Time) (bool, error) {
+	var claimed int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.ClaimIdempotencyKey(:1, :2, :3, :4, :5, :6); END;`)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(scope, key, requestHash, now.UTC(), staleBefore.UTC(), sql.Out{Dest: &claimed})
+	if err != nil {
+		return false, err
+	}
+
+	return claimed > 0, nil
+}
+
+// GetIdempotencyKey retrieves a used idempotency key with the response
+// stored for it.
+//
+// Returns:
+//   - *models.IdempotencyRecord: The record if found, nil if not found
+//   - error: Any database error
+func (db Database) GetIdempotencyKey(scope, key string) (*models.IdempotencyRecord, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetIdempotencyKey(:1, :2, :3); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(scope, key, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	if err := cursor.Next(r); err != nil {
+		return nil, nil
+	}
+
+	record := models.IdempotencyRecord{
+		Scope:       r[0].(string),
+		Key:         r[1].(string),
+		RequestHash: r[2].(string),
+		CreatedAt:   r[5].(time.Time),
+	}
+	if r[3] != nil {
+		record.StatusCode, _ = strconv.Atoi(r[3].(godror.Number).String())
+	}
+	switch response := r[4].(type) {
+	case string:
+		record.Response = response
+	case *godror.Lob:
+		b, err := io.ReadAll(response)
+		if err != nil {
+			return nil, err
+		}
+		record.Response = string(b)
+	}
+	return &record, nil
+}
+
+// SaveIdempotencyResponse stores the response of the request that claimed
+// an idempotency key.
+func (db Database) SaveIdempotencyResponse(scope, key string, statusCode int, response string) error {
+	query := `BEGIN MindenAirport.SaveIdempotencyResponse(:1, :2, :3, :4); END;`
+	_, err := db.Exec(query, scope, key, statusCode, godror.Lob{IsClob: true, Reader: strings.NewReader(response)})
+	return err
+}
+
+// DeleteIdempotencyKey releases an idempotency key, so the request can be
+// retried with it.
+func (db Database) DeleteIdempotencyKey(scope, key string) error {
+	query := `BEGIN MindenAirport.DeleteIdempotencyKey(:1, :2); END;`
+	_, err := db.Exec(query, scope, key)
+	return err
+}
+
+// DeleteIdempotencyKeysBefore removes the idempotency keys first used before
+// the given time and returns how many were removed.
+func (db Database) DeleteIdempotencyKeysBefore(before time.Time) (int, error) {
+	var deleted int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.DeleteIdempotencyKeysBefore(:1, :2); END;`)
+	if err != nil {
+		return 0, err
+	}
+	_, err = stmt.Exec(before.UTC(), sql.Out{Dest: &deleted})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,497 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetInvoiceIssuer retrieves the legal details of the airport valid at the
+// given time. It returns nil if none were set up.
+func (db Database) GetInvoiceIssuer(at time.Time) (*models.InvoiceIssuer, error) {
+	issuers, err := db.invoiceIssuers(`BEGIN MindenAirport.GetInvoiceIssuer(:1, :2); END;`, at.UTC())
+	if err != nil || len(issuers) == 0 {
+		return nil, err
+	}
+	return &issuers[0], nil
+}
+
+// GetInvoiceIssuerByID retrieves a version of the legal details of the
+// airport. It returns nil if not found.
+func (db Database) GetInvoiceIssuerByID(id string) (*models.InvoiceIssuer, error) {
+	issuers, err := db.invoiceIssuers(`BEGIN MindenAirport.GetInvoiceIssuerByID(:1, :2); END;`, id)
+	if err != nil || len(issuers) == 0 {
+		return nil, err
+	}
+	return &issuers[0], nil
+}
+
+// invoiceIssuers runs a procedure returning versions of the legal details
+// of the airport for the given arguments.
+func (db Database) invoiceIssuers(query string, args ...interface{}) ([]models.InvoiceIssuer, error) {
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(append(args, sql.Out{Dest: &cursor})...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var issuers []models.InvoiceIssuer
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		issuers = append(issuers, models.InvoiceIssuer{
+			ID:                r[0].(string),
+			Name:              r[1].(string),
+			Street:            r[2].(string),
+			Postcode:          r[3].(string),
+			City:              r[4].(string),
+			Country:           r[5].(string),
+			VATID:             optionalString(r[6]),
+			TaxNumber:         optionalString(r[7]),
+			RegisterCourt:     optionalString(r[8]),
+			RegisterNumber:    optionalString(r[9]),
+			ManagingDirectors: optionalString(r[10]),
+			ContactName:       r[11].(string),
+			Email:             r[12].(string),
+			Phone:             r[13].(string),
+			IBAN:              optionalString(r[14]),
+			BIC:               optionalString(r[15]),
+			BankName:          optionalString(r[16]),
+			ValidFrom:         r[17].(time.Time),
+			CreatedBy:         optionalString(r[18]),
+		})
+	}
+
+	return issuers, nil
+}
+
+// CreateInvoiceIssuer adds a new version of the legal details of the
+// airport. The ID is generated if not set.
+func (db Database) CreateInvoiceIssuer(issuer *models.InvoiceIssuer) error {
+	if issuer.ID == "" {
+		issuer.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateInvoiceIssuer(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13, :14, :15, :16, :17, :18, :19); END;`
+	_, err := db.Exec(query, issuer.ID, issuer.Name, issuer.Street, issuer.Postcode, issuer.City, issuer.Country,
+		issuer.VATID, issuer.TaxNumber, issuer.RegisterCourt, issuer.RegisterNumber, issuer.ManagingDirectors,
+		issuer.ContactName, issuer.Email, issuer.Phone, issuer.IBAN, issuer.BIC, issuer.BankName,
+		issuer.ValidFrom.UTC(), nullString(issuer.CreatedBy))
+	return err
+}
+
+// GetBillingAddress retrieves the billing address of a user. It returns nil
+// if the user has none.
+func (db Database) GetBillingAddress(userID string) (*models.BillingAddress, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetBillingAddress(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(userID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	if err := cursor.Next(r); err != nil {
+		return nil, nil
+	}
+
+	updatedAt := r[10].(time.Time)
+	return &models.BillingAddress{
+		AirportUserID:  r[0].(string),
+		Company:        optionalString(r[1]),
+		Name:           r[2].(string),
+		Street:         optionalString(r[3]),
+		Postcode:       optionalString(r[4]),
+		City:           optionalString(r[5]),
+		Country:        optionalString(r[6]),
+		VATID:          optionalString(r[7]),
+		BuyerReference: optionalString(r[8]),
+		Email:          optionalString(r[9]),
+		UpdatedAt:      &updatedAt,
+	}, nil
+}
+
+// SaveBillingAddress creates or replaces the billing address of a user.
+func (db Database) SaveBillingAddress(address models.BillingAddress) error {
+	updatedAt := time.Now().UTC()
+	if address.UpdatedAt != nil {
+		updatedAt = address.UpdatedAt.UTC()
+	}
+
+	query := `BEGIN MindenAirport.SaveBillingAddress(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11); END;`
+	_, err := db.Exec(query, address.AirportUserID, address.Company, address.Name, address.Street, address.Postcode,
+		address.City, address.Country, address.VATID, address.BuyerReference, address.Email, updatedAt)
+	return err
+}
+
+// DeleteBillingAddress removes the billing address of a user.
+func (db Database) DeleteBillingAddress(userID string) error {
+	_, err := db.Exec(`BEGIN MindenAirport.DeleteBillingAddress(:1); END;`, userID)
+	return err
+}
+
+// GetBaggageFeeByID retrieves an excess baggage fee. It returns nil if not
+// found.
+func (db Database) GetBaggageFeeByID(id string) (*models.BaggageFee, error) {
+	return db.baggageFee(`BEGIN MindenAirport.GetBaggageFeeByID(:1, :2); END;`, id)
+}
+
+// GetBaggageFeeByBaggage retrieves the excess baggage fee charged for a bag.
+// It returns nil if none was charged.
+func (db Database) GetBaggageFeeByBaggage(baggageID string) (*models.BaggageFee, error) {
+	return db.baggageFee(`BEGIN MindenAirport.GetBaggageFeeByBaggage(:1, :2); END;`, baggageID)
+}
+
+// baggageFee runs a procedure returning at most one excess baggage fee.
+func (db Database) baggageFee(query string, id string) (*models.BaggageFee, error) {
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	if err := cursor.Next(r); err != nil {
+		return nil, nil
+	}
+
+	return &models.BaggageFee{
+		ID:             r[0].(string),
+		BaggageID:      r[1].(string),
+		TrackingNumber: optionalString(r[2]),
+		AirportUserID:  r[3].(string),
+		FlightID:       r[4].(string),
+		Reason:         r[5].(string),
+		Weight:         numberFromRow(r[6]),
+		Amount:         numberFromRow(r[7]),
+		Currency:       r[8].(string),
+		PaymentMethod:  r[9].(string),
+		CardLast4:      optionalString(r[10]),
+		PaidAt:         r[11].(time.Time),
+		CreatedBy:      optionalString(r[12]),
+	}, nil
+}
+
+// CreateBaggageFee records a paid excess baggage fee. The ID is generated
+// if not set.
+func (db Database) CreateBaggageFee(fee *models.BaggageFee) error {
+	if fee.ID == "" {
+		fee.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateBaggageFee(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12); END;`
+	_, err := db.Exec(query, fee.ID, fee.BaggageID, fee.AirportUserID, fee.FlightID, fee.Reason, fee.Weight,
+		fee.Amount, fee.Currency, fee.PaymentMethod, fee.CardLast4, fee.PaidAt.UTC(), nullString(fee.CreatedBy))
+	return err
+}
+
+// CalculateBaggageFeeRevenue returns the total of all paid excess baggage fees.
+func (db Database) CalculateBaggageFeeRevenue() (float64, error) {
+	var total float64
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.CalculateBaggageFeeRevenue(:1); END;`)
+	if err != nil {
+		return 0, err
+	}
+	_, err = stmt.Exec(sql.Out{Dest: &total})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// CreateInvoice numbers and inserts an invoice or credit note with its
+// lines in one transaction. The next number of the year is drawn within
+// the transaction, so a failed insert does not leave a gap; number formats
+// the sequence number into invoice.Number. The ID is generated if not set.
+func (db Database) CreateInvoice(invoice *models.Invoice, year int, number func(sequence int) string) error {
+	if invoice.ID == "" {
+		invoice.ID = uuid.New().String()
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var sequence int
+	_, err = tx.Exec(`BEGIN MindenAirport.NextInvoiceNumber(:1, :2); END;`, year, sql.Out{Dest: &sequence})
+	if err != nil {
+		return err
+	}
+	invoice.Number = number(sequence)
+
+	buyer := invoice.Buyer
+	_, err = tx.Exec(`BEGIN MindenAirport.CreateInvoice(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13, :14, :15, :16, :17, :18, :19, :20, :21, :22, :23, :24, :25, :26, :27, :28, :29, :30, :31); END;`,
+		invoice.ID, invoice.Number, year, sequence, invoice.Type, invoice.IssuerID, invoice.AirportUserID,
+		nullString(invoice.BookingID), nullString(invoice.PaymentID), nullString(invoice.RefundID),
+		nullString(invoice.BaggageFeeID), nullString(invoice.CorrectsID), invoice.IssuedAt.UTC(), invoice.ServiceDate,
+		invoice.Currency, invoice.NetAmount, invoice.VATAmount, invoice.GrossAmount, invoice.PaymentMethod,
+		invoice.CardLast4, buyer.Name, buyer.Company, buyer.Street, buyer.Postcode, buyer.City, buyer.Country,
+		buyer.VATID, buyer.BuyerReference, buyer.Email, invoice.Note, nullString(invoice.CreatedBy))
+	if err != nil {
+		return err
+	}
+
+	for _, line := range invoice.Lines {
+		var correctsLine interface{}
+		if line.CorrectsLine > 0 {
+			correctsLine = line.CorrectsLine
+		}
+		_, err = tx.Exec(`BEGIN MindenAirport.AddInvoiceLine(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12); END;`,
+			invoice.ID, line.LineNo, line.Description, line.Quantity, line.NetAmount, line.VATCategory, line.VATRate,
+			line.VATAmount, line.GrossAmount, nullString(line.TicketID), nullString(line.BaggageFeeID), correctsLine)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetInvoiceByID retrieves an invoice or credit note without its lines.
+//
+// Returns:
+//   - *models.Invoice: The invoice if found, nil if not found
+//   - error: Any database error
+func (db Database) GetInvoiceByID(id string) (*models.Invoice, error) {
+	invoices, err := db.invoices(`BEGIN MindenAirport.GetInvoiceByID(:1, :2); END;`, id)
+	if err != nil || len(invoices) == 0 {
+		return nil, err
+	}
+	return &invoices[0], nil
+}
+
+// GetInvoicesByUser retrieves the invoices and credit notes of a user,
+// newest first, without their lines.
+func (db Database) GetInvoicesByUser(userID string) ([]models.Invoice, error) {
+	return db.invoices(`BEGIN MindenAirport.GetInvoicesByUser(:1, :2); END;`, userID)
+}
+
+// GetInvoicesBySource retrieves the invoices and credit notes issued for a
+// payment, refund or excess baggage fee, oldest first, without their
+// lines. Only one of the IDs is expected to be set.
+func (db Database) GetInvoicesBySource(paymentID, refundID, baggageFeeID string) ([]models.Invoice, error) {
+	return db.invoices(`BEGIN MindenAirport.GetInvoicesBySource(:1, :2, :3, :4); END;`,
+		nullString(paymentID), nullString(refundID), nullString(baggageFeeID))
+}
+
+// GetCreditNotes retrieves the credit notes correcting an invoice, oldest
+// first, without their lines.
+func (db Database) GetCreditNotes(invoiceID string) ([]models.Invoice, error) {
+	return db.invoices(`BEGIN MindenAirport.GetCreditNotes(:1, :2); END;`, invoiceID)
+}
+
+// SearchInvoices retrieves the invoices and credit notes matching a filter,
+// newest first, without their lines.
+func (db Database) SearchInvoices(filter models.InvoiceFilter) ([]models.Invoice, error) {
+	var from, to interface{}
+	if filter.From != nil {
+		from = filter.From.UTC()
+	}
+	if filter.To != nil {
+		to = filter.To.UTC()
+	}
+
+	return db.invoices(`BEGIN MindenAirport.SearchInvoices(:1, :2, :3, :4, :5, :6, :7, :8); END;`,
+		nullString(filter.Number), nullString(filter.Locator), nullString(filter.AirportUserID),
+		nullString(filter.Customer), nullString(filter.Type), from, to)
+}
+
+// invoices runs a procedure returning invoices for the given arguments.
+func (db Database) invoices(query string, args ...interface{}) ([]models.Invoice, error) {
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(append(args, sql.Out{Dest: &cursor})...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var invoices []models.Invoice
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		invoices = append(invoices, models.Invoice{
+			ID:             r[0].(string),
+			Number:         r[1].(string),
+			Type:           r[2].(string),
+			IssuerID:       r[3].(string),
+			AirportUserID:  r[4].(string),
+			BookingID:      optionalString(r[5]),
+			Locator:        optionalString(r[6]),
+			PaymentID:      optionalString(r[7]),
+			RefundID:       optionalString(r[8]),
+			BaggageFeeID:   optionalString(r[9]),
+			CorrectsID:     optionalString(r[10]),
+			CorrectsNumber: optionalString(r[11]),
+			IssuedAt:       r[12].(time.Time),
+			ServiceDate:    r[13].(time.Time),
+			Currency:       r[14].(string),
+			NetAmount:      numberFromRow(r[15]),
+			VATAmount:      numberFromRow(r[16]),
+			GrossAmount:    numberFromRow(r[17]),
+			CreditedAmount: numberFromRow(r[18]),
+			PaymentMethod:  r[19].(string),
+			CardLast4:      optionalString(r[20]),
+			Buyer: models.BillingAddress{
+				Name:           r[21].(string),
+				Company:        optionalString(r[22]),
+				Street:         optionalString(r[23]),
+				Postcode:       optionalString(r[24]),
+				City:           optionalString(r[25]),
+				Country:        optionalString(r[26]),
+				VATID:          optionalString(r[27]),
+				BuyerReference: optionalString(r[28]),
+				Email:          optionalString(r[29]),
+			},
+			Note:      optionalString(r[30]),
+			CreatedBy: optionalString(r[31]),
+		})
+	}
+
+	return invoices, nil
+}
+
+// GetInvoiceLines retrieves the lines of an invoice or credit note.
+func (db Database) GetInvoiceLines(invoiceID string) ([]models.InvoiceLine, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetInvoiceLines(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(invoiceID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var lines []models.InvoiceLine
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		line := models.InvoiceLine{
+			Description:  r[1].(string),
+			Quantity:     numberFromRow(r[2]),
+			NetAmount:    numberFromRow(r[3]),
+			VATCategory:  r[4].(string),
+			VATRate:      numberFromRow(r[5]),
+			VATAmount:    numberFromRow(r[6]),
+			GrossAmount:  numberFromRow(r[7]),
+			TicketID:     optionalString(r[8]),
+			BaggageFeeID: optionalString(r[9]),
+		}
+		line.LineNo, _ = strconv.Atoi(r[0].(godror.Number).String())
+		if r[10] != nil {
+			line.CorrectsLine, _ = strconv.Atoi(r[10].(godror.Number).String())
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// GetUninvoicedSources retrieves the captured payments, succeeded refunds
+// and excess baggage fees no invoice or credit note was issued for yet,
+// oldest first.
+func (db Database) GetUninvoicedSources() ([]models.InvoiceSource, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetUninvoicedSources(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var sources []models.InvoiceSource
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		sources = append(sources, models.InvoiceSource{Type: r[0].(string), ID: r[1].(string), PaymentID: optionalString(r[2])})
+	}
+
+	return sources, nil
+}
+
+// optionalString returns the value of a nullable text column, or "" for NULL.
+func optionalString(v driver.Value) string {
+	if v == nil {
+		return ""
+	}
+	return v.(string)
+}
+
+// numberFromRow returns the value of a NUMBER column, or 0 for NULL.
+func numberFromRow(v driver.Value) float64 {
+	if v == nil {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(v.(godror.Number).String(), 64)
+	return f
+}
+
+// nullString binds an empty string as NULL, e.g. for optional foreign keys.
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,261 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetLeases retrieves the lease contracts ordered by shop and start date.
+//
+// Parameters:
+//   - shopID: Only contracts of this shop, or "" for all shops
+func (db Database) GetLeases(shopID string) ([]models.Lease, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllLeases(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(shopID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	return readLeases(cursor), nil
+}
+
+// GetLeasesInPeriod retrieves the lease contracts running at some time in [from, to).
+func (db Database) GetLeasesInPeriod(from, to time.Time) ([]models.Lease, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetLeasesInPeriod(:1, :2, :3); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(from, to, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	return readLeases(cursor), nil
+}
+
+// GetLeaseByID retrieves a specific lease contract.
+//
+// Returns:
+//   - *models.Lease: The lease if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetLeaseByID(id string) (*models.Lease, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetLeaseByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		lease := leaseFromRow(r)
+		return &lease, nil
+	}
+
+	return nil, nil
+}
+
+// readLeases reads all rows of a lease cursor and closes it.
+func readLeases(cursor driver.Rows) []models.Lease {
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var leases []models.Lease
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		leases = append(leases, leaseFromRow(r))
+	}
+
+	return leases
+}
+
+// leaseFromRow maps a row of the lease procedures onto a models.Lease.
+func leaseFromRow(r []driver.Value) models.Lease {
+	var lease models.Lease
+	lease.ID = r[0].(string)
+	lease.ShopID = r[1].(string)
+	lease.PlotID = r[2].(string)
+	lease.StartDate = r[3].(time.Time)
+	if r[4] != nil {
+		t := r[4].(time.Time)
+		lease.EndDate = &t
+	}
+	lease.RentPerSqFt, _ = strconv.ParseFloat(r[5].(godror.Number).String(), 64)
+	lease.TurnoverPercent, _ = strconv.ParseFloat(r[6].(godror.Number).String(), 64)
+	lease.ShopName = r[7].(string)
+	if r[8] != nil {
+		lease.AreaSqFt, _ = strconv.ParseFloat(r[8].(godror.Number).String(), 64)
+	}
+	return lease
+}
+
+// CreateLease inserts a new lease contract. A new ID is generated if none is set.
+func (db Database) CreateLease(lease *models.Lease) error {
+	if lease.ID == "" {
+		lease.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateLease(:1, :2, :3, :4, :5, :6, :7); END;`
+	_, err := db.Exec(query, lease.ID, lease.ShopID, lease.PlotID, lease.StartDate, lease.EndDate,
+		lease.RentPerSqFt, lease.TurnoverPercent)
+	return err
+}
+
+// UpdateLease replaces all attributes of an existing lease contract.
+func (db Database) UpdateLease(lease models.Lease) error {
+	query := `BEGIN MindenAirport.UpdateLease(:1, :2, :3, :4, :5, :6, :7); END;`
+	_, err := db.Exec(query, lease.ID, lease.ShopID, lease.PlotID, lease.StartDate, lease.EndDate,
+		lease.RentPerSqFt, lease.TurnoverPercent)
+	return err
+}
+
+// DeleteLease removes a lease contract together with its turnover reports.
+// Contracts that were already invoiced cannot be deleted.
+func (db Database) DeleteLease(id string) error {
+	query := `BEGIN MindenAirport.DeleteLease(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// SetLeaseTurnover records the turnover a shop reported for the month
+// starting at period, replacing an earlier report.
+func (db Database) SetLeaseTurnover(leaseID string, period time.Time, amount float64) error {
+	query := `BEGIN MindenAirport.SetLeaseTurnover(:1, :2, :3); END;`
+	_, err := db.Exec(query, leaseID, period, amount)
+	return err
+}
+
+// GetLeaseTurnovers retrieves the turnover reported for the month starting
+// at period, keyed by lease ID.
+func (db Database) GetLeaseTurnovers(period time.Time) (map[string]float64, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetLeaseTurnovers(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(period, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	turnovers := make(map[string]float64)
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		amount, _ := strconv.ParseFloat(r[1].(godror.Number).String(), 64)
+		turnovers[r[0].(string)] = amount
+	}
+
+	return turnovers, nil
+}
+
+// SaveLeaseInvoice stores the invoice of a lease for the month starting at
+// period. An invoice already issued for that month is replaced and keeps its
+// ID. A new ID is generated if none is set.
+func (db Database) SaveLeaseInvoice(invoice *models.LeaseInvoice, period time.Time) error {
+	if invoice.ID == "" {
+		invoice.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.SaveLeaseInvoice(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	_, err := db.Exec(query, invoice.ID, invoice.LeaseID, period, invoice.AreaSqFt, invoice.FixedRent,
+		invoice.Turnover, invoice.TurnoverRent, invoice.Total)
+	return err
+}
+
+// GetLeaseInvoices retrieves the lease invoices of the months in [from, to)
+// ordered by month and shop.
+func (db Database) GetLeaseInvoices(from, to time.Time) ([]models.LeaseInvoice, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetLeaseInvoices(:1, :2, :3); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(from, to, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var invoices []models.LeaseInvoice
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		invoices = append(invoices, leaseInvoiceFromRow(r))
+	}
+
+	return invoices, nil
+}
+
+// leaseInvoiceFromRow maps a row of GetLeaseInvoices onto a models.LeaseInvoice.
+func leaseInvoiceFromRow(r []driver.Value) models.LeaseInvoice {
+	var invoice models.LeaseInvoice
+	invoice.ID = r[0].(string)
+	invoice.LeaseID = r[1].(string)
+	invoice.ShopID = r[2].(string)
+	invoice.ShopName = r[3].(string)
+	invoice.Month = r[4].(time.Time).Format("2006-01")
+	invoice.AreaSqFt, _ = strconv.ParseFloat(r[5].(godror.Number).String(), 64)
+	invoice.FixedRent, _ = strconv.ParseFloat(r[6].(godror.Number).String(), 64)
+	if r[7] != nil {
+		turnover, _ := strconv.ParseFloat(r[7].(godror.Number).String(), 64)
+		invoice.Turnover = &turnover
+	}
+	invoice.TurnoverRent, _ = strconv.ParseFloat(r[8].(godror.Number).String(), 64)
+	invoice.Total, _ = strconv.ParseFloat(r[9].(godror.Number).String(), 64)
+	invoice.IssuedAt = r[10].(time.Time)
+	return invoice
+}
+
+// CalculateConcessionRevenue returns the total amount invoiced to shops.
+func (db Database) CalculateConcessionRevenue() (float64, error) {
+	var total float64
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.CalculateConcessionRevenue(:1); END;`)
+	if err != nil {
+		return 0, err
+	}
+	_, err = stmt.Exec(sql.Out{Dest: &total})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
@@ -3,84 +3,160 @@ package database
 import (
 	"database/sql"
 	"database/sql/driver"
-	"log"
 	"mindenairport/models"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-func (db Database) GetMaintenanceLogById(id string) models.MaintenanceLog {
+// GetMaintenanceLogById retrieves a specific maintenance record.
+//
+// Returns:
+//   - *models.MaintenanceLog: The maintenance record if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetMaintenanceLogById(id string) (*models.MaintenanceLog, error) {
 	stmt, err := db.Prepare(`
 	BEGIN MindenAirport.GetMaintenanceLogByID(:1, :2); END;`)
 	if err != nil {
-		log.Fatal("Error preparing statement:", err)
+		return nil, err
 	}
 
 	var cursor driver.Rows
 	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
 	if err != nil {
-		log.Fatal("Failed to execute prepared statement:", err)
+		return nil, err
 	}
 
 	r := make([]driver.Value, len(cursor.Columns()))
 	defer cursor.Close()
 
-	var maintenanceLog models.MaintenanceLog
-
 	err = cursor.Next(r)
 	if err == nil {
-		maintenanceLog.ID = r[0].(string)
-		maintenanceLog.PlaneID = r[1].(string)
-		if r[2] != nil {
-			maintenanceLog.MaintenanceDate = r[2].(time.Time)
-		}
-		maintenanceLog.Description = r[3].(string)
-		maintenanceLog.Technician = r[4].(string)
-		if r[5] != nil {
-			t := r[5].(time.Time)
-			maintenanceLog.NextMaintenance = &t
-		}
+		maintenanceLog := maintenanceLogFromRow(r)
+		return &maintenanceLog, nil
 	}
 
-	return maintenanceLog
+	return nil, nil
 }
 
-func (db Database) GetMaintenanceLogs() []models.MaintenanceLog {
+// GetMaintenanceLogs retrieves all maintenance records, newest first.
+func (db Database) GetMaintenanceLogs() ([]models.MaintenanceLog, error) {
 	stmt, err := db.Prepare(`
 	BEGIN MindenAirport.GetMaintenanceLogs(:1); END;`)
 	if err != nil {
-		log.Fatal("Error preparing statement:", err)
+		return nil, err
 	}
 
 	var cursor driver.Rows
 	_, err = stmt.Exec(sql.Out{Dest: &cursor})
 	if err != nil {
-		log.Fatal("Failed to execute prepared statement:", err)
+		return nil, err
+	}
+
+	return readMaintenanceLogs(cursor), nil
+}
+
+// GetMaintenanceLogsByPlane retrieves the maintenance history of a plane, newest first.
+func (db Database) GetMaintenanceLogsByPlane(planeID string) ([]models.MaintenanceLog, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetMaintenanceLogsByPlane(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(planeID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	return readMaintenanceLogs(cursor), nil
+}
+
+// CreateMaintenanceLog records a maintenance activity. A new ID is generated if none is set.
+func (db Database) CreateMaintenanceLog(maintenanceLog *models.MaintenanceLog) error {
+	if maintenanceLog.ID == "" {
+		maintenanceLog.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateMaintenanceLog(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query, maintenanceLog.ID, maintenanceLog.PlaneID, maintenanceLog.MaintenanceDate,
+		maintenanceLog.Description, maintenanceLog.Technician, maintenanceLog.NextMaintenance)
+	return err
+}
+
+// GetMaintenanceSchedule retrieves every plane with a maintenance history
+// together with the next maintenance date of its latest maintenance record.
+func (db Database) GetMaintenanceSchedule() ([]models.MaintenanceDue, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetMaintenanceSchedule(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
 	}
 
 	r := make([]driver.Value, len(cursor.Columns()))
 	defer cursor.Close()
 
-	var maintenanceLogs []models.MaintenanceLog
+	var schedule []models.MaintenanceDue
 
 	for {
 		err := cursor.Next(r)
 		if err != nil {
 			break
 		}
-		var maintenanceLog models.MaintenanceLog
-		maintenanceLog.ID = r[0].(string)
-		maintenanceLog.PlaneID = r[1].(string)
-		if r[2] != nil {
-			maintenanceLog.MaintenanceDate = r[2].(time.Time)
+		var due models.MaintenanceDue
+		due.PlaneID = r[0].(string)
+		if r[1] != nil {
+			due.PlaneName = r[1].(string)
 		}
-		maintenanceLog.Description = r[3].(string)
-		maintenanceLog.Technician = r[4].(string)
-		if r[5] != nil {
-			t := r[5].(time.Time)
-			maintenanceLog.NextMaintenance = &t
+		due.Model = r[2].(string)
+		if r[3] != nil {
+			due.PlaneStatus = r[3].(string)
 		}
-		maintenanceLogs = append(maintenanceLogs, maintenanceLog)
+		if r[4] != nil {
+			due.NextMaintenance = r[4].(time.Time)
+		}
+		schedule = append(schedule, due)
+	}
+
+	return schedule, nil
+}
+
+// readMaintenanceLogs reads all rows of a maintenance log cursor and closes it.
+func readMaintenanceLogs(cursor driver.Rows) []models.MaintenanceLog {
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var maintenanceLogs []models.MaintenanceLog
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		maintenanceLogs = append(maintenanceLogs, maintenanceLogFromRow(r))
 	}
 
 	return maintenanceLogs
 }
+
+// maintenanceLogFromRow maps a row of the MAINTENANCE_LOG table onto a models.MaintenanceLog.
+func maintenanceLogFromRow(r []driver.Value) models.MaintenanceLog {
+	var maintenanceLog models.MaintenanceLog
+	maintenanceLog.ID = r[0].(string)
+	maintenanceLog.PlaneID = r[1].(string)
+	if r[2] != nil {
+		maintenanceLog.MaintenanceDate = r[2].(time.Time)
+	}
+	maintenanceLog.Description = r[3].(string)
+	maintenanceLog.Technician = r[4].(string)
+	if r[5] != nil {
+		t := r[5].(time.Time)
+		maintenanceLog.NextMaintenance = &t
+	}
+	return maintenanceLog
+}
@@ -0,0 +1,192 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetFlightManifest retrieves the passengers of a flight whose tickets are
+// not cancelled, ordered by name, with their checked bags.
+func (db Database) GetFlightManifest(flightID string) ([]models.ManifestPassenger, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightManifest(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var passengers []models.ManifestPassenger
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var p models.ManifestPassenger
+		p.TicketID = r[0].(string)
+		if r[1] != nil {
+			p.AirportUserID = r[1].(string)
+		}
+		p.FirstName = r[2].(string)
+		p.LastName = r[3].(string)
+		if r[4] != nil {
+			birthDate := r[4].(time.Time)
+			p.BirthDate = &birthDate
+		}
+		if r[5] != nil {
+			p.SeatNumber = r[5].(string)
+		}
+		if r[6] != nil {
+			p.TravelClassID, _ = strconv.Atoi(r[6].(godror.Number).String())
+		}
+		if r[7] != nil {
+			p.TravelClass = r[7].(string)
+		}
+		if r[8] != nil {
+			p.Status = r[8].(string)
+		}
+		p.Bags, _ = strconv.Atoi(r[9].(godror.Number).String())
+		p.BagWeightKg, _ = strconv.ParseFloat(r[10].(godror.Number).String(), 64)
+		if r[11] != nil {
+			p.TravelDocumentID = r[11].(string)
+		}
+		if r[12] != nil {
+			p.Locator = r[12].(string)
+		}
+		if r[13] != nil {
+			p.CompanionID = r[13].(string)
+		}
+		passengers = append(passengers, p)
+	}
+
+	return passengers, nil
+}
+
+// GetPassengerListMessages retrieves the PNL and ADL messages sent for a
+// flight, oldest first.
+func (db Database) GetPassengerListMessages(flightID string) ([]models.PassengerListMessage, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPassengerListMessages(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var messages []models.PassengerListMessage
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var m models.PassengerListMessage
+		m.ID = r[0].(string)
+		m.FlightID = r[1].(string)
+		m.Type = r[2].(string)
+		m.Sequence, _ = strconv.Atoi(r[3].(godror.Number).String())
+		m.SentAt = r[4].(time.Time)
+		m.Parts, _ = strconv.Atoi(r[5].(godror.Number).String())
+		m.Passengers, _ = strconv.Atoi(r[6].(godror.Number).String())
+		messages = append(messages, m)
+	}
+
+	return messages, nil
+}
+
+// GetPassengerListEntries retrieves the passenger list as it was known after
+// a PNL or ADL message.
+func (db Database) GetPassengerListEntries(messageID string) ([]models.PassengerListEntry, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPassengerListEntries(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(messageID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var entries []models.PassengerListEntry
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var e models.PassengerListEntry
+		e.TicketID = r[0].(string)
+		e.Surname = r[1].(string)
+		if r[2] != nil {
+			e.GivenName = r[2].(string)
+		}
+		e.BookingClass = r[3].(string)
+		if r[4] != nil {
+			e.Seat = r[4].(string)
+		}
+		if r[5] != nil {
+			e.Locator = r[5].(string)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// CreatePassengerListMessage records a sent message together with the
+// passenger list it leaves the handler with. Both are written in one
+// transaction, so a later ADL never diffs against a partial list. A new ID
+// is generated if none is set.
+func (db Database) CreatePassengerListMessage(message *models.PassengerListMessage, entries []models.PassengerListEntry) error {
+	if message.ID == "" {
+		message.ID = uuid.New().String()
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`BEGIN MindenAirport.CreatePassengerListMessage(:1, :2, :3, :4, :5, :6, :7); END;`,
+		message.ID, message.FlightID, message.Type, message.Sequence, message.SentAt, message.Parts, message.Passengers)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		_, err = tx.Exec(`BEGIN MindenAirport.AddPassengerListEntry(:1, :2, :3, :4, :5, :6, :7); END;`,
+			message.ID, e.TicketID, e.Surname, e.GivenName, e.BookingClass, e.Seat, e.Locator)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+)
+
+// GetNotificationPreference retrieves the notification settings of a user.
+//
+// Returns:
+//   - *models.NotificationPreference: The stored preferences, nil if the user has none
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetNotificationPreference(userID string) (*models.NotificationPreference, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetNotificationPreference(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(userID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var pref models.NotificationPreference
+
+	err = cursor.Next(r)
+	if err == nil {
+		pref.AirportUserID = r[0].(string)
+		pref.Language = r[1].(string)
+		pref.Email = r[2].(int64) == 1
+		pref.SMS = r[3].(int64) == 1
+		pref.Push = r[4].(int64) == 1
+		if r[5] != nil {
+			pref.PushEndpoint = r[5].(string)
+		}
+		return &pref, nil
+	}
+
+	return nil, nil
+}
+
+// SaveNotificationPreference creates or replaces the notification settings of a user.
+func (db Database) SaveNotificationPreference(pref models.NotificationPreference) error {
+	query := `BEGIN MindenAirport.SaveNotificationPreference(:1, :2, :3, :4, :5, :6); END;`
+	_, err := db.Exec(query,
+		pref.AirportUserID,
+		pref.Language,
+		boolToNumber(pref.Email),
+		boolToNumber(pref.SMS),
+		boolToNumber(pref.Push),
+		pref.PushEndpoint,
+	)
+	return err
+}
+
+// boolToNumber converts a bool into the 0/1 representation used by NUMBER(1) flag columns.
+func boolToNumber(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetParkings retrieves all car parks ordered by name.
+func (db Database) GetParkings() ([]models.Parking, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllParkings(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var parkings []models.Parking
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		parkings = append(parkings, parkingFromRow(r))
+	}
+
+	return parkings, nil
+}
+
+// GetParkingByID retrieves a specific car park.
+//
+// Returns:
+//   - *models.Parking: The car park if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetParkingByID(id string) (*models.Parking, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetParkingByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		parking := parkingFromRow(r)
+		return &parking, nil
+	}
+
+	return nil, nil
+}
+
+// parkingFromRow maps a row of the car park procedures onto a models.Parking.
+func parkingFromRow(r []driver.Value) models.Parking {
+	var parking models.Parking
+	parking.ID = r[0].(string)
+	parking.Name = r[1].(string)
+	parking.PlotID = r[2].(string)
+	if r[3] != nil {
+		parking.Spaces, _ = strconv.Atoi(r[3].(godror.Number).String())
+	}
+	if r[4] != nil {
+		parking.Status = r[4].(string)
+	}
+	return parking
+}
+
+// CreateParking inserts a new car park. A new ID is generated if none is set.
+func (db Database) CreateParking(parking *models.Parking) error {
+	if parking.ID == "" {
+		parking.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateParking(:1, :2, :3, :4, :5); END;`
+	_, err := db.Exec(query, parking.ID, parking.Name, parking.PlotID, parking.Spaces, parking.Status)
+	return err
+}
+
+// UpdateParking replaces all attributes of an existing car park.
+func (db Database) UpdateParking(parking models.Parking) error {
+	query := `BEGIN MindenAirport.UpdateParking(:1, :2, :3, :4, :5); END;`
+	_, err := db.Exec(query, parking.ID, parking.Name, parking.PlotID, parking.Spaces, parking.Status)
+	return err
+}
+
+// DeleteParking removes a car park.
+func (db Database) DeleteParking(id string) error {
+	query := `BEGIN MindenAirport.DeleteParking(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
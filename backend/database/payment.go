@@ -0,0 +1,365 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetFlightFares retrieves the fares of a flight by travel class. The
+// currency is left for the caller to fill in.
+func (db Database) GetFlightFares(flightID string) ([]models.Fare, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightFares(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var fares []models.Fare
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var fare models.Fare
+		fare.TravelClassID, _ = strconv.Atoi(r[0].(godror.Number).String())
+		if r[1] != nil {
+			fare.TravelClass = r[1].(string)
+		}
+		fare.Price, _ = strconv.ParseFloat(r[2].(godror.Number).String(), 64)
+		fares = append(fares, fare)
+	}
+
+	return fares, nil
+}
+
+// SetFlightFares replaces the fares of a flight in one transaction.
+func (db Database) SetFlightFares(flightID string, fares []models.FareRequest) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`BEGIN MindenAirport.DeleteFlightFares(:1); END;`, flightID); err != nil {
+		return err
+	}
+	for _, fare := range fares {
+		_, err := tx.Exec(`BEGIN MindenAirport.AddFlightFare(:1, :2, :3); END;`, flightID, fare.TravelClass, *fare.Price)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPaymentByID retrieves a payment without its tickets and refunds.
+//
+// Returns:
+//   - *models.Payment: The payment if found, nil if not found
+//   - error: Any database error
+func (db Database) GetPaymentByID(id string) (*models.Payment, error) {
+	payments, err := db.payments(`BEGIN MindenAirport.GetPaymentByID(:1, :2); END;`, id)
+	if err != nil || len(payments) == 0 {
+		return nil, err
+	}
+	return &payments[0], nil
+}
+
+// GetPaymentByReference retrieves the payment a provider knows by the
+// reference, without its tickets and refunds. It returns nil if not found.
+func (db Database) GetPaymentByReference(provider, reference string) (*models.Payment, error) {
+	payments, err := db.payments(`BEGIN MindenAirport.GetPaymentByReference(:1, :2, :3); END;`, provider, reference)
+	if err != nil || len(payments) == 0 {
+		return nil, err
+	}
+	return &payments[0], nil
+}
+
+// GetPaymentsByBooking retrieves the payments of a booking, oldest first,
+// without their tickets and refunds.
+func (db Database) GetPaymentsByBooking(bookingID string) ([]models.Payment, error) {
+	return db.payments(`BEGIN MindenAirport.GetPaymentsByBooking(:1, :2); END;`, bookingID)
+}
+
+// payments runs a procedure returning payments for the given arguments.
+func (db Database) payments(query string, args ...interface{}) ([]models.Payment, error) {
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(append(args, sql.Out{Dest: &cursor})...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var payments []models.Payment
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var p models.Payment
+		p.ID = r[0].(string)
+		p.BookingID = r[1].(string)
+		if r[2] != nil {
+			p.Locator = r[2].(string)
+		}
+		p.AirportUserID = r[3].(string)
+		p.Provider = r[4].(string)
+		if r[5] != nil {
+			p.Reference = r[5].(string)
+		}
+		p.Status = r[6].(string)
+		p.Amount, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
+		p.RefundedAmount, _ = strconv.ParseFloat(r[8].(godror.Number).String(), 64)
+		p.Currency = r[9].(string)
+		if r[10] != nil {
+			p.CardBrand = r[10].(string)
+		}
+		if r[11] != nil {
+			p.CardLast4 = r[11].(string)
+		}
+		if r[12] != nil {
+			p.FailureCode = r[12].(string)
+		}
+		if r[13] != nil {
+			p.FailureMessage = r[13].(string)
+		}
+		p.CreatedAt = r[14].(time.Time)
+		p.UpdatedAt = r[15].(time.Time)
+		if r[16] != nil {
+			capturedAt := r[16].(time.Time)
+			p.CapturedAt = &capturedAt
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, nil
+}
+
+// CreatePayment inserts a pending payment linked to the tickets it pays, in
+// one transaction. The ID and creation time are generated if not set.
+func (db Database) CreatePayment(payment *models.Payment) error {
+	if payment.ID == "" {
+		payment.ID = uuid.New().String()
+	}
+	if payment.CreatedAt.IsZero() {
+		payment.CreatedAt = time.Now().UTC()
+	}
+	payment.UpdatedAt = payment.CreatedAt
+	payment.Status = "PENDING"
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`BEGIN MindenAirport.CreatePayment(:1, :2, :3, :4, :5, :6, :7, :8, :9); END;`,
+		payment.ID, payment.BookingID, payment.AirportUserID, payment.Provider, payment.Amount, payment.Currency,
+		payment.CardBrand, payment.CardLast4, payment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, ticket := range payment.Tickets {
+		_, err = tx.Exec(`BEGIN MindenAirport.AddPaymentTicket(:1, :2, :3); END;`, payment.ID, ticket.TicketID, ticket.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdatePayment stores the provider reference, status and failure of a payment.
+func (db Database) UpdatePayment(payment models.Payment) error {
+	var capturedAt interface{}
+	if payment.CapturedAt != nil {
+		capturedAt = *payment.CapturedAt
+	}
+
+	query := `BEGIN MindenAirport.UpdatePayment(:1, :2, :3, :4, :5, :6, :7); END;`
+	_, err := db.Exec(query, payment.ID, payment.Reference, payment.Status, payment.FailureCode, payment.FailureMessage,
+		payment.UpdatedAt, capturedAt)
+	return err
+}
+
+// GetPaymentTickets retrieves the tickets paid by a payment, by departure.
+func (db Database) GetPaymentTickets(paymentID string) ([]models.PaymentTicket, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPaymentTickets(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(paymentID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var tickets []models.PaymentTicket
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var t models.PaymentTicket
+		t.TicketID = r[0].(string)
+		t.Amount, _ = strconv.ParseFloat(r[1].(godror.Number).String(), 64)
+		if r[2] != nil {
+			t.RefundID = r[2].(string)
+		}
+		tickets = append(tickets, t)
+	}
+
+	return tickets, nil
+}
+
+// GetPaymentRefunds retrieves the refunds of a payment, oldest first.
+func (db Database) GetPaymentRefunds(paymentID string) ([]models.PaymentRefund, error) {
+	return db.refunds(`BEGIN MindenAirport.GetPaymentRefunds(:1, :2); END;`, paymentID)
+}
+
+// GetRefundByReference retrieves the refund a provider knows by the
+// reference. It returns nil if not found.
+func (db Database) GetRefundByReference(provider, reference string) (*models.PaymentRefund, error) {
+	refunds, err := db.refunds(`BEGIN MindenAirport.GetRefundByReference(:1, :2, :3); END;`, provider, reference)
+	if err != nil || len(refunds) == 0 {
+		return nil, err
+	}
+	return &refunds[0], nil
+}
+
+// refunds runs a procedure returning refunds for the given arguments.
+func (db Database) refunds(query string, args ...interface{}) ([]models.PaymentRefund, error) {
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(append(args, sql.Out{Dest: &cursor})...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var refunds []models.PaymentRefund
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var refund models.PaymentRefund
+		refund.ID = r[0].(string)
+		refund.PaymentID = r[1].(string)
+		if r[2] != nil {
+			refund.Reference = r[2].(string)
+		}
+		refund.Amount, _ = strconv.ParseFloat(r[3].(godror.Number).String(), 64)
+		refund.Status = r[4].(string)
+		if r[5] != nil {
+			refund.Reason = r[5].(string)
+		}
+		refund.CreatedAt = r[6].(time.Time)
+		if r[7] != nil {
+			refund.CreatedBy = r[7].(string)
+		}
+		refunds = append(refunds, refund)
+	}
+
+	return refunds, nil
+}
+
+// CreatePaymentRefund inserts a pending refund and marks the tickets it
+// pays back, in one transaction. It returns false, and stores nothing, if
+// one of the tickets is not part of the payment or was already refunded.
+// The ID and creation time are generated if not set.
+func (db Database) CreatePaymentRefund(refund *models.PaymentRefund, ticketIDs []string) (bool, error) {
+	if refund.ID == "" {
+		refund.ID = uuid.New().String()
+	}
+	if refund.CreatedAt.IsZero() {
+		refund.CreatedAt = time.Now().UTC()
+	}
+	refund.Status = "PENDING"
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var createdBy interface{}
+	if refund.CreatedBy != "" {
+		createdBy = refund.CreatedBy
+	}
+	_, err = tx.Exec(`BEGIN MindenAirport.CreatePaymentRefund(:1, :2, :3, :4, :5, :6); END;`,
+		refund.ID, refund.PaymentID, refund.Amount, refund.Reason, refund.CreatedAt, createdBy)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ticketID := range ticketIDs {
+		var updated int
+		_, err = tx.Exec(`BEGIN MindenAirport.SetPaymentTicketRefund(:1, :2, :3, :4); END;`,
+			refund.PaymentID, ticketID, refund.ID, sql.Out{Dest: &updated})
+		if err != nil {
+			return false, err
+		}
+		if updated == 0 {
+			return false, nil
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// CompletePaymentRefund sets a pending refund to SUCCEEDED or FAILED with
+// the provider reference, if known. A successful refund is added to the
+// refunded amount of its payment; the tickets of a failed one can be
+// refunded again.
+func (db Database) CompletePaymentRefund(id, reference, status string, now time.Time) error {
+	var ref interface{}
+	if reference != "" {
+		ref = reference
+	}
+
+	query := `BEGIN MindenAirport.CompletePaymentRefund(:1, :2, :3, :4); END;`
+	_, err := db.Exec(query, id, ref, status, now.UTC())
+	return err
+}
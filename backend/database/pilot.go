@@ -0,0 +1,130 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetPilots retrieves all pilots ordered by last name.
+func (db Database) GetPilots() ([]models.Pilot, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllPilots(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var pilots []models.Pilot
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		pilots = append(pilots, pilotFromRow(r))
+	}
+
+	return pilots, nil
+}
+
+// GetPilotByID retrieves a specific pilot.
+//
+// Returns:
+//   - *models.Pilot: The pilot if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetPilotByID(id string) (*models.Pilot, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPilotByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		pilot := pilotFromRow(r)
+		return &pilot, nil
+	}
+
+	return nil, nil
+}
+
+// CreatePilot inserts a new pilot. A new ID is generated if none is set.
+func (db Database) CreatePilot(pilot *models.Pilot) error {
+	if pilot.ID == "" {
+		pilot.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreatePilot(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	_, err := db.Exec(query, pilot.ID, pilot.FirstName, pilot.LastName, pilot.LicenseType,
+		pilot.LicenseNumber, pilot.LicenseExpiry, pilot.FlightHours, pilot.MedicalCheckDate)
+	return err
+}
+
+// UpdatePilot replaces all attributes of an existing pilot.
+func (db Database) UpdatePilot(pilot models.Pilot) error {
+	query := `BEGIN MindenAirport.UpdatePilot(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	_, err := db.Exec(query, pilot.ID, pilot.FirstName, pilot.LastName, pilot.LicenseType,
+		pilot.LicenseNumber, pilot.LicenseExpiry, pilot.FlightHours, pilot.MedicalCheckDate)
+	return err
+}
+
+// DeletePilot removes a pilot. Pilots still assigned to flights cannot be deleted.
+func (db Database) DeletePilot(id string) error {
+	query := `BEGIN MindenAirport.DeletePilot(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// AddPilotFlightHours adds (or, for negative values, removes) logged flight hours of a pilot.
+func (db Database) AddPilotFlightHours(id string, hours float64) error {
+	query := `BEGIN MindenAirport.AddPilotFlightHours(:1, :2); END;`
+	_, err := db.Exec(query, id, hours)
+	return err
+}
+
+// pilotFromRow maps a row of the pilot procedures onto a models.Pilot.
+func pilotFromRow(r []driver.Value) models.Pilot {
+	var pilot models.Pilot
+	pilot.ID = r[0].(string)
+	pilot.FirstName = r[1].(string)
+	pilot.LastName = r[2].(string)
+	if r[3] != nil {
+		pilot.LicenseType = r[3].(string)
+	}
+	if r[4] != nil {
+		pilot.LicenseNumber = r[4].(string)
+	}
+	if r[5] != nil {
+		t := r[5].(time.Time)
+		pilot.LicenseExpiry = &t
+	}
+	if r[6] != nil {
+		pilot.FlightHours, _ = strconv.ParseFloat(r[6].(godror.Number).String(), 64)
+	}
+	if r[7] != nil {
+		t := r[7].(time.Time)
+		pilot.MedicalCheckDate = &t
+	}
+	return pilot
+}
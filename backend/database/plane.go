@@ -0,0 +1,197 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetPlanes retrieves the planes of the fleet, optionally restricted to one airline.
+//
+// Parameters:
+//   - airlineID: IATA code of the airline, or "" for all planes
+func (db Database) GetPlanes(airlineID string) ([]models.Plane, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllPlanes(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(airlineID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var planes []models.Plane
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		planes = append(planes, planeFromRow(r))
+	}
+
+	return planes, nil
+}
+
+// GetPlaneByID retrieves a specific aircraft of the fleet.
+//
+// Returns:
+//   - *models.Plane: The plane if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetPlaneByID(id string) (*models.Plane, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPlaneByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		plane := planeFromRow(r)
+		return &plane, nil
+	}
+
+	return nil, nil
+}
+
+// planeFromRow maps a row of the plane procedures onto a models.Plane.
+func planeFromRow(r []driver.Value) models.Plane {
+	var plane models.Plane
+	plane.ID = r[0].(string)
+	if r[1] != nil {
+		plane.Name = r[1].(string)
+	}
+	plane.Model = r[2].(string)
+	plane.Seats, _ = strconv.Atoi(r[3].(godror.Number).String())
+	if r[4] != nil {
+		plane.AirlineID = r[4].(string)
+	}
+	if r[5] != nil {
+		plane.HangarID = r[5].(string)
+	}
+	if r[6] != nil {
+		plane.ManufacturingYear, _ = strconv.Atoi(r[6].(godror.Number).String())
+	}
+	if r[7] != nil {
+		plane.MaxTakeoffWeight, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
+	}
+	if r[8] != nil {
+		plane.FuelCapacity, _ = strconv.ParseFloat(r[8].(godror.Number).String(), 64)
+	}
+	if r[9] != nil {
+		plane.Status = r[9].(string)
+	}
+	return plane
+}
+
+// CreatePlane inserts a new plane. A new ID is generated if none is set.
+func (db Database) CreatePlane(plane *models.Plane) error {
+	if plane.ID == "" {
+		plane.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreatePlane(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10); END;`
+	_, err := db.Exec(query, plane.ID, plane.Name, plane.Model, plane.Seats, plane.AirlineID, plane.HangarID,
+		plane.ManufacturingYear, plane.MaxTakeoffWeight, plane.FuelCapacity, plane.Status)
+	return err
+}
+
+// UpdatePlane replaces all attributes of an existing plane.
+func (db Database) UpdatePlane(plane models.Plane) error {
+	query := `BEGIN MindenAirport.UpdatePlane(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10); END;`
+	_, err := db.Exec(query, plane.ID, plane.Name, plane.Model, plane.Seats, plane.AirlineID, plane.HangarID,
+		plane.ManufacturingYear, plane.MaxTakeoffWeight, plane.FuelCapacity, plane.Status)
+	return err
+}
+
+// DeletePlane removes a plane. Planes still used by flights cannot be deleted.
+func (db Database) DeletePlane(id string) error {
+	query := `BEGIN MindenAirport.DeletePlane(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// GetFleetOverview counts the planes and seats of every airline by status.
+func (db Database) GetFleetOverview() ([]models.FleetSummary, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFleetOverview(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var fleet []models.FleetSummary
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		var summary models.FleetSummary
+		summary.AirlineID = r[0].(string)
+		summary.AirlineName = r[1].(string)
+		summary.Planes, _ = strconv.Atoi(r[2].(godror.Number).String())
+		summary.Active, _ = strconv.Atoi(r[3].(godror.Number).String())
+		summary.Maintenance, _ = strconv.Atoi(r[4].(godror.Number).String())
+		summary.Inactive, _ = strconv.Atoi(r[5].(godror.Number).String())
+		summary.ActiveSeats, _ = strconv.Atoi(r[6].(godror.Number).String())
+		fleet = append(fleet, summary)
+	}
+
+	return fleet, nil
+}
+
+// GetFlightsByPlane retrieves the flights of a plane departing within
+// [start, end), ordered by departure.
+func (db Database) GetFlightsByPlane(planeID string, start, end time.Time) ([]models.Flight, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightsByPlane(:1, :2, :3, :4); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(planeID, start, end, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var flights []models.Flight
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		flights = append(flights, flightFromRow(r))
+	}
+
+	return flights, nil
+}
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetPlots retrieves the plots of the airport with their type and current
+// load, ordered by position. Occupants are not filled in.
+//
+// Parameters:
+//   - plotType: Plot type ID or name (e.g. "hangar"), or "" for all types
+//   - status: Plot status (AVAILABLE, OCCUPIED, MAINTENANCE), or "" for all
+func (db Database) GetPlots(plotType, status string) ([]models.PlotOverview, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllPlots(:1, :2, :3); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(plotType, status, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var plots []models.PlotOverview
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		plots = append(plots, plotFromRow(r))
+	}
+
+	return plots, nil
+}
+
+// GetPlotByID retrieves a specific plot with its type and current load.
+// Occupants are not filled in.
+//
+// Returns:
+//   - *models.PlotOverview: The plot if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetPlotByID(id string) (*models.PlotOverview, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPlotByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		plot := plotFromRow(r)
+		return &plot, nil
+	}
+
+	return nil, nil
+}
+
+// plotFromRow maps a row of the plot procedures onto a models.PlotOverview.
+func plotFromRow(r []driver.Value) models.PlotOverview {
+	var plot models.PlotOverview
+	plot.ID = r[0].(string)
+	plot.Position, _ = strconv.Atoi(r[1].(godror.Number).String())
+	plot.TypeID = r[2].(godror.Number).String()
+	if r[3] != nil {
+		plot.AreaSqFt, _ = strconv.ParseFloat(r[3].(godror.Number).String(), 64)
+	}
+	if r[4] != nil {
+		plot.Status = r[4].(string)
+	}
+	if r[5] != nil {
+		t := r[5].(time.Time)
+		plot.LastMaintenance = &t
+	}
+	if r[6] != nil {
+		plot.MaxWeightCapacity, _ = strconv.ParseFloat(r[6].(godror.Number).String(), 64)
+	}
+	if r[7] != nil {
+		plot.UtilitiesAvailable = r[7].(string)
+	}
+	if r[8] != nil {
+		plot.TerminalID = r[8].(string)
+	}
+	plot.TypeName = r[9].(string)
+	plot.TypeLabel = r[10].(string)
+	plot.Load, _ = strconv.ParseFloat(r[11].(godror.Number).String(), 64)
+	plot.Occupants = []models.PlotOccupant{}
+	return plot
+}
+
+// GetPlotTypes retrieves all plot types.
+func (db Database) GetPlotTypes() ([]models.PlotType, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllPlotTypes(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var types []models.PlotType
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		var plotType models.PlotType
+		plotType.ID = r[0].(godror.Number).String()
+		plotType.Name = r[1].(string)
+		plotType.Label = r[2].(string)
+		types = append(types, plotType)
+	}
+
+	return types, nil
+}
+
+// CreatePlot inserts a new plot. A new ID is generated if none is set.
+func (db Database) CreatePlot(plot *models.Plot) error {
+	if plot.ID == "" {
+		plot.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreatePlot(:1, :2, :3, :4, :5, :6, :7, :8, :9); END;`
+	_, err := db.Exec(query, plot.ID, plot.Position, plot.TypeID, plot.AreaSqFt, plot.Status,
+		plot.LastMaintenance, plot.MaxWeightCapacity, plot.UtilitiesAvailable, plot.TerminalID)
+	return err
+}
+
+// UpdatePlot replaces all attributes of an existing plot.
+func (db Database) UpdatePlot(plot models.Plot) error {
+	query := `BEGIN MindenAirport.UpdatePlot(:1, :2, :3, :4, :5, :6, :7, :8, :9); END;`
+	_, err := db.Exec(query, plot.ID, plot.Position, plot.TypeID, plot.AreaSqFt, plot.Status,
+		plot.LastMaintenance, plot.MaxWeightCapacity, plot.UtilitiesAvailable, plot.TerminalID)
+	return err
+}
+
+// DeletePlot removes a plot. Plots with facilities built on them cannot be deleted.
+func (db Database) DeletePlot(id string) error {
+	query := `BEGIN MindenAirport.DeletePlot(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// GetPlotOccupants retrieves the hangars, shops, terminals and car parks
+// built on a plot, or on all plots if plotID is "".
+func (db Database) GetPlotOccupants(plotID string) ([]models.PlotOccupant, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPlotOccupants(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(plotID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var occupants []models.PlotOccupant
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		var occupant models.PlotOccupant
+		occupant.PlotID = r[0].(string)
+		occupant.Kind = r[1].(string)
+		occupant.ID = r[2].(string)
+		occupant.Name = r[3].(string)
+		occupants = append(occupants, occupant)
+	}
+
+	return occupants, nil
+}
+
+// GetPlotLoad returns the maximum takeoff weight in pounds of all planes
+// parked in hangars on a plot, leaving out the plane excludePlaneID.
+func (db Database) GetPlotLoad(plotID, excludePlaneID string) (float64, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetPlotLoad(:1, :2, :3); END;`)
+	if err != nil {
+		return 0, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(plotID, excludePlaneID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return 0, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var load float64
+	if err := cursor.Next(r); err == nil {
+		load, _ = strconv.ParseFloat(r[0].(godror.Number).String(), 64)
+	}
+
+	return load, nil
+}
+
+// RefreshPlotStatus sets a plot to OCCUPIED or AVAILABLE depending on whether
+// facilities are built on it. Plots under maintenance keep their status.
+func (db Database) RefreshPlotStatus(id string) error {
+	query := `BEGIN MindenAirport.RefreshPlotStatus(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetScheduleTemplates retrieves all schedule templates ordered by flight
+// number and start of validity.
+func (db Database) GetScheduleTemplates() ([]models.ScheduleTemplate, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetScheduleTemplates(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var templates []models.ScheduleTemplate
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		templates = append(templates, scheduleTemplateFromRow(r))
+	}
+
+	return templates, nil
+}
+
+// GetScheduleTemplateByID retrieves a specific schedule template.
+//
+// Returns:
+//   - *models.ScheduleTemplate: The template if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetScheduleTemplateByID(id string) (*models.ScheduleTemplate, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetScheduleTemplateByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		template := scheduleTemplateFromRow(r)
+		return &template, nil
+	}
+
+	return nil, nil
+}
+
+// scheduleTemplateFromRow maps a row of the schedule template procedures onto
+// a models.ScheduleTemplate.
+func scheduleTemplateFromRow(r []driver.Value) models.ScheduleTemplate {
+	var template models.ScheduleTemplate
+	template.ID = r[0].(string)
+	template.FlightNumber = r[1].(string)
+	template.From = r[2].(string)
+	template.To = r[3].(string)
+	template.DepartureTime = r[4].(string)
+	template.ArrivalTime = r[5].(string)
+	template.ArrivalDayOffset, _ = strconv.Atoi(r[6].(godror.Number).String())
+	template.DaysOfWeek = r[7].(string)
+	template.ValidFrom = r[8].(time.Time)
+	template.ValidTo = r[9].(time.Time)
+	template.PlaneID = r[10].(string)
+	if r[11] != nil {
+		template.TerminalID = r[11].(string)
+	}
+	if r[12] != nil {
+		template.Gate = r[12].(string)
+	}
+	template.Revision, _ = strconv.Atoi(r[13].(godror.Number).String())
+	return template
+}
+
+// CreateScheduleTemplate inserts a new schedule template as revision 1. A new
+// ID is generated if none is set.
+func (db Database) CreateScheduleTemplate(template *models.ScheduleTemplate) error {
+	if template.ID == "" {
+		template.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateScheduleTemplate(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13); END;`
+	_, err := db.Exec(query, template.ID, template.FlightNumber, template.From, template.To, template.DepartureTime,
+		template.ArrivalTime, template.ArrivalDayOffset, template.DaysOfWeek, template.ValidFrom, template.ValidTo,
+		template.PlaneID, template.TerminalID, template.Gate)
+	if err != nil {
+		return err
+	}
+
+	template.Revision = 1
+	return nil
+}
+
+// UpdateScheduleTemplate replaces all attributes of an existing schedule
+// template and sets its new revision.
+func (db Database) UpdateScheduleTemplate(template *models.ScheduleTemplate) error {
+	var revision int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.UpdateScheduleTemplate(:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13, :14); END;`)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(template.ID, template.FlightNumber, template.From, template.To, template.DepartureTime,
+		template.ArrivalTime, template.ArrivalDayOffset, template.DaysOfWeek, template.ValidFrom, template.ValidTo,
+		template.PlaneID, template.TerminalID, template.Gate, sql.Out{Dest: &revision})
+	if err != nil {
+		return err
+	}
+
+	template.Revision = revision
+	return nil
+}
+
+// DeleteScheduleTemplate removes a schedule template. Its flights are kept
+// and lose their template link.
+func (db Database) DeleteScheduleTemplate(id string) error {
+	query := `BEGIN MindenAirport.DeleteScheduleTemplate(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// GetTemplateFlights retrieves the flights of a schedule template departing
+// at or after from, ordered by scheduled departure, with their number of
+// tickets that are not cancelled.
+func (db Database) GetTemplateFlights(templateID string, from time.Time) ([]models.TemplateFlight, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetTemplateFlights(:1, :2, :3); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(templateID, from, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var flights []models.TemplateFlight
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		flight := models.TemplateFlight{Flight: flightFromRow(r)}
+		flight.Tickets, _ = strconv.Atoi(r[16].(godror.Number).String())
+		flights = append(flights, flight)
+	}
+
+	return flights, nil
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetShops retrieves the shops of the directory ordered by name.
+//
+// Parameters:
+//   - category: Shop type category (e.g. "Food"), or "" for all categories
+//   - terminalID: Terminal the shop's plot lies in, or "" for all terminals
+//   - dutyFree: Only duty-free (true) or regular (false) shops, or nil for both
+func (db Database) GetShops(category, terminalID string, dutyFree *bool) ([]models.ShopListing, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllShops(:1, :2, :3, :4); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var dutyFreeFilter any
+	if dutyFree != nil {
+		dutyFreeFilter = boolToNumber(*dutyFree)
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(category, terminalID, dutyFreeFilter, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var shops []models.ShopListing
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		shops = append(shops, shopFromRow(r))
+	}
+
+	return shops, nil
+}
+
+// GetShopByID retrieves a specific shop.
+//
+// Returns:
+//   - *models.ShopListing: The shop if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetShopByID(id string) (*models.ShopListing, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetShopByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		shop := shopFromRow(r)
+		return &shop, nil
+	}
+
+	return nil, nil
+}
+
+// shopFromRow maps a row of the shop procedures onto a models.ShopListing.
+func shopFromRow(r []driver.Value) models.ShopListing {
+	var shop models.ShopListing
+	shop.ID = r[0].(string)
+	shop.Name = r[1].(string)
+	shop.TypeID = r[2].(string)
+	shop.PlotID = r[3].(string)
+	if r[4] != nil {
+		shop.OpeningTime = r[4].(string)
+	}
+	if r[5] != nil {
+		shop.ClosingTime = r[5].(string)
+	}
+	if r[6] != nil {
+		shop.Description = r[6].(string)
+	}
+	if r[7] != nil {
+		shop.IsDutyFree = r[7].(godror.Number).String() == "1"
+	}
+	shop.TypeName = r[8].(string)
+	if r[9] != nil {
+		shop.Category = r[9].(string)
+	}
+	if r[10] != nil {
+		shop.SecurityLevel = r[10].(string)
+	}
+	if r[11] != nil {
+		shop.TypicalHours = r[11].(string)
+	}
+	if r[12] != nil {
+		shop.TerminalID = r[12].(string)
+	}
+	return shop
+}
+
+// GetShopTypes retrieves all shop types.
+func (db Database) GetShopTypes() ([]models.ShopType, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllShopTypes(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var types []models.ShopType
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		var shopType models.ShopType
+		shopType.ID = r[0].(string)
+		shopType.Name = r[1].(string)
+		shopType.Label = r[2].(string)
+		if r[3] != nil {
+			shopType.Category = r[3].(string)
+		}
+		if r[4] != nil {
+			shopType.SecurityLevel = r[4].(string)
+		}
+		if r[5] != nil {
+			shopType.Description = r[5].(string)
+		}
+		if r[6] != nil {
+			shopType.TypicalHours = r[6].(string)
+		}
+		types = append(types, shopType)
+	}
+
+	return types, nil
+}
+
+// CreateShop inserts a new shop. A new ID is generated if none is set.
+func (db Database) CreateShop(shop *models.Shop) error {
+	if shop.ID == "" {
+		shop.ID = uuid.New().String()
+	}
+
+	query := `BEGIN MindenAirport.CreateShop(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	_, err := db.Exec(query, shop.ID, shop.Name, shop.TypeID, shop.PlotID, shop.OpeningTime,
+		shop.ClosingTime, shop.Description, boolToNumber(shop.IsDutyFree))
+	return err
+}
+
+// UpdateShop replaces all attributes of an existing shop.
+func (db Database) UpdateShop(shop models.Shop) error {
+	query := `BEGIN MindenAirport.UpdateShop(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	_, err := db.Exec(query, shop.ID, shop.Name, shop.TypeID, shop.PlotID, shop.OpeningTime,
+		shop.ClosingTime, shop.Description, boolToNumber(shop.IsDutyFree))
+	return err
+}
+
+// DeleteShop removes a shop.
+func (db Database) DeleteShop(id string) error {
+	query := `BEGIN MindenAirport.DeleteShop(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
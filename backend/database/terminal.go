@@ -0,0 +1,163 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+)
+
+// GetTerminals retrieves all terminals ordered by ID.
+func (db Database) GetTerminals() ([]models.Terminal, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetAllTerminals(:1); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var terminals []models.Terminal
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		terminals = append(terminals, terminalFromRow(r))
+	}
+
+	return terminals, nil
+}
+
+// GetTerminalByID retrieves a specific terminal.
+//
+// Returns:
+//   - *models.Terminal: The terminal if found, nil if not found
+//   - error: Any database error that occurred during retrieval
+func (db Database) GetTerminalByID(id string) (*models.Terminal, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetTerminalByID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	err = cursor.Next(r)
+	if err == nil {
+		terminal := terminalFromRow(r)
+		return &terminal, nil
+	}
+
+	return nil, nil
+}
+
+// CreateTerminal inserts a new terminal.
+func (db Database) CreateTerminal(terminal models.Terminal) error {
+	query := `BEGIN MindenAirport.CreateTerminal(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	_, err := db.Exec(query, terminal.ID, terminal.Name, terminal.Capacity, terminal.Status,
+		terminal.FloorCount, terminal.Services, terminal.OpeningHours, terminal.PlotID)
+	return err
+}
+
+// UpdateTerminal replaces all attributes of an existing terminal.
+func (db Database) UpdateTerminal(terminal models.Terminal) error {
+	query := `BEGIN MindenAirport.UpdateTerminal(:1, :2, :3, :4, :5, :6, :7, :8); END;`
+	_, err := db.Exec(query, terminal.ID, terminal.Name, terminal.Capacity, terminal.Status,
+		terminal.FloorCount, terminal.Services, terminal.OpeningHours, terminal.PlotID)
+	return err
+}
+
+// DeleteTerminal removes a terminal. Terminals still referenced by flights
+// or gates cannot be deleted.
+func (db Database) DeleteTerminal(id string) error {
+	query := `BEGIN MindenAirport.DeleteTerminal(:1); END;`
+	_, err := db.Exec(query, id)
+	return err
+}
+
+// GetTerminalLoads counts the departures and booked (not cancelled) passengers
+// of every terminal for flights scheduled to depart within [start, end).
+// Utilization and crowding level are left for the caller to derive.
+func (db Database) GetTerminalLoads(start, end time.Time) ([]models.TerminalLoad, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetTerminalLoad(:1, :2, :3); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(start, end, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var loads []models.TerminalLoad
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		var load models.TerminalLoad
+		load.TerminalID = r[0].(string)
+		load.Name = r[1].(string)
+		if r[2] != nil {
+			load.Status = r[2].(string)
+		}
+		if r[3] != nil {
+			load.Capacity, _ = strconv.Atoi(r[3].(godror.Number).String())
+		}
+		load.Flights, _ = strconv.Atoi(r[4].(godror.Number).String())
+		load.Passengers, _ = strconv.Atoi(r[5].(godror.Number).String())
+		load.WindowStart = start
+		load.WindowEnd = end
+		loads = append(loads, load)
+	}
+
+	return loads, nil
+}
+
+// terminalFromRow maps a row of the terminal procedures onto a models.Terminal.
+func terminalFromRow(r []driver.Value) models.Terminal {
+	var terminal models.Terminal
+	terminal.ID = r[0].(string)
+	terminal.Name = r[1].(string)
+	if r[2] != nil {
+		terminal.Capacity, _ = strconv.Atoi(r[2].(godror.Number).String())
+	}
+	if r[3] != nil {
+		terminal.Status = r[3].(string)
+	}
+	if r[4] != nil {
+		terminal.FloorCount, _ = strconv.Atoi(r[4].(godror.Number).String())
+	}
+	if r[5] != nil {
+		terminal.Services = r[5].(string)
+	}
+	if r[6] != nil {
+		terminal.OpeningHours = r[6].(string)
+	}
+	if r[7] != nil {
+		terminal.PlotID = r[7].(string)
+	}
+	return terminal
+}
@@ -3,7 +3,6 @@ package database
 import (
 	"database/sql"
 	"database/sql/driver"
-	"log"
 	"mindenairport/models"
 	"strconv"
 	"time"
@@ -11,19 +10,18 @@ import (
 	"github.com/godror/godror"
 )
 
+// GetTicketByID retrieves a specific ticket with its flight details.
+// The returned ticket has an empty ID if it does not exist.
 func (db Database) GetTicketByID(id string) (models.Ticket, error) {
-	stmt, err := db.Prepare(`
-	BEGIN 
-	GetTicketByID(:1, :2); END;
-	`)
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetTicketByID(:1, :2); END;`)
 	if err != nil {
-		log.Fatal("Error preparing statement:", err)
+		return models.Ticket{}, err
 	}
 
 	var cursor driver.Rows
 	_, err = stmt.Exec(id, sql.Out{Dest: &cursor})
 	if err != nil {
-		log.Fatal("Failed to execute prepared statement:", err)
+		return models.Ticket{}, err
 	}
 
 	r := make([]driver.Value, len(cursor.Columns()))
@@ -33,39 +31,14 @@ func (db Database) GetTicketByID(id string) (models.Ticket, error) {
 
 	err = cursor.Next(r)
 	if err == nil {
-		ticket.ID = r[0].(string)
-		if r[1] != nil {
-			ticket.SeatNumber = r[1].(string)
-		}
-		ticket.From = r[2].(string)
-		ticket.To = r[3].(string)
-		if r[4] != nil {
-			ticket.BookingDate = r[4].(time.Time)
-		}
-		if r[5] != nil {
-			ticket.DepartureTime = r[5].(string)
-		}
-		if r[6] != nil {
-			ticket.TravelClass = r[6].(string)
-		}
-		if r[7] != nil {
-			ticket.Price, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
-		}
-		if r[8] != nil {
-			ticket.Gate = r[8].(string)
-		}
-		if r[9] != nil {
-			ticket.BaggageClaim = r[9].(string)
-		}
-		if r[10] != nil {
-			ticket.Status = r[10].(string)
-		}
+		ticket = ticketFromRow(r)
 	}
 
 	return ticket, nil
 }
 
-// GetTicketsByUserID retrieves all tickets for a specific user
+// GetTicketsByUserID retrieves all tickets a user holds or travels on as
+// booking passenger
 func (db Database) GetTicketsByUserID(userID string) ([]models.Ticket, error) {
 	stmt, err := db.Prepare(`
 	BEGIN 
@@ -91,41 +64,7 @@ func (db Database) GetTicketsByUserID(userID string) ([]models.Ticket, error) {
 		if err != nil {
 			break
 		}
-		var ticket models.Ticket
-		ticket.ID = r[0].(string)
-		if r[1] != nil {
-			ticket.SeatNumber = r[1].(string)
-		}
-		ticket.From = r[2].(string)
-		ticket.To = r[3].(string)
-		if r[4] != nil {
-			ticket.BookingDate = r[4].(time.Time)
-		}
-		if r[5] != nil {
-			ticket.DepartureTime = r[5].(string)
-		}
-		if r[6] != nil {
-			ticket.TravelClass = r[6].(string)
-		}
-		if r[7] != nil {
-			ticket.Price, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
-		}
-		if r[8] != nil {
-			ticket.Gate = r[8].(string)
-		}
-		if r[9] != nil {
-			ticket.BaggageClaim = r[9].(string)
-		}
-		if r[10] != nil {
-			ticket.Status = r[10].(string)
-		}
-		if r[11] != nil {
-			ticket.AirportUserID = r[11].(string)
-		}
-		if r[12] != nil {
-			ticket.Flight = r[12].(string)
-		}
-		tickets = append(tickets, ticket)
+		tickets = append(tickets, ticketFromRow(r))
 	}
 
 	return tickets, nil
@@ -169,41 +108,7 @@ func (db Database) GetAllTickets(page, limit int) ([]models.Ticket, int, error)
 		if err != nil {
 			break
 		}
-		var ticket models.Ticket
-		ticket.ID = r[0].(string)
-		if r[1] != nil {
-			ticket.SeatNumber = r[1].(string)
-		}
-		ticket.From = r[2].(string)
-		ticket.To = r[3].(string)
-		if r[4] != nil {
-			ticket.BookingDate = r[4].(time.Time)
-		}
-		if r[5] != nil {
-			ticket.DepartureTime = r[5].(string)
-		}
-		if r[6] != nil {
-			ticket.TravelClass = r[6].(string)
-		}
-		if r[7] != nil {
-			ticket.Price, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
-		}
-		if r[8] != nil {
-			ticket.Gate = r[8].(string)
-		}
-		if r[9] != nil {
-			ticket.BaggageClaim = r[9].(string)
-		}
-		if r[10] != nil {
-			ticket.Status = r[10].(string)
-		}
-		if r[11] != nil {
-			ticket.AirportUserID = r[11].(string)
-		}
-		if r[12] != nil {
-			ticket.Flight = r[12].(string)
-		}
-		tickets = append(tickets, ticket)
+		tickets = append(tickets, ticketFromRow(r))
 	}
 
 	return tickets, total, nil
@@ -224,3 +129,92 @@ func (db Database) CalculateRevenue() (int, error) {
 
 	return total, nil
 }
+
+// GetTicketsByFlightID retrieves all tickets booked on a specific flight
+func (db Database) GetTicketsByFlightID(flightID string) ([]models.Ticket, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetTicketsByFlightID(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var tickets []models.Ticket
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+		tickets = append(tickets, ticketFromRow(r))
+	}
+
+	return tickets, nil
+}
+
+// ticketFromRow maps a row of the ticket procedures (GetTicketByID,
+// GetTicketsByUserID, GetTicketsByFlightID, GetAllTickets, GetBookingTickets)
+// onto a models.Ticket.
+func ticketFromRow(r []driver.Value) models.Ticket {
+	var ticket models.Ticket
+	ticket.ID = r[0].(string)
+	if r[1] != nil {
+		ticket.SeatNumber = r[1].(string)
+	}
+	ticket.From = r[2].(string)
+	ticket.To = r[3].(string)
+	if r[4] != nil {
+		ticket.BookingDate = r[4].(time.Time)
+	}
+	if r[5] != nil {
+		departure := utcTimestamp(r[5].(time.Time))
+		ticket.DepartureTime = departure.Format(time.RFC3339)
+		ticket.Departure = &models.LocalTime{UTC: departure}
+	}
+	if r[6] != nil {
+		ticket.TravelClass = r[6].(string)
+	}
+	if r[7] != nil {
+		ticket.Price, _ = strconv.ParseFloat(r[7].(godror.Number).String(), 64)
+	}
+	if r[8] != nil {
+		ticket.Gate = r[8].(string)
+	}
+	if r[9] != nil {
+		ticket.BaggageClaim = r[9].(string)
+	}
+	if r[10] != nil {
+		ticket.Status = r[10].(string)
+	}
+	if r[11] != nil {
+		ticket.AirportUserID = r[11].(string)
+	}
+	if r[12] != nil {
+		ticket.Flight = r[12].(string)
+	}
+	if r[13] != nil {
+		ticket.Aircraft = r[13].(string)
+	}
+	if r[14] != nil {
+		ticket.BookingID = r[14].(string)
+		ticket.Locator = r[15].(string)
+	}
+	if r[16] != nil {
+		ticket.PassengerID = r[16].(string)
+		ticket.PassengerName = r[17].(string) + " " + r[18].(string)
+	}
+	if r[19] != nil {
+		ticket.PassengerUserID = r[19].(string)
+	}
+	if r[20] != nil {
+		ticket.CompanionID = r[20].(string)
+	}
+	return ticket
+}
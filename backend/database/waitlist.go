@@ -0,0 +1,306 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"mindenairport/models"
+	"strconv"
+	"time"
+
+	"github.com/godror/godror"
+	"github.com/google/uuid"
+)
+
+// GetFlightOverbooking retrieves the seats of a flight's plane and the
+// overbooking percentages of the flight and its airline. Sold, held and
+// waiting seats are left for the caller to fill in.
+//
+// Returns:
+//   - *models.Overbooking: The limits if the flight exists and has a plane, nil otherwise
+//   - error: Any database error
+func (db Database) GetFlightOverbooking(flightID string) (*models.Overbooking, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightOverbooking(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	if err := cursor.Next(r); err != nil {
+		return nil, nil
+	}
+
+	overbooking := models.Overbooking{FlightID: r[0].(string)}
+	if r[1] != nil {
+		percent, _ := strconv.ParseFloat(r[1].(godror.Number).String(), 64)
+		overbooking.FlightPercent = &percent
+	}
+	if r[2] != nil {
+		overbooking.AirlineID = r[2].(string)
+	}
+	if r[3] != nil {
+		percent, _ := strconv.ParseFloat(r[3].(godror.Number).String(), 64)
+		overbooking.AirlinePercent = &percent
+	}
+	if r[4] != nil {
+		overbooking.Seats, _ = strconv.Atoi(r[4].(godror.Number).String())
+	}
+	return &overbooking, nil
+}
+
+// SetFlightOverbooking sets the overbooking percentage of a flight, or
+// removes it if percent is nil. It returns false if the flight does not exist.
+func (db Database) SetFlightOverbooking(flightID string, percent *float64) (bool, error) {
+	return db.setOverbooking(`BEGIN MindenAirport.SetFlightOverbooking(:1, :2, :3); END;`, flightID, percent)
+}
+
+// SetAirlineOverbooking sets the overbooking percentage of an airline, or
+// removes it if percent is nil. It returns false if the airline does not exist.
+func (db Database) SetAirlineOverbooking(airlineID string, percent *float64) (bool, error) {
+	return db.setOverbooking(`BEGIN MindenAirport.SetAirlineOverbooking(:1, :2, :3); END;`, airlineID, percent)
+}
+
+// setOverbooking runs one of the procedures setting an overbooking percentage.
+func (db Database) setOverbooking(query, id string, percent *float64) (bool, error) {
+	var updated int
+	var value interface{}
+	if percent != nil {
+		value = *percent
+	}
+
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return false, err
+	}
+	_, err = stmt.Exec(id, value, sql.Out{Dest: &updated})
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+// GetFlightCheckedInCount returns the number of checked-in tickets of a flight.
+func (db Database) GetFlightCheckedInCount(flightID string) (int, error) {
+	var count int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightCheckedInCount(:1, :2); END;`)
+	if err != nil {
+		return 0, err
+	}
+	_, err = stmt.Exec(flightID, sql.Out{Dest: &count})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetFlightHeldSeats returns the seats of a flight held by waitlist offers
+// that have not expired at now. The offer of excludeID is not counted.
+func (db Database) GetFlightHeldSeats(flightID string, now time.Time, excludeID string) (int, error) {
+	var count int
+
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetFlightHeldSeats(:1, :2, :3, :4); END;`)
+	if err != nil {
+		return 0, err
+	}
+	_, err = stmt.Exec(flightID, now.UTC(), excludeID, sql.Out{Dest: &count})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetWaitlistEntryByID retrieves a waitlist entry without its passengers.
+//
+// Returns:
+//   - *models.WaitlistEntry: The entry if found, nil if not found
+//   - error: Any database error
+func (db Database) GetWaitlistEntryByID(id string) (*models.WaitlistEntry, error) {
+	entries, err := db.waitlistEntries(`BEGIN MindenAirport.GetWaitlistEntryByID(:1, :2); END;`, id)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// GetWaitlistByUserID retrieves the waitlist entries of a user, newest
+// first, without their passengers.
+func (db Database) GetWaitlistByUserID(userID string) ([]models.WaitlistEntry, error) {
+	return db.waitlistEntries(`BEGIN MindenAirport.GetWaitlistByUserID(:1, :2); END;`, userID)
+}
+
+// GetFlightWaitlist retrieves all waitlist entries of a flight in the order
+// they were made, without their passengers.
+func (db Database) GetFlightWaitlist(flightID string) ([]models.WaitlistEntry, error) {
+	return db.waitlistEntries(`BEGIN MindenAirport.GetFlightWaitlist(:1, :2); END;`, flightID)
+}
+
+// GetStaleWaitlistEntries retrieves the open waitlist entries whose offer
+// has expired at now or whose flight has departed or was cancelled.
+func (db Database) GetStaleWaitlistEntries(now time.Time) ([]models.WaitlistEntry, error) {
+	return db.waitlistEntries(`BEGIN MindenAirport.GetStaleWaitlistEntries(:1, :2); END;`, now.UTC())
+}
+
+// waitlistEntries runs a procedure returning waitlist entries for one argument.
+func (db Database) waitlistEntries(query string, arg interface{}) ([]models.WaitlistEntry, error) {
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(arg, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var entries []models.WaitlistEntry
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var entry models.WaitlistEntry
+		entry.ID = r[0].(string)
+		entry.FlightID = r[1].(string)
+		entry.AirportUserID = r[2].(string)
+		entry.TravelClass, _ = strconv.Atoi(r[3].(godror.Number).String())
+		entry.Seats, _ = strconv.Atoi(r[4].(godror.Number).String())
+		entry.Status = r[5].(string)
+		entry.CreatedAt = r[6].(time.Time)
+		if r[7] != nil {
+			offeredAt := r[7].(time.Time)
+			entry.OfferedAt = &offeredAt
+		}
+		if r[8] != nil {
+			expiresAt := r[8].(time.Time)
+			entry.OfferExpiresAt = &expiresAt
+		}
+		if r[9] != nil {
+			entry.BookingID = r[9].(string)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetWaitlistPassengers retrieves the passengers of a waitlist entry in the
+// order they were requested.
+func (db Database) GetWaitlistPassengers(entryID string) ([]models.BookingPassengerRequest, error) {
+	stmt, err := db.Prepare(`BEGIN MindenAirport.GetWaitlistPassengers(:1, :2); END;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor driver.Rows
+	_, err = stmt.Exec(entryID, sql.Out{Dest: &cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]driver.Value, len(cursor.Columns()))
+	defer cursor.Close()
+
+	var passengers []models.BookingPassengerRequest
+
+	for {
+		err := cursor.Next(r)
+		if err != nil {
+			break
+		}
+
+		var p models.BookingPassengerRequest
+		// NUMBER(1) may be returned as int64 or godror.Number depending on the driver settings
+		p.Self = fmt.Sprint(r[0]) == "1"
+		if r[1] != nil {
+			p.CompanionID = r[1].(string)
+		}
+		if r[2] != nil {
+			p.FirstName = r[2].(string)
+		}
+		if r[3] != nil {
+			p.LastName = r[3].(string)
+		}
+		if r[4] != nil {
+			birthDate := r[4].(time.Time)
+			p.BirthDate = &birthDate
+		}
+		passengers = append(passengers, p)
+	}
+
+	return passengers, nil
+}
+
+// CreateWaitlistEntry inserts a waiting entry with its passengers in one
+// transaction. The ID and creation time are generated if not set.
+func (db Database) CreateWaitlistEntry(entry *models.WaitlistEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	entry.Seats = len(entry.Passengers)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`BEGIN MindenAirport.CreateWaitlistEntry(:1, :2, :3, :4, :5, :6); END;`,
+		entry.ID, entry.FlightID, entry.AirportUserID, entry.TravelClass, entry.Seats, entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for i, p := range entry.Passengers {
+		self := 0
+		if p.Self {
+			self = 1
+		}
+		var birthDate interface{}
+		if p.BirthDate != nil {
+			birthDate = *p.BirthDate
+		}
+		_, err = tx.Exec(`BEGIN MindenAirport.AddWaitlistPassenger(:1, :2, :3, :4, :5, :6, :7, :8); END;`,
+			uuid.New().String(), entry.ID, i+1, self, p.CompanionID, p.FirstName, p.LastName, birthDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateWaitlistEntry stores the status, offer and booking of a waitlist entry.
+func (db Database) UpdateWaitlistEntry(entry models.WaitlistEntry) error {
+	var offeredAt, expiresAt interface{}
+	if entry.OfferedAt != nil {
+		offeredAt = *entry.OfferedAt
+	}
+	if entry.OfferExpiresAt != nil {
+		expiresAt = *entry.OfferExpiresAt
+	}
+
+	query := `BEGIN MindenAirport.UpdateWaitlistEntry(:1, :2, :3, :4, :5); END;`
+	_, err := db.Exec(query, entry.ID, entry.Status, offeredAt, expiresAt, entry.BookingID)
+	return err
+}
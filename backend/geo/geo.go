@@ -0,0 +1,226 @@
+// Package geo calculates great-circle distances between airports and derives
+// block time and CO2 estimates for flights from them. The estimates use the
+// typical cruise performance of the aircraft catalogue and are meant for
+// passenger information, not for flight planning.
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"mindenairport/aircraft"
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+const (
+	earthRadiusKm = 6371.0088 // Mean earth radius
+	kmPerNM       = 1.852
+	kmPerMile     = 1.609344
+
+	taxiTime         = 20 * time.Minute // Taxi-out and taxi-in
+	climbAndDescent  = 15 * time.Minute // Time lost against cruise speed during climb and approach
+	blockRounding    = 5 * time.Minute
+	kgCO2PerKgFuel   = 3.16 // CO2 emitted by burning one kg of jet fuel
+	seatLoadFactor   = 0.8  // Average share of occupied seats
+	defaultModelName = "Airbus A320"
+)
+
+var (
+	// ErrUnknownAirport is returned for airport IDs that do not exist.
+	ErrUnknownAirport = errors.New("unknown airport")
+	// ErrNoCoordinates is returned for airports without latitude and longitude.
+	ErrNoCoordinates = errors.New("airport has no coordinates")
+)
+
+// DefaultAircraft returns the aircraft type estimates are based on when the
+// aircraft of a flight is not known.
+func DefaultAircraft() aircraft.Type {
+	t, _ := aircraft.Lookup(defaultModelName)
+	return t
+}
+
+// aircraftFor looks up the type of a plane model, falling back to the default aircraft.
+func aircraftFor(model string) aircraft.Type {
+	if t, ok := aircraft.Lookup(model); ok {
+		return t
+	}
+	return DefaultAircraft()
+}
+
+// Haversine returns the great-circle distance in kilometers between two
+// points given in decimal degrees.
+func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// hasCoordinates reports whether the position of an airport is known. The
+// AIRPORT table leaves unknown positions empty, which read as 0/0.
+func hasCoordinates(a models.Airport) bool {
+	return a.Latitude != 0 || a.Longitude != 0
+}
+
+// Distance returns the great-circle distance in kilometers between two airports.
+func Distance(from, to models.Airport) (float64, error) {
+	for _, a := range []models.Airport{from, to} {
+		if !hasCoordinates(a) {
+			return 0, fmt.Errorf("%w: %s", ErrNoCoordinates, a.ID)
+		}
+	}
+	return Haversine(from.Latitude, from.Longitude, to.Latitude, to.Longitude), nil
+}
+
+// airborneTime returns the estimated time in the air for a distance.
+func airborneTime(distanceKm float64, t aircraft.Type) time.Duration {
+	hours := distanceKm / kmPerNM / t.CruiseKt
+	return climbAndDescent + time.Duration(hours*float64(time.Hour))
+}
+
+// BlockTime estimates the gate-to-gate time of a flight over a distance,
+// rounded to five minutes.
+func BlockTime(distanceKm float64, t aircraft.Type) time.Duration {
+	return (taxiTime + airborneTime(distanceKm, t)).Round(blockRounding)
+}
+
+// CO2PerPassenger estimates the CO2 emissions in kg per passenger of a
+// flight over a distance. The fuel burned is spread over the occupied seats
+// at an average load factor; seats of 0 uses the typical seat count of the type.
+func CO2PerPassenger(distanceKm float64, t aircraft.Type, seats int) float64 {
+	if seats <= 0 {
+		seats = t.Seats
+	}
+	fuelKg := t.FuelKgH * airborneTime(distanceKm, t).Hours()
+	perPassenger := fuelKg * kgCO2PerKgFuel / (float64(seats) * seatLoadFactor)
+	return math.Round(perPassenger*10) / 10
+}
+
+// Estimate calculates the route estimate between two airports for an
+// aircraft type with the given number of seats (0 for the typical layout).
+func Estimate(from, to models.Airport, t aircraft.Type, seats int) (models.RouteEstimate, error) {
+	km, err := Distance(from, to)
+	if err != nil {
+		return models.RouteEstimate{}, err
+	}
+
+	return models.RouteEstimate{
+		From:              from.ID,
+		To:                to.ID,
+		DistanceKm:        math.Round(km),
+		DistanceNM:        math.Round(km / kmPerNM),
+		DistanceMiles:     math.Round(km / kmPerMile),
+		Aircraft:          t.Name,
+		BlockMinutes:      int(BlockTime(km, t).Minutes()),
+		CO2PerPassengerKg: CO2PerPassenger(km, t, seats),
+	}, nil
+}
+
+// Estimator calculates route estimates for airport IDs, flights and tickets.
+// It caches airports and planes, so it should only live for one request.
+type Estimator struct {
+	db       database.Database
+	airports map[string]models.Airport
+	planes   map[string]*models.Plane
+}
+
+// NewEstimator creates an estimator reading airports and planes from db.
+func NewEstimator(db database.Database) *Estimator {
+	return &Estimator{
+		db:       db,
+		airports: make(map[string]models.Airport),
+		planes:   make(map[string]*models.Plane),
+	}
+}
+
+// airport returns the airport with the given ID.
+func (e *Estimator) airport(id string) (models.Airport, error) {
+	if a, ok := e.airports[id]; ok {
+		return a, nil
+	}
+	a := e.db.GetAirportByID(id)
+	if a.ID == "" {
+		return a, fmt.Errorf("%w: %s", ErrUnknownAirport, id)
+	}
+	e.airports[id] = a
+	return a, nil
+}
+
+// plane returns the plane with the given ID, or nil if it does not exist.
+func (e *Estimator) plane(id string) (*models.Plane, error) {
+	if p, ok := e.planes[id]; ok {
+		return p, nil
+	}
+	p, err := e.db.GetPlaneByID(id)
+	if err != nil {
+		return nil, err
+	}
+	e.planes[id] = p
+	return p, nil
+}
+
+// Route calculates the estimate between two airport IDs for an aircraft type
+// with the given number of seats (0 for the typical layout).
+func (e *Estimator) Route(fromID, toID string, t aircraft.Type, seats int) (models.RouteEstimate, error) {
+	from, err := e.airport(fromID)
+	if err != nil {
+		return models.RouteEstimate{}, err
+	}
+	to, err := e.airport(toID)
+	if err != nil {
+		return models.RouteEstimate{}, err
+	}
+	return Estimate(from, to, t, seats)
+}
+
+// Flight sets the distance and CO2 estimate of a flight based on its plane.
+// Routes between airports without coordinates are left without estimate.
+func (e *Estimator) Flight(f *models.Flight) error {
+	t, seats := DefaultAircraft(), 0
+	if f.PlaneID != "" {
+		p, err := e.plane(f.PlaneID)
+		if err != nil {
+			return err
+		}
+		if p != nil {
+			t, seats = aircraftFor(p.Model), p.Seats
+		}
+	}
+
+	estimate, err := e.Route(f.From, f.To, t, seats)
+	if errors.Is(err, ErrNoCoordinates) || errors.Is(err, ErrUnknownAirport) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f.DistanceKm = estimate.DistanceKm
+	f.CO2PerPassengerKg = estimate.CO2PerPassengerKg
+	return nil
+}
+
+// Ticket sets the distance and CO2 estimate of a ticket based on the model
+// of the plane operating its flight.
+// Routes between airports without coordinates are left without estimate.
+func (e *Estimator) Ticket(t *models.Ticket) error {
+	if t.From == "" || t.To == "" {
+		return nil
+	}
+
+	estimate, err := e.Route(t.From, t.To, aircraftFor(t.Aircraft), 0)
+	if errors.Is(err, ErrNoCoordinates) || errors.Is(err, ErrUnknownAirport) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	t.DistanceKm = estimate.DistanceKm
+	t.CO2PerPassengerKg = estimate.CO2PerPassengerKg
+	return nil
+}
@@ -0,0 +1,92 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"mindenairport/models"
+)
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"same point", 52.3086, 4.7639, 52.3086, 4.7639, 0},
+		{"FRA to JFK", 50.0333, 8.5706, 40.6398, -73.7789, 6189.45},
+		{"AMS to LHR", 52.3086, 4.7639, 51.4706, -0.4619, 370.45},
+		{"across the antimeridian", 0, 179, 0, -179, 222.39},
+		{"antipodes on the equator", 0, 0, 0, 180, math.Pi * earthRadiusKm},
+		{"pole to pole", 90, 0, -90, 0, math.Pi * earthRadiusKm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Haversine(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Haversine() = %.2f, want %.2f", got, tt.want)
+			}
+			if back := Haversine(tt.lat2, tt.lon2, tt.lat1, tt.lon1); math.Abs(back-got) > 1e-9 {
+				t.Errorf("Haversine() is not symmetric: %.6f and %.6f", got, back)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	fra := models.Airport{ID: "FRA", Latitude: 50.0333, Longitude: 8.5706}
+	jfk := models.Airport{ID: "JFK", Latitude: 40.6398, Longitude: -73.7789}
+	equator := models.Airport{ID: "EQU", Latitude: 0, Longitude: 10}
+
+	tests := []struct {
+		name     string
+		from, to models.Airport
+		want     float64
+		wantErr  error
+	}{
+		{"both known", fra, jfk, 6189.45, nil},
+		{"zero latitude is a known position", equator, equator, 0, nil},
+		{"origin without coordinates", models.Airport{ID: "XXX"}, jfk, 0, ErrNoCoordinates},
+		{"destination without coordinates", fra, models.Airport{ID: "XXX"}, 0, ErrNoCoordinates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Distance(tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Distance() error = %v, want %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Distance() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockTimeRoundsToFiveMinutes(t *testing.T) {
+	a320 := DefaultAircraft()
+	for _, km := range []float64{0, 1, 370.45, 6189.45} {
+		got := BlockTime(km, a320)
+		if got%blockRounding != 0 {
+			t.Errorf("BlockTime(%.2f) = %v, not a multiple of %v", km, got, blockRounding)
+		}
+		if got < taxiTime+climbAndDescent {
+			t.Errorf("BlockTime(%.2f) = %v, shorter than taxi, climb and descent", km, got)
+		}
+	}
+}
+
+func TestCO2PerPassengerUsesTypicalSeats(t *testing.T) {
+	a320 := DefaultAircraft()
+	typical := CO2PerPassenger(1000, a320, a320.Seats)
+	for _, seats := range []int{0, -1} {
+		if got := CO2PerPassenger(1000, a320, seats); got != typical {
+			t.Errorf("CO2PerPassenger(seats=%d) = %v, want %v", seats, got, typical)
+		}
+	}
+	if dense := CO2PerPassenger(1000, a320, a320.Seats*2); dense >= typical {
+		t.Errorf("CO2PerPassenger with more seats = %v, want less than %v", dense, typical)
+	}
+}
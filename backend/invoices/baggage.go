@@ -0,0 +1,183 @@
+package invoices
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"mindenairport/models"
+)
+
+const (
+	reasonOverweight = "OVERWEIGHT"
+	reasonOversize   = "OVERSIZE"
+
+	// sizeOversized is the size category of oversized bags.
+	sizeOversized = 3
+)
+
+var (
+	// ErrWithinAllowance is returned when an excess baggage fee is charged
+	// for a bag that is neither too heavy nor oversized.
+	ErrWithinAllowance = errors.New("bag is within the baggage allowance")
+	// ErrFeeCharged is returned when the excess baggage fee of a bag was
+	// already charged.
+	ErrFeeCharged = errors.New("excess baggage fee already charged")
+)
+
+// ExcessReason returns why a bag is charged an excess baggage fee:
+// OVERSIZE for oversized bags, OVERWEIGHT for bags heavier than the
+// allowance, or "" if the bag is within the allowance.
+func (s *Service) ExcessReason(bag models.Baggage) string {
+	switch {
+	case bag.Size == sizeOversized:
+		return reasonOversize
+	case bag.Weight > s.BaggageAllowance:
+		return reasonOverweight
+	}
+	return ""
+}
+
+// ValidateBaggageFee normalizes the payment of an excess baggage fee and
+// checks it. It returns a message describing the first problem, or "".
+func ValidateBaggageFee(req *models.BaggageFeeRequest) string {
+	req.PaymentMethod = strings.ToUpper(strings.TrimSpace(req.PaymentMethod))
+	req.CardLast4 = strings.TrimSpace(req.CardLast4)
+
+	if req.Amount != nil && *req.Amount <= 0 {
+		return "amount must be positive"
+	}
+	switch req.PaymentMethod {
+	case methodCard:
+		if len(req.CardLast4) != 4 || strings.Trim(req.CardLast4, "0123456789") != "" {
+			return "cardLast4 must be the last four digits of the card"
+		}
+	case methodCash:
+		if req.CardLast4 != "" {
+			return "cardLast4 is only allowed for card payments"
+		}
+	default:
+		return "paymentMethod must be CARD or CASH"
+	}
+	return ""
+}
+
+// ChargeExcessBaggage records the excess baggage fee a passenger paid at
+// the counter for a bag and issues its invoice. The amount defaults to the
+// configured fee. If the invoice cannot be issued right away, the error is
+// logged and the invoice is issued by the periodic check; the returned
+// invoice is nil then. staffID is the staff member charging the fee.
+func (s *Service) ChargeExcessBaggage(bag *models.Baggage, req models.BaggageFeeRequest, staffID string, now time.Time) (*models.BaggageFee, *models.Invoice, error) {
+	reason := s.ExcessReason(*bag)
+	if reason == "" {
+		return nil, nil, fmt.Errorf("%w of %.0f lb", ErrWithinAllowance, s.BaggageAllowance)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.db.GetBaggageFeeByBaggage(bag.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil {
+		return nil, nil, ErrFeeCharged
+	}
+
+	fee := &models.BaggageFee{
+		BaggageID:      bag.ID,
+		TrackingNumber: bag.TrackingNumber,
+		AirportUserID:  bag.AirportUserID,
+		FlightID:       bag.FlightID,
+		Reason:         reason,
+		Weight:         bag.Weight,
+		Amount:         s.ExcessBaggageFee,
+		Currency:       Currency,
+		PaymentMethod:  req.PaymentMethod,
+		CardLast4:      req.CardLast4,
+		PaidAt:         now.UTC(),
+		CreatedBy:      staffID,
+	}
+	if req.Amount != nil {
+		fee.Amount = round(*req.Amount)
+	}
+	if err := s.db.CreateBaggageFee(fee); err != nil {
+		return nil, nil, err
+	}
+
+	invoice, err := s.issueBaggageFee(fee, now)
+	if err != nil {
+		log.Printf("Error issuing invoice for excess baggage fee %s: %v", fee.ID, err)
+		return fee, nil, nil
+	}
+	return fee, invoice, nil
+}
+
+// IssueForBaggageFee issues the invoice of an excess baggage fee. It
+// returns the invoice already issued for the fee, if any.
+func (s *Service) IssueForBaggageFee(feeID string, now time.Time) (*models.Invoice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fee, err := s.db.GetBaggageFeeByID(feeID)
+	if err != nil {
+		return nil, err
+	}
+	if fee == nil {
+		return nil, fmt.Errorf("excess baggage fee %s not found", feeID)
+	}
+	return s.issueBaggageFee(fee, now)
+}
+
+// issueBaggageFee issues the invoice of an excess baggage fee, taxed like
+// the flight the bag travels on. The caller must hold s.mu.
+func (s *Service) issueBaggageFee(fee *models.BaggageFee, now time.Time) (*models.Invoice, error) {
+	invoices, err := s.db.GetInvoicesBySource("", "", fee.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(invoices) - 1; i >= 0; i-- {
+		if invoices[i].Type == TypeInvoice {
+			return &invoices[i], s.load(&invoices[i])
+		}
+	}
+
+	c := s.newLookup()
+	flight, err := c.flight(fee.FlightID)
+	if err != nil {
+		return nil, err
+	}
+	category, rate := s.category(c.airport(flight.From), c.airport(flight.To), c.airport(s.HomeAirport))
+
+	kind := "overweight"
+	if fee.Reason == reasonOversize {
+		kind = "oversized"
+	}
+	invoice := &models.Invoice{
+		Type:          TypeInvoice,
+		AirportUserID: fee.AirportUserID,
+		BaggageFeeID:  fee.ID,
+		ServiceDate:   s.date(fee.PaidAt),
+		Currency:      fee.Currency,
+		PaymentMethod: fee.PaymentMethod,
+		CardLast4:     fee.CardLast4,
+		CreatedBy:     fee.CreatedBy,
+		Lines: []models.InvoiceLine{{
+			Description: fmt.Sprintf("Excess baggage %s, %s bag of %.1f lb, %s",
+				fee.TrackingNumber, kind, fee.Weight, s.flightDescription(flight)),
+			Quantity:     1,
+			GrossAmount:  fee.Amount,
+			VATCategory:  category,
+			VATRate:      rate,
+			BaggageFeeID: fee.ID,
+		}},
+	}
+	SplitVAT(invoice.Lines)
+
+	if err := s.issue(invoice, now); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
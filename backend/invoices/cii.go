@@ -0,0 +1,435 @@
+package invoices
+
+import (
+	"encoding/xml"
+	"errors"
+	"strconv"
+	"time"
+
+	"mindenairport/models"
+)
+
+const (
+	// ProfileXRechnung is the CII profile of XRechnung, the German standard
+	// for invoices to public buyers.
+	ProfileXRechnung = "xrechnung"
+	// ProfileZUGFeRD is the EN 16931 profile of ZUGFeRD/Factur-X.
+	ProfileZUGFeRD = "zugferd"
+
+	guidelineXRechnung = "urn:cen.eu:en16931:2017#compliant#urn:xeinkauf.de:kosit:xrechnung_3.0"
+	guidelineZUGFeRD   = "urn:cen.eu:en16931:2017"
+	businessProcess    = "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0"
+
+	typeCodeInvoice    = "380"
+	typeCodeCreditNote = "381"
+	meansCard          = "48"
+	meansCash          = "10"
+	dateFormat         = "102" // YYYYMMDD
+	unitPiece          = "C62"
+)
+
+var (
+	// ErrUnknownProfile is returned for an XML profile other than xrechnung
+	// or zugferd.
+	ErrUnknownProfile = errors.New("profile must be xrechnung or zugferd")
+	// ErrIncompleteAddress is returned when an XRechnung is requested for an
+	// invoice without postcode, city and country of the buyer.
+	ErrIncompleteAddress = errors.New("XRechnung requires a billing address with postcode, city and country")
+)
+
+// XML renders an invoice or credit note, loaded with its lines, as UN/CEFACT
+// Cross Industry Invoice in the given profile, for buyers that process
+// invoices electronically.
+func (s *Service) XML(invoice *models.Invoice, profile string) ([]byte, error) {
+	guideline := ""
+	switch profile {
+	case ProfileXRechnung:
+		guideline = guidelineXRechnung
+		buyer := invoice.Buyer
+		if buyer.Postcode == "" || buyer.City == "" || buyer.Country == "" {
+			return nil, ErrIncompleteAddress
+		}
+	case ProfileZUGFeRD:
+		guideline = guidelineZUGFeRD
+	default:
+		return nil, ErrUnknownProfile
+	}
+
+	issuer, err := s.db.GetInvoiceIssuerByID(invoice.IssuerID)
+	if err != nil {
+		return nil, err
+	}
+	if issuer == nil {
+		return nil, ErrNoIssuer
+	}
+	return s.renderXML(invoice, issuer, guideline)
+}
+
+// renderXML writes the Cross Industry Invoice of an invoice issued by issuer
+// for the specification identifier guideline.
+func (s *Service) renderXML(invoice *models.Invoice, issuer *models.InvoiceIssuer, guideline string) ([]byte, error) {
+	doc := ciiInvoice{
+		XmlnsRsm: "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100",
+		XmlnsRam: "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100",
+		XmlnsQdt: "urn:un:unece:uncefact:data:standard:QualifiedDataType:100",
+		XmlnsUdt: "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100",
+		Context: ciiContext{
+			BusinessProcess: ciiID{ID: businessProcess},
+			Guideline:       ciiID{ID: guideline},
+		},
+		Document: ciiDocument{
+			ID:        invoice.Number,
+			TypeCode:  typeCodeInvoice,
+			IssueDate: ciiDate(invoice.IssuedAt.In(s.Location)),
+		},
+		Transaction: ciiTransaction{
+			Agreement: s.agreement(invoice, issuer),
+			Delivery: ciiDelivery{
+				Event: ciiEvent{Date: ciiDate(invoice.ServiceDate)},
+			},
+			Settlement: s.settlement(invoice),
+		},
+	}
+	if invoice.Type == TypeCreditNote {
+		doc.Document.TypeCode = typeCodeCreditNote
+	}
+	if invoice.Note != "" {
+		doc.Document.Notes = []ciiNote{{Content: invoice.Note}}
+	}
+	for _, line := range invoice.Lines {
+		doc.Transaction.Lines = append(doc.Transaction.Lines, ciiLine{
+			Document: ciiLineDocument{LineID: strconv.Itoa(line.LineNo)},
+			Product:  ciiProduct{Name: line.Description},
+			Agreement: ciiLineAgreement{
+				NetPrice: ciiPrice{Amount: ciiAmount(round(line.NetAmount / line.Quantity))},
+			},
+			Delivery: ciiLineDelivery{
+				Quantity: ciiQuantity{UnitCode: unitPiece, Value: strconv.FormatFloat(line.Quantity, 'f', -1, 64)},
+			},
+			Settlement: ciiLineSettlement{
+				Tax: ciiTax{
+					TypeCode:     "VAT",
+					CategoryCode: line.VATCategory,
+					Rate:         strconv.FormatFloat(line.VATRate, 'f', -1, 64),
+				},
+				Summation: ciiLineSummation{LineTotal: ciiAmount(line.NetAmount)},
+			},
+		})
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// agreement describes seller and buyer of an invoice.
+func (s *Service) agreement(invoice *models.Invoice, issuer *models.InvoiceIssuer) ciiAgreement {
+	buyer := invoice.Buyer
+	reference := buyer.BuyerReference
+	if reference == "" {
+		reference = invoice.Locator
+	}
+	if reference == "" {
+		reference = invoice.Number
+	}
+
+	seller := ciiParty{
+		Name: issuer.Name,
+		Contact: &ciiContact{
+			PersonName: issuer.ContactName,
+			Phone:      &ciiPhone{Number: issuer.Phone},
+			Email:      &ciiEmail{URIID: issuer.Email},
+		},
+		Address: ciiAddress{
+			Postcode: issuer.Postcode,
+			Line:     issuer.Street,
+			City:     issuer.City,
+			Country:  issuer.Country,
+		},
+		Communication: &ciiCommunication{URIID: ciiSchemeID{SchemeID: "EM", Value: issuer.Email}},
+	}
+	if issuer.VATID != "" {
+		seller.Tax = append(seller.Tax, ciiTaxRegistration{ID: ciiSchemeID{SchemeID: "VA", Value: issuer.VATID}})
+	}
+	if issuer.TaxNumber != "" {
+		seller.Tax = append(seller.Tax, ciiTaxRegistration{ID: ciiSchemeID{SchemeID: "FC", Value: issuer.TaxNumber}})
+	}
+
+	name := buyer.Company
+	if name == "" {
+		name = buyer.Name
+	}
+	party := ciiParty{
+		Name: name,
+		Address: ciiAddress{
+			Postcode: buyer.Postcode,
+			Line:     buyer.Street,
+			City:     buyer.City,
+			Country:  buyer.Country,
+		},
+	}
+	if buyer.Company != "" && buyer.Name != "" {
+		party.Contact = &ciiContact{PersonName: buyer.Name}
+	}
+	if buyer.Email != "" {
+		party.Communication = &ciiCommunication{URIID: ciiSchemeID{SchemeID: "EM", Value: buyer.Email}}
+	}
+	if buyer.VATID != "" {
+		party.Tax = append(party.Tax, ciiTaxRegistration{ID: ciiSchemeID{SchemeID: "VA", Value: buyer.VATID}})
+	}
+
+	return ciiAgreement{BuyerReference: reference, Seller: seller, Buyer: party}
+}
+
+// settlement describes payment, VAT breakdown and totals of an invoice.
+// Invoices are paid when they are issued, so nothing is due.
+func (s *Service) settlement(invoice *models.Invoice) ciiSettlement {
+	settlement := ciiSettlement{
+		Currency: invoice.Currency,
+		Means:    ciiMeans{TypeCode: meansCash},
+		Terms:    ciiTerms{Description: "Paid in cash"},
+		Summation: ciiSummation{
+			LineTotal:     ciiAmount(invoice.NetAmount),
+			TaxBasisTotal: ciiAmount(invoice.NetAmount),
+			TaxTotal:      ciiCurrencyAmount{Currency: invoice.Currency, Value: ciiAmount(invoice.VATAmount)},
+			GrandTotal:    ciiAmount(invoice.GrossAmount),
+			TotalPrepaid:  ciiAmount(invoice.GrossAmount),
+			DuePayable:    ciiAmount(0),
+		},
+	}
+	if invoice.PaymentMethod == methodCard {
+		settlement.Means = ciiMeans{TypeCode: meansCard, Card: &ciiCard{ID: invoice.CardLast4}}
+		settlement.Terms.Description = "Paid by card ending in " + invoice.CardLast4
+	}
+	if invoice.Type == TypeCreditNote {
+		settlement.Terms.Description = "Refunded"
+		if invoice.PaymentMethod == methodCard {
+			settlement.Terms.Description = "Refunded to the card ending in " + invoice.CardLast4
+		}
+	}
+
+	for _, entry := range invoice.VATBreakdown {
+		settlement.Taxes = append(settlement.Taxes, ciiTax{
+			Calculated:      ciiAmount(entry.VATAmount),
+			TypeCode:        "VAT",
+			ExemptionReason: entry.ExemptionReason,
+			Basis:           ciiAmount(entry.NetAmount),
+			CategoryCode:    entry.Category,
+			Rate:            strconv.FormatFloat(entry.Rate, 'f', -1, 64),
+		})
+	}
+
+	if invoice.CorrectsNumber != "" {
+		settlement.Referenced = &ciiReferencedDocument{ID: invoice.CorrectsNumber}
+	}
+	return settlement
+}
+
+// ciiDate formats a date as CII date time string.
+func ciiDate(t time.Time) ciiDateTime {
+	return ciiDateTime{Value: ciiDateString{Format: dateFormat, Value: t.Format("20060102")}}
+}
+
+// ciiAmount formats an amount with two decimals.
+func ciiAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// The elements of a Cross Industry Invoice. The namespace prefixes are part
+// of the element names, since encoding/xml would otherwise declare the
+// namespace on every element.
+type ciiInvoice struct {
+	XMLName     xml.Name       `xml:"rsm:CrossIndustryInvoice"`
+	XmlnsRsm    string         `xml:"xmlns:rsm,attr"`
+	XmlnsRam    string         `xml:"xmlns:ram,attr"`
+	XmlnsQdt    string         `xml:"xmlns:qdt,attr"`
+	XmlnsUdt    string         `xml:"xmlns:udt,attr"`
+	Context     ciiContext     `xml:"rsm:ExchangedDocumentContext"`
+	Document    ciiDocument    `xml:"rsm:ExchangedDocument"`
+	Transaction ciiTransaction `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type ciiContext struct {
+	BusinessProcess ciiID `xml:"ram:BusinessProcessSpecifiedDocumentContextParameter"`
+	Guideline       ciiID `xml:"ram:GuidelineSpecifiedDocumentContextParameter"`
+}
+
+type ciiID struct {
+	ID string `xml:"ram:ID"`
+}
+
+type ciiDocument struct {
+	ID        string      `xml:"ram:ID"`
+	TypeCode  string      `xml:"ram:TypeCode"`
+	IssueDate ciiDateTime `xml:"ram:IssueDateTime"`
+	Notes     []ciiNote   `xml:"ram:IncludedNote"`
+}
+
+type ciiNote struct {
+	Content string `xml:"ram:Content"`
+}
+
+type ciiDateTime struct {
+	Value ciiDateString `xml:"udt:DateTimeString"`
+}
+
+type ciiDateString struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiTransaction struct {
+	Lines      []ciiLine     `xml:"ram:IncludedSupplyChainTradeLineItem"`
+	Agreement  ciiAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   ciiDelivery   `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement ciiSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type ciiLine struct {
+	Document   ciiLineDocument   `xml:"ram:AssociatedDocumentLineDocument"`
+	Product    ciiProduct        `xml:"ram:SpecifiedTradeProduct"`
+	Agreement  ciiLineAgreement  `xml:"ram:SpecifiedLineTradeAgreement"`
+	Delivery   ciiLineDelivery   `xml:"ram:SpecifiedLineTradeDelivery"`
+	Settlement ciiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type ciiLineDocument struct {
+	LineID string `xml:"ram:LineID"`
+}
+
+type ciiProduct struct {
+	Name string `xml:"ram:Name"`
+}
+
+type ciiLineAgreement struct {
+	NetPrice ciiPrice `xml:"ram:NetPriceProductTradePrice"`
+}
+
+type ciiPrice struct {
+	Amount string `xml:"ram:ChargeAmount"`
+}
+
+type ciiLineDelivery struct {
+	Quantity ciiQuantity `xml:"ram:BilledQuantity"`
+}
+
+type ciiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiLineSettlement struct {
+	Tax       ciiTax           `xml:"ram:ApplicableTradeTax"`
+	Summation ciiLineSummation `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation"`
+}
+
+type ciiLineSummation struct {
+	LineTotal string `xml:"ram:LineTotalAmount"`
+}
+
+type ciiAgreement struct {
+	BuyerReference string   `xml:"ram:BuyerReference"`
+	Seller         ciiParty `xml:"ram:SellerTradeParty"`
+	Buyer          ciiParty `xml:"ram:BuyerTradeParty"`
+}
+
+type ciiParty struct {
+	Name          string               `xml:"ram:Name"`
+	Contact       *ciiContact          `xml:"ram:DefinedTradeContact"`
+	Address       ciiAddress           `xml:"ram:PostalTradeAddress"`
+	Communication *ciiCommunication    `xml:"ram:URIUniversalCommunication"`
+	Tax           []ciiTaxRegistration `xml:"ram:SpecifiedTaxRegistration"`
+}
+
+type ciiContact struct {
+	PersonName string    `xml:"ram:PersonName"`
+	Phone      *ciiPhone `xml:"ram:TelephoneUniversalCommunication"`
+	Email      *ciiEmail `xml:"ram:EmailURIUniversalCommunication"`
+}
+
+type ciiPhone struct {
+	Number string `xml:"ram:CompleteNumber"`
+}
+
+type ciiEmail struct {
+	URIID string `xml:"ram:URIID"`
+}
+
+type ciiAddress struct {
+	Postcode string `xml:"ram:PostcodeCode,omitempty"`
+	Line     string `xml:"ram:LineOne,omitempty"`
+	City     string `xml:"ram:CityName,omitempty"`
+	Country  string `xml:"ram:CountryID,omitempty"`
+}
+
+type ciiCommunication struct {
+	URIID ciiSchemeID `xml:"ram:URIID"`
+}
+
+type ciiTaxRegistration struct {
+	ID ciiSchemeID `xml:"ram:ID"`
+}
+
+type ciiSchemeID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiDelivery struct {
+	Event ciiEvent `xml:"ram:ActualDeliverySupplyChainEvent"`
+}
+
+type ciiEvent struct {
+	Date ciiDateTime `xml:"ram:OccurrenceDateTime"`
+}
+
+type ciiSettlement struct {
+	Currency   string                 `xml:"ram:InvoiceCurrencyCode"`
+	Means      ciiMeans               `xml:"ram:SpecifiedTradeSettlementPaymentMeans"`
+	Taxes      []ciiTax               `xml:"ram:ApplicableTradeTax"`
+	Terms      ciiTerms               `xml:"ram:SpecifiedTradePaymentTerms"`
+	Summation  ciiSummation           `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+	Referenced *ciiReferencedDocument `xml:"ram:InvoiceReferencedDocument"`
+}
+
+type ciiMeans struct {
+	TypeCode string   `xml:"ram:TypeCode"`
+	Card     *ciiCard `xml:"ram:ApplicableTradeSettlementFinancialCard"`
+}
+
+type ciiCard struct {
+	ID string `xml:"ram:ID"`
+}
+
+type ciiTax struct {
+	Calculated      string `xml:"ram:CalculatedAmount,omitempty"`
+	TypeCode        string `xml:"ram:TypeCode"`
+	ExemptionReason string `xml:"ram:ExemptionReason,omitempty"`
+	Basis           string `xml:"ram:BasisAmount,omitempty"`
+	CategoryCode    string `xml:"ram:CategoryCode"`
+	Rate            string `xml:"ram:RateApplicablePercent"`
+}
+
+type ciiTerms struct {
+	Description string `xml:"ram:Description"`
+}
+
+type ciiSummation struct {
+	LineTotal     string            `xml:"ram:LineTotalAmount"`
+	TaxBasisTotal string            `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotal      ciiCurrencyAmount `xml:"ram:TaxTotalAmount"`
+	GrandTotal    string            `xml:"ram:GrandTotalAmount"`
+	TotalPrepaid  string            `xml:"ram:TotalPrepaidAmount"`
+	DuePayable    string            `xml:"ram:DuePayableAmount"`
+}
+
+type ciiCurrencyAmount struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiReferencedDocument struct {
+	ID string `xml:"ram:IssuerAssignedID"`
+}
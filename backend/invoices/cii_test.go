@@ -0,0 +1,205 @@
+package invoices
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"mindenairport/models"
+)
+
+func testIssuer() *models.InvoiceIssuer {
+	return &models.InvoiceIssuer{
+		ID: "issuer", Name: "Flughafen Minden GmbH", Street: "Flughafenstraße 1", Postcode: "32423",
+		City: "Minden", Country: "DE", VATID: "DE123456789", ContactName: "Buchhaltung",
+		Email: "rechnung@minden-airport.example", Phone: "+49 571 0000",
+	}
+}
+
+func testInvoice() *models.Invoice {
+	lines := []models.InvoiceLine{
+		{LineNo: 1, Description: "Ticket MIN-MUC", Quantity: 2, VATCategory: CategoryStandard, VATRate: 19, GrossAmount: 238},
+		{LineNo: 2, Description: "Ticket MIN-VIE <economy & more>", Quantity: 1, VATCategory: CategoryExempt, GrossAmount: 150.5},
+	}
+	SplitVAT(lines)
+
+	invoice := &models.Invoice{
+		Number: "MA-2025-000042", Type: TypeInvoice, IssuerID: "issuer", Locator: "ABC123",
+		IssuedAt:    time.Date(2025, 12, 31, 23, 30, 0, 0, time.UTC),
+		ServiceDate: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		Currency:    "EUR", PaymentMethod: methodCard, CardLast4: "4242",
+		Buyer: models.BillingAddress{Name: "Erika Mustermann", Street: "Hauptstraße 5",
+			Postcode: "32423", City: "Minden", Country: "DE"},
+		Lines: lines,
+	}
+	invoice.VATBreakdown = Breakdown(lines)
+	for _, entry := range invoice.VATBreakdown {
+		invoice.NetAmount += entry.NetAmount
+		invoice.VATAmount += entry.VATAmount
+		invoice.GrossAmount += entry.GrossAmount
+	}
+	return invoice
+}
+
+func testService(t *testing.T) *Service {
+	t.Helper()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available:", err)
+	}
+	return &Service{Location: berlin, VATRate: 19}
+}
+
+// checkWellFormed fails the test if the document is not well-formed XML.
+func checkWellFormed(t *testing.T, doc []byte) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(string(doc)))
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("document is not well-formed: %v\n%s", err, doc)
+		}
+	}
+}
+
+func TestXMLRejectsInvalidRequests(t *testing.T) {
+	s := testService(t)
+	tests := []struct {
+		name    string
+		profile string
+		modify  func(b *models.BillingAddress)
+		wantErr error
+	}{
+		{"unknown profile", "ubl", func(b *models.BillingAddress) {}, ErrUnknownProfile},
+		{"empty profile", "", func(b *models.BillingAddress) {}, ErrUnknownProfile},
+		{"profile is case sensitive", "XRECHNUNG", func(b *models.BillingAddress) {}, ErrUnknownProfile},
+		{"XRechnung without postcode", ProfileXRechnung, func(b *models.BillingAddress) { b.Postcode = "" }, ErrIncompleteAddress},
+		{"XRechnung without city", ProfileXRechnung, func(b *models.BillingAddress) { b.City = "" }, ErrIncompleteAddress},
+		{"XRechnung without country", ProfileXRechnung, func(b *models.BillingAddress) { b.Country = "" }, ErrIncompleteAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := testInvoice()
+			tt.modify(&invoice.Buyer)
+			if _, err := s.XML(invoice, tt.profile); !errors.Is(err, tt.wantErr) {
+				t.Errorf("XML() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderXMLInvoice(t *testing.T) {
+	s := testService(t)
+	invoice := testInvoice()
+
+	doc, err := s.renderXML(invoice, testIssuer(), guidelineXRechnung)
+	if err != nil {
+		t.Fatalf("renderXML() error = %v", err)
+	}
+	checkWellFormed(t, doc)
+
+	content := string(doc)
+	for _, want := range []string{
+		`<ram:ID>` + guidelineXRechnung + `</ram:ID>`,
+		`<ram:ID>MA-2025-000042</ram:ID>`,
+		`<ram:TypeCode>380</ram:TypeCode>`,
+		// Issued on New Year's Eve in UTC, which is already January 1st in Minden
+		`<udt:DateTimeString format="102">20260101</udt:DateTimeString>`,
+		`<ram:BuyerReference>ABC123</ram:BuyerReference>`,
+		`<ram:Name>Ticket MIN-VIE &lt;economy &amp; more&gt;</ram:Name>`,
+		`<ram:BilledQuantity unitCode="C62">2</ram:BilledQuantity>`,
+		`<ram:ChargeAmount>100.00</ram:ChargeAmount>`, // Net price per ticket
+		`<ram:LineTotalAmount>200.00</ram:LineTotalAmount>`,
+		`<ram:ID schemeID="VA">DE123456789</ram:ID>`,
+		`<ram:TypeCode>48</ram:TypeCode>`,
+		`<ram:ID>4242</ram:ID>`,
+		`<ram:Description>Paid by card ending in 4242</ram:Description>`,
+		`<ram:ExemptionReason>` + ExemptionInternational + `</ram:ExemptionReason>`,
+		`<ram:TaxTotalAmount currencyID="EUR">38.00</ram:TaxTotalAmount>`,
+		`<ram:GrandTotalAmount>388.50</ram:GrandTotalAmount>`,
+		`<ram:DuePayableAmount>0.00</ram:DuePayableAmount>`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("%s missing in\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "InvoiceReferencedDocument") {
+		t.Error("invoice refers to a corrected invoice")
+	}
+}
+
+func TestRenderXMLCreditNote(t *testing.T) {
+	s := testService(t)
+	invoice := testInvoice()
+	invoice.Type = TypeCreditNote
+	invoice.CorrectsNumber = "MA-2025-000041"
+	invoice.PaymentMethod = methodCash
+	invoice.CardLast4 = ""
+	invoice.Buyer.Company = "Muster AG"
+	invoice.Buyer.BuyerReference = "04011000-12345-67"
+
+	doc, err := s.renderXML(invoice, testIssuer(), guidelineZUGFeRD)
+	if err != nil {
+		t.Fatalf("renderXML() error = %v", err)
+	}
+	checkWellFormed(t, doc)
+
+	content := string(doc)
+	for _, want := range []string{
+		`<ram:TypeCode>381</ram:TypeCode>`,
+		`<ram:BuyerReference>04011000-12345-67</ram:BuyerReference>`,
+		`<ram:Name>Muster AG</ram:Name>`,
+		`<ram:PersonName>Erika Mustermann</ram:PersonName>`,
+		`<ram:TypeCode>10</ram:TypeCode>`,
+		`<ram:Description>Refunded</ram:Description>`,
+		`<ram:IssuerAssignedID>MA-2025-000041</ram:IssuerAssignedID>`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("%s missing in\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "ApplicableTradeSettlementFinancialCard") {
+		t.Error("cash credit note names a card")
+	}
+}
+
+func TestAgreementBuyerReference(t *testing.T) {
+	s := testService(t)
+	tests := []struct {
+		name      string
+		reference string
+		locator   string
+		want      string
+	}{
+		{"explicit reference", "991-01234-56", "ABC123", "991-01234-56"},
+		{"booking locator", "", "ABC123", "ABC123"},
+		{"invoice number", "", "", "MA-2025-000042"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := testInvoice()
+			invoice.Buyer.BuyerReference = tt.reference
+			invoice.Locator = tt.locator
+			if got := s.agreement(invoice, testIssuer()).BuyerReference; got != tt.want {
+				t.Errorf("BuyerReference = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCIIAmount(t *testing.T) {
+	tests := map[float64]string{0: "0.00", 8.4: "8.40", -8.41: "-8.41", 1234567.891: "1234567.89", 0.005: "0.01"}
+	for amount, want := range tests {
+		if got := ciiAmount(amount); got != want {
+			t.Errorf("ciiAmount(%v) = %q, want %q", amount, got, want)
+		}
+	}
+}
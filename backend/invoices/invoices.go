@@ -0,0 +1,742 @@
+// Package invoices issues numbered invoices for paid bookings and excess
+// baggage fees, and credit notes for refunds. Numbers are gap-free per
+// calendar year; every invoice carries a VAT breakdown and the legal
+// details of the airport at the time of issue, and can be downloaded as
+// PDF or as XRechnung or ZUGFeRD XML. Issued invoices are never changed:
+// they are corrected by credit notes and, if needed, issued again.
+package invoices
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+const (
+	// TypeInvoice is the type of invoices.
+	TypeInvoice = "INVOICE"
+	// TypeCreditNote is the type of credit notes correcting an invoice.
+	TypeCreditNote = "CREDIT_NOTE"
+
+	// Currency of all invoices.
+	Currency = "EUR"
+
+	methodCard = "CARD"
+	methodCash = "CASH"
+
+	sourcePayment    = "PAYMENT"
+	sourceRefund     = "REFUND"
+	sourceBaggageFee = "BAGGAGE_FEE"
+
+	refundSucceeded = "SUCCEEDED"
+)
+
+var (
+	// ErrNoIssuer is returned when the legal details of the airport are not
+	// set up, so no invoice can be issued.
+	ErrNoIssuer = errors.New("legal details of the airport are not set up")
+	// ErrNotInvoiceable is returned for payments that are not captured and
+	// refunds that did not succeed.
+	ErrNotInvoiceable = errors.New("cannot be invoiced")
+	// ErrNotCreditable is returned for credit notes and for invoices that
+	// are fully credited.
+	ErrNotCreditable = errors.New("cannot be credited")
+)
+
+// Service issues invoices and credit notes.
+type Service struct {
+	db database.Database
+
+	HomeAirport      string         // IATA code of this airport; flights within its country are taxed
+	Location         *time.Location // Time zone of the airport, for invoice years and dates
+	NumberPrefix     string         // Prefix of invoice numbers, e.g. "MA" for "MA-2025-000042"
+	VATRate          float64        // Standard VAT rate in percent
+	BaggageAllowance float64        // Heaviest bag in pounds carried without an excess baggage fee
+	ExcessBaggageFee float64        // Default excess baggage fee in EUR
+
+	// mu serializes issuing, so a payment, refund or fee is not invoiced
+	// twice by a request and the periodic check at the same time.
+	mu sync.Mutex
+}
+
+// NewService creates an invoice service for the airport with the given
+// IATA code and time zone. Numbering, VAT and the excess baggage fee are
+// read from the environment:
+//
+//	INVOICE_NUMBER_PREFIX  (default "MA")
+//	INVOICE_VAT_RATE       (default 19)
+//	BAGGAGE_ALLOWANCE_LB   (default 50)
+//	EXCESS_BAGGAGE_FEE     (default 75)
+func NewService(db database.Database, homeAirport string, location *time.Location) *Service {
+	prefix := strings.ToUpper(strings.TrimSpace(os.Getenv("INVOICE_NUMBER_PREFIX")))
+	if prefix == "" {
+		prefix = "MA"
+	}
+
+	return &Service{
+		db:               db,
+		HomeAirport:      homeAirport,
+		Location:         location,
+		NumberPrefix:     prefix,
+		VATRate:          floatFromEnv("INVOICE_VAT_RATE", 19),
+		BaggageAllowance: floatFromEnv("BAGGAGE_ALLOWANCE_LB", 50),
+		ExcessBaggageFee: floatFromEnv("EXCESS_BAGGAGE_FEE", 75),
+	}
+}
+
+// IssueForPayment issues the invoice of a captured payment with a line per
+// ticket. It returns the invoice already issued for the payment, if any.
+func (s *Service) IssueForPayment(paymentID string, now time.Time) (*models.Invoice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issuePayment(paymentID, now)
+}
+
+// issuePayment issues the invoice of a payment. The caller must hold s.mu.
+func (s *Service) issuePayment(paymentID string, now time.Time) (*models.Invoice, error) {
+	if invoice, err := s.latestInvoice(paymentID); err != nil || invoice != nil {
+		return invoice, err
+	}
+
+	payment, err := s.db.GetPaymentByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil || payment.CapturedAt == nil {
+		return nil, fmt.Errorf("payment %w: it is not captured", ErrNotInvoiceable)
+	}
+
+	paid, err := s.db.GetPaymentTickets(payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := s.db.GetBookingTickets(payment.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Ticket)
+	for _, ticket := range tickets {
+		byID[ticket.ID] = ticket
+	}
+
+	invoice := &models.Invoice{
+		Type:          TypeInvoice,
+		AirportUserID: payment.AirportUserID,
+		BookingID:     payment.BookingID,
+		Locator:       payment.Locator,
+		PaymentID:     payment.ID,
+		Currency:      payment.Currency,
+		PaymentMethod: methodCard,
+		CardLast4:     payment.CardLast4,
+	}
+	first := *payment.CapturedAt
+	c := s.newLookup()
+	for i, p := range paid {
+		ticket, ok := byID[p.TicketID]
+		if !ok {
+			return nil, fmt.Errorf("ticket %s of payment %s not found", p.TicketID, payment.ID)
+		}
+		line, departure, err := s.ticketLine(ticket, p.Amount, c)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 || departure.Before(first) {
+			first = departure
+		}
+		invoice.Lines = append(invoice.Lines, line)
+	}
+	SplitVAT(invoice.Lines)
+	invoice.ServiceDate = s.date(first)
+
+	if err := s.issue(invoice, now); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// IssueForRefund issues the credit note of a succeeded refund, paying back
+// the lines of its tickets on the latest invoice of the payment. The
+// invoice of the payment is issued first if it is missing. It returns the
+// credit note already issued for the refund, if any, and nil if the refund
+// pays nothing back that is still invoiced.
+func (s *Service) IssueForRefund(paymentID, refundID string, now time.Time) (*models.Invoice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.db.GetInvoicesBySource("", refundID, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return &existing[0], s.load(&existing[0])
+	}
+
+	refunds, err := s.db.GetPaymentRefunds(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	var refund *models.PaymentRefund
+	for i := range refunds {
+		if refunds[i].ID == refundID {
+			refund = &refunds[i]
+		}
+	}
+	if refund == nil || refund.Status != refundSucceeded {
+		return nil, fmt.Errorf("refund %w: it did not succeed", ErrNotInvoiceable)
+	}
+	if refund.Amount == 0 {
+		return nil, nil
+	}
+
+	original, err := s.issuePayment(paymentID, now)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := s.uncredited(original)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.db.GetPaymentTickets(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	refunded := make(map[string]bool)
+	for _, ticket := range paid {
+		if ticket.RefundID == refund.ID {
+			refunded[ticket.TicketID] = true
+		}
+	}
+
+	var lines []models.InvoiceLine
+	for _, line := range remaining {
+		if refunded[line.TicketID] {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	credit := s.creditNote(original, lines, refund.Reason)
+	credit.RefundID = refund.ID
+	credit.CreatedBy = refund.CreatedBy
+	if err := s.issue(credit, now); err != nil {
+		return nil, err
+	}
+	return credit, nil
+}
+
+// CreditNote cancels the lines of an invoice that are not yet credited with
+// a credit note. With reissue, the lines are invoiced again to the current
+// billing address of the customer, e.g. to correct the address. staffID is
+// the staff member issuing the credit note.
+func (s *Service) CreditNote(original *models.Invoice, reason string, reissue bool, staffID string, now time.Time) (*models.Invoice, *models.Invoice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if original.Type != TypeInvoice {
+		return nil, nil, fmt.Errorf("credit note %w", ErrNotCreditable)
+	}
+	remaining, err := s.uncredited(original)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(remaining) == 0 {
+		return nil, nil, fmt.Errorf("invoice %w: it is fully credited", ErrNotCreditable)
+	}
+
+	credit := s.creditNote(original, remaining, reason)
+	credit.CreatedBy = staffID
+	if err := s.issue(credit, now); err != nil {
+		return nil, nil, err
+	}
+	if !reissue {
+		return credit, nil, nil
+	}
+
+	reissued := &models.Invoice{
+		Type:          TypeInvoice,
+		AirportUserID: original.AirportUserID,
+		BookingID:     original.BookingID,
+		Locator:       original.Locator,
+		PaymentID:     original.PaymentID,
+		BaggageFeeID:  original.BaggageFeeID,
+		ServiceDate:   original.ServiceDate,
+		Currency:      original.Currency,
+		PaymentMethod: original.PaymentMethod,
+		CardLast4:     original.CardLast4,
+		Note:          "Replaces invoice " + original.Number,
+		CreatedBy:     staffID,
+	}
+	for _, line := range remaining {
+		line.CorrectsLine = 0
+		reissued.Lines = append(reissued.Lines, line)
+	}
+	if err := s.issue(reissued, now); err != nil {
+		return credit, nil, err
+	}
+	return credit, reissued, nil
+}
+
+// creditNote prepares a credit note paying back lines of an invoice to the
+// buyer of the invoice.
+func (s *Service) creditNote(original *models.Invoice, lines []models.InvoiceLine, note string) *models.Invoice {
+	credit := &models.Invoice{
+		Type:           TypeCreditNote,
+		AirportUserID:  original.AirportUserID,
+		BookingID:      original.BookingID,
+		Locator:        original.Locator,
+		PaymentID:      original.PaymentID,
+		BaggageFeeID:   original.BaggageFeeID,
+		CorrectsID:     original.ID,
+		CorrectsNumber: original.Number,
+		ServiceDate:    original.ServiceDate,
+		Currency:       original.Currency,
+		PaymentMethod:  original.PaymentMethod,
+		CardLast4:      original.CardLast4,
+		Buyer:          original.Buyer,
+		Note:           note,
+	}
+	for _, line := range lines {
+		line.CorrectsLine = line.LineNo
+		credit.Lines = append(credit.Lines, line)
+	}
+	return credit
+}
+
+// IssuePending issues the invoices and credit notes missing for captured
+// payments, succeeded refunds and excess baggage fees, e.g. because the
+// legal details of the airport were not set up or the database failed when
+// they were paid. Failures are logged and retried on the next call. It
+// returns the number of documents issued.
+func (s *Service) IssuePending(now time.Time) (int, error) {
+	sources, err := s.db.GetUninvoicedSources()
+	if err != nil {
+		return 0, err
+	}
+
+	issued := 0
+	for _, source := range sources {
+		var invoice *models.Invoice
+		switch source.Type {
+		case sourcePayment:
+			invoice, err = s.IssueForPayment(source.ID, now)
+		case sourceRefund:
+			invoice, err = s.IssueForRefund(source.PaymentID, source.ID, now)
+		case sourceBaggageFee:
+			invoice, err = s.IssueForBaggageFee(source.ID, now)
+		default:
+			continue
+		}
+		if err != nil {
+			log.Printf("Error issuing invoice for %s %s: %v", strings.ToLower(source.Type), source.ID, err)
+			continue
+		}
+		if invoice != nil {
+			issued++
+		}
+	}
+	return issued, nil
+}
+
+// issue numbers and stores an invoice or credit note with the legal details
+// of the airport valid now. The buyer is the billing address of the
+// customer, unless it is already set.
+func (s *Service) issue(invoice *models.Invoice, now time.Time) error {
+	issuer, err := s.db.GetInvoiceIssuer(now)
+	if err != nil {
+		return err
+	}
+	if issuer == nil {
+		return ErrNoIssuer
+	}
+	if invoice.Buyer.Name == "" {
+		buyer, err := s.buyer(invoice.AirportUserID)
+		if err != nil {
+			return err
+		}
+		invoice.Buyer = buyer
+	}
+
+	invoice.IssuerID = issuer.ID
+	invoice.IssuedAt = now.UTC()
+	invoice.NetAmount, invoice.VATAmount, invoice.GrossAmount = 0, 0, 0
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		line.LineNo = i + 1
+		invoice.NetAmount += line.NetAmount
+		invoice.VATAmount += line.VATAmount
+		invoice.GrossAmount += line.GrossAmount
+	}
+	invoice.NetAmount = round(invoice.NetAmount)
+	invoice.VATAmount = round(invoice.VATAmount)
+	invoice.GrossAmount = round(invoice.GrossAmount)
+	invoice.VATBreakdown = Breakdown(invoice.Lines)
+
+	year := now.In(s.Location).Year()
+	return s.db.CreateInvoice(invoice, year, func(sequence int) string {
+		return fmt.Sprintf("%s-%d-%06d", s.NumberPrefix, year, sequence)
+	})
+}
+
+// buyer returns the billing address of a user, completed with name and
+// email of the account.
+func (s *Service) buyer(userID string) (models.BillingAddress, error) {
+	var buyer models.BillingAddress
+	address, err := s.db.GetBillingAddress(userID)
+	if err != nil {
+		return buyer, err
+	}
+	if address != nil {
+		buyer = *address
+		buyer.AirportUserID = ""
+		buyer.UpdatedAt = nil
+	}
+
+	user, err := s.db.GetUserByID(userID)
+	if err != nil {
+		return buyer, err
+	}
+	if user != nil {
+		if buyer.Name == "" {
+			buyer.Name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		}
+		if buyer.Email == "" {
+			buyer.Email = user.Email
+		}
+	}
+	if buyer.Name == "" {
+		buyer.Name = userID
+	}
+	return buyer, nil
+}
+
+// latestInvoice returns the latest invoice of a payment with its lines, or
+// nil if none was issued. Earlier invoices were replaced by it.
+func (s *Service) latestInvoice(paymentID string) (*models.Invoice, error) {
+	invoices, err := s.db.GetInvoicesBySource(paymentID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for i := len(invoices) - 1; i >= 0; i-- {
+		if invoices[i].Type == TypeInvoice {
+			return &invoices[i], s.load(&invoices[i])
+		}
+	}
+	return nil, nil
+}
+
+// uncredited returns the lines of an invoice that no credit note paid back.
+func (s *Service) uncredited(invoice *models.Invoice) ([]models.InvoiceLine, error) {
+	lines, err := s.db.GetInvoiceLines(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.db.GetCreditNotes(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	credited := make(map[int]bool)
+	for _, note := range notes {
+		noteLines, err := s.db.GetInvoiceLines(note.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range noteLines {
+			credited[line.CorrectsLine] = true
+		}
+	}
+
+	var remaining []models.InvoiceLine
+	for _, line := range lines {
+		if !credited[line.LineNo] {
+			remaining = append(remaining, line)
+		}
+	}
+	return remaining, nil
+}
+
+// ticketLine prepares the invoice line of a ticket paid with the given
+// amount. It also returns the scheduled departure of the flight.
+func (s *Service) ticketLine(ticket models.Ticket, amount float64, c *lookup) (models.InvoiceLine, time.Time, error) {
+	flight, err := c.flight(ticket.Flight)
+	if err != nil {
+		return models.InvoiceLine{}, time.Time{}, err
+	}
+	category, rate := s.category(c.airport(flight.From), c.airport(flight.To), c.airport(s.HomeAirport))
+
+	parts := []string{s.flightDescription(flight)}
+	for _, part := range []string{ticket.PassengerName, ticket.TravelClass} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return models.InvoiceLine{
+		Description: strings.Join(parts, ", "),
+		Quantity:    1,
+		GrossAmount: round(amount),
+		VATCategory: category,
+		VATRate:     rate,
+		TicketID:    ticket.ID,
+	}, flight.ScheduledDeparture, nil
+}
+
+// flightDescription describes a flight by number, route and local date of
+// departure, e.g. "Flight LH100 MIN–FRA 2025-01-02".
+func (s *Service) flightDescription(flight models.Flight) string {
+	description := "Flight "
+	if flight.FlightNumber != "" {
+		description += flight.FlightNumber + " "
+	}
+	return description + flight.From + "–" + flight.To + " " + flight.ScheduledDeparture.In(s.Location).Format("2006-01-02")
+}
+
+// lookup caches the flights and airports read while issuing an invoice.
+type lookup struct {
+	db       database.Database
+	flights  map[string]models.Flight
+	airports map[string]models.Airport
+}
+
+// newLookup creates an empty cache.
+func (s *Service) newLookup() *lookup {
+	return &lookup{db: s.db, flights: make(map[string]models.Flight), airports: make(map[string]models.Airport)}
+}
+
+// flight returns a flight by ID.
+func (c *lookup) flight(id string) (models.Flight, error) {
+	if flight, ok := c.flights[id]; ok {
+		return flight, nil
+	}
+	flight, err := c.db.GetFlightByID(id)
+	if err != nil {
+		return flight, err
+	}
+	if flight.ID == "" {
+		return flight, fmt.Errorf("flight %s not found", id)
+	}
+	c.flights[id] = flight
+	return flight, nil
+}
+
+// airport returns an airport by IATA code; unknown airports have no country.
+func (c *lookup) airport(id string) models.Airport {
+	if airport, ok := c.airports[id]; ok {
+		return airport
+	}
+	airport := c.db.GetAirportByID(id)
+	c.airports[id] = airport
+	return airport
+}
+
+// Get returns an invoice or credit note with its lines, or nil if not found.
+func (s *Service) Get(id string) (*models.Invoice, error) {
+	invoice, err := s.db.GetInvoiceByID(id)
+	if err != nil || invoice == nil {
+		return nil, err
+	}
+	if err := s.load(invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// ForUser returns the invoices and credit notes of a user with their lines,
+// newest first.
+func (s *Service) ForUser(userID string) ([]models.Invoice, error) {
+	invoices, err := s.db.GetInvoicesByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadAll(invoices)
+}
+
+// Search returns the invoices and credit notes matching a filter with their
+// lines, newest first.
+func (s *Service) Search(filter models.InvoiceFilter) ([]models.Invoice, error) {
+	invoices, err := s.db.SearchInvoices(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadAll(invoices)
+}
+
+// loadAll reads the lines of invoices.
+func (s *Service) loadAll(invoices []models.Invoice) ([]models.Invoice, error) {
+	if invoices == nil {
+		invoices = []models.Invoice{}
+	}
+	for i := range invoices {
+		if err := s.load(&invoices[i]); err != nil {
+			return nil, err
+		}
+	}
+	return invoices, nil
+}
+
+// load reads the lines of an invoice and sums its VAT breakdown.
+func (s *Service) load(invoice *models.Invoice) error {
+	lines, err := s.db.GetInvoiceLines(invoice.ID)
+	if err != nil {
+		return err
+	}
+	invoice.Lines = lines
+	if invoice.Lines == nil {
+		invoice.Lines = []models.InvoiceLine{}
+	}
+	invoice.VATBreakdown = Breakdown(invoice.Lines)
+	return nil
+}
+
+// Issuer returns the legal details of the airport valid at the given time,
+// or nil if none were set up.
+func (s *Service) Issuer(at time.Time) (*models.InvoiceIssuer, error) {
+	return s.db.GetInvoiceIssuer(at)
+}
+
+// ValidateIssuer normalizes the legal details of the airport and checks
+// them. It returns a message describing the first problem, or "".
+func ValidateIssuer(req *models.InvoiceIssuerRequest) string {
+	req.Country = strings.ToUpper(strings.TrimSpace(req.Country))
+	req.VATID = normalizeVATID(req.VATID)
+	req.TaxNumber = strings.TrimSpace(req.TaxNumber)
+	req.IBAN = strings.ToUpper(strings.ReplaceAll(req.IBAN, " ", ""))
+	req.BIC = strings.ToUpper(strings.TrimSpace(req.BIC))
+
+	if !isCountryCode(req.Country) {
+		return "country must be a two-letter ISO 3166 code"
+	}
+	if req.VATID == "" && req.TaxNumber == "" {
+		return "vatId or taxNumber is required"
+	}
+	return ""
+}
+
+// SetIssuer adds a new version of the legal details of the airport, valid
+// from the time given in the request or from now. staffID is the staff
+// member making the change.
+func (s *Service) SetIssuer(req models.InvoiceIssuerRequest, staffID string, now time.Time) (*models.InvoiceIssuer, error) {
+	issuer := &models.InvoiceIssuer{
+		Name:              strings.TrimSpace(req.Name),
+		Street:            strings.TrimSpace(req.Street),
+		Postcode:          strings.TrimSpace(req.Postcode),
+		City:              strings.TrimSpace(req.City),
+		Country:           req.Country,
+		VATID:             req.VATID,
+		TaxNumber:         req.TaxNumber,
+		RegisterCourt:     strings.TrimSpace(req.RegisterCourt),
+		RegisterNumber:    strings.TrimSpace(req.RegisterNumber),
+		ManagingDirectors: strings.TrimSpace(req.ManagingDirectors),
+		ContactName:       strings.TrimSpace(req.ContactName),
+		Email:             strings.TrimSpace(req.Email),
+		Phone:             strings.TrimSpace(req.Phone),
+		IBAN:              req.IBAN,
+		BIC:               req.BIC,
+		BankName:          strings.TrimSpace(req.BankName),
+		ValidFrom:         now.UTC(),
+		CreatedBy:         staffID,
+	}
+	if req.ValidFrom != nil {
+		issuer.ValidFrom = req.ValidFrom.UTC()
+	}
+
+	if err := s.db.CreateInvoiceIssuer(issuer); err != nil {
+		return nil, err
+	}
+	return issuer, nil
+}
+
+// BillingAddress returns the billing address of a user, or nil if the user
+// has none.
+func (s *Service) BillingAddress(userID string) (*models.BillingAddress, error) {
+	return s.db.GetBillingAddress(userID)
+}
+
+// ValidateBillingAddress normalizes a billing address and checks it. It
+// returns a message describing the first problem, or "".
+func ValidateBillingAddress(req *models.BillingAddressRequest) string {
+	req.Company = strings.TrimSpace(req.Company)
+	req.Name = strings.TrimSpace(req.Name)
+	req.Street = strings.TrimSpace(req.Street)
+	req.Postcode = strings.TrimSpace(req.Postcode)
+	req.City = strings.TrimSpace(req.City)
+	req.Country = strings.ToUpper(strings.TrimSpace(req.Country))
+	req.VATID = normalizeVATID(req.VATID)
+	req.BuyerReference = strings.TrimSpace(req.BuyerReference)
+	req.Email = strings.TrimSpace(req.Email)
+
+	if req.Name == "" {
+		return "name is required"
+	}
+	if req.Country != "" && !isCountryCode(req.Country) {
+		return "country must be a two-letter ISO 3166 code"
+	}
+	if req.Email != "" && !strings.Contains(req.Email, "@") {
+		return "email is not a valid email address"
+	}
+	return ""
+}
+
+// SaveBillingAddress creates or replaces the billing address of a user.
+// Invoices issued earlier keep the address they were issued to.
+func (s *Service) SaveBillingAddress(userID string, req models.BillingAddressRequest, now time.Time) (*models.BillingAddress, error) {
+	updatedAt := now.UTC()
+	address := &models.BillingAddress{
+		AirportUserID:  userID,
+		Company:        req.Company,
+		Name:           req.Name,
+		Street:         req.Street,
+		Postcode:       req.Postcode,
+		City:           req.City,
+		Country:        req.Country,
+		VATID:          req.VATID,
+		BuyerReference: req.BuyerReference,
+		Email:          req.Email,
+		UpdatedAt:      &updatedAt,
+	}
+	if err := s.db.SaveBillingAddress(*address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// DeleteBillingAddress removes the billing address of a user; later
+// invoices are issued to the name and email of the account.
+func (s *Service) DeleteBillingAddress(userID string) error {
+	return s.db.DeleteBillingAddress(userID)
+}
+
+// date returns the local calendar day of t at the airport.
+func (s *Service) date(t time.Time) time.Time {
+	y, m, d := t.In(s.Location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// normalizeVATID removes spaces from a VAT ID and upper-cases it.
+func normalizeVATID(id string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(id), ""))
+}
+
+// isCountryCode reports whether code consists of two upper-case letters.
+func isCountryCode(code string) bool {
+	return len(code) == 2 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z'
+}
+
+// floatFromEnv reads a positive number from an environment variable,
+// falling back to the default if it is unset or invalid.
+func floatFromEnv(name string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
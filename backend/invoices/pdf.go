@@ -0,0 +1,504 @@
+package invoices
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"mindenairport/models"
+)
+
+// Layout of the A4 pages in points.
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	marginLeft   = 56
+	marginRight  = 539
+	footerTop    = 92
+	tableBottom  = 150
+	rowHeight    = 12
+	descriptionX = 84
+	descriptionW = 240
+)
+
+// PDF renders an invoice or credit note, loaded with its lines, as a PDF
+// document with the legal details of the airport it was issued with.
+func (s *Service) PDF(invoice *models.Invoice) ([]byte, error) {
+	issuer, err := s.db.GetInvoiceIssuerByID(invoice.IssuerID)
+	if err != nil {
+		return nil, err
+	}
+	if issuer == nil {
+		return nil, ErrNoIssuer
+	}
+
+	r := &invoiceRenderer{doc: &pdfDocument{}, invoice: invoice, issuer: issuer, loc: s.Location}
+	r.render()
+	return r.doc.bytes(r.title()+" "+invoice.Number, invoice.IssuedAt)
+}
+
+// invoiceRenderer lays an invoice out on the pages of a PDF document.
+type invoiceRenderer struct {
+	doc     *pdfDocument
+	invoice *models.Invoice
+	issuer  *models.InvoiceIssuer
+	loc     *time.Location
+	y       float64 // Baseline of the next row
+}
+
+// title returns "Invoice" or "Credit note".
+func (r *invoiceRenderer) title() string {
+	if r.invoice.Type == TypeCreditNote {
+		return "Credit note"
+	}
+	return "Invoice"
+}
+
+// render draws the whole document.
+func (r *invoiceRenderer) render() {
+	inv := r.invoice
+	r.newPage()
+	r.addresses()
+
+	r.doc.text(marginLeft, 590, 14, true, r.title()+" "+inv.Number)
+	r.y = 560
+	if inv.Type == TypeCreditNote {
+		r.doc.text(marginLeft, 574, 9, false, "This credit note corrects invoice "+inv.CorrectsNumber+".")
+	}
+
+	r.tableHeader()
+	for _, line := range inv.Lines {
+		r.row(line)
+	}
+	r.totals()
+	r.notes()
+	r.footers()
+}
+
+// newPage starts a page with the letterhead of the airport.
+func (r *invoiceRenderer) newPage() {
+	r.doc.addPage()
+	r.doc.text(marginLeft, 790, 16, true, r.issuer.Name)
+	r.doc.text(marginLeft, 774, 9, false, r.issuer.Street+", "+r.issuer.Postcode+" "+r.issuer.City)
+	r.doc.text(marginLeft, 762, 9, false, r.issuer.Email+" · "+r.issuer.Phone)
+	r.y = 730
+}
+
+// addresses draws the address of the buyer and the invoice details.
+func (r *invoiceRenderer) addresses() {
+	inv, buyer := r.invoice, r.invoice.Buyer
+	r.doc.text(marginLeft, 712, 7, false, r.issuer.Name+" · "+r.issuer.Street+" · "+r.issuer.Postcode+" "+r.issuer.City)
+
+	var lines []string
+	for _, line := range []string{buyer.Company, buyer.Name, buyer.Street, strings.TrimSpace(buyer.Postcode + " " + buyer.City)} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if buyer.Country != "" && buyer.Country != r.issuer.Country {
+		lines = append(lines, buyer.Country)
+	}
+	for i, line := range lines {
+		r.doc.text(marginLeft, 696-float64(i)*13, 10, false, line)
+	}
+
+	details := [][2]string{
+		{r.title() + " no.", inv.Number},
+		{"Date", inv.IssuedAt.In(r.loc).Format("2006-01-02")},
+		{"Service date", inv.ServiceDate.Format("2006-01-02")},
+	}
+	if inv.Locator != "" {
+		details = append(details, [2]string{"Booking", inv.Locator})
+	}
+	if inv.CorrectsNumber != "" {
+		details = append(details, [2]string{"Corrects invoice", inv.CorrectsNumber})
+	}
+	if buyer.VATID != "" {
+		details = append(details, [2]string{"Your VAT ID", buyer.VATID})
+	}
+	if buyer.BuyerReference != "" {
+		details = append(details, [2]string{"Your reference", buyer.BuyerReference})
+	}
+	for i, detail := range details {
+		y := 712 - float64(i)*13
+		r.doc.text(340, y, 9, false, detail[0])
+		r.doc.textRight(marginRight, y, 9, false, detail[1])
+	}
+}
+
+// tableHeader draws the column titles of the lines.
+func (r *invoiceRenderer) tableHeader() {
+	r.doc.text(marginLeft, r.y, 9, true, "Pos.")
+	r.doc.text(descriptionX, r.y, 9, true, "Description")
+	r.doc.textRight(380, r.y, 9, true, "VAT")
+	r.doc.textRight(460, r.y, 9, true, "Net "+r.invoice.Currency)
+	r.doc.textRight(marginRight, r.y, 9, true, "Gross "+r.invoice.Currency)
+	r.doc.line(marginLeft, r.y-5, marginRight, r.y-5)
+	r.y -= rowHeight + 6
+}
+
+// row draws a line of the invoice, continuing on a new page if needed.
+func (r *invoiceRenderer) row(line models.InvoiceLine) {
+	description := wrapText(line.Description, descriptionW, 9, false)
+	if r.y-float64(len(description)-1)*rowHeight < tableBottom {
+		r.newPage()
+		r.tableHeader()
+	}
+
+	vat := "exempt"
+	if line.VATCategory == CategoryStandard {
+		vat = formatRate(line.VATRate)
+	}
+	r.doc.text(marginLeft, r.y, 9, false, strconv.Itoa(line.LineNo))
+	r.doc.textRight(380, r.y, 9, false, vat)
+	r.doc.textRight(460, r.y, 9, false, formatAmount(line.NetAmount))
+	r.doc.textRight(marginRight, r.y, 9, false, formatAmount(line.GrossAmount))
+	for _, text := range description {
+		r.doc.text(descriptionX, r.y, 9, false, text)
+		r.y -= rowHeight
+	}
+	r.y -= 4
+}
+
+// totals draws the net total, the VAT breakdown and the gross total.
+func (r *invoiceRenderer) totals() {
+	inv := r.invoice
+	if r.y-float64(len(inv.VATBreakdown)+2)*14 < tableBottom {
+		r.newPage()
+	}
+
+	r.doc.line(marginLeft, r.y+8, marginRight, r.y+8)
+	r.y -= 6
+	r.total("Net total", inv.NetAmount, false)
+	for _, entry := range inv.VATBreakdown {
+		label := "VAT exempt on " + formatAmount(entry.NetAmount)
+		if entry.Category == CategoryStandard {
+			label = "VAT " + formatRate(entry.Rate) + " on " + formatAmount(entry.NetAmount)
+		}
+		r.total(label, entry.VATAmount, false)
+	}
+	r.total("Total "+inv.Currency, inv.GrossAmount, true)
+	r.y -= 10
+}
+
+// total draws a line of the totals.
+func (r *invoiceRenderer) total(label string, amount float64, bold bool) {
+	r.doc.text(300, r.y, 9, bold, label)
+	r.doc.textRight(marginRight, r.y, 9, bold, formatAmount(amount))
+	r.y -= 14
+}
+
+// notes draws the VAT exemptions, how the invoice was paid and its note.
+func (r *invoiceRenderer) notes() {
+	inv := r.invoice
+	var notes []string
+	for _, entry := range inv.VATBreakdown {
+		if entry.ExemptionReason != "" {
+			notes = append(notes, entry.ExemptionReason+".")
+		}
+	}
+
+	paid := inv.IssuedAt.In(r.loc).Format("2006-01-02")
+	switch {
+	case inv.Type == TypeCreditNote && inv.PaymentMethod == methodCard:
+		notes = append(notes, "The amount is refunded to the card ending in "+inv.CardLast4+".")
+	case inv.Type == TypeCreditNote:
+		notes = append(notes, "The amount is paid back in cash.")
+	case inv.PaymentMethod == methodCard:
+		notes = append(notes, "Paid by card ending in "+inv.CardLast4+" on "+paid+". No payment is due.")
+	default:
+		notes = append(notes, "Paid in cash on "+paid+". No payment is due.")
+	}
+
+	if inv.Note != "" {
+		if inv.Type == TypeCreditNote {
+			notes = append(notes, "Reason: "+inv.Note)
+		} else {
+			notes = append(notes, inv.Note)
+		}
+	}
+
+	for _, note := range notes {
+		for _, text := range wrapText(note, marginRight-marginLeft, 9, false) {
+			if r.y < footerTop+12 {
+				r.newPage()
+			}
+			r.doc.text(marginLeft, r.y, 9, false, text)
+			r.y -= rowHeight
+		}
+		r.y -= 2
+	}
+}
+
+// footers draws the legal details of the airport and the page number at
+// the bottom of every page.
+func (r *invoiceRenderer) footers() {
+	is := r.issuer
+	columns := [][]string{
+		{is.Name, is.Street, is.Postcode + " " + is.City},
+		{},
+		{},
+	}
+	if is.ManagingDirectors != "" {
+		columns[1] = append(columns[1], "Managing directors: "+is.ManagingDirectors)
+	}
+	if is.RegisterCourt != "" || is.RegisterNumber != "" {
+		columns[1] = append(columns[1], strings.TrimSpace(is.RegisterCourt+" "+is.RegisterNumber))
+	}
+	if is.VATID != "" {
+		columns[1] = append(columns[1], "VAT ID: "+is.VATID)
+	}
+	if is.TaxNumber != "" {
+		columns[1] = append(columns[1], "Tax number: "+is.TaxNumber)
+	}
+	for _, line := range []string{is.BankName, ibanLine(is.IBAN), bicLine(is.BIC)} {
+		if line != "" {
+			columns[2] = append(columns[2], line)
+		}
+	}
+
+	count := len(r.doc.pages)
+	for i, page := range r.doc.pages {
+		r.doc.page = page
+		r.doc.line(marginLeft, footerTop, marginRight, footerTop)
+		for c, column := range columns {
+			for l, text := range column {
+				r.doc.text(marginLeft+float64(c)*170, footerTop-12-float64(l)*9, 7, false, text)
+			}
+		}
+		r.doc.textRight(marginRight, 40, 7, false, fmt.Sprintf("Page %d of %d", i+1, count))
+	}
+}
+
+// ibanLine labels the IBAN of the airport, or returns "" if unset.
+func ibanLine(iban string) string {
+	if iban == "" {
+		return ""
+	}
+	return "IBAN: " + iban
+}
+
+// bicLine labels the BIC of the airport, or returns "" if unset.
+func bicLine(bic string) string {
+	if bic == "" {
+		return ""
+	}
+	return "BIC: " + bic
+}
+
+// formatAmount formats an amount with two decimals and thousands
+// separators, e.g. "1,234.50".
+func formatAmount(amount float64) string {
+	text := strconv.FormatFloat(amount, 'f', 2, 64)
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	whole, cents := text[:len(text)-3], text[len(text)-3:]
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	return sign + whole + cents
+}
+
+// formatRate formats a VAT rate in percent, e.g. "19%" or "7.5%".
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+}
+
+// pdfDocument writes a PDF document of A4 pages using the standard fonts
+// Helvetica (F1) and Helvetica-Bold (F2), which need not be embedded. Text
+// is encoded in WinAnsiEncoding.
+type pdfDocument struct {
+	pages []*bytes.Buffer // Content stream of each page
+	page  *bytes.Buffer   // Page drawn on
+}
+
+// addPage starts a new page and draws on it from then on.
+func (d *pdfDocument) addPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+}
+
+// text draws text with its baseline starting at x, y.
+func (d *pdfDocument) text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT /%s %s Tf %s %s Td %s Tj ET\n", font, pdfNumber(size), pdfNumber(x), pdfNumber(y), pdfString(text))
+}
+
+// textRight draws text ending at x.
+func (d *pdfDocument) textRight(x, y, size float64, bold bool, text string) {
+	d.text(x-textWidth(text, size, bold), y, size, bold, text)
+}
+
+// line draws a thin line.
+func (d *pdfDocument) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page, "0.5 w %s %s m %s %s l S\n", pdfNumber(x1), pdfNumber(y1), pdfNumber(x2), pdfNumber(y2))
+}
+
+// bytes assembles the document with the given title. Objects 1 to 5 are
+// the catalog, the page tree, both fonts and the document information;
+// each page and its compressed content stream follow.
+func (d *pdfDocument) bytes(title string, created time.Time) ([]byte, error) {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (MindenAirport) /CreationDate (D:%s) >>",
+		pdfString(title), created.UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 7+2*i))
+
+		var content bytes.Buffer
+		w := zlib.NewWriter(&content)
+		if _, err := w.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", len(offsets), content.Len())
+		out.Write(content.Bytes())
+		out.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
+
+// pdfNumber formats a coordinate or font size.
+func pdfNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// pdfString encodes text as a PDF literal string in WinAnsiEncoding.
+func pdfString(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range winAnsi(text) {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 32:
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// winAnsiSpecial maps the characters WinAnsiEncoding places in 0x80-0x9F.
+var winAnsiSpecial = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// winAnsi converts UTF-8 text to WinAnsiEncoding, replacing characters it
+// cannot represent with "?".
+func winAnsi(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, c := range text {
+		switch {
+		case c < 0x80 || (c >= 0xA0 && c <= 0xFF):
+			out = append(out, byte(c))
+		case winAnsiSpecial[c] != 0:
+			out = append(out, winAnsiSpecial[c])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// Widths of the printable ASCII characters from space to "~" in thousandths
+// of the font size, from the metrics of the standard fonts.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// textWidth returns the width of text in points. Characters outside ASCII
+// are measured with the width of a digit, which fits most Latin letters.
+func textWidth(text string, size float64, bold bool) float64 {
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, c := range winAnsi(text) {
+		if c >= 32 && c <= 126 {
+			total += widths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// wrapText breaks text into lines no wider than width at the given font
+// size. Words longer than a line are not broken.
+func wrapText(text string, width, size float64, bold bool) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && textWidth(candidate, size, bold) > width {
+			lines = append(lines, current)
+			candidate = word
+		}
+		current = candidate
+	}
+	if current != "" || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
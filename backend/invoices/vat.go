@@ -0,0 +1,98 @@
+package invoices
+
+import (
+	"math"
+
+	"mindenairport/apis"
+	"mindenairport/models"
+)
+
+const (
+	// CategoryStandard is the VAT category of domestic flights and their fees.
+	CategoryStandard = "S"
+	// CategoryExempt is the VAT category of international flights and their fees.
+	CategoryExempt = "E"
+
+	// ExemptionInternational states why international flights carry no VAT.
+	ExemptionInternational = "Steuerfreie grenzüberschreitende Personenbeförderung im Luftverkehr (§ 26 Abs. 3 UStG)"
+)
+
+// category returns the VAT category and rate of a flight between two
+// airports. Flights within the country of the home airport are taxed at the
+// standard rate; flights crossing a border are exempt.
+func (s *Service) category(from, to, home models.Airport) (string, float64) {
+	if !apis.IsInternational(from, to) && !apis.IsInternational(from, home) {
+		return CategoryStandard, s.VATRate
+	}
+	return CategoryExempt, 0
+}
+
+// SplitVAT sets net and VAT amount of lines from their gross amount. The
+// VAT of each category and rate is calculated on the sum of its lines, and
+// the last line of the group takes the rounding difference, so the lines
+// add up to the breakdown.
+func SplitVAT(lines []models.InvoiceLine) {
+	gross := make(map[vatKey]float64)
+	last := make(map[vatKey]int)
+	for i, line := range lines {
+		key := vatKey{line.VATCategory, line.VATRate}
+		gross[key] += line.GrossAmount
+		last[key] = i
+	}
+
+	remaining := make(map[vatKey]float64)
+	for key, amount := range gross {
+		remaining[key] = netOf(amount, key.rate)
+	}
+	for i := range lines {
+		line := &lines[i]
+		key := vatKey{line.VATCategory, line.VATRate}
+		if i == last[key] {
+			line.NetAmount = round(remaining[key])
+		} else {
+			line.NetAmount = netOf(line.GrossAmount, key.rate)
+			remaining[key] -= line.NetAmount
+		}
+		line.VATAmount = round(line.GrossAmount - line.NetAmount)
+	}
+}
+
+// vatKey identifies the lines taxed alike.
+type vatKey struct {
+	category string
+	rate     float64
+}
+
+// Breakdown sums the lines by VAT category and rate, in the order they
+// first appear.
+func Breakdown(lines []models.InvoiceLine) []models.VATBreakdown {
+	breakdown := []models.VATBreakdown{}
+	for _, line := range lines {
+		i := 0
+		for i < len(breakdown) && (breakdown[i].Category != line.VATCategory || breakdown[i].Rate != line.VATRate) {
+			i++
+		}
+		if i == len(breakdown) {
+			entry := models.VATBreakdown{Category: line.VATCategory, Rate: line.VATRate}
+			if line.VATCategory == CategoryExempt {
+				entry.ExemptionReason = ExemptionInternational
+			}
+			breakdown = append(breakdown, entry)
+		}
+		breakdown[i].NetAmount = round(breakdown[i].NetAmount + line.NetAmount)
+		breakdown[i].VATAmount = round(breakdown[i].VATAmount + line.VATAmount)
+		breakdown[i].GrossAmount = round(breakdown[i].GrossAmount + line.GrossAmount)
+	}
+	return breakdown
+}
+
+// netOf returns the net amount contained in a gross amount at a VAT rate
+// in percent.
+func netOf(gross, rate float64) float64 {
+	return round(gross / (1 + rate/100))
+}
+
+// round rounds an amount to cents.
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,143 @@
+package invoices
+
+import (
+	"math"
+	"testing"
+
+	"mindenairport/models"
+)
+
+// amountsEqual compares two amounts in EUR to the cent.
+func amountsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.001
+}
+
+func TestSplitVAT(t *testing.T) {
+	standard := func(gross float64) models.InvoiceLine {
+		return models.InvoiceLine{VATCategory: CategoryStandard, VATRate: 19, GrossAmount: gross}
+	}
+	exempt := func(gross float64) models.InvoiceLine {
+		return models.InvoiceLine{VATCategory: CategoryExempt, GrossAmount: gross}
+	}
+
+	tests := []struct {
+		name    string
+		lines   []models.InvoiceLine
+		wantNet []float64
+		wantVAT []float64
+	}{
+		{"single line", []models.InvoiceLine{standard(100)}, []float64{84.03}, []float64{15.97}},
+		{"last line takes the rounding difference",
+			[]models.InvoiceLine{standard(10), standard(10), standard(10)},
+			[]float64{8.40, 8.40, 8.41}, []float64{1.60, 1.60, 1.59}},
+		{"exempt lines carry no VAT", []models.InvoiceLine{exempt(249.99), exempt(0.01)},
+			[]float64{249.99, 0.01}, []float64{0, 0}},
+		{"groups are rounded separately",
+			[]models.InvoiceLine{standard(10), exempt(50), standard(10)},
+			[]float64{8.40, 50, 8.41}, []float64{1.60, 0, 1.59}},
+		{"smallest amount", []models.InvoiceLine{standard(0.01)}, []float64{0.01}, []float64{0}},
+		{"zero amount", []models.InvoiceLine{standard(0)}, []float64{0}, []float64{0}},
+		{"credit note lines are negative",
+			[]models.InvoiceLine{standard(-10), standard(-10), standard(-10)},
+			[]float64{-8.40, -8.40, -8.41}, []float64{-1.60, -1.60, -1.59}},
+		{"reduced rate is a group of its own",
+			[]models.InvoiceLine{standard(10), {VATCategory: CategoryStandard, VATRate: 7, GrossAmount: 10}},
+			[]float64{8.40, 9.35}, []float64{1.60, 0.65}},
+		{"no lines", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SplitVAT(tt.lines)
+			for i, line := range tt.lines {
+				if !amountsEqual(line.NetAmount, tt.wantNet[i]) || !amountsEqual(line.VATAmount, tt.wantVAT[i]) {
+					t.Errorf("line %d: net %.2f, VAT %.2f, want %.2f, %.2f",
+						i, line.NetAmount, line.VATAmount, tt.wantNet[i], tt.wantVAT[i])
+				}
+				if !amountsEqual(line.NetAmount+line.VATAmount, line.GrossAmount) {
+					t.Errorf("line %d: net %.2f + VAT %.2f != gross %.2f", i, line.NetAmount, line.VATAmount, line.GrossAmount)
+				}
+			}
+		})
+	}
+}
+
+// TestSplitVATMatchesGroupTotal checks that the lines of a group always add
+// up to the VAT calculated on the group's total, whatever the amounts.
+func TestSplitVATMatchesGroupTotal(t *testing.T) {
+	for cents := 1; cents <= 500; cents++ {
+		gross := float64(cents) / 100
+		lines := []models.InvoiceLine{
+			{VATCategory: CategoryStandard, VATRate: 19, GrossAmount: gross},
+			{VATCategory: CategoryStandard, VATRate: 19, GrossAmount: gross + 0.01},
+			{VATCategory: CategoryStandard, VATRate: 19, GrossAmount: gross + 0.02},
+		}
+		SplitVAT(lines)
+
+		total := 3*gross + 0.03
+		want := netOf(total, 19)
+		got := lines[0].NetAmount + lines[1].NetAmount + lines[2].NetAmount
+		if !amountsEqual(got, want) {
+			t.Fatalf("gross %.2f: lines add up to net %.2f, want %.2f", gross, got, want)
+		}
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	lines := []models.InvoiceLine{
+		{VATCategory: CategoryExempt, NetAmount: 120, GrossAmount: 120},
+		{VATCategory: CategoryStandard, VATRate: 19, NetAmount: 8.40, VATAmount: 1.60, GrossAmount: 10},
+		{VATCategory: CategoryExempt, NetAmount: 80, GrossAmount: 80},
+		{VATCategory: CategoryStandard, VATRate: 19, NetAmount: 8.41, VATAmount: 1.59, GrossAmount: 10},
+	}
+
+	breakdown := Breakdown(lines)
+
+	if len(breakdown) != 2 {
+		t.Fatalf("Breakdown() = %+v, want two entries", breakdown)
+	}
+	exempt, standard := breakdown[0], breakdown[1]
+	if exempt.Category != CategoryExempt || !amountsEqual(exempt.NetAmount, 200) || exempt.VATAmount != 0 {
+		t.Errorf("first entry = %+v, want the exempt lines", exempt)
+	}
+	if exempt.ExemptionReason != ExemptionInternational {
+		t.Errorf("exempt entry has exemption reason %q", exempt.ExemptionReason)
+	}
+	if standard.Category != CategoryStandard || !amountsEqual(standard.NetAmount, 16.81) ||
+		!amountsEqual(standard.VATAmount, 3.19) || !amountsEqual(standard.GrossAmount, 20) {
+		t.Errorf("second entry = %+v, want net 16.81, VAT 3.19, gross 20", standard)
+	}
+	if standard.ExemptionReason != "" {
+		t.Errorf("standard entry has exemption reason %q", standard.ExemptionReason)
+	}
+
+	if empty := Breakdown(nil); empty == nil || len(empty) != 0 {
+		t.Errorf("Breakdown(nil) = %#v, want an empty list", empty)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	s := &Service{VATRate: 19}
+	home := models.Airport{ID: "MIN", Country: "DE"}
+	tests := []struct {
+		name         string
+		from, to     models.Airport
+		wantCategory string
+		wantRate     float64
+	}{
+		{"domestic", home, models.Airport{Country: "DE"}, CategoryStandard, 19},
+		{"outbound", home, models.Airport{Country: "AT"}, CategoryExempt, 0},
+		{"inbound", models.Airport{Country: "AT"}, home, CategoryExempt, 0},
+		{"abroad", models.Airport{Country: "FR"}, models.Airport{Country: "FR"}, CategoryExempt, 0},
+		{"unknown country", models.Airport{}, home, CategoryExempt, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, rate := s.category(tt.from, tt.to, home)
+			if category != tt.wantCategory || rate != tt.wantRate {
+				t.Errorf("category() = %s %v, want %s %v", category, rate, tt.wantCategory, tt.wantRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+// InspectionMonitor periodically checks the next safety inspection dates of
+// all hangars and keeps a list of hangars whose inspection is due or overdue.
+// Closed hangars are not reminded.
+type InspectionMonitor struct {
+	db        database.Database
+	DueWindow time.Duration // Reminder period before an inspection date
+	Interval  time.Duration // Time between two checks
+
+	mu     sync.RWMutex
+	report models.InspectionReport
+}
+
+// NewInspectionMonitor creates a monitor configured from the environment:
+//
+//	HANGAR_INSPECTION_DUE_DAYS                (default 30)
+//	HANGAR_INSPECTION_CHECK_INTERVAL_MINUTES  (default 360)
+func NewInspectionMonitor(db database.Database) *InspectionMonitor {
+	return &InspectionMonitor{
+		db:        db,
+		DueWindow: time.Duration(intFromEnv("HANGAR_INSPECTION_DUE_DAYS", 30)) * 24 * time.Hour,
+		Interval:  time.Duration(intFromEnv("HANGAR_INSPECTION_CHECK_INTERVAL_MINUTES", 360)) * time.Minute,
+		report:    models.InspectionReport{Reminders: []models.InspectionReminder{}},
+	}
+}
+
+// Start runs the check immediately and then periodically in the background.
+func (m *InspectionMonitor) Start() {
+	runEvery("hangar inspection", m.Interval, func(now time.Time) error {
+		_, err := m.Check(now)
+		return err
+	})
+}
+
+// Report returns the result of the latest check.
+func (m *InspectionMonitor) Report() models.InspectionReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.report
+}
+
+// Check evaluates the inspection dates of all hangars at the given time.
+// Overdue inspections are logged. The report is stored for Report and returned.
+func (m *InspectionMonitor) Check(now time.Time) (models.InspectionReport, error) {
+	report := models.InspectionReport{CheckedAt: now, Reminders: []models.InspectionReminder{}}
+
+	hangars, err := m.db.GetHangars()
+	if err != nil {
+		return report, err
+	}
+
+	for _, hangar := range hangars {
+		if hangar.Status == "CLOSED" || hangar.NextInspection == nil || hangar.NextInspection.After(now.Add(m.DueWindow)) {
+			continue
+		}
+
+		reminder := models.InspectionReminder{
+			HangarID:       hangar.ID,
+			PlotID:         hangar.PlotID,
+			HangarStatus:   hangar.Status,
+			NextInspection: *hangar.NextInspection,
+			State:          models.MaintenanceStateDue,
+			DaysRemaining:  int(math.Floor(hangar.NextInspection.Sub(now).Hours() / 24)),
+		}
+		if !hangar.NextInspection.After(now) {
+			reminder.State = models.MaintenanceStateOverdue
+			log.Printf("Safety inspection of hangar %s is overdue since %s",
+				hangar.ID, hangar.NextInspection.Format("2006-01-02"))
+		}
+		report.Reminders = append(report.Reminders, reminder)
+	}
+
+	m.mu.Lock()
+	m.report = report
+	m.mu.Unlock()
+
+	return report, nil
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"mindenairport/invoices"
+)
+
+// InvoiceMonitor periodically issues the invoices and credit notes of
+// payments, refunds and excess baggage fees that could not be issued right
+// away, e.g. because the legal details of the airport were not set up.
+type InvoiceMonitor struct {
+	invoices *invoices.Service
+	Interval time.Duration // Time between two checks
+}
+
+// NewInvoiceMonitor creates a monitor configured from the environment:
+//
+//	INVOICE_CHECK_INTERVAL_MINUTES  (default 15)
+func NewInvoiceMonitor(invoicing *invoices.Service) *InvoiceMonitor {
+	return &InvoiceMonitor{
+		invoices: invoicing,
+		Interval: time.Duration(intFromEnv("INVOICE_CHECK_INTERVAL_MINUTES", 15)) * time.Minute,
+	}
+}
+
+// Start runs the check immediately and then periodically in the background.
+func (m *InvoiceMonitor) Start() {
+	runEvery("invoice", m.Interval, func(now time.Time) error {
+		issued, err := m.invoices.IssuePending(now)
+		if issued > 0 {
+			log.Printf("Issued %d missing invoices", issued)
+		}
+		return err
+	})
+}
@@ -0,0 +1,41 @@
+// Package jobs runs periodic background tasks of the MindenAirport backend,
+// such as watching maintenance and hangar inspection deadlines, rolling out
+// flight schedules, expiring waitlist offers, releasing unpaid bookings or
+// issuing missing invoices.
+package jobs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// runEvery runs task once immediately and then at the given interval in a
+// background goroutine. Errors are logged; the task keeps running.
+func runEvery(name string, interval time.Duration, task func(now time.Time) error) {
+	go func() {
+		run := func() {
+			if err := task(time.Now()); err != nil {
+				log.Printf("Error running %s job: %v", name, err)
+			}
+		}
+
+		run()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
+
+// intFromEnv reads a positive integer from an environment variable,
+// falling back to the default if it is unset or invalid.
+func intFromEnv(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
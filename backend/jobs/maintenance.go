@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+// MaintenanceMonitor periodically checks the next maintenance dates of all
+// planes. Planes whose deadline passed are taken out of service, and the
+// latest report lists due and overdue planes with their affected flights.
+type MaintenanceMonitor struct {
+	db        database.Database
+	DueWindow time.Duration // Warning period before a deadline; also the look-ahead for affected flights
+	Interval  time.Duration // Time between two checks
+
+	mu     sync.RWMutex
+	report models.MaintenanceReport
+}
+
+// NewMaintenanceMonitor creates a monitor configured from the environment:
+//
+//	MAINTENANCE_DUE_DAYS                (default 14)
+//	MAINTENANCE_CHECK_INTERVAL_MINUTES  (default 60)
+func NewMaintenanceMonitor(db database.Database) *MaintenanceMonitor {
+	return &MaintenanceMonitor{
+		db:        db,
+		DueWindow: time.Duration(intFromEnv("MAINTENANCE_DUE_DAYS", 14)) * 24 * time.Hour,
+		Interval:  time.Duration(intFromEnv("MAINTENANCE_CHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+		report:    models.MaintenanceReport{Alerts: []models.MaintenanceAlert{}},
+	}
+}
+
+// Start runs the check immediately and then periodically in the background.
+func (m *MaintenanceMonitor) Start() {
+	runEvery("maintenance", m.Interval, func(now time.Time) error {
+		_, err := m.Check(now)
+		return err
+	})
+}
+
+// Report returns the result of the latest check.
+func (m *MaintenanceMonitor) Report() models.MaintenanceReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.report
+}
+
+// Check evaluates the maintenance deadlines of all planes at the given time.
+// Active planes with an overdue deadline are set to MAINTENANCE. The report
+// is stored for Report and returned.
+func (m *MaintenanceMonitor) Check(now time.Time) (models.MaintenanceReport, error) {
+	report := models.MaintenanceReport{CheckedAt: now, Alerts: []models.MaintenanceAlert{}}
+
+	schedule, err := m.db.GetMaintenanceSchedule()
+	if err != nil {
+		return report, err
+	}
+
+	for _, due := range schedule {
+		if due.NextMaintenance.IsZero() || due.NextMaintenance.After(now.Add(m.DueWindow)) {
+			continue
+		}
+
+		alert := models.MaintenanceAlert{MaintenanceDue: due, State: models.MaintenanceStateDue}
+		if !due.NextMaintenance.After(now) {
+			alert.State = models.MaintenanceStateOverdue
+			if due.PlaneStatus == "ACTIVE" {
+				changed, err := m.takeOutOfService(due.PlaneID)
+				if err != nil {
+					return report, err
+				}
+				alert.StatusChanged = changed
+			}
+		}
+
+		alert.AffectedFlights, err = m.affectedFlights(due, now)
+		if err != nil {
+			return report, err
+		}
+
+		if alert.StatusChanged {
+			log.Printf("Plane %s is overdue for maintenance since %s and was set to MAINTENANCE; affected flights: %v",
+				due.PlaneID, due.NextMaintenance.Format("2006-01-02"), alert.AffectedFlights)
+		}
+		report.Alerts = append(report.Alerts, alert)
+	}
+
+	m.mu.Lock()
+	m.report = report
+	m.mu.Unlock()
+
+	return report, nil
+}
+
+// takeOutOfService sets an active plane to MAINTENANCE. It reports whether
+// the status was changed.
+func (m *MaintenanceMonitor) takeOutOfService(planeID string) (bool, error) {
+	plane, err := m.db.GetPlaneByID(planeID)
+	if err != nil || plane == nil || plane.Status != "ACTIVE" {
+		return false, err
+	}
+
+	plane.Status = "MAINTENANCE"
+	if err := m.db.UpdatePlane(*plane); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// affectedFlights returns the flights of the plane departing after its
+// maintenance deadline (or from now on, if the deadline has passed) within
+// the due window.
+func (m *MaintenanceMonitor) affectedFlights(due models.MaintenanceDue, now time.Time) ([]string, error) {
+	from := due.NextMaintenance
+	if from.Before(now) {
+		from = now
+	}
+
+	flights, err := m.db.GetFlightsByPlane(due.PlaneID, from, from.Add(m.DueWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, f := range flights {
+		if f.StatusID != models.FlightStatusCancelled {
+			ids = append(ids, f.ID)
+		}
+	}
+	return ids, nil
+}
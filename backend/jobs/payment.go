@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"mindenairport/payments"
+)
+
+// HoldMonitor periodically releases held bookings that were not paid in
+// time, voiding their open payments, so their seats can be booked again.
+type HoldMonitor struct {
+	payments *payments.Service
+	Interval time.Duration // Time between two checks
+}
+
+// NewHoldMonitor creates a monitor configured from the environment:
+//
+//	PAYMENT_CHECK_INTERVAL_MINUTES  (default 1)
+func NewHoldMonitor(charges *payments.Service) *HoldMonitor {
+	return &HoldMonitor{
+		payments: charges,
+		Interval: time.Duration(intFromEnv("PAYMENT_CHECK_INTERVAL_MINUTES", 1)) * time.Minute,
+	}
+}
+
+// Start runs the check immediately and then periodically in the background.
+func (m *HoldMonitor) Start() {
+	runEvery("payment hold", m.Interval, func(now time.Time) error {
+		released, err := m.payments.ExpireHolds(now)
+		if released > 0 {
+			log.Printf("Released %d unpaid bookings", released)
+		}
+		return err
+	})
+}
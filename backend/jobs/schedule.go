@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+	"mindenairport/schedule"
+)
+
+// ScheduleRollout periodically generates the flights of all schedule
+// templates up to a rolling horizon, so that each day the next day of the
+// season becomes bookable. Template changes are rolled out immediately via
+// Sync.
+type ScheduleRollout struct {
+	db        database.Database
+	generator *schedule.Generator
+	Horizon   time.Duration // How far ahead flights are generated
+	Interval  time.Duration // Time between two runs
+
+	// run serializes changes to template flights, so that concurrent runs
+	// do not create the same flight twice
+	run sync.Mutex
+
+	mu     sync.RWMutex
+	report models.ScheduleRolloutReport
+}
+
+// NewScheduleRollout creates a rollout configured from the environment:
+//
+//	SCHEDULE_HORIZON_DAYS              (default 90)
+//	SCHEDULE_ROLLOUT_INTERVAL_MINUTES  (default 360)
+func NewScheduleRollout(db database.Database, planner *planning.Planner) *ScheduleRollout {
+	return &ScheduleRollout{
+		db:        db,
+		generator: schedule.NewGenerator(db, planner),
+		Horizon:   time.Duration(intFromEnv("SCHEDULE_HORIZON_DAYS", 90)) * 24 * time.Hour,
+		Interval:  time.Duration(intFromEnv("SCHEDULE_ROLLOUT_INTERVAL_MINUTES", 360)) * time.Minute,
+		report:    models.ScheduleRolloutReport{Templates: []models.ScheduleSync{}},
+	}
+}
+
+// Start runs the rollout immediately and then periodically in the background.
+func (r *ScheduleRollout) Start() {
+	runEvery("schedule rollout", r.Interval, func(now time.Time) error {
+		_, err := r.Run(now)
+		return err
+	})
+}
+
+// Report returns the result of the latest run.
+func (r *ScheduleRollout) Report() models.ScheduleRolloutReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.report
+}
+
+// Run generates the flights of all templates departing within the horizon
+// from now. A template that fails is logged and the remaining templates are
+// still rolled out. The report is stored for Report and returned.
+func (r *ScheduleRollout) Run(now time.Time) (models.ScheduleRolloutReport, error) {
+	report := models.ScheduleRolloutReport{CheckedAt: now, Until: now.Add(r.Horizon), Templates: []models.ScheduleSync{}}
+
+	templates, err := r.db.GetScheduleTemplates()
+	if err != nil {
+		return report, err
+	}
+
+	for _, template := range templates {
+		if template.ValidTo.Before(now.AddDate(0, 0, -1)) {
+			continue
+		}
+
+		result, err := r.Sync(template, now)
+		if err != nil {
+			log.Printf("Error rolling out schedule template %s (%s): %v", template.ID, template.FlightNumber, err)
+			continue
+		}
+		if len(result.Outdated) > 0 {
+			log.Printf("Schedule template %s (%s) has %d booked flights that differ from the template",
+				template.ID, template.FlightNumber, len(result.Outdated))
+		}
+		report.Templates = append(report.Templates, result)
+	}
+
+	r.mu.Lock()
+	r.report = report
+	r.mu.Unlock()
+
+	return report, nil
+}
+
+// Sync brings the flights of one template within the horizon in line with it.
+func (r *ScheduleRollout) Sync(template models.ScheduleTemplate, now time.Time) (models.ScheduleSync, error) {
+	r.run.Lock()
+	defer r.run.Unlock()
+	return r.generator.Sync(template, now, now.Add(r.Horizon))
+}
+
+// Validate checks that a template can generate flights.
+func (r *ScheduleRollout) Validate(template models.ScheduleTemplate) error {
+	return r.generator.Validate(template)
+}
+
+// Remove withdraws the future flights of a template that is about to be deleted.
+func (r *ScheduleRollout) Remove(template models.ScheduleTemplate, now time.Time) (models.ScheduleSync, error) {
+	r.run.Lock()
+	defer r.run.Unlock()
+	return r.generator.Remove(template, now)
+}
+
+// Apply changes an outdated flight to the current revision of its template.
+func (r *ScheduleRollout) Apply(template models.ScheduleTemplate, flight models.Flight) (models.Flight, error) {
+	r.run.Lock()
+	defer r.run.Unlock()
+	return r.generator.Apply(template, flight)
+}
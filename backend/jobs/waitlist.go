@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"mindenairport/booking"
+)
+
+// WaitlistMonitor periodically closes waitlist offers that were not accepted
+// in time and entries of flights that departed or were cancelled, and passes
+// the seats of expired offers on to the next passengers waiting.
+type WaitlistMonitor struct {
+	bookings *booking.Service
+	Interval time.Duration // Time between two checks
+}
+
+// NewWaitlistMonitor creates a monitor configured from the environment:
+//
+//	WAITLIST_CHECK_INTERVAL_MINUTES  (default 5)
+func NewWaitlistMonitor(bookings *booking.Service) *WaitlistMonitor {
+	return &WaitlistMonitor{
+		bookings: bookings,
+		Interval: time.Duration(intFromEnv("WAITLIST_CHECK_INTERVAL_MINUTES", 5)) * time.Minute,
+	}
+}
+
+// Start runs the check immediately and then periodically in the background.
+func (m *WaitlistMonitor) Start() {
+	runEvery("waitlist", m.Interval, func(now time.Time) error {
+		closed, err := m.bookings.ExpireOffers(now)
+		if closed > 0 {
+			log.Printf("Closed %d stale waitlist entries", closed)
+		}
+		return err
+	})
+}
@@ -12,18 +12,48 @@
 //   - Ticket booking and management
 //   - Administrative dashboard and user management
 //   - Airport and airline information management
+//   - Terminal information and live terminal load
+//   - Aircraft maintenance tracking with overdue alerts
+//   - Hangar allocation and hangar inspection reminders
+//   - Airport plot and space management
+//   - Shop and services directory with opening hours
+//   - Retail leases with monthly concession invoices
+//   - Route distances with block time and CO2 estimates
+//   - Flight times stored in UTC with local airport times
+//   - Airport and airline management with IATA/ICAO code validation
+//   - Reference data import from OurAirports/OpenFlights files (see cmd/import)
+//   - SSIM schedule import generating recurring flights
+//   - Recurring flight schedule templates rolled out over a rolling horizon
+//   - iCalendar export of passengers' trips with subscription feeds
+//   - Passenger manifests and PNL/ADL messages for ground handling
+//   - Encrypted travel documents, check-in and APIS (PAXLST) export
+//   - Booking records (PNR) with several passengers and flights per locator
+//   - Companion traveller profiles managed by one account
+//   - Overbooking limits, waitlists for full flights and denied boarding
+//   - Flight fares and card payments of bookings through a payment provider
+//   - Numbered invoices and credit notes with VAT breakdown as PDF and XRechnung/ZUGFeRD XML
 //
 // The server runs on port 8080 by default and provides endpoints under /api.
 package main
 
 import (
+	"log"
+	_ "time/tzdata" // Embedded time zone database for AIRPORT_TIMEZONE
+
 	"github.com/gin-gonic/gin"
 
 	_ "github.com/godror/godror" // Oracle database driver
 
+	"mindenairport/apis"
+	"mindenairport/booking"
 	"mindenairport/database"
 	"mindenairport/initializers"
+	"mindenairport/invoices"
+	"mindenairport/jobs"
 	"mindenairport/middleware"
+	"mindenairport/notifications"
+	"mindenairport/payments"
+	"mindenairport/planning"
 	"mindenairport/routers"
 )
 
@@ -55,6 +85,22 @@ func main() {
 		c.JSON(200, gin.H{"status": "healthy", "message": "MindenAirport API is running"})
 	})
 
+	// Resource planner shared by the shop directory, hangar allocation and flight schedules
+	planner := planning.NewPlanner(db)
+
+	// Booking records, looked up by guests and managed by the account that booked
+	bookings := booking.NewService(db, apis.NewService(db), notifications.NewService(db))
+
+	// Invoices of payments, refunds and excess baggage fees, numbered per year
+	invoicing := invoices.NewService(db, planner.Config.HomeAirport, planner.Config.Location)
+
+	// Card payments of held bookings through the configured payment provider
+	provider, err := payments.ProviderFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure payment provider: %v", err)
+	}
+	charges := payments.NewService(db, bookings, provider, invoicing)
+
 	// ======= PUBLIC ROUTES (no authentication required) =======
 
 	// Authentication routes - registration, login, password reset
@@ -65,11 +111,24 @@ func main() {
 	routers.AirportRoutes(apiRouter.Group("/airport"), db)
 	routers.FlightStatusRoutes(apiRouter.Group("/flightStatus"), db)
 	routers.FlightRoutes(apiRouter.Group("/flight"), db)
+	routers.FareRoutes(apiRouter.Group("/flight"), db, bookings)
+	routers.TerminalRoutes(apiRouter.Group("/terminal"), db)
+	routers.ShopRoutes(apiRouter.Group("/shops"), db, planner)
 
 	// Public baggage tracking - allows tracking without authentication
 	publicBaggage := apiRouter.Group("/baggage")
 	publicBaggage.GET("/track", routers.GetBaggageByTrackingNumber(db))
 
+	// Public booking lookup by locator and passenger last name
+	publicBookings := apiRouter.Group("/bookings")
+	publicBookings.GET("/lookup", routers.LookupBooking(db, bookings))
+
+	// Payment provider webhooks - authenticated by the provider's signature
+	apiRouter.POST("/payments/webhook/:provider", routers.HandlePaymentWebhook(charges))
+
+	// Calendar feeds - authenticated by the secret token in the URL
+	routers.CalendarRoutes(apiRouter.Group("/calendar"), db)
+
 	// ======= PROTECTED ROUTES (authentication required) =======
 
 	// Protected routes that require valid JWT token
@@ -77,6 +136,14 @@ func main() {
 	protected.Use(middleware.AuthMiddleware())
 	routers.TicketRoutes(protected.Group("/ticket"), db)
 	routers.BaggageRoutes(protected.Group("/baggage"), db)
+	routers.NotificationRoutes(protected.Group("/notifications"), db)
+	routers.TravelDocumentRoutes(protected.Group("/documents"), db, apis.NewService(db))
+	routers.BookingRoutes(protected.Group("/bookings"), db, bookings, charges)
+	routers.PaymentRoutes(protected.Group("/bookings"), db, bookings, charges)
+	routers.CompanionRoutes(protected.Group("/companions"), db)
+	routers.WaitlistRoutes(protected.Group("/waitlist"), db, bookings)
+	routers.InvoiceRoutes(protected.Group("/invoices"), invoicing)
+	routers.BillingAddressRoutes(protected.Group("/billing-address"), invoicing)
 
 	// ======= ADMIN ROUTES (authentication + admin role required) =======
 
@@ -85,6 +152,39 @@ func main() {
 	adminProtected.Use(middleware.AuthMiddleware())
 	routers.AdminRoutes(adminProtected, db)
 
+	// Maintenance log and due-date alerts, backed by a periodic check
+	maintenanceMonitor := jobs.NewMaintenanceMonitor(db)
+	maintenanceMonitor.Start()
+	routers.MaintenanceRoutes(adminProtected.Group("/maintenance"), db, maintenanceMonitor)
+
+	// Hangar allocation and inspection reminders, backed by a periodic check
+	inspectionMonitor := jobs.NewInspectionMonitor(db)
+	inspectionMonitor.Start()
+	routers.HangarRoutes(adminProtected.Group("/hangars"), db, planner, inspectionMonitor)
+
+	// Recurring flight schedules, generated over a rolling horizon
+	scheduleRollout := jobs.NewScheduleRollout(db, planner)
+	scheduleRollout.Start()
+	routers.ScheduleRoutes(adminProtected.Group("/schedules"), db, scheduleRollout)
+
+	// Overbooking limits and waitlists, with offers expired by a periodic check
+	waitlistMonitor := jobs.NewWaitlistMonitor(bookings)
+	waitlistMonitor.Start()
+	routers.OverbookingRoutes(adminProtected, db, bookings)
+
+	// Volunteers and denied boarding on oversold flights
+	routers.BoardingRoutes(adminProtected, db)
+
+	// Fares, payments and refunds, with unpaid holds released by a periodic check
+	holdMonitor := jobs.NewHoldMonitor(charges)
+	holdMonitor.Start()
+	routers.PaymentAdminRoutes(adminProtected, db, bookings, charges)
+
+	// Invoice search, credit notes and excess baggage fees, with missing invoices issued by a periodic check
+	invoiceMonitor := jobs.NewInvoiceMonitor(invoicing)
+	invoiceMonitor.Start()
+	routers.InvoiceAdminRoutes(adminProtected, db, invoicing)
+
 	// ======= PROTECTED AUTH ROUTES =======
 
 	// Protected authentication routes for logged-in users
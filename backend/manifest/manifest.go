@@ -0,0 +1,110 @@
+// Package manifest builds the passenger list of a flight for ground
+// handling: as JSON or CSV manifest, and as the IATA-style PNL (Passenger
+// Name List) and ADL (Additions and Deletions List) messages departure
+// control systems expect.
+package manifest
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/timezone"
+)
+
+const statusCheckedIn = "CHECKED_IN"
+
+// bookingClasses maps travel classes to the cabin codes used in PNL and ADL
+// messages. Classes not listed travel in economy (Y).
+var bookingClasses = map[int]string{
+	1: "F", // First Class
+	2: "J", // Business Class
+	3: "W", // Premium Economy
+	7: "J", // Business First
+	8: "F", // Suites
+}
+
+// BookingClass returns the cabin code of a travel class.
+func BookingClass(travelClassID int) string {
+	if class, ok := bookingClasses[travelClassID]; ok {
+		return class
+	}
+	return "Y"
+}
+
+// Get returns the manifest of a flight, or nil if the flight does not exist.
+func Get(db database.Database, flightID string) (*models.Manifest, error) {
+	flight, err := db.GetFlightByID(flightID)
+	if err != nil {
+		return nil, err
+	}
+	if flight.ID == "" {
+		return nil, nil
+	}
+
+	passengers, err := db.GetFlightManifest(flightID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := timezone.NewLocalizer(db).Location(flight.From)
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	m := &models.Manifest{
+		FlightID:     flight.ID,
+		FlightNumber: flight.FlightNumber,
+		From:         flight.From,
+		To:           flight.To,
+		Departure:    timezone.At(flight.ScheduledDeparture, loc),
+		Passengers:   []models.ManifestPassenger{},
+	}
+	for _, p := range passengers {
+		p.BookingClass = BookingClass(p.TravelClassID)
+		p.CheckedIn = p.Status == statusCheckedIn
+		m.Passengers = append(m.Passengers, p)
+
+		m.Total++
+		if p.CheckedIn {
+			m.CheckedIn++
+		}
+		m.Bags += p.Bags
+		m.BagWeightKg += p.BagWeightKg
+	}
+	return m, nil
+}
+
+// WriteCSV writes the passengers of a manifest as CSV with a header row.
+func WriteCSV(w io.Writer, m *models.Manifest) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{
+		"ticketId", "locator", "lastName", "firstName", "birthDate", "seatNumber", "travelClass",
+		"bookingClass", "status", "checkedIn", "bags", "bagWeightKg",
+	})
+	for _, p := range m.Passengers {
+		var birthDate string
+		if p.BirthDate != nil {
+			birthDate = p.BirthDate.Format("2006-01-02")
+		}
+		out.Write([]string{
+			p.TicketID,
+			p.Locator,
+			p.LastName,
+			p.FirstName,
+			birthDate,
+			p.SeatNumber,
+			p.TravelClass,
+			p.BookingClass,
+			p.Status,
+			strconv.FormatBool(p.CheckedIn),
+			strconv.Itoa(p.Bags),
+			strconv.FormatFloat(p.BagWeightKg, 'f', 2, 64),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
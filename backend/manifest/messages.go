@@ -0,0 +1,394 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+const (
+	typePNL = "PNL"
+	typeADL = "ADL"
+
+	maxLineLength   = 64 // Longest line of a teletype message
+	maxLinesPerPart = 60 // Longest part, including its header and end line
+)
+
+// classOrder is the order in which cabins are listed.
+var classOrder = []string{"F", "J", "W", "Y"}
+
+// ErrNoPNL is returned when an ADL is requested for a flight that has not
+// had a PNL sent yet.
+var ErrNoPNL = errors.New("no PNL has been sent for this flight")
+
+// Messenger generates PNL and ADL messages and records them as sent, so that
+// each ADL contains the changes since the list the handler last received.
+type Messenger struct {
+	db database.Database
+
+	// mu serializes messages, so that two messages for a flight neither get
+	// the same sequence number nor diff against the same list
+	mu sync.Mutex
+}
+
+// NewMessenger creates a messenger storing sent messages in db.
+func NewMessenger(db database.Database) *Messenger {
+	return &Messenger{db: db}
+}
+
+// Messages returns the messages sent for a flight, oldest first.
+func (s *Messenger) Messages(flightID string) ([]models.PassengerListMessage, error) {
+	messages, err := s.db.GetPassengerListMessages(flightID)
+	if messages == nil {
+		messages = []models.PassengerListMessage{}
+	}
+	return messages, err
+}
+
+// SendPNL generates the full passenger list of a flight and records it as
+// sent. A PNL may be sent again, e.g. after the handler lost its list; later
+// ADLs are computed against the newest one.
+func (s *Messenger) SendPNL(m *models.Manifest, now time.Time) (*models.PassengerListMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages, err := s.db.GetPassengerListMessages(m.FlightID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := Entries(m)
+	parts := PNL(m, entries)
+	message := &models.PassengerListMessage{
+		FlightID:   m.FlightID,
+		Type:       typePNL,
+		Sequence:   nextSequence(messages, typePNL),
+		SentAt:     now,
+		Parts:      len(parts),
+		Passengers: len(entries),
+		Added:      len(entries),
+		Content:    strings.Join(parts, "\n"),
+	}
+	if err := s.db.CreatePassengerListMessage(message, entries); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// SendADL generates the additions, deletions and changes since the last
+// message sent for a flight and records them as sent. It returns nil if the
+// passenger list has not changed, and ErrNoPNL if there is no list to
+// compare against yet.
+func (s *Messenger) SendADL(m *models.Manifest, now time.Time) (*models.PassengerListMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages, err := s.db.GetPassengerListMessages(m.FlightID)
+	if err != nil {
+		return nil, err
+	}
+	if nextSequence(messages, typePNL) == 1 {
+		return nil, ErrNoPNL
+	}
+
+	previous, err := s.db.GetPassengerListEntries(messages[len(messages)-1].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := Entries(m)
+	changes := Diff(previous, entries)
+	if changes.Empty() {
+		return nil, nil
+	}
+
+	parts := ADL(m, entries, changes)
+	message := &models.PassengerListMessage{
+		FlightID:   m.FlightID,
+		Type:       typeADL,
+		Sequence:   nextSequence(messages, typeADL),
+		SentAt:     now,
+		Parts:      len(parts),
+		Passengers: len(entries),
+		Added:      len(changes.Added),
+		Deleted:    len(changes.Deleted),
+		Changed:    len(changes.Changed),
+		Content:    strings.Join(parts, "\n"),
+	}
+	if err := s.db.CreatePassengerListMessage(message, entries); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// nextSequence returns the sequence number of the next message of a type.
+func nextSequence(messages []models.PassengerListMessage, kind string) int {
+	next := 1
+	for _, m := range messages {
+		if m.Type == kind && m.Sequence >= next {
+			next = m.Sequence + 1
+		}
+	}
+	return next
+}
+
+// Entries returns the passengers of a manifest as they appear in messages.
+func Entries(m *models.Manifest) []models.PassengerListEntry {
+	entries := make([]models.PassengerListEntry, 0, len(m.Passengers))
+	for _, p := range m.Passengers {
+		entries = append(entries, models.PassengerListEntry{
+			TicketID:     p.TicketID,
+			Surname:      messageName(p.LastName),
+			GivenName:    messageName(p.FirstName),
+			BookingClass: p.BookingClass,
+			Seat:         p.SeatNumber,
+			Locator:      p.Locator,
+		})
+	}
+	return entries
+}
+
+// Changes are the differences between two passenger lists. A passenger
+// moved to another cabin is deleted from the old and added to the new one.
+type Changes struct {
+	Added   []models.PassengerListEntry
+	Deleted []models.PassengerListEntry
+	Changed []models.PassengerListEntry // Name or seat changed, with the new values
+}
+
+// Empty reports whether the lists are equal.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Deleted) == 0 && len(c.Changed) == 0
+}
+
+// Diff returns the changes from the previous to the current passenger list.
+func Diff(previous, current []models.PassengerListEntry) Changes {
+	before := make(map[string]models.PassengerListEntry, len(previous))
+	for _, e := range previous {
+		before[e.TicketID] = e
+	}
+
+	var changes Changes
+	for _, e := range current {
+		old, ok := before[e.TicketID]
+		delete(before, e.TicketID)
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, e)
+		case old.BookingClass != e.BookingClass:
+			changes.Deleted = append(changes.Deleted, old)
+			changes.Added = append(changes.Added, e)
+		case old != e:
+			changes.Changed = append(changes.Changed, e)
+		}
+	}
+	for _, e := range previous {
+		if _, ok := before[e.TicketID]; ok {
+			changes.Deleted = append(changes.Deleted, e)
+		}
+	}
+	return changes
+}
+
+// PNL returns the parts of the passenger name list of a flight.
+//
+//	PNL
+//	LH100/15JAN MIN PART1
+//	-FRA002Y
+//	1MUELLER/HANS .L/T001 .R/SEAT 12A
+//	1SCHMIDT/ANNA .L/T002
+//	ENDPNL
+func PNL(m *models.Manifest, entries []models.PassengerListEntry) []string {
+	counts := classCounts(entries)
+
+	var lines []line
+	for _, class := range classOrder {
+		if counts[class] == 0 {
+			continue
+		}
+		header := classLine(m.To, counts[class], class)
+		lines = append(lines, line{text: header})
+		for _, e := range sortedEntries(entries, class) {
+			lines = appendName(lines, []string{header}, e)
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, line{text: "NIL"})
+	}
+	return paginate(typePNL, m, lines)
+}
+
+// ADL returns the parts of the additions and deletions list of a flight.
+// Every cabin with changes is listed with its new passenger count, followed
+// by the ADD, DEL and CHG sections.
+//
+//	ADL
+//	LH100/15JAN MIN PART1
+//	-FRA002Y
+//	ADD
+//	1SCHMIDT/ANNA .L/T002
+//	ENDADL
+func ADL(m *models.Manifest, entries []models.PassengerListEntry, changes Changes) []string {
+	counts := classCounts(entries)
+
+	var lines []line
+	for _, class := range classOrder {
+		sections := []struct {
+			name    string
+			entries []models.PassengerListEntry
+		}{
+			{"ADD", sortedEntries(changes.Added, class)},
+			{"DEL", sortedEntries(changes.Deleted, class)},
+			{"CHG", sortedEntries(changes.Changed, class)},
+		}
+		if len(sections[0].entries)+len(sections[1].entries)+len(sections[2].entries) == 0 {
+			continue
+		}
+
+		header := classLine(m.To, counts[class], class)
+		lines = append(lines, line{text: header})
+		for _, section := range sections {
+			if len(section.entries) == 0 {
+				continue
+			}
+			lines = append(lines, line{text: section.name, context: []string{header}})
+			for _, e := range section.entries {
+				lines = appendName(lines, []string{header, section.name}, e)
+			}
+		}
+	}
+	return paginate(typeADL, m, lines)
+}
+
+// line is a line of a message body together with the cabin header and
+// section it belongs to, which are repeated when a part ends before it.
+type line struct {
+	text    string
+	context []string
+}
+
+// appendName appends the name element of a passenger, continuing elements
+// that do not fit on the line on the next ones.
+func appendName(lines []line, context []string, e models.PassengerListEntry) []line {
+	name := "1" + e.Surname
+	if e.GivenName != "" {
+		name += "/" + e.GivenName
+	}
+	if len(name) > maxLineLength {
+		name = name[:maxLineLength]
+	}
+
+	reference := e.Locator
+	if reference == "" {
+		reference = e.TicketID
+	}
+	elements := []string{".L/" + reference}
+	if e.Seat != "" {
+		elements = append(elements, ".R/SEAT "+e.Seat)
+	}
+
+	current := name
+	for _, element := range elements {
+		if len(current)+1+len(element) > maxLineLength {
+			lines = append(lines, line{text: current, context: context})
+			current = element
+			continue
+		}
+		current += " " + element
+	}
+	return append(lines, line{text: current, context: context})
+}
+
+// paginate splits a message body into parts. Each part starts with the
+// message type and flight line; a cabin or section continuing from the
+// previous part repeats its header. All parts but the last end with ENDPARTn.
+func paginate(kind string, m *models.Manifest, lines []line) []string {
+	number := m.FlightNumber
+	if number == "" {
+		number = m.FlightID
+	}
+	local, err := time.Parse(time.RFC3339, m.Departure.Local)
+	if err != nil {
+		local = m.Departure.UTC
+	}
+	flightLine := fmt.Sprintf("%s/%s %s", number, strings.ToUpper(local.Format("02Jan")), m.From)
+
+	var parts []string
+	part := []string{kind, flightLine + " PART1"}
+	for _, l := range lines {
+		// Leave room for the end line
+		if len(part)+1 >= maxLinesPerPart {
+			part = append(part, fmt.Sprintf("ENDPART%d", len(parts)+1))
+			parts = append(parts, strings.Join(part, "\n"))
+			part = append([]string{kind, fmt.Sprintf("%s PART%d", flightLine, len(parts)+1)}, l.context...)
+		}
+		part = append(part, l.text)
+	}
+	part = append(part, "END"+kind)
+	return append(parts, strings.Join(part, "\n"))
+}
+
+// classLine returns the header of a cabin, e.g. "-FRA012Y".
+func classLine(destination string, count int, class string) string {
+	return fmt.Sprintf("-%s%03d%s", destination, count, class)
+}
+
+// classCounts returns the number of passengers per cabin.
+func classCounts(entries []models.PassengerListEntry) map[string]int {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[e.BookingClass]++
+	}
+	return counts
+}
+
+// sortedEntries returns the entries of a cabin ordered by name.
+func sortedEntries(entries []models.PassengerListEntry, class string) []models.PassengerListEntry {
+	var result []models.PassengerListEntry
+	for _, e := range entries {
+		if e.BookingClass == class {
+			result = append(result, e)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Surname != result[j].Surname {
+			return result[i].Surname < result[j].Surname
+		}
+		return result[i].GivenName < result[j].GivenName
+	})
+	return result
+}
+
+// transliterations replaces letters that teletype messages cannot carry.
+var transliterations = strings.NewReplacer(
+	"Ä", "AE", "Ö", "OE", "Ü", "UE", "ß", "SS", "ẞ", "SS", "Æ", "AE", "Œ", "OE", "Ø", "O", "Å", "A",
+	"À", "A", "Á", "A", "Â", "A", "Ã", "A", "Ç", "C", "È", "E", "É", "E", "Ê", "E", "Ë", "E",
+	"Ì", "I", "Í", "I", "Î", "I", "Ï", "I", "Ñ", "N", "Ò", "O", "Ó", "O", "Ô", "O", "Õ", "O",
+	"Ù", "U", "Ú", "U", "Û", "U", "Ý", "Y", "Ł", "L", "Š", "S", "Ž", "Z", "Č", "C",
+)
+
+// ASCIIName returns a name in upper case letters A-Z, as airline messages
+// carry them. Other letters are transliterated, single spaces between parts
+// are kept, and hyphens, apostrophes and other characters are dropped.
+func ASCIIName(name string) string {
+	name = transliterations.Replace(strings.ToUpper(name))
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' || r == ' ' {
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// messageName returns a name as written in PNL and ADL messages, without
+// spaces.
+func messageName(name string) string {
+	return strings.ReplaceAll(ASCIIName(name), " ", "")
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader names the header clients send to make a request
+	// safe to retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// maxIdempotencyKeyLength is the longest idempotency key accepted.
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyClaimTimeout is how long a request may run before another
+	// request with its key takes over, e.g. after the server crashed.
+	idempotencyClaimTimeout = 5 * time.Minute
+)
+
+// Idempotency makes POST requests safe to retry. Clients may send an
+// Idempotency-Key header with a unique value per request, scoped to the
+// authenticated user, so it must run after AuthMiddleware. Requests
+// without the header are passed through.
+//
+// The first request with a key runs as usual and its response is stored;
+// repeating the request with the same key replays that response with the
+// header "Idempotent-Replayed: true" instead of running it again.
+//
+// Returns:
+//   - HTTP 409 Conflict if the first request with the key is still running
+//   - HTTP 422 Unprocessable Entity if the key was used for a different request
+//
+// Responses with a 5xx status are not stored and the key is released, also
+// when the handler panics, so the request can be retried with the same key.
+// A request that never finished, e.g. because the server stopped, is taken
+// over by a retry after idempotencyClaimTimeout.
+func Idempotency(db database.Database) gin.HandlerFunc {
+	return idempotency(db, time.Now)
+}
+
+// idempotencyStore keeps the idempotency keys, see the methods of the same
+// name of database.Database.
+type idempotencyStore interface {
+	ClaimIdempotencyKey(scope, key, requestHash string, now, staleBefore time.Time) (bool, error)
+	GetIdempotencyKey(scope, key string) (*models.IdempotencyRecord, error)
+	SaveIdempotencyResponse(scope, key string, statusCode int, response string) error
+	DeleteIdempotencyKey(scope, key string) error
+}
+
+// idempotency implements Idempotency with the keys kept in db and the
+// current time taken from now.
+func idempotency(db idempotencyStore, now func() time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		userID, exists := c.Get("userID")
+		if key == "" || !exists {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must not be longer than 255 characters"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		scope := userID.(string)
+		claimedAt := now()
+		claimed, err := db.ClaimIdempotencyKey(scope, key, requestHash, claimedAt, claimedAt.Add(-idempotencyClaimTimeout))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+			c.Abort()
+			return
+		}
+
+		if !claimed {
+			record, err := db.GetIdempotencyKey(scope, key)
+			switch {
+			case err != nil:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+			case record == nil:
+				c.JSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key was just released, please retry"})
+			case record.RequestHash != requestHash:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			case record.StatusCode == 0:
+				c.JSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key is still in progress"})
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(record.StatusCode, "application/json; charset=utf-8", []byte(record.Response))
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Runs while a panic of the handler unwinds to the recovery middleware
+		finished := false
+		defer func() {
+			if !finished {
+				releaseIdempotencyKey(db, scope, key)
+			}
+		}()
+		c.Next()
+		finished = true
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			releaseIdempotencyKey(db, scope, key)
+			return
+		}
+		if err := db.SaveIdempotencyResponse(scope, key, status, recorder.body.String()); err != nil {
+			log.Printf("Error storing response for Idempotency-Key %s: %v", key, err)
+		}
+	}
+}
+
+// releaseIdempotencyKey deletes a claimed key, so the request can be
+// retried with it.
+func releaseIdempotencyKey(db idempotencyStore, scope, key string) {
+	if err := db.DeleteIdempotencyKey(scope, key); err != nil {
+		log.Printf("Error releasing Idempotency-Key %s: %v", key, err)
+	}
+}
+
+// responseRecorder keeps a copy of the response body written through it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes to the response and the copy.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// WriteString writes to the response and the copy.
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
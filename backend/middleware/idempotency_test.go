@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryStore keeps idempotency keys in memory with the semantics of the
+// stored procedures: a claim fails for a used key unless the request that
+// used it never finished and was claimed before staleBefore.
+type memoryStore struct {
+	records  map[string]models.IdempotencyRecord
+	claimErr error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]models.IdempotencyRecord)}
+}
+
+func (m *memoryStore) ClaimIdempotencyKey(scope, key, requestHash string, now, staleBefore time.Time) (bool, error) {
+	if m.claimErr != nil {
+		return false, m.claimErr
+	}
+	if record, ok := m.records[scope+"/"+key]; ok {
+		if record.StatusCode != 0 || !record.CreatedAt.Before(staleBefore) {
+			return false, nil
+		}
+	}
+	m.records[scope+"/"+key] = models.IdempotencyRecord{Scope: scope, Key: key, RequestHash: requestHash, CreatedAt: now}
+	return true, nil
+}
+
+func (m *memoryStore) GetIdempotencyKey(scope, key string) (*models.IdempotencyRecord, error) {
+	record, ok := m.records[scope+"/"+key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *memoryStore) SaveIdempotencyResponse(scope, key string, statusCode int, response string) error {
+	record := m.records[scope+"/"+key]
+	record.StatusCode, record.Response = statusCode, response
+	m.records[scope+"/"+key] = record
+	return nil
+}
+
+func (m *memoryStore) DeleteIdempotencyKey(scope, key string) error {
+	delete(m.records, scope+"/"+key)
+	return nil
+}
+
+// idempotencyTest is a router with the Idempotency middleware in front of
+// handlers that count how often they ran.
+type idempotencyTest struct {
+	router *gin.Engine
+	store  *memoryStore
+	now    time.Time
+	runs   int
+}
+
+func newIdempotencyTest() *idempotencyTest {
+	gin.SetMode(gin.TestMode)
+	test := &idempotencyTest{
+		router: gin.New(),
+		store:  newMemoryStore(),
+		now:    time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC),
+	}
+
+	test.router.Use(gin.Recovery())
+	test.router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("userID", user)
+		}
+	})
+	test.router.Use(idempotency(test.store, func() time.Time { return test.now }))
+
+	test.router.POST("/bookings", func(c *gin.Context) {
+		test.runs++
+		c.JSON(http.StatusCreated, gin.H{"run": test.runs})
+	})
+	test.router.POST("/payments", func(c *gin.Context) {
+		test.runs++
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "declined"})
+	})
+	test.router.POST("/fail", func(c *gin.Context) {
+		test.runs++
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "try again"})
+	})
+	test.router.POST("/panic", func(c *gin.Context) {
+		test.runs++
+		panic("handler failed")
+	})
+	return test
+}
+
+// post sends a request with the given user and Idempotency-Key; empty
+// values leave the header out.
+func (test *idempotencyTest) post(path, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	test.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	test := newIdempotencyTest()
+
+	first := test.post("/bookings", "u1", "key-1", `{"flight":"f1"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first request: status %d, want 201", first.Code)
+	}
+
+	replay := test.post("/bookings", "u1", "key-1", `{"flight":"f1"}`)
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", replay.Code, replay.Body, first.Code, first.Body)
+	}
+	if replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("replay is not marked with Idempotent-Replayed")
+	}
+	if test.runs != 1 {
+		t.Errorf("handler ran %d times, want 1", test.runs)
+	}
+
+	// Client errors are final as well
+	test.post("/payments", "u1", "key-2", "")
+	if replay := test.post("/payments", "u1", "key-2", ""); replay.Code != http.StatusPaymentRequired || test.runs != 2 {
+		t.Errorf("replay of 402 = %d after %d runs, want 402 after 2 runs", replay.Code, test.runs)
+	}
+}
+
+func TestIdempotencyRejectsReuse(t *testing.T) {
+	tests := []struct {
+		name       string
+		path, user string
+		body       string
+		wantStatus int
+		wantRuns   int
+	}{
+		{"different body", "/bookings", "u1", `{"flight":"f2"}`, http.StatusUnprocessableEntity, 1},
+		{"different path", "/payments", "u1", `{"flight":"f1"}`, http.StatusUnprocessableEntity, 1},
+		{"other user has its own keys", "/bookings", "u2", `{"flight":"f1"}`, http.StatusCreated, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newIdempotencyTest()
+			test.post("/bookings", "u1", "key-1", `{"flight":"f1"}`)
+
+			w := test.post(tt.path, tt.user, "key-1", tt.body)
+			if w.Code != tt.wantStatus || test.runs != tt.wantRuns {
+				t.Errorf("status %d after %d runs, want %d after %d runs", w.Code, test.runs, tt.wantStatus, tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestIdempotencyPassesThrough(t *testing.T) {
+	tests := []struct {
+		name string
+		user string
+		key  string
+	}{
+		{"without key", "u1", ""},
+		{"without user", "", "key-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newIdempotencyTest()
+			test.post("/bookings", tt.user, tt.key, "")
+			w := test.post("/bookings", tt.user, tt.key, "")
+			if w.Code != http.StatusCreated || test.runs != 2 || len(test.store.records) != 0 {
+				t.Errorf("status %d after %d runs with %d keys, want 201 after 2 runs without keys",
+					w.Code, test.runs, len(test.store.records))
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyLength(t *testing.T) {
+	tests := []struct {
+		length     int
+		wantStatus int
+	}{
+		{1, http.StatusCreated},
+		{maxIdempotencyKeyLength, http.StatusCreated},
+		{maxIdempotencyKeyLength + 1, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		test := newIdempotencyTest()
+		w := test.post("/bookings", "u1", strings.Repeat("k", tt.length), "")
+		if w.Code != tt.wantStatus {
+			t.Errorf("key of %d characters: status %d, want %d", tt.length, w.Code, tt.wantStatus)
+		}
+	}
+}
+
+func TestIdempotencyReleasesKeyOnFailure(t *testing.T) {
+	for _, path := range []string{"/fail", "/panic"} {
+		t.Run(path, func(t *testing.T) {
+			test := newIdempotencyTest()
+
+			first := test.post(path, "u1", "key-1", "")
+			if first.Code < http.StatusInternalServerError {
+				t.Fatalf("first request: status %d, want a server error", first.Code)
+			}
+			if len(test.store.records) != 0 {
+				t.Fatalf("key was not released: %+v", test.store.records)
+			}
+
+			test.post(path, "u1", "key-1", "")
+			if test.runs != 2 {
+				t.Errorf("handler ran %d times, want the retry to run it again", test.runs)
+			}
+		})
+	}
+}
+
+func TestIdempotencyStaleClaim(t *testing.T) {
+	tests := []struct {
+		name       string
+		age        time.Duration
+		wantStatus int
+		wantRuns   int
+	}{
+		{"just claimed", time.Second, http.StatusConflict, 0},
+		{"exactly at the timeout", idempotencyClaimTimeout, http.StatusConflict, 0},
+		{"past the timeout", idempotencyClaimTimeout + time.Second, http.StatusCreated, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newIdempotencyTest()
+			test.post("/bookings", "u1", "key-1", `{"flight":"f1"}`)
+			test.runs = 0
+
+			// Pretend the first request is still running since age
+			record := test.store.records["u1/key-1"]
+			record.StatusCode, record.Response = 0, ""
+			record.CreatedAt = test.now.Add(-tt.age)
+			test.store.records["u1/key-1"] = record
+
+			w := test.post("/bookings", "u1", "key-1", `{"flight":"f1"}`)
+			if w.Code != tt.wantStatus || test.runs != tt.wantRuns {
+				t.Errorf("status %d after %d runs, want %d after %d runs", w.Code, test.runs, tt.wantStatus, tt.wantRuns)
+			}
+			if tt.wantStatus == http.StatusCreated {
+				if record := test.store.records["u1/key-1"]; record.StatusCode != http.StatusCreated || !record.CreatedAt.Equal(test.now) {
+					t.Errorf("taken over claim = %+v, want the new response", record)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyStoreErrors(t *testing.T) {
+	test := newIdempotencyTest()
+	test.store.claimErr = errors.New("connection lost")
+	if w := test.post("/bookings", "u1", "key-1", ""); w.Code != http.StatusInternalServerError || test.runs != 0 {
+		t.Errorf("failed claim: status %d after %d runs, want 500 without running the handler", w.Code, test.runs)
+	}
+}
@@ -7,6 +7,7 @@ package models
 // facilities, and operational details.
 type Airport struct {
 	ID               string  `json:"id"`                          // IATA airport code (e.g., "LAX", "JFK")
+	ICAO             string  `json:"icao,omitempty"`              // ICAO airport code (e.g., "KLAX", "KJFK")
 	Name             string  `json:"name,omitempty"`              // Full airport name
 	Country          string  `json:"country"`                     // Country where airport is located
 	City             string  `json:"city"`                        // City where airport is located
@@ -16,3 +17,16 @@ type Airport struct {
 	Latitude         float64 `json:"latitude,omitempty"`          // GPS latitude coordinate
 	Longitude        float64 `json:"longitude,omitempty"`         // GPS longitude coordinate
 }
+
+// RouteEstimate is the great-circle distance between two airports together
+// with the estimated block time and emissions of a flight on that route.
+type RouteEstimate struct {
+	From              string  `json:"from"`
+	To                string  `json:"to"`
+	DistanceKm        float64 `json:"distanceKm"`
+	DistanceNM        float64 `json:"distanceNm"`    // Nautical miles
+	DistanceMiles     float64 `json:"distanceMiles"` // Statute miles, e.g. for loyalty mileage
+	Aircraft          string  `json:"aircraft"`      // Aircraft type the estimate is based on
+	BlockMinutes      int     `json:"blockMinutes"`  // Gate-to-gate time including taxiing
+	CO2PerPassengerKg float64 `json:"co2PerPassengerKg"`
+}
@@ -8,6 +8,7 @@ package models
 type Baggage struct {
 	ID              string  `json:"id"`                        // Unique identifier for the baggage item
 	AirportUserID   string  `json:"airportUserId"`             // ID of the passenger who owns the baggage
+	CompanionID     string  `json:"companionId,omitempty"`     // Companion of the owner travelling with the baggage
 	FlightID        string  `json:"flightId"`                  // ID of the flight this baggage is associated with
 	Size            int     `json:"size"`                      // Size category (1=carry-on, 2=checked, 3=oversized)
 	Weight          float64 `json:"weight"`                    // Weight of the baggage in pounds
@@ -0,0 +1,51 @@
+// Package models defines the denied boarding records of oversold flights in
+// the MindenAirport system.
+package models
+
+import "time"
+
+// DeniedBoarding records a passenger who volunteered to give up their seat
+// on an oversold flight, or who was denied boarding, with the compensation
+// paid.
+type DeniedBoarding struct {
+	ID                    string     `json:"id"`
+	TicketID              string     `json:"ticketId"`
+	FlightID              string     `json:"flightId"`
+	PassengerName         string     `json:"passengerName,omitempty"`
+	TicketStatus          string     `json:"ticketStatus,omitempty"`
+	Status                string     `json:"status"`                          // VOLUNTEER or DENIED
+	Voluntary             bool       `json:"voluntary"`                       // The passenger volunteered
+	RequestedCompensation *float64   `json:"requestedCompensation,omitempty"` // Amount the volunteer asked for
+	Compensation          *float64   `json:"compensation,omitempty"`          // Amount paid on denial
+	Currency              string     `json:"currency,omitempty"`
+	Note                  string     `json:"note,omitempty"`
+	CreatedAt             time.Time  `json:"createdAt"`
+	DecidedAt             *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy             string     `json:"decidedBy,omitempty"` // Staff member who denied boarding
+}
+
+// BoardingStatus shows staff at the gate whether a flight is oversold and
+// who volunteered to give up their seat.
+type BoardingStatus struct {
+	FlightID            string           `json:"flightId"`
+	Seats               int              `json:"seats"`
+	Booked              int              `json:"booked"`                        // Tickets holding a seat
+	CheckedIn           int              `json:"checkedIn"`                     // Tickets checked in
+	Oversold            int              `json:"oversold"`                      // Passengers who have to give up their seat
+	DefaultCompensation *float64         `json:"defaultCompensation,omitempty"` // Compensation due by distance
+	Currency            string           `json:"currency"`
+	Volunteers          []DeniedBoarding `json:"volunteers"`
+	Denied              []DeniedBoarding `json:"denied"`
+}
+
+// VolunteerRequest offers to give up the seat on an oversold flight.
+type VolunteerRequest struct {
+	Compensation *float64 `json:"compensation"` // Amount asked for, optional
+}
+
+// DeniedBoardingRequest denies a passenger boarding.
+type DeniedBoardingRequest struct {
+	TicketID     string   `json:"ticketId" binding:"required"`
+	Compensation *float64 `json:"compensation"` // Defaults to the volunteer's request or the amount due by distance
+	Note         string   `json:"note"`
+}
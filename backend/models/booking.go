@@ -0,0 +1,73 @@
+// Package models defines the booking records (PNR) that group passengers
+// and flights in the MindenAirport system.
+package models
+
+import "time"
+
+// Booking groups the tickets of one or more passengers on one or more
+// flights under a locator. The account that made the booking holds all its
+// tickets; each ticket names the passenger who travels on it. New bookings
+// are held until they are paid, and cancelled if the hold expires first.
+type Booking struct {
+	ID            string             `json:"id"`
+	Locator       string             `json:"locator"`                 // Six character booking reference, e.g. "K7PX2M"
+	AirportUserID string             `json:"airportUserId,omitempty"` // Account that made the booking
+	Status        string             `json:"status"`                  // HELD, CONFIRMED or CANCELLED
+	CreatedAt     time.Time          `json:"createdAt"`
+	HoldExpiresAt *time.Time         `json:"holdExpiresAt,omitempty"` // Deadline for the payment of a held booking
+	Total         float64            `json:"total"`                   // Price of the tickets that are not cancelled, calculated
+	Currency      string             `json:"currency"`
+	Passengers    []BookingPassenger `json:"passengers"`
+	Tickets       []Ticket           `json:"tickets"` // One ticket per passenger and flight, by departure
+}
+
+// BookingPassenger is a person travelling on a booking. Passengers need not
+// have an account; AirportUserID is empty for guests and companions.
+type BookingPassenger struct {
+	ID            string     `json:"id"`
+	FirstName     string     `json:"firstName"`
+	LastName      string     `json:"lastName"`
+	BirthDate     *time.Time `json:"birthDate,omitempty"`
+	AirportUserID string     `json:"airportUserId,omitempty"`
+	CompanionID   string     `json:"companionId,omitempty"` // Companion profile of the booking account
+}
+
+// BookingRequest creates a booking for the authenticated user.
+type BookingRequest struct {
+	Passengers []BookingPassengerRequest `json:"passengers" binding:"required,min=1,max=9,dive"`
+	Flights    []BookingFlightRequest    `json:"flights" binding:"required,min=1,max=8,dive"`
+}
+
+// BookingPassengerRequest names a passenger of a new booking. With Self the
+// account holder travels and the names are taken from the account; with
+// CompanionID one of the holder's companions travels.
+type BookingPassengerRequest struct {
+	Self        bool       `json:"self"`
+	CompanionID string     `json:"companionId"`
+	FirstName   string     `json:"firstName"`
+	LastName    string     `json:"lastName"`
+	BirthDate   *time.Time `json:"birthDate"`
+}
+
+// BookingFlightRequest is a flight of a new booking, booked for all
+// passengers in the same travel class.
+type BookingFlightRequest struct {
+	FlightID    string `json:"flightId" binding:"required"`
+	TravelClass int    `json:"travelClass" binding:"required,min=1"`
+}
+
+// BookingCheckInRequest optionally limits the check-in of a booking to one
+// of its flights.
+type BookingCheckInRequest struct {
+	FlightID string `json:"flightId"`
+}
+
+// BookingCheckIn is the outcome of checking in one ticket of a booking.
+type BookingCheckIn struct {
+	TicketID         string `json:"ticketId"`
+	PassengerID      string `json:"passengerId,omitempty"`
+	FlightID         string `json:"flightId"`
+	Status           string `json:"status"` // Ticket status after the attempt
+	TravelDocumentID string `json:"travelDocumentId,omitempty"`
+	Error            string `json:"error,omitempty"` // Why the ticket was not checked in
+}
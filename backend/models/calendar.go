@@ -0,0 +1,15 @@
+// Package models defines the data structures for calendar subscriptions
+// in the MindenAirport system.
+package models
+
+import "time"
+
+// CalendarSubscription is the secret feed of a user's trips that calendar
+// apps poll. Only a hash of the token is stored, so the URL is returned once
+// when the token is created.
+type CalendarSubscription struct {
+	AirportUserID string    `json:"airportUserId"`
+	CreatedAt     time.Time `json:"createdAt"`
+	URL           string    `json:"url,omitempty"`       // HTTPS feed URL, only set when the token is created
+	WebcalURL     string    `json:"webcalUrl,omitempty"` // Same feed with the webcal scheme, opens calendar apps directly
+}
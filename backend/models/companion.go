@@ -0,0 +1,18 @@
+// Package models defines the companion traveller profiles managed by an
+// account in the MindenAirport system.
+package models
+
+import "time"
+
+// Companion is a traveller without login of their own, e.g. a child, whose
+// profile is managed by an account. The account holder books tickets,
+// registers baggage and keeps travel documents for them and stays the owner
+// of all of it; the companion is the traveller.
+type Companion struct {
+	ID            string    `json:"id"`
+	AirportUserID string    `json:"airportUserId"` // Account managing the companion
+	FirstName     string    `json:"firstName" binding:"required"`
+	LastName      string    `json:"lastName" binding:"required"`
+	BirthDate     time.Time `json:"birthDate" binding:"required"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
@@ -0,0 +1,18 @@
+// Package models defines the CrewDuty data structure used for crew
+// rostering in the MindenAirport system.
+package models
+
+import "time"
+
+// CrewDuty is a flight a crew member is assigned to, together with the
+// flight times needed to check duty and rest limits.
+type CrewDuty struct {
+	AssignmentID       string    `json:"assignmentId"`       // ID of the FLIGHT_CREW assignment
+	CrewMemberID       string    `json:"crewMemberId"`       // ID of the assigned crew member
+	FlightID           string    `json:"flightId"`           // ID of the flight
+	Role               string    `json:"role"`               // Role of the crew member on this flight
+	From               string    `json:"from"`               // Departure airport code
+	To                 string    `json:"to"`                 // Arrival airport code
+	ScheduledDeparture time.Time `json:"scheduledDeparture"` // Scheduled departure time
+	ScheduledArrival   time.Time `json:"scheduledArrival"`   // Scheduled arrival time
+}
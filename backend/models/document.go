@@ -0,0 +1,36 @@
+// Package models defines the travel document data structures used for
+// Advance Passenger Information (APIS) in the MindenAirport system.
+package models
+
+import "time"
+
+// TravelDocument is a passport or identity card of a user or one of their
+// companions. All fields but the type are personal data and stored
+// encrypted.
+type TravelDocument struct {
+	ID             string    `json:"id"`
+	AirportUserID  string    `json:"airportUserId"`
+	CompanionID    string    `json:"companionId,omitempty"`             // Companion the document belongs to, empty for the user's own
+	Type           string    `json:"type" binding:"required"`           // P = passport, I = identity card
+	Number         string    `json:"number" binding:"required"`         // Document number
+	Nationality    string    `json:"nationality" binding:"required"`    // ISO 3166-1 alpha-3 code, e.g. "DEU"
+	IssuingCountry string    `json:"issuingCountry" binding:"required"` // ISO 3166-1 alpha-3 code
+	ExpiryDate     time.Time `json:"expiryDate" binding:"required"`
+	Sex            string    `json:"sex" binding:"required"` // M, F or X as printed in the document
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// CheckInRequest optionally names the travel document shown at check-in.
+type CheckInRequest struct {
+	TravelDocumentID string `json:"travelDocumentId"`
+}
+
+// Paxlst is a UN/EDIFACT PAXLST message with the passengers of a flight.
+type Paxlst struct {
+	FlightID   string   `json:"flightId"`
+	Passengers int      `json:"passengers"` // Passengers included in the message
+	Missing    []string `json:"missing"`    // Tickets without a valid travel document
+	Content    string   `json:"content"`    // EDIFACT interchange
+	Reference  string   `json:"reference"`  // Interchange control reference
+	Recipient  string   `json:"recipient"`  // Recipient of the interchange
+}
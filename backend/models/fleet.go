@@ -0,0 +1,14 @@
+// Package models defines the FleetSummary data structure for the fleet
+// overview of the MindenAirport system.
+package models
+
+// FleetSummary aggregates the planes of one airline by status.
+type FleetSummary struct {
+	AirlineID   string `json:"airlineId"`   // IATA code of the airline
+	AirlineName string `json:"airlineName"` // Full airline name
+	Planes      int    `json:"planes"`      // Total number of planes
+	Active      int    `json:"active"`      // Planes available for flights
+	Maintenance int    `json:"maintenance"` // Planes in maintenance
+	Inactive    int    `json:"inactive"`    // Planes taken out of service
+	ActiveSeats int    `json:"activeSeats"` // Passenger seats of all active planes
+}
@@ -7,18 +7,43 @@ import "time"
 // Flight represents a scheduled flight in the airport system.
 // This is the core entity for tracking flight operations, containing
 // all essential information about departure, arrival, crew, and aircraft.
+// All times are stored in UTC; LocalTimes adds them in the airports' time zones.
 type Flight struct {
-	ID                 string     `json:"id"`                        // Unique flight identifier
-	From               string     `json:"from"`                      // Origin airport code (IATA)
-	To                 string     `json:"to"`                        // Destination airport code (IATA)
-	PilotID            string     `json:"pilotId"`                   // ID of the assigned pilot
-	PlaneID            string     `json:"planeId"`                   // ID of the assigned aircraft
-	TerminalID         string     `json:"terminalId"`                // ID of the departure terminal
-	StatusID           int        `json:"statusId"`                  // Current flight status (references FlightStatus)
-	ScheduledDeparture time.Time  `json:"scheduledDeparture"`        // Planned departure time
-	ActualDeparture    *time.Time `json:"actualDeparture,omitempty"` // Actual departure time (if departed)
-	ScheduledArrival   time.Time  `json:"scheduledArrival"`          // Planned arrival time
-	ActualArrival      *time.Time `json:"actualArrival,omitempty"`   // Actual arrival time (if arrived)
-	Gate               string     `json:"gate,omitempty"`            // Assigned departure gate
-	BaggageClaim       string     `json:"baggageClaim,omitempty"`    // Baggage claim area for arrival
+	ID                 string     `json:"id"`                         // Unique flight identifier
+	FlightNumber       string     `json:"flightNumber,omitempty"`     // Commercial flight number (e.g. "LH123")
+	From               string     `json:"from"`                       // Origin airport code (IATA)
+	To                 string     `json:"to"`                         // Destination airport code (IATA)
+	PilotID            string     `json:"pilotId"`                    // ID of the assigned pilot, empty until crew is rostered
+	PlaneID            string     `json:"planeId"`                    // ID of the assigned aircraft
+	TerminalID         string     `json:"terminalId"`                 // ID of the departure terminal
+	StatusID           int        `json:"statusId"`                   // Current flight status (references FlightStatus)
+	ScheduledDeparture time.Time  `json:"scheduledDeparture"`         // Planned departure time
+	ActualDeparture    *time.Time `json:"actualDeparture,omitempty"`  // Actual departure time (if departed)
+	ScheduledArrival   time.Time  `json:"scheduledArrival"`           // Planned arrival time
+	ActualArrival      *time.Time `json:"actualArrival,omitempty"`    // Actual arrival time (if arrived)
+	Gate               string     `json:"gate,omitempty"`             // Assigned departure gate
+	BaggageClaim       string     `json:"baggageClaim,omitempty"`     // Baggage claim area for arrival
+	TemplateID         string     `json:"templateId,omitempty"`       // Schedule template the flight was generated from
+	TemplateRevision   int        `json:"templateRevision,omitempty"` // Template revision the flight follows
+
+	DistanceKm        float64 `json:"distanceKm,omitempty"`        // Great-circle distance of the route, calculated
+	CO2PerPassengerKg float64 `json:"co2PerPassengerKg,omitempty"` // Estimated CO2 emissions per passenger, calculated
+
+	LocalTimes *FlightTimes `json:"localTimes,omitempty"` // Times at origin and destination, calculated
+}
+
+// LocalTime is an instant together with the wall clock time at an airport.
+type LocalTime struct {
+	UTC      time.Time `json:"utc"`
+	Local    string    `json:"local,omitempty"`    // RFC 3339 with the airport's UTC offset
+	Timezone string    `json:"timezone,omitempty"` // IANA time zone of the airport
+}
+
+// FlightTimes are the times of a flight in local time: departures at the
+// origin, arrivals at the destination.
+type FlightTimes struct {
+	ScheduledDeparture LocalTime  `json:"scheduledDeparture"`
+	ActualDeparture    *LocalTime `json:"actualDeparture,omitempty"`
+	ScheduledArrival   LocalTime  `json:"scheduledArrival"`
+	ActualArrival      *LocalTime `json:"actualArrival,omitempty"`
 }
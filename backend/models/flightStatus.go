@@ -1,5 +1,18 @@
 package models
 
+// Flight status IDs as seeded in the FLIGHT_STATUS table.
+const (
+	FlightStatusScheduled = 1
+	FlightStatusBoarding  = 2
+	FlightStatusDeparted  = 3
+	FlightStatusArrived   = 4
+	FlightStatusDelayed   = 5
+	FlightStatusCancelled = 6
+	FlightStatusDiverted  = 7
+	FlightStatusCheckIn   = 8
+	FlightStatusFinalCall = 9
+)
+
 type FlightStatus struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
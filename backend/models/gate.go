@@ -0,0 +1,14 @@
+// Package models defines the Gate data structure for departure gate and
+// stand planning in the MindenAirport system.
+package models
+
+// Gate represents a departure gate and its aircraft stand.
+// Flights reference gates through Flight.Gate, which holds the gate ID.
+type Gate struct {
+	ID              string `json:"id"`               // Gate designator shown to passengers (e.g. "A1")
+	TerminalID      string `json:"terminalId"`       // ID of the terminal the gate belongs to
+	MaxAircraftCode string `json:"maxAircraftCode"`  // Largest ICAO aerodrome reference code (A-F) the stand accepts
+	StandType       string `json:"standType"`        // CONTACT (jet bridge) or REMOTE (bus boarding)
+	Area            string `json:"area"`             // SCHENGEN or NON_SCHENGEN passport control area
+	Status          string `json:"status,omitempty"` // Current status (ACTIVE, MAINTENANCE, CLOSED)
+}
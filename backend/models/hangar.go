@@ -0,0 +1,32 @@
+// Package models defines the data structures for hangar allocation and
+// inspection tracking in the MindenAirport system.
+package models
+
+import "time"
+
+// HangarOccupancy describes a hangar together with the planes parked in it
+// and the remaining space.
+type HangarOccupancy struct {
+	Hangar
+	Planes     []Plane  `json:"planes"`
+	UsedSqFt   float64  `json:"usedSqFt"`   // Floor space taken by planes of known aircraft types
+	FreeSqFt   float64  `json:"freeSqFt"`   // Remaining floor space
+	FreeSlots  int      `json:"freeSlots"`  // Number of planes that can still be parked
+	Unmeasured []string `json:"unmeasured"` // Planes whose model is unknown and has no known footprint
+}
+
+// InspectionReminder reports a hangar whose safety inspection is due or overdue.
+type InspectionReminder struct {
+	HangarID       string    `json:"hangarId"`
+	PlotID         string    `json:"plotId"`
+	HangarStatus   string    `json:"hangarStatus"`
+	NextInspection time.Time `json:"nextInspection"`
+	State          string    `json:"state"` // DUE or OVERDUE, see MaintenanceStateDue
+	DaysRemaining  int       `json:"daysRemaining"`
+}
+
+// InspectionReport is the result of a hangar inspection due-date check.
+type InspectionReport struct {
+	CheckedAt time.Time            `json:"checkedAt"`
+	Reminders []InspectionReminder `json:"reminders"`
+}
@@ -0,0 +1,63 @@
+// Package models defines the reports of bulk data imports in the
+// MindenAirport system.
+package models
+
+// ImportReport summarizes a bulk import of airports, airlines or scheduled
+// flights. In a dry run nothing is written and the report shows what the
+// import would change.
+type ImportReport struct {
+	Kind      string         `json:"kind"` // "airports", "airlines" or "flights"
+	DryRun    bool           `json:"dryRun"`
+	Read      int            `json:"read"`    // Records read from the file
+	Ignored   int            `json:"ignored"` // Records without data to import, e.g. airports without IATA code
+	Unchanged int            `json:"unchanged"`
+	Created   []ImportChange `json:"created"`
+	Updated   []ImportChange `json:"updated"`
+	Skipped   []ImportSkip   `json:"skipped"`             // Records that failed validation
+	Conflicts []ImportSkip   `json:"conflicts,omitempty"` // Valid records that clash with existing data
+}
+
+// ImportChange is a record that an import creates or updates. Changes lists
+// the updated fields; it is empty for new records.
+type ImportChange struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is the old and new value of a single field.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ImportSkip is a record of the import file that was not imported.
+type ImportSkip struct {
+	Line   int    `json:"line"`
+	ID     string `json:"id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// AirportImportRequest names the files of an airport import in the reference
+// data directory. Countries and Timezones are optional.
+type AirportImportRequest struct {
+	File      string `json:"file" binding:"required"` // OurAirports airports.csv
+	Countries string `json:"countries,omitempty"`     // OurAirports countries.csv
+	Timezones string `json:"timezones,omitempty"`     // OpenFlights airports.dat
+	DryRun    bool   `json:"dryRun"`
+}
+
+// AirlineImportRequest names the OpenFlights airlines.dat file of an airline
+// import in the reference data directory.
+type AirlineImportRequest struct {
+	File   string `json:"file" binding:"required"`
+	DryRun bool   `json:"dryRun"`
+}
+
+// SSIMImportRequest names the SSIM schedule file of a flight import in the
+// reference data directory.
+type SSIMImportRequest struct {
+	File   string `json:"file" binding:"required"`
+	DryRun bool   `json:"dryRun"`
+}
@@ -0,0 +1,189 @@
+// Package models defines invoices, credit notes and excess baggage fees in
+// the MindenAirport system.
+package models
+
+import "time"
+
+// Invoice is a numbered invoice or credit note of a paid booking, a refund
+// or an excess baggage fee. Numbers are gap-free per calendar year. Prices
+// are gross; all amounts of a credit note are positive.
+type Invoice struct {
+	ID             string         `json:"id"`
+	Number         string         `json:"number"` // e.g. "MA-2025-000042"
+	Type           string         `json:"type"`   // INVOICE or CREDIT_NOTE
+	IssuerID       string         `json:"issuerId"`
+	AirportUserID  string         `json:"airportUserId"` // Account the invoice is addressed to
+	BookingID      string         `json:"bookingId,omitempty"`
+	Locator        string         `json:"locator,omitempty"`
+	PaymentID      string         `json:"paymentId,omitempty"`
+	RefundID       string         `json:"refundId,omitempty"`
+	BaggageFeeID   string         `json:"baggageFeeId,omitempty"`
+	CorrectsID     string         `json:"correctsId,omitempty"`     // Invoice a credit note corrects
+	CorrectsNumber string         `json:"correctsNumber,omitempty"` // Number of the corrected invoice
+	IssuedAt       time.Time      `json:"issuedAt"`
+	ServiceDate    time.Time      `json:"serviceDate"` // Date of the first flight or of the fee, in local time
+	Currency       string         `json:"currency"`
+	NetAmount      float64        `json:"netAmount"`
+	VATAmount      float64        `json:"vatAmount"`
+	GrossAmount    float64        `json:"grossAmount"`
+	CreditedAmount float64        `json:"creditedAmount"` // Gross amount of the credit notes correcting an invoice
+	PaymentMethod  string         `json:"paymentMethod"`  // CARD or CASH
+	CardLast4      string         `json:"cardLast4,omitempty"`
+	Buyer          BillingAddress `json:"buyer"` // Billing address at the time of issue
+	Note           string         `json:"note,omitempty"`
+	CreatedBy      string         `json:"createdBy,omitempty"` // Staff member, empty for invoices issued automatically
+	Lines          []InvoiceLine  `json:"lines"`
+	VATBreakdown   []VATBreakdown `json:"vatBreakdown"` // Calculated from the lines
+}
+
+// InvoiceLine is a ticket or fee billed on an invoice.
+type InvoiceLine struct {
+	LineNo       int     `json:"lineNo"`
+	Description  string  `json:"description"`
+	Quantity     float64 `json:"quantity"`
+	NetAmount    float64 `json:"netAmount"`
+	VATCategory  string  `json:"vatCategory"` // S (standard rate) or E (exempt)
+	VATRate      float64 `json:"vatRate"`     // Percent
+	VATAmount    float64 `json:"vatAmount"`
+	GrossAmount  float64 `json:"grossAmount"`
+	TicketID     string  `json:"ticketId,omitempty"`
+	BaggageFeeID string  `json:"baggageFeeId,omitempty"`
+	CorrectsLine int     `json:"correctsLine,omitempty"` // Line of the corrected invoice a credit note line pays back
+}
+
+// VATBreakdown sums the lines of an invoice with the same VAT category and
+// rate.
+type VATBreakdown struct {
+	Category        string  `json:"category"`
+	Rate            float64 `json:"rate"`
+	NetAmount       float64 `json:"netAmount"`
+	VATAmount       float64 `json:"vatAmount"`
+	GrossAmount     float64 `json:"grossAmount"`
+	ExemptionReason string  `json:"exemptionReason,omitempty"`
+}
+
+// InvoiceIssuer holds the legal details of the airport printed on invoices.
+// Changes add a new version valid from then on, so invoices issued earlier
+// keep the details they were issued with.
+type InvoiceIssuer struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Street            string    `json:"street"`
+	Postcode          string    `json:"postcode"`
+	City              string    `json:"city"`
+	Country           string    `json:"country"` // ISO 3166-1 alpha-2 code, e.g. "DE"
+	VATID             string    `json:"vatId,omitempty"`
+	TaxNumber         string    `json:"taxNumber,omitempty"`
+	RegisterCourt     string    `json:"registerCourt,omitempty"`
+	RegisterNumber    string    `json:"registerNumber,omitempty"`
+	ManagingDirectors string    `json:"managingDirectors,omitempty"`
+	ContactName       string    `json:"contactName"`
+	Email             string    `json:"email"`
+	Phone             string    `json:"phone"`
+	IBAN              string    `json:"iban,omitempty"`
+	BIC               string    `json:"bic,omitempty"`
+	BankName          string    `json:"bankName,omitempty"`
+	ValidFrom         time.Time `json:"validFrom"`
+	CreatedBy         string    `json:"createdBy,omitempty"`
+}
+
+// InvoiceIssuerRequest sets the legal details of the airport. VAT ID or
+// tax number is required.
+type InvoiceIssuerRequest struct {
+	Name              string     `json:"name" binding:"required,max=255"`
+	Street            string     `json:"street" binding:"required,max=255"`
+	Postcode          string     `json:"postcode" binding:"required,max=20"`
+	City              string     `json:"city" binding:"required,max=100"`
+	Country           string     `json:"country" binding:"required,len=2"`
+	VATID             string     `json:"vatId" binding:"max=20"`
+	TaxNumber         string     `json:"taxNumber" binding:"max=30"`
+	RegisterCourt     string     `json:"registerCourt" binding:"max=100"`
+	RegisterNumber    string     `json:"registerNumber" binding:"max=30"`
+	ManagingDirectors string     `json:"managingDirectors" binding:"max=255"`
+	ContactName       string     `json:"contactName" binding:"required,max=100"`
+	Email             string     `json:"email" binding:"required,email"`
+	Phone             string     `json:"phone" binding:"required,max=50"`
+	IBAN              string     `json:"iban" binding:"max=34"`
+	BIC               string     `json:"bic" binding:"max=11"`
+	BankName          string     `json:"bankName" binding:"max=100"`
+	ValidFrom         *time.Time `json:"validFrom"` // Defaults to now
+}
+
+// BillingAddress is the address invoices of a user are issued to. Users
+// without one are invoiced by name and email of their account.
+type BillingAddress struct {
+	AirportUserID  string     `json:"airportUserId,omitempty"`
+	Company        string     `json:"company,omitempty"`
+	Name           string     `json:"name"`
+	Street         string     `json:"street,omitempty"`
+	Postcode       string     `json:"postcode,omitempty"`
+	City           string     `json:"city,omitempty"`
+	Country        string     `json:"country,omitempty"` // ISO 3166-1 alpha-2 code
+	VATID          string     `json:"vatId,omitempty"`
+	BuyerReference string     `json:"buyerReference,omitempty"` // e.g. the Leitweg-ID of a public buyer
+	Email          string     `json:"email,omitempty"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+}
+
+// BillingAddressRequest sets the billing address of the authenticated user.
+type BillingAddressRequest struct {
+	Company        string `json:"company" binding:"max=255"`
+	Name           string `json:"name" binding:"required,max=255"`
+	Street         string `json:"street" binding:"max=255"`
+	Postcode       string `json:"postcode" binding:"max=20"`
+	City           string `json:"city" binding:"max=100"`
+	Country        string `json:"country" binding:"max=2"`
+	VATID          string `json:"vatId" binding:"max=20"`
+	BuyerReference string `json:"buyerReference" binding:"max=100"`
+	Email          string `json:"email" binding:"max=255"`
+}
+
+// InvoiceFilter narrows the admin invoice search. Empty fields are ignored.
+type InvoiceFilter struct {
+	Number        string
+	Locator       string
+	AirportUserID string
+	Customer      string     // Part of name, company or email of the buyer
+	Type          string     // INVOICE or CREDIT_NOTE
+	From          *time.Time // Issued at or after
+	To            *time.Time // Issued before
+}
+
+// CreditNoteRequest cancels what is left of an invoice with a credit note.
+type CreditNoteRequest struct {
+	Reason  string `json:"reason" binding:"required,max=500"`
+	Reissue bool   `json:"reissue"` // Issue a new invoice to the current billing address
+}
+
+// BaggageFee is a fee charged at the counter for a bag that is too heavy
+// or oversized.
+type BaggageFee struct {
+	ID             string    `json:"id"`
+	BaggageID      string    `json:"baggageId"`
+	TrackingNumber string    `json:"trackingNumber,omitempty"`
+	AirportUserID  string    `json:"airportUserId"`
+	FlightID       string    `json:"flightId"`
+	Reason         string    `json:"reason"` // OVERWEIGHT or OVERSIZE
+	Weight         float64   `json:"weight"` // Pounds
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+	PaymentMethod  string    `json:"paymentMethod"` // CARD or CASH
+	CardLast4      string    `json:"cardLast4,omitempty"`
+	PaidAt         time.Time `json:"paidAt"`
+	CreatedBy      string    `json:"createdBy,omitempty"`
+}
+
+// BaggageFeeRequest charges the excess baggage fee of a bag.
+type BaggageFeeRequest struct {
+	Amount        *float64 `json:"amount"` // Defaults to the configured fee
+	PaymentMethod string   `json:"paymentMethod" binding:"required"`
+	CardLast4     string   `json:"cardLast4"`
+}
+
+// InvoiceSource is a payment, refund or excess baggage fee an invoice or
+// credit note is issued for.
+type InvoiceSource struct {
+	Type      string // PAYMENT, REFUND or BAGGAGE_FEE
+	ID        string
+	PaymentID string // Payment of a refund
+}
@@ -0,0 +1,70 @@
+// Package models defines the data structures for retail leases and
+// concession revenue in the MindenAirport system.
+package models
+
+import "time"
+
+// Lease is the concession contract of a shop for the plot it occupies. The
+// shop pays a fixed monthly rent per square foot of the plot plus a share
+// of its reported turnover.
+type Lease struct {
+	ID              string     `json:"id"`
+	ShopID          string     `json:"shopId"`
+	ShopName        string     `json:"shopName,omitempty"`
+	PlotID          string     `json:"plotId"`
+	AreaSqFt        float64    `json:"areaSqFt,omitempty"` // Area of the plot, taken from the PLOT table
+	StartDate       time.Time  `json:"startDate"`
+	EndDate         *time.Time `json:"endDate,omitempty"` // Last day of the contract, open-ended if unset
+	RentPerSqFt     float64    `json:"rentPerSqFt"`       // Fixed monthly rent per square foot in USD
+	TurnoverPercent float64    `json:"turnoverPercent"`   // Share of the monthly turnover in percent
+}
+
+// LeaseTurnover is the turnover a shop reported for one month.
+type LeaseTurnover struct {
+	LeaseID string  `json:"leaseId"`
+	Month   string  `json:"month"` // YYYY-MM
+	Amount  float64 `json:"amount" binding:"min=0"`
+}
+
+// LeaseInvoice is the monthly concession invoice of a lease. Amounts are in USD.
+type LeaseInvoice struct {
+	ID           string    `json:"id"`
+	LeaseID      string    `json:"leaseId"`
+	ShopID       string    `json:"shopId"`
+	ShopName     string    `json:"shopName,omitempty"`
+	Month        string    `json:"month"` // YYYY-MM
+	AreaSqFt     float64   `json:"areaSqFt"`
+	FixedRent    float64   `json:"fixedRent"`          // Rent for the days of the month covered by the lease
+	Turnover     *float64  `json:"turnover,omitempty"` // Reported turnover, unset if not reported yet
+	TurnoverRent float64   `json:"turnoverRent"`
+	Total        float64   `json:"total"`
+	IssuedAt     time.Time `json:"issuedAt"`
+}
+
+// RevenueByMonth is the concession revenue invoiced for one month.
+type RevenueByMonth struct {
+	Month        string  `json:"month"`
+	FixedRent    float64 `json:"fixedRent"`
+	TurnoverRent float64 `json:"turnoverRent"`
+	Total        float64 `json:"total"`
+}
+
+// RevenueByShop is the concession revenue invoiced to one shop.
+type RevenueByShop struct {
+	ShopID   string  `json:"shopId"`
+	ShopName string  `json:"shopName"`
+	Invoices int     `json:"invoices"`
+	Total    float64 `json:"total"`
+}
+
+// ConcessionRevenue reports the concession revenue of the months from From
+// to To (both YYYY-MM, inclusive).
+type ConcessionRevenue struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	FixedRent    float64          `json:"fixedRent"`
+	TurnoverRent float64          `json:"turnoverRent"`
+	Total        float64          `json:"total"`
+	Months       []RevenueByMonth `json:"months"`
+	Shops        []RevenueByShop  `json:"shops"`
+}
@@ -0,0 +1,36 @@
+// Package models defines the data structures for maintenance due-date
+// tracking in the MindenAirport system.
+package models
+
+import "time"
+
+// Maintenance states of a plane derived from its next maintenance date.
+const (
+	MaintenanceStateDue     = "DUE"     // The next maintenance is within the warning period
+	MaintenanceStateOverdue = "OVERDUE" // The next maintenance date has passed
+)
+
+// MaintenanceDue is the next maintenance deadline of a plane, taken from its
+// latest maintenance record.
+type MaintenanceDue struct {
+	PlaneID         string    `json:"planeId"`
+	PlaneName       string    `json:"planeName,omitempty"`
+	Model           string    `json:"model"`
+	PlaneStatus     string    `json:"planeStatus"`
+	NextMaintenance time.Time `json:"nextMaintenance"`
+}
+
+// MaintenanceAlert reports a plane whose maintenance is due or overdue and
+// the upcoming flights that would be affected.
+type MaintenanceAlert struct {
+	MaintenanceDue
+	State           string   `json:"state"`           // DUE or OVERDUE
+	StatusChanged   bool     `json:"statusChanged"`   // Set if the plane was just taken out of service
+	AffectedFlights []string `json:"affectedFlights"` // Flights scheduled after the deadline
+}
+
+// MaintenanceReport is the result of a maintenance due-date check.
+type MaintenanceReport struct {
+	CheckedAt time.Time          `json:"checkedAt"`
+	Alerts    []MaintenanceAlert `json:"alerts"`
+}
@@ -0,0 +1,70 @@
+// Package models defines the data structures for passenger manifests and
+// airline passenger list messages in the MindenAirport system.
+package models
+
+import "time"
+
+// ManifestPassenger is a passenger holding a ticket for a flight that is
+// not cancelled.
+type ManifestPassenger struct {
+	TicketID      string     `json:"ticketId"`
+	AirportUserID string     `json:"airportUserId,omitempty"` // Account of the traveller or managing the companion, empty for guests
+	CompanionID   string     `json:"companionId,omitempty"`   // Companion travelling on the ticket
+	Locator       string     `json:"locator,omitempty"`       // Booking the ticket was issued for
+	FirstName     string     `json:"firstName"`
+	LastName      string     `json:"lastName"`
+	BirthDate     *time.Time `json:"birthDate,omitempty"`
+	SeatNumber    string     `json:"seatNumber,omitempty"`
+	TravelClassID int        `json:"travelClassId,omitempty"`
+	TravelClass   string     `json:"travelClass,omitempty"`
+	BookingClass  string     `json:"bookingClass"` // Cabin code used in PNL/ADL messages (F, J, W, Y)
+	Status        string     `json:"status"`       // Ticket status (CONFIRMED, CHECKED_IN)
+	CheckedIn     bool       `json:"checkedIn"`
+	Bags          int        `json:"bags"`        // Checked bags of the passenger on this flight
+	BagWeightKg   float64    `json:"bagWeightKg"` // Total weight of the checked bags
+
+	TravelDocumentID string `json:"travelDocumentId,omitempty"` // Document shown at check-in
+}
+
+// Manifest is the passenger list of a flight.
+type Manifest struct {
+	FlightID     string              `json:"flightId"`
+	FlightNumber string              `json:"flightNumber,omitempty"`
+	From         string              `json:"from"`
+	To           string              `json:"to"`
+	Departure    LocalTime           `json:"departure"` // Scheduled departure at the origin
+	Passengers   []ManifestPassenger `json:"passengers"`
+	Total        int                 `json:"total"`
+	CheckedIn    int                 `json:"checkedIn"`
+	Bags         int                 `json:"bags"`
+	BagWeightKg  float64             `json:"bagWeightKg"`
+}
+
+// PassengerListMessage is a PNL (Passenger Name List) or ADL (Additions and
+// Deletions List) sent for a flight. Content is only set when the message
+// is generated.
+type PassengerListMessage struct {
+	ID         string    `json:"id"`
+	FlightID   string    `json:"flightId"`
+	Type       string    `json:"type"`     // PNL or ADL
+	Sequence   int       `json:"sequence"` // Number of the message of its type for the flight
+	SentAt     time.Time `json:"sentAt"`
+	Parts      int       `json:"parts"`      // Number of message parts
+	Passengers int       `json:"passengers"` // Passengers on the list after the message
+	Added      int       `json:"added,omitempty"`
+	Deleted    int       `json:"deleted,omitempty"`
+	Changed    int       `json:"changed,omitempty"`
+	Content    string    `json:"content,omitempty"`
+}
+
+// PassengerListEntry is a passenger as sent in a PNL or ADL. The entries of
+// the latest message are the list the handler knows, which the next ADL is
+// computed against.
+type PassengerListEntry struct {
+	TicketID     string `json:"ticketId"`
+	Surname      string `json:"surname"`
+	GivenName    string `json:"givenName,omitempty"`
+	BookingClass string `json:"bookingClass"`
+	Seat         string `json:"seat,omitempty"`
+	Locator      string `json:"locator,omitempty"` // Booking locator, sent instead of the ticket ID
+}
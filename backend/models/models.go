@@ -85,8 +85,9 @@ type Plot struct {
 	AreaSqFt           float64    `json:"areaSqFt,omitempty"`
 	Status             string     `json:"status,omitempty"`
 	LastMaintenance    *time.Time `json:"lastMaintenance,omitempty"`
-	MaxWeightCapacity  float64    `json:"maxWeightCapacity,omitempty"`
+	MaxWeightCapacity  float64    `json:"maxWeightCapacity,omitempty"` // Weight the plot can carry in pounds
 	UtilitiesAvailable string     `json:"utilitiesAvailable,omitempty"`
+	TerminalID         string     `json:"terminalId,omitempty"` // Terminal building the plot lies in, if any
 }
 
 type PlotType struct {
@@ -124,6 +125,7 @@ type Terminal struct {
 	FloorCount   int    `json:"floorCount,omitempty"`
 	Services     string `json:"services,omitempty"`
 	OpeningHours string `json:"openingHours,omitempty"`
+	PlotID       string `json:"plotId,omitempty"`
 }
 
 type AirportUser struct {
@@ -0,0 +1,15 @@
+// Package models defines the notification data structures used to inform
+// passengers about changes to their flights in the MindenAirport system.
+package models
+
+// NotificationPreference stores how a user wants to be informed about
+// gate changes, delays and boarding calls for the flights they hold tickets for.
+// Users without a stored preference receive e-mails in the default language.
+type NotificationPreference struct {
+	AirportUserID string `json:"airportUserId"`          // ID of the user these preferences belong to
+	Language      string `json:"language"`               // Message language ("de" or "en")
+	Email         bool   `json:"email"`                  // Send notifications by e-mail
+	SMS           bool   `json:"sms"`                    // Send notifications by SMS to the user's phone number
+	Push          bool   `json:"push"`                   // Send notifications as web push messages
+	PushEndpoint  string `json:"pushEndpoint,omitempty"` // Web push subscription endpoint of the user's browser
+}
@@ -0,0 +1,102 @@
+// Package models defines fares, payments and refunds of bookings in the
+// MindenAirport system.
+package models
+
+import "time"
+
+// Fare is the price of a ticket on a flight in one travel class.
+type Fare struct {
+	TravelClassID int     `json:"travelClassId"`
+	TravelClass   string  `json:"travelClass,omitempty"` // Name of the travel class
+	Price         float64 `json:"price"`
+	Currency      string  `json:"currency"`
+}
+
+// FaresRequest replaces all fares of a flight. Travel classes without a
+// fare cannot be booked.
+type FaresRequest struct {
+	Fares []FareRequest `json:"fares" binding:"required,dive"`
+}
+
+// FareRequest sets the fare of one travel class.
+type FareRequest struct {
+	TravelClass int      `json:"travelClass" binding:"required,min=1"`
+	Price       *float64 `json:"price" binding:"required,min=0"`
+}
+
+// Payment is a card payment of the held tickets of a booking. It is
+// authorized and captured with the payment provider; refunds are recorded
+// per ticket.
+type Payment struct {
+	ID             string          `json:"id"`
+	BookingID      string          `json:"bookingId"`
+	Locator        string          `json:"locator,omitempty"`
+	AirportUserID  string          `json:"airportUserId"` // Account that paid
+	Provider       string          `json:"provider"`      // Payment provider, e.g. "mock"
+	Reference      string          `json:"reference,omitempty"`
+	Status         string          `json:"status"` // PENDING, AUTHORIZED, CAPTURED, DECLINED, FAILED, VOIDED or REFUNDED
+	Amount         float64         `json:"amount"`
+	RefundedAmount float64         `json:"refundedAmount"`
+	Currency       string          `json:"currency"`
+	CardBrand      string          `json:"cardBrand,omitempty"`
+	CardLast4      string          `json:"cardLast4,omitempty"`
+	FailureCode    string          `json:"failureCode,omitempty"` // Why the payment was declined or failed
+	FailureMessage string          `json:"failureMessage,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	CapturedAt     *time.Time      `json:"capturedAt,omitempty"`
+	Tickets        []PaymentTicket `json:"tickets"`
+	Refunds        []PaymentRefund `json:"refunds"`
+}
+
+// PaymentTicket is a ticket paid by a payment.
+type PaymentTicket struct {
+	TicketID string  `json:"ticketId"`
+	Amount   float64 `json:"amount"`
+	RefundID string  `json:"refundId,omitempty"` // Refund that paid the ticket back
+}
+
+// PaymentRefund pays back tickets of a captured payment.
+type PaymentRefund struct {
+	ID        string    `json:"id"`
+	PaymentID string    `json:"paymentId"`
+	Reference string    `json:"reference,omitempty"`
+	Amount    float64   `json:"amount"`
+	Status    string    `json:"status"` // PENDING, SUCCEEDED or FAILED
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy,omitempty"` // Staff member, empty for automatic refunds
+}
+
+// PaymentRequest pays the held tickets of a booking by card.
+type PaymentRequest struct {
+	Card CardRequest `json:"card" binding:"required"`
+}
+
+// CardRequest holds the card details of a payment. They are passed to the
+// payment provider and never stored, apart from brand and last four digits.
+type CardRequest struct {
+	Number      string `json:"number" binding:"required"`
+	ExpiryMonth int    `json:"expiryMonth" binding:"required,min=1,max=12"`
+	ExpiryYear  int    `json:"expiryYear" binding:"required"`
+	CVC         string `json:"cvc" binding:"required"`
+	Holder      string `json:"holder"`
+}
+
+// RefundRequest refunds tickets of a payment. Without ticket IDs all tickets
+// not yet refunded are refunded.
+type RefundRequest struct {
+	TicketIDs []string `json:"ticketIds"`
+	Reason    string   `json:"reason" binding:"max=255"`
+}
+
+// IdempotencyRecord is a request made with an Idempotency-Key header and,
+// once it completed, its response.
+type IdempotencyRecord struct {
+	Scope       string    // Account or webhook provider the key belongs to
+	Key         string    // Value of the Idempotency-Key header
+	RequestHash string    // SHA-256 of method, path and body of the request
+	StatusCode  int       // 0 while the request is in progress
+	Response    string    // Response body
+	CreatedAt   time.Time // When the key was first used
+}
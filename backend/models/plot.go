@@ -0,0 +1,38 @@
+// Package models defines the data structures for managing the airport's
+// plots and the facilities built on them in the MindenAirport system.
+package models
+
+// Kinds of facilities that can occupy a plot. They match the NAME of the
+// corresponding PLOTTYPE.
+const (
+	OccupantHangar   = "hangar"
+	OccupantShop     = "shop"
+	OccupantTerminal = "terminal"
+	OccupantParking  = "parking"
+)
+
+// PlotOccupant is a facility built on a plot.
+type PlotOccupant struct {
+	Kind   string `json:"kind"` // hangar, shop, terminal or parking
+	ID     string `json:"id"`
+	Name   string `json:"name,omitempty"`
+	PlotID string `json:"-"`
+}
+
+// PlotOverview describes a plot together with its type and what occupies it.
+type PlotOverview struct {
+	Plot
+	TypeName  string         `json:"typeName"`
+	TypeLabel string         `json:"typeLabel"`
+	Occupants []PlotOccupant `json:"occupants"`
+	Load      float64        `json:"load"` // Weight currently carried by the plot in pounds (parked aircraft)
+}
+
+// Parking represents a car park built on a plot
+type Parking struct {
+	ID     string `json:"id"`               // Unique identifier for the car park
+	Name   string `json:"name"`             // Display name (e.g. "P1 Short-term")
+	PlotID string `json:"plotId"`           // ID of the plot where the car park is located
+	Spaces int    `json:"spaces,omitempty"` // Number of parking spaces
+	Status string `json:"status,omitempty"` // Current status (ACTIVE, CLOSED)
+}
@@ -0,0 +1,73 @@
+// Package models defines the data structures for recurring flight schedules
+// in the MindenAirport system.
+package models
+
+import "time"
+
+// ScheduleTemplate describes a flight operated on fixed weekdays during a
+// season, e.g. "LH123 MIN→FRA daily 07:15". Flights are generated from it
+// over a rolling horizon. Times are wall clock times at the airports: the
+// departure at the origin, the arrival at the destination.
+type ScheduleTemplate struct {
+	ID               string    `json:"id"`
+	FlightNumber     string    `json:"flightNumber"`
+	From             string    `json:"from"`
+	To               string    `json:"to"`
+	DepartureTime    string    `json:"departureTime"`    // HH:MM local time at the origin
+	ArrivalTime      string    `json:"arrivalTime"`      // HH:MM local time at the destination
+	ArrivalDayOffset int       `json:"arrivalDayOffset"` // Days between the dates of departure and arrival
+	DaysOfWeek       string    `json:"daysOfWeek"`       // Days of operation, 1 = Monday to 7 = Sunday, e.g. "12345"
+	ValidFrom        time.Time `json:"validFrom"`        // First day of operation
+	ValidTo          time.Time `json:"validTo"`          // Last day of operation
+	PlaneID          string    `json:"planeId"`
+	TerminalID       string    `json:"terminalId,omitempty"`
+	Gate             string    `json:"gate,omitempty"`
+	Revision         int       `json:"revision"` // Incremented with every change of the template
+}
+
+// TemplateFlight is a flight generated from a schedule template together
+// with its bookings.
+type TemplateFlight struct {
+	Flight
+	Tickets  int  `json:"tickets"`  // Tickets that are not cancelled
+	Outdated bool `json:"outdated"` // The flight does not follow the current template revision
+}
+
+// OutdatedFlight is a booked flight that no longer matches its template.
+// It keeps its schedule until an admin applies the template or keeps the
+// flight as it is.
+type OutdatedFlight struct {
+	FlightID  string        `json:"flightId"`
+	Date      string        `json:"date"` // Local date of departure, YYYY-MM-DD
+	Tickets   int           `json:"tickets"`
+	Cancelled bool          `json:"cancelled,omitempty"` // The template no longer operates on this date
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+// ScheduleConflict is a date on which a template flight could not be created.
+type ScheduleConflict struct {
+	Date   string `json:"date"` // Local date of departure, YYYY-MM-DD
+	Reason string `json:"reason"`
+}
+
+// ScheduleSync reports how the flights of a template were brought in line
+// with it up to Until.
+type ScheduleSync struct {
+	TemplateID   string             `json:"templateId"`
+	FlightNumber string             `json:"flightNumber"`
+	Revision     int                `json:"revision"`
+	Until        time.Time          `json:"until"`
+	Created      []string           `json:"created"`   // IDs of new flights
+	Updated      []string           `json:"updated"`   // IDs of unbooked flights changed to the template
+	Removed      []string           `json:"removed"`   // IDs of unbooked flights no longer scheduled
+	Outdated     []OutdatedFlight   `json:"outdated"`  // Booked flights that differ from the template
+	Conflicts    []ScheduleConflict `json:"conflicts"` // Dates without flight
+}
+
+// ScheduleRolloutReport is the result of generating the flights of all
+// templates.
+type ScheduleRolloutReport struct {
+	CheckedAt time.Time      `json:"checkedAt"`
+	Until     time.Time      `json:"until"`
+	Templates []ScheduleSync `json:"templates"`
+}
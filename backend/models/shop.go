@@ -0,0 +1,33 @@
+// Package models defines the data structures for the shop and services
+// directory of the MindenAirport system.
+package models
+
+import "time"
+
+// Security levels of shops as stored in the SHOPTYPE table.
+const (
+	SecurityLandside = "PRE_SECURITY"  // Reachable before the security check
+	SecurityAirside  = "POST_SECURITY" // Reachable after the security check only
+)
+
+// ShopListing is a shop as shown in the directory, together with the
+// attributes of its shop type and the terminal it is located in.
+type ShopListing struct {
+	Shop
+	TypeName      string `json:"typeName"`
+	Category      string `json:"category,omitempty"`
+	SecurityLevel string `json:"securityLevel,omitempty"` // PRE_SECURITY (landside) or POST_SECURITY (airside)
+	TypicalHours  string `json:"typicalHours,omitempty"`  // Hours of the shop type, used if the shop has none
+	TerminalID    string `json:"terminalId,omitempty"`
+	OpenNow       bool   `json:"openNow"`
+}
+
+// DepartureShops lists the shops a passenger can still visit before the
+// gate of their flight closes.
+type DepartureShops struct {
+	FlightID     string        `json:"flightId"`
+	TerminalID   string        `json:"terminalId"`
+	Gate         string        `json:"gate,omitempty"`
+	GateClosesAt time.Time     `json:"gateClosesAt"`
+	Shops        []ShopListing `json:"shops"`
+}
@@ -0,0 +1,20 @@
+// Package models defines the TerminalLoad data structure for monitoring
+// passenger crowding in the terminals of the MindenAirport system.
+package models
+
+import "time"
+
+// TerminalLoad describes how many departing passengers a terminal handles
+// within a time window, compared against its capacity.
+type TerminalLoad struct {
+	TerminalID  string    `json:"terminalId"`  // ID of the terminal
+	Name        string    `json:"name"`        // Display name of the terminal
+	Status      string    `json:"status"`      // Current terminal status (ACTIVE, MAINTENANCE, CLOSED)
+	Capacity    int       `json:"capacity"`    // Passenger capacity of the terminal
+	Flights     int       `json:"flights"`     // Number of departures in the window
+	Passengers  int       `json:"passengers"`  // Booked passengers departing in the window
+	Utilization float64   `json:"utilization"` // Passengers relative to capacity (1.0 = full)
+	Level       string    `json:"level"`       // Crowding level (LOW, MODERATE, HIGH, OVER_CAPACITY)
+	WindowStart time.Time `json:"windowStart"` // Start of the observed time window
+	WindowEnd   time.Time `json:"windowEnd"`   // End of the observed time window
+}
@@ -13,12 +13,49 @@ type Ticket struct {
 	Flight        string    `json:"flight"`                  // Flight ID for this ticket
 	SeatNumber    string    `json:"seatNumber,omitempty"`    // Assigned seat number (e.g., "12A")
 	TravelClass   string    `json:"travelClass,omitempty"`   // Travel class (Economy, Business, First)
-	Price         float64   `json:"price,omitempty"`         // Ticket price in USD
+	Price         float64   `json:"price,omitempty"`         // Ticket price in EUR
 	BookingDate   time.Time `json:"bookingDate,omitempty"`   // Date and time when ticket was booked
-	Status        string    `json:"status,omitempty"`        // Ticket status (CONFIRMED, CANCELLED, CHECKED_IN)
+	Status        string    `json:"status,omitempty"`        // Ticket status (HELD, CONFIRMED, CANCELLED, CHECKED_IN, DENIED_BOARDING)
 	From          string    `json:"from,omitempty"`          // Origin airport code
 	To            string    `json:"to,omitempty"`            // Destination airport code
 	Gate          string    `json:"gate,omitempty"`          // Departure gate assignment
 	BaggageClaim  string    `json:"baggageClaim,omitempty"`  // Baggage claim area for arrival
-	DepartureTime string    `json:"departureTime,omitempty"` // Scheduled departure time
+	DepartureTime string    `json:"departureTime,omitempty"` // Expected departure time in UTC (RFC 3339)
+	Aircraft      string    `json:"aircraft,omitempty"`      // Model of the plane operating the flight
+
+	Departure *LocalTime `json:"departure,omitempty"` // Expected departure in UTC and local time at the origin
+
+	BookingID       string `json:"bookingId,omitempty"`       // Booking the ticket was issued for
+	Locator         string `json:"locator,omitempty"`         // Locator of the booking
+	PassengerID     string `json:"passengerId,omitempty"`     // Booking passenger travelling on the ticket
+	PassengerName   string `json:"passengerName,omitempty"`   // Full name of the booking passenger
+	PassengerUserID string `json:"passengerUserId,omitempty"` // Account of the booking passenger, empty for guests and companions
+	CompanionID     string `json:"companionId,omitempty"`     // Companion of the holder travelling on the ticket
+
+	DistanceKm        float64 `json:"distanceKm,omitempty"`        // Great-circle distance of the flight, calculated
+	CO2PerPassengerKg float64 `json:"co2PerPassengerKg,omitempty"` // Estimated CO2 emissions per passenger, calculated
+}
+
+// TravellerID returns the account of the person travelling on the ticket:
+// the booking passenger's, or the holder's for tickets issued without a
+// booking passenger. It is empty for guests and companions.
+func (t Ticket) TravellerID() string {
+	if t.PassengerID != "" {
+		return t.PassengerUserID
+	}
+	return t.AirportUserID
+}
+
+// HeldBy reports whether a user holds the ticket or travels on it.
+func (t Ticket) HeldBy(userID string) bool {
+	return userID != "" && (t.AirportUserID == userID || t.TravellerID() == userID)
+}
+
+// Reference returns the booking locator of the ticket, or its ID for
+// tickets issued without a booking.
+func (t Ticket) Reference() string {
+	if t.Locator != "" {
+		return t.Locator
+	}
+	return t.ID
 }
@@ -0,0 +1,53 @@
+// Package models defines the overbooking limits and waitlists of full
+// flights in the MindenAirport system.
+package models
+
+import "time"
+
+// Overbooking describes how many tickets a flight may sell beyond the seats
+// of its plane. A percentage set for the flight overrides the one of its
+// airline; without either the flight is sold up to its seats.
+type Overbooking struct {
+	FlightID       string   `json:"flightId"`
+	AirlineID      string   `json:"airlineId,omitempty"`
+	Seats          int      `json:"seats"`          // Seats of the plane operating the flight
+	FlightPercent  *float64 `json:"flightPercent"`  // Percentage set for the flight
+	AirlinePercent *float64 `json:"airlinePercent"` // Percentage set for the airline
+	Percent        float64  `json:"percent"`        // Percentage in effect
+	Capacity       int      `json:"capacity"`       // Tickets that can be sold, seats plus overbooking
+	Sold           int      `json:"sold"`           // Tickets holding a seat
+	Held           int      `json:"held"`           // Seats held by open waitlist offers
+	Available      int      `json:"available"`      // Tickets that can still be sold
+	Waiting        int      `json:"waiting"`        // Passengers on the waitlist
+}
+
+// OverbookingRequest sets the overbooking percentage of a flight or airline.
+// A null percentage removes it.
+type OverbookingRequest struct {
+	Percent *float64 `json:"percent"`
+}
+
+// WaitlistEntry is a request to book passengers on a full flight. When seats
+// become free the entry is offered them for a limited time; accepting the
+// offer creates a booking.
+type WaitlistEntry struct {
+	ID             string                    `json:"id"`
+	FlightID       string                    `json:"flightId"`
+	AirportUserID  string                    `json:"airportUserId"`
+	TravelClass    int                       `json:"travelClass"`
+	Seats          int                       `json:"seats"`              // Number of passengers
+	Status         string                    `json:"status"`             // WAITING, OFFERED, ACCEPTED, DECLINED, EXPIRED or CANCELLED
+	Position       int                       `json:"position,omitempty"` // Place in the queue of the flight while waiting
+	CreatedAt      time.Time                 `json:"createdAt"`
+	OfferedAt      *time.Time                `json:"offeredAt,omitempty"`
+	OfferExpiresAt *time.Time                `json:"offerExpiresAt,omitempty"` // The offer must be accepted before
+	BookingID      string                    `json:"bookingId,omitempty"`      // Booking created when the offer was accepted
+	Passengers     []BookingPassengerRequest `json:"passengers"`
+}
+
+// WaitlistRequest puts passengers on the waitlist of a full flight.
+type WaitlistRequest struct {
+	FlightID    string                    `json:"flightId" binding:"required"`
+	TravelClass int                       `json:"travelClass" binding:"required,min=1"`
+	Passengers  []BookingPassengerRequest `json:"passengers" binding:"required,min=1,max=9,dive"`
+}
@@ -0,0 +1,200 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"mindenairport/models"
+)
+
+// Message is a rendered notification for one recipient.
+type Message struct {
+	Recipient  models.AirportUser
+	Preference models.NotificationPreference
+	Subject    string
+	Body       string
+}
+
+// Channel delivers messages through one transport (e-mail, SMS, web push, ...).
+// New transports are added by implementing this interface and registering the
+// channel in NewService.
+type Channel interface {
+	// Name returns a short identifier of the channel used in log output
+	Name() string
+	// Accepts reports whether the recipient of the message wants and can receive it on this channel
+	Accepts(msg Message) bool
+	// Send delivers the message
+	Send(msg Message) error
+}
+
+// SMTPChannel sends notifications as plain text e-mails.
+// Without credentials it connects unauthenticated, which works with local
+// SMTP stand-ins such as MailHog.
+type SMTPChannel struct {
+	Addr     string // host:port of the SMTP server
+	From     string // Sender address
+	Username string
+	Password string
+}
+
+// Name returns the channel identifier.
+func (c SMTPChannel) Name() string { return "email" }
+
+// Accepts reports whether the user opted into e-mails and has an address.
+func (c SMTPChannel) Accepts(msg Message) bool {
+	return msg.Preference.Email && msg.Recipient.Email != ""
+}
+
+// Send delivers the message via SMTP.
+func (c SMTPChannel) Send(msg Message) error {
+	var auth smtp.Auth
+	if c.Username != "" {
+		host := strings.Split(c.Addr, ":")[0]
+		auth = smtp.PlainAuth("", c.Username, c.Password, host)
+	}
+
+	var mail bytes.Buffer
+	fmt.Fprintf(&mail, "From: %s\r\n", c.From)
+	fmt.Fprintf(&mail, "To: %s\r\n", msg.Recipient.Email)
+	fmt.Fprintf(&mail, "Subject: %s\r\n", msg.Subject)
+	mail.WriteString("MIME-Version: 1.0\r\n")
+	mail.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	mail.WriteString("\r\n")
+	mail.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return smtp.SendMail(c.Addr, auth, c.From, []string{msg.Recipient.Email}, mail.Bytes())
+}
+
+// SMSChannel sends notifications through an HTTP SMS gateway.
+// The gateway receives a JSON body with the fields "to" and "message".
+type SMSChannel struct {
+	GatewayURL string
+	APIKey     string
+	Client     *http.Client
+}
+
+// Name returns the channel identifier.
+func (c SMSChannel) Name() string { return "sms" }
+
+// Accepts reports whether the user opted into SMS and has a phone number.
+func (c SMSChannel) Accepts(msg Message) bool {
+	return msg.Preference.SMS && msg.Recipient.Phone != ""
+}
+
+// Send posts the message to the SMS gateway.
+func (c SMSChannel) Send(msg Message) error {
+	payload := map[string]string{
+		"to":      msg.Recipient.Phone,
+		"message": msg.Subject + "\n" + msg.Body,
+	}
+	return postJSON(c.Client, c.GatewayURL, c.APIKey, payload)
+}
+
+// PushChannel sends web push notifications through a push relay service that
+// takes care of the subscription encryption. The relay receives a JSON body
+// with the fields "endpoint", "title" and "body".
+type PushChannel struct {
+	RelayURL string
+	APIKey   string
+	Client   *http.Client
+}
+
+// Name returns the channel identifier.
+func (c PushChannel) Name() string { return "push" }
+
+// Accepts reports whether the user opted into push messages and registered a subscription.
+func (c PushChannel) Accepts(msg Message) bool {
+	return msg.Preference.Push && msg.Preference.PushEndpoint != ""
+}
+
+// Send posts the message to the push relay.
+func (c PushChannel) Send(msg Message) error {
+	payload := map[string]string{
+		"endpoint": msg.Preference.PushEndpoint,
+		"title":    msg.Subject,
+		"body":     msg.Body,
+	}
+	return postJSON(c.Client, c.RelayURL, c.APIKey, payload)
+}
+
+// postJSON sends a JSON payload with an optional bearer API key and treats
+// every non-2xx response as an error.
+func postJSON(client *http.Client, url, apiKey string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("gateway responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// channelsFromEnv creates the channels configured through environment variables:
+//
+//	SMTP_HOST, SMTP_PORT, SMTP_FROM, SMTP_USERNAME, SMTP_PASSWORD
+//	SMS_GATEWAY_URL, SMS_GATEWAY_API_KEY
+//	PUSH_RELAY_URL, PUSH_RELAY_API_KEY
+//
+// Channels whose address is not configured are skipped.
+func channelsFromEnv() []Channel {
+	var channels []Channel
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "25"
+		}
+		from := os.Getenv("SMTP_FROM")
+		if from == "" {
+			from = "noreply@minden-airport.de"
+		}
+		channels = append(channels, SMTPChannel{
+			Addr:     host + ":" + port,
+			From:     from,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		})
+	}
+
+	if url := os.Getenv("SMS_GATEWAY_URL"); url != "" {
+		channels = append(channels, SMSChannel{
+			GatewayURL: url,
+			APIKey:     os.Getenv("SMS_GATEWAY_API_KEY"),
+			Client:     client,
+		})
+	}
+
+	if url := os.Getenv("PUSH_RELAY_URL"); url != "" {
+		channels = append(channels, PushChannel{
+			RelayURL: url,
+			APIKey:   os.Getenv("PUSH_RELAY_API_KEY"),
+			Client:   client,
+		})
+	}
+
+	return channels
+}
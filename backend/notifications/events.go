@@ -0,0 +1,74 @@
+// Package notifications informs passengers about changes to their flights
+// and about seats offered to them from a waitlist. It compares flight
+// updates, finds the ticket holders of the affected flight, renders
+// localized messages and delivers them through pluggable channels such as
+// e-mail, SMS and web push.
+package notifications
+
+import (
+	"time"
+
+	"mindenairport/models"
+)
+
+// EventType identifies the kind of flight change a passenger is notified about.
+type EventType string
+
+const (
+	EventGateChange EventType = "GATE_CHANGE" // The departure gate was changed
+	EventDelay      EventType = "DELAY"       // The scheduled departure was moved to a later time
+	EventBoarding   EventType = "BOARDING"    // Boarding started or the final call was made
+
+	EventWaitlistOffer EventType = "WAITLIST_OFFER" // Seats on a full flight are offered to a waitlist entry
+)
+
+// Event describes a single passenger-relevant change between two versions of a flight.
+type Event struct {
+	Type         EventType
+	Flight       models.Flight // The flight after the update
+	OldGate      string
+	NewGate      string
+	OldDeparture time.Time
+	NewDeparture time.Time
+	FinalCall    bool           // Set for boarding events caused by the final call status
+	Location     *time.Location // Time zone of the origin airport, UTC if unset
+	Seats        int            // Seats offered to a waitlist entry
+	OfferExpires time.Time      // End of a waitlist offer
+}
+
+// DiffFlight compares a flight before and after an update and returns the
+// events passengers have to be informed about. Earlier departures are not
+// reported as delays, and a gate that is assigned for the first time is not
+// reported as a gate change.
+func DiffFlight(old, updated models.Flight) []Event {
+	var events []Event
+
+	if old.Gate != "" && updated.Gate != "" && old.Gate != updated.Gate {
+		events = append(events, Event{
+			Type:    EventGateChange,
+			Flight:  updated,
+			OldGate: old.Gate,
+			NewGate: updated.Gate,
+		})
+	}
+
+	if !old.ScheduledDeparture.IsZero() && updated.ScheduledDeparture.After(old.ScheduledDeparture) {
+		events = append(events, Event{
+			Type:         EventDelay,
+			Flight:       updated,
+			OldDeparture: old.ScheduledDeparture,
+			NewDeparture: updated.ScheduledDeparture,
+		})
+	}
+
+	if updated.StatusID != old.StatusID && (updated.StatusID == models.FlightStatusBoarding || updated.StatusID == models.FlightStatusFinalCall) {
+		events = append(events, Event{
+			Type:      EventBoarding,
+			Flight:    updated,
+			NewGate:   updated.Gate,
+			FinalCall: updated.StatusID == models.FlightStatusFinalCall,
+		})
+	}
+
+	return events
+}
@@ -0,0 +1,157 @@
+package notifications
+
+import (
+	"log"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/timezone"
+)
+
+// Service finds the passengers affected by a flight change and sends them
+// notifications through all channels they opted into.
+type Service struct {
+	db       database.Database
+	channels []Channel
+}
+
+// NewService creates a notification service with the channels configured in
+// the environment (see channelsFromEnv).
+func NewService(db database.Database) *Service {
+	return NewServiceWithChannels(db, channelsFromEnv()...)
+}
+
+// NewServiceWithChannels creates a notification service delivering through the given channels.
+func NewServiceWithChannels(db database.Database, channels ...Channel) *Service {
+	if len(channels) == 0 {
+		log.Println("Warning: no notification channels configured, passenger notifications are disabled")
+	}
+	return &Service{db: db, channels: channels}
+}
+
+// NotifyFlightChange compares both versions of a flight and informs all ticket
+// holders of the flight about gate changes, delays and boarding calls. For
+// tickets of a booking, passengers with an account are informed as well,
+// so every traveller and the person who booked hear about it once.
+// Delivery errors are logged and do not stop the remaining notifications.
+// The method is meant to be called in its own goroutine after an update was stored.
+func (s *Service) NotifyFlightChange(old, updated models.Flight) {
+	events := DiffFlight(old, updated)
+	if len(events) == 0 || len(s.channels) == 0 {
+		return
+	}
+
+	// Passengers read departure times in the local time of the origin airport
+	loc := s.originLocation(updated)
+	for i := range events {
+		events[i].Location = loc
+	}
+
+	tickets, err := s.db.GetTicketsByFlightID(updated.ID)
+	if err != nil {
+		log.Printf("Error loading tickets for flight %s: %v", updated.ID, err)
+		return
+	}
+
+	notified := make(map[string]bool)
+	for _, ticket := range tickets {
+		if ticket.Status == "CANCELLED" || ticket.Status == "DENIED_BOARDING" {
+			continue
+		}
+		for _, userID := range []string{ticket.AirportUserID, ticket.TravellerID()} {
+			if userID == "" || notified[userID] {
+				continue
+			}
+			notified[userID] = true
+
+			user, err := s.db.GetUserByID(userID)
+			if err != nil || user == nil {
+				log.Printf("Error loading user %s for notification: %v", userID, err)
+				continue
+			}
+
+			pref := s.preferenceFor(user.ID)
+			for _, event := range events {
+				s.notify(event, *user, pref)
+			}
+		}
+	}
+}
+
+// NotifyWaitlistOffer informs the user of a waitlist entry that seats on
+// the full flight are offered to them until the offer expires. Like
+// NotifyFlightChange it is meant to be called in its own goroutine.
+func (s *Service) NotifyWaitlistOffer(entry models.WaitlistEntry, flight models.Flight) {
+	if len(s.channels) == 0 || entry.OfferExpiresAt == nil {
+		return
+	}
+
+	user, err := s.db.GetUserByID(entry.AirportUserID)
+	if err != nil || user == nil {
+		log.Printf("Error loading user %s for notification: %v", entry.AirportUserID, err)
+		return
+	}
+
+	event := Event{
+		Type:         EventWaitlistOffer,
+		Flight:       flight,
+		Location:     s.originLocation(flight),
+		Seats:        entry.Seats,
+		OfferExpires: *entry.OfferExpiresAt,
+	}
+	s.notify(event, *user, s.preferenceFor(user.ID))
+}
+
+// originLocation returns the time zone of a flight's origin airport, or UTC
+// if the airport has no valid time zone.
+func (s *Service) originLocation(flight models.Flight) *time.Location {
+	loc, err := timezone.AirportLocation(s.db.GetAirportByID(flight.From))
+	if err != nil {
+		log.Printf("Error loading time zone of flight %s: %v", flight.ID, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// preferenceFor returns the stored preference of a user or the default
+// (e-mail only, default language) if none is stored.
+func (s *Service) preferenceFor(userID string) models.NotificationPreference {
+	pref, err := s.db.GetNotificationPreference(userID)
+	if err != nil {
+		log.Printf("Error loading notification preference of user %s: %v", userID, err)
+	}
+	if pref == nil {
+		return models.NotificationPreference{
+			AirportUserID: userID,
+			Language:      DefaultLanguage,
+			Email:         true,
+		}
+	}
+	return *pref
+}
+
+// notify renders one event for one user and sends it through all accepting channels.
+func (s *Service) notify(event Event, user models.AirportUser, pref models.NotificationPreference) {
+	subject, body, err := Render(event, pref.Language, user.FirstName)
+	if err != nil {
+		log.Printf("Error rendering %s notification: %v", event.Type, err)
+		return
+	}
+
+	msg := Message{
+		Recipient:  user,
+		Preference: pref,
+		Subject:    subject,
+		Body:       body,
+	}
+
+	for _, channel := range s.channels {
+		if !channel.Accepts(msg) {
+			continue
+		}
+		if err := channel.Send(msg); err != nil {
+			log.Printf("Error sending %s notification to user %s via %s: %v", event.Type, user.ID, channel.Name(), err)
+		}
+	}
+}
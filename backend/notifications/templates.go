@@ -0,0 +1,180 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// DefaultLanguage is used for users without a stored preference or with an unsupported language.
+const DefaultLanguage = "en"
+
+// messageTemplate holds the subject and body template of one event type in one language.
+type messageTemplate struct {
+	Subject string
+	Body    string
+}
+
+// messageTemplates contains the message texts per language and event type.
+var messageTemplates = map[string]map[EventType]messageTemplate{
+	"en": {
+		EventGateChange: {
+			Subject: "Gate change for flight {{.FlightID}}",
+			Body: "Hello {{.FirstName}},\n\n" +
+				"the departure gate of your flight {{.FlightID}} from {{.From}} to {{.To}} has changed " +
+				"from {{.OldGate}} to {{.NewGate}}.\n\n" +
+				"Scheduled departure: {{.Departure}}\n\n" +
+				"Your Minden Airport team",
+		},
+		EventDelay: {
+			Subject: "Delay of flight {{.FlightID}}",
+			Body: "Hello {{.FirstName}},\n\n" +
+				"your flight {{.FlightID}} from {{.From}} to {{.To}} is delayed.\n" +
+				"Previous departure: {{.OldDeparture}}\n" +
+				"New departure: {{.Departure}}\n\n" +
+				"We apologize for the inconvenience.\n" +
+				"Your Minden Airport team",
+		},
+		EventBoarding: {
+			Subject: "{{if .FinalCall}}Final call{{else}}Boarding{{end}} for flight {{.FlightID}}",
+			Body: "Hello {{.FirstName}},\n\n" +
+				"{{if .FinalCall}}this is the final call for{{else}}boarding has started for{{end}} " +
+				"your flight {{.FlightID}} to {{.To}}" +
+				"{{if .NewGate}} at gate {{.NewGate}}{{end}}.\n" +
+				"Please proceed to the gate immediately.\n\n" +
+				"Your Minden Airport team",
+		},
+		EventWaitlistOffer: {
+			Subject: "Seats available on flight {{.FlightID}}",
+			Body: "Hello {{.FirstName}},\n\n" +
+				"good news: {{.Seats}} {{if eq .Seats 1}}seat has{{else}}seats have{{end}} become available " +
+				"on flight {{.FlightID}} from {{.From}} to {{.To}}, departing {{.Departure}}.\n" +
+				"We hold {{if eq .Seats 1}}it{{else}}them{{end}} for you until {{.OfferExpires}}. " +
+				"Accept the offer in your waitlist to receive your booking.\n\n" +
+				"Your Minden Airport team",
+		},
+	},
+	"de": {
+		EventGateChange: {
+			Subject: "Gatewechsel für Flug {{.FlightID}}",
+			Body: "Hallo {{.FirstName}},\n\n" +
+				"das Abfluggate Ihres Fluges {{.FlightID}} von {{.From}} nach {{.To}} hat sich " +
+				"von {{.OldGate}} auf {{.NewGate}} geändert.\n\n" +
+				"Planmäßiger Abflug: {{.Departure}}\n\n" +
+				"Ihr Team vom Flughafen Minden",
+		},
+		EventDelay: {
+			Subject: "Verspätung von Flug {{.FlightID}}",
+			Body: "Hallo {{.FirstName}},\n\n" +
+				"Ihr Flug {{.FlightID}} von {{.From}} nach {{.To}} ist verspätet.\n" +
+				"Bisheriger Abflug: {{.OldDeparture}}\n" +
+				"Neuer Abflug: {{.Departure}}\n\n" +
+				"Wir bitten um Ihr Verständnis.\n" +
+				"Ihr Team vom Flughafen Minden",
+		},
+		EventBoarding: {
+			Subject: "{{if .FinalCall}}Letzter Aufruf{{else}}Boarding{{end}} für Flug {{.FlightID}}",
+			Body: "Hallo {{.FirstName}},\n\n" +
+				"{{if .FinalCall}}dies ist der letzte Aufruf für{{else}}das Boarding hat begonnen für{{end}} " +
+				"Ihren Flug {{.FlightID}} nach {{.To}}" +
+				"{{if .NewGate}} an Gate {{.NewGate}}{{end}}.\n" +
+				"Bitte begeben Sie sich umgehend zum Gate.\n\n" +
+				"Ihr Team vom Flughafen Minden",
+		},
+		EventWaitlistOffer: {
+			Subject: "Freie Plätze auf Flug {{.FlightID}}",
+			Body: "Hallo {{.FirstName}},\n\n" +
+				"gute Nachricht: Auf Flug {{.FlightID}} von {{.From}} nach {{.To}}, Abflug {{.Departure}}, " +
+				"{{if eq .Seats 1}}ist ein Platz{{else}}sind {{.Seats}} Plätze{{end}} frei geworden.\n" +
+				"Wir halten {{if eq .Seats 1}}ihn{{else}}sie{{end}} bis {{.OfferExpires}} für Sie bereit. " +
+				"Nehmen Sie das Angebot in Ihrer Warteliste an, um Ihre Buchung zu erhalten.\n\n" +
+				"Ihr Team vom Flughafen Minden",
+		},
+	},
+}
+
+// timeLayouts contains the date format used in messages per language. Times
+// are given in the local time of the origin airport, followed by its zone.
+var timeLayouts = map[string]string{
+	"en": "Jan 2, 2006 3:04 PM MST",
+	"de": "02.01.2006 15:04 MST",
+}
+
+// templateData is the data passed to the message templates.
+type templateData struct {
+	FirstName    string
+	FlightID     string
+	From         string
+	To           string
+	OldGate      string
+	NewGate      string
+	Departure    string
+	OldDeparture string
+	FinalCall    bool
+	Seats        int
+	OfferExpires string
+}
+
+// Render builds the subject and body of the message for an event in the given
+// language. Unsupported languages fall back to DefaultLanguage.
+func Render(event Event, language, firstName string) (string, string, error) {
+	templates, ok := messageTemplates[language]
+	if !ok {
+		language = DefaultLanguage
+		templates = messageTemplates[language]
+	}
+
+	tmpl, ok := templates[event.Type]
+	if !ok {
+		return "", "", fmt.Errorf("no template for event type %s", event.Type)
+	}
+
+	loc := event.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	data := templateData{
+		FirstName: firstName,
+		FlightID:  event.Flight.ID,
+		From:      event.Flight.From,
+		To:        event.Flight.To,
+		OldGate:   event.OldGate,
+		NewGate:   event.NewGate,
+		Departure: event.Flight.ScheduledDeparture.In(loc).Format(timeLayouts[language]),
+		FinalCall: event.FinalCall,
+		Seats:     event.Seats,
+	}
+	if !event.OldDeparture.IsZero() {
+		data.OldDeparture = event.OldDeparture.In(loc).Format(timeLayouts[language])
+	}
+	if !event.OfferExpires.IsZero() {
+		data.OfferExpires = event.OfferExpires.In(loc).Format(timeLayouts[language])
+	}
+
+	subject, err := execute(tmpl.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := execute(tmpl.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	return subject, body, nil
+}
+
+// execute parses and runs a single template string.
+func execute(text string, data templateData) (string, error) {
+	t, err := template.New("message").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering template: %w", err)
+	}
+	return buf.String(), nil
+}
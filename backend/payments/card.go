@@ -0,0 +1,105 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mindenairport/models"
+)
+
+// ErrInvalidCard is returned for card details that cannot be valid.
+var ErrInvalidCard = errors.New("invalid card")
+
+// NormalizeCard removes spaces and dashes from the card number and checks
+// number, expiry and security code. It returns ErrInvalidCard describing
+// the first problem.
+func NormalizeCard(card *models.CardRequest, now time.Time) error {
+	card.Number = strings.NewReplacer(" ", "", "-", "").Replace(card.Number)
+	card.CVC = strings.TrimSpace(card.CVC)
+	card.Holder = strings.TrimSpace(card.Holder)
+
+	if len(card.Number) < 12 || len(card.Number) > 19 || !digits(card.Number) || !luhn(card.Number) {
+		return fmt.Errorf("%w: card number is not valid", ErrInvalidCard)
+	}
+	if len(card.CVC) < 3 || len(card.CVC) > 4 || !digits(card.CVC) {
+		return fmt.Errorf("%w: security code must have 3 or 4 digits", ErrInvalidCard)
+	}
+
+	year := card.ExpiryYear
+	if year < 100 {
+		year += 2000
+	}
+	// Cards are valid until the end of their expiry month
+	expires := time.Date(year, time.Month(card.ExpiryMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	if !expires.After(now) {
+		return fmt.Errorf("%w: card has expired", ErrInvalidCard)
+	}
+	card.ExpiryYear = year
+	return nil
+}
+
+// CardBrand returns the scheme of a card number from its leading digits,
+// or "" if it is not recognized.
+func CardBrand(number string) string {
+	switch {
+	case strings.HasPrefix(number, "4"):
+		return "VISA"
+	case hasPrefixBetween(number, 2, 51, 55), hasPrefixBetween(number, 4, 2221, 2720):
+		return "MASTERCARD"
+	case strings.HasPrefix(number, "34"), strings.HasPrefix(number, "37"):
+		return "AMEX"
+	default:
+		return ""
+	}
+}
+
+// CardLast4 returns the last four digits of a card number.
+func CardLast4(number string) string {
+	if len(number) < 4 {
+		return number
+	}
+	return number[len(number)-4:]
+}
+
+// hasPrefixBetween reports whether the first n digits of s form a number
+// between lo and hi.
+func hasPrefixBetween(s string, n, lo, hi int) bool {
+	if len(s) < n {
+		return false
+	}
+	value := 0
+	for _, c := range s[:n] {
+		value = value*10 + int(c-'0')
+	}
+	return value >= lo && value <= hi
+}
+
+// digits reports whether s consists of ASCII digits only.
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// luhn reports whether a card number has a valid Luhn check digit.
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,281 @@
+package payments
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Test cards of the mock provider. Any other card number passing the Luhn
+// check is approved.
+const (
+	CardApproved          = "4242424242424242"
+	CardApprovedMaster    = "5555555555554444"
+	CardDeclined          = "4000000000000002"
+	CardInsufficientFunds = "4000000000009995"
+	CardExpired           = "4000000000000069"
+	CardIncorrectCVC      = "4000000000000127"
+	CardProviderError     = "4000000000000119" // The provider fails to process the authorization
+	CardCaptureFails      = "4000000000000341" // Authorized, but the capture is declined
+	CardPendingApproved   = "4000000000003220" // Pending, then authorized by webhook
+	CardPendingDeclined   = "4000000000003063" // Pending, then declined by webhook
+)
+
+// mockSignatureHeader carries the hex HMAC-SHA256 of the webhook body.
+const mockSignatureHeader = "X-Mock-Signature"
+
+// mockDeclines maps the declined test cards to their decline code.
+var mockDeclines = map[string]string{
+	CardDeclined:          "card_declined",
+	CardInsufficientFunds: "insufficient_funds",
+	CardExpired:           "expired_card",
+	CardIncorrectCVC:      "incorrect_cvc",
+}
+
+// MockProvider simulates a card payment provider in memory for development
+// and testing. Test cards trigger declines, failures and authorizations
+// that complete later by a signed webhook posted to WebhookURL.
+type MockProvider struct {
+	WebhookURL    string
+	WebhookSecret string
+	WebhookDelay  time.Duration
+	Client        *http.Client
+
+	mu         sync.Mutex
+	payments   map[string]*mockPayment // By reference
+	authorized map[string]Result       // By idempotency key, to answer retries alike
+}
+
+// mockPayment is the state the mock provider keeps of a payment.
+type mockPayment struct {
+	amount       float64
+	status       string // PENDING, AUTHORIZED, CAPTURED or VOIDED
+	refunded     float64
+	failsCapture bool
+}
+
+// NewMockProvider creates a mock provider posting webhooks signed with the
+// secret to the URL after the delay.
+func NewMockProvider(webhookURL, webhookSecret string, webhookDelay time.Duration) *MockProvider {
+	return &MockProvider{
+		WebhookURL:    webhookURL,
+		WebhookSecret: webhookSecret,
+		WebhookDelay:  webhookDelay,
+		Client:        &http.Client{Timeout: 10 * time.Second},
+		payments:      make(map[string]*mockPayment),
+		authorized:    make(map[string]Result),
+	}
+}
+
+// Name returns the provider identifier.
+func (p *MockProvider) Name() string { return "mock" }
+
+// Authorize approves, declines or defers the authorization depending on
+// the test card.
+func (p *MockProvider) Authorize(req AuthorizeRequest) (Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.authorized[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		return result, nil
+	}
+
+	number := req.Card.Number
+	if number == CardProviderError {
+		return Result{}, errors.New("mock: processing error")
+	}
+
+	reference, err := mockReference("pay")
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Reference: reference, Status: ResultApproved}
+	payment := &mockPayment{amount: req.Amount, status: "AUTHORIZED", failsCapture: number == CardCaptureFails}
+
+	if code, ok := mockDeclines[number]; ok {
+		result.Status = ResultDeclined
+		result.Code = code
+		result.Message = "The card was declined"
+		payment = nil
+	}
+	if number == CardPendingApproved || number == CardPendingDeclined {
+		result.Status = ResultPending
+		payment.status = "PENDING"
+		go p.completeLater(reference, number == CardPendingApproved)
+	}
+
+	if payment != nil {
+		p.payments[reference] = payment
+	}
+	if req.IdempotencyKey != "" {
+		p.authorized[req.IdempotencyKey] = result
+	}
+	return result, nil
+}
+
+// Capture collects an authorized payment in full.
+func (p *MockProvider) Capture(reference string, amount float64) (Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[reference]
+	if !ok || payment.status != "AUTHORIZED" {
+		return Result{}, fmt.Errorf("mock: payment %s cannot be captured", reference)
+	}
+	if payment.failsCapture {
+		return Result{Reference: reference, Status: ResultDeclined, Code: "capture_declined", Message: "The capture was declined"}, nil
+	}
+	payment.amount = amount
+	payment.status = "CAPTURED"
+	return Result{Reference: reference, Status: ResultApproved}, nil
+}
+
+// Void releases a pending or authorized payment. Pending payments do not
+// complete anymore.
+func (p *MockProvider) Void(reference string) (Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[reference]
+	if !ok || (payment.status != "PENDING" && payment.status != "AUTHORIZED") {
+		return Result{}, fmt.Errorf("mock: payment %s cannot be voided", reference)
+	}
+	payment.status = "VOIDED"
+	return Result{Reference: reference, Status: ResultApproved}, nil
+}
+
+// Refund pays back part of a captured payment right away.
+func (p *MockProvider) Refund(reference string, amount float64) (Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[reference]
+	if !ok || payment.status != "CAPTURED" {
+		return Result{}, fmt.Errorf("mock: payment %s cannot be refunded", reference)
+	}
+	if payment.refunded+amount > payment.amount+0.005 {
+		return Result{Status: ResultDeclined, Code: "amount_too_large", Message: "The refund exceeds the captured amount"}, nil
+	}
+	refund, err := mockReference("re")
+	if err != nil {
+		return Result{}, err
+	}
+	payment.refunded += amount
+	return Result{Reference: refund, Status: ResultApproved}, nil
+}
+
+// mockWebhook is the body of the webhooks of the mock provider.
+type mockWebhook struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Reference string `json:"reference"`
+	Code      string `json:"code,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ParseWebhook checks the signature of a webhook and decodes it.
+func (p *MockProvider) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	signature, err := hex.DecodeString(header.Get(mockSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(body)) {
+		return nil, fmt.Errorf("%w: signature does not match", ErrInvalidWebhook)
+	}
+
+	var webhook mockWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if webhook.ID == "" || webhook.Type == "" || webhook.Reference == "" {
+		return nil, fmt.Errorf("%w: id, type and reference are required", ErrInvalidWebhook)
+	}
+	return &WebhookEvent{
+		ID:        webhook.ID,
+		Type:      webhook.Type,
+		Reference: webhook.Reference,
+		Code:      webhook.Code,
+		Message:   webhook.Message,
+	}, nil
+}
+
+// completeLater authorizes or declines a pending payment after the webhook
+// delay and reports it by webhook, unless it was voided in the meantime.
+func (p *MockProvider) completeLater(reference string, approve bool) {
+	time.Sleep(p.WebhookDelay)
+
+	webhook := mockWebhook{Type: EventAuthorized, Reference: reference}
+	p.mu.Lock()
+	payment := p.payments[reference]
+	if payment == nil || payment.status != "PENDING" {
+		p.mu.Unlock()
+		return
+	}
+	if approve {
+		payment.status = "AUTHORIZED"
+	} else {
+		delete(p.payments, reference)
+		webhook.Type = EventDeclined
+		webhook.Code = "card_declined"
+		webhook.Message = "The card was declined"
+	}
+	p.mu.Unlock()
+
+	id, err := mockReference("evt")
+	if err != nil {
+		log.Printf("Error creating mock payment webhook: %v", err)
+		return
+	}
+	webhook.ID = id
+	if err := p.post(webhook); err != nil {
+		log.Printf("Error posting mock payment webhook %s for %s: %v", webhook.Type, reference, err)
+	}
+}
+
+// post sends a signed webhook to the webhook URL.
+func (p *MockProvider) post(webhook mockWebhook) error {
+	body, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(mockSignatureHeader, hex.EncodeToString(p.sign(body)))
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign returns the HMAC-SHA256 of a webhook body.
+func (p *MockProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(p.WebhookSecret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// mockReference returns a random reference with a prefix, e.g. "pay_3f9a...".
+func mockReference(prefix string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + "_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,660 @@
+// Package payments charges held bookings by card through a payment provider.
+// A payment is authorized and captured in one request, or completed later
+// by a webhook of the provider; once captured, the booking is confirmed.
+// Held bookings that are not paid in time are released, and cancelled
+// tickets are refunded. Captured payments and refunds are invoiced.
+package payments
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mindenairport/booking"
+	"mindenairport/database"
+	"mindenairport/invoices"
+	"mindenairport/models"
+)
+
+const (
+	statusPending    = "PENDING"
+	statusAuthorized = "AUTHORIZED"
+	statusCaptured   = "CAPTURED"
+	statusDeclined   = "DECLINED"
+	statusFailed     = "FAILED"
+	statusVoided     = "VOIDED"
+
+	refundPending   = "PENDING"
+	refundSucceeded = "SUCCEEDED"
+	refundFailed    = "FAILED"
+
+	bookingHeld     = "HELD"
+	ticketHeld      = "HELD"
+	ticketCancelled = "CANCELLED"
+
+	// idempotencyRetention is how long idempotency keys are kept.
+	idempotencyRetention = 24 * time.Hour
+	// webhookClaimTimeout is how long a webhook event may be applied before
+	// a repeated delivery takes over, e.g. after the server crashed.
+	webhookClaimTimeout = 5 * time.Minute
+)
+
+var (
+	// ErrNotPayable is returned for bookings that are not held, whose hold
+	// expired or that are already paid.
+	ErrNotPayable = errors.New("booking cannot be paid")
+	// ErrPaymentInProgress is returned when a booking already has a payment
+	// that has not completed.
+	ErrPaymentInProgress = errors.New("a payment of the booking is in progress")
+	// ErrDeclined is returned when the provider declines a payment.
+	ErrDeclined = errors.New("payment declined")
+	// ErrProvider is returned when the payment provider fails.
+	ErrProvider = errors.New("payment provider error")
+	// ErrNotRefundable is returned for payments that are not captured and
+	// for tickets that are not part of a payment or already refunded.
+	ErrNotRefundable = errors.New("cannot be refunded")
+)
+
+// Service takes payments of held bookings.
+type Service struct {
+	db       database.Database
+	bookings *booking.Service
+	provider Provider
+	invoices *invoices.Service
+
+	// locks serializes payments, webhooks, refunds and expiring holds per
+	// booking, so a booking is not charged twice or released while it is
+	// being paid. mu guards the map.
+	mu    sync.Mutex
+	locks map[string]*bookingLock
+}
+
+// bookingLock is the lock of a booking, shared by the operations waiting
+// for it.
+type bookingLock struct {
+	mu      sync.Mutex
+	waiting int
+}
+
+// NewService creates a payment service confirming bookings of the booking
+// service once they are paid through the provider, and invoicing payments
+// and refunds.
+func NewService(db database.Database, bookings *booking.Service, provider Provider, invoicing *invoices.Service) *Service {
+	return &Service{db: db, bookings: bookings, provider: provider, invoices: invoicing, locks: make(map[string]*bookingLock)}
+}
+
+// lock locks the payments of a booking and returns the function unlocking
+// them. Bookings are locked one by one, so a slow provider call only
+// delays other requests about the same booking.
+func (s *Service) lock(bookingID string) func() {
+	s.mu.Lock()
+	l := s.locks[bookingID]
+	if l == nil {
+		l = &bookingLock{}
+		s.locks[bookingID] = l
+	}
+	l.waiting++
+	s.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		s.mu.Lock()
+		l.waiting--
+		if l.waiting == 0 {
+			delete(s.locks, bookingID)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Provider returns the name of the payment provider in use.
+func (s *Service) Provider() string {
+	return s.provider.Name()
+}
+
+// Pay charges the held tickets of a booking to a card on behalf of a user.
+// The returned payment is CAPTURED and the booking confirmed, or PENDING
+// until the provider reports the outcome by webhook. Declined payments are
+// returned along with ErrDeclined.
+func (s *Service) Pay(userID string, b *models.Booking, card models.CardRequest, now time.Time) (*models.Payment, error) {
+	if err := NormalizeCard(&card, now); err != nil {
+		return nil, err
+	}
+
+	defer s.lock(b.ID)()
+
+	// Reload the booking, a concurrent request may have paid or released it
+	current, err := s.bookings.Get(b.Locator)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w: booking no longer exists", ErrNotPayable)
+	}
+	*b = *current
+	if err := s.checkPayable(b, now); err != nil {
+		return nil, err
+	}
+
+	payment := &models.Payment{
+		BookingID:     b.ID,
+		Locator:       b.Locator,
+		AirportUserID: userID,
+		Provider:      s.provider.Name(),
+		Currency:      b.Currency,
+		CardBrand:     CardBrand(card.Number),
+		CardLast4:     CardLast4(card.Number),
+		CreatedAt:     now.UTC(),
+	}
+	for _, ticket := range b.Tickets {
+		if ticket.Status == ticketHeld {
+			payment.Tickets = append(payment.Tickets, models.PaymentTicket{TicketID: ticket.ID, Amount: ticket.Price})
+			payment.Amount += ticket.Price
+		}
+	}
+	payment.Amount = round(payment.Amount)
+	if payment.Amount <= 0 {
+		return nil, fmt.Errorf("%w: nothing to pay", ErrNotPayable)
+	}
+
+	if err := s.db.CreatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	result, err := s.provider.Authorize(AuthorizeRequest{
+		Amount:         payment.Amount,
+		Currency:       payment.Currency,
+		Card:           card,
+		Description:    "MindenAirport booking " + b.Locator,
+		IdempotencyKey: payment.ID,
+	})
+	if err != nil {
+		s.fail(payment, statusFailed, "provider_error", err.Error(), now)
+		return payment, fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	payment.Reference = result.Reference
+
+	switch result.Status {
+	case ResultPending:
+		return payment, s.update(payment, statusPending, now)
+	case ResultDeclined:
+		s.fail(payment, statusDeclined, result.Code, result.Message, now)
+		return payment, fmt.Errorf("%w: %s", ErrDeclined, result.Code)
+	}
+
+	if err := s.update(payment, statusAuthorized, now); err != nil {
+		return payment, err
+	}
+	return payment, s.capture(payment, b, now)
+}
+
+// checkPayable returns ErrNotPayable or ErrPaymentInProgress unless a
+// booking is held and has no payment that is open or captured.
+func (s *Service) checkPayable(b *models.Booking, now time.Time) error {
+	if b.Status != bookingHeld {
+		return fmt.Errorf("%w: booking is %s", ErrNotPayable, strings.ToLower(b.Status))
+	}
+	if b.HoldExpiresAt != nil && !b.HoldExpiresAt.After(now) {
+		return fmt.Errorf("%w: the hold expired at %s", ErrNotPayable, b.HoldExpiresAt.Format(time.RFC3339))
+	}
+
+	payments, err := s.db.GetPaymentsByBooking(b.ID)
+	if err != nil {
+		return err
+	}
+	for _, payment := range payments {
+		switch payment.Status {
+		case statusPending, statusAuthorized:
+			return ErrPaymentInProgress
+		case statusCaptured:
+			return fmt.Errorf("%w: booking is already paid", ErrNotPayable)
+		}
+	}
+	return nil
+}
+
+// capture collects an authorized payment and confirms its booking. A
+// capture that is declined or fails voids the authorization.
+func (s *Service) capture(payment *models.Payment, b *models.Booking, now time.Time) error {
+	result, err := s.provider.Capture(payment.Reference, payment.Amount)
+	if err != nil || result.Status != ResultApproved {
+		if _, voidErr := s.provider.Void(payment.Reference); voidErr != nil {
+			log.Printf("Error voiding payment %s after a failed capture: %v", payment.ID, voidErr)
+		}
+		if err != nil {
+			s.fail(payment, statusFailed, "provider_error", err.Error(), now)
+			return fmt.Errorf("%w: %v", ErrProvider, err)
+		}
+		s.fail(payment, statusFailed, result.Code, result.Message, now)
+		return fmt.Errorf("%w: %s", ErrDeclined, result.Code)
+	}
+
+	capturedAt := now.UTC()
+	payment.CapturedAt = &capturedAt
+	if err := s.update(payment, statusCaptured, now); err != nil {
+		return err
+	}
+	if err := s.bookings.Confirm(b); err != nil {
+		return err
+	}
+
+	// Invoices that fail are issued by the periodic check
+	if _, err := s.invoices.IssueForPayment(payment.ID, now); err != nil {
+		log.Printf("Error issuing invoice for payment %s: %v", payment.ID, err)
+	}
+	return nil
+}
+
+// void releases an open payment with the provider and records why.
+func (s *Service) void(payment *models.Payment, code string, now time.Time) error {
+	if payment.Reference != "" {
+		if _, err := s.provider.Void(payment.Reference); err != nil {
+			return fmt.Errorf("%w: %v", ErrProvider, err)
+		}
+	}
+	payment.FailureCode = code
+	return s.update(payment, statusVoided, now)
+}
+
+// update stores a new status of a payment.
+func (s *Service) update(payment *models.Payment, status string, now time.Time) error {
+	payment.Status = status
+	payment.UpdatedAt = now.UTC()
+	return s.db.UpdatePayment(*payment)
+}
+
+// fail stores a declined or failed payment. Errors are only logged, as the
+// caller reports the failure itself.
+func (s *Service) fail(payment *models.Payment, status, code, message string, now time.Time) {
+	payment.FailureCode = code
+	payment.FailureMessage = message
+	if err := s.update(payment, status, now); err != nil {
+		log.Printf("Error storing %s payment %s: %v", strings.ToLower(status), payment.ID, err)
+	}
+}
+
+// HandleWebhook verifies a webhook call addressed to the named provider and
+// applies its event. Calls for any other than the configured provider are
+// rejected with ErrUnknownProvider before the signature is checked. Events
+// the provider repeats are applied only once.
+func (s *Service) HandleWebhook(provider string, header http.Header, body []byte, now time.Time) error {
+	if provider != s.provider.Name() {
+		return ErrUnknownProvider
+	}
+
+	event, err := s.provider.ParseWebhook(header, body)
+	if err != nil {
+		return err
+	}
+
+	scope := "webhook:" + s.provider.Name()
+	hash := sha256.Sum256(body)
+	claimed, err := s.db.ClaimIdempotencyKey(scope, event.ID, hex.EncodeToString(hash[:]), now, now.Add(-webhookClaimTimeout))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	if err := s.apply(event, now); err != nil {
+		// Release the event, so the provider's retry applies it
+		if delErr := s.db.DeleteIdempotencyKey(scope, event.ID); delErr != nil {
+			log.Printf("Error releasing webhook event %s: %v", event.ID, delErr)
+		}
+		return err
+	}
+	return s.db.SaveIdempotencyResponse(scope, event.ID, 200, "")
+}
+
+// apply applies a webhook event. Events about unknown payments and refunds
+// or ones that already completed are ignored.
+func (s *Service) apply(event *WebhookEvent, now time.Time) error {
+	switch event.Type {
+	case EventAuthorized, EventDeclined:
+		payment, err := s.db.GetPaymentByReference(s.provider.Name(), event.Reference)
+		if err != nil || payment == nil {
+			return err
+		}
+		defer s.lock(payment.BookingID)()
+
+		// Reload the payment, it may have been voided meanwhile
+		payment, err = s.db.GetPaymentByReference(s.provider.Name(), event.Reference)
+		if err != nil {
+			return err
+		}
+		if payment == nil || payment.Status != statusPending {
+			return nil
+		}
+		if event.Type == EventDeclined {
+			s.fail(payment, statusDeclined, event.Code, event.Message, now)
+			return nil
+		}
+
+		// The booking may have been released while the payment was pending
+		b, err := s.bookings.Get(payment.Locator)
+		if err != nil {
+			return err
+		}
+		if b == nil || b.Status != bookingHeld || (b.HoldExpiresAt != nil && !b.HoldExpiresAt.After(now)) {
+			return s.void(payment, "hold_expired", now)
+		}
+		if err := s.update(payment, statusAuthorized, now); err != nil {
+			return err
+		}
+		err = s.capture(payment, b, now)
+		if errors.Is(err, ErrDeclined) {
+			return nil
+		}
+		return err
+
+	case EventRefunded, EventRefundFailed:
+		refund, err := s.db.GetRefundByReference(s.provider.Name(), event.Reference)
+		if err != nil || refund == nil {
+			return err
+		}
+		payment, err := s.db.GetPaymentByID(refund.PaymentID)
+		if err != nil || payment == nil {
+			return err
+		}
+		defer s.lock(payment.BookingID)()
+
+		refund, err = s.db.GetRefundByReference(s.provider.Name(), event.Reference)
+		if err != nil || refund == nil || refund.Status != refundPending {
+			return err
+		}
+		status := refundSucceeded
+		if event.Type == EventRefundFailed {
+			status = refundFailed
+		}
+		if err := s.db.CompletePaymentRefund(refund.ID, "", status, now); err != nil {
+			return err
+		}
+		if status == refundSucceeded {
+			s.invoiceRefund(refund.PaymentID, refund.ID, now)
+		}
+		return nil
+	}
+	return nil
+}
+
+// Refund pays back tickets of a captured payment, or all tickets not yet
+// refunded if ticketIDs is empty. staffID is the staff member issuing the
+// refund.
+func (s *Service) Refund(payment *models.Payment, ticketIDs []string, reason, staffID string, now time.Time) (*models.PaymentRefund, error) {
+	defer s.lock(payment.BookingID)()
+
+	current, err := s.Get(payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w: payment no longer exists", ErrNotRefundable)
+	}
+	*payment = *current
+	if payment.Status != statusCaptured {
+		return nil, fmt.Errorf("payment %w: it is %s", ErrNotRefundable, strings.ToLower(payment.Status))
+	}
+
+	if len(ticketIDs) == 0 {
+		for _, ticket := range payment.Tickets {
+			if ticket.RefundID == "" {
+				ticketIDs = append(ticketIDs, ticket.TicketID)
+			}
+		}
+		if len(ticketIDs) == 0 {
+			return nil, fmt.Errorf("payment %w: all tickets are refunded", ErrNotRefundable)
+		}
+	}
+	return s.refund(payment, ticketIDs, reason, staffID, now)
+}
+
+// refund pays back tickets of a captured payment loaded with its tickets.
+// The caller must hold the lock of the booking.
+func (s *Service) refund(payment *models.Payment, ticketIDs []string, reason, staffID string, now time.Time) (*models.PaymentRefund, error) {
+	amounts := make(map[string]float64)
+	for _, ticket := range payment.Tickets {
+		if ticket.RefundID == "" {
+			amounts[ticket.TicketID] = ticket.Amount
+		}
+	}
+
+	refund := &models.PaymentRefund{
+		PaymentID: payment.ID,
+		Reason:    reason,
+		CreatedAt: now.UTC(),
+		CreatedBy: staffID,
+	}
+	for _, id := range ticketIDs {
+		amount, ok := amounts[id]
+		if !ok {
+			return nil, fmt.Errorf("ticket %s %w: it is not paid by the payment or already refunded", id, ErrNotRefundable)
+		}
+		refund.Amount += amount
+	}
+	refund.Amount = round(refund.Amount)
+
+	created, err := s.db.CreatePaymentRefund(refund, ticketIDs)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, fmt.Errorf("tickets %w: they were refunded meanwhile", ErrNotRefundable)
+	}
+
+	// Refunds of nothing, e.g. of free tickets, need no provider call
+	result := Result{Status: ResultApproved}
+	if refund.Amount > 0 {
+		result, err = s.provider.Refund(payment.Reference, refund.Amount)
+	}
+	status := refundSucceeded
+	switch {
+	case err != nil:
+		status = refundFailed
+	case result.Status == ResultPending:
+		status = refundPending
+	case result.Status == ResultDeclined:
+		status = refundFailed
+	}
+
+	if completeErr := s.db.CompletePaymentRefund(refund.ID, result.Reference, status, now); completeErr != nil {
+		return nil, completeErr
+	}
+	refund.Reference = result.Reference
+	refund.Status = status
+	if status == refundSucceeded && refund.Amount > 0 {
+		s.invoiceRefund(payment.ID, refund.ID, now)
+	}
+
+	if err != nil {
+		return refund, fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	if result.Status == ResultDeclined {
+		return refund, fmt.Errorf("%w: %s", ErrDeclined, result.Code)
+	}
+	return refund, nil
+}
+
+// invoiceRefund issues the credit note of a refund. Credit notes that fail
+// are issued by the periodic check.
+func (s *Service) invoiceRefund(paymentID, refundID string, now time.Time) {
+	if _, err := s.invoices.IssueForRefund(paymentID, refundID, now); err != nil {
+		log.Printf("Error issuing credit note for refund %s: %v", refundID, err)
+	}
+}
+
+// Settle brings the payments of a booking in line with its tickets after it
+// was cancelled: open payments are voided and captured payments refund the
+// cancelled tickets.
+func (s *Service) Settle(b *models.Booking, now time.Time) error {
+	defer s.lock(b.ID)()
+	return s.settle(b, now)
+}
+
+// settle settles the payments of a booking. The caller must hold the lock
+// of the booking.
+func (s *Service) settle(b *models.Booking, now time.Time) error {
+	tickets, err := s.db.GetBookingTickets(b.ID)
+	if err != nil {
+		return err
+	}
+	cancelled := make(map[string]bool)
+	for _, ticket := range tickets {
+		if ticket.Status == ticketCancelled {
+			cancelled[ticket.ID] = true
+		}
+	}
+
+	payments, err := s.ForBooking(b.ID)
+	if err != nil {
+		return err
+	}
+	for i := range payments {
+		payment := &payments[i]
+		switch payment.Status {
+		case statusPending, statusAuthorized:
+			if err := s.void(payment, "booking_cancelled", now); err != nil {
+				return err
+			}
+		case statusCaptured:
+			var refundable []string
+			for _, ticket := range payment.Tickets {
+				if ticket.RefundID == "" && cancelled[ticket.TicketID] {
+					refundable = append(refundable, ticket.TicketID)
+				}
+			}
+			if len(refundable) == 0 {
+				continue
+			}
+			if _, err := s.refund(payment, refundable, "Booking cancelled", "", now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExpireHolds releases the held bookings whose hold expired at now, voiding
+// their open payments, and removes idempotency keys past their retention.
+// It returns the number of bookings released.
+func (s *Service) ExpireHolds(now time.Time) (int, error) {
+	bookings, err := s.bookings.ExpiredHolds(now)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range bookings {
+		ok, err := s.expireHold(bookings[i].ID, bookings[i].Locator, now)
+		if err != nil {
+			return released, err
+		}
+		if ok {
+			released++
+		}
+	}
+
+	if _, err := s.db.DeleteIdempotencyKeysBefore(now.Add(-idempotencyRetention)); err != nil {
+		return released, err
+	}
+	return released, nil
+}
+
+// expireHold releases a booking whose hold expired, unless it was paid or
+// released meanwhile, and settles its payments. It reports whether the
+// booking was released.
+func (s *Service) expireHold(bookingID, locator string, now time.Time) (bool, error) {
+	defer s.lock(bookingID)()
+
+	b, err := s.bookings.Get(locator)
+	if err != nil {
+		return false, err
+	}
+	if b == nil || b.Status != bookingHeld || b.HoldExpiresAt == nil || b.HoldExpiresAt.After(now) {
+		return false, nil
+	}
+	if err := s.bookings.ExpireHold(b, now); err != nil {
+		return false, err
+	}
+	if err := s.settle(b, now); err != nil {
+		log.Printf("Error settling payments of expired booking %s: %v", b.Locator, err)
+	}
+	return true, nil
+}
+
+// Get returns a payment with its tickets and refunds, or nil if not found.
+func (s *Service) Get(id string) (*models.Payment, error) {
+	payment, err := s.db.GetPaymentByID(id)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	if err := s.load(payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// ForBooking returns the payments of a booking with their tickets and
+// refunds, oldest first.
+func (s *Service) ForBooking(bookingID string) ([]models.Payment, error) {
+	payments, err := s.db.GetPaymentsByBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if payments == nil {
+		payments = []models.Payment{}
+	}
+	for i := range payments {
+		if err := s.load(&payments[i]); err != nil {
+			return nil, err
+		}
+	}
+	return payments, nil
+}
+
+// load reads the tickets and refunds of a payment.
+func (s *Service) load(payment *models.Payment) error {
+	tickets, err := s.db.GetPaymentTickets(payment.ID)
+	if err != nil {
+		return err
+	}
+	refunds, err := s.db.GetPaymentRefunds(payment.ID)
+	if err != nil {
+		return err
+	}
+
+	payment.Tickets = tickets
+	if payment.Tickets == nil {
+		payment.Tickets = []models.PaymentTicket{}
+	}
+	payment.Refunds = refunds
+	if payment.Refunds == nil {
+		payment.Refunds = []models.PaymentRefund{}
+	}
+	return nil
+}
+
+// round rounds an amount to cents.
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// intFromEnv reads a positive integer from an environment variable,
+// falling back to the default if it is unset or invalid.
+func intFromEnv(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"mindenairport/models"
+)
+
+// Outcomes of a provider operation.
+const (
+	ResultApproved = "APPROVED"
+	ResultPending  = "PENDING" // Completed later, reported by webhook
+	ResultDeclined = "DECLINED"
+)
+
+// Types of webhook events.
+const (
+	EventAuthorized   = "payment.authorized"
+	EventDeclined     = "payment.declined"
+	EventRefunded     = "refund.succeeded"
+	EventRefundFailed = "refund.failed"
+)
+
+var (
+	// ErrInvalidWebhook is returned for webhook calls whose signature or body
+	// cannot be verified.
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	// ErrUnknownProvider is returned for webhook calls addressed to another
+	// provider than the configured one.
+	ErrUnknownProvider = errors.New("unknown payment provider")
+)
+
+// AuthorizeRequest reserves an amount on a card.
+type AuthorizeRequest struct {
+	Amount         float64
+	Currency       string
+	Card           models.CardRequest
+	Description    string // Shown on the card statement
+	IdempotencyKey string // Lets the provider recognize retries of the same authorization
+}
+
+// Result is the outcome of a provider operation. Declines are results, not
+// errors; errors mean the provider could not be reached or failed.
+type Result struct {
+	Reference string // Provider ID of the payment or refund
+	Status    string // ResultApproved, ResultPending or ResultDeclined
+	Code      string // Reason of a decline, e.g. "insufficient_funds"
+	Message   string
+}
+
+// WebhookEvent is a verified notification of the provider about a payment
+// or refund that completed after the request that started it.
+type WebhookEvent struct {
+	ID        string // Unique per event; retries of the provider repeat it
+	Type      string // One of the Event constants
+	Reference string // Provider ID of the payment or refund
+	Code      string
+	Message   string
+}
+
+// Provider is a payment service provider. New providers are added by
+// implementing this interface and selecting them in ProviderFromEnv.
+type Provider interface {
+	// Name returns a short identifier stored with each payment
+	Name() string
+	// Authorize reserves the amount on the card
+	Authorize(req AuthorizeRequest) (Result, error)
+	// Capture collects an authorized amount
+	Capture(reference string, amount float64) (Result, error)
+	// Void releases an authorization that was not captured
+	Void(reference string) (Result, error)
+	// Refund pays back part or all of a captured amount
+	Refund(reference string, amount float64) (Result, error)
+	// ParseWebhook verifies and decodes a webhook call of the provider
+	ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+// ProviderFromEnv returns the provider selected by PAYMENT_PROVIDER. Only
+// "mock" (the default) ships with the backend; it is configured by:
+//
+//	MOCK_PAYMENT_WEBHOOK_URL            (default http://localhost:8080/api/payments/webhook/mock)
+//	MOCK_PAYMENT_WEBHOOK_SECRET         (default development secret)
+//	MOCK_PAYMENT_WEBHOOK_DELAY_SECONDS  (default 5)
+func ProviderFromEnv() (Provider, error) {
+	switch name := os.Getenv("PAYMENT_PROVIDER"); name {
+	case "", "mock":
+		url := os.Getenv("MOCK_PAYMENT_WEBHOOK_URL")
+		if url == "" {
+			url = "http://localhost:8080/api/payments/webhook/mock"
+		}
+		secret := os.Getenv("MOCK_PAYMENT_WEBHOOK_SECRET")
+		if secret == "" {
+			secret = "mindenairport-development-webhook-secret"
+		}
+		return NewMockProvider(url, secret, time.Duration(intFromEnv("MOCK_PAYMENT_WEBHOOK_DELAY_SECONDS", 5))*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", name)
+	}
+}
@@ -0,0 +1,34 @@
+package planning
+
+import (
+	"fmt"
+
+	"mindenairport/models"
+)
+
+// ValidateAirline checks that an airline exists and is active. Deactivated
+// airlines keep their planes and flights, but must not get new ones.
+func (p *Planner) ValidateAirline(id string) error {
+	airline := p.db.GetAirlineByID(id)
+	if airline.ID == "" {
+		return &ValidationError{Reason: fmt.Sprintf("airline %s does not exist", id)}
+	}
+	if !airline.Active {
+		return &ValidationError{Reason: fmt.Sprintf("airline %s is not active", airline.ID)}
+	}
+	return nil
+}
+
+// ValidateFlightAirline checks that a new flight is operated by an active
+// airline, i.e. that the airline of its plane is active. Planes without an
+// airline are not checked; a missing plane is reported by ValidatePlane.
+func (p *Planner) ValidateFlightAirline(flight models.Flight) error {
+	plane, err := p.db.GetPlaneByID(flight.PlaneID)
+	if err != nil {
+		return err
+	}
+	if plane == nil || plane.AirlineID == "" {
+		return nil
+	}
+	return p.ValidateAirline(plane.AirlineID)
+}
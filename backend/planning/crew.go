@@ -0,0 +1,232 @@
+package planning
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"mindenairport/models"
+)
+
+// DutyPeriod is a continuous period of duty of a crew member. Flights belong
+// to the same duty period if the break between them is shorter than the
+// minimum rest.
+type DutyPeriod struct {
+	Start      time.Time `json:"start"`                // Report time for the first flight
+	End        time.Time `json:"end"`                  // Release time after the last flight
+	DutyHours  float64   `json:"dutyHours"`            // Length of the duty period in hours
+	RestBefore float64   `json:"restBefore,omitempty"` // Rest since the previous duty period in hours
+	Flights    []string  `json:"flights"`              // IDs of the flights in the duty period
+}
+
+// CrewRoster lists the upcoming duties of a crew member.
+type CrewRoster struct {
+	CrewMember  models.CrewMember `json:"crewMember"`
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	Duties      []models.CrewDuty `json:"duties"`
+	DutyPeriods []DutyPeriod      `json:"dutyPeriods"`
+}
+
+// dutyFromFlight describes a flight as a duty for the duty time calculation.
+func dutyFromFlight(flight models.Flight) models.CrewDuty {
+	return models.CrewDuty{
+		FlightID:           flight.ID,
+		From:               flight.From,
+		To:                 flight.To,
+		ScheduledDeparture: flight.ScheduledDeparture,
+		ScheduledArrival:   flight.ScheduledArrival,
+	}
+}
+
+// DutyPeriods groups duties into duty periods. Each duty starts with the
+// report time before departure and ends with the release time after arrival.
+func (c Config) DutyPeriods(duties []models.CrewDuty) []DutyPeriod {
+	sorted := make([]models.CrewDuty, len(duties))
+	copy(sorted, duties)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ScheduledDeparture.Before(sorted[j].ScheduledDeparture)
+	})
+
+	var periods []DutyPeriod
+	for _, duty := range sorted {
+		start := duty.ScheduledDeparture.Add(-c.CrewReportTime)
+		end := duty.ScheduledArrival.Add(c.CrewReleaseTime)
+
+		if n := len(periods); n > 0 && start.Sub(periods[n-1].End) < c.MinRest {
+			last := &periods[n-1]
+			if end.After(last.End) {
+				last.End = end
+			}
+			last.DutyHours = last.End.Sub(last.Start).Hours()
+			last.Flights = append(last.Flights, duty.FlightID)
+			continue
+		}
+
+		period := DutyPeriod{
+			Start:     start,
+			End:       end,
+			DutyHours: end.Sub(start).Hours(),
+			Flights:   []string{duty.FlightID},
+		}
+		if n := len(periods); n > 0 {
+			period.RestBefore = start.Sub(periods[n-1].End).Hours()
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// dutyWithin returns the duty time of the periods falling within [start, end).
+func dutyWithin(periods []DutyPeriod, start, end time.Time) time.Duration {
+	var total time.Duration
+	for _, p := range periods {
+		from, to := p.Start, p.End
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			total += to.Sub(from)
+		}
+	}
+	return total
+}
+
+// CheckDutyLimits verifies that adding flight to the existing duties of a crew
+// member keeps the duty period containing the flight within the maximum duty
+// period and every 7-day window touching it within the weekly duty limit.
+func (c Config) CheckDutyLimits(flight models.Flight, duties []models.CrewDuty) error {
+	all := make([]models.CrewDuty, 0, len(duties)+1)
+	all = append(all, duties...)
+	periods := c.DutyPeriods(append(all, dutyFromFlight(flight)))
+
+	for _, p := range periods {
+		contains := false
+		for _, id := range p.Flights {
+			if id == flight.ID {
+				contains = true
+				break
+			}
+		}
+		if !contains {
+			continue
+		}
+
+		if p.End.Sub(p.Start) > c.MaxDutyPeriod {
+			return &ValidationError{Reason: fmt.Sprintf(
+				"duty period from %s to %s would last %.1f hours, the limit is %.0f hours",
+				p.Start.Format(time.RFC3339), p.End.Format(time.RFC3339), p.DutyHours, c.MaxDutyPeriod.Hours())}
+		}
+
+		// Check every 7-day window ending at the end of a duty period that overlaps this one
+		week := 7 * 24 * time.Hour
+		for _, other := range periods {
+			if other.End.Before(p.End) || other.End.Sub(p.End) > week {
+				continue
+			}
+			if total := dutyWithin(periods, other.End.Add(-week), other.End); total > c.MaxWeeklyDuty {
+				return &ValidationError{Reason: fmt.Sprintf(
+					"duty time in the 7 days before %s would be %.1f hours, the limit is %.0f hours",
+					other.End.Format(time.RFC3339), total.Hours(), c.MaxWeeklyDuty.Hours())}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateCrewAssignment checks whether a crew member may be assigned to a
+// flight: the license must be valid until the flight arrives, the crew
+// member must not be booked on an overlapping flight, and the duty and rest
+// limits must be kept.
+func (p *Planner) ValidateCrewAssignment(member models.CrewMember, flight models.Flight) error {
+	return p.checkCrewMember(member, flight, false)
+}
+
+// ValidateCrew re-checks the crew assigned to a flight against its times,
+// e.g. before the flight is moved, with the same rules as a new assignment.
+func (p *Planner) ValidateCrew(flight models.Flight) error {
+	if flight.ID == "" {
+		return nil
+	}
+	crew, err := p.db.GetFlightCrew(flight.ID)
+	if err != nil {
+		return err
+	}
+	for _, assignment := range crew {
+		member, err := p.db.GetCrewMemberByID(assignment.CrewMemberID)
+		if err != nil {
+			return err
+		}
+		if member == nil {
+			continue
+		}
+		if err := p.checkCrewMember(*member, flight, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCrewMember checks license, overlaps and duty limits of a crew member
+// on a flight. If the member is already assigned to the flight, the stored
+// duty of the flight is replaced by its new times instead of being a
+// double assignment.
+func (p *Planner) checkCrewMember(member models.CrewMember, flight models.Flight, assigned bool) error {
+	if member.LicenseExpiry != nil && member.LicenseExpiry.Before(flight.ScheduledArrival) {
+		return &ValidationError{Reason: fmt.Sprintf("license of crew member %s expires on %s",
+			member.ID, member.LicenseExpiry.Format("2006-01-02"))}
+	}
+
+	// Look far enough around the flight to cover a full week of duties on both sides
+	margin := 7*24*time.Hour + p.Config.MaxDutyPeriod + p.Config.MinRest
+	duties, err := p.db.GetCrewDuties(member.ID, flight.ScheduledDeparture.Add(-margin), flight.ScheduledDeparture.Add(margin))
+	if err != nil {
+		return err
+	}
+
+	var others []models.CrewDuty
+	var overlapping []string
+	for _, duty := range duties {
+		if duty.FlightID == flight.ID {
+			if assigned {
+				continue
+			}
+			return &ValidationError{Reason: fmt.Sprintf("crew member %s is already assigned to flight %s", member.ID, flight.ID)}
+		}
+		if duty.ScheduledDeparture.Before(flight.ScheduledArrival) && flight.ScheduledDeparture.Before(duty.ScheduledArrival) {
+			overlapping = append(overlapping, duty.FlightID)
+		}
+		others = append(others, duty)
+	}
+
+	if len(overlapping) > 0 {
+		return &ConflictError{Resource: "crew member", ID: member.ID, Flights: overlapping}
+	}
+
+	return p.Config.CheckDutyLimits(flight, others)
+}
+
+// CrewRoster returns the duties of a crew member departing within [from, to)
+// together with the resulting duty periods.
+func (p *Planner) CrewRoster(member models.CrewMember, from, to time.Time) (CrewRoster, error) {
+	roster := CrewRoster{CrewMember: member, From: from, To: to}
+
+	duties, err := p.db.GetCrewDuties(member.ID, from, to)
+	if err != nil {
+		return roster, err
+	}
+
+	roster.Duties = duties
+	if roster.Duties == nil {
+		roster.Duties = []models.CrewDuty{}
+	}
+	roster.DutyPeriods = p.Config.DutyPeriods(duties)
+	if roster.DutyPeriods == nil {
+		roster.DutyPeriods = []DutyPeriod{}
+	}
+	return roster, nil
+}
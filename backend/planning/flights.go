@@ -0,0 +1,86 @@
+package planning
+
+import (
+	"fmt"
+
+	"mindenairport/models"
+	"mindenairport/timezone"
+)
+
+// ValidateSchedule checks that both airports of a flight exist and have a
+// valid time zone, and that the flight arrives after it departs. Times are
+// compared as instants, so a flight may land at an earlier local time than
+// it took off.
+func (p *Planner) ValidateSchedule(flight models.Flight) error {
+	for _, id := range []string{flight.From, flight.To} {
+		airport := p.db.GetAirportByID(id)
+		if airport.ID == "" {
+			return &ValidationError{Reason: fmt.Sprintf("airport %s does not exist", id)}
+		}
+		if _, err := timezone.AirportLocation(airport); err != nil {
+			return &ValidationError{Reason: err.Error()}
+		}
+	}
+
+	if flight.ScheduledDeparture.IsZero() || flight.ScheduledArrival.IsZero() {
+		return &ValidationError{Reason: "scheduled departure and arrival are required"}
+	}
+	if !flight.ScheduledArrival.After(flight.ScheduledDeparture) {
+		return &ValidationError{Reason: "scheduled arrival must be after scheduled departure"}
+	}
+	if flight.ActualDeparture != nil && flight.ActualArrival != nil &&
+		!flight.ActualArrival.After(*flight.ActualDeparture) {
+		return &ValidationError{Reason: "actual arrival must be after actual departure"}
+	}
+	return nil
+}
+
+// ValidateFlight runs the schedule check and all resource checks for a
+// flight that is about to be created or updated, including the duty limits
+// of its assigned crew. The first failing check is returned. Cancelled
+// flights no longer use their resources, so only their schedule is checked.
+func (p *Planner) ValidateFlight(flight models.Flight) error {
+	return p.validateFlight(flight, true)
+}
+
+// ValidateImportedFlight checks a flight generated from a schedule, by the
+// SSIM import or a schedule template, like ValidateFlight. Such flights get
+// their pilot rostered later, so the pilot is only checked once assigned.
+func (p *Planner) ValidateImportedFlight(flight models.Flight) error {
+	return p.validateFlight(flight, false)
+}
+
+// validateFlight runs the checks of ValidateFlight, skipping the pilot
+// check for flights without a pilot unless requirePilot is set.
+func (p *Planner) validateFlight(flight models.Flight, requirePilot bool) error {
+	if err := p.ValidateSchedule(flight); err != nil {
+		return err
+	}
+	if flight.StatusID == models.FlightStatusCancelled {
+		return nil
+	}
+	if err := p.ValidatePlane(flight); err != nil {
+		return err
+	}
+	if requirePilot || flight.PilotID != "" {
+		if err := p.ValidatePilot(flight); err != nil {
+			return err
+		}
+	}
+	if err := p.ValidateGate(flight); err != nil {
+		return err
+	}
+	return p.ValidateCrew(flight)
+}
+
+// ApplyFlightHours credits the block time of completed flights to their
+// pilots after a flight changed from old to updated. For new flights old is
+// the zero value.
+func (p *Planner) ApplyFlightHours(old, updated models.Flight) error {
+	for pilotID, hours := range FlightHoursChanges(old, updated) {
+		if err := p.db.AddPilotFlightHours(pilotID, hours); err != nil {
+			return err
+		}
+	}
+	return nil
+}
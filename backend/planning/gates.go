@@ -0,0 +1,333 @@
+package planning
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"mindenairport/aircraft"
+	"mindenairport/models"
+)
+
+// Gate areas and stand types as stored in the GATE table.
+const (
+	AreaSchengen    = "SCHENGEN"
+	AreaNonSchengen = "NON_SCHENGEN"
+	StandContact    = "CONTACT"
+	StandRemote     = "REMOTE"
+)
+
+// gateWindow returns the time span a departing flight occupies its gate.
+// Delayed flights occupy the gate until their actual departure.
+func (c Config) gateWindow(f models.Flight) (time.Time, time.Time) {
+	departure := f.ScheduledDeparture
+	if f.ActualDeparture != nil && f.ActualDeparture.After(departure) {
+		departure = *f.ActualDeparture
+	}
+	return departure.Add(-c.GateOccupancy), departure
+}
+
+// GateClosing returns the time the gate of a departing flight closes for
+// boarding. Delays postpone the gate closing accordingly.
+func (c Config) GateClosing(f models.Flight) time.Time {
+	_, departure := c.gateWindow(f)
+	return departure.Add(-c.GateClose)
+}
+
+// gateOverlap reports whether two flights would use a gate at overlapping
+// times, taking the buffer between consecutive flights into account.
+func (c Config) gateOverlap(a, b models.Flight) bool {
+	aStart, aEnd := c.gateWindow(a)
+	bStart, bEnd := c.gateWindow(b)
+	return aStart.Before(bEnd.Add(c.GateBuffer)) && bStart.Before(aEnd.Add(c.GateBuffer))
+}
+
+// GateConflicts returns the flights from others that are assigned to the same
+// gate as flight at overlapping times. Only departures from the home airport
+// use its gates; the flight itself, cancelled flights and inbound flights are
+// ignored.
+func (c Config) GateConflicts(flight models.Flight, others []models.Flight) []models.Flight {
+	var conflicts []models.Flight
+	if flight.Gate == "" || flight.From != c.HomeAirport {
+		return conflicts
+	}
+
+	for _, other := range others {
+		if other.ID == flight.ID || other.Gate != flight.Gate || other.From != c.HomeAirport ||
+			other.StatusID == models.FlightStatusCancelled {
+			continue
+		}
+		if c.gateOverlap(flight, other) {
+			conflicts = append(conflicts, other)
+		}
+	}
+	return conflicts
+}
+
+// flightArea describes whether a flight needs a Schengen or non-Schengen gate.
+// An empty area means the airports' countries are unknown and any area fits.
+func flightArea(from, to models.Airport) string {
+	if from.Country == "" || to.Country == "" {
+		return ""
+	}
+	if IsSchengenFlight(from, to) {
+		return AreaSchengen
+	}
+	return AreaNonSchengen
+}
+
+// CheckGateCompatibility verifies that a gate is open, belongs to the
+// terminal of the flight, is large enough for the plane and located in the
+// passport control area required by the flight. An empty terminal skips the
+// terminal check, a nil plane or an unknown aircraft model skips the size
+// check, an empty area skips the area check.
+func CheckGateCompatibility(gate models.Gate, terminalID string, plane *models.Plane, area string) error {
+	if gate.Status != "" && gate.Status != "ACTIVE" {
+		return &ValidationError{Reason: fmt.Sprintf("gate %s is not in service (%s)", gate.ID, gate.Status)}
+	}
+
+	if terminalID != "" && gate.TerminalID != terminalID {
+		return &ValidationError{Reason: fmt.Sprintf("gate %s is in terminal %s, the flight departs from terminal %s",
+			gate.ID, gate.TerminalID, terminalID)}
+	}
+
+	if plane != nil {
+		if t, ok := aircraft.Lookup(plane.Model); ok && t.CodeLetter() > gate.MaxAircraftCode {
+			return &ValidationError{Reason: fmt.Sprintf(
+				"gate %s accepts aircraft up to code %s, but %s is code %s",
+				gate.ID, gate.MaxAircraftCode, plane.Model, t.CodeLetter())}
+		}
+	}
+
+	if area != "" && gate.Area != area {
+		return &ValidationError{Reason: fmt.Sprintf("gate %s is in the %s area, the flight requires %s", gate.ID, gate.Area, area)}
+	}
+
+	return nil
+}
+
+// ValidateGate checks the gate assignment of a flight departing from the
+// home airport: the gate must exist, belong to the flight's terminal, fit the
+// aircraft and flight area, and must not be used by another flight at
+// overlapping times (including the configured buffer). Gates of inbound
+// flights refer to the departure airport and are not checked.
+func (p *Planner) ValidateGate(flight models.Flight) error {
+	if flight.Gate == "" || flight.From != p.Config.HomeAirport {
+		return nil
+	}
+
+	gate, err := p.db.GetGateByID(flight.Gate)
+	if err != nil {
+		return err
+	}
+	if gate == nil {
+		return &ValidationError{Reason: fmt.Sprintf("gate %s does not exist", flight.Gate)}
+	}
+
+	plane, err := p.db.GetPlaneByID(flight.PlaneID)
+	if err != nil {
+		return err
+	}
+
+	area := flightArea(p.db.GetAirportByID(flight.From), p.db.GetAirportByID(flight.To))
+	if err := CheckGateCompatibility(*gate, flight.TerminalID, plane, area); err != nil {
+		return err
+	}
+
+	// Delays can stretch a gate occupancy, so look at a whole day around the departure
+	others, err := p.db.GetFlightsInWindow(flight.ScheduledDeparture.Add(-24*time.Hour), flight.ScheduledDeparture.Add(24*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	if conflicts := p.Config.GateConflicts(flight, others); len(conflicts) > 0 {
+		return &ConflictError{Resource: "gate", ID: gate.ID, Flights: flightIDs(conflicts)}
+	}
+
+	return nil
+}
+
+// GateAssignment is the proposed gate of one flight in a gate plan.
+type GateAssignment struct {
+	FlightID           string    `json:"flightId"`
+	ScheduledDeparture time.Time `json:"scheduledDeparture"`
+	Gate               string    `json:"gate"`
+	PreviousGate       string    `json:"previousGate,omitempty"`
+	Changed            bool      `json:"changed"`
+}
+
+// UnassignedFlight is a flight for which no conflict-free gate was found.
+type UnassignedFlight struct {
+	FlightID string `json:"flightId"`
+	Reason   string `json:"reason"`
+}
+
+// GatePlan is a proposed conflict-free gate assignment for all departures of a day.
+type GatePlan struct {
+	Date        string             `json:"date"`
+	Assignments []GateAssignment   `json:"assignments"`
+	Unassigned  []UnassignedFlight `json:"unassigned"`
+}
+
+// gateCandidate bundles a flight with the information needed to pick a gate.
+type gateCandidate struct {
+	flight models.Flight
+	plane  *models.Plane
+	area   string
+}
+
+// ProposeGatePlan builds a conflict-free gate plan for all flights departing
+// from the home airport on the given local day. Flights keep their current
+// gate where possible; the plan is only a proposal and is not stored.
+func (p *Planner) ProposeGatePlan(day time.Time) (GatePlan, error) {
+	day = day.In(p.Config.Location)
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	// Days with a daylight saving time change have 23 or 25 hours
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	plan := GatePlan{Date: dayStart.Format("2006-01-02")}
+
+	gates, err := p.db.GetGates()
+	if err != nil {
+		return plan, err
+	}
+
+	// Include neighbouring flights so gates used around midnight stay blocked
+	margin := p.Config.GateOccupancy + p.Config.GateBuffer
+	flights, err := p.db.GetFlightsInWindow(dayStart.Add(-margin), dayEnd.Add(margin))
+	if err != nil {
+		return plan, err
+	}
+
+	airports := make(map[string]models.Airport)
+	airport := func(id string) models.Airport {
+		if a, ok := airports[id]; ok {
+			return a
+		}
+		a := p.db.GetAirportByID(id)
+		airports[id] = a
+		return a
+	}
+
+	var candidates []gateCandidate
+	var fixed []models.Flight
+	for _, f := range flights {
+		if f.From != p.Config.HomeAirport || f.StatusID == models.FlightStatusCancelled {
+			continue
+		}
+		if f.ScheduledDeparture.Before(dayStart) || !f.ScheduledDeparture.Before(dayEnd) {
+			fixed = append(fixed, f)
+			continue
+		}
+		plane, err := p.db.GetPlaneByID(f.PlaneID)
+		if err != nil {
+			return plan, err
+		}
+		candidates = append(candidates, gateCandidate{
+			flight: f,
+			plane:  plane,
+			area:   flightArea(airport(f.From), airport(f.To)),
+		})
+	}
+
+	plan.Assignments, plan.Unassigned = p.Config.assignGates(candidates, gates, fixed)
+	return plan, nil
+}
+
+// assignGates greedily assigns gates to the candidates in departure order.
+// A flight keeps its current gate if that is still valid; otherwise contact
+// stands are preferred and the smallest fitting stand is chosen so large
+// stands stay available for large aircraft. Flights in fixed are not
+// reassigned but block their gates.
+func (c Config) assignGates(candidates []gateCandidate, gates []models.Gate, fixed []models.Flight) ([]GateAssignment, []UnassignedFlight) {
+	assignments := []GateAssignment{}
+	unassigned := []UnassignedFlight{}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].flight.ScheduledDeparture.Before(candidates[j].flight.ScheduledDeparture)
+	})
+
+	ordered := make([]models.Gate, len(gates))
+	copy(ordered, gates)
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.StandType != b.StandType {
+			return a.StandType == StandContact
+		}
+		if a.MaxAircraftCode != b.MaxAircraftCode {
+			return a.MaxAircraftCode < b.MaxAircraftCode
+		}
+		return a.ID < b.ID
+	})
+
+	occupied := make(map[string][]models.Flight)
+	for _, f := range fixed {
+		if f.Gate != "" {
+			occupied[f.Gate] = append(occupied[f.Gate], f)
+		}
+	}
+
+	fits := func(cand gateCandidate, gate models.Gate) bool {
+		if CheckGateCompatibility(gate, cand.flight.TerminalID, cand.plane, cand.area) != nil {
+			return false
+		}
+		probe := cand.flight
+		probe.Gate = gate.ID
+		return len(c.GateConflicts(probe, occupied[gate.ID])) == 0
+	}
+
+	for _, cand := range candidates {
+		chosen := ""
+		for _, gate := range ordered {
+			if gate.ID == cand.flight.Gate && fits(cand, gate) {
+				chosen = gate.ID
+				break
+			}
+		}
+		if chosen == "" {
+			for _, gate := range ordered {
+				if fits(cand, gate) {
+					chosen = gate.ID
+					break
+				}
+			}
+		}
+
+		if chosen == "" {
+			reason := "all compatible gates are occupied"
+			compatible := false
+			for _, gate := range ordered {
+				if CheckGateCompatibility(gate, cand.flight.TerminalID, cand.plane, cand.area) == nil {
+					compatible = true
+					break
+				}
+			}
+			if !compatible {
+				reason = "no gate in the terminal is compatible with the aircraft and flight area"
+			}
+			unassigned = append(unassigned, UnassignedFlight{FlightID: cand.flight.ID, Reason: reason})
+			continue
+		}
+
+		placed := cand.flight
+		placed.Gate = chosen
+		occupied[chosen] = append(occupied[chosen], placed)
+		assignments = append(assignments, GateAssignment{
+			FlightID:           cand.flight.ID,
+			ScheduledDeparture: cand.flight.ScheduledDeparture,
+			Gate:               chosen,
+			PreviousGate:       cand.flight.Gate,
+			Changed:            chosen != cand.flight.Gate,
+		})
+	}
+
+	return assignments, unassigned
+}
+
+// flightIDs returns the IDs of the given flights.
+func flightIDs(flights []models.Flight) []string {
+	ids := make([]string, 0, len(flights))
+	for _, f := range flights {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
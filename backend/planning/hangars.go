@@ -0,0 +1,99 @@
+package planning
+
+import (
+	"fmt"
+
+	"mindenairport/aircraft"
+	"mindenairport/models"
+)
+
+// HangarOccupancy sums up the planes parked in a hangar and the space left.
+// Planes of unknown aircraft types count against the capacity but not
+// against the floor space.
+func HangarOccupancy(hangar models.Hangar, parked []models.Plane) models.HangarOccupancy {
+	occupancy := models.HangarOccupancy{
+		Hangar:     hangar,
+		Planes:     []models.Plane{},
+		Unmeasured: []string{},
+	}
+
+	for _, plane := range parked {
+		occupancy.Planes = append(occupancy.Planes, plane)
+		if t, ok := aircraft.Lookup(plane.Model); ok {
+			occupancy.UsedSqFt += t.FootprintSqFt()
+		} else {
+			occupancy.Unmeasured = append(occupancy.Unmeasured, plane.ID)
+		}
+	}
+
+	occupancy.FreeSqFt = max(hangar.SizeSqFt-occupancy.UsedSqFt, 0)
+	occupancy.FreeSlots = max(hangar.Capacity-len(parked), 0)
+	return occupancy
+}
+
+// CheckHangarAllocation verifies that a plane can be parked in a hangar next
+// to the planes already parked there: the hangar must be open, must have a
+// free slot and enough floor space for the aircraft. An unknown aircraft
+// model skips the floor space check; a capacity or size of 0 is unlimited.
+func CheckHangarAllocation(hangar models.Hangar, parked []models.Plane, plane models.Plane) error {
+	if hangar.Status == "MAINTENANCE" || hangar.Status == "CLOSED" {
+		return &ValidationError{Reason: fmt.Sprintf("hangar %s does not accept aircraft (%s)", hangar.ID, hangar.Status)}
+	}
+
+	others := make([]models.Plane, 0, len(parked))
+	for _, p := range parked {
+		if p.ID != plane.ID {
+			others = append(others, p)
+		}
+	}
+	occupancy := HangarOccupancy(hangar, others)
+
+	if hangar.Capacity > 0 && occupancy.FreeSlots == 0 {
+		return &ValidationError{Reason: fmt.Sprintf("hangar %s is full (%d of %d planes)", hangar.ID, len(others), hangar.Capacity)}
+	}
+
+	if t, ok := aircraft.Lookup(plane.Model); ok && hangar.SizeSqFt > 0 && t.FootprintSqFt() > occupancy.FreeSqFt {
+		return &ValidationError{Reason: fmt.Sprintf(
+			"hangar %s has %.0f sq ft left, but %s needs %.0f sq ft",
+			hangar.ID, occupancy.FreeSqFt, plane.Model, t.FootprintSqFt())}
+	}
+
+	return nil
+}
+
+// ValidateHangar checks the hangar assignment of a plane: the hangar must
+// exist and have room for the plane (see CheckHangarAllocation), and its plot
+// must carry the weight of the plane in addition to the planes already parked.
+func (p *Planner) ValidateHangar(plane models.Plane) error {
+	if plane.HangarID == "" {
+		return nil
+	}
+
+	hangar, err := p.db.GetHangarByID(plane.HangarID)
+	if err != nil {
+		return err
+	}
+	if hangar == nil {
+		return &ValidationError{Reason: fmt.Sprintf("hangar %s does not exist", plane.HangarID)}
+	}
+
+	parked, err := p.db.GetPlanesByHangar(hangar.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckHangarAllocation(*hangar, parked, plane); err != nil {
+		return err
+	}
+
+	// The plot carries the aircraft of all hangars built on it
+	plot, err := p.plot(hangar.PlotID)
+	if err != nil {
+		return err
+	}
+	load, err := p.db.GetPlotLoad(plot.ID, plane.ID)
+	if err != nil {
+		return err
+	}
+	return CheckPlotLoad(*plot, load+plane.MaxTakeoffWeight)
+}
@@ -0,0 +1,94 @@
+package planning
+
+import (
+	"fmt"
+	"time"
+
+	"mindenairport/models"
+)
+
+// MedicalValidUntil returns the end of the validity of a pilot's medical
+// certificate, or nil if no medical check is on record.
+func (c Config) MedicalValidUntil(pilot models.Pilot) *time.Time {
+	if pilot.MedicalCheckDate == nil {
+		return nil
+	}
+	until := pilot.MedicalCheckDate.AddDate(0, c.MedicalValidityMonths, 0)
+	return &until
+}
+
+// CheckPilotValidity verifies that the license and medical certificate of a
+// pilot remain valid until the given arrival time.
+func (c Config) CheckPilotValidity(pilot models.Pilot, arrival time.Time) error {
+	if pilot.LicenseExpiry != nil && pilot.LicenseExpiry.Before(arrival) {
+		return &ValidationError{Reason: fmt.Sprintf("license of pilot %s expires on %s",
+			pilot.ID, pilot.LicenseExpiry.Format("2006-01-02"))}
+	}
+
+	until := c.MedicalValidUntil(pilot)
+	if until == nil {
+		return &ValidationError{Reason: fmt.Sprintf("pilot %s has no medical check on record", pilot.ID)}
+	}
+	if until.Before(arrival) {
+		return &ValidationError{Reason: fmt.Sprintf("medical certificate of pilot %s expires on %s",
+			pilot.ID, until.Format("2006-01-02"))}
+	}
+
+	return nil
+}
+
+// ValidatePilot checks that the flight has a pilot, that the pilot exists
+// and may fly until the scheduled arrival. The license and medical check is
+// skipped for flights that already departed.
+func (p *Planner) ValidatePilot(flight models.Flight) error {
+	if flight.PilotID == "" {
+		return &ValidationError{Reason: "pilotId is required"}
+	}
+
+	pilot, err := p.db.GetPilotByID(flight.PilotID)
+	if err != nil {
+		return err
+	}
+	if pilot == nil {
+		return &ValidationError{Reason: fmt.Sprintf("pilot %s does not exist", flight.PilotID)}
+	}
+
+	// Flights that already departed may still be updated, e.g. with their arrival time
+	if flight.ActualDeparture != nil {
+		return nil
+	}
+	return p.Config.CheckPilotValidity(*pilot, flight.ScheduledArrival)
+}
+
+// BlockHours returns the actual block time of a completed flight in hours,
+// or 0 if the flight has not arrived yet.
+func BlockHours(flight models.Flight) float64 {
+	if flight.StatusID != models.FlightStatusArrived || flight.ActualDeparture == nil || flight.ActualArrival == nil {
+		return 0
+	}
+	if !flight.ActualArrival.After(*flight.ActualDeparture) {
+		return 0
+	}
+	return flight.ActualArrival.Sub(*flight.ActualDeparture).Hours()
+}
+
+// FlightHoursChanges returns the flight hours to add to (or remove from) each
+// pilot when a flight changes from old to updated. Completing a flight credits
+// its block time to the pilot; corrections of the actual times, a changed
+// pilot or a reverted status adjust the logged hours accordingly.
+func FlightHoursChanges(old, updated models.Flight) map[string]float64 {
+	changes := make(map[string]float64)
+	if hours := BlockHours(old); hours > 0 {
+		changes[old.PilotID] -= hours
+	}
+	if hours := BlockHours(updated); hours > 0 {
+		changes[updated.PilotID] += hours
+	}
+
+	for id, hours := range changes {
+		if hours == 0 || id == "" {
+			delete(changes, id)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,98 @@
+package planning
+
+import (
+	"fmt"
+	"time"
+
+	"mindenairport/models"
+)
+
+// rotationWindow limits how far before and after a flight the previous and
+// next flight of the same plane are searched.
+const rotationWindow = 7 * 24 * time.Hour
+
+// blockWindow returns the time span a flight occupies its plane, using the
+// actual times where they are known.
+func (c Config) blockWindow(f models.Flight) (time.Time, time.Time) {
+	start, end := f.ScheduledDeparture, f.ScheduledArrival
+	if f.ActualDeparture != nil {
+		start = *f.ActualDeparture
+	}
+	if f.ActualArrival != nil {
+		end = *f.ActualArrival
+	} else if f.ActualDeparture != nil {
+		// A late departure shifts the arrival by the same delay
+		end = end.Add(f.ActualDeparture.Sub(f.ScheduledDeparture))
+	}
+	return start, end
+}
+
+// CheckPlaneRotation verifies a flight against the other flights of the same
+// plane: the plane must not fly overlapping flights (including the minimum
+// turnaround time), the previous flight must arrive where this flight departs
+// and the next flight must depart where this flight arrives.
+func (c Config) CheckPlaneRotation(flight models.Flight, others []models.Flight) error {
+	start, end := c.blockWindow(flight)
+
+	var overlapping []string
+	var previous, next *models.Flight
+	for i := range others {
+		other := others[i]
+		if other.ID == flight.ID || other.StatusID == models.FlightStatusCancelled {
+			continue
+		}
+
+		otherStart, otherEnd := c.blockWindow(other)
+		if otherStart.Before(end.Add(c.MinTurnaround)) && start.Before(otherEnd.Add(c.MinTurnaround)) {
+			overlapping = append(overlapping, other.ID)
+			continue
+		}
+
+		if otherEnd.Before(start) && (previous == nil || other.ScheduledDeparture.After(previous.ScheduledDeparture)) {
+			previous = &others[i]
+		}
+		if otherStart.After(end) && (next == nil || other.ScheduledDeparture.Before(next.ScheduledDeparture)) {
+			next = &others[i]
+		}
+	}
+
+	if len(overlapping) > 0 {
+		return &ConflictError{Resource: "plane", ID: flight.PlaneID, Flights: overlapping}
+	}
+	if previous != nil && previous.To != flight.From {
+		return &ValidationError{Reason: fmt.Sprintf(
+			"plane %s arrives at %s with flight %s, but flight %s departs from %s",
+			flight.PlaneID, previous.To, previous.ID, flight.ID, flight.From)}
+	}
+	if next != nil && next.From != flight.To {
+		return &ValidationError{Reason: fmt.Sprintf(
+			"plane %s arrives at %s with flight %s, but its next flight %s departs from %s",
+			flight.PlaneID, flight.To, flight.ID, next.ID, next.From)}
+	}
+
+	return nil
+}
+
+// ValidatePlane checks that the plane of a flight exists, is airworthy when
+// the flight has not departed yet, and fits into the plane's rotation.
+func (p *Planner) ValidatePlane(flight models.Flight) error {
+	plane, err := p.db.GetPlaneByID(flight.PlaneID)
+	if err != nil {
+		return err
+	}
+	if plane == nil {
+		return &ValidationError{Reason: fmt.Sprintf("plane %s does not exist", flight.PlaneID)}
+	}
+
+	// Flights that already departed may still be updated, e.g. with their arrival time
+	if flight.ActualDeparture == nil && (plane.Status == "MAINTENANCE" || plane.Status == "INACTIVE") {
+		return &ValidationError{Reason: fmt.Sprintf("plane %s is not available for flights (%s)", plane.ID, plane.Status)}
+	}
+
+	others, err := p.db.GetFlightsByPlane(plane.ID, flight.ScheduledDeparture.Add(-rotationWindow), flight.ScheduledDeparture.Add(rotationWindow))
+	if err != nil {
+		return err
+	}
+
+	return p.Config.CheckPlaneRotation(flight, others)
+}
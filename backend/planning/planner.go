@@ -0,0 +1,148 @@
+// Package planning validates the assignment of airport and airline resources
+// such as gates, planes, pilots and crew to flights, and of planes to hangars.
+// It detects conflicting assignments before they are stored and proposes
+// conflict-free plans.
+package planning
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+)
+
+// Config holds the planning parameters. Durations are configured in minutes
+// or hours through environment variables (see ConfigFromEnv).
+type Config struct {
+	HomeAirport   string         // IATA code of the airport operating this system
+	Location      *time.Location // Time zone of the airport, used for opening hours and local days
+	GateOccupancy time.Duration  // Time a departing flight occupies its gate before departure
+	GateBuffer    time.Duration  // Minimum gap between two flights on the same gate
+	GateClose     time.Duration  // Time before departure at which the gate closes for boarding
+	MinTurnaround time.Duration  // Minimum ground time of a plane between two flights
+
+	CrewReportTime  time.Duration // Time crew reports for duty before departure
+	CrewReleaseTime time.Duration // Time crew stays on duty after arrival
+	MaxDutyPeriod   time.Duration // Maximum length of a single duty period
+	MinRest         time.Duration // Minimum rest between two duty periods
+	MaxWeeklyDuty   time.Duration // Maximum duty time within any 7 consecutive days
+
+	MedicalValidityMonths int // Months a pilot's medical certificate stays valid after the check
+}
+
+// ConfigFromEnv reads the planning configuration from the environment:
+//
+//	AIRPORT_CODE            (default "MIN")
+//	AIRPORT_TIMEZONE        (default "Europe/Berlin")
+//	GATE_OCCUPANCY_MINUTES  (default 60)
+//	GATE_BUFFER_MINUTES     (default 15)
+//	GATE_CLOSE_MINUTES      (default 20)
+//	MIN_TURNAROUND_MINUTES  (default 30)
+//	CREW_REPORT_MINUTES     (default 60)
+//	CREW_RELEASE_MINUTES    (default 30)
+//	CREW_MAX_DUTY_HOURS     (default 13)
+//	CREW_MIN_REST_HOURS     (default 12)
+//	CREW_MAX_WEEKLY_HOURS   (default 60)
+//	PILOT_MEDICAL_VALIDITY_MONTHS (default 12)
+func ConfigFromEnv() Config {
+	home := os.Getenv("AIRPORT_CODE")
+	if home == "" {
+		home = "MIN"
+	}
+
+	return Config{
+		HomeAirport:   strings.ToUpper(home),
+		Location:      locationFromEnv("AIRPORT_TIMEZONE", "Europe/Berlin"),
+		GateOccupancy: minutesFromEnv("GATE_OCCUPANCY_MINUTES", 60),
+		GateBuffer:    minutesFromEnv("GATE_BUFFER_MINUTES", 15),
+		GateClose:     minutesFromEnv("GATE_CLOSE_MINUTES", 20),
+		MinTurnaround: minutesFromEnv("MIN_TURNAROUND_MINUTES", 30),
+
+		CrewReportTime:  minutesFromEnv("CREW_REPORT_MINUTES", 60),
+		CrewReleaseTime: minutesFromEnv("CREW_RELEASE_MINUTES", 30),
+		MaxDutyPeriod:   hoursFromEnv("CREW_MAX_DUTY_HOURS", 13),
+		MinRest:         hoursFromEnv("CREW_MIN_REST_HOURS", 12),
+		MaxWeeklyDuty:   hoursFromEnv("CREW_MAX_WEEKLY_HOURS", 60),
+
+		MedicalValidityMonths: intFromEnv("PILOT_MEDICAL_VALIDITY_MONTHS", 12),
+	}
+}
+
+// locationFromEnv loads the IANA time zone named by an environment variable,
+// falling back to the default zone and finally to the local time zone.
+func locationFromEnv(name, fallback string) *time.Location {
+	for _, zone := range []string{os.Getenv(name), fallback} {
+		if zone == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(zone); err == nil {
+			return loc
+		}
+		log.Printf("Unknown time zone %q in %s, using default", zone, name)
+	}
+	return time.Local
+}
+
+// minutesFromEnv reads a duration in minutes from an environment variable,
+// falling back to the default if it is unset or invalid.
+func minutesFromEnv(name string, fallback int) time.Duration {
+	return durationFromEnv(name, fallback, time.Minute)
+}
+
+// hoursFromEnv reads a duration in hours from an environment variable,
+// falling back to the default if it is unset or invalid.
+func hoursFromEnv(name string, fallback int) time.Duration {
+	return durationFromEnv(name, fallback, time.Hour)
+}
+
+// durationFromEnv reads a non-negative number of units from an environment variable.
+func durationFromEnv(name string, fallback int, unit time.Duration) time.Duration {
+	return time.Duration(intFromEnv(name, fallback)) * unit
+}
+
+// intFromEnv reads a non-negative integer from an environment variable,
+// falling back to the default if it is unset or invalid.
+func intFromEnv(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// Planner validates flight resource assignments against the stored flights.
+type Planner struct {
+	db     database.Database
+	Config Config
+}
+
+// NewPlanner creates a planner using the configuration from the environment.
+func NewPlanner(db database.Database) *Planner {
+	return &Planner{db: db, Config: ConfigFromEnv()}
+}
+
+// ValidationError reports an assignment that is invalid on its own,
+// e.g. an unknown gate or an aircraft that is too large for a stand.
+type ValidationError struct {
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Reason
+}
+
+// ConflictError reports an assignment that collides with other flights.
+type ConflictError struct {
+	Resource string   // Kind of resource in conflict (e.g. "gate")
+	ID       string   // ID of the resource in conflict
+	Flights  []string // IDs of the conflicting flights
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s is already assigned to overlapping flights: %s",
+		e.Resource, e.ID, strings.Join(e.Flights, ", "))
+}
@@ -0,0 +1,98 @@
+package planning
+
+import (
+	"fmt"
+	"strings"
+
+	"mindenairport/models"
+)
+
+// OccupiedError reports a plot that is already occupied by another facility.
+type OccupiedError struct {
+	PlotID    string
+	Occupants []models.PlotOccupant
+}
+
+func (e *OccupiedError) Error() string {
+	names := make([]string, 0, len(e.Occupants))
+	for _, o := range e.Occupants {
+		names = append(names, o.Kind+" "+o.ID)
+	}
+	return fmt.Sprintf("plot %s is already occupied by %s", e.PlotID, strings.Join(names, ", "))
+}
+
+// CheckPlotLoad verifies that a plot can carry the given weight in pounds.
+// A MaxWeightCapacity of 0 is unlimited.
+func CheckPlotLoad(plot models.PlotOverview, load float64) error {
+	if plot.MaxWeightCapacity > 0 && load > plot.MaxWeightCapacity {
+		return &ValidationError{Reason: fmt.Sprintf(
+			"plot %s carries up to %.0f lbs, but the load would be %.0f lbs",
+			plot.ID, plot.MaxWeightCapacity, load)}
+	}
+	return nil
+}
+
+// CheckPlotPlacement verifies that a facility can be built on a plot: the plot
+// must not be under maintenance, must be of the matching plot type, must not
+// be occupied by another facility and must carry the facility's load.
+func CheckPlotPlacement(plot models.PlotOverview, occupant models.PlotOccupant, load float64) error {
+	if plot.Status == "MAINTENANCE" {
+		return &ValidationError{Reason: fmt.Sprintf("plot %s is under maintenance", plot.ID)}
+	}
+
+	if plot.TypeName != occupant.Kind {
+		return &ValidationError{Reason: fmt.Sprintf("plot %s is a %s plot and cannot hold a %s", plot.ID, plot.TypeLabel, occupant.Kind)}
+	}
+
+	var others []models.PlotOccupant
+	for _, o := range plot.Occupants {
+		if o.Kind != occupant.Kind || o.ID != occupant.ID {
+			others = append(others, o)
+		}
+	}
+	if len(others) > 0 {
+		return &OccupiedError{PlotID: plot.ID, Occupants: others}
+	}
+
+	return CheckPlotLoad(plot, load)
+}
+
+// plot loads a plot with its occupants. It returns a ValidationError if the
+// plot does not exist.
+func (p *Planner) plot(id string) (*models.PlotOverview, error) {
+	plot, err := p.db.GetPlotByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if plot == nil {
+		return nil, &ValidationError{Reason: fmt.Sprintf("plot %s does not exist", id)}
+	}
+
+	occupants, err := p.db.GetPlotOccupants(id)
+	if err != nil {
+		return nil, err
+	}
+	plot.Occupants = append(plot.Occupants, occupants...)
+	return plot, nil
+}
+
+// ValidatePlacement checks that a facility can be placed on the plot given by
+// occupant.PlotID (see CheckPlotPlacement). The load is the weight in pounds
+// the facility puts on the plot, e.g. the aircraft parked in a hangar.
+func (p *Planner) ValidatePlacement(occupant models.PlotOccupant, load float64) error {
+	plot, err := p.plot(occupant.PlotID)
+	if err != nil {
+		return err
+	}
+	return CheckPlotPlacement(*plot, occupant, load)
+}
+
+// HangarLoad returns the weight in pounds the planes parked in a hangar put
+// on its plot, based on their maximum takeoff weight.
+func HangarLoad(parked []models.Plane) float64 {
+	var load float64
+	for _, plane := range parked {
+		load += plane.MaxTakeoffWeight
+	}
+	return load
+}
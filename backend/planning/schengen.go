@@ -0,0 +1,52 @@
+package planning
+
+import (
+	"strings"
+
+	"mindenairport/models"
+)
+
+// schengenCountries contains the members of the Schengen area, both by
+// English name (as stored in AIRPORT.COUNTRY) and by ISO 3166 alpha-2 code.
+var schengenCountries = map[string]bool{
+	"austria": true, "at": true,
+	"belgium": true, "be": true,
+	"bulgaria": true, "bg": true,
+	"croatia": true, "hr": true,
+	"czech republic": true, "czechia": true, "cz": true,
+	"denmark": true, "dk": true,
+	"estonia": true, "ee": true,
+	"finland": true, "fi": true,
+	"france": true, "fr": true,
+	"germany": true, "de": true,
+	"greece": true, "gr": true,
+	"hungary": true, "hu": true,
+	"iceland": true, "is": true,
+	"italy": true, "it": true,
+	"latvia": true, "lv": true,
+	"liechtenstein": true, "li": true,
+	"lithuania": true, "lt": true,
+	"luxembourg": true, "lu": true,
+	"malta": true, "mt": true,
+	"netherlands": true, "nl": true,
+	"norway": true, "no": true,
+	"poland": true, "pl": true,
+	"portugal": true, "pt": true,
+	"romania": true, "ro": true,
+	"slovakia": true, "sk": true,
+	"slovenia": true, "si": true,
+	"spain": true, "es": true,
+	"sweden": true, "se": true,
+	"switzerland": true, "ch": true,
+}
+
+// IsSchengenCountry reports whether a country name or ISO code belongs to the Schengen area.
+func IsSchengenCountry(country string) bool {
+	return schengenCountries[strings.ToLower(strings.TrimSpace(country))]
+}
+
+// IsSchengenFlight reports whether a flight between two airports stays within
+// the Schengen area and therefore needs no passport control.
+func IsSchengenFlight(from, to models.Airport) bool {
+	return IsSchengenCountry(from.Country) && IsSchengenCountry(to.Country)
+}
@@ -0,0 +1,275 @@
+package refdata
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+// AirportFiles names the files of an airport import. Countries and Timezones
+// are optional.
+type AirportFiles struct {
+	Airports  string // OurAirports airports.csv
+	Countries string // OurAirports countries.csv, for country names instead of ISO codes
+	Timezones string // OpenFlights airports.dat, for the airports' time zones
+}
+
+// ReadAirportFiles parses the files of an airport import.
+func ReadAirportFiles(files AirportFiles) (AirportSource, error) {
+	var countries, timezones map[string]string
+	var err error
+
+	if files.Countries != "" {
+		if countries, err = parseFile(files.Countries, ParseOurAirportsCountries); err != nil {
+			return AirportSource{}, err
+		}
+	}
+	if files.Timezones != "" {
+		if timezones, err = parseFile(files.Timezones, ParseOpenFlightsTimezones); err != nil {
+			return AirportSource{}, err
+		}
+	}
+
+	f, err := os.Open(files.Airports)
+	if err != nil {
+		return AirportSource{}, err
+	}
+	defer f.Close()
+
+	source, err := ParseOurAirports(f, countries, timezones)
+	if err != nil {
+		return source, fmt.Errorf("%s: %w", files.Airports, err)
+	}
+	return source, nil
+}
+
+// ReadAirlineFile parses an OpenFlights airlines.dat file.
+func ReadAirlineFile(path string) (AirlineSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return AirlineSource{}, err
+	}
+	defer f.Close()
+
+	source, err := ParseOpenFlightsAirlines(f)
+	if err != nil {
+		return source, fmt.Errorf("%s: %w", path, err)
+	}
+	return source, nil
+}
+
+// parseFile opens a file and parses it into a lookup map.
+func parseFile(path string, parse func(io.Reader) (map[string]string, error)) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Importer upserts parsed reference data into the database. Records are
+// matched by IATA code; fields missing in the files, such as the number of
+// terminals or airline logos, keep their stored values. Importing the same
+// file twice therefore changes nothing the second time.
+type Importer struct {
+	db database.Database
+}
+
+// NewImporter creates an importer writing to db.
+func NewImporter(db database.Database) *Importer {
+	return &Importer{db: db}
+}
+
+// ImportAirports creates new airports and updates changed ones. In a dry run
+// the report is built without writing. Records that fail validation or use
+// the ICAO code of another airport are skipped.
+func (i *Importer) ImportAirports(source AirportSource, dryRun bool) (models.ImportReport, error) {
+	report := models.ImportReport{
+		Kind:    "airports",
+		DryRun:  dryRun,
+		Read:    source.Read,
+		Ignored: source.Ignored,
+		Skipped: source.Skipped,
+	}
+
+	existing := make(map[string]models.Airport)
+	icaoOwner := make(map[string]string)
+	for _, airport := range i.db.GetAirports() {
+		existing[airport.ID] = airport
+		if airport.ICAO != "" {
+			icaoOwner[airport.ICAO] = airport.ID
+		}
+	}
+
+	for n, airport := range source.Airports {
+		old, found := existing[airport.ID]
+		if found {
+			airport.NumberOfTerminal = old.NumberOfTerminal
+			if airport.Timezone == "" {
+				airport.Timezone = old.Timezone
+			}
+			if airport.ICAO == "" {
+				airport.ICAO = old.ICAO
+			}
+		}
+
+		skip := models.ImportSkip{Line: source.Lines[n], ID: airport.ID}
+		if msg := ValidateAirport(&airport); msg != "" {
+			skip.Reason = msg
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+		if owner, ok := icaoOwner[airport.ICAO]; ok && owner != airport.ID {
+			skip.Reason = fmt.Sprintf("ICAO code %s is already used by airport %s", airport.ICAO, owner)
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+
+		var err error
+		changes := airportChanges(old, airport)
+		switch {
+		case !found:
+			report.Created = append(report.Created, models.ImportChange{ID: airport.ID, Name: airport.Name})
+			if !dryRun {
+				err = i.db.CreateAirport(airport)
+			}
+		case len(changes) > 0:
+			report.Updated = append(report.Updated, models.ImportChange{ID: airport.ID, Name: airport.Name, Changes: changes})
+			if !dryRun {
+				err = i.db.UpdateAirport(airport)
+			}
+		default:
+			report.Unchanged++
+		}
+		if err != nil {
+			return report, fmt.Errorf("airport %s: %w", airport.ID, err)
+		}
+
+		delete(icaoOwner, old.ICAO)
+		if airport.ICAO != "" {
+			icaoOwner[airport.ICAO] = airport.ID
+		}
+		existing[airport.ID] = airport
+	}
+
+	return report, nil
+}
+
+// ImportAirlines creates new airlines and updates changed ones. In a dry run
+// the report is built without writing. Records that fail validation or use
+// the ICAO code of another airline are skipped.
+func (i *Importer) ImportAirlines(source AirlineSource, dryRun bool) (models.ImportReport, error) {
+	report := models.ImportReport{
+		Kind:    "airlines",
+		DryRun:  dryRun,
+		Read:    source.Read,
+		Ignored: source.Ignored,
+		Skipped: source.Skipped,
+	}
+
+	existing := make(map[string]models.Airline)
+	icaoOwner := make(map[string]string)
+	for _, airline := range i.db.GetAirlines() {
+		existing[airline.ID] = airline
+		if airline.ICAO != "" {
+			icaoOwner[airline.ICAO] = airline.ID
+		}
+	}
+
+	for n, airline := range source.Airlines {
+		old, found := existing[airline.ID]
+		if found {
+			airline.Logo = old.Logo
+			if airline.ICAO == "" {
+				airline.ICAO = old.ICAO
+			}
+		}
+
+		skip := models.ImportSkip{Line: source.Lines[n], ID: airline.ID}
+		if msg := ValidateAirline(&airline); msg != "" {
+			skip.Reason = msg
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+		if owner, ok := icaoOwner[airline.ICAO]; ok && owner != airline.ID {
+			skip.Reason = fmt.Sprintf("ICAO code %s is already used by airline %s", airline.ICAO, owner)
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+
+		var err error
+		changes := airlineChanges(old, airline)
+		switch {
+		case !found:
+			report.Created = append(report.Created, models.ImportChange{ID: airline.ID, Name: airline.Name})
+			if !dryRun {
+				err = i.db.CreateAirline(airline)
+			}
+		case len(changes) > 0:
+			report.Updated = append(report.Updated, models.ImportChange{ID: airline.ID, Name: airline.Name, Changes: changes})
+			if !dryRun {
+				err = i.db.UpdateAirline(airline)
+			}
+		default:
+			report.Unchanged++
+		}
+		if err != nil {
+			return report, fmt.Errorf("airline %s: %w", airline.ID, err)
+		}
+
+		delete(icaoOwner, old.ICAO)
+		if airline.ICAO != "" {
+			icaoOwner[airline.ICAO] = airline.ID
+		}
+		existing[airline.ID] = airline
+	}
+
+	return report, nil
+}
+
+// airportChanges lists the fields that differ between two versions of an airport.
+func airportChanges(old, updated models.Airport) []models.FieldChange {
+	var changes []models.FieldChange
+	changes = appendChange(changes, "icao", old.ICAO, updated.ICAO)
+	changes = appendChange(changes, "name", old.Name, updated.Name)
+	changes = appendChange(changes, "country", old.Country, updated.Country)
+	changes = appendChange(changes, "city", old.City, updated.City)
+	changes = appendChange(changes, "timezone", old.Timezone, updated.Timezone)
+	changes = appendChange(changes, "elevation", formatFloat(old.Elevation), formatFloat(updated.Elevation))
+	changes = appendChange(changes, "latitude", formatFloat(old.Latitude), formatFloat(updated.Latitude))
+	changes = appendChange(changes, "longitude", formatFloat(old.Longitude), formatFloat(updated.Longitude))
+	return changes
+}
+
+// airlineChanges lists the fields that differ between two versions of an airline.
+func airlineChanges(old, updated models.Airline) []models.FieldChange {
+	var changes []models.FieldChange
+	changes = appendChange(changes, "icao", old.ICAO, updated.ICAO)
+	changes = appendChange(changes, "name", old.Name, updated.Name)
+	changes = appendChange(changes, "country", old.Country, updated.Country)
+	changes = appendChange(changes, "active", strconv.FormatBool(old.Active), strconv.FormatBool(updated.Active))
+	return changes
+}
+
+// appendChange appends a field change if the old and new value differ.
+func appendChange(changes []models.FieldChange, field, old, updated string) []models.FieldChange {
+	if old == updated {
+		return changes
+	}
+	return append(changes, models.FieldChange{Field: field, Old: old, New: updated})
+}
+
+// formatFloat formats a number with as many decimals as needed.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
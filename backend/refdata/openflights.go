@@ -0,0 +1,142 @@
+package refdata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"mindenairport/models"
+)
+
+// OpenFlights files have no header; these are the used columns.
+const (
+	airlineNameCol    = 1
+	airlineIATACol    = 3
+	airlineICAOCol    = 4
+	airlineCountryCol = 6
+	airlineActiveCol  = 7
+
+	airportIATACol     = 4
+	airportTimezoneCol = 11
+)
+
+// AirlineSource holds the airlines parsed from an OpenFlights airlines.dat
+// file. Lines contains the line of each airline in the file.
+type AirlineSource struct {
+	Airlines []models.Airline
+	Lines    []int
+	Read     int
+	Ignored  int
+	Skipped  []models.ImportSkip
+}
+
+// ParseOpenFlightsAirlines reads an OpenFlights airlines.dat file. Airlines
+// without IATA code are ignored. OpenFlights lists defunct airlines next to
+// the current holder of a code, so for duplicate codes an active airline is
+// preferred over inactive ones and otherwise the first one is kept.
+func ParseOpenFlightsAirlines(r io.Reader) (AirlineSource, error) {
+	var source AirlineSource
+
+	reader := openFlightsReader(r)
+	index := make(map[string]int) // Position of each IATA code in source.Airlines
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return source, err
+		}
+		line, _ := reader.FieldPos(0)
+		source.Read++
+
+		if len(record) <= airlineActiveCol {
+			source.Skipped = append(source.Skipped, models.ImportSkip{Line: line, Reason: "too few columns"})
+			continue
+		}
+
+		iata := strings.ToUpper(openFlightsValue(record[airlineIATACol]))
+		if iata == "" || iata == "-" {
+			source.Ignored++
+			continue
+		}
+
+		airline := models.Airline{
+			ID:      iata,
+			ICAO:    strings.ToUpper(openFlightsValue(record[airlineICAOCol])),
+			Name:    openFlightsValue(record[airlineNameCol]),
+			Country: openFlightsValue(record[airlineCountryCol]),
+			Active:  openFlightsValue(record[airlineActiveCol]) == "Y",
+		}
+		// Some airlines have placeholders like "N/A" instead of an ICAO code
+		if !airlineICAOPattern.MatchString(airline.ICAO) {
+			airline.ICAO = ""
+		}
+
+		i, seen := index[iata]
+		switch {
+		case !seen:
+			index[iata] = len(source.Airlines)
+			source.Airlines = append(source.Airlines, airline)
+			source.Lines = append(source.Lines, line)
+		case airline.Active && !source.Airlines[i].Active:
+			source.Skipped = append(source.Skipped, models.ImportSkip{
+				Line: source.Lines[i], ID: iata, Reason: "duplicate IATA code of inactive airline"})
+			source.Airlines[i] = airline
+			source.Lines[i] = line
+		default:
+			source.Skipped = append(source.Skipped, models.ImportSkip{Line: line, ID: iata, Reason: "duplicate IATA code"})
+		}
+	}
+
+	return source, nil
+}
+
+// ParseOpenFlightsTimezones reads an OpenFlights airports.dat file and returns
+// the IANA time zone of each airport by IATA code. It complements OurAirports
+// files, which have no time zones.
+func ParseOpenFlightsTimezones(r io.Reader) (map[string]string, error) {
+	timezones := make(map[string]string)
+
+	reader := openFlightsReader(r)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return timezones, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) <= airportTimezoneCol {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: too few columns", line)
+		}
+
+		iata := openFlightsValue(record[airportIATACol])
+		zone := openFlightsValue(record[airportTimezoneCol])
+		if iata != "" && zone != "" {
+			timezones[strings.ToUpper(iata)] = zone
+		}
+	}
+}
+
+// openFlightsReader returns a CSV reader for the OpenFlights files, which
+// contain unescaped quotes in some names.
+func openFlightsReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader
+}
+
+// openFlightsValue returns a trimmed field value, mapping the OpenFlights
+// NULL marker \N to "".
+func openFlightsValue(field string) string {
+	field = strings.TrimSpace(field)
+	if field == `\N` {
+		return ""
+	}
+	return field
+}
@@ -0,0 +1,171 @@
+package refdata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"mindenairport/models"
+)
+
+// AirportSource holds the airports parsed from an OurAirports airports.csv
+// file. Lines contains the line of each airport in the file.
+type AirportSource struct {
+	Airports []models.Airport
+	Lines    []int
+	Read     int
+	Ignored  int
+	Skipped  []models.ImportSkip
+}
+
+// ParseOurAirports reads an OurAirports airports.csv file. Only airports with
+// an IATA code are kept; closed airports, heliports and airfields without a
+// code are ignored. The file does not contain time zones and names countries
+// by their ISO code, so both are looked up in the optional maps countries
+// (ISO code to name, see ParseOurAirportsCountries) and timezones (IATA code
+// to IANA name, see ParseOpenFlightsTimezones).
+func ParseOurAirports(r io.Reader, countries, timezones map[string]string) (AirportSource, error) {
+	var source AirportSource
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return source, fmt.Errorf("reading header: %w", err)
+	}
+	col, err := columns(header, "type", "name", "latitude_deg", "longitude_deg", "elevation_ft",
+		"iso_country", "municipality", "gps_code", "iata_code")
+	if err != nil {
+		return source, err
+	}
+	// Newer files have a dedicated ICAO column, older ones only the GPS code
+	icaoCol, hasICAO := indexOf(header, "icao_code")
+
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return source, err
+		}
+		line, _ := reader.FieldPos(0)
+		source.Read++
+
+		iata := strings.ToUpper(strings.TrimSpace(record[col["iata_code"]]))
+		if iata == "" || record[col["type"]] == "closed" {
+			source.Ignored++
+			continue
+		}
+		if seen[iata] {
+			source.Skipped = append(source.Skipped, models.ImportSkip{Line: line, ID: iata, Reason: "duplicate IATA code"})
+			continue
+		}
+
+		airport := models.Airport{
+			ID:       iata,
+			ICAO:     airportICAO(record[col["gps_code"]]),
+			Name:     record[col["name"]],
+			Country:  record[col["iso_country"]],
+			City:     record[col["municipality"]],
+			Timezone: timezones[iata],
+		}
+		if hasICAO && airportICAO(record[icaoCol]) != "" {
+			airport.ICAO = airportICAO(record[icaoCol])
+		}
+		if name, ok := countries[airport.Country]; ok {
+			airport.Country = name
+		}
+
+		airport.Latitude, err = parseCoordinate(record[col["latitude_deg"]])
+		if err == nil {
+			airport.Longitude, err = parseCoordinate(record[col["longitude_deg"]])
+		}
+		if err == nil && record[col["elevation_ft"]] != "" {
+			airport.Elevation, err = strconv.ParseFloat(record[col["elevation_ft"]], 64)
+		}
+		if err != nil {
+			source.Skipped = append(source.Skipped, models.ImportSkip{Line: line, ID: iata, Reason: "invalid number: " + err.Error()})
+			continue
+		}
+
+		seen[iata] = true
+		source.Airports = append(source.Airports, airport)
+		source.Lines = append(source.Lines, line)
+	}
+
+	return source, nil
+}
+
+// ParseOurAirportsCountries reads an OurAirports countries.csv file and
+// returns the country names by ISO code.
+func ParseOurAirportsCountries(r io.Reader) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	col, err := columns(header, "code", "name")
+	if err != nil {
+		return nil, err
+	}
+
+	countries := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return countries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		countries[record[col["code"]]] = record[col["name"]]
+	}
+}
+
+// airportICAO returns value if it is an ICAO airport code, or "" otherwise.
+// GPS codes of small airfields are often local identifiers like "US-0001".
+func airportICAO(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if !airportICAOPattern.MatchString(value) {
+		return ""
+	}
+	return value
+}
+
+// parseCoordinate parses a latitude or longitude and rounds it to the six
+// decimal places stored by the AIRPORT table, so that unchanged airports
+// compare equal on the next import.
+func parseCoordinate(value string) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strconv.FormatFloat(v, 'f', 6, 64), 64)
+}
+
+// columns returns the index of each of the given columns in a CSV header.
+func columns(header []string, names ...string) (map[string]int, error) {
+	col := make(map[string]int, len(names))
+	for _, name := range names {
+		i, ok := indexOf(header, name)
+		if !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+		col[name] = i
+	}
+	return col, nil
+}
+
+// indexOf returns the index of a column in a CSV header.
+func indexOf(header []string, name string) (int, bool) {
+	for i, h := range header {
+		if strings.TrimSpace(h) == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,73 @@
+// Package refdata validates airport and airline reference data and imports
+// it in bulk from the public OurAirports and OpenFlights data files.
+package refdata
+
+import (
+	"regexp"
+	"strings"
+
+	"mindenairport/models"
+	"mindenairport/timezone"
+)
+
+var (
+	// airportIATAPattern matches 3-letter IATA airport codes such as "FRA".
+	airportIATAPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	// airportICAOPattern matches 4-letter ICAO airport codes such as "EDDF".
+	airportICAOPattern = regexp.MustCompile(`^[A-Z]{4}$`)
+	// airlineIATAPattern matches 2-character IATA airline codes such as "LH"
+	// or "U2". Codes of two digits are not assigned.
+	airlineIATAPattern = regexp.MustCompile(`^([A-Z][A-Z0-9]|[0-9][A-Z])$`)
+	// airlineICAOPattern matches 3-letter ICAO airline codes such as "DLH".
+	airlineICAOPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// ValidateAirport normalizes the airport attributes and checks the codes,
+// the time zone and the coordinates. It returns an error message or "".
+func ValidateAirport(airport *models.Airport) string {
+	airport.ID = strings.ToUpper(strings.TrimSpace(airport.ID))
+	airport.ICAO = strings.ToUpper(strings.TrimSpace(airport.ICAO))
+	airport.Name = strings.TrimSpace(airport.Name)
+	airport.Country = strings.TrimSpace(airport.Country)
+	airport.City = strings.TrimSpace(airport.City)
+	airport.Timezone = strings.TrimSpace(airport.Timezone)
+
+	switch {
+	case !airportIATAPattern.MatchString(airport.ID):
+		return "ID must be a 3-letter IATA airport code"
+	case airport.ICAO != "" && !airportICAOPattern.MatchString(airport.ICAO):
+		return "ICAO must be a 4-letter ICAO airport code"
+	case airport.Country == "" || airport.City == "":
+		return "Country and city are required"
+	case airport.Latitude < -90 || airport.Latitude > 90:
+		return "Latitude must be between -90 and 90"
+	case airport.Longitude < -180 || airport.Longitude > 180:
+		return "Longitude must be between -180 and 180"
+	case airport.NumberOfTerminal < 0:
+		return "Number of terminals must not be negative"
+	}
+	if _, err := timezone.Load(airport.Timezone); err != nil {
+		return "Timezone must be an IANA time zone name such as 'Europe/Berlin'"
+	}
+	return ""
+}
+
+// ValidateAirline normalizes the airline attributes and checks the codes.
+// It returns an error message or "".
+func ValidateAirline(airline *models.Airline) string {
+	airline.ID = strings.ToUpper(strings.TrimSpace(airline.ID))
+	airline.ICAO = strings.ToUpper(strings.TrimSpace(airline.ICAO))
+	airline.Name = strings.TrimSpace(airline.Name)
+	airline.Country = strings.TrimSpace(airline.Country)
+	airline.Logo = strings.TrimSpace(airline.Logo)
+
+	switch {
+	case !airlineIATAPattern.MatchString(airline.ID):
+		return "ID must be a 2-character IATA airline code"
+	case airline.ICAO != "" && !airlineICAOPattern.MatchString(airline.ICAO):
+		return "ICAO must be a 3-letter ICAO airline code"
+	case airline.Name == "":
+		return "Name is required"
+	}
+	return ""
+}
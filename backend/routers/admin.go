@@ -1,11 +1,16 @@
 package routers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 
+	"mindenairport/apis"
 	"mindenairport/database"
+	"mindenairport/manifest"
 	"mindenairport/models"
+	"mindenairport/notifications"
+	"mindenairport/planning"
 
 	"github.com/gin-gonic/gin"
 )
@@ -60,7 +65,9 @@ func GetAdminDashboard(db database.Database) gin.HandlerFunc {
 
 		users := db.GetUserCount()
 
-		revenue, _ := db.CalculateRevenue()
+		ticketRevenue, _ := db.CalculateRevenue()
+		concessionRevenue, _ := db.CalculateConcessionRevenue()
+		baggageFeeRevenue, _ := db.CalculateBaggageFeeRevenue()
 
 		// Calculate statistics
 		totalFlights := len(flights)
@@ -84,7 +91,12 @@ func GetAdminDashboard(db database.Database) gin.HandlerFunc {
 				"totalAirlines":   totalAirlines,
 				"activeAirlines":  activeAirlines,
 				"totalPassengers": totalPassengers,
-				"revenue":         revenue,
+				"revenue":         float64(ticketRevenue) + concessionRevenue + baggageFeeRevenue,
+				"revenueBySource": gin.H{
+					"tickets":     ticketRevenue,
+					"concessions": concessionRevenue,
+					"baggageFees": baggageFeeRevenue,
+				},
 			},
 			"airlines": airlines,
 			"airports": airports,
@@ -388,8 +400,70 @@ func GetFlightManagement(db database.Database) gin.HandlerFunc {
 	}
 }
 
-// UpdateFlight allows admin to update flight information
-func UpdateFlight(db database.Database) gin.HandlerFunc {
+// CreateFlight allows admin to schedule a new flight.
+// The flight is validated against the plane's availability and rotation, the
+// pilot's license and medical validity and the gate assignment before it is stored.
+// Planes of deactivated airlines cannot get new flights.
+//
+// Returns:
+//   - 201: Flight created
+//   - 400: Invalid request data or resource assignment
+//   - 409: Assigned resource is used by an overlapping flight
+//   - 500: Internal server error
+func CreateFlight(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Check admin role
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var flight models.Flight
+		if err := c.ShouldBindJSON(&flight); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if flight.From == "" || flight.To == "" || flight.PilotID == "" || flight.PlaneID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "From, to, pilot and plane are required"})
+			return
+		}
+
+		// New flights start as scheduled unless a status is given
+		if flight.StatusID == 0 {
+			flight.StatusID = models.FlightStatusScheduled
+		}
+
+		if err := planner.ValidateFlight(flight); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+		if err := planner.ValidateFlightAirline(flight); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.CreateFlight(&flight); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create flight"})
+			return
+		}
+
+		if err := planner.ApplyFlightHours(models.Flight{}, flight); err != nil {
+			log.Printf("Failed to update pilot flight hours for flight %s: %v", flight.ID, err)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    flight,
+			"message": "Flight created successfully",
+		})
+	}
+}
+
+// UpdateFlight allows admin to update flight information.
+// The plane, pilot and gate assignments are validated before the update is stored,
+// and the block time of completed flights is credited to the pilot. Passengers holding tickets for the flight are
+// notified about gate changes, delays and boarding calls caused by the update.
+func UpdateFlight(db database.Database, planner *planning.Planner, notifier *notifications.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check admin role
 		_, authorized := checkAdminRole(c, db)
@@ -408,8 +482,37 @@ func UpdateFlight(db database.Database) gin.HandlerFunc {
 		// Set the ID from the URL parameter
 		updateData.ID = flightID
 
+		// Keep the current state to detect passenger-relevant changes
+		existing, err := db.GetFlightByID(flightID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flight"})
+			return
+		}
+
+		if existing.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		// Reject pilots and gates that may not be used for the flight, and
+		// new times that break the duty limits of its crew
+		if err := planner.ValidateFlight(updateData); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
 		// Update flight in database
-		db.UpdateFlight(updateData)
+		if err := db.UpdateFlight(updateData); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update flight"})
+			return
+		}
+
+		if err := planner.ApplyFlightHours(existing, updateData); err != nil {
+			log.Printf("Failed to update pilot flight hours for flight %s: %v", flightID, err)
+		}
+
+		// Inform affected passengers without delaying the response
+		go notifier.NotifyFlightChange(existing, updateData)
 
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Flight updated successfully",
@@ -422,11 +525,14 @@ func AdminRoutes(router *gin.RouterGroup, db database.Database) {
 	// Admin dashboard
 	router.GET("/dashboard", GetAdminDashboard(db))
 
+	passengers := apis.NewService(db)
+
 	// User management
 	router.GET("/users", GetAllUsers(db))
 	router.GET("/users/:id", GetUserById(db))
 	router.PUT("/users/:id", UpdateUser(db))
 	router.DELETE("/users/:id", DeactivateUser(db))
+	router.GET("/users/:id/documents", GetUserTravelDocuments(db, passengers))
 
 	// Ticket management
 	router.GET("/tickets", GetAllTickets(db))
@@ -435,6 +541,50 @@ func AdminRoutes(router *gin.RouterGroup, db database.Database) {
 	router.GET("/baggage", GetAllBaggage(db))
 
 	// Flight management
+	notifier := notifications.NewService(db)
+	planner := planning.NewPlanner(db)
 	router.GET("/flights", GetFlightManagement(db))
-	router.PATCH("/flights/:id", UpdateFlight(db))
+	router.POST("/flights", CreateFlight(db, planner))
+	router.PATCH("/flights/:id", UpdateFlight(db, planner, notifier))
+	router.GET("/flights/:id/crew", GetFlightCrew(db))
+	router.POST("/flights/:id/crew", AssignFlightCrew(db, planner))
+	router.DELETE("/flights/:id/crew/:assignmentId", RemoveFlightCrew(db))
+
+	// Passenger lists for ground handling
+	messenger := manifest.NewMessenger(db)
+	router.GET("/flights/:id/manifest", GetFlightManifest(db))
+	router.GET("/flights/:id/messages", GetPassengerListMessages(db, messenger))
+	router.POST("/flights/:id/pnl", SendPNL(db, messenger))
+	router.POST("/flights/:id/adl", SendADL(db, messenger))
+	router.GET("/flights/:id/paxlst", GetFlightPaxlst(db, passengers))
+
+	// Airport and airline reference data
+	AirportAdminRoutes(router.Group("/airports"), db)
+	AirlineAdminRoutes(router.Group("/airlines"), db)
+	ImportRoutes(router.Group("/import"), db, planner)
+
+	// Fleet management
+	PlaneRoutes(router.Group("/planes"), db, planner)
+
+	// Pilot registry
+	PilotRoutes(router.Group("/pilots"), db)
+
+	// Crew management and rostering
+	CrewRoutes(router.Group("/crew"), db, planner)
+
+	// Terminal management
+	TerminalAdminRoutes(router.Group("/terminals"), db, planner)
+
+	// Gate management and planning
+	GateRoutes(router.Group("/gates"), db, planner)
+
+	// Plot and space management
+	PlotRoutes(router.Group("/plots"), db)
+	ParkingRoutes(router.Group("/parking"), db, planner)
+
+	// Shop management
+	ShopAdminRoutes(router.Group("/shops"), db, planner)
+
+	// Retail leases and concession revenue
+	LeaseRoutes(router.Group("/leases"), db)
 }
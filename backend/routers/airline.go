@@ -2,8 +2,11 @@ package routers
 
 import (
 	"net/http"
+	"strings"
 
 	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/refdata"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,16 +32,205 @@ func GetAirlines(db database.Database) gin.HandlerFunc {
 // @Produce json
 // @Param id path string true "Airline ID"
 // @Success 200 {object} models.Airline
+// @Failure 404 {object} map[string]string
 // @Router /airline/{id} [get]
 func GetAirlineByID(db database.Database) gin.HandlerFunc {
 	fn := func(c *gin.Context) {
-		id := c.Param("id")
-		c.IndentedJSON(http.StatusOK, db.GetAirlineByID(id))
+		id := strings.ToUpper(c.Param("id"))
+		airline := db.GetAirlineByID(id)
+		if airline.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airline not found"})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, airline)
 	}
 
 	return gin.HandlerFunc(fn)
 }
 
+// airlineICAOTaken reports whether another airline already uses the ICAO code of airline.
+func airlineICAOTaken(db database.Database, airline models.Airline) bool {
+	if airline.ICAO == "" {
+		return false
+	}
+	for _, other := range db.GetAirlines() {
+		if other.ICAO == airline.ICAO && other.ID != airline.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAirline adds a new airline. The ID is the airline's IATA code.
+//
+// Request body:
+//   - id: 2-character IATA code
+//   - icao: Optional 3-letter ICAO code
+//   - name: Airline name
+//   - country, logo: Optional details
+//   - active: Whether the airline operates (defaults to true)
+func CreateAirline(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		// Airlines are active unless the request says otherwise
+		airline := models.Airline{Active: true}
+		if err := c.ShouldBindJSON(&airline); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := refdata.ValidateAirline(&airline); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		if existing := db.GetAirlineByID(airline.ID); existing.ID != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Airline already exists"})
+			return
+		}
+		if airlineICAOTaken(db, airline) {
+			c.JSON(http.StatusConflict, gin.H{"error": "ICAO code is already used by another airline"})
+			return
+		}
+
+		if err := db.CreateAirline(airline); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create airline"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    airline,
+			"message": "Airline created successfully",
+		})
+	}
+}
+
+// UpdateAirline replaces the attributes of an existing airline. The IATA
+// code in the URL cannot be changed. Setting active to false deactivates an
+// airline that can no longer be deleted.
+func UpdateAirline(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var airline models.Airline
+		if err := c.ShouldBindJSON(&airline); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		airline.ID = c.Param("id")
+
+		if msg := refdata.ValidateAirline(&airline); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		if existing := db.GetAirlineByID(airline.ID); existing.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airline not found"})
+			return
+		}
+		if airlineICAOTaken(db, airline) {
+			c.JSON(http.StatusConflict, gin.H{"error": "ICAO code is already used by another airline"})
+			return
+		}
+
+		if err := db.UpdateAirline(airline); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update airline"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    airline,
+			"message": "Airline updated successfully",
+		})
+	}
+}
+
+// DeactivateAirline marks an airline as no longer operating while keeping
+// its planes and flights.
+func DeactivateAirline(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		airline := db.GetAirlineByID(strings.ToUpper(c.Param("id")))
+		if airline.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airline not found"})
+			return
+		}
+
+		airline.Active = false
+		if err := db.UpdateAirline(airline); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deactivate airline"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    airline,
+			"message": "Airline deactivated successfully",
+		})
+	}
+}
+
+// DeleteAirline removes an airline. Airlines that still own planes cannot be
+// deleted; the response points to deactivation instead.
+func DeleteAirline(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		airline := db.GetAirlineByID(strings.ToUpper(c.Param("id")))
+		if airline.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airline not found"})
+			return
+		}
+
+		planes, flights, err := db.CountAirlineUsage(airline.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check airline usage"})
+			return
+		}
+		if planes > 0 || flights > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "Airline is still used by planes or flights",
+				"planes":     planes,
+				"flights":    flights,
+				"deactivate": "POST /api/admin/airlines/" + airline.ID + "/deactivate",
+			})
+			return
+		}
+
+		if err := db.DeleteAirline(airline.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete airline"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Airline deleted successfully",
+		})
+	}
+}
+
+// AirlineAdminRoutes sets up the airline management routes
+func AirlineAdminRoutes(router *gin.RouterGroup, db database.Database) {
+	router.POST("", CreateAirline(db))
+	router.PUT("/:id", UpdateAirline(db))
+	router.DELETE("/:id", DeleteAirline(db))
+	router.POST("/:id/deactivate", DeactivateAirline(db))
+}
+
 func AirlineRoutes(router *gin.RouterGroup, db database.Database) {
 	router.GET("/", GetAirlines(db))
 	router.GET("/:id", GetAirlineByID(db))
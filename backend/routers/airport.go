@@ -1,19 +1,25 @@
 package routers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
+	"mindenairport/aircraft"
 	"mindenairport/database"
+	"mindenairport/geo"
+	"mindenairport/models"
+	"mindenairport/refdata"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetAirlines godoc
-// @Summary Get all airlines
-// @Description Get all airlines
+// GetAirports godoc
+// @Summary Get all airports
+// @Description Get all airports
 // @Produce json
-// @Success 200 {array} models.Airline
-// @Router /airline [get]
+// @Success 200 {array} models.Airport
+// @Router /airport [get]
 func GetAirports(db database.Database) gin.HandlerFunc {
 	fn := func(c *gin.Context) {
 		// Your handler code goes in here - e.g.
@@ -23,23 +29,218 @@ func GetAirports(db database.Database) gin.HandlerFunc {
 	return gin.HandlerFunc(fn)
 }
 
-// GetAirlineByID godoc
-// @Summary Get airline by ID
-// @Description Get airline by ID
+// GetAirportByID godoc
+// @Summary Get airport by ID
+// @Description Get airport by IATA code
 // @Produce json
-// @Param id path string true "Airline ID"
-// @Success 200 {object} models.Airline
-// @Router /airline/{id} [get]
+// @Param id path string true "Airport ID"
+// @Success 200 {object} models.Airport
+// @Failure 404 {object} map[string]string
+// @Router /airport/{id} [get]
 func GetAirportByID(db database.Database) gin.HandlerFunc {
 	fn := func(c *gin.Context) {
-		id := c.Param("id")
-		c.IndentedJSON(http.StatusOK, db.GetAirportByID(id))
+		id := strings.ToUpper(c.Param("id"))
+		airport := db.GetAirportByID(id)
+		if airport.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airport not found"})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, airport)
 	}
 
 	return gin.HandlerFunc(fn)
 }
 
+// GetAirportDistance returns the great-circle distance between two airports
+// together with the estimated block time and CO2 emissions per passenger.
+//
+// URL Parameters:
+//   - id: Origin airport code
+//   - to: Destination airport code
+//
+// Query parameters:
+//   - aircraft: Aircraft model or ICAO/IATA type code the estimate is based on
+//     (defaults to an Airbus A320)
+func GetAirportDistance(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t := geo.DefaultAircraft()
+		if model := c.Query("aircraft"); model != "" {
+			var ok bool
+			if t, ok = aircraft.Lookup(model); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown aircraft type"})
+				return
+			}
+		}
+
+		estimate, err := geo.NewEstimator(db).Route(c.Param("id"), c.Param("to"), t, 0)
+		switch {
+		case errors.Is(err, geo.ErrUnknownAirport):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, geo.ErrNoCoordinates):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate distance"})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, estimate)
+	}
+}
+
+// airportICAOTaken reports whether another airport already uses the ICAO code of airport.
+func airportICAOTaken(db database.Database, airport models.Airport) bool {
+	if airport.ICAO == "" {
+		return false
+	}
+	for _, other := range db.GetAirports() {
+		if other.ICAO == airport.ICAO && other.ID != airport.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAirport adds a new airport. The ID is the airport's IATA code.
+//
+// Request body:
+//   - id: 3-letter IATA code
+//   - icao: Optional 4-letter ICAO code
+//   - country, city: Location of the airport
+//   - timezone: IANA time zone name (e.g. "Europe/Berlin")
+//   - name, elevation, numberOfTerminals, latitude, longitude: Optional details
+func CreateAirport(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var airport models.Airport
+		if err := c.ShouldBindJSON(&airport); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := refdata.ValidateAirport(&airport); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		if existing := db.GetAirportByID(airport.ID); existing.ID != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Airport already exists"})
+			return
+		}
+		if airportICAOTaken(db, airport) {
+			c.JSON(http.StatusConflict, gin.H{"error": "ICAO code is already used by another airport"})
+			return
+		}
+
+		if err := db.CreateAirport(airport); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create airport"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    airport,
+			"message": "Airport created successfully",
+		})
+	}
+}
+
+// UpdateAirport replaces the attributes of an existing airport. The IATA
+// code in the URL cannot be changed.
+func UpdateAirport(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var airport models.Airport
+		if err := c.ShouldBindJSON(&airport); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		airport.ID = c.Param("id")
+
+		if msg := refdata.ValidateAirport(&airport); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		if existing := db.GetAirportByID(airport.ID); existing.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airport not found"})
+			return
+		}
+		if airportICAOTaken(db, airport) {
+			c.JSON(http.StatusConflict, gin.H{"error": "ICAO code is already used by another airport"})
+			return
+		}
+
+		if err := db.UpdateAirport(airport); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update airport"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    airport,
+			"message": "Airport updated successfully",
+		})
+	}
+}
+
+// DeleteAirport removes an airport. Airports still used as origin or
+// destination of a flight cannot be deleted.
+func DeleteAirport(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		airport := db.GetAirportByID(strings.ToUpper(c.Param("id")))
+		if airport.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airport not found"})
+			return
+		}
+
+		flights, err := db.CountAirportFlights(airport.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check airport usage"})
+			return
+		}
+		if flights > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Airport is still used by flights",
+				"flights": flights,
+			})
+			return
+		}
+
+		if err := db.DeleteAirport(airport.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete airport"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Airport deleted successfully",
+		})
+	}
+}
+
+// AirportAdminRoutes sets up the airport management routes
+func AirportAdminRoutes(router *gin.RouterGroup, db database.Database) {
+	router.POST("", CreateAirport(db))
+	router.PUT("/:id", UpdateAirport(db))
+	router.DELETE("/:id", DeleteAirport(db))
+}
+
 func AirportRoutes(router *gin.RouterGroup, db database.Database) {
 	router.GET("/", GetAirports(db))
 	router.GET("/:id", GetAirportByID(db))
+	router.GET("/:id/distance/:to", GetAirportDistance(db))
 }
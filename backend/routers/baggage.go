@@ -33,7 +33,8 @@ func GetBaggageByID(db database.Database) gin.HandlerFunc {
 	}
 }
 
-// GetMyBaggage retrieves all baggage for the authenticated user
+// GetMyBaggage retrieves all baggage for the authenticated user and their
+// companions; ?traveller=self or a companion ID narrows the list
 func GetMyBaggage(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get user ID from context (set by auth middleware)
@@ -43,8 +44,13 @@ func GetMyBaggage(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		filter, ok := parseTravellerFilter(c, db, userID.(string))
+		if !ok {
+			return
+		}
+
 		// Get baggage for the user
-		baggageList, err := db.GetBaggageByUserID(userID.(string))
+		allBaggage, err := db.GetBaggageByUserID(userID.(string))
 		if err != nil {
 			fmt.Println("Error retrieving baggage:", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve baggage"})
@@ -52,8 +58,11 @@ func GetMyBaggage(db database.Database) gin.HandlerFunc {
 		}
 
 		// Return empty array if no baggage found
-		if baggageList == nil {
-			baggageList = []models.Baggage{}
+		baggageList := []models.Baggage{}
+		for _, baggage := range allBaggage {
+			if filter.matches(baggage.CompanionID) {
+				baggageList = append(baggageList, baggage)
+			}
 		}
 
 		c.JSON(http.StatusOK, gin.H{
@@ -146,6 +155,11 @@ func CreateBaggage(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		// Baggage of a companion must name one of the user's companions
+		if baggage.CompanionID != "" && ownCompanion(c, db, userID.(string), baggage.CompanionID) == nil {
+			return
+		}
+
 		// Create the baggage
 		createdBaggage, err := db.CreateBaggage(baggage)
 		if err != nil {
@@ -201,6 +215,11 @@ func UpdateBaggage(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		// Baggage of a companion must name one of the user's companions
+		if baggage.CompanionID != "" && ownCompanion(c, db, userID.(string), baggage.CompanionID) == nil {
+			return
+		}
+
 		// Update the baggage
 		updatedBaggage, err := db.UpdateBaggage(id, baggage)
 		if err != nil {
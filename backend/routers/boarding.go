@@ -0,0 +1,190 @@
+// Package routers provides HTTP route handlers for volunteers and denied
+// boarding on oversold flights in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"mindenairport/boarding"
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VolunteerTicket records that the passenger of a ticket is willing to give
+// up their seat on an oversold flight. The body may name the compensation
+// the passenger asks for.
+//
+// Returns:
+//   - 201: Volunteer recorded
+//   - 404: Ticket not found
+//   - 409: Flight not oversold or no longer boarding, ticket holds no seat or already volunteered
+func VolunteerTicket(db database.Database, gate *boarding.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req models.VolunteerRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+				return
+			}
+		}
+		if req.Compensation != nil && *req.Compensation < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Compensation must not be negative"})
+			return
+		}
+
+		ticket, err := db.GetTicketByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket"})
+			return
+		}
+		if ticket.ID == "" || !ticket.HeldBy(userID.(string)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+			return
+		}
+
+		record, err := gate.Volunteer(ticket, req.Compensation, time.Now())
+		switch {
+		case errors.Is(err, boarding.ErrBoardingClosed), errors.Is(err, boarding.ErrNotOversold),
+			errors.Is(err, boarding.ErrNoSeat), errors.Is(err, boarding.ErrAlreadyVolunteered):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to volunteer"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    record,
+			"message": "Volunteered successfully",
+		})
+	}
+}
+
+// WithdrawVolunteer takes back the volunteer offer of a ticket as long as
+// staff have not denied it boarding.
+func WithdrawVolunteer(db database.Database, gate *boarding.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		ticket, err := db.GetTicketByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket"})
+			return
+		}
+		if ticket.ID == "" || !ticket.HeldBy(userID.(string)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+			return
+		}
+
+		err = gate.Withdraw(ticket)
+		switch {
+		case errors.Is(err, boarding.ErrNotVolunteer):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw volunteer"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Volunteer withdrawn successfully"})
+	}
+}
+
+// GetBoardingStatus returns the seats, booked and checked-in passengers of
+// a flight with its volunteers and denied boardings.
+func GetBoardingStatus(db database.Database, gate *boarding.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		status, err := gate.Status(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve boarding status"})
+			return
+		}
+		if status == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    status,
+			"message": "Boarding status retrieved successfully",
+		})
+	}
+}
+
+// DenyBoarding denies the passenger of a ticket boarding an oversold flight
+// and records the compensation paid and the staff member deciding it.
+//
+// Returns:
+//   - 201: Denied boarding recorded
+//   - 400: Negative compensation, or none given for a flight of unknown distance
+//   - 404: Ticket not on the flight
+//   - 409: Flight not oversold or no longer boarding, or ticket holds no seat
+func DenyBoarding(db database.Database, gate *boarding.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		staff, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var req models.DeniedBoardingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if req.Compensation != nil && *req.Compensation < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Compensation must not be negative"})
+			return
+		}
+
+		record, err := gate.Deny(c.Param("id"), req, staff.ID, time.Now())
+		switch {
+		case errors.Is(err, boarding.ErrUnknownTicket):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, boarding.ErrCompensationRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, boarding.ErrBoardingClosed), errors.Is(err, boarding.ErrNotOversold), errors.Is(err, boarding.ErrNoSeat):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deny boarding"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    record,
+			"message": "Boarding denied successfully",
+		})
+	}
+}
+
+// BoardingRoutes registers the volunteers and denied boardings of oversold
+// flights for admins.
+func BoardingRoutes(router *gin.RouterGroup, db database.Database) {
+	gate := boarding.NewService(db)
+
+	router.GET("/flights/:id/boarding", GetBoardingStatus(db, gate))
+	router.POST("/flights/:id/denied-boarding", DenyBoarding(db, gate))
+}
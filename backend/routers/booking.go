@@ -0,0 +1,317 @@
+// Package routers provides HTTP route handlers for booking records (PNR)
+// in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"mindenairport/booking"
+	"mindenairport/database"
+	"mindenairport/middleware"
+	"mindenairport/models"
+	"mindenairport/payments"
+	"mindenairport/timezone"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateBooking books one or more passengers on one or more flights for the
+// authenticated user. Passengers need no account; one of them may be the
+// user ("self": true) and others companions of the user ("companionId").
+// The booking holds its seats at the current fares until it is paid or the
+// hold expires; bookings that cost nothing are confirmed right away.
+//
+// Returns:
+//   - 201: Booking created, with its locator, tickets, total and hold expiry
+//   - 400: Invalid passengers or flights
+//   - 404: A companion does not belong to the user
+//   - 409: A flight cannot be booked, has no fare for the travel class or not enough seats left
+func CreateBooking(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req models.BookingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := booking.Validate(&req); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		user, err := db.GetUserByID(userID.(string))
+		if err != nil || user == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+			return
+		}
+
+		b, err := bookings.Create(*user, req, time.Now())
+		switch {
+		case errors.Is(err, booking.ErrUnknownCompanion):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Companion not found"})
+			return
+		case errors.Is(err, booking.ErrSoldOut):
+			// Sold out flights can still be waitlisted
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "waitlist": true})
+			return
+		case errors.Is(err, booking.ErrFlightNotBookable), errors.Is(err, booking.ErrNoFare):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking"})
+			return
+		}
+
+		localizeBooking(db, b)
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    b,
+			"message": "Booking created successfully",
+		})
+	}
+}
+
+// GetMyBookings returns the bookings the authenticated user made or travels
+// on, newest first.
+func GetMyBookings(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		list, err := bookings.ForUser(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve bookings"})
+			return
+		}
+		for i := range list {
+			localizeBooking(db, &list[i])
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    list,
+			"count":   len(list),
+			"message": "Bookings retrieved successfully",
+		})
+	}
+}
+
+// GetBooking returns a booking of the authenticated user by its locator.
+func GetBooking(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b, ok := accessibleBooking(c, bookings)
+		if !ok {
+			return
+		}
+
+		localizeBooking(db, b)
+		c.JSON(http.StatusOK, gin.H{
+			"data":    b,
+			"message": "Booking retrieved successfully",
+		})
+	}
+}
+
+// CancelBooking cancels all tickets of a booking on flights that have not
+// departed. Only the user who made the booking can cancel it. Open payments
+// are voided and paid tickets refunded.
+//
+// Returns:
+//   - 200: Booking cancelled
+//   - 403: The user travels on the booking but did not make it
+//   - 404: Booking not found
+//   - 409: Booking already cancelled, fully flown or passengers checked in
+func CancelBooking(db database.Database, bookings *booking.Service, charges *payments.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b, ok := accessibleBooking(c, bookings)
+		if !ok {
+			return
+		}
+		userID, _ := c.Get("userID")
+		if b.AirportUserID != userID.(string) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the person who made the booking can cancel it"})
+			return
+		}
+
+		err := bookings.Cancel(b, time.Now())
+		switch {
+		case errors.Is(err, booking.ErrBookingClosed), errors.Is(err, booking.ErrCheckedIn):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel booking"})
+			return
+		}
+
+		// The cancellation stands even if a refund fails; staff can refund
+		// the tickets later
+		if err := charges.Settle(b, time.Now()); err != nil {
+			log.Printf("Error settling payments of cancelled booking %s: %v", b.Locator, err)
+		}
+
+		b, err = bookings.Get(b.Locator)
+		if err != nil || b == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
+			return
+		}
+		localizeBooking(db, b)
+		c.JSON(http.StatusOK, gin.H{
+			"data":    b,
+			"message": "Booking cancelled successfully",
+		})
+	}
+}
+
+// CheckInBooking checks in all passengers of a booking with their first
+// valid travel document.
+//
+// Request body may contain:
+//   - flightId: Only check in for this flight of the booking
+//
+// Returns:
+//   - 200: Outcome per ticket; at least one ticket is checked in
+//   - 404: Booking not found, or the flight is not part of it
+//   - 409: No ticket could be checked in, the outcome per ticket says why
+func CheckInBooking(bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.BookingCheckInRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+				return
+			}
+		}
+
+		b, ok := accessibleBooking(c, bookings)
+		if !ok {
+			return
+		}
+
+		results, err := bookings.CheckIn(b, req.FlightID, time.Now())
+		switch {
+		case errors.Is(err, booking.ErrUnknownFlight):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
+			return
+		}
+
+		checkedIn := 0
+		for _, result := range results {
+			if result.Status == "CHECKED_IN" {
+				checkedIn++
+			}
+		}
+		if checkedIn == 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"data":  results,
+				"error": "No passenger could be checked in",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":      results,
+			"checkedIn": checkedIn,
+			"message":   "Booking checked in successfully",
+		})
+	}
+}
+
+// LookupBooking lets guests without account retrieve a booking by its
+// locator and the last name of one of its passengers. Account IDs and birth
+// dates are not included.
+//
+// Query parameters:
+//   - locator: Booking locator, e.g. "K7PX2M"
+//   - lastName: Last name of a passenger of the booking
+//
+// Returns:
+//   - 200: Booking found
+//   - 400: Locator or last name missing
+//   - 404: No booking matches both
+func LookupBooking(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locator, lastName := c.Query("locator"), c.Query("lastName")
+		if locator == "" || lastName == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Locator and last name are required"})
+			return
+		}
+
+		b, err := bookings.Lookup(locator, lastName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
+			return
+		}
+		// The same answer for unknown locators and wrong names, so locators
+		// cannot be probed
+		if b == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+
+		localizeBooking(db, b)
+		c.JSON(http.StatusOK, gin.H{
+			"data":    b,
+			"message": "Booking retrieved successfully",
+		})
+	}
+}
+
+// accessibleBooking loads the booking of the locator parameter if the
+// authenticated user made it or travels on it. Otherwise it writes the
+// error response and returns false.
+func accessibleBooking(c *gin.Context, bookings *booking.Service) (*models.Booking, bool) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	locator := booking.NormalizeLocator(c.Param("locator"))
+	if locator == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		return nil, false
+	}
+
+	b, err := bookings.Get(locator)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
+		return nil, false
+	}
+	if b == nil || !booking.CanAccess(b, userID.(string)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		return nil, false
+	}
+	return b, true
+}
+
+// localizeBooking adds the local departure times to the tickets of a booking.
+func localizeBooking(db database.Database, b *models.Booking) {
+	localizer := timezone.NewLocalizer(db)
+	for i := range b.Tickets {
+		localizer.Ticket(&b.Tickets[i])
+	}
+}
+
+// BookingRoutes registers the bookings of the authenticated user.
+func BookingRoutes(router *gin.RouterGroup, db database.Database, bookings *booking.Service, charges *payments.Service) {
+	router.GET("", GetMyBookings(db, bookings))                                                       // Bookings the user made or travels on
+	router.POST("", CreateBooking(db, bookings))                                                      // Book passengers on flights
+	router.GET("/:locator", GetBooking(db, bookings))                                                 // Booking with passengers and tickets
+	router.POST("/:locator/cancel", middleware.Idempotency(db), CancelBooking(db, bookings, charges)) // Cancel all upcoming tickets of the booking
+	router.POST("/:locator/checkin", CheckInBooking(bookings))                                        // Check in all passengers of the booking
+}
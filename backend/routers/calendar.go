@@ -0,0 +1,233 @@
+// Package routers provides HTTP route handlers for the iCalendar export of
+// passengers' trips in the MindenAirport API.
+package routers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"mindenairport/calendar"
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	calendarName         = "MindenAirport trips"
+	calendarContentType  = "text/calendar; charset=utf-8"
+	calendarRefreshEvery = time.Hour // Polling interval suggested to subscribed calendar apps
+)
+
+// GetMyTicketsCalendar returns all tickets of the authenticated user as
+// iCalendar file for a one-off import.
+func GetMyTicketsCalendar(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		tickets, err := db.GetTicketsByUserID(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tickets"})
+			return
+		}
+
+		cal, err := calendar.NewTrips(db).Calendar(calendarName, tickets)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar"})
+			return
+		}
+
+		writeCalendar(c, cal, "trips.ics", true)
+	}
+}
+
+// GetTicketCalendar returns a single ticket of the authenticated user as
+// iCalendar file. Tickets of other users are reported as not found.
+func GetTicketCalendar(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		ticket, err := db.GetTicketByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket"})
+			return
+		}
+		if ticket.ID == "" || !ticket.HeldBy(userID.(string)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+			return
+		}
+
+		cal, err := calendar.NewTrips(db).Calendar(calendarName, []models.Ticket{ticket})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar"})
+			return
+		}
+		if len(cal.Events) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		writeCalendar(c, cal, "ticket-"+ticket.ID+".ics", true)
+	}
+}
+
+// GetCalendarSubscription returns whether the authenticated user has a
+// calendar subscription. The feed URL cannot be shown again, as only a hash
+// of its token is stored.
+func GetCalendarSubscription(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		subscription, err := db.GetCalendarSubscription(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve calendar subscription"})
+			return
+		}
+		if subscription == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No calendar subscription"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    subscription,
+			"message": "Calendar subscription retrieved successfully",
+		})
+	}
+}
+
+// CreateCalendarSubscription creates a new secret feed URL for the trips of
+// the authenticated user. A previous URL stops working.
+//
+// Returns:
+//   - 201: Subscription created, the response contains the feed URL
+//   - 500: Internal server error
+func CreateCalendarSubscription(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		token, hash, err := calendar.NewToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar subscription"})
+			return
+		}
+
+		createdAt := time.Now().UTC()
+		if err := db.SaveCalendarSubscription(userID.(string), hash, createdAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar subscription"})
+			return
+		}
+
+		url, webcal := calendarFeedURLs(c, token)
+		c.JSON(http.StatusCreated, gin.H{
+			"data": models.CalendarSubscription{
+				AirportUserID: userID.(string),
+				CreatedAt:     createdAt,
+				URL:           url,
+				WebcalURL:     webcal,
+			},
+			"message": "Calendar subscription created successfully",
+		})
+	}
+}
+
+// DeleteCalendarSubscription revokes the feed URL of the authenticated user.
+func DeleteCalendarSubscription(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		if err := db.DeleteCalendarSubscription(userID.(string)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete calendar subscription"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Calendar subscription deleted successfully"})
+	}
+}
+
+// GetCalendarFeed returns the trips of the user a subscription token belongs
+// to. It needs no login, as calendar apps cannot send one; the token in the
+// URL is the secret.
+func GetCalendarFeed(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := db.GetCalendarSubscriber(calendar.HashToken(c.Param("token")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve calendar subscription"})
+			return
+		}
+		if userID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+			return
+		}
+
+		tickets, err := db.GetTicketsByUserID(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tickets"})
+			return
+		}
+
+		cal, err := calendar.NewTrips(db).Calendar(calendarName, tickets)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar"})
+			return
+		}
+		cal.RefreshInterval = calendarRefreshEvery
+
+		c.Header("Cache-Control", "private, max-age=300")
+		writeCalendar(c, cal, "trips.ics", false)
+	}
+}
+
+// writeCalendar sends a calendar, as download if attachment is set.
+func writeCalendar(c *gin.Context, cal calendar.Calendar, filename string, attachment bool) {
+	disposition := "inline"
+	if attachment {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, filename))
+	c.Data(http.StatusOK, calendarContentType, cal.Bytes(time.Now()))
+}
+
+// calendarFeedURLs returns the absolute URL of the feed for a token, based on
+// the host the request was sent to, and the same URL with the webcal scheme.
+func calendarFeedURLs(c *gin.Context, token string) (string, string) {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	path := fmt.Sprintf("://%s/api/calendar/%s/trips.ics", c.Request.Host, token)
+	return scheme + path, "webcal" + path
+}
+
+// CalendarRoutes registers the public calendar feed.
+func CalendarRoutes(router *gin.RouterGroup, db database.Database) {
+	router.GET("/:token/trips.ics", GetCalendarFeed(db)) // Subscribed trips of a user
+}
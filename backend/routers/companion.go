@@ -0,0 +1,264 @@
+// Package routers provides HTTP route handlers for the companion traveller
+// profiles of an account in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// travellerSelf selects the account holder's own items in the traveller filter.
+const travellerSelf = "self"
+
+// validateCompanion normalizes the companion attributes. It returns an error message or "".
+func validateCompanion(companion *models.Companion, now time.Time) string {
+	companion.FirstName = strings.TrimSpace(companion.FirstName)
+	companion.LastName = strings.TrimSpace(companion.LastName)
+	y, m, d := companion.BirthDate.Date()
+	companion.BirthDate = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	if companion.FirstName == "" || companion.LastName == "" {
+		return "First name and last name are required"
+	}
+	if companion.BirthDate.After(now) {
+		return "Birth date must not be in the future"
+	}
+	return ""
+}
+
+// ownCompanion loads a companion of the authenticated user. Otherwise it
+// writes the error response and returns nil.
+func ownCompanion(c *gin.Context, db database.Database, userID, id string) *models.Companion {
+	companion, err := db.GetCompanionByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve companion"})
+		return nil
+	}
+	if companion == nil || companion.AirportUserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Companion not found"})
+		return nil
+	}
+	return companion
+}
+
+// travellerFilter limits the lists of the authenticated user to one
+// traveller: "" keeps all, "self" the user's own items and a companion ID
+// those of the companion.
+type travellerFilter string
+
+// parseTravellerFilter reads the traveller query parameter. Unknown
+// companions are answered with 404 and false.
+func parseTravellerFilter(c *gin.Context, db database.Database, userID string) (travellerFilter, bool) {
+	traveller := c.Query("traveller")
+	if traveller == "" || traveller == travellerSelf {
+		return travellerFilter(traveller), true
+	}
+	if ownCompanion(c, db, userID, traveller) == nil {
+		return "", false
+	}
+	return travellerFilter(traveller), true
+}
+
+// matches reports whether an item of the given companion, or of the user if
+// companionID is empty, passes the filter.
+func (f travellerFilter) matches(companionID string) bool {
+	switch f {
+	case "":
+		return true
+	case travellerSelf:
+		return companionID == ""
+	default:
+		return companionID == string(f)
+	}
+}
+
+// matchesTicket reports whether a ticket of the user passes the filter.
+// Tickets held for guest passengers only show up unfiltered.
+func (f travellerFilter) matchesTicket(ticket models.Ticket, userID string) bool {
+	if f == travellerSelf {
+		return ticket.CompanionID == "" && ticket.TravellerID() == userID
+	}
+	return f.matches(ticket.CompanionID)
+}
+
+// GetCompanions returns the companions of the authenticated user.
+func GetCompanions(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		companions, err := db.GetCompanions(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve companions"})
+			return
+		}
+		if companions == nil {
+			companions = []models.Companion{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    companions,
+			"count":   len(companions),
+			"message": "Companions retrieved successfully",
+		})
+	}
+}
+
+// GetCompanion returns a companion of the authenticated user.
+func GetCompanion(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		companion := ownCompanion(c, db, userID.(string), c.Param("id"))
+		if companion == nil {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    companion,
+			"message": "Companion retrieved successfully",
+		})
+	}
+}
+
+// CreateCompanion adds a traveller profile to the authenticated user.
+//
+// Request body should contain:
+//   - firstName, lastName: Name as in the travel document
+//   - birthDate: Date of birth
+//
+// Returns:
+//   - 201: Companion created
+//   - 400: Invalid name or birth date
+func CreateCompanion(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var companion models.Companion
+		if err := c.ShouldBindJSON(&companion); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := validateCompanion(&companion, time.Now()); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		companion.ID = ""
+		companion.AirportUserID = userID.(string)
+		companion.CreatedAt = time.Time{}
+		if err := db.CreateCompanion(&companion); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create companion"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    companion,
+			"message": "Companion created successfully",
+		})
+	}
+}
+
+// UpdateCompanion replaces the name and birth date of a companion. Existing
+// bookings keep the name they were made with.
+func UpdateCompanion(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		existing := ownCompanion(c, db, userID.(string), c.Param("id"))
+		if existing == nil {
+			return
+		}
+
+		var companion models.Companion
+		if err := c.ShouldBindJSON(&companion); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := validateCompanion(&companion, time.Now()); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		companion.ID = existing.ID
+		companion.AirportUserID = existing.AirportUserID
+		companion.CreatedAt = existing.CreatedAt
+		if err := db.UpdateCompanion(companion); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update companion"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    companion,
+			"message": "Companion updated successfully",
+		})
+	}
+}
+
+// DeleteCompanion removes a companion together with their travel documents.
+//
+// Returns:
+//   - 200: Companion deleted
+//   - 404: Companion not found
+//   - 409: The companion still travels on an upcoming flight
+func DeleteCompanion(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		companion := ownCompanion(c, db, userID.(string), c.Param("id"))
+		if companion == nil {
+			return
+		}
+
+		deleted, err := db.DeleteCompanion(companion.ID, time.Now().UTC())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete companion"})
+			return
+		}
+		if !deleted {
+			c.JSON(http.StatusConflict, gin.H{"error": "Companion still travels on an upcoming flight, cancel the booking first"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Companion deleted successfully"})
+	}
+}
+
+// CompanionRoutes registers the companions of the authenticated user.
+func CompanionRoutes(router *gin.RouterGroup, db database.Database) {
+	router.GET("", GetCompanions(db))
+	router.POST("", CreateCompanion(db))
+	router.GET("/:id", GetCompanion(db))
+	router.PUT("/:id", UpdateCompanion(db))
+	router.DELETE("/:id", DeleteCompanion(db))
+}
@@ -0,0 +1,362 @@
+// Package routers provides HTTP route handlers for crew management and
+// flight crew rostering in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRosterDays is the number of days shown in a roster if none is requested.
+const defaultRosterDays = 14
+
+// validateCrewMember normalizes the crew member attributes. It returns an error message or "".
+func validateCrewMember(member *models.CrewMember) string {
+	member.FirstName = strings.TrimSpace(member.FirstName)
+	member.LastName = strings.TrimSpace(member.LastName)
+	member.Role = strings.TrimSpace(member.Role)
+
+	if member.FirstName == "" || member.LastName == "" {
+		return "First name and last name are required"
+	}
+	if member.Role == "" {
+		return "Role is required"
+	}
+	return ""
+}
+
+// GetCrewMembers returns all crew members
+func GetCrewMembers(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		members, err := db.GetCrewMembers()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve crew members"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    members,
+			"message": "Crew members retrieved successfully",
+		})
+	}
+}
+
+// GetCrewMemberByID returns a specific crew member
+func GetCrewMemberByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		member, err := db.GetCrewMemberByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve crew member"})
+			return
+		}
+
+		if member == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crew member not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    member,
+			"message": "Crew member retrieved successfully",
+		})
+	}
+}
+
+// CreateCrewMember adds a new crew member.
+//
+// Request body should contain:
+//   - firstName, lastName: Name of the crew member
+//   - role: Default role (e.g. "Cabin Crew", "Purser")
+//   - licenseNumber, licenseExpiry: Optional license details
+func CreateCrewMember(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var member models.CrewMember
+		if err := c.ShouldBindJSON(&member); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateCrewMember(&member); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// IDs are generated by the database layer
+		member.ID = ""
+		if err := db.CreateCrewMember(&member); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create crew member"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    member,
+			"message": "Crew member created successfully",
+		})
+	}
+}
+
+// UpdateCrewMember replaces the attributes of an existing crew member
+func UpdateCrewMember(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var member models.CrewMember
+		if err := c.ShouldBindJSON(&member); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		member.ID = c.Param("id")
+
+		if msg := validateCrewMember(&member); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetCrewMemberByID(member.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve crew member"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crew member not found"})
+			return
+		}
+
+		if err := db.UpdateCrewMember(member); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update crew member"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    member,
+			"message": "Crew member updated successfully",
+		})
+	}
+}
+
+// DeleteCrewMember removes a crew member. Crew members still assigned to flights cannot be deleted.
+func DeleteCrewMember(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		member, err := db.GetCrewMemberByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve crew member"})
+			return
+		}
+		if member == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crew member not found"})
+			return
+		}
+
+		if err := db.DeleteCrewMember(member.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Crew member is still assigned to flights"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete crew member"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Crew member deleted successfully",
+		})
+	}
+}
+
+// GetCrewRoster returns the upcoming duties and duty periods of a crew member.
+//
+// Query parameters:
+//   - days: Number of days to include, 1-90 (defaults to 14)
+func GetCrewRoster(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		days := defaultRosterDays
+		if d := c.Query("days"); d != "" {
+			parsed, err := strconv.Atoi(d)
+			if err != nil || parsed < 1 || parsed > 90 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Days must be between 1 and 90"})
+				return
+			}
+			days = parsed
+		}
+
+		member, err := db.GetCrewMemberByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve crew member"})
+			return
+		}
+		if member == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crew member not found"})
+			return
+		}
+
+		from := time.Now()
+		roster, err := planner.CrewRoster(*member, from, from.AddDate(0, 0, days))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve roster"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    roster,
+			"message": "Roster retrieved successfully",
+		})
+	}
+}
+
+// GetFlightCrew returns the crew assigned to a flight
+func GetFlightCrew(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		crew, err := db.GetFlightCrew(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flight crew"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    crew,
+			"message": "Flight crew retrieved successfully",
+		})
+	}
+}
+
+// AssignFlightCrew assigns a crew member to a flight in a given role.
+// Assignments are rejected if the license has expired, the crew member is
+// already booked on an overlapping flight, or duty and rest limits are exceeded.
+//
+// Request body should contain:
+//   - crewMemberId: ID of the crew member
+//   - role: Role on this flight (defaults to the crew member's role)
+//
+// Returns:
+//   - 201: Crew member assigned
+//   - 400: Invalid request, expired license or duty limit exceeded
+//   - 404: Flight or crew member not found
+//   - 409: Crew member is booked on an overlapping flight
+func AssignFlightCrew(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var assignment models.FlightCrew
+		if err := c.ShouldBindJSON(&assignment); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		assignment.ID = ""
+		assignment.FlightID = c.Param("id")
+
+		flight, err := db.GetFlightByID(assignment.FlightID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flight"})
+			return
+		}
+		if flight.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		member, err := db.GetCrewMemberByID(assignment.CrewMemberID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve crew member"})
+			return
+		}
+		if member == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crew member not found"})
+			return
+		}
+
+		assignment.Role = strings.TrimSpace(assignment.Role)
+		if assignment.Role == "" {
+			assignment.Role = member.Role
+		}
+
+		if err := planner.ValidateCrewAssignment(*member, flight); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.AssignFlightCrew(&assignment); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign crew member"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    assignment,
+			"message": "Crew member assigned successfully",
+		})
+	}
+}
+
+// RemoveFlightCrew removes a crew assignment from a flight
+func RemoveFlightCrew(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		if err := db.RemoveFlightCrew(c.Param("id"), c.Param("assignmentId")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove crew member"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Crew member removed successfully",
+		})
+	}
+}
+
+// CrewRoutes sets up crew management routes
+func CrewRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	router.GET("", GetCrewMembers(db))
+	router.POST("", CreateCrewMember(db))
+	router.GET("/:id", GetCrewMemberByID(db))
+	router.PUT("/:id", UpdateCrewMember(db))
+	router.DELETE("/:id", DeleteCrewMember(db))
+	router.GET("/:id/roster", GetCrewRoster(db, planner))
+}
@@ -0,0 +1,363 @@
+// Package routers provides HTTP route handlers for passengers' travel
+// documents and the APIS export in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"mindenairport/apis"
+	"mindenairport/database"
+	"mindenairport/manifest"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTravelDocuments returns the travel documents of the authenticated user
+// and their companions.
+//
+// Query parameters:
+//   - traveller: "self" or a companion ID to list only their documents
+func GetTravelDocuments(db database.Database, passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		filter, ok := parseTravellerFilter(c, db, userID.(string))
+		if !ok {
+			return
+		}
+
+		all, err := passengers.Documents(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve travel documents"})
+			return
+		}
+		documents := []models.TravelDocument{}
+		for _, document := range all {
+			if filter.matches(document.CompanionID) {
+				documents = append(documents, document)
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    documents,
+			"count":   len(documents),
+			"message": "Travel documents retrieved successfully",
+		})
+	}
+}
+
+// GetTravelDocument returns a travel document of the authenticated user.
+func GetTravelDocument(passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		document, err := passengers.Document(userID.(string), c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve travel document"})
+			return
+		}
+		if document == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Travel document not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    document,
+			"message": "Travel document retrieved successfully",
+		})
+	}
+}
+
+// CreateTravelDocument adds a passport or identity card to the authenticated
+// user or one of their companions.
+//
+// Request body should contain:
+//   - type: "P" (passport) or "I" (identity card)
+//   - number: Document number
+//   - nationality, issuingCountry: ISO 3166-1 alpha-3 codes
+//   - expiryDate: Last day of validity
+//   - sex: "M", "F" or "X"
+//   - companionId: Companion the document belongs to (optional)
+//
+// Returns:
+//   - 201: Document created
+//   - 400: Invalid or expired document
+//   - 404: Companion not found
+//   - 500: Internal server error
+func CreateTravelDocument(db database.Database, passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var document models.TravelDocument
+		if err := c.ShouldBindJSON(&document); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := apis.Validate(&document, time.Now()); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		if document.CompanionID != "" && ownCompanion(c, db, userID.(string), document.CompanionID) == nil {
+			return
+		}
+
+		document.ID = ""
+		document.AirportUserID = userID.(string)
+		document.CreatedAt = time.Time{}
+		if err := passengers.Create(&document); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create travel document"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    document,
+			"message": "Travel document created successfully",
+		})
+	}
+}
+
+// UpdateTravelDocument replaces a travel document of the authenticated user,
+// e.g. after it was renewed. The document stays with its traveller.
+func UpdateTravelDocument(passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		existing, err := passengers.Document(userID.(string), c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve travel document"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Travel document not found"})
+			return
+		}
+
+		var document models.TravelDocument
+		if err := c.ShouldBindJSON(&document); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := apis.Validate(&document, time.Now()); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		document.ID = existing.ID
+		document.AirportUserID = existing.AirportUserID
+		document.CompanionID = existing.CompanionID
+		document.CreatedAt = existing.CreatedAt
+		if err := passengers.Update(document); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update travel document"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    document,
+			"message": "Travel document updated successfully",
+		})
+	}
+}
+
+// DeleteTravelDocument removes a travel document of the authenticated user.
+func DeleteTravelDocument(passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		document, err := passengers.Document(userID.(string), c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve travel document"})
+			return
+		}
+		if document == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Travel document not found"})
+			return
+		}
+
+		if err := passengers.Delete(document.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete travel document"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Travel document deleted successfully"})
+	}
+}
+
+// CheckInTicket checks in a ticket of the authenticated user. International
+// flights require a travel document that is valid on arrival.
+//
+// Request body may contain:
+//   - travelDocumentId: Document to travel with (defaults to the first valid one)
+//
+// Returns:
+//   - 200: Ticket checked in
+//   - 400: No valid travel document for an international flight
+//   - 404: Ticket or travel document not found
+//   - 409: Ticket is not confirmed, the flight is cancelled or has departed, or
+//     all seats of an overbooked flight are checked in
+func CheckInTicket(db database.Database, passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req models.CheckInRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+				return
+			}
+		}
+
+		ticket, err := db.GetTicketByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket"})
+			return
+		}
+		if ticket.ID == "" || !ticket.HeldBy(userID.(string)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+			return
+		}
+
+		document, err := passengers.CheckIn(ticket, req.TravelDocumentID, time.Now())
+		switch {
+		case errors.Is(err, apis.ErrUnknownDocument):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Travel document not found"})
+			return
+		case errors.Is(err, apis.ErrDocumentRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, apis.ErrCheckInClosed), errors.Is(err, apis.ErrFlightFull):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
+			return
+		}
+
+		response := gin.H{"ticketId": ticket.ID, "status": "CHECKED_IN"}
+		if document != nil {
+			response["travelDocumentId"] = document.ID
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"data":    response,
+			"message": "Checked in successfully",
+		})
+	}
+}
+
+// GetUserTravelDocuments returns the travel documents of a user for admins.
+func GetUserTravelDocuments(db database.Database, passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		documents, err := passengers.Documents(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve travel documents"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    documents,
+			"count":   len(documents),
+			"message": "Travel documents retrieved successfully",
+		})
+	}
+}
+
+// GetFlightPaxlst returns the UN/EDIFACT PAXLST message with the passengers
+// and travel documents of a flight.
+//
+// Query parameters:
+//   - recipient: Interchange recipient, e.g. the border authority's ID (default "APIS")
+//   - format: "json" (default) or "edi" for the plain message
+//
+// Returns:
+//   - 200: PAXLST message; passengers without valid document are listed as missing
+//   - 404: Flight not found
+func GetFlightPaxlst(db database.Database, passengers *apis.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "edi" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be either 'json' or 'edi'"})
+			return
+		}
+
+		m, err := manifest.Get(db, c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve manifest"})
+			return
+		}
+		if m == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		paxlst, err := passengers.Paxlst(m, c.DefaultQuery("recipient", apis.DefaultRecipient), time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create PAXLST"})
+			return
+		}
+
+		if format == "edi" {
+			c.Header("Content-Disposition", `attachment; filename="paxlst-`+paxlst.Reference+`.edi"`)
+			c.Data(http.StatusOK, "application/edifact", []byte(paxlst.Content))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    paxlst,
+			"message": "PAXLST created successfully",
+		})
+	}
+}
+
+// TravelDocumentRoutes registers the travel documents of the authenticated user.
+func TravelDocumentRoutes(router *gin.RouterGroup, db database.Database, passengers *apis.Service) {
+	router.GET("", GetTravelDocuments(db, passengers))
+	router.POST("", CreateTravelDocument(db, passengers))
+	router.GET("/:id", GetTravelDocument(passengers))
+	router.PUT("/:id", UpdateTravelDocument(passengers))
+	router.DELETE("/:id", DeleteTravelDocument(passengers))
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 
 	"mindenairport/database"
+	"mindenairport/geo"
+	"mindenairport/timezone"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,6 +22,7 @@ import (
 //   - Scheduled departure and arrival times
 //   - Current flight status
 //   - Gate and baggage claim information
+//   - Route distance and estimated CO2 emissions per passenger
 //
 // Returns:
 //   - 200: List of all flights
@@ -27,7 +30,19 @@ import (
 func GetFlights(db database.Database) gin.HandlerFunc {
 	fn := func(c *gin.Context) {
 		// Retrieve all flights from database
-		c.IndentedJSON(http.StatusOK, db.GetFlights())
+		flights := db.GetFlights()
+
+		estimator := geo.NewEstimator(db)
+		localizer := timezone.NewLocalizer(db)
+		for i := range flights {
+			if err := estimator.Flight(&flights[i]); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to estimate flight emissions"})
+				return
+			}
+			localizer.Flight(&flights[i])
+		}
+
+		c.IndentedJSON(http.StatusOK, flights)
 	}
 
 	return gin.HandlerFunc(fn)
@@ -52,6 +67,14 @@ func GetFlightByID(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		if flight.ID != "" {
+			if err := geo.NewEstimator(db).Flight(&flight); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to estimate flight emissions"})
+				return
+			}
+			timezone.NewLocalizer(db).Flight(&flight)
+		}
+
 		c.IndentedJSON(http.StatusOK, flight)
 	}
 
@@ -0,0 +1,291 @@
+// Package routers provides HTTP route handlers for gate management and
+// gate planning in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validateGate normalizes the gate attributes and checks them against the
+// values allowed by the GATE table. It returns an error message or "".
+func validateGate(gate *models.Gate) string {
+	gate.ID = strings.ToUpper(strings.TrimSpace(gate.ID))
+	gate.MaxAircraftCode = strings.ToUpper(gate.MaxAircraftCode)
+	gate.StandType = strings.ToUpper(gate.StandType)
+	gate.Area = strings.ToUpper(gate.Area)
+	gate.Status = strings.ToUpper(gate.Status)
+
+	if gate.Status == "" {
+		gate.Status = "ACTIVE"
+	}
+
+	switch {
+	case gate.ID == "" || len(gate.ID) > 10:
+		return "Gate ID is required and must not exceed 10 characters"
+	case gate.TerminalID == "":
+		return "Terminal ID is required"
+	case len(gate.MaxAircraftCode) != 1 || gate.MaxAircraftCode < "A" || gate.MaxAircraftCode > "F":
+		return "Max aircraft code must be one of A-F"
+	case gate.StandType != planning.StandContact && gate.StandType != planning.StandRemote:
+		return "Stand type must be either 'CONTACT' or 'REMOTE'"
+	case gate.Area != planning.AreaSchengen && gate.Area != planning.AreaNonSchengen:
+		return "Area must be either 'SCHENGEN' or 'NON_SCHENGEN'"
+	case gate.Status != "ACTIVE" && gate.Status != "MAINTENANCE" && gate.Status != "CLOSED":
+		return "Status must be one of 'ACTIVE', 'MAINTENANCE' or 'CLOSED'"
+	}
+	return ""
+}
+
+// respondPlanningError writes the HTTP response for an error returned by the planner.
+// Invalid assignments yield 400, conflicts with other flights or occupied plots 409.
+func respondPlanningError(c *gin.Context, err error) {
+	var validationErr *planning.ValidationError
+	var conflictErr *planning.ConflictError
+	var occupiedErr *planning.OccupiedError
+
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+	case errors.As(err, &conflictErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":              conflictErr.Error(),
+			"resource":           conflictErr.Resource,
+			"conflictingFlights": conflictErr.Flights,
+		})
+	case errors.As(err, &occupiedErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     occupiedErr.Error(),
+			"resource":  "plot",
+			"occupants": occupiedErr.Occupants,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate flight assignment"})
+	}
+}
+
+// GetGates returns all gates
+func GetGates(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		gates, err := db.GetGates()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve gates"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    gates,
+			"message": "Gates retrieved successfully",
+		})
+	}
+}
+
+// GetGateByID returns a specific gate
+func GetGateByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		gate, err := db.GetGateByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve gate"})
+			return
+		}
+
+		if gate == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gate not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    gate,
+			"message": "Gate retrieved successfully",
+		})
+	}
+}
+
+// CreateGate adds a new gate to a terminal.
+//
+// Request body should contain:
+//   - id: Gate designator (e.g. "A1")
+//   - terminalId: Terminal the gate belongs to
+//   - maxAircraftCode: Largest aircraft code letter (A-F) the stand accepts
+//   - standType: CONTACT or REMOTE
+//   - area: SCHENGEN or NON_SCHENGEN
+//   - status: ACTIVE (default), MAINTENANCE or CLOSED
+func CreateGate(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var gate models.Gate
+		if err := c.ShouldBindJSON(&gate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateGate(&gate); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetGateByID(gate.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve gate"})
+			return
+		}
+		if existing != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Gate already exists"})
+			return
+		}
+
+		if err := db.CreateGate(gate); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create gate"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    gate,
+			"message": "Gate created successfully",
+		})
+	}
+}
+
+// UpdateGate replaces the attributes of an existing gate
+func UpdateGate(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var gate models.Gate
+		if err := c.ShouldBindJSON(&gate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		gate.ID = c.Param("id")
+
+		if msg := validateGate(&gate); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetGateByID(gate.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve gate"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gate not found"})
+			return
+		}
+
+		if err := db.UpdateGate(gate); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update gate"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    gate,
+			"message": "Gate updated successfully",
+		})
+	}
+}
+
+// DeleteGate removes a gate. Gates still referenced by flights cannot be deleted.
+func DeleteGate(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		gate, err := db.GetGateByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve gate"})
+			return
+		}
+		if gate == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gate not found"})
+			return
+		}
+
+		if err := db.DeleteGate(gate.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Gate is still assigned to flights"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete gate"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Gate deleted successfully",
+		})
+	}
+}
+
+// AutoAssignGates proposes a conflict-free gate plan for all departures of a day.
+// The plan is not stored; flights are updated individually once it is accepted.
+//
+// Query parameters:
+//   - date: Day to plan in YYYY-MM-DD format (defaults to today)
+func AutoAssignGates(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		day := time.Now()
+		if d := c.Query("date"); d != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", d, planner.Config.Location)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Date must be in YYYY-MM-DD format"})
+				return
+			}
+			day = parsed
+		}
+
+		plan, err := planner.ProposeGatePlan(day)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create gate plan"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plan,
+			"message": "Gate plan proposed successfully",
+		})
+	}
+}
+
+// GateRoutes sets up gate management routes
+func GateRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	router.GET("", GetGates(db))
+	router.POST("", CreateGate(db))
+	router.POST("/auto-assign", AutoAssignGates(db, planner))
+	router.GET("/:id", GetGateByID(db))
+	router.PUT("/:id", UpdateGate(db))
+	router.DELETE("/:id", DeleteGate(db))
+}
@@ -0,0 +1,373 @@
+// Package routers provides HTTP route handlers for hangar management,
+// hangar allocation and inspection reminders in the MindenAirport API.
+package routers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/jobs"
+	"mindenairport/models"
+	"mindenairport/planning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validateHangar normalizes the hangar attributes and checks them against the
+// values allowed by the HANGAR table. It returns an error message or "".
+func validateHangar(hangar *models.Hangar) string {
+	hangar.PlotID = strings.TrimSpace(hangar.PlotID)
+	hangar.Status = strings.ToUpper(hangar.Status)
+
+	if hangar.Status == "" {
+		hangar.Status = "ACTIVE"
+	}
+
+	switch {
+	case hangar.PlotID == "":
+		return "Plot ID is required"
+	case hangar.Capacity < 0 || hangar.SizeSqFt < 0:
+		return "Capacity and size must not be negative"
+	case hangar.Status != "ACTIVE" && hangar.Status != "MAINTENANCE" && hangar.Status != "CLOSED":
+		return "Status must be one of 'ACTIVE', 'MAINTENANCE' or 'CLOSED'"
+	case hangar.LastInspection != nil && hangar.NextInspection != nil && !hangar.NextInspection.After(*hangar.LastInspection):
+		return "Next inspection must be after the last inspection"
+	}
+	return ""
+}
+
+// recheckInspections refreshes the inspection reminders after a hangar changed.
+func recheckInspections(monitor *jobs.InspectionMonitor) {
+	go func() {
+		if _, err := monitor.Check(time.Now()); err != nil {
+			log.Printf("Error checking hangar inspections: %v", err)
+		}
+	}()
+}
+
+// GetHangars returns all hangars
+func GetHangars(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		hangars, err := db.GetHangars()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve hangars"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    hangars,
+			"message": "Hangars retrieved successfully",
+		})
+	}
+}
+
+// GetHangarByID returns a specific hangar with its parked planes and free space
+func GetHangarByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		hangar, err := db.GetHangarByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve hangar"})
+			return
+		}
+		if hangar == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hangar not found"})
+			return
+		}
+
+		parked, err := db.GetPlanesByHangar(hangar.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve parked planes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    planning.HangarOccupancy(*hangar, parked),
+			"message": "Hangar retrieved successfully",
+		})
+	}
+}
+
+// CreateHangar adds a new hangar on a plot.
+//
+// Request body should contain:
+//   - plotId: Free hangar plot the hangar is built on
+//   - capacity: Maximum number of parked planes (0 for unlimited)
+//   - sizeSqFt: Floor space in square feet (0 for unlimited)
+//   - status: ACTIVE (default), MAINTENANCE or CLOSED
+//   - lastInspection, nextInspection: Optional safety inspection dates
+func CreateHangar(db database.Database, planner *planning.Planner, monitor *jobs.InspectionMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var hangar models.Hangar
+		if err := c.ShouldBindJSON(&hangar); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateHangar(&hangar); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// IDs are generated by the database layer
+		hangar.ID = ""
+		occupant := models.PlotOccupant{Kind: models.OccupantHangar, PlotID: hangar.PlotID}
+		if err := planner.ValidatePlacement(occupant, 0); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.CreateHangar(&hangar); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create hangar"})
+			return
+		}
+		refreshPlotStatus(db, hangar.PlotID)
+		recheckInspections(monitor)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    hangar,
+			"message": "Hangar created successfully",
+		})
+	}
+}
+
+// UpdateHangar replaces the attributes of an existing hangar. The capacity
+// and size cannot be reduced below what the parked planes already take, and
+// a new plot must be free and carry the parked planes.
+func UpdateHangar(db database.Database, planner *planning.Planner, monitor *jobs.InspectionMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var hangar models.Hangar
+		if err := c.ShouldBindJSON(&hangar); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		hangar.ID = c.Param("id")
+
+		if msg := validateHangar(&hangar); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetHangarByID(hangar.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve hangar"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hangar not found"})
+			return
+		}
+
+		parked, err := db.GetPlanesByHangar(hangar.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve parked planes"})
+			return
+		}
+		occupancy := planning.HangarOccupancy(hangar, parked)
+		if (hangar.Capacity > 0 && len(parked) > hangar.Capacity) || (hangar.SizeSqFt > 0 && occupancy.UsedSqFt > hangar.SizeSqFt) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":    "Capacity and size must fit the planes parked in the hangar",
+				"planes":   len(parked),
+				"usedSqFt": occupancy.UsedSqFt,
+			})
+			return
+		}
+
+		if hangar.PlotID != existing.PlotID {
+			occupant := models.PlotOccupant{Kind: models.OccupantHangar, ID: hangar.ID, Name: hangar.ID, PlotID: hangar.PlotID}
+			if err := planner.ValidatePlacement(occupant, planning.HangarLoad(parked)); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+
+		if err := db.UpdateHangar(hangar); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update hangar"})
+			return
+		}
+		refreshPlotStatus(db, existing.PlotID, hangar.PlotID)
+		recheckInspections(monitor)
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    hangar,
+			"message": "Hangar updated successfully",
+		})
+	}
+}
+
+// DeleteHangar removes a hangar. Hangars with parked planes cannot be deleted.
+func DeleteHangar(db database.Database, monitor *jobs.InspectionMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		hangar, err := db.GetHangarByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve hangar"})
+			return
+		}
+		if hangar == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hangar not found"})
+			return
+		}
+
+		if err := db.DeleteHangar(hangar.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Hangar still has parked planes"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete hangar"})
+			return
+		}
+		refreshPlotStatus(db, hangar.PlotID)
+		recheckInspections(monitor)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Hangar deleted successfully",
+		})
+	}
+}
+
+// AllocateHangar parks a plane in a hangar. The hangar must be open and have
+// a free slot and enough floor space for the aircraft type, and its plot must
+// carry the additional weight.
+//
+// Request body should contain:
+//   - planeId: ID of the plane to park
+func AllocateHangar(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var request struct {
+			PlaneID string `json:"planeId" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		hangar, err := db.GetHangarByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve hangar"})
+			return
+		}
+		if hangar == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hangar not found"})
+			return
+		}
+
+		plane, err := db.GetPlaneByID(request.PlaneID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plane"})
+			return
+		}
+		if plane == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plane not found"})
+			return
+		}
+
+		plane.HangarID = hangar.ID
+		if err := planner.ValidateHangar(*plane); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.UpdatePlane(*plane); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plane"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plane,
+			"message": "Plane parked in hangar successfully",
+		})
+	}
+}
+
+// ReleaseHangar removes a plane from a hangar
+func ReleaseHangar(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		plane, err := db.GetPlaneByID(c.Param("planeId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plane"})
+			return
+		}
+		if plane == nil || plane.HangarID != c.Param("id") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plane is not parked in this hangar"})
+			return
+		}
+
+		plane.HangarID = ""
+		if err := db.UpdatePlane(*plane); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plane"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plane,
+			"message": "Plane removed from hangar successfully",
+		})
+	}
+}
+
+// GetInspectionReminders returns the hangars whose safety inspection is due
+// or overdue, as found by the latest inspection check.
+func GetInspectionReminders(db database.Database, monitor *jobs.InspectionMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    monitor.Report(),
+			"message": "Inspection reminders retrieved successfully",
+		})
+	}
+}
+
+// HangarRoutes sets up hangar management and allocation routes
+func HangarRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner, monitor *jobs.InspectionMonitor) {
+	router.GET("", GetHangars(db))
+	router.POST("", CreateHangar(db, planner, monitor))
+	router.GET("/inspections", GetInspectionReminders(db, monitor))
+	router.GET("/:id", GetHangarByID(db))
+	router.PUT("/:id", UpdateHangar(db, planner, monitor))
+	router.DELETE("/:id", DeleteHangar(db, monitor))
+	router.POST("/:id/planes", AllocateHangar(db, planner))
+	router.DELETE("/:id/planes/:planeId", ReleaseHangar(db))
+}
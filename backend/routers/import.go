@@ -0,0 +1,201 @@
+// Package routers provides HTTP route handlers for reference data and
+// schedule imports in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+	"mindenairport/refdata"
+	"mindenairport/schedule"
+
+	"github.com/gin-gonic/gin"
+)
+
+// refdataPath returns the path of a file in the reference data directory.
+// Only plain file names are accepted, so requests cannot read other files
+// of the server.
+func refdataPath(dir, name string) (string, bool) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", false
+	}
+	return filepath.Join(dir, name), true
+}
+
+// respondImportError maps errors of reading import files to responses.
+func respondImportError(c *gin.Context, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Import file not found", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import file", "details": err.Error()})
+}
+
+// ImportAirports upserts the airports of an OurAirports airports.csv file
+// from the reference data directory. Airports are matched by IATA code.
+//
+// Request body:
+//   - file: Name of the airports.csv file
+//   - countries: Optional OurAirports countries.csv for country names
+//   - timezones: Optional OpenFlights airports.dat for time zones
+//   - dryRun: Only report the changes without writing them
+func ImportAirports(db database.Database, dir string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var req models.AirportImportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		var files refdata.AirportFiles
+		var ok bool
+		if files.Airports, ok = refdataPath(dir, req.File); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File must be a file name in the reference data directory"})
+			return
+		}
+		if req.Countries != "" {
+			if files.Countries, ok = refdataPath(dir, req.Countries); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Countries must be a file name in the reference data directory"})
+				return
+			}
+		}
+		if req.Timezones != "" {
+			if files.Timezones, ok = refdataPath(dir, req.Timezones); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Timezones must be a file name in the reference data directory"})
+				return
+			}
+		}
+
+		source, err := refdata.ReadAirportFiles(files)
+		if err != nil {
+			respondImportError(c, err)
+			return
+		}
+
+		report, err := refdata.NewImporter(db).ImportAirports(source, req.DryRun)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import airports", "details": err.Error(), "data": report})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    report,
+			"message": "Airports imported successfully",
+		})
+	}
+}
+
+// ImportAirlines upserts the airlines of an OpenFlights airlines.dat file
+// from the reference data directory. Airlines are matched by IATA code.
+//
+// Request body:
+//   - file: Name of the airlines.dat file
+//   - dryRun: Only report the changes without writing them
+func ImportAirlines(db database.Database, dir string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var req models.AirlineImportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		path, ok := refdataPath(dir, req.File)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File must be a file name in the reference data directory"})
+			return
+		}
+
+		source, err := refdata.ReadAirlineFile(path)
+		if err != nil {
+			respondImportError(c, err)
+			return
+		}
+
+		report, err := refdata.NewImporter(db).ImportAirlines(source, req.DryRun)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import airlines", "details": err.Error(), "data": report})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    report,
+			"message": "Airlines imported successfully",
+		})
+	}
+}
+
+// ImportSSIM creates and updates flights from an SSIM schedule file in the
+// reference data directory. Flights are matched by flight number, origin and
+// local date of departure; planes are assigned from the airline's fleet and
+// pilots are left empty. Flights that cannot be imported are listed as
+// conflicts in the report.
+//
+// Request body:
+//   - file: Name of the SSIM file
+//   - dryRun: Only report the changes without writing them
+func ImportSSIM(db database.Database, planner *planning.Planner, dir string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var req models.SSIMImportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		path, ok := refdataPath(dir, req.File)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File must be a file name in the reference data directory"})
+			return
+		}
+
+		carriers, err := schedule.ReadSSIMFile(path)
+		if err != nil {
+			respondImportError(c, err)
+			return
+		}
+
+		report, err := schedule.NewImporter(db, planner).ImportSSIM(carriers, req.DryRun)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import flights", "details": err.Error(), "data": report})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    report,
+			"message": "Flights imported successfully",
+		})
+	}
+}
+
+// ImportRoutes sets up the reference data and schedule import routes. The
+// files are read from the directory configured in REFDATA_DIR (default "data").
+func ImportRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	dir := os.Getenv("REFDATA_DIR")
+	if dir == "" {
+		dir = "data"
+	}
+
+	router.POST("/airports", ImportAirports(db, dir))
+	router.POST("/airlines", ImportAirlines(db, dir))
+	router.POST("/ssim", ImportSSIM(db, planner, dir))
+}
@@ -0,0 +1,545 @@
+// Package routers provides HTTP route handlers for invoices, credit notes,
+// billing addresses and excess baggage fees in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/invoices"
+	"mindenairport/middleware"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyInvoices returns the invoices and credit notes of the authenticated
+// user, newest first.
+func GetMyInvoices(invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		list, err := invoicing.ForUser(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    list,
+			"count":   len(list),
+			"message": "Invoices retrieved successfully",
+		})
+	}
+}
+
+// GetMyInvoice returns an invoice or credit note of the authenticated user.
+func GetMyInvoice(invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoice, ok := ownInvoice(c, invoicing)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    invoice,
+			"message": "Invoice retrieved successfully",
+		})
+	}
+}
+
+// DownloadMyInvoicePDF returns an invoice or credit note of the
+// authenticated user as PDF.
+func DownloadMyInvoicePDF(invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if invoice, ok := ownInvoice(c, invoicing); ok {
+			sendInvoicePDF(c, invoicing, invoice)
+		}
+	}
+}
+
+// DownloadMyInvoiceXML returns an invoice or credit note of the
+// authenticated user as electronic invoice.
+//
+// Query parameters:
+//   - profile: xrechnung (default) or zugferd
+func DownloadMyInvoiceXML(invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if invoice, ok := ownInvoice(c, invoicing); ok {
+			sendInvoiceXML(c, invoicing, invoice)
+		}
+	}
+}
+
+// GetBillingAddress returns the billing address of the authenticated user.
+// Users without one are invoiced by the name and email of their account.
+func GetBillingAddress(invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		address, err := invoicing.BillingAddress(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve billing address"})
+			return
+		}
+		if address == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No billing address set"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    address,
+			"message": "Billing address retrieved successfully",
+		})
+	}
+}
+
+// SaveBillingAddress sets the billing address of the authenticated user for
+// invoices issued from now on.
+//
+// Request body:
+//   - name: Name of the buyer (required)
+//   - company, street, postcode, city, country: Address; XRechnung needs postcode, city and country
+//   - vatId: VAT ID of a business buyer
+//   - buyerReference: Reference the buyer wants on invoices, e.g. a Leitweg-ID
+//   - email: Email of the buyer's accounting, defaults to the account's email
+func SaveBillingAddress(invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req models.BillingAddressRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := invoices.ValidateBillingAddress(&req); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		address, err := invoicing.SaveBillingAddress(userID.(string), req, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save billing address"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    address,
+			"message": "Billing address saved successfully",
+		})
+	}
+}
+
+// DeleteBillingAddress removes the billing address of the authenticated user.
+func DeleteBillingAddress(invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		if err := invoicing.DeleteBillingAddress(userID.(string)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete billing address"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Billing address deleted successfully"})
+	}
+}
+
+// SearchInvoices returns the invoices and credit notes matching the query
+// for admins, newest first.
+//
+// Query parameters:
+//   - number: Invoice number
+//   - locator: Booking locator
+//   - user: Account the invoices are addressed to
+//   - customer: Part of name, company or email of the buyer
+//   - type: INVOICE or CREDIT_NOTE
+//   - from, to: Days of issue in YYYY-MM-DD format, both inclusive
+func SearchInvoices(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := checkAdminRole(c, db); !ok {
+			return
+		}
+
+		filter := models.InvoiceFilter{
+			Number:        strings.ToUpper(strings.TrimSpace(c.Query("number"))),
+			Locator:       strings.ToUpper(strings.TrimSpace(c.Query("locator"))),
+			AirportUserID: c.Query("user"),
+			Customer:      strings.TrimSpace(c.Query("customer")),
+			Type:          strings.ToUpper(c.Query("type")),
+		}
+		if filter.Type != "" && filter.Type != invoices.TypeInvoice && filter.Type != invoices.TypeCreditNote {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "type must be INVOICE or CREDIT_NOTE"})
+			return
+		}
+		for _, param := range []string{"from", "to"} {
+			d := c.Query(param)
+			if d == "" {
+				continue
+			}
+			day, err := time.ParseInLocation("2006-01-02", d, invoicing.Location)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Date must be in YYYY-MM-DD format"})
+				return
+			}
+			if param == "from" {
+				filter.From = &day
+			} else {
+				end := day.AddDate(0, 0, 1)
+				filter.To = &end
+			}
+		}
+
+		list, err := invoicing.Search(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search invoices"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    list,
+			"count":   len(list),
+			"message": "Invoices retrieved successfully",
+		})
+	}
+}
+
+// GetInvoiceAdmin returns any invoice or credit note for admins.
+func GetInvoiceAdmin(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoice, ok := adminInvoice(c, db, invoicing)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    invoice,
+			"message": "Invoice retrieved successfully",
+		})
+	}
+}
+
+// DownloadInvoicePDFAdmin returns any invoice or credit note as PDF.
+func DownloadInvoicePDFAdmin(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if invoice, ok := adminInvoice(c, db, invoicing); ok {
+			sendInvoicePDF(c, invoicing, invoice)
+		}
+	}
+}
+
+// DownloadInvoiceXMLAdmin returns any invoice or credit note as electronic
+// invoice.
+//
+// Query parameters:
+//   - profile: xrechnung (default) or zugferd
+func DownloadInvoiceXMLAdmin(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if invoice, ok := adminInvoice(c, db, invoicing); ok {
+			sendInvoiceXML(c, invoicing, invoice)
+		}
+	}
+}
+
+// IssueCreditNote cancels the lines of an invoice not yet credited with a
+// credit note, e.g. to correct a wrong invoice. Invoices are numbered
+// without gaps and cannot be changed or deleted.
+//
+// Request body:
+//   - reason: Printed on the credit note (required)
+//   - reissue: Invoice the lines again to the current billing address of the customer
+//
+// Returns:
+//   - 201: Credit note issued, with the new invoice if reissued
+//   - 404: Invoice not found
+//   - 409: A credit note, or an invoice that is fully credited
+//   - 503: The legal details of the airport are not set up
+func IssueCreditNote(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, ok := checkAdminRole(c, db)
+		if !ok {
+			return
+		}
+
+		var req models.CreditNoteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		original, err := invoicing.Get(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoice"})
+			return
+		}
+		if original == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return
+		}
+
+		credit, reissued, err := invoicing.CreditNote(original, strings.TrimSpace(req.Reason), req.Reissue, admin.ID, time.Now())
+		switch {
+		case errors.Is(err, invoices.ErrNotCreditable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, invoices.ErrNoIssuer):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		case err != nil && credit == nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue credit note"})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Credit note issued, but the invoice could not be reissued", "data": credit})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":     credit,
+			"reissued": reissued,
+			"message":  "Credit note issued successfully",
+		})
+	}
+}
+
+// GetInvoiceIssuer returns the current legal details of the airport printed
+// on invoices.
+func GetInvoiceIssuer(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := checkAdminRole(c, db); !ok {
+			return
+		}
+
+		issuer, err := invoicing.Issuer(time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoice issuer"})
+			return
+		}
+		if issuer == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": invoices.ErrNoIssuer.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    issuer,
+			"message": "Invoice issuer retrieved successfully",
+		})
+	}
+}
+
+// SetInvoiceIssuer changes the legal details of the airport. Invoices issued
+// before validFrom keep the details they were issued with.
+//
+// Request body:
+//   - name, street, postcode, city, country: Address of the airport (required)
+//   - vatId, taxNumber: At least one is required
+//   - registerCourt, registerNumber, managingDirectors: Commercial register entry
+//   - contactName, email, phone: Contact for invoice questions (required)
+//   - iban, bic, bankName: Bank account
+//   - validFrom: Defaults to now
+func SetInvoiceIssuer(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, ok := checkAdminRole(c, db)
+		if !ok {
+			return
+		}
+
+		var req models.InvoiceIssuerRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := invoices.ValidateIssuer(&req); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		issuer, err := invoicing.SetIssuer(req, admin.ID, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update invoice issuer"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    issuer,
+			"message": "Invoice issuer updated successfully",
+		})
+	}
+}
+
+// ChargeExcessBaggage records the excess baggage fee a passenger paid at the
+// counter for an overweight or oversized bag and issues its invoice.
+//
+// Request body:
+//   - amount: Defaults to the configured fee
+//   - paymentMethod: CARD or CASH
+//   - cardLast4: Last four digits of the card for CARD payments
+//
+// Returns:
+//   - 201: Fee recorded; invoice is null if it is issued later by the periodic check
+//   - 404: Bag not found
+//   - 409: The bag is within the allowance, or its fee was already charged
+func ChargeExcessBaggage(db database.Database, invoicing *invoices.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, ok := checkAdminRole(c, db)
+		if !ok {
+			return
+		}
+
+		var req models.BaggageFeeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := invoices.ValidateBaggageFee(&req); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		bag, err := db.GetBaggageByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve baggage"})
+			return
+		}
+		if bag == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Baggage not found"})
+			return
+		}
+
+		fee, invoice, err := invoicing.ChargeExcessBaggage(bag, req, admin.ID, time.Now())
+		switch {
+		case errors.Is(err, invoices.ErrWithinAllowance), errors.Is(err, invoices.ErrFeeCharged):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to charge excess baggage fee"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    fee,
+			"invoice": invoice,
+			"message": "Excess baggage fee charged successfully",
+		})
+	}
+}
+
+// ownInvoice loads the invoice of the id parameter if it is addressed to
+// the authenticated user. Otherwise it writes the error response and
+// returns false.
+func ownInvoice(c *gin.Context, invoicing *invoices.Service) (*models.Invoice, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	invoice, err := invoicing.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoice"})
+		return nil, false
+	}
+	if invoice == nil || invoice.AirportUserID != userID.(string) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return nil, false
+	}
+	return invoice, true
+}
+
+// adminInvoice loads the invoice of the id parameter for admins. Otherwise
+// it writes the error response and returns false.
+func adminInvoice(c *gin.Context, db database.Database, invoicing *invoices.Service) (*models.Invoice, bool) {
+	if _, ok := checkAdminRole(c, db); !ok {
+		return nil, false
+	}
+
+	invoice, err := invoicing.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoice"})
+		return nil, false
+	}
+	if invoice == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return nil, false
+	}
+	return invoice, true
+}
+
+// sendInvoicePDF writes an invoice as PDF attachment.
+func sendInvoicePDF(c *gin.Context, invoicing *invoices.Service, invoice *models.Invoice) {
+	pdf, err := invoicing.PDF(invoice)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate invoice PDF"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+invoice.Number+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// sendInvoiceXML writes an invoice as XML attachment in the profile of the
+// query.
+func sendInvoiceXML(c *gin.Context, invoicing *invoices.Service, invoice *models.Invoice) {
+	profile := strings.ToLower(c.DefaultQuery("profile", invoices.ProfileXRechnung))
+	doc, err := invoicing.XML(invoice, profile)
+	switch {
+	case errors.Is(err, invoices.ErrUnknownProfile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, invoices.ErrIncompleteAddress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate invoice XML"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+invoice.Number+`-`+profile+`.xml"`)
+	c.Data(http.StatusOK, "application/xml", doc)
+}
+
+// InvoiceRoutes registers the invoices of the authenticated user.
+func InvoiceRoutes(router *gin.RouterGroup, invoicing *invoices.Service) {
+	router.GET("", GetMyInvoices(invoicing))                // Invoices and credit notes, newest first
+	router.GET("/:id", GetMyInvoice(invoicing))             // Invoice with lines and VAT breakdown
+	router.GET("/:id/pdf", DownloadMyInvoicePDF(invoicing)) // Invoice as PDF
+	router.GET("/:id/xml", DownloadMyInvoiceXML(invoicing)) // Invoice as XRechnung or ZUGFeRD XML
+}
+
+// BillingAddressRoutes registers the billing address of the authenticated
+// user.
+func BillingAddressRoutes(router *gin.RouterGroup, invoicing *invoices.Service) {
+	router.GET("", GetBillingAddress(invoicing))
+	router.PUT("", SaveBillingAddress(invoicing))
+	router.DELETE("", DeleteBillingAddress(invoicing))
+}
+
+// InvoiceAdminRoutes registers the invoice search, credit notes, the legal
+// details of the airport and excess baggage fees for admins.
+func InvoiceAdminRoutes(router *gin.RouterGroup, db database.Database, invoicing *invoices.Service) {
+	router.GET("/invoices", SearchInvoices(db, invoicing))
+	router.GET("/invoices/:id", GetInvoiceAdmin(db, invoicing))
+	router.GET("/invoices/:id/pdf", DownloadInvoicePDFAdmin(db, invoicing))
+	router.GET("/invoices/:id/xml", DownloadInvoiceXMLAdmin(db, invoicing))
+	router.POST("/invoices/:id/credit-note", middleware.Idempotency(db), IssueCreditNote(db, invoicing))
+	router.GET("/invoice-issuer", GetInvoiceIssuer(db, invoicing))
+	router.PUT("/invoice-issuer", SetInvoiceIssuer(db, invoicing))
+	router.POST("/baggage/:id/excess-fee", middleware.Idempotency(db), ChargeExcessBaggage(db, invoicing))
+}
@@ -0,0 +1,515 @@
+// Package routers provides HTTP route handlers for retail lease contracts,
+// concession invoices and the concession revenue report in the
+// MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"mindenairport/billing"
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validateLease normalizes the lease attributes and checks them against the
+// values allowed by the LEASE table. It returns an error message or "".
+func validateLease(lease *models.Lease) string {
+	lease.ShopID = strings.TrimSpace(lease.ShopID)
+	lease.PlotID = strings.TrimSpace(lease.PlotID)
+
+	// Leases run on whole days
+	y, m, d := lease.StartDate.Date()
+	lease.StartDate = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	if lease.EndDate != nil {
+		y, m, d := lease.EndDate.Date()
+		end := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		lease.EndDate = &end
+	}
+
+	switch {
+	case lease.ShopID == "":
+		return "Shop ID is required"
+	case lease.StartDate.Year() < 1900:
+		return "Start date is required"
+	case lease.EndDate != nil && lease.EndDate.Before(lease.StartDate):
+		return "End date must not be before the start date"
+	case lease.RentPerSqFt < 0:
+		return "Rent per sq ft must not be negative"
+	case lease.TurnoverPercent < 0 || lease.TurnoverPercent > 100:
+		return "Turnover percent must be between 0 and 100"
+	}
+	return ""
+}
+
+// leasesOverlap reports whether two lease contracts run at the same time.
+func leasesOverlap(a, b models.Lease) bool {
+	aEndsBeforeB := a.EndDate != nil && a.EndDate.Before(b.StartDate)
+	bEndsBeforeA := b.EndDate != nil && b.EndDate.Before(a.StartDate)
+	return !aEndsBeforeB && !bEndsBeforeA
+}
+
+// prepareLease completes a validated lease and checks it against the shop,
+// the plot and the other contracts. The plot defaults to the plot the shop
+// occupies. It writes an error response and returns false if the lease
+// cannot be stored.
+func prepareLease(c *gin.Context, db database.Database, lease *models.Lease) bool {
+	shop, err := db.GetShopByID(lease.ShopID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shop"})
+		return false
+	}
+	if shop == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Shop does not exist"})
+		return false
+	}
+	if lease.PlotID == "" {
+		lease.PlotID = shop.PlotID
+	}
+
+	plot, err := db.GetPlotByID(lease.PlotID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot"})
+		return false
+	}
+	if plot == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Plot does not exist"})
+		return false
+	}
+	if plot.AreaSqFt <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Plot has no area to calculate the rent from"})
+		return false
+	}
+
+	// A shop has one contract at a time, and a plot is leased to one shop at a time
+	others, err := db.GetLeases("")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve leases"})
+		return false
+	}
+	conflicts := []string{}
+	for _, other := range others {
+		if other.ID == lease.ID || (other.ShopID != lease.ShopID && other.PlotID != lease.PlotID) {
+			continue
+		}
+		if leasesOverlap(*lease, other) {
+			conflicts = append(conflicts, other.ID)
+		}
+	}
+	if len(conflicts) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":             "The shop or plot is already leased for this period",
+			"conflictingLeases": conflicts,
+		})
+		return false
+	}
+
+	lease.ShopName = shop.Name
+	lease.AreaSqFt = plot.AreaSqFt
+	return true
+}
+
+// monthQuery parses a YYYY-MM query parameter into the first day of the
+// month, using def if the parameter is missing. It writes a 400 response and
+// returns false as second value if the parameter is invalid.
+func monthQuery(c *gin.Context, name string, def time.Time) (time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return billing.MonthStart(def), true
+	}
+	month, err := billing.ParseMonth(value, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be in YYYY-MM format"})
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// GetLeases returns all lease contracts.
+//
+// Query parameters:
+//   - shop: Only contracts of this shop
+func GetLeases(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		leases, err := db.GetLeases(c.Query("shop"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve leases"})
+			return
+		}
+		if leases == nil {
+			leases = []models.Lease{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    leases,
+			"message": "Leases retrieved successfully",
+		})
+	}
+}
+
+// GetLeaseByID returns a specific lease contract
+func GetLeaseByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		lease, err := db.GetLeaseByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lease"})
+			return
+		}
+		if lease == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Lease not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    lease,
+			"message": "Lease retrieved successfully",
+		})
+	}
+}
+
+// CreateLease adds a lease contract for a shop.
+//
+// Request body should contain:
+//   - shopId: Shop the contract is made with
+//   - plotId: Leased plot (defaults to the plot the shop occupies)
+//   - startDate, endDate: First and last day of the contract; endDate is optional
+//   - rentPerSqFt: Fixed monthly rent per square foot of the plot in USD
+//   - turnoverPercent: Share of the shop's monthly turnover in percent
+func CreateLease(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var lease models.Lease
+		if err := c.ShouldBindJSON(&lease); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateLease(&lease); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// IDs are generated by the database layer
+		lease.ID = ""
+		if !prepareLease(c, db, &lease) {
+			return
+		}
+
+		if err := db.CreateLease(&lease); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create lease"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    lease,
+			"message": "Lease created successfully",
+		})
+	}
+}
+
+// UpdateLease replaces the terms of an existing lease contract. Invoices
+// already issued are not changed.
+func UpdateLease(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var lease models.Lease
+		if err := c.ShouldBindJSON(&lease); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		lease.ID = c.Param("id")
+
+		if msg := validateLease(&lease); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetLeaseByID(lease.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lease"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Lease not found"})
+			return
+		}
+
+		if !prepareLease(c, db, &lease) {
+			return
+		}
+
+		if err := db.UpdateLease(lease); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update lease"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    lease,
+			"message": "Lease updated successfully",
+		})
+	}
+}
+
+// DeleteLease removes a lease contract. Contracts that were already invoiced
+// cannot be deleted; they are ended by setting an end date instead.
+func DeleteLease(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		lease, err := db.GetLeaseByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lease"})
+			return
+		}
+		if lease == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Lease not found"})
+			return
+		}
+
+		if err := db.DeleteLease(lease.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Lease has already been invoiced"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete lease"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Lease deleted successfully",
+		})
+	}
+}
+
+// ReportLeaseTurnover records the turnover a shop reported for a month.
+// Invoices generated afterwards for that month include the turnover rent.
+//
+// Request body should contain:
+//   - amount: Turnover of the month in USD
+func ReportLeaseTurnover(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var turnover models.LeaseTurnover
+		if err := c.ShouldBindJSON(&turnover); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		month, err := billing.ParseMonth(c.Param("month"), time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Month must be in YYYY-MM format"})
+			return
+		}
+		if month.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Turnover cannot be reported for future months"})
+			return
+		}
+
+		lease, err := db.GetLeaseByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lease"})
+			return
+		}
+		if lease == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Lease not found"})
+			return
+		}
+		if billing.ActiveDays(*lease, month) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Lease does not cover this month"})
+			return
+		}
+
+		if err := db.SetLeaseTurnover(lease.ID, month, turnover.Amount); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record turnover"})
+			return
+		}
+
+		turnover.LeaseID = lease.ID
+		turnover.Month = month.Format(billing.MonthLayout)
+		c.JSON(http.StatusOK, gin.H{
+			"data":    turnover,
+			"message": "Turnover recorded successfully",
+		})
+	}
+}
+
+// GetLeaseInvoices returns the concession invoices of a month.
+//
+// Query parameters:
+//   - month: Billing month in YYYY-MM format (defaults to the previous month)
+func GetLeaseInvoices(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		month, ok := monthQuery(c, "month", billing.MonthStart(time.Now()).AddDate(0, -1, 0))
+		if !ok {
+			return
+		}
+
+		invoices, err := db.GetLeaseInvoices(month, month.AddDate(0, 1, 0))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+			return
+		}
+		if invoices == nil {
+			invoices = []models.LeaseInvoice{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    invoices,
+			"message": "Invoices retrieved successfully",
+		})
+	}
+}
+
+// GenerateLeaseInvoices issues the concession invoices of a month for all
+// leases covering it. Running it again replaces the invoices of that month,
+// e.g. to include turnover reported late.
+//
+// Query parameters:
+//   - month: Billing month in YYYY-MM format (defaults to the previous month)
+func GenerateLeaseInvoices(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		month, ok := monthQuery(c, "month", billing.MonthStart(time.Now()).AddDate(0, -1, 0))
+		if !ok {
+			return
+		}
+		if month.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoices cannot be generated for future months"})
+			return
+		}
+		next := month.AddDate(0, 1, 0)
+
+		leases, err := db.GetLeasesInPeriod(month, next)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve leases"})
+			return
+		}
+		turnovers, err := db.GetLeaseTurnovers(month)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve turnover reports"})
+			return
+		}
+
+		for _, lease := range leases {
+			var turnover *float64
+			if amount, ok := turnovers[lease.ID]; ok {
+				turnover = &amount
+			}
+
+			invoice, ok := billing.LeaseInvoice(lease, month, turnover)
+			if !ok {
+				continue
+			}
+			if err := db.SaveLeaseInvoice(&invoice, month); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save invoice"})
+				return
+			}
+		}
+
+		invoices, err := db.GetLeaseInvoices(month, next)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+			return
+		}
+		if invoices == nil {
+			invoices = []models.LeaseInvoice{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    invoices,
+			"message": "Invoices generated successfully",
+		})
+	}
+}
+
+// GetConcessionRevenue returns the concession revenue invoiced per month and per shop.
+//
+// Query parameters:
+//   - from: First month in YYYY-MM format (defaults to January of the current year)
+//   - to: Last month in YYYY-MM format (defaults to the current month)
+func GetConcessionRevenue(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		now := time.Now()
+		from, ok := monthQuery(c, "from", time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.Local))
+		if !ok {
+			return
+		}
+		to, ok := monthQuery(c, "to", now)
+		if !ok {
+			return
+		}
+		if to.Before(from) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+			return
+		}
+
+		invoices, err := db.GetLeaseInvoices(from, to.AddDate(0, 1, 0))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+			return
+		}
+
+		report := billing.ConcessionRevenue(from.Format(billing.MonthLayout), to.Format(billing.MonthLayout), invoices)
+		c.JSON(http.StatusOK, gin.H{
+			"data":    report,
+			"message": "Concession revenue retrieved successfully",
+		})
+	}
+}
+
+// LeaseRoutes sets up lease contract and concession billing routes
+func LeaseRoutes(router *gin.RouterGroup, db database.Database) {
+	router.GET("", GetLeases(db))
+	router.POST("", CreateLease(db))
+	router.GET("/invoices", GetLeaseInvoices(db))
+	router.POST("/invoices", GenerateLeaseInvoices(db))
+	router.GET("/revenue", GetConcessionRevenue(db))
+	router.GET("/:id", GetLeaseByID(db))
+	router.PUT("/:id", UpdateLease(db))
+	router.DELETE("/:id", DeleteLease(db))
+	router.PUT("/:id/turnover/:month", ReportLeaseTurnover(db))
+}
@@ -0,0 +1,189 @@
+// Package routers provides HTTP route handlers for the aircraft maintenance
+// log and maintenance due-date alerts in the MindenAirport API.
+package routers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/jobs"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMaintenanceLogs returns all maintenance records
+func GetMaintenanceLogs(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		logs, err := db.GetMaintenanceLogs()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve maintenance logs"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    logs,
+			"message": "Maintenance logs retrieved successfully",
+		})
+	}
+}
+
+// GetMaintenanceLogByID returns a specific maintenance record
+func GetMaintenanceLogByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		maintenanceLog, err := db.GetMaintenanceLogById(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve maintenance log"})
+			return
+		}
+
+		if maintenanceLog == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance log not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    maintenanceLog,
+			"message": "Maintenance log retrieved successfully",
+		})
+	}
+}
+
+// GetPlaneMaintenanceHistory returns the maintenance history of a plane
+func GetPlaneMaintenanceHistory(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		plane, err := db.GetPlaneByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plane"})
+			return
+		}
+		if plane == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plane not found"})
+			return
+		}
+
+		logs, err := db.GetMaintenanceLogsByPlane(plane.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve maintenance logs"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data": gin.H{
+				"plane": plane,
+				"logs":  logs,
+			},
+			"message": "Maintenance history retrieved successfully",
+		})
+	}
+}
+
+// CreateMaintenanceLog records a maintenance activity on a plane.
+// The due-date check runs again afterwards so alerts reflect the new deadline.
+//
+// Request body should contain:
+//   - planeId: ID of the maintained plane
+//   - maintenanceDate: Date of the maintenance (defaults to now)
+//   - description: Work performed
+//   - technician: Name or ID of the technician
+//   - nextMaintenance: Deadline of the next maintenance
+func CreateMaintenanceLog(db database.Database, monitor *jobs.MaintenanceMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var maintenanceLog models.MaintenanceLog
+		if err := c.ShouldBindJSON(&maintenanceLog); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		maintenanceLog.Description = strings.TrimSpace(maintenanceLog.Description)
+		maintenanceLog.Technician = strings.TrimSpace(maintenanceLog.Technician)
+		if maintenanceLog.Description == "" || maintenanceLog.Technician == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Description and technician are required"})
+			return
+		}
+
+		if maintenanceLog.MaintenanceDate.IsZero() {
+			maintenanceLog.MaintenanceDate = time.Now()
+		}
+		if maintenanceLog.NextMaintenance != nil && !maintenanceLog.NextMaintenance.After(maintenanceLog.MaintenanceDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Next maintenance must be after the maintenance date"})
+			return
+		}
+
+		plane, err := db.GetPlaneByID(maintenanceLog.PlaneID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plane"})
+			return
+		}
+		if plane == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plane not found"})
+			return
+		}
+
+		// IDs are generated by the database layer
+		maintenanceLog.ID = ""
+		if err := db.CreateMaintenanceLog(&maintenanceLog); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create maintenance log"})
+			return
+		}
+
+		go func() {
+			if _, err := monitor.Check(time.Now()); err != nil {
+				log.Printf("Error checking maintenance deadlines: %v", err)
+			}
+		}()
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    maintenanceLog,
+			"message": "Maintenance log created successfully",
+		})
+	}
+}
+
+// GetMaintenanceAlerts returns the planes whose maintenance is due or overdue,
+// as found by the latest due-date check, with the flights they would affect.
+func GetMaintenanceAlerts(db database.Database, monitor *jobs.MaintenanceMonitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    monitor.Report(),
+			"message": "Maintenance alerts retrieved successfully",
+		})
+	}
+}
+
+// MaintenanceRoutes sets up maintenance log routes
+func MaintenanceRoutes(router *gin.RouterGroup, db database.Database, monitor *jobs.MaintenanceMonitor) {
+	router.GET("", GetMaintenanceLogs(db))
+	router.POST("", CreateMaintenanceLog(db, monitor))
+	router.GET("/alerts", GetMaintenanceAlerts(db, monitor))
+	router.GET("/planes/:id", GetPlaneMaintenanceHistory(db))
+	router.GET("/:id", GetMaintenanceLogByID(db))
+}
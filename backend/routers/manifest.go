@@ -0,0 +1,175 @@
+// Package routers provides HTTP route handlers for passenger manifests and
+// PNL/ADL messages in the MindenAirport API.
+package routers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/manifest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFlightManifest returns the passengers of a flight with seat, class,
+// check-in state and checked bags.
+//
+// Query parameters:
+//   - format: "json" (default) or "csv"
+//
+// Returns:
+//   - 200: Manifest as JSON or CSV download
+//   - 400: Unknown format
+//   - 404: Flight not found
+func GetFlightManifest(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be either 'json' or 'csv'"})
+			return
+		}
+
+		m, err := manifest.Get(db, c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve manifest"})
+			return
+		}
+		if m == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		if format == "csv" {
+			var buf bytes.Buffer
+			if err := manifest.WriteCSV(&buf, m); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write manifest"})
+				return
+			}
+			name := m.FlightNumber
+			if name == "" {
+				name = m.FlightID
+			}
+			c.Header("Content-Disposition", `attachment; filename="manifest-`+name+`.csv"`)
+			c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    m,
+			"message": "Manifest retrieved successfully",
+		})
+	}
+}
+
+// GetPassengerListMessages returns the PNL and ADL messages sent for a
+// flight, oldest first.
+func GetPassengerListMessages(db database.Database, messenger *manifest.Messenger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		messages, err := messenger.Messages(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve passenger list messages"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    messages,
+			"count":   len(messages),
+			"message": "Passenger list messages retrieved successfully",
+		})
+	}
+}
+
+// SendPNL generates the Passenger Name List of a flight and records it as
+// sent to ground handling.
+//
+// Returns:
+//   - 201: PNL generated, the response contains its text
+//   - 404: Flight not found
+func SendPNL(db database.Database, messenger *manifest.Messenger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		m, err := manifest.Get(db, c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve manifest"})
+			return
+		}
+		if m == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		message, err := messenger.SendPNL(m, time.Now().UTC())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create PNL"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    message,
+			"message": "PNL created successfully",
+		})
+	}
+}
+
+// SendADL generates the Additions and Deletions List of a flight with the
+// changes since the last PNL or ADL and records it as sent.
+//
+// Returns:
+//   - 200: Passenger list unchanged, no ADL sent
+//   - 201: ADL generated, the response contains its text
+//   - 404: Flight not found
+//   - 409: No PNL has been sent for the flight yet
+func SendADL(db database.Database, messenger *manifest.Messenger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		m, err := manifest.Get(db, c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve manifest"})
+			return
+		}
+		if m == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		message, err := messenger.SendADL(m, time.Now().UTC())
+		if errors.Is(err, manifest.ErrNoPNL) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Send a PNL for this flight first"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ADL"})
+			return
+		}
+		if message == nil {
+			c.JSON(http.StatusOK, gin.H{"message": "Passenger list unchanged since the last message"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    message,
+			"message": "ADL created successfully",
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package routers provides HTTP route handlers for the notification settings
+// of authenticated users in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/notifications"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotificationPreference returns the notification settings of the authenticated user.
+// Users who never saved settings receive the defaults (e-mail in the default language).
+func GetNotificationPreference(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		pref, err := db.GetNotificationPreference(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve notification preferences"})
+			return
+		}
+
+		if pref == nil {
+			pref = &models.NotificationPreference{
+				AirportUserID: userID.(string),
+				Language:      notifications.DefaultLanguage,
+				Email:         true,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    pref,
+			"message": "Notification preferences retrieved successfully",
+		})
+	}
+}
+
+// UpdateNotificationPreference stores the notification settings of the authenticated user.
+//
+// Request body should contain:
+//   - language: "de" or "en"
+//   - email, sms, push: Channels the user wants to be notified on
+//   - pushEndpoint: Web push subscription endpoint (required if push is enabled)
+//
+// Returns:
+//   - 200: Preferences saved
+//   - 400: Invalid request data
+//   - 500: Internal server error
+func UpdateNotificationPreference(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var pref models.NotificationPreference
+		if err := c.ShouldBindJSON(&pref); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if pref.Language == "" {
+			pref.Language = notifications.DefaultLanguage
+		}
+		if pref.Language != "de" && pref.Language != "en" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Language must be either 'de' or 'en'"})
+			return
+		}
+
+		if pref.Push && pref.PushEndpoint == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Push endpoint is required for push notifications"})
+			return
+		}
+
+		// Users can only change their own preferences
+		pref.AirportUserID = userID.(string)
+
+		if err := db.SaveNotificationPreference(pref); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save notification preferences"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    pref,
+			"message": "Notification preferences updated successfully",
+		})
+	}
+}
+
+// NotificationRoutes sets up notification preference routes
+func NotificationRoutes(router *gin.RouterGroup, db database.Database) {
+	router.GET("/preferences", GetNotificationPreference(db))
+	router.PUT("/preferences", UpdateNotificationPreference(db))
+}
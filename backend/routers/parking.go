@@ -0,0 +1,223 @@
+// Package routers provides HTTP route handlers for car park management
+// in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strings"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validateParking normalizes the car park attributes and checks them against
+// the values allowed by the PARKING table. It returns an error message or "".
+func validateParking(parking *models.Parking) string {
+	parking.Name = strings.TrimSpace(parking.Name)
+	parking.PlotID = strings.TrimSpace(parking.PlotID)
+	parking.Status = strings.ToUpper(parking.Status)
+
+	if parking.Status == "" {
+		parking.Status = "ACTIVE"
+	}
+
+	switch {
+	case parking.Name == "":
+		return "Name is required"
+	case parking.PlotID == "":
+		return "Plot ID is required"
+	case parking.Spaces < 0:
+		return "Spaces must not be negative"
+	case parking.Status != "ACTIVE" && parking.Status != "CLOSED":
+		return "Status must be either 'ACTIVE' or 'CLOSED'"
+	}
+	return ""
+}
+
+// GetParkings returns all car parks
+func GetParkings(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		parkings, err := db.GetParkings()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve car parks"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    parkings,
+			"message": "Car parks retrieved successfully",
+		})
+	}
+}
+
+// GetParkingByID returns a specific car park
+func GetParkingByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		parking, err := db.GetParkingByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve car park"})
+			return
+		}
+		if parking == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Car park not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    parking,
+			"message": "Car park retrieved successfully",
+		})
+	}
+}
+
+// CreateParking adds a new car park on a free parking plot.
+//
+// Request body should contain:
+//   - name: Display name
+//   - plotId: Plot the car park is built on
+//   - spaces: Number of parking spaces
+//   - status: ACTIVE (default) or CLOSED
+func CreateParking(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var parking models.Parking
+		if err := c.ShouldBindJSON(&parking); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateParking(&parking); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// IDs are generated by the database layer
+		parking.ID = ""
+		occupant := models.PlotOccupant{Kind: models.OccupantParking, Name: parking.Name, PlotID: parking.PlotID}
+		if err := planner.ValidatePlacement(occupant, 0); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.CreateParking(&parking); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create car park"})
+			return
+		}
+		refreshPlotStatus(db, parking.PlotID)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    parking,
+			"message": "Car park created successfully",
+		})
+	}
+}
+
+// UpdateParking replaces the attributes of an existing car park. Moving it
+// to another plot requires that plot to be free.
+func UpdateParking(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var parking models.Parking
+		if err := c.ShouldBindJSON(&parking); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		parking.ID = c.Param("id")
+
+		if msg := validateParking(&parking); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetParkingByID(parking.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve car park"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Car park not found"})
+			return
+		}
+
+		if parking.PlotID != existing.PlotID {
+			occupant := models.PlotOccupant{Kind: models.OccupantParking, ID: parking.ID, Name: parking.Name, PlotID: parking.PlotID}
+			if err := planner.ValidatePlacement(occupant, 0); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+
+		if err := db.UpdateParking(parking); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update car park"})
+			return
+		}
+		refreshPlotStatus(db, existing.PlotID, parking.PlotID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    parking,
+			"message": "Car park updated successfully",
+		})
+	}
+}
+
+// DeleteParking removes a car park and frees its plot
+func DeleteParking(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		parking, err := db.GetParkingByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve car park"})
+			return
+		}
+		if parking == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Car park not found"})
+			return
+		}
+
+		if err := db.DeleteParking(parking.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete car park"})
+			return
+		}
+		refreshPlotStatus(db, parking.PlotID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Car park deleted successfully",
+		})
+	}
+}
+
+// ParkingRoutes sets up car park management routes
+func ParkingRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	router.GET("", GetParkings(db))
+	router.POST("", CreateParking(db, planner))
+	router.GET("/:id", GetParkingByID(db))
+	router.PUT("/:id", UpdateParking(db, planner))
+	router.DELETE("/:id", DeleteParking(db))
+}
@@ -0,0 +1,344 @@
+// Package routers provides HTTP route handlers for fares, booking payments
+// and refunds in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"mindenairport/booking"
+	"mindenairport/database"
+	"mindenairport/middleware"
+	"mindenairport/models"
+	"mindenairport/payments"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFlightFares returns the fares of a flight by travel class.
+func GetFlightFares(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		flight, err := db.GetFlightByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flight"})
+			return
+		}
+		if flight.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		fares, err := bookings.Fares(flight.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve fares"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    fares,
+			"count":   len(fares),
+			"message": "Fares retrieved successfully",
+		})
+	}
+}
+
+// SetFlightFares replaces the fares of a flight. Travel classes left out
+// can no longer be booked; tickets already booked keep their price.
+//
+// Request body:
+//   - fares: List of {travelClass, price} in EUR
+func SetFlightFares(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := checkAdminRole(c, db); !ok {
+			return
+		}
+
+		var req models.FaresRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := booking.ValidateFares(req); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		flight, err := db.GetFlightByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flight"})
+			return
+		}
+		if flight.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		if err := db.SetFlightFares(flight.ID, req.Fares); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update fares"})
+			return
+		}
+		fares, err := bookings.Fares(flight.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve fares"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    fares,
+			"count":   len(fares),
+			"message": "Fares updated successfully",
+		})
+	}
+}
+
+// PayBooking charges the held tickets of a booking to a card. Clients
+// should send an Idempotency-Key header, so a retried request does not
+// charge the card twice.
+//
+// Request body:
+//   - card: {number, expiryMonth, expiryYear, cvc, holder}
+//
+// Returns:
+//   - 201: Payment captured, the booking is confirmed
+//   - 202: Payment pending with the provider, the booking is confirmed once it approves
+//   - 400: Invalid card details
+//   - 402: Payment declined, the card can be changed and the payment retried
+//   - 404: Booking not found
+//   - 409: Booking is not held, its hold expired, it is paid or a payment is in progress
+//   - 502: The payment provider failed
+func PayBooking(db database.Database, bookings *booking.Service, charges *payments.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.PaymentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		b, ok := accessibleBooking(c, bookings)
+		if !ok {
+			return
+		}
+		userID, _ := c.Get("userID")
+
+		payment, err := charges.Pay(userID.(string), b, req.Card, time.Now())
+		switch {
+		case errors.Is(err, payments.ErrInvalidCard):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, payments.ErrNotPayable), errors.Is(err, payments.ErrPaymentInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, payments.ErrDeclined):
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "data": payment})
+			return
+		case errors.Is(err, payments.ErrProvider):
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Payment provider is not available, please try again"})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process payment"})
+			return
+		}
+
+		if payment.Status != "CAPTURED" {
+			c.JSON(http.StatusAccepted, gin.H{
+				"data":    payment,
+				"message": "Payment is pending, the booking is confirmed once the provider approves it",
+			})
+			return
+		}
+
+		localizeBooking(db, b)
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    payment,
+			"booking": b,
+			"message": "Payment completed successfully",
+		})
+	}
+}
+
+// GetBookingPayments returns the payments of a booking of the authenticated
+// user with their tickets and refunds.
+func GetBookingPayments(bookings *booking.Service, charges *payments.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b, ok := accessibleBooking(c, bookings)
+		if !ok {
+			return
+		}
+
+		list, err := charges.ForBooking(b.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    list,
+			"count":   len(list),
+			"message": "Payments retrieved successfully",
+		})
+	}
+}
+
+// HandlePaymentWebhook receives the notifications of the payment provider
+// about payments and refunds that completed later. The provider signs each
+// call; repeated events are acknowledged without being applied again.
+// Calls for any other than the configured provider return 404.
+func HandlePaymentWebhook(charges *payments.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+
+		err = charges.HandleWebhook(c.Param("provider"), c.Request.Header, body, time.Now())
+		switch {
+		case errors.Is(err, payments.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment provider not found"})
+			return
+		case errors.Is(err, payments.ErrInvalidWebhook):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			// The provider retries the event
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Webhook processed successfully"})
+	}
+}
+
+// GetPaymentAdmin returns a payment with its tickets and refunds for admins.
+func GetPaymentAdmin(db database.Database, charges *payments.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := checkAdminRole(c, db); !ok {
+			return
+		}
+
+		payment, err := charges.Get(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payment"})
+			return
+		}
+		if payment == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    payment,
+			"message": "Payment retrieved successfully",
+		})
+	}
+}
+
+// GetBookingPaymentsAdmin returns the payments of any booking for admins.
+func GetBookingPaymentsAdmin(db database.Database, bookings *booking.Service, charges *payments.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := checkAdminRole(c, db); !ok {
+			return
+		}
+
+		b, err := bookings.Get(booking.NormalizeLocator(c.Param("locator")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking"})
+			return
+		}
+		if b == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+
+		list, err := charges.ForBooking(b.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    list,
+			"count":   len(list),
+			"message": "Payments retrieved successfully",
+		})
+	}
+}
+
+// RefundPayment refunds tickets of a captured payment, e.g. as a goodwill
+// gesture. Tickets of cancelled bookings are refunded automatically.
+//
+// Request body may contain:
+//   - ticketIds: Tickets to refund; all tickets not yet refunded if empty
+//   - reason: Shown in the payment history
+//
+// Returns:
+//   - 201: Refund issued, or pending with the provider
+//   - 404: Payment not found
+//   - 409: Payment not captured, or a ticket not part of it or already refunded
+//   - 502: The payment provider failed or rejected the refund
+func RefundPayment(db database.Database, charges *payments.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, ok := checkAdminRole(c, db)
+		if !ok {
+			return
+		}
+
+		var req models.RefundRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+				return
+			}
+		}
+
+		payment, err := charges.Get(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payment"})
+			return
+		}
+		if payment == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+
+		refund, err := charges.Refund(payment, req.TicketIDs, req.Reason, admin.ID, time.Now())
+		switch {
+		case errors.Is(err, payments.ErrNotRefundable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, payments.ErrProvider), errors.Is(err, payments.ErrDeclined):
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "data": refund})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refund payment"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    refund,
+			"message": "Refund issued successfully",
+		})
+	}
+}
+
+// FareRoutes registers the public fares of flights.
+func FareRoutes(router *gin.RouterGroup, db database.Database, bookings *booking.Service) {
+	router.GET("/:id/fares", GetFlightFares(db, bookings))
+}
+
+// PaymentRoutes registers the payments of the bookings of the authenticated
+// user. Payments accept an Idempotency-Key header.
+func PaymentRoutes(router *gin.RouterGroup, db database.Database, bookings *booking.Service, charges *payments.Service) {
+	router.GET("/:locator/payments", GetBookingPayments(bookings, charges))                          // Payments with tickets and refunds
+	router.POST("/:locator/payments", middleware.Idempotency(db), PayBooking(db, bookings, charges)) // Pay the held tickets by card
+}
+
+// PaymentAdminRoutes registers fares, payments and refunds for admins.
+func PaymentAdminRoutes(router *gin.RouterGroup, db database.Database, bookings *booking.Service, charges *payments.Service) {
+	router.PUT("/flights/:id/fares", SetFlightFares(db, bookings))
+	router.GET("/payments/:id", GetPaymentAdmin(db, charges))
+	router.POST("/payments/:id/refund", middleware.Idempotency(db), RefundPayment(db, charges))
+	router.GET("/bookings/:locator/payments", GetBookingPaymentsAdmin(db, bookings, charges))
+}
@@ -0,0 +1,199 @@
+// Package routers provides HTTP route handlers for the pilot registry
+// in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strings"
+
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validatePilot normalizes the pilot attributes. It returns an error message or "".
+func validatePilot(pilot *models.Pilot) string {
+	pilot.FirstName = strings.TrimSpace(pilot.FirstName)
+	pilot.LastName = strings.TrimSpace(pilot.LastName)
+
+	if pilot.FirstName == "" || pilot.LastName == "" {
+		return "First name and last name are required"
+	}
+	if pilot.FlightHours < 0 {
+		return "Flight hours must not be negative"
+	}
+	return ""
+}
+
+// GetPilots returns all pilots
+func GetPilots(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		pilots, err := db.GetPilots()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pilots"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    pilots,
+			"message": "Pilots retrieved successfully",
+		})
+	}
+}
+
+// GetPilotByID returns a specific pilot
+func GetPilotByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		pilot, err := db.GetPilotByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pilot"})
+			return
+		}
+
+		if pilot == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pilot not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    pilot,
+			"message": "Pilot retrieved successfully",
+		})
+	}
+}
+
+// CreatePilot adds a new pilot to the registry.
+//
+// Request body should contain:
+//   - firstName, lastName: Name of the pilot
+//   - licenseType, licenseNumber, licenseExpiry: License details
+//   - medicalCheckDate: Date of the last medical examination
+//   - flightHours: Hours logged before registration (completed flights are added automatically)
+func CreatePilot(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var pilot models.Pilot
+		if err := c.ShouldBindJSON(&pilot); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validatePilot(&pilot); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// IDs are generated by the database layer
+		pilot.ID = ""
+		if err := db.CreatePilot(&pilot); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pilot"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    pilot,
+			"message": "Pilot created successfully",
+		})
+	}
+}
+
+// UpdatePilot replaces the attributes of an existing pilot
+func UpdatePilot(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var pilot models.Pilot
+		if err := c.ShouldBindJSON(&pilot); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		pilot.ID = c.Param("id")
+
+		if msg := validatePilot(&pilot); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetPilotByID(pilot.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pilot"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pilot not found"})
+			return
+		}
+
+		if err := db.UpdatePilot(pilot); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pilot"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    pilot,
+			"message": "Pilot updated successfully",
+		})
+	}
+}
+
+// DeletePilot removes a pilot. Pilots still assigned to flights cannot be deleted.
+func DeletePilot(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		pilot, err := db.GetPilotByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pilot"})
+			return
+		}
+		if pilot == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pilot not found"})
+			return
+		}
+
+		if err := db.DeletePilot(pilot.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Pilot is still assigned to flights"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pilot"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Pilot deleted successfully",
+		})
+	}
+}
+
+// PilotRoutes sets up pilot registry routes
+func PilotRoutes(router *gin.RouterGroup, db database.Database) {
+	router.GET("", GetPilots(db))
+	router.POST("", CreatePilot(db))
+	router.GET("/:id", GetPilotByID(db))
+	router.PUT("/:id", UpdatePilot(db))
+	router.DELETE("/:id", DeletePilot(db))
+}
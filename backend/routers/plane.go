@@ -0,0 +1,263 @@
+// Package routers provides HTTP route handlers for fleet management
+// in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strings"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validatePlane normalizes the plane attributes and checks them against the
+// values allowed by the PLANE table. It returns an error message or "".
+func validatePlane(plane *models.Plane) string {
+	plane.Model = strings.TrimSpace(plane.Model)
+	plane.AirlineID = strings.ToUpper(strings.TrimSpace(plane.AirlineID))
+	plane.Status = strings.ToUpper(plane.Status)
+
+	if plane.Status == "" {
+		plane.Status = "ACTIVE"
+	}
+
+	switch {
+	case plane.Model == "":
+		return "Model is required"
+	case plane.Seats <= 0:
+		return "Seats must be greater than 0"
+	case plane.MaxTakeoffWeight < 0 || plane.FuelCapacity < 0:
+		return "Max takeoff weight and fuel capacity must not be negative"
+	case plane.Status != "ACTIVE" && plane.Status != "MAINTENANCE" && plane.Status != "INACTIVE":
+		return "Status must be one of 'ACTIVE', 'MAINTENANCE' or 'INACTIVE'"
+	}
+	return ""
+}
+
+// GetPlanes returns the planes of the fleet.
+//
+// Query parameters:
+//   - airline: Only return planes of this airline (IATA code)
+func GetPlanes(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		planes, err := db.GetPlanes(strings.ToUpper(c.Query("airline")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve planes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    planes,
+			"message": "Planes retrieved successfully",
+		})
+	}
+}
+
+// GetPlaneByID returns a specific plane
+func GetPlaneByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		plane, err := db.GetPlaneByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plane"})
+			return
+		}
+
+		if plane == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plane not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plane,
+			"message": "Plane retrieved successfully",
+		})
+	}
+}
+
+// GetFleetOverview returns the number of planes and seats per airline and status
+func GetFleetOverview(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		fleet, err := db.GetFleetOverview()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve fleet overview"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    fleet,
+			"message": "Fleet overview retrieved successfully",
+		})
+	}
+}
+
+// CreatePlane adds a new plane to the fleet.
+//
+// Request body should contain:
+//   - model: Aircraft model (e.g. "Airbus A320")
+//   - seats: Passenger capacity
+//   - name, airlineId, hangarId: Optional registration, operator and hangar
+//   - manufacturingYear, maxTakeoffWeight, fuelCapacity: Optional technical data
+//   - status: ACTIVE (default), MAINTENANCE or INACTIVE
+//
+// A hangar must have room for the plane (see planning.CheckHangarAllocation).
+func CreatePlane(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var plane models.Plane
+		if err := c.ShouldBindJSON(&plane); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validatePlane(&plane); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// IDs are generated by the database layer
+		plane.ID = ""
+		if plane.AirlineID != "" {
+			if err := planner.ValidateAirline(plane.AirlineID); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+		if err := planner.ValidateHangar(plane); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.CreatePlane(&plane); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create plane"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    plane,
+			"message": "Plane created successfully",
+		})
+	}
+}
+
+// UpdatePlane replaces the attributes of an existing plane. A changed hangar
+// or model is checked against the space left in the hangar.
+func UpdatePlane(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var plane models.Plane
+		if err := c.ShouldBindJSON(&plane); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		plane.ID = c.Param("id")
+
+		if msg := validatePlane(&plane); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetPlaneByID(plane.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plane"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plane not found"})
+			return
+		}
+
+		if plane.AirlineID != "" && plane.AirlineID != existing.AirlineID {
+			if err := planner.ValidateAirline(plane.AirlineID); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+		if plane.HangarID != existing.HangarID || plane.Model != existing.Model {
+			if err := planner.ValidateHangar(plane); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+
+		if err := db.UpdatePlane(plane); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plane"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plane,
+			"message": "Plane updated successfully",
+		})
+	}
+}
+
+// DeletePlane removes a plane. Planes still used by flights cannot be deleted.
+func DeletePlane(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		plane, err := db.GetPlaneByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plane"})
+			return
+		}
+		if plane == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plane not found"})
+			return
+		}
+
+		if err := db.DeletePlane(plane.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Plane is still used by flights or maintenance records"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete plane"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Plane deleted successfully",
+		})
+	}
+}
+
+// PlaneRoutes sets up fleet management routes
+func PlaneRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	router.GET("", GetPlanes(db))
+	router.POST("", CreatePlane(db, planner))
+	router.GET("/fleet", GetFleetOverview(db))
+	router.GET("/:id", GetPlaneByID(db))
+	router.PUT("/:id", UpdatePlane(db, planner))
+	router.DELETE("/:id", DeletePlane(db))
+}
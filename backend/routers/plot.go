@@ -0,0 +1,314 @@
+// Package routers provides HTTP route handlers for managing the airport's
+// plots and the facilities occupying them in the MindenAirport API.
+package routers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validatePlot normalizes the plot attributes and checks them against the
+// values allowed by the PLOT table. It returns an error message or "".
+func validatePlot(plot *models.Plot) string {
+	plot.ID = strings.TrimSpace(plot.ID)
+	plot.TypeID = strings.TrimSpace(plot.TypeID)
+	plot.TerminalID = strings.TrimSpace(plot.TerminalID)
+	plot.Status = strings.ToUpper(plot.Status)
+
+	if plot.Status == "" {
+		plot.Status = "AVAILABLE"
+	}
+
+	switch {
+	case len(plot.ID) > 36:
+		return "Plot ID must not exceed 36 characters"
+	case plot.Position <= 0:
+		return "Position must be greater than 0"
+	case plot.TypeID == "":
+		return "Plot type ID is required"
+	case plot.AreaSqFt < 0 || plot.MaxWeightCapacity < 0:
+		return "Area and max weight capacity must not be negative"
+	case plot.Status != "AVAILABLE" && plot.Status != "OCCUPIED" && plot.Status != "MAINTENANCE":
+		return "Status must be one of 'AVAILABLE', 'OCCUPIED' or 'MAINTENANCE'"
+	}
+	return ""
+}
+
+// refreshPlotStatus updates the status of the given plots after facilities
+// were placed on or removed from them. Errors are logged only, since the
+// facility itself has already been stored.
+func refreshPlotStatus(db database.Database, plotIDs ...string) {
+	seen := make(map[string]bool)
+	for _, id := range plotIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if err := db.RefreshPlotStatus(id); err != nil {
+			log.Printf("Error refreshing status of plot %s: %v", id, err)
+		}
+	}
+}
+
+// GetPlots returns the plots of the airport with their occupants.
+//
+// Query parameters:
+//   - type: Only return plots of this type (ID or name, e.g. "hangar")
+//   - status: Only return plots with this status (AVAILABLE, OCCUPIED, MAINTENANCE)
+func GetPlots(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		plots, err := db.GetPlots(strings.ToLower(c.Query("type")), strings.ToUpper(c.Query("status")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plots"})
+			return
+		}
+
+		occupants, err := db.GetPlotOccupants("")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot occupants"})
+			return
+		}
+
+		byPlot := make(map[string][]models.PlotOccupant)
+		for _, o := range occupants {
+			byPlot[o.PlotID] = append(byPlot[o.PlotID], o)
+		}
+		for i := range plots {
+			plots[i].Occupants = append(plots[i].Occupants, byPlot[plots[i].ID]...)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plots,
+			"message": "Plots retrieved successfully",
+		})
+	}
+}
+
+// GetPlotByID returns a specific plot with its occupants
+func GetPlotByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		plot, err := db.GetPlotByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot"})
+			return
+		}
+		if plot == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plot not found"})
+			return
+		}
+
+		occupants, err := db.GetPlotOccupants(plot.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot occupants"})
+			return
+		}
+		plot.Occupants = append(plot.Occupants, occupants...)
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plot,
+			"message": "Plot retrieved successfully",
+		})
+	}
+}
+
+// GetPlotTypes returns all plot types
+func GetPlotTypes(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		types, err := db.GetPlotTypes()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot types"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    types,
+			"message": "Plot types retrieved successfully",
+		})
+	}
+}
+
+// CreatePlot adds a new plot.
+//
+// Request body should contain:
+//   - id: Optional plot ID (generated if empty)
+//   - position: Position number within the airport
+//   - typeId: ID of the plot type
+//   - areaSqFt, maxWeightCapacity: Area in square feet and carrying capacity in pounds
+//   - status: AVAILABLE (default), OCCUPIED or MAINTENANCE
+//   - terminalId: Optional terminal building the plot lies in
+//   - lastMaintenance, utilitiesAvailable: Optional details
+func CreatePlot(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var plot models.Plot
+		if err := c.ShouldBindJSON(&plot); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validatePlot(&plot); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// A new plot has nothing built on it yet
+		if plot.Status == "OCCUPIED" {
+			plot.Status = "AVAILABLE"
+		}
+
+		if plot.ID != "" {
+			existing, err := db.GetPlotByID(plot.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot"})
+				return
+			}
+			if existing != nil {
+				c.JSON(http.StatusConflict, gin.H{"error": "Plot already exists"})
+				return
+			}
+		}
+
+		if err := db.CreatePlot(&plot); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create plot"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    plot,
+			"message": "Plot created successfully",
+		})
+	}
+}
+
+// UpdatePlot replaces the attributes of an existing plot. The type of an
+// occupied plot cannot be changed, and its weight capacity cannot be reduced
+// below the weight it currently carries.
+func UpdatePlot(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var plot models.Plot
+		if err := c.ShouldBindJSON(&plot); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		plot.ID = c.Param("id")
+
+		if msg := validatePlot(&plot); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetPlotByID(plot.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plot not found"})
+			return
+		}
+
+		occupants, err := db.GetPlotOccupants(plot.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot occupants"})
+			return
+		}
+
+		if len(occupants) > 0 && plot.TypeID != existing.TypeID {
+			c.JSON(http.StatusConflict, gin.H{"error": "The type of an occupied plot cannot be changed", "occupants": occupants})
+			return
+		}
+		if plot.MaxWeightCapacity > 0 && plot.MaxWeightCapacity < existing.Load {
+			c.JSON(http.StatusConflict, gin.H{"error": "Max weight capacity is below the current load", "load": existing.Load})
+			return
+		}
+
+		if len(occupants) > 0 && plot.Status == "AVAILABLE" {
+			plot.Status = "OCCUPIED"
+		}
+
+		if err := db.UpdatePlot(plot); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plot"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    plot,
+			"message": "Plot updated successfully",
+		})
+	}
+}
+
+// DeletePlot removes a plot. Plots with facilities built on them cannot be deleted.
+func DeletePlot(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		plot, err := db.GetPlotByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve plot"})
+			return
+		}
+		if plot == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plot not found"})
+			return
+		}
+
+		if err := db.DeletePlot(plot.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Plot is still occupied"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete plot"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Plot deleted successfully",
+		})
+	}
+}
+
+// PlotRoutes sets up plot and space management routes
+func PlotRoutes(router *gin.RouterGroup, db database.Database) {
+	router.GET("", GetPlots(db))
+	router.POST("", CreatePlot(db))
+	router.GET("/types", GetPlotTypes(db))
+	router.GET("/:id", GetPlotByID(db))
+	router.PUT("/:id", UpdatePlot(db))
+	router.DELETE("/:id", DeletePlot(db))
+}
@@ -0,0 +1,463 @@
+// Package routers provides HTTP route handlers for recurring flight schedule
+// templates in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/jobs"
+	"mindenairport/models"
+	"mindenairport/notifications"
+	"mindenairport/schedule"
+	"mindenairport/timezone"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// flightNumberPattern matches flight numbers such as "LH123" or "U21234A":
+	// airline code, up to four digits and an optional operational suffix.
+	flightNumberPattern = regexp.MustCompile(`^[A-Z0-9]{2}[0-9]{1,4}[A-Z]?$`)
+	// clockPattern matches wall clock times such as "07:15".
+	clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+)
+
+// validateScheduleTemplate normalizes the template attributes and checks
+// them against the values allowed by the SCHEDULE_TEMPLATE table. It returns
+// an error message or "".
+func validateScheduleTemplate(template *models.ScheduleTemplate) string {
+	template.FlightNumber = strings.ToUpper(strings.TrimSpace(template.FlightNumber))
+	template.From = strings.ToUpper(strings.TrimSpace(template.From))
+	template.To = strings.ToUpper(strings.TrimSpace(template.To))
+	template.DepartureTime = strings.TrimSpace(template.DepartureTime)
+	template.ArrivalTime = strings.TrimSpace(template.ArrivalTime)
+	template.PlaneID = strings.TrimSpace(template.PlaneID)
+	template.TerminalID = strings.TrimSpace(template.TerminalID)
+	template.Gate = strings.TrimSpace(template.Gate)
+
+	// Days are stored in order and without repetitions, e.g. "135"
+	var days []string
+	for _, day := range "1234567" {
+		if strings.ContainsRune(template.DaysOfWeek, day) {
+			days = append(days, string(day))
+		}
+	}
+	valid := strings.Trim(template.DaysOfWeek, "1234567 ") == ""
+	template.DaysOfWeek = strings.Join(days, "")
+
+	// Templates are valid on whole days
+	y, m, d := template.ValidFrom.Date()
+	template.ValidFrom = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	y, m, d = template.ValidTo.Date()
+	template.ValidTo = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	switch {
+	case !flightNumberPattern.MatchString(template.FlightNumber):
+		return "Flight number must be an airline code followed by up to four digits, e.g. 'LH123'"
+	case template.From == "" || template.To == "":
+		return "From and to are required"
+	case template.From == template.To:
+		return "From and to must be different airports"
+	case !clockPattern.MatchString(template.DepartureTime) || !clockPattern.MatchString(template.ArrivalTime):
+		return "Departure and arrival time must be in HH:MM format"
+	case template.ArrivalDayOffset < 0 || template.ArrivalDayOffset > 2:
+		return "Arrival day offset must be between 0 and 2"
+	case !valid || template.DaysOfWeek == "":
+		return "Days of week must list the days of operation from 1 (Monday) to 7 (Sunday), e.g. '12345'"
+	case template.ValidFrom.Year() < 1900 || template.ValidTo.Year() < 1900:
+		return "Valid from and valid to are required"
+	case template.ValidTo.Before(template.ValidFrom):
+		return "Valid to must not be before valid from"
+	case template.PlaneID == "":
+		return "Plane ID is required"
+	}
+	return ""
+}
+
+// loadScheduleTemplate retrieves the template named by the id parameter. It
+// writes an error response and returns false if there is none.
+func loadScheduleTemplate(c *gin.Context, db database.Database) (*models.ScheduleTemplate, bool) {
+	template, err := db.GetScheduleTemplateByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedule template"})
+		return nil, false
+	}
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule template not found"})
+		return nil, false
+	}
+	return template, true
+}
+
+// loadTemplateFlight retrieves the flight named by the flightId parameter and
+// checks that it was generated from the template. It writes an error response
+// and returns false otherwise.
+func loadTemplateFlight(c *gin.Context, db database.Database, template models.ScheduleTemplate) (models.Flight, bool) {
+	flight, err := db.GetFlightByID(c.Param("flightId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flight"})
+		return flight, false
+	}
+	if flight.ID == "" || flight.TemplateID != template.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found for this schedule template"})
+		return flight, false
+	}
+	if !schedule.IsOutdated(models.TemplateFlight{Flight: flight}, template) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Flight already follows the current template revision"})
+		return flight, false
+	}
+	return flight, true
+}
+
+// GetScheduleTemplates returns all schedule templates
+func GetScheduleTemplates(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		templates, err := db.GetScheduleTemplates()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedule templates"})
+			return
+		}
+		if templates == nil {
+			templates = []models.ScheduleTemplate{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    templates,
+			"message": "Schedule templates retrieved successfully",
+		})
+	}
+}
+
+// GetScheduleTemplateByID returns a specific schedule template
+func GetScheduleTemplateByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		template, ok := loadScheduleTemplate(c, db)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    template,
+			"message": "Schedule template retrieved successfully",
+		})
+	}
+}
+
+// CreateScheduleTemplate adds a schedule template and generates its flights
+// within the rollout horizon.
+//
+// Request body should contain:
+//   - flightNumber: Flight number, e.g. "LH123"
+//   - from, to: IATA codes of origin and destination
+//   - departureTime, arrivalTime: Local times at origin and destination (HH:MM)
+//   - arrivalDayOffset: Days between departure and arrival date (0-2)
+//   - daysOfWeek: Days of operation, e.g. "1234567" for daily
+//   - validFrom, validTo: First and last day of operation
+//   - planeId: Plane of the generated flights
+//   - terminalId, gate: Optional default terminal and gate
+//
+// Returns:
+//   - 201: Template created; sync lists the generated flights and the dates
+//     on which no flight could be created
+//   - 400: Invalid template
+func CreateScheduleTemplate(db database.Database, rollout *jobs.ScheduleRollout) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var template models.ScheduleTemplate
+		if err := c.ShouldBindJSON(&template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateScheduleTemplate(&template); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		if err := rollout.Validate(template); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		// IDs are generated by the database layer
+		template.ID = ""
+		if err := db.CreateScheduleTemplate(&template); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create schedule template"})
+			return
+		}
+
+		sync, err := rollout.Sync(template, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Schedule template created, but generating its flights failed", "details": err.Error(), "data": template})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    template,
+			"sync":    sync,
+			"message": "Schedule template created successfully",
+		})
+	}
+}
+
+// UpdateScheduleTemplate replaces a schedule template and rolls the change
+// out to its future flights. Flights without tickets are changed or removed;
+// booked flights keep their schedule and are listed as outdated in the sync
+// report, to be resolved with the apply and keep endpoints.
+func UpdateScheduleTemplate(db database.Database, rollout *jobs.ScheduleRollout) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var template models.ScheduleTemplate
+		if err := c.ShouldBindJSON(&template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		template.ID = c.Param("id")
+
+		if msg := validateScheduleTemplate(&template); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		if _, ok := loadScheduleTemplate(c, db); !ok {
+			return
+		}
+		if err := rollout.Validate(template); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.UpdateScheduleTemplate(&template); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update schedule template"})
+			return
+		}
+
+		sync, err := rollout.Sync(template, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Schedule template updated, but rolling out the change failed", "details": err.Error(), "data": template})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    template,
+			"sync":    sync,
+			"message": "Schedule template updated successfully",
+		})
+	}
+}
+
+// DeleteScheduleTemplate removes a schedule template together with its
+// future flights without tickets. Booked flights are kept and listed as
+// outdated in the sync report; they have to be cancelled separately.
+func DeleteScheduleTemplate(db database.Database, rollout *jobs.ScheduleRollout) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		template, ok := loadScheduleTemplate(c, db)
+		if !ok {
+			return
+		}
+
+		sync, err := rollout.Remove(*template, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove template flights", "details": err.Error(), "sync": sync})
+			return
+		}
+
+		if err := db.DeleteScheduleTemplate(template.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule template"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"sync":    sync,
+			"message": "Schedule template deleted successfully",
+		})
+	}
+}
+
+// GetTemplateFlights returns the future flights of a schedule template with
+// their number of tickets. Flights marked as outdated differ from the
+// current template revision.
+//
+// Query parameters:
+//   - outdated: "true" to return only outdated flights
+func GetTemplateFlights(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		template, ok := loadScheduleTemplate(c, db)
+		if !ok {
+			return
+		}
+
+		flights, err := db.GetTemplateFlights(template.ID, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flights"})
+			return
+		}
+
+		localizer := timezone.NewLocalizer(db)
+		result := []models.TemplateFlight{}
+		for _, f := range flights {
+			f.Outdated = schedule.IsOutdated(f, *template)
+			if c.Query("outdated") == "true" && !f.Outdated {
+				continue
+			}
+			localizer.Flight(&f.Flight)
+			result = append(result, f)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    result,
+			"message": "Flights retrieved successfully",
+		})
+	}
+}
+
+// ApplyScheduleTemplate changes an outdated flight to the current revision
+// of its template despite its tickets. Flights the template no longer
+// operates are cancelled. Passengers are notified about the change.
+func ApplyScheduleTemplate(db database.Database, rollout *jobs.ScheduleRollout, notifier *notifications.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		template, ok := loadScheduleTemplate(c, db)
+		if !ok {
+			return
+		}
+		flight, ok := loadTemplateFlight(c, db, *template)
+		if !ok {
+			return
+		}
+
+		updated, err := rollout.Apply(*template, flight)
+		if err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		// Inform affected passengers without delaying the response
+		go notifier.NotifyFlightChange(flight, updated)
+
+		timezone.NewLocalizer(db).Flight(&updated)
+		c.JSON(http.StatusOK, gin.H{
+			"data":    updated,
+			"message": "Schedule template applied successfully",
+		})
+	}
+}
+
+// KeepTemplateFlight marks an outdated flight as following the current
+// template revision without changing it, e.g. to keep operating a booked
+// flight the template no longer schedules.
+func KeepTemplateFlight(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		template, ok := loadScheduleTemplate(c, db)
+		if !ok {
+			return
+		}
+		flight, ok := loadTemplateFlight(c, db, *template)
+		if !ok {
+			return
+		}
+
+		if err := db.SetFlightTemplateRevision(flight.ID, template.Revision); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update flight"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Flight kept successfully",
+		})
+	}
+}
+
+// GetScheduleRollout returns the result of the latest rollout of all templates.
+func GetScheduleRollout(db database.Database, rollout *jobs.ScheduleRollout) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    rollout.Report(),
+			"message": "Schedule rollout retrieved successfully",
+		})
+	}
+}
+
+// RunScheduleRollout generates the flights of all templates within the
+// horizon now instead of waiting for the next periodic run.
+func RunScheduleRollout(db database.Database, rollout *jobs.ScheduleRollout) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		report, err := rollout.Run(time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to roll out schedules"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    report,
+			"message": "Schedules rolled out successfully",
+		})
+	}
+}
+
+// ScheduleRoutes sets up schedule template routes
+func ScheduleRoutes(router *gin.RouterGroup, db database.Database, rollout *jobs.ScheduleRollout) {
+	notifier := notifications.NewService(db)
+
+	router.GET("", GetScheduleTemplates(db))
+	router.POST("", CreateScheduleTemplate(db, rollout))
+	router.GET("/rollout", GetScheduleRollout(db, rollout))
+	router.POST("/rollout", RunScheduleRollout(db, rollout))
+	router.GET("/:id", GetScheduleTemplateByID(db))
+	router.PUT("/:id", UpdateScheduleTemplate(db, rollout))
+	router.DELETE("/:id", DeleteScheduleTemplate(db, rollout))
+	router.GET("/:id/flights", GetTemplateFlights(db))
+	router.POST("/:id/flights/:flightId/apply", ApplyScheduleTemplate(db, rollout, notifier))
+	router.POST("/:id/flights/:flightId/keep", KeepTemplateFlight(db))
+}
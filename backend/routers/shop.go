@@ -0,0 +1,407 @@
+// Package routers provides HTTP route handlers for the shop and services
+// directory and shop management in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clockLayouts are the accepted formats of opening hours: the HH:MM format of
+// the SHOP table and the 12-hour format used in the typical hours of shop types.
+var clockLayouts = []string{"15:04", "3:04 PM", "3:04PM", "3 PM", "3PM"}
+
+// parseClock parses a time of day and returns it in minutes after midnight.
+func parseClock(value string) (int, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Hour()*60 + t.Minute(), true
+		}
+	}
+	return 0, false
+}
+
+// shopHours returns the daily opening and closing time of a shop in minutes
+// after midnight. Shops without own times use the typical hours of their
+// type (e.g. "6:00 AM - 10:00 PM" or "24 hours"). Equal opening and closing
+// times mean the shop never closes; a closing time before the opening time
+// means it closes after midnight. The last value is false if the hours are
+// unknown.
+func shopHours(shop models.ShopListing) (int, int, bool) {
+	if shop.OpeningTime != "" && shop.ClosingTime != "" {
+		open, okOpen := parseClock(shop.OpeningTime)
+		closing, okClose := parseClock(shop.ClosingTime)
+		return open, closing, okOpen && okClose
+	}
+
+	hours := strings.ToLower(shop.TypicalHours)
+	if strings.Contains(hours, "24 hours") {
+		return 0, 0, true
+	}
+	parts := strings.Split(hours, "-")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	open, okOpen := parseClock(parts[0])
+	closing, okClose := parseClock(parts[1])
+	return open, closing, okOpen && okClose
+}
+
+// shopOpenAt reports whether a shop is open at the given time. The time must
+// be in the airport's time zone.
+func shopOpenAt(shop models.ShopListing, t time.Time) bool {
+	open, closing, ok := shopHours(shop)
+	if !ok {
+		return false
+	}
+	if open == closing {
+		return true
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if open < closing {
+		return minute >= open && minute < closing
+	}
+	return minute >= open || minute < closing
+}
+
+// shopOpenDuring reports whether a shop is open at any time within [from, to).
+// The times must be in the airport's time zone.
+func shopOpenDuring(shop models.ShopListing, from, to time.Time) bool {
+	if !from.Before(to) {
+		return false
+	}
+	if shopOpenAt(shop, from) {
+		return true
+	}
+
+	open, _, ok := shopHours(shop)
+	if !ok {
+		return false
+	}
+	opening := time.Date(from.Year(), from.Month(), from.Day(), open/60, open%60, 0, 0, from.Location())
+	if !opening.After(from) {
+		opening = opening.AddDate(0, 0, 1)
+	}
+	return opening.Before(to)
+}
+
+// optionalBool parses an optional boolean query parameter. It writes a 400
+// response and returns false as second value if the parameter is invalid.
+func optionalBool(c *gin.Context, name string) (*bool, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be true or false"})
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// validateShop normalizes the shop attributes and checks them against the
+// values allowed by the SHOP table. It returns an error message or "".
+func validateShop(shop *models.Shop) string {
+	shop.Name = strings.TrimSpace(shop.Name)
+	shop.TypeID = strings.TrimSpace(shop.TypeID)
+	shop.PlotID = strings.TrimSpace(shop.PlotID)
+	shop.OpeningTime = strings.TrimSpace(shop.OpeningTime)
+	shop.ClosingTime = strings.TrimSpace(shop.ClosingTime)
+
+	validTime := func(v string) bool {
+		_, err := time.Parse("15:04", v)
+		return err == nil && len(v) == 5
+	}
+
+	switch {
+	case shop.Name == "":
+		return "Name is required"
+	case shop.TypeID == "":
+		return "Shop type ID is required"
+	case shop.PlotID == "":
+		return "Plot ID is required"
+	case (shop.OpeningTime == "") != (shop.ClosingTime == ""):
+		return "Opening and closing time must be given together"
+	case shop.OpeningTime != "" && (!validTime(shop.OpeningTime) || !validTime(shop.ClosingTime)):
+		return "Opening and closing time must be in HH:MM format"
+	}
+	return ""
+}
+
+// GetShops returns the shop and services directory.
+//
+// Query parameters:
+//   - category: Only shops of this category (e.g. "Food", "Retail")
+//   - terminal: Only shops in this terminal
+//   - dutyFree: Only duty-free (true) or regular (false) shops
+//   - airside: Only shops after (true) or before (false) the security check
+//   - openNow: Only shops open at the moment (true), in the airport's time zone
+func GetShops(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dutyFree, ok := optionalBool(c, "dutyFree")
+		if !ok {
+			return
+		}
+		airside, ok := optionalBool(c, "airside")
+		if !ok {
+			return
+		}
+		openNow, ok := optionalBool(c, "openNow")
+		if !ok {
+			return
+		}
+
+		shops, err := db.GetShops(c.Query("category"), c.Query("terminal"), dutyFree)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shops"})
+			return
+		}
+
+		now := time.Now().In(planner.Config.Location)
+		result := []models.ShopListing{}
+		for _, shop := range shops {
+			shop.OpenNow = shopOpenAt(shop, now)
+			if airside != nil && *airside != (shop.SecurityLevel == models.SecurityAirside) {
+				continue
+			}
+			if openNow != nil && *openNow != shop.OpenNow {
+				continue
+			}
+			result = append(result, shop)
+		}
+
+		c.IndentedJSON(http.StatusOK, result)
+	}
+}
+
+// GetShopByID returns a specific shop
+func GetShopByID(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shop, err := db.GetShopByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shop"})
+			return
+		}
+		if shop == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Shop not found"})
+			return
+		}
+
+		shop.OpenNow = shopOpenAt(*shop, time.Now().In(planner.Config.Location))
+		c.IndentedJSON(http.StatusOK, shop)
+	}
+}
+
+// GetShopTypes returns all shop types
+func GetShopTypes(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		types, err := db.GetShopTypes()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shop types"})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, types)
+	}
+}
+
+// GetDepartureShops returns the shops in the departure terminal of a flight
+// that are open at some time before the gate of the flight closes.
+func GetDepartureShops(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		flight, err := db.GetFlightByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve flight"})
+			return
+		}
+		if flight.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+		if flight.From != planner.Config.HomeAirport {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Flight does not depart from this airport"})
+			return
+		}
+
+		shops, err := db.GetShops("", flight.TerminalID, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shops"})
+			return
+		}
+
+		now := time.Now().In(planner.Config.Location)
+		closes := planner.Config.GateClosing(flight).In(planner.Config.Location)
+		result := models.DepartureShops{
+			FlightID:     flight.ID,
+			TerminalID:   flight.TerminalID,
+			Gate:         flight.Gate,
+			GateClosesAt: closes,
+			Shops:        []models.ShopListing{},
+		}
+		for _, shop := range shops {
+			if shopOpenDuring(shop, now, closes) {
+				shop.OpenNow = shopOpenAt(shop, now)
+				result.Shops = append(result.Shops, shop)
+			}
+		}
+
+		c.IndentedJSON(http.StatusOK, result)
+	}
+}
+
+// CreateShop adds a new shop on a free shop plot.
+//
+// Request body should contain:
+//   - name: Display name
+//   - typeId: ID of the shop type
+//   - plotId: Plot the shop is located on
+//   - openingTime, closingTime: Optional daily hours in HH:MM format (defaults to the type's typical hours)
+//   - description, isDutyFree: Optional details
+func CreateShop(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var shop models.Shop
+		if err := c.ShouldBindJSON(&shop); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateShop(&shop); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		// IDs are generated by the database layer
+		shop.ID = ""
+		occupant := models.PlotOccupant{Kind: models.OccupantShop, Name: shop.Name, PlotID: shop.PlotID}
+		if err := planner.ValidatePlacement(occupant, 0); err != nil {
+			respondPlanningError(c, err)
+			return
+		}
+
+		if err := db.CreateShop(&shop); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create shop"})
+			return
+		}
+		refreshPlotStatus(db, shop.PlotID)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    shop,
+			"message": "Shop created successfully",
+		})
+	}
+}
+
+// UpdateShop replaces the attributes of an existing shop. Moving it to
+// another plot requires that plot to be free.
+func UpdateShop(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var shop models.Shop
+		if err := c.ShouldBindJSON(&shop); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		shop.ID = c.Param("id")
+
+		if msg := validateShop(&shop); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetShopByID(shop.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shop"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Shop not found"})
+			return
+		}
+
+		if shop.PlotID != existing.PlotID {
+			occupant := models.PlotOccupant{Kind: models.OccupantShop, ID: shop.ID, Name: shop.Name, PlotID: shop.PlotID}
+			if err := planner.ValidatePlacement(occupant, 0); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+
+		if err := db.UpdateShop(shop); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update shop"})
+			return
+		}
+		refreshPlotStatus(db, existing.PlotID, shop.PlotID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    shop,
+			"message": "Shop updated successfully",
+		})
+	}
+}
+
+// DeleteShop removes a shop and frees its plot
+func DeleteShop(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		shop, err := db.GetShopByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve shop"})
+			return
+		}
+		if shop == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Shop not found"})
+			return
+		}
+
+		if err := db.DeleteShop(shop.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete shop"})
+			return
+		}
+		refreshPlotStatus(db, shop.PlotID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Shop deleted successfully",
+		})
+	}
+}
+
+// ShopRoutes sets up the public shop and services directory routes
+func ShopRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	router.GET("", GetShops(db, planner))
+	router.GET("/types", GetShopTypes(db))
+	router.GET("/flight/:id", GetDepartureShops(db, planner))
+	router.GET("/:id", GetShopByID(db, planner))
+}
+
+// ShopAdminRoutes sets up shop management routes
+func ShopAdminRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	router.POST("", CreateShop(db, planner))
+	router.PUT("/:id", UpdateShop(db, planner))
+	router.DELETE("/:id", DeleteShop(db))
+}
@@ -0,0 +1,340 @@
+// Package routers provides HTTP route handlers for terminal information,
+// terminal management and terminal load monitoring in the MindenAirport API.
+package routers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Utilization thresholds for the terminal crowding levels.
+const (
+	terminalLoadModerate = 0.5
+	terminalLoadHigh     = 0.8
+)
+
+// defaultLoadWindow is the time window used for terminal load if none is requested.
+const defaultLoadWindow = 2 * time.Hour
+
+// loadLevel derives the utilization and crowding level of a terminal load.
+func loadLevel(load *models.TerminalLoad) {
+	if load.Capacity <= 0 {
+		load.Level = "UNKNOWN"
+		return
+	}
+
+	load.Utilization = float64(load.Passengers) / float64(load.Capacity)
+	switch {
+	case load.Utilization > 1:
+		load.Level = "OVER_CAPACITY"
+	case load.Utilization >= terminalLoadHigh:
+		load.Level = "HIGH"
+	case load.Utilization >= terminalLoadModerate:
+		load.Level = "MODERATE"
+	default:
+		load.Level = "LOW"
+	}
+}
+
+// loadWindow parses the time window for terminal load requests.
+//
+// Query parameters:
+//   - from: Start of the window in RFC 3339 format (defaults to now)
+//   - hours: Length of the window in hours, 1-24 (defaults to 2)
+func loadWindow(c *gin.Context) (time.Time, time.Time, bool) {
+	start := time.Now()
+	if f := c.Query("from"); f != "" {
+		parsed, err := time.Parse(time.RFC3339, f)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "From must be in RFC 3339 format"})
+			return start, start, false
+		}
+		start = parsed
+	}
+
+	window := defaultLoadWindow
+	if h := c.Query("hours"); h != "" {
+		parsed, err := strconv.Atoi(h)
+		if err != nil || parsed < 1 || parsed > 24 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Hours must be between 1 and 24"})
+			return start, start, false
+		}
+		window = time.Duration(parsed) * time.Hour
+	}
+
+	return start, start.Add(window), true
+}
+
+// validateTerminal normalizes the terminal attributes and checks them against
+// the values allowed by the TERMINAL table. It returns an error message or "".
+func validateTerminal(terminal *models.Terminal) string {
+	terminal.ID = strings.TrimSpace(terminal.ID)
+	terminal.Name = strings.TrimSpace(terminal.Name)
+	terminal.Status = strings.ToUpper(terminal.Status)
+	terminal.PlotID = strings.TrimSpace(terminal.PlotID)
+
+	if terminal.Status == "" {
+		terminal.Status = "ACTIVE"
+	}
+
+	switch {
+	case terminal.ID == "" || len(terminal.ID) > 36:
+		return "Terminal ID is required and must not exceed 36 characters"
+	case terminal.Name == "":
+		return "Terminal name is required"
+	case terminal.Capacity < 0 || terminal.FloorCount < 0:
+		return "Capacity and floor count must not be negative"
+	case terminal.Status != "ACTIVE" && terminal.Status != "MAINTENANCE" && terminal.Status != "CLOSED":
+		return "Status must be one of 'ACTIVE', 'MAINTENANCE' or 'CLOSED'"
+	}
+	return ""
+}
+
+// GetTerminals returns all terminals of the airport
+func GetTerminals(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		terminals, err := db.GetTerminals()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve terminals"})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, terminals)
+	}
+}
+
+// GetTerminalByID returns a specific terminal
+func GetTerminalByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		terminal, err := db.GetTerminalByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve terminal"})
+			return
+		}
+
+		if terminal == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Terminal not found"})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, terminal)
+	}
+}
+
+// GetTerminalLoads returns the live load of all terminals, computed from the
+// booked passengers of flights departing within the requested time window.
+func GetTerminalLoads(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, ok := loadWindow(c)
+		if !ok {
+			return
+		}
+
+		loads, err := db.GetTerminalLoads(start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate terminal load"})
+			return
+		}
+
+		for i := range loads {
+			loadLevel(&loads[i])
+		}
+
+		c.IndentedJSON(http.StatusOK, loads)
+	}
+}
+
+// GetTerminalLoadByID returns the live load of a specific terminal
+func GetTerminalLoadByID(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, ok := loadWindow(c)
+		if !ok {
+			return
+		}
+
+		loads, err := db.GetTerminalLoads(start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate terminal load"})
+			return
+		}
+
+		for _, load := range loads {
+			if load.TerminalID == c.Param("id") {
+				loadLevel(&load)
+				c.IndentedJSON(http.StatusOK, load)
+				return
+			}
+		}
+
+		c.JSON(http.StatusNotFound, gin.H{"error": "Terminal not found"})
+	}
+}
+
+// CreateTerminal adds a new terminal.
+//
+// Request body should contain:
+//   - id: Terminal ID (e.g. "T003")
+//   - name: Display name
+//   - capacity: Passenger capacity used for load monitoring
+//   - status: ACTIVE (default), MAINTENANCE or CLOSED
+//   - floorCount, services, openingHours: Optional details
+//   - plotId: Optional free terminal plot the building stands on
+func CreateTerminal(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var terminal models.Terminal
+		if err := c.ShouldBindJSON(&terminal); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		if msg := validateTerminal(&terminal); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetTerminalByID(terminal.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve terminal"})
+			return
+		}
+		if existing != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Terminal already exists"})
+			return
+		}
+
+		if terminal.PlotID != "" {
+			occupant := models.PlotOccupant{Kind: models.OccupantTerminal, ID: terminal.ID, Name: terminal.Name, PlotID: terminal.PlotID}
+			if err := planner.ValidatePlacement(occupant, 0); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+
+		if err := db.CreateTerminal(terminal); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create terminal"})
+			return
+		}
+		refreshPlotStatus(db, terminal.PlotID)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    terminal,
+			"message": "Terminal created successfully",
+		})
+	}
+}
+
+// UpdateTerminal replaces the attributes of an existing terminal. A new plot
+// must be a free terminal plot.
+func UpdateTerminal(db database.Database, planner *planning.Planner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		var terminal models.Terminal
+		if err := c.ShouldBindJSON(&terminal); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+
+		// Set the ID from the URL parameter
+		terminal.ID = c.Param("id")
+
+		if msg := validateTerminal(&terminal); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		existing, err := db.GetTerminalByID(terminal.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve terminal"})
+			return
+		}
+		if existing == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Terminal not found"})
+			return
+		}
+
+		if terminal.PlotID != "" && terminal.PlotID != existing.PlotID {
+			occupant := models.PlotOccupant{Kind: models.OccupantTerminal, ID: terminal.ID, Name: terminal.Name, PlotID: terminal.PlotID}
+			if err := planner.ValidatePlacement(occupant, 0); err != nil {
+				respondPlanningError(c, err)
+				return
+			}
+		}
+
+		if err := db.UpdateTerminal(terminal); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update terminal"})
+			return
+		}
+		refreshPlotStatus(db, existing.PlotID, terminal.PlotID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    terminal,
+			"message": "Terminal updated successfully",
+		})
+	}
+}
+
+// DeleteTerminal removes a terminal. Terminals still used by flights or gates cannot be deleted.
+func DeleteTerminal(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		terminal, err := db.GetTerminalByID(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve terminal"})
+			return
+		}
+		if terminal == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Terminal not found"})
+			return
+		}
+
+		if err := db.DeleteTerminal(terminal.ID); err != nil {
+			if database.IsChildRecordError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Terminal is still used by flights or gates"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete terminal"})
+			return
+		}
+		refreshPlotStatus(db, terminal.PlotID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Terminal deleted successfully",
+		})
+	}
+}
+
+// TerminalRoutes sets up the public terminal information routes
+func TerminalRoutes(router *gin.RouterGroup, db database.Database) {
+	router.GET("/", GetTerminals(db))
+	router.GET("/load", GetTerminalLoads(db))
+	router.GET("/:id", GetTerminalByID(db))
+	router.GET("/:id/load", GetTerminalLoadByID(db))
+}
+
+// TerminalAdminRoutes sets up terminal management routes
+func TerminalAdminRoutes(router *gin.RouterGroup, db database.Database, planner *planning.Planner) {
+	router.POST("", CreateTerminal(db, planner))
+	router.PUT("/:id", UpdateTerminal(db, planner))
+	router.DELETE("/:id", DeleteTerminal(db))
+}
@@ -3,14 +3,28 @@ package routers
 import (
 	"net/http"
 
+	"mindenairport/apis"
+	"mindenairport/boarding"
 	"mindenairport/database"
+	"mindenairport/geo"
 	"mindenairport/models"
+	"mindenairport/timezone"
 
 	"github.com/gin-gonic/gin"
 )
 
+// GetTicketByID returns a specific ticket including the route distance and
+// the estimated CO2 emissions per passenger of its flight. Only the holder,
+// the passenger and admins may see a ticket; others get 404, as the ticket
+// reveals the booking locator.
 func GetTicketByID(db database.Database) gin.HandlerFunc {
 	fn := func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
 		id := c.Param("id")
 
 		ticket, err := db.GetTicketByID(id)
@@ -19,13 +33,34 @@ func GetTicketByID(db database.Database) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket"})
 			return
 		}
+		if ticket.ID != "" && !ticket.HeldBy(userID.(string)) {
+			user, err := db.GetUserByID(userID.(string))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+				return
+			}
+			if user == nil || (user.Role != "ADMIN" && user.Role != "admin") {
+				ticket = models.Ticket{}
+			}
+		}
+		if ticket.ID == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+			return
+		}
+
+		if err := geo.NewEstimator(db).Ticket(&ticket); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to estimate flight emissions"})
+			return
+		}
+		timezone.NewLocalizer(db).Ticket(&ticket)
 		c.IndentedJSON(http.StatusOK, ticket)
 	}
 
 	return gin.HandlerFunc(fn)
 }
 
-// GetMyTickets retrieves all tickets for the authenticated user
+// GetMyTickets retrieves all tickets the authenticated user holds or travels
+// on; ?traveller=self or a companion ID narrows the list
 func GetMyTickets(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get user ID from context (set by auth middleware)
@@ -35,16 +70,34 @@ func GetMyTickets(db database.Database) gin.HandlerFunc {
 			return
 		}
 
+		filter, ok := parseTravellerFilter(c, db, userID.(string))
+		if !ok {
+			return
+		}
+
 		// Get tickets for the user
-		tickets, err := db.GetTicketsByUserID(userID.(string))
+		allTickets, err := db.GetTicketsByUserID(userID.(string))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tickets"})
 			return
 		}
 
 		// Return empty array if no tickets found
-		if tickets == nil {
-			tickets = []models.Ticket{}
+		tickets := []models.Ticket{}
+		for _, ticket := range allTickets {
+			if filter.matchesTicket(ticket, userID.(string)) {
+				tickets = append(tickets, ticket)
+			}
+		}
+
+		estimator := geo.NewEstimator(db)
+		localizer := timezone.NewLocalizer(db)
+		for i := range tickets {
+			if err := estimator.Ticket(&tickets[i]); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to estimate flight emissions"})
+				return
+			}
+			localizer.Ticket(&tickets[i])
 		}
 
 		c.JSON(http.StatusOK, gin.H{
@@ -56,6 +109,17 @@ func GetMyTickets(db database.Database) gin.HandlerFunc {
 }
 
 func TicketRoutes(router *gin.RouterGroup, db database.Database) {
-	router.GET("/my", GetMyTickets(db))   // Get authenticated user's tickets
-	router.GET("/:id", GetTicketByID(db)) // Get specific ticket by ID
+	passengers := apis.NewService(db)
+	gate := boarding.NewService(db)
+
+	router.GET("/my", GetMyTickets(db))                          // Get authenticated user's tickets
+	router.GET("/my.ics", GetMyTicketsCalendar(db))              // Authenticated user's tickets as iCalendar file
+	router.GET("/calendar", GetCalendarSubscription(db))         // Calendar subscription of the authenticated user
+	router.POST("/calendar", CreateCalendarSubscription(db))     // Create or renew the calendar subscription URL
+	router.DELETE("/calendar", DeleteCalendarSubscription(db))   // Revoke the calendar subscription URL
+	router.GET("/:id", GetTicketByID(db))                        // Get specific ticket by ID
+	router.GET("/:id/calendar.ics", GetTicketCalendar(db))       // Specific ticket as iCalendar file
+	router.POST("/:id/checkin", CheckInTicket(db, passengers))   // Check in, with travel document for international flights
+	router.POST("/:id/volunteer", VolunteerTicket(db, gate))     // Offer to give up the seat on an oversold flight
+	router.DELETE("/:id/volunteer", WithdrawVolunteer(db, gate)) // Take back the volunteer offer
 }
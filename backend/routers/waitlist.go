@@ -0,0 +1,343 @@
+// Package routers provides HTTP route handlers for the overbooking limits and
+// waitlists of full flights in the MindenAirport API.
+package routers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"mindenairport/booking"
+	"mindenairport/database"
+	"mindenairport/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyWaitlist returns the waitlist entries of the authenticated user,
+// newest first.
+func GetMyWaitlist(bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		entries, err := bookings.WaitlistForUser(userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve waitlist"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    entries,
+			"count":   len(entries),
+			"message": "Waitlist retrieved successfully",
+		})
+	}
+}
+
+// JoinWaitlist puts passengers on the waitlist of a full flight. Passengers
+// are given as for a booking. When seats become free the user is notified
+// and has a limited time to accept the offer.
+//
+// Returns:
+//   - 201: Entry created, with its place in the queue
+//   - 400: Invalid passengers or flight
+//   - 404: A companion does not belong to the user
+//   - 409: The flight cannot be booked, or still has seats and can be booked directly
+func JoinWaitlist(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from context (set by auth middleware)
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		var req models.WaitlistRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if msg := booking.ValidateWaitlist(&req); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+
+		user, err := db.GetUserByID(userID.(string))
+		if err != nil || user == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+			return
+		}
+
+		entry, err := bookings.JoinWaitlist(*user, req, time.Now())
+		switch {
+		case errors.Is(err, booking.ErrUnknownCompanion):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Companion not found"})
+			return
+		case errors.Is(err, booking.ErrFlightNotBookable), errors.Is(err, booking.ErrSeatsAvailable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join waitlist"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    entry,
+			"message": "Waitlist joined successfully",
+		})
+	}
+}
+
+// AcceptWaitlistOffer books the passengers of a waitlist entry on the seats
+// offered to it. Like any new booking it is held until it is paid.
+//
+// Returns:
+//   - 201: Booking created
+//   - 404: Entry or a companion not found
+//   - 409: No open offer, or the flight can no longer be booked
+func AcceptWaitlistOffer(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entry, ok := ownWaitlistEntry(c, bookings)
+		if !ok {
+			return
+		}
+
+		user, err := db.GetUserByID(entry.AirportUserID)
+		if err != nil || user == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+			return
+		}
+
+		b, err := bookings.AcceptOffer(*user, entry, time.Now())
+		switch {
+		case errors.Is(err, booking.ErrUnknownCompanion):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Companion not found"})
+			return
+		case errors.Is(err, booking.ErrOfferClosed), errors.Is(err, booking.ErrFlightNotBookable), errors.Is(err, booking.ErrSoldOut),
+			errors.Is(err, booking.ErrNoFare):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept offer"})
+			return
+		}
+
+		localizeBooking(db, b)
+		c.JSON(http.StatusCreated, gin.H{
+			"data":    b,
+			"message": "Offer accepted successfully",
+		})
+	}
+}
+
+// LeaveWaitlist takes an entry of the authenticated user off the waitlist.
+// An open offer is declined and passed on to the next passengers waiting.
+func LeaveWaitlist(bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entry, ok := ownWaitlistEntry(c, bookings)
+		if !ok {
+			return
+		}
+
+		err := bookings.Withdraw(entry, time.Now())
+		switch {
+		case errors.Is(err, booking.ErrWaitlistClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave waitlist"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Waitlist left successfully"})
+	}
+}
+
+// ownWaitlistEntry loads the waitlist entry of the id parameter if it
+// belongs to the authenticated user. Otherwise it writes the error response
+// and returns false.
+func ownWaitlistEntry(c *gin.Context, bookings *booking.Service) (*models.WaitlistEntry, bool) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	entry, err := bookings.WaitlistEntry(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve waitlist entry"})
+		return nil, false
+	}
+	if entry == nil || entry.AirportUserID != userID.(string) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Waitlist entry not found"})
+		return nil, false
+	}
+	return entry, true
+}
+
+// GetFlightOverbooking returns the overbooking limit of a flight with the
+// tickets sold, the seats held by waitlist offers and the passengers waiting.
+func GetFlightOverbooking(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		availability, err := bookings.Availability(c.Param("id"), time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve overbooking"})
+			return
+		}
+		if availability == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    availability,
+			"message": "Overbooking retrieved successfully",
+		})
+	}
+}
+
+// SetFlightOverbooking sets the overbooking percentage of a flight, which
+// overrides the one of its airline. A null percentage removes it. Seats
+// freed by a higher limit are offered to the waitlist.
+//
+// Returns:
+//   - 200: Percentage set, with the resulting limit
+//   - 400: Percentage out of range
+//   - 404: Flight not found
+func SetFlightOverbooking(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		req, ok := bindOverbooking(c)
+		if !ok {
+			return
+		}
+
+		updated, err := db.SetFlightOverbooking(c.Param("id"), req.Percent)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update overbooking"})
+			return
+		}
+		if !updated {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Flight not found"})
+			return
+		}
+
+		now := time.Now()
+		if err := bookings.ReleaseSeats(c.Param("id"), now); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to offer seats to the waitlist"})
+			return
+		}
+		availability, err := bookings.Availability(c.Param("id"), now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve overbooking"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    availability,
+			"message": "Overbooking updated successfully",
+		})
+	}
+}
+
+// SetAirlineOverbooking sets the overbooking percentage of an airline for
+// all of its flights without a percentage of their own. A null percentage
+// removes it. The new limit applies to the next bookings and waitlist offers.
+func SetAirlineOverbooking(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		req, ok := bindOverbooking(c)
+		if !ok {
+			return
+		}
+
+		updated, err := db.SetAirlineOverbooking(c.Param("id"), req.Percent)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update overbooking"})
+			return
+		}
+		if !updated {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Airline not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    gin.H{"airlineId": c.Param("id"), "percent": req.Percent},
+			"message": "Overbooking updated successfully",
+		})
+	}
+}
+
+// bindOverbooking reads and checks an overbooking request. Otherwise it
+// writes the error response and returns false.
+func bindOverbooking(c *gin.Context) (models.OverbookingRequest, bool) {
+	var req models.OverbookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return req, false
+	}
+	if req.Percent != nil && (*req.Percent < 0 || *req.Percent > booking.MaxOverbookingPercent) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Percent must be between 0 and %d", booking.MaxOverbookingPercent)})
+		return req, false
+	}
+	return req, true
+}
+
+// GetFlightWaitlist returns all waitlist entries of a flight in queue order.
+func GetFlightWaitlist(db database.Database, bookings *booking.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, authorized := checkAdminRole(c, db)
+		if !authorized {
+			return
+		}
+
+		entries, err := bookings.FlightWaitlist(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve waitlist"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":    entries,
+			"count":   len(entries),
+			"message": "Waitlist retrieved successfully",
+		})
+	}
+}
+
+// WaitlistRoutes registers the waitlist of the authenticated user.
+func WaitlistRoutes(router *gin.RouterGroup, db database.Database, bookings *booking.Service) {
+	router.GET("", GetMyWaitlist(bookings))                       // Waitlist entries of the user
+	router.POST("", JoinWaitlist(db, bookings))                   // Queue passengers for a full flight
+	router.POST("/:id/accept", AcceptWaitlistOffer(db, bookings)) // Book the offered seats
+	router.DELETE("/:id", LeaveWaitlist(bookings))                // Leave the queue or decline the offer
+}
+
+// OverbookingRoutes registers the overbooking limits and waitlists of
+// flights and airlines for admins.
+func OverbookingRoutes(router *gin.RouterGroup, db database.Database, bookings *booking.Service) {
+	router.GET("/flights/:id/overbooking", GetFlightOverbooking(db, bookings))
+	router.PUT("/flights/:id/overbooking", SetFlightOverbooking(db, bookings))
+	router.GET("/flights/:id/waitlist", GetFlightWaitlist(db, bookings))
+	router.PUT("/airlines/:id/overbooking", SetAirlineOverbooking(db))
+}
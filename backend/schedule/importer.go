@@ -0,0 +1,280 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mindenairport/aircraft"
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+	"mindenairport/timezone"
+)
+
+// planned is a single operation of an SSIM leg waiting to be imported.
+type planned struct {
+	leg    Leg
+	flight models.Flight
+}
+
+// Importer creates and updates flights from SSIM schedules. Flights are
+// identified by flight number, origin and local date of departure, so
+// importing a revised schedule updates the existing flights.
+type Importer struct {
+	db      database.Database
+	planner *planning.Planner
+
+	planes    map[string][]models.Plane // Active planes by airline
+	byPlane   map[string][]models.Flight
+	localizer *timezone.Localizer
+}
+
+// NewImporter creates an importer that assigns planes with the rotation
+// rules of planner.
+func NewImporter(db database.Database, planner *planning.Planner) *Importer {
+	return &Importer{db: db, planner: planner}
+}
+
+// ImportSSIM expands the legs of the carriers into flights and stores them.
+// Each flight gets a plane of its airline and aircraft type that is free at
+// that time; flights without such a plane, new flights of unknown or
+// deactivated airlines, departed or cancelled flights and legs operated twice
+// on the same day are reported as conflicts and left unchanged. Pilots are not part of SSIM and have to be assigned afterwards.
+// In a dry run nothing is written.
+func (i *Importer) ImportSSIM(carriers []Carrier, dryRun bool) (models.ImportReport, error) {
+	report := models.ImportReport{Kind: "flights", DryRun: dryRun}
+
+	items := i.expand(carriers, &report)
+	if len(items) == 0 {
+		return report, nil
+	}
+	sort.Slice(items, func(a, b int) bool {
+		return items[a].flight.ScheduledDeparture.Before(items[b].flight.ScheduledDeparture)
+	})
+
+	// Load the flights around the schedule for matching and plane rotations
+	first := items[0].flight.ScheduledDeparture.Add(-48 * time.Hour)
+	last := items[len(items)-1].flight.ScheduledDeparture.Add(48 * time.Hour)
+	existing, err := i.db.GetFlightsInWindow(first, last)
+	if err != nil {
+		return report, err
+	}
+
+	i.planes = make(map[string][]models.Plane)
+	i.byPlane = make(map[string][]models.Flight)
+	i.localizer = timezone.NewLocalizer(i.db)
+	byKey := make(map[string]models.Flight)
+	for _, f := range existing {
+		i.byPlane[f.PlaneID] = append(i.byPlane[f.PlaneID], f)
+		if f.FlightNumber != "" {
+			byKey[i.key(f)] = f
+		}
+	}
+
+	imported := make(map[string]bool)
+	for _, item := range items {
+		flight := item.flight
+		name := flight.FlightNumber + " " + localDate(flight, i.localizer)
+		conflict := models.ImportSkip{Line: item.leg.Line, ID: name}
+
+		key := i.key(flight)
+		if imported[key] {
+			conflict.Reason = "flight is scheduled twice on the same day"
+			report.Conflicts = append(report.Conflicts, conflict)
+			continue
+		}
+		imported[key] = true
+
+		old, found := byKey[key]
+		if found {
+			switch {
+			case old.ActualDeparture != nil:
+				conflict.Reason = fmt.Sprintf("flight %s has already departed", old.ID)
+			case old.StatusID == models.FlightStatusCancelled:
+				conflict.Reason = fmt.Sprintf("flight %s is cancelled", old.ID)
+			}
+			if conflict.Reason != "" {
+				report.Conflicts = append(report.Conflicts, conflict)
+				continue
+			}
+
+			// Keep everything SSIM does not know about
+			updated := old
+			updated.To = flight.To
+			updated.ScheduledDeparture = flight.ScheduledDeparture
+			updated.ScheduledArrival = flight.ScheduledArrival
+			flight = updated
+		} else {
+			if err := i.planner.ValidateAirline(item.leg.Airline); err != nil {
+				conflict.Reason = err.Error()
+				report.Conflicts = append(report.Conflicts, conflict)
+				continue
+			}
+			flight.ID = uuid.New().String()
+		}
+
+		planeID, err := i.assignPlane(item.leg, flight, old.PlaneID)
+		if err != nil {
+			return report, err
+		}
+		if planeID == "" {
+			conflict.Reason = fmt.Sprintf("no %s plane of %s is available", item.leg.AircraftType, item.leg.Airline)
+			report.Conflicts = append(report.Conflicts, conflict)
+			continue
+		}
+		flight.PlaneID = planeID
+
+		changes := flightChanges(old, flight)
+		switch {
+		case !found:
+			report.Created = append(report.Created, models.ImportChange{ID: flight.ID, Name: name})
+			if !dryRun {
+				err = i.db.CreateFlight(&flight)
+			}
+		case len(changes) > 0:
+			report.Updated = append(report.Updated, models.ImportChange{ID: flight.ID, Name: name, Changes: changes})
+			if !dryRun {
+				err = i.db.UpdateFlight(flight)
+			}
+		default:
+			report.Unchanged++
+		}
+		if err != nil {
+			return report, fmt.Errorf("flight %s: %w", name, err)
+		}
+
+		i.moveFlight(old, flight)
+	}
+
+	return report, nil
+}
+
+// expand turns the legs of all carriers into flights. Legs with an invalid
+// route or period are added to the report as skipped.
+func (i *Importer) expand(carriers []Carrier, report *models.ImportReport) []planned {
+	var items []planned
+	for _, carrier := range carriers {
+		for _, leg := range carrier.Legs {
+			report.Read++
+			skip := models.ImportSkip{Line: leg.Line, ID: leg.Designator()}
+
+			occurrences, err := leg.Occurrences(carrier.ValidTo)
+			if err != nil {
+				skip.Reason = err.Error()
+				report.Skipped = append(report.Skipped, skip)
+				continue
+			}
+			if len(occurrences) == 0 {
+				report.Ignored++
+				continue
+			}
+
+			var flights []models.Flight
+			for _, o := range occurrences {
+				flights = append(flights, models.Flight{
+					FlightNumber:       leg.Designator(),
+					From:               leg.From,
+					To:                 leg.To,
+					StatusID:           models.FlightStatusScheduled,
+					ScheduledDeparture: o.Departure,
+					ScheduledArrival:   o.Arrival,
+				})
+			}
+
+			// All operations of a leg share route and block time
+			if err := i.planner.ValidateSchedule(flights[0]); err != nil {
+				skip.Reason = err.Error()
+				report.Skipped = append(report.Skipped, skip)
+				continue
+			}
+			for _, f := range flights {
+				items = append(items, planned{leg: leg, flight: f})
+			}
+		}
+	}
+	return items
+}
+
+// key identifies a flight by flight number, origin and local date of departure.
+func (i *Importer) key(f models.Flight) string {
+	return f.FlightNumber + "/" + f.From + "/" + localDate(f, i.localizer)
+}
+
+// assignPlane returns an active plane of the leg's airline and aircraft type
+// that can fly the flight without breaking its rotation, or "" if there is
+// none. The current plane of the flight is kept if possible.
+func (i *Importer) assignPlane(leg Leg, flight models.Flight, current string) (string, error) {
+	wanted, ok := aircraft.Lookup(leg.AircraftType)
+	if !ok {
+		return "", nil
+	}
+
+	planes, ok := i.planes[leg.Airline]
+	if !ok {
+		all, err := i.db.GetPlanes(leg.Airline)
+		if err != nil {
+			return "", err
+		}
+		for _, plane := range all {
+			if t, ok := aircraft.Lookup(plane.Model); ok && plane.Status == "ACTIVE" && t.Name == wanted.Name {
+				planes = append(planes, plane)
+			}
+		}
+		i.planes[leg.Airline] = planes
+	}
+
+	// Try the current plane first
+	sort.SliceStable(planes, func(a, b int) bool { return planes[a].ID == current && planes[b].ID != current })
+	for _, plane := range planes {
+		flight.PlaneID = plane.ID
+		if i.planner.Config.CheckPlaneRotation(flight, i.byPlane[plane.ID]) == nil {
+			return plane.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// moveFlight replaces the old version of a flight in the plane rotations
+// with the imported one.
+func (i *Importer) moveFlight(old, flight models.Flight) {
+	if old.ID != "" {
+		others := i.byPlane[old.PlaneID]
+		for n := range others {
+			if others[n].ID == old.ID {
+				i.byPlane[old.PlaneID] = append(others[:n:n], others[n+1:]...)
+				break
+			}
+		}
+	}
+	i.byPlane[flight.PlaneID] = append(i.byPlane[flight.PlaneID], flight)
+}
+
+// flightChanges lists the scheduled fields that differ between two versions of a flight.
+func flightChanges(old, updated models.Flight) []models.FieldChange {
+	var changes []models.FieldChange
+	add := func(field, o, n string) {
+		if o != n {
+			changes = append(changes, models.FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+	add("flightNumber", old.FlightNumber, updated.FlightNumber)
+	add("from", old.From, updated.From)
+	add("to", old.To, updated.To)
+	add("scheduledDeparture", formatTime(old.ScheduledDeparture), formatTime(updated.ScheduledDeparture))
+	add("scheduledArrival", formatTime(old.ScheduledArrival), formatTime(updated.ScheduledArrival))
+	add("planeId", old.PlaneID, updated.PlaneID)
+	add("terminalId", old.TerminalID, updated.TerminalID)
+	add("gate", old.Gate, updated.Gate)
+	return changes
+}
+
+// formatTime formats a flight time in UTC, leaving unset times empty.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
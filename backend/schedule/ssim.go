@@ -0,0 +1,369 @@
+// Package schedule turns airline schedules into individual flights. Schedules
+// are either imported from IATA SSIM files or defined as recurring templates.
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSIM Chapter 7 files consist of fixed-width records of 200 characters. The
+// first character is the record type.
+const (
+	ssimRecordLength = 200
+
+	recordHeader  = '1'
+	recordCarrier = '2'
+	recordLeg     = '3'
+	recordSegment = '4'
+	recordTrailer = '5'
+)
+
+// openDate marks an open end of a period in SSIM ("00XXX00").
+const openDate = "00XXX00"
+
+// Carrier is an SSIM type 2 record with the flight legs that follow it.
+type Carrier struct {
+	Airline   string
+	UTC       bool // Times are in UTC (time mode U) instead of local time (L)
+	Season    string
+	ValidFrom time.Time
+	ValidTo   time.Time // Zero if the schedule is open-ended
+	Legs      []Leg
+}
+
+// Leg is an SSIM type 3 record: one leg of a flight operated on the days of
+// a period. Times are minutes after midnight of the date of operation.
+type Leg struct {
+	Line         int
+	Airline      string
+	FlightNumber int
+	Suffix       string // Operational suffix, e.g. "A" for a rescheduled flight
+	Variation    string // Itinerary variation identifier
+	Sequence     int    // Leg sequence number within the itinerary
+	ServiceType  string
+
+	PeriodFrom  time.Time
+	PeriodTo    time.Time // Zero if the period is open-ended
+	Days        [7]bool   // Monday first
+	Fortnightly bool      // Operates every second week only
+
+	From              string
+	Departure         int // Passenger departure time
+	DepartureOffset   int // UTC offset at the departure station in minutes
+	DepartureTerminal string
+	To                string
+	Arrival           int // Passenger arrival time
+	ArrivalOffset     int // UTC offset at the arrival station in minutes
+	ArrivalTerminal   string
+	AircraftType      string // IATA aircraft type code, e.g. "320"
+
+	DepartureDays int // Date variation of the departure relative to the date of operation
+	ArrivalDays   int // Date variation of the arrival relative to the date of operation
+
+	Segments []Segment
+}
+
+// Segment is an SSIM type 4 record with additional data of a leg or of a
+// segment between two of its stations.
+type Segment struct {
+	Board   string
+	Off     string
+	Element int // Data element identifier (DEI)
+	Data    string
+}
+
+// Designator returns the flight number as shown to passengers, e.g. "LH123".
+func (l Leg) Designator() string {
+	return l.Airline + strconv.Itoa(l.FlightNumber) + l.Suffix
+}
+
+// Occurrence is a single operation of a leg.
+type Occurrence struct {
+	Date      time.Time // Date of operation
+	Departure time.Time // Scheduled departure in UTC
+	Arrival   time.Time // Scheduled arrival in UTC
+}
+
+// Occurrences expands the period and days of operation of a leg into its
+// single operations. Open-ended periods end at until, usually the end of the
+// carrier's schedule validity.
+func (l Leg) Occurrences(until time.Time) ([]Occurrence, error) {
+	end := l.PeriodTo
+	if end.IsZero() {
+		end = until
+	}
+	if end.IsZero() {
+		return nil, fmt.Errorf("flight %s has an open period of operation", l.Designator())
+	}
+
+	var occurrences []Occurrence
+	for day := 0; !l.PeriodFrom.AddDate(0, 0, day).After(end); day++ {
+		date := l.PeriodFrom.AddDate(0, 0, day)
+		if !l.Days[(int(date.Weekday())+6)%7] || (l.Fortnightly && day/7%2 == 1) {
+			continue
+		}
+		occurrences = append(occurrences, Occurrence{
+			Date:      date,
+			Departure: l.instant(date, l.DepartureDays, l.Departure, l.DepartureOffset),
+			Arrival:   l.instant(date, l.ArrivalDays, l.Arrival, l.ArrivalOffset),
+		})
+	}
+	return occurrences, nil
+}
+
+// instant converts a time of a leg into UTC. Dates are parsed as UTC
+// midnight, so subtracting the UTC variation yields the instant.
+func (l Leg) instant(date time.Time, days, minutes, offset int) time.Time {
+	return date.AddDate(0, 0, days).Add(time.Duration(minutes-offset) * time.Minute)
+}
+
+// ParseSSIM reads an SSIM Chapter 7 file. Header and trailer records are
+// checked for their type only; legs are grouped by the carrier record that
+// precedes them and type 4 records are attached to the preceding leg.
+func ParseSSIM(r io.Reader) ([]Carrier, error) {
+	var carriers []Carrier
+	var utc bool
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(record) == "" || strings.Trim(record, "0") == "" {
+			// Blank lines and the zero-filled padding records between sections
+			continue
+		}
+		if len(record) < ssimRecordLength {
+			record += strings.Repeat(" ", ssimRecordLength-len(record))
+		}
+
+		switch record[0] {
+		case recordHeader, recordTrailer:
+		case recordCarrier:
+			carrier, err := parseCarrier(record)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			utc = carrier.UTC
+			carriers = append(carriers, carrier)
+		case recordLeg:
+			if len(carriers) == 0 {
+				return nil, fmt.Errorf("line %d: flight leg before carrier record", line)
+			}
+			leg, err := parseLeg(record, utc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			leg.Line = line
+			c := &carriers[len(carriers)-1]
+			c.Legs = append(c.Legs, leg)
+		case recordSegment:
+			if len(carriers) == 0 || len(carriers[len(carriers)-1].Legs) == 0 {
+				return nil, fmt.Errorf("line %d: segment data before flight leg", line)
+			}
+			c := &carriers[len(carriers)-1]
+			segment, err := parseSegment(record)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			leg := &c.Legs[len(c.Legs)-1]
+			leg.Segments = append(leg.Segments, segment)
+		default:
+			return nil, fmt.Errorf("line %d: unknown record type %q", line, record[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return carriers, nil
+}
+
+// ReadSSIMFile parses an SSIM file.
+func ReadSSIMFile(path string) ([]Carrier, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	carriers, err := ParseSSIM(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return carriers, nil
+}
+
+// field returns the characters from position start to end (1-based,
+// inclusive, as in the SSIM manual) without surrounding blanks.
+func field(record string, start, end int) string {
+	return strings.TrimSpace(record[start-1 : end])
+}
+
+// parseCarrier parses a type 2 record.
+func parseCarrier(record string) (Carrier, error) {
+	carrier := Carrier{
+		Airline: field(record, 3, 5),
+		Season:  field(record, 11, 13),
+	}
+
+	switch record[1] {
+	case 'U':
+		carrier.UTC = true
+	case 'L':
+	default:
+		return carrier, fmt.Errorf("invalid time mode %q", record[1])
+	}
+
+	var err error
+	if carrier.ValidFrom, err = parseDate(field(record, 15, 21)); err != nil {
+		return carrier, err
+	}
+	if carrier.ValidTo, err = parseDate(field(record, 22, 28)); err != nil {
+		return carrier, err
+	}
+	return carrier, nil
+}
+
+// parseLeg parses a type 3 record. In UTC mode the time variations are
+// ignored, since the times are already in UTC.
+func parseLeg(record string, utc bool) (Leg, error) {
+	leg := Leg{
+		Suffix:            field(record, 2, 2),
+		Airline:           field(record, 3, 5),
+		Variation:         field(record, 10, 11),
+		ServiceType:       field(record, 14, 14),
+		From:              field(record, 37, 39),
+		DepartureTerminal: field(record, 53, 54),
+		To:                field(record, 55, 57),
+		ArrivalTerminal:   field(record, 71, 72),
+		AircraftType:      field(record, 73, 75),
+		Fortnightly:       field(record, 36, 36) == "2",
+	}
+
+	var err error
+	if leg.FlightNumber, err = strconv.Atoi(field(record, 6, 9)); err != nil {
+		return leg, fmt.Errorf("invalid flight number %q", field(record, 6, 9))
+	}
+	if leg.Sequence, err = strconv.Atoi(field(record, 12, 13)); err != nil {
+		return leg, fmt.Errorf("invalid leg sequence number %q", field(record, 12, 13))
+	}
+	if leg.PeriodFrom, err = parseDate(field(record, 15, 21)); err != nil {
+		return leg, err
+	}
+	if leg.PeriodFrom.IsZero() {
+		return leg, fmt.Errorf("period of operation has no start")
+	}
+	if leg.PeriodTo, err = parseDate(field(record, 22, 28)); err != nil {
+		return leg, err
+	}
+
+	days := record[28:35]
+	for i := 0; i < 7; i++ {
+		switch days[i] {
+		case byte('1' + i):
+			leg.Days[i] = true
+		case ' ':
+		default:
+			return leg, fmt.Errorf("invalid days of operation %q", days)
+		}
+	}
+
+	if leg.Departure, err = parseClock(field(record, 40, 43)); err != nil {
+		return leg, err
+	}
+	if leg.Arrival, err = parseClock(field(record, 62, 65)); err != nil {
+		return leg, err
+	}
+	if !utc {
+		if leg.DepartureOffset, err = parseOffset(field(record, 48, 52)); err != nil {
+			return leg, err
+		}
+		if leg.ArrivalOffset, err = parseOffset(field(record, 66, 70)); err != nil {
+			return leg, err
+		}
+	}
+	if leg.DepartureDays, err = parseDateVariation(record[192]); err != nil {
+		return leg, err
+	}
+	if leg.ArrivalDays, err = parseDateVariation(record[193]); err != nil {
+		return leg, err
+	}
+
+	return leg, nil
+}
+
+// parseSegment parses a type 4 record.
+func parseSegment(record string) (Segment, error) {
+	element, err := strconv.Atoi(field(record, 31, 33))
+	if err != nil {
+		return Segment{}, fmt.Errorf("invalid data element identifier %q", field(record, 31, 33))
+	}
+	return Segment{
+		Board:   field(record, 34, 36),
+		Off:     field(record, 37, 39),
+		Element: element,
+		Data:    field(record, 40, 194),
+	}, nil
+}
+
+// parseDate parses an SSIM date like "26OCT25". Open dates return the zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" || value == openDate {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("02Jan06", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return t, nil
+}
+
+// parseClock parses a time like "0715" into minutes after midnight. "2400"
+// is accepted as the end of the day.
+func parseClock(value string) (int, error) {
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	h, errH := strconv.Atoi(value[:2])
+	m, errM := strconv.Atoi(value[2:])
+	if errH != nil || errM != nil || h > 24 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return h*60 + m, nil
+}
+
+// parseOffset parses a UTC time variation like "+0100" into minutes.
+func parseOffset(value string) (int, error) {
+	if len(value) != 5 || (value[0] != '+' && value[0] != '-') {
+		return 0, fmt.Errorf("invalid UTC time variation %q", value)
+	}
+	minutes, err := parseClock(value[1:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid UTC time variation %q", value)
+	}
+	if value[0] == '-' {
+		minutes = -minutes
+	}
+	return minutes, nil
+}
+
+// parseDateVariation parses a date variation: a digit for days after the
+// date of operation or "A" for the day before.
+func parseDateVariation(c byte) (int, error) {
+	switch {
+	case c == ' ':
+		return 0, nil
+	case c == 'A':
+		return -1, nil
+	case c >= '0' && c <= '9':
+		return int(c - '0'), nil
+	}
+	return 0, fmt.Errorf("invalid date variation %q", c)
+}
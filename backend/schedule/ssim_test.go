@@ -0,0 +1,261 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ssimRecord builds a 200 character record from values at 1-based positions.
+func ssimRecord(values map[int]string) string {
+	record := []byte(strings.Repeat(" ", ssimRecordLength))
+	for pos, value := range values {
+		copy(record[pos-1:], value)
+	}
+	return string(record)
+}
+
+// carrierRecord returns a local time carrier record of LH for winter 2025.
+func carrierRecord(overrides map[int]string) string {
+	values := map[int]string{1: "2", 2: "L", 3: "LH ", 11: "W25", 15: "26OCT25", 22: "28MAR26"}
+	for pos, value := range overrides {
+		values[pos] = value
+	}
+	return ssimRecord(values)
+}
+
+// legRecord returns a leg of LH123 from FRA to JFK on Mondays, Wednesdays
+// and Fridays in November 2025, departing 07:15 (+0100) and arriving 10:05
+// (-0500) on the same day.
+func legRecord(overrides map[int]string) string {
+	values := map[int]string{
+		1: "3", 3: "LH ", 6: "0123", 10: "01", 12: "01", 14: "J",
+		15: "03NOV25", 22: "30NOV25", 29: "1 3 5  ",
+		37: "FRA", 40: "0715", 44: "0715", 48: "+0100", 53: "1 ",
+		55: "JFK", 58: "1005", 62: "1005", 66: "-0500", 71: "4 ", 73: "744",
+	}
+	for pos, value := range overrides {
+		values[pos] = value
+	}
+	return ssimRecord(values)
+}
+
+func segmentRecord() string {
+	return ssimRecord(map[int]string{1: "4", 3: "LH ", 6: "0123", 31: "010", 34: "FRA", 37: "JFK", 40: "UA 9123"})
+}
+
+func TestParseSSIM(t *testing.T) {
+	input := strings.Join([]string{
+		ssimRecord(map[int]string{1: "1", 2: "Airline Standard Schedule Data Set"}),
+		strings.Repeat("0", ssimRecordLength),
+		carrierRecord(nil),
+		legRecord(map[int]string{194: "1"}),
+		segmentRecord(),
+		"",
+		ssimRecord(map[int]string{1: "5", 3: "LH "}),
+	}, "\r\n")
+
+	carriers, err := ParseSSIM(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSSIM() error = %v", err)
+	}
+	if len(carriers) != 1 || len(carriers[0].Legs) != 1 {
+		t.Fatalf("ParseSSIM() = %+v, want one carrier with one leg", carriers)
+	}
+
+	carrier := carriers[0]
+	if carrier.Airline != "LH" || carrier.UTC || carrier.Season != "W25" {
+		t.Errorf("carrier = %+v", carrier)
+	}
+	if want := time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC); !carrier.ValidTo.Equal(want) {
+		t.Errorf("carrier.ValidTo = %v, want %v", carrier.ValidTo, want)
+	}
+
+	leg := carrier.Legs[0]
+	if leg.Line != 4 {
+		t.Errorf("leg.Line = %d, want 4", leg.Line)
+	}
+	if leg.Designator() != "LH123" || leg.From != "FRA" || leg.To != "JFK" || leg.AircraftType != "744" {
+		t.Errorf("leg = %s %s-%s %s", leg.Designator(), leg.From, leg.To, leg.AircraftType)
+	}
+	if want := [7]bool{true, false, true, false, true, false, false}; leg.Days != want {
+		t.Errorf("leg.Days = %v, want %v", leg.Days, want)
+	}
+	if leg.Departure != 7*60+15 || leg.DepartureOffset != 60 || leg.Arrival != 10*60+5 || leg.ArrivalOffset != -300 {
+		t.Errorf("leg times = %d%+d / %d%+d", leg.Departure, leg.DepartureOffset, leg.Arrival, leg.ArrivalOffset)
+	}
+	if leg.DepartureTerminal != "1" || leg.ArrivalTerminal != "4" || leg.ArrivalDays != 1 {
+		t.Errorf("leg = %+v", leg)
+	}
+	if len(leg.Segments) != 1 || leg.Segments[0].Element != 10 || leg.Segments[0].Data != "UA 9123" {
+		t.Errorf("leg.Segments = %+v", leg.Segments)
+	}
+}
+
+func TestParseSSIMIgnoresOffsetsInUTCMode(t *testing.T) {
+	input := carrierRecord(map[int]string{2: "U"}) + "\n" + legRecord(map[int]string{48: "XXXXX"})
+
+	carriers, err := ParseSSIM(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSSIM() error = %v", err)
+	}
+	if leg := carriers[0].Legs[0]; leg.DepartureOffset != 0 || leg.ArrivalOffset != 0 {
+		t.Errorf("offsets = %d, %d, want 0 in UTC mode", leg.DepartureOffset, leg.ArrivalOffset)
+	}
+}
+
+func TestParseSSIMMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []string
+		wantErr string
+	}{
+		{"leg before carrier", []string{legRecord(nil)}, "line 1: flight leg before carrier record"},
+		{"segment before leg", []string{carrierRecord(nil), segmentRecord()}, "line 2: segment data before flight leg"},
+		{"unknown record type", []string{ssimRecord(map[int]string{1: "9"})}, "unknown record type"},
+		{"invalid time mode", []string{carrierRecord(map[int]string{2: "X"})}, "invalid time mode"},
+		{"invalid validity date", []string{carrierRecord(map[int]string{15: "31FEB25"})}, `invalid date "31FEB25"`},
+		{"invalid flight number", []string{carrierRecord(nil), legRecord(map[int]string{6: "12AB"})}, "invalid flight number"},
+		{"invalid leg sequence", []string{carrierRecord(nil), legRecord(map[int]string{12: "  "})}, "invalid leg sequence number"},
+		{"period without start", []string{carrierRecord(nil), legRecord(map[int]string{15: openDate})}, "period of operation has no start"},
+		{"day out of place", []string{carrierRecord(nil), legRecord(map[int]string{29: "2      "})}, "invalid days of operation"},
+		{"minutes out of range", []string{carrierRecord(nil), legRecord(map[int]string{40: "0760"})}, `invalid time "0760"`},
+		{"after end of day", []string{carrierRecord(nil), legRecord(map[int]string{62: "2401"})}, `invalid time "2401"`},
+		{"offset without sign", []string{carrierRecord(nil), legRecord(map[int]string{48: "01000"})}, "invalid UTC time variation"},
+		{"invalid date variation", []string{carrierRecord(nil), legRecord(map[int]string{193: "B"})}, "invalid date variation"},
+		{"invalid data element", []string{carrierRecord(nil), legRecord(nil), ssimRecord(map[int]string{1: "4", 31: "X10"})}, "invalid data element identifier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSSIM(strings.NewReader(strings.Join(tt.records, "\n")))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSSIM() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"0000", 0, false},
+		{"0715", 435, false},
+		{"2359", 1439, false},
+		{"2400", 1440, false},
+		{"2401", 0, true},
+		{"2500", 0, true},
+		{"0060", 0, true},
+		{"715", 0, true},
+		{"07:15", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseClock(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseClock(%q) = %d, %v, want %d (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"+0000", 0, false},
+		{"+0100", 60, false},
+		{"-0530", -330, false},
+		{"+1400", 840, false},
+		{"0100", 0, true},
+		{"+01", 0, true},
+		{"*0100", 0, true},
+		{"+0160", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseOffset(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseOffset(%q) = %d, %v, want %d (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLegOccurrences(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2025, 11, day, 0, 0, 0, 0, time.UTC) }
+	base := Leg{
+		Airline: "LH", FlightNumber: 123,
+		PeriodFrom: date(3), PeriodTo: date(16), // Monday to Sunday two weeks later
+		Days:      [7]bool{true, false, true, false, true, false, false},
+		Departure: 7*60 + 15, DepartureOffset: 60,
+		Arrival: 10*60 + 5, ArrivalOffset: -300,
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(l *Leg)
+		until     time.Time
+		wantDates []int
+		wantErr   bool
+	}{
+		{"days of operation", func(l *Leg) {}, time.Time{}, []int{3, 5, 7, 10, 12, 14}, false},
+		{"period end is inclusive", func(l *Leg) { l.PeriodTo = date(10) }, time.Time{}, []int{3, 5, 7, 10}, false},
+		{"single day period", func(l *Leg) { l.PeriodTo = date(3) }, time.Time{}, []int{3}, false},
+		{"fortnightly", func(l *Leg) { l.Fortnightly = true }, time.Time{}, []int{3, 5, 7}, false},
+		{"open period ends at until", func(l *Leg) { l.PeriodTo = time.Time{} }, date(5), []int{3, 5}, false},
+		{"open period without until", func(l *Leg) { l.PeriodTo = time.Time{} }, time.Time{}, nil, true},
+		{"period ends before it starts", func(l *Leg) { l.PeriodTo = date(2) }, time.Time{}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leg := base
+			tt.modify(&leg)
+			occurrences, err := leg.Occurrences(tt.until)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Occurrences() error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(occurrences) != len(tt.wantDates) {
+				t.Fatalf("Occurrences() returned %d operations, want %d", len(occurrences), len(tt.wantDates))
+			}
+			for i, o := range occurrences {
+				if !o.Date.Equal(date(tt.wantDates[i])) {
+					t.Errorf("occurrence %d on %v, want %v", i, o.Date, date(tt.wantDates[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestLegOccurrenceTimesInUTC(t *testing.T) {
+	operation := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	leg := Leg{
+		PeriodFrom: operation, PeriodTo: operation,
+		Days:      [7]bool{true, true, true, true, true, true, true},
+		Departure: 22*60 + 30, DepartureOffset: 60,
+		Arrival: 30, ArrivalOffset: -300, ArrivalDays: 1,
+	}
+
+	occurrences, err := leg.Occurrences(time.Time{})
+	if err != nil || len(occurrences) != 1 {
+		t.Fatalf("Occurrences() = %v, %v", occurrences, err)
+	}
+	if want := time.Date(2025, 11, 3, 21, 30, 0, 0, time.UTC); !occurrences[0].Departure.Equal(want) {
+		t.Errorf("Departure = %v, want %v", occurrences[0].Departure, want)
+	}
+	if want := time.Date(2025, 11, 4, 5, 30, 0, 0, time.UTC); !occurrences[0].Arrival.Equal(want) {
+		t.Errorf("Arrival = %v, want %v", occurrences[0].Arrival, want)
+	}
+
+	// A departure shortly after midnight UTC that is still the day before locally
+	leg.Departure, leg.DepartureOffset, leg.DepartureDays = 23*60+50, -60, -1
+	occurrences, _ = leg.Occurrences(time.Time{})
+	if want := time.Date(2025, 11, 3, 0, 50, 0, 0, time.UTC); !occurrences[0].Departure.Equal(want) {
+		t.Errorf("Departure with date variation A = %v, want %v", occurrences[0].Departure, want)
+	}
+}
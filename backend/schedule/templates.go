@@ -0,0 +1,384 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+	"mindenairport/planning"
+	"mindenairport/timezone"
+)
+
+// dateLayout formats the local date of departure of template flights.
+const dateLayout = "2006-01-02"
+
+// Generator materializes the flights of schedule templates. Each flight
+// records the template revision it follows. Unbooked flights are changed
+// along with their template; booked flights keep their schedule and are
+// reported as outdated until an admin applies the template to them or keeps
+// them as they are. Departed and cancelled flights are never touched.
+type Generator struct {
+	db      database.Database
+	planner *planning.Planner
+}
+
+// NewGenerator creates a generator that checks new and changed flights with
+// the rules of planner.
+func NewGenerator(db database.Database, planner *planning.Planner) *Generator {
+	return &Generator{db: db, planner: planner}
+}
+
+// Validate checks that a template can generate flights: its plane exists,
+// it operates on at least one day of its validity and its first flight
+// passes the schedule check, which requires both airports to have a valid
+// time zone.
+func (g *Generator) Validate(template models.ScheduleTemplate) error {
+	plane, err := g.db.GetPlaneByID(template.PlaneID)
+	if err != nil {
+		return err
+	}
+	if plane == nil {
+		return &planning.ValidationError{Reason: fmt.Sprintf("plane %s does not exist", template.PlaneID)}
+	}
+
+	localizer := timezone.NewLocalizer(g.db)
+	origin, destination := localizer.Location(template.From), localizer.Location(template.To)
+	for date := dateOf(template.ValidFrom); !date.After(dateOf(template.ValidTo)); date = date.AddDate(0, 0, 1) {
+		if !operatesOn(template, date) {
+			continue
+		}
+		flight, err := flightOn(template, date, origin, destination)
+		if err != nil {
+			continue
+		}
+		return g.planner.ValidateSchedule(flight)
+	}
+	return &planning.ValidationError{Reason: "template does not operate on any day of its validity"}
+}
+
+// Sync brings the flights of a template departing from now until until in
+// line with its current revision. Missing flights are created; flights that
+// cannot be created, e.g. because the plane is busy, are reported as
+// conflicts and retried on the next run. Flights that already follow the
+// current revision are left alone, so manual changes to single flights are
+// kept.
+func (g *Generator) Sync(template models.ScheduleTemplate, now, until time.Time) (models.ScheduleSync, error) {
+	sync := newSync(template, until)
+
+	localizer := timezone.NewLocalizer(g.db)
+	origin, destination := localizer.Location(template.From), localizer.Location(template.To)
+	if origin == nil || destination == nil {
+		return sync, fmt.Errorf("template %s: airports %s and %s need valid time zones", template.ID, template.From, template.To)
+	}
+
+	existing, err := g.db.GetTemplateFlights(template.ID, now)
+	if err != nil {
+		return sync, err
+	}
+
+	// Flights generated earlier with a longer horizon are kept in sync as well
+	last := until
+	byDate := make(map[string]models.TemplateFlight)
+	var unmatched []models.TemplateFlight
+	for _, f := range existing {
+		date := localDate(f.Flight, localizer)
+		if _, ok := byDate[date]; ok {
+			unmatched = append(unmatched, f)
+			continue
+		}
+		byDate[date] = f
+		if f.ScheduledDeparture.After(last) {
+			last = f.ScheduledDeparture
+		}
+	}
+
+	for date := dateOf(now.In(origin)); !date.After(dateOf(last.In(origin))); date = date.AddDate(0, 0, 1) {
+		if date.Before(dateOf(template.ValidFrom)) || date.After(dateOf(template.ValidTo)) || !operatesOn(template, date) {
+			continue
+		}
+		day := date.Format(dateLayout)
+
+		planned, err := flightOn(template, date, origin, destination)
+		if err != nil {
+			sync.Conflicts = append(sync.Conflicts, models.ScheduleConflict{Date: day, Reason: err.Error()})
+			continue
+		}
+
+		current, found := byDate[day]
+		delete(byDate, day)
+		if !found {
+			if planned.ScheduledDeparture.Before(now) || !planned.ScheduledDeparture.Before(until) {
+				continue
+			}
+			err = g.planner.ValidateImportedFlight(planned)
+			if err == nil {
+				err = g.planner.ValidateFlightAirline(planned)
+			}
+			if err != nil {
+				if !isPlanningError(err) {
+					return sync, err
+				}
+				sync.Conflicts = append(sync.Conflicts, models.ScheduleConflict{Date: day, Reason: err.Error()})
+				continue
+			}
+			if err := g.db.CreateFlight(&planned); err != nil {
+				return sync, fmt.Errorf("flight %s on %s: %w", template.FlightNumber, day, err)
+			}
+			sync.Created = append(sync.Created, planned.ID)
+			continue
+		}
+
+		if !IsOutdated(current, template) {
+			continue
+		}
+
+		updated := applyTemplate(current.Flight, planned)
+		changes := flightChanges(current.Flight, updated)
+
+		switch {
+		case len(changes) == 0:
+			err = g.db.SetFlightTemplateRevision(current.ID, template.Revision)
+		case current.Tickets > 0:
+			sync.Outdated = append(sync.Outdated, models.OutdatedFlight{
+				FlightID: current.ID, Date: day, Tickets: current.Tickets, Changes: changes})
+		default:
+			if err := g.planner.ValidateImportedFlight(updated); err != nil {
+				if !isPlanningError(err) {
+					return sync, err
+				}
+				sync.Conflicts = append(sync.Conflicts, models.ScheduleConflict{Date: day, Reason: err.Error()})
+				continue
+			}
+			if err = g.db.UpdateFlight(updated); err == nil {
+				err = g.db.SetFlightTemplateRevision(current.ID, template.Revision)
+			}
+			sync.Updated = append(sync.Updated, current.ID)
+		}
+		if err != nil {
+			return sync, fmt.Errorf("flight %s: %w", current.ID, err)
+		}
+	}
+
+	// The remaining flights are no longer scheduled by the template
+	for _, f := range byDate {
+		unmatched = append(unmatched, f)
+	}
+	for _, f := range unmatched {
+		if !IsOutdated(f, template) {
+			continue
+		}
+		if err := g.withdraw(f, localDate(f.Flight, localizer), &sync); err != nil {
+			return sync, err
+		}
+	}
+
+	return sync, nil
+}
+
+// Remove withdraws the flights of a template departing from now on before
+// the template is deleted. Unbooked flights are removed; booked flights are
+// reported as outdated and have to be cancelled by an admin.
+func (g *Generator) Remove(template models.ScheduleTemplate, now time.Time) (models.ScheduleSync, error) {
+	sync := newSync(template, time.Time{})
+
+	flights, err := g.db.GetTemplateFlights(template.ID, now)
+	if err != nil {
+		return sync, err
+	}
+
+	localizer := timezone.NewLocalizer(g.db)
+	for _, f := range flights {
+		if err := g.withdraw(f, localDate(f.Flight, localizer), &sync); err != nil {
+			return sync, err
+		}
+	}
+	return sync, nil
+}
+
+// withdraw removes a flight that its template no longer schedules. Flights
+// that cannot be deleted because of cancelled tickets or baggage records are
+// cancelled instead. Booked flights are only reported.
+func (g *Generator) withdraw(f models.TemplateFlight, date string, sync *models.ScheduleSync) error {
+	if f.ActualDeparture != nil || f.StatusID == models.FlightStatusCancelled {
+		return nil
+	}
+	if f.Tickets > 0 {
+		sync.Outdated = append(sync.Outdated, models.OutdatedFlight{
+			FlightID: f.ID, Date: date, Tickets: f.Tickets, Cancelled: true})
+		return nil
+	}
+
+	deleted, err := g.db.DeleteUnbookedFlight(f.ID)
+	if err == nil && !deleted {
+		cancelled := f.Flight
+		cancelled.StatusID = models.FlightStatusCancelled
+		err = g.db.UpdateFlight(cancelled)
+	}
+	if err != nil {
+		return fmt.Errorf("flight %s: %w", f.ID, err)
+	}
+
+	sync.Removed = append(sync.Removed, f.ID)
+	return nil
+}
+
+// Apply changes an outdated flight to the current revision of its template,
+// whether it is booked or not. Flights the template no longer schedules are
+// cancelled. It returns the updated flight, which has to be announced to
+// the passengers by the caller.
+func (g *Generator) Apply(template models.ScheduleTemplate, flight models.Flight) (models.Flight, error) {
+	localizer := timezone.NewLocalizer(g.db)
+	origin, destination := localizer.Location(template.From), localizer.Location(template.To)
+	if origin == nil || destination == nil {
+		return flight, &planning.ValidationError{Reason: "template airports need valid time zones"}
+	}
+
+	date, _ := time.Parse(dateLayout, localDate(flight, localizer))
+	updated := flight
+	if date.Before(dateOf(template.ValidFrom)) || date.After(dateOf(template.ValidTo)) || !operatesOn(template, date) {
+		updated.StatusID = models.FlightStatusCancelled
+	} else {
+		planned, err := flightOn(template, date, origin, destination)
+		if err != nil {
+			return flight, &planning.ValidationError{Reason: err.Error()}
+		}
+		updated = applyTemplate(flight, planned)
+		if err := g.planner.ValidateImportedFlight(updated); err != nil {
+			return flight, err
+		}
+	}
+
+	if err := g.db.UpdateFlight(updated); err != nil {
+		return flight, err
+	}
+	if err := g.db.SetFlightTemplateRevision(flight.ID, template.Revision); err != nil {
+		return flight, err
+	}
+	updated.TemplateRevision = template.Revision
+	return updated, nil
+}
+
+// newSync creates an empty sync report of a template.
+func newSync(template models.ScheduleTemplate, until time.Time) models.ScheduleSync {
+	return models.ScheduleSync{
+		TemplateID:   template.ID,
+		FlightNumber: template.FlightNumber,
+		Revision:     template.Revision,
+		Until:        until,
+		Created:      []string{},
+		Updated:      []string{},
+		Removed:      []string{},
+		Outdated:     []models.OutdatedFlight{},
+		Conflicts:    []models.ScheduleConflict{},
+	}
+}
+
+// IsOutdated reports whether a flight does not follow the current revision
+// of its template and may still be changed. Departed and cancelled flights
+// keep their schedule and are never outdated.
+func IsOutdated(f models.TemplateFlight, template models.ScheduleTemplate) bool {
+	return f.TemplateRevision < template.Revision && f.ActualDeparture == nil && f.StatusID != models.FlightStatusCancelled
+}
+
+// applyTemplate returns the flight with the schedule of planned. The terminal
+// and gate of the template are defaults: flights keep theirs if the template
+// has none.
+func applyTemplate(flight, planned models.Flight) models.Flight {
+	flight.FlightNumber = planned.FlightNumber
+	flight.From = planned.From
+	flight.To = planned.To
+	flight.PlaneID = planned.PlaneID
+	flight.ScheduledDeparture = planned.ScheduledDeparture
+	flight.ScheduledArrival = planned.ScheduledArrival
+	if planned.TerminalID != "" {
+		flight.TerminalID = planned.TerminalID
+	}
+	if planned.Gate != "" {
+		flight.Gate = planned.Gate
+	}
+	return flight
+}
+
+// flightOn returns the flight of a template departing on the given local
+// date. Times that do not exist because of a daylight saving time change are
+// rejected.
+func flightOn(template models.ScheduleTemplate, date time.Time, origin, destination *time.Location) (models.Flight, error) {
+	depHour, depMinute, err := parseTime(template.DepartureTime)
+	if err != nil {
+		return models.Flight{}, err
+	}
+	arrHour, arrMinute, err := parseTime(template.ArrivalTime)
+	if err != nil {
+		return models.Flight{}, err
+	}
+
+	departure, err := timezone.Date(date.Year(), date.Month(), date.Day(), depHour, depMinute, origin)
+	if err != nil {
+		return models.Flight{}, err
+	}
+	arrivalDate := date.AddDate(0, 0, template.ArrivalDayOffset)
+	arrival, err := timezone.Date(arrivalDate.Year(), arrivalDate.Month(), arrivalDate.Day(), arrHour, arrMinute, destination)
+	if err != nil {
+		return models.Flight{}, err
+	}
+
+	return models.Flight{
+		FlightNumber:       template.FlightNumber,
+		From:               template.From,
+		To:                 template.To,
+		PlaneID:            template.PlaneID,
+		TerminalID:         template.TerminalID,
+		Gate:               template.Gate,
+		StatusID:           models.FlightStatusScheduled,
+		ScheduledDeparture: departure,
+		ScheduledArrival:   arrival,
+		TemplateID:         template.ID,
+		TemplateRevision:   template.Revision,
+	}, nil
+}
+
+// operatesOn reports whether the template schedules a flight on the weekday of date.
+func operatesOn(template models.ScheduleTemplate, date time.Time) bool {
+	day := int(date.Weekday())
+	if day == 0 {
+		day = 7
+	}
+	return strings.Contains(template.DaysOfWeek, strconv.Itoa(day))
+}
+
+// dateOf returns the calendar date of t as midnight UTC, so that dates from
+// different time zones can be compared and iterated.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// localDate returns the date of departure of a flight at its origin.
+func localDate(f models.Flight, localizer *timezone.Localizer) string {
+	departure := f.ScheduledDeparture
+	if loc := localizer.Location(f.From); loc != nil {
+		departure = departure.In(loc)
+	}
+	return departure.Format(dateLayout)
+}
+
+// parseTime parses a template time like "07:15".
+func parseTime(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// isPlanningError reports whether err is a rule violation of the planner
+// rather than a database error.
+func isPlanningError(err error) bool {
+	var validationErr *planning.ValidationError
+	var conflictErr *planning.ConflictError
+	var occupiedErr *planning.OccupiedError
+	return errors.As(err, &validationErr) || errors.As(err, &conflictErr) || errors.As(err, &occupiedErr)
+}
@@ -0,0 +1,138 @@
+// Package timezone converts flight times between UTC, in which they are
+// stored, and the local time at the airports. Airports store their time zone
+// as IANA name (e.g. "Europe/Berlin"), so daylight saving time is taken into
+// account by Go's time zone database.
+package timezone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mindenairport/database"
+	"mindenairport/models"
+)
+
+var (
+	// ErrInvalidTimezone is returned for time zone names that are not IANA names.
+	ErrInvalidTimezone = errors.New("invalid time zone")
+	// ErrNonexistentTime is returned for local times skipped by a daylight saving time change.
+	ErrNonexistentTime = errors.New("local time does not exist")
+)
+
+// Load returns the location of an IANA time zone name such as "Europe/Berlin"
+// or "UTC". Fixed offsets like "UTC+1" are rejected because they ignore
+// daylight saving time, as are "" and "Local", which depend on the server.
+func Load(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimezone, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimezone, name)
+	}
+	return loc, nil
+}
+
+// AirportLocation returns the location of an airport's time zone.
+func AirportLocation(airport models.Airport) (*time.Location, error) {
+	loc, err := Load(airport.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("airport %s: %w", airport.ID, err)
+	}
+	return loc, nil
+}
+
+// At returns an instant together with its local time in loc.
+func At(t time.Time, loc *time.Location) models.LocalTime {
+	return models.LocalTime{
+		UTC:      t.UTC(),
+		Local:    t.In(loc).Format(time.RFC3339),
+		Timezone: loc.String(),
+	}
+}
+
+// Date returns the instant of a wall clock time in loc. Times skipped when
+// clocks are put forward are rejected; times occurring twice when clocks are
+// put back resolve to the first occurrence.
+func Date(year int, month time.Month, day, hour, min int, loc *time.Location) (time.Time, error) {
+	t := time.Date(year, month, day, hour, min, 0, 0, loc)
+	if t.Hour() != hour || t.Minute() != min || t.Day() != day {
+		return time.Time{}, fmt.Errorf("%w: %04d-%02d-%02d %02d:%02d in %s",
+			ErrNonexistentTime, year, month, day, hour, min, loc)
+	}
+
+	// time.Date may pick either occurrence of an ambiguous time
+	for _, shift := range []time.Duration{-time.Hour, -30 * time.Minute} {
+		earlier := t.Add(shift).In(loc)
+		if earlier.Hour() == hour && earlier.Minute() == min && earlier.Day() == day {
+			return earlier, nil
+		}
+	}
+	return t, nil
+}
+
+// Localizer adds the local times at the airports to flights and tickets. It
+// caches the airports' locations, so it should only live for one request.
+type Localizer struct {
+	db        database.Database
+	locations map[string]*time.Location
+}
+
+// NewLocalizer creates a localizer reading airports from db.
+func NewLocalizer(db database.Database) *Localizer {
+	return &Localizer{db: db, locations: make(map[string]*time.Location)}
+}
+
+// Location returns the location of the airport with the given ID, or nil if
+// the airport does not exist or has no valid time zone.
+func (l *Localizer) Location(airportID string) *time.Location {
+	if loc, ok := l.locations[airportID]; ok {
+		return loc
+	}
+
+	var loc *time.Location
+	if airport := l.db.GetAirportByID(airportID); airport.ID != "" {
+		loc, _ = AirportLocation(airport)
+	}
+	l.locations[airportID] = loc
+	return loc
+}
+
+// Flight sets the local departure and arrival times of a flight. Departures
+// are given in the time zone of the origin, arrivals in the time zone of the
+// destination. Flights between airports without valid time zone keep only
+// their UTC times.
+func (l *Localizer) Flight(f *models.Flight) {
+	origin, destination := l.Location(f.From), l.Location(f.To)
+	if origin == nil || destination == nil {
+		return
+	}
+
+	times := &models.FlightTimes{
+		ScheduledDeparture: At(f.ScheduledDeparture, origin),
+		ScheduledArrival:   At(f.ScheduledArrival, destination),
+	}
+	if f.ActualDeparture != nil {
+		t := At(*f.ActualDeparture, origin)
+		times.ActualDeparture = &t
+	}
+	if f.ActualArrival != nil {
+		t := At(*f.ActualArrival, destination)
+		times.ActualArrival = &t
+	}
+	f.LocalTimes = times
+}
+
+// Ticket sets the local departure time of a ticket's flight at its origin.
+func (l *Localizer) Ticket(t *models.Ticket) {
+	if t.Departure == nil {
+		return
+	}
+	if origin := l.Location(t.From); origin != nil {
+		departure := At(t.Departure.UTC, origin)
+		t.Departure = &departure
+	}
+}
@@ -1,20 +1,20 @@
 -- Beispiel-Datensätze für die Tabelle AIRLINE
 INSERT ALL
-    INTO AIRLINE ("NAME", "ID", COUNTRY, ACTIVE) VALUES ('Lufthansa', 'LH', 'Germany', 1)
-    INTO AIRLINE ("NAME", "ID", COUNTRY, ACTIVE) VALUES ('United Airlines', 'UA', 'United States', 1)
-    INTO AIRLINE ("NAME", "ID", COUNTRY, ACTIVE) VALUES ('Emirates', 'EK', 'United Arab Emirates', 1)
-    INTO AIRLINE ("NAME", "ID", COUNTRY, ACTIVE) VALUES ('Air France', 'AF', 'France', 1)
-    INTO AIRLINE ("NAME", "ID", COUNTRY, ACTIVE) VALUES ('Air Berlin', 'AB', 'Germany', 0)
+    INTO AIRLINE ("NAME", "ID", ICAO, COUNTRY, ACTIVE, OVERBOOKING_PERCENT) VALUES ('Lufthansa', 'LH', 'DLH', 'Germany', 1, 5)
+    INTO AIRLINE ("NAME", "ID", ICAO, COUNTRY, ACTIVE, OVERBOOKING_PERCENT) VALUES ('United Airlines', 'UA', 'UAL', 'United States', 1, 10)
+    INTO AIRLINE ("NAME", "ID", ICAO, COUNTRY, ACTIVE) VALUES ('Emirates', 'EK', 'UAE', 'United Arab Emirates', 1)
+    INTO AIRLINE ("NAME", "ID", ICAO, COUNTRY, ACTIVE) VALUES ('Air France', 'AF', 'AFR', 'France', 1)
+    INTO AIRLINE ("NAME", "ID", ICAO, COUNTRY, ACTIVE) VALUES ('Air Berlin', 'AB', 'BER', 'Germany', 0)
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle AIRPORT
 INSERT ALL
-    INTO AIRPORT ("NAME", "ID", COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Minden Airport', 'MIN', 'Germany', 'Minden', 'UTC+1', 70, 2, 52.285, 8.918)
-    INTO AIRPORT ("NAME", "ID", COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Frankfurt Airport', 'FRA', 'Germany', 'Frankfurt', 'UTC+1', 111, 2, 50.033, 8.570)
-    INTO AIRPORT ("NAME", "ID", COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('John F. Kennedy International Airport', 'JFK', 'United States', 'New York', 'UTC-5', 4, 6, 40.639, -73.778)
-    INTO AIRPORT ("NAME", "ID", COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Charles de Gaulle Airport', 'CDG', 'France', 'Paris', 'UTC+1', 119, 3, 49.004, 2.571)
-    INTO AIRPORT ("NAME", "ID", COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Dubai International Airport', 'DXB', 'United Arab Emirates', 'Dubai', 'UTC+4', 19, 3, 25.252, 55.364)
-    INTO AIRPORT ("NAME", "ID", COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Munich Airport', 'MUC', 'Germany', 'Munich', 'UTC+1', 453, 2, 48.136, 11.687)
+    INTO AIRPORT ("NAME", "ID", ICAO, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Minden Airport', 'MIN', 'EDVY', 'Germany', 'Minden', 'Europe/Berlin', 70, 2, 52.285, 8.918)
+    INTO AIRPORT ("NAME", "ID", ICAO, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Frankfurt Airport', 'FRA', 'EDDF', 'Germany', 'Frankfurt', 'Europe/Berlin', 111, 2, 50.033, 8.570)
+    INTO AIRPORT ("NAME", "ID", ICAO, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('John F. Kennedy International Airport', 'JFK', 'KJFK', 'United States', 'New York', 'America/New_York', 4, 6, 40.639, -73.778)
+    INTO AIRPORT ("NAME", "ID", ICAO, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Charles de Gaulle Airport', 'CDG', 'LFPG', 'France', 'Paris', 'Europe/Paris', 119, 3, 49.004, 2.571)
+    INTO AIRPORT ("NAME", "ID", ICAO, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Dubai International Airport', 'DXB', 'OMDB', 'United Arab Emirates', 'Dubai', 'Asia/Dubai', 19, 3, 25.252, 55.364)
+    INTO AIRPORT ("NAME", "ID", ICAO, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) VALUES ('Munich Airport', 'MUC', 'EDDM', 'Germany', 'Munich', 'Europe/Berlin', 453, 2, 48.136, 11.687)
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle FLIGHT_STATUS
@@ -57,31 +57,38 @@ INSERT ALL
     INTO PLOTTYPE ("ID", "NAME", LABEL) VALUES (2, 'police', 'Police')
     INTO PLOTTYPE ("ID", "NAME", LABEL) VALUES (3, 'hangar', 'Hangar')
     INTO PLOTTYPE ("ID", "NAME", LABEL) VALUES (4, 'fire', 'Fire Department')
+    INTO PLOTTYPE ("ID", "NAME", LABEL) VALUES (5, 'terminal', 'Terminal')
+    INTO PLOTTYPE ("ID", "NAME", LABEL) VALUES (6, 'parking', 'Parking')
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle PLOT
 INSERT ALL
     INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P001', 1, 1, 400, 'OCCUPIED', TO_DATE('15-12-24','DD-MM-YY'), 5000, 'Water,Electricity,Internet')
     INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P002', 2, 2, 600, 'OCCUPIED', TO_DATE('20-11-24','DD-MM-YY'), 8000, 'Water,Electricity')
-    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P003', 3, 3, 1200, 'MAINTENANCE', TO_DATE('05-01-25','DD-MM-YY'), 12000, 'Water,Electricity,Internet,Gas')
+    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P003', 3, 3, 160000, 'MAINTENANCE', TO_DATE('05-01-25','DD-MM-YY'), 2500000, 'Water,Electricity,Internet,Gas')
     INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P004', 4, 4, 400, 'OCCUPIED', TO_DATE('01-12-24','DD-MM-YY'), 5000, 'Water,Electricity')
-    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P005', 5, 3, 800, 'OCCUPIED', TO_DATE('30-10-24','DD-MM-YY'), 10000, 'Water,Electricity,Internet')
+    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P005', 5, 3, 100000, 'OCCUPIED', TO_DATE('30-10-24','DD-MM-YY'), 1500000, 'Water,Electricity,Internet')
     INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P006', 6, 4, 800, 'AVAILABLE', TO_DATE('30-11-24','DD-MM-YY'), 1000, 'Water,Electricity,Internet')
+    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P007', 7, 5, 250000, 'OCCUPIED', TO_DATE('10-09-24','DD-MM-YY'), 50000000, 'Water,Electricity,Internet,Gas')
+    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P008', 8, 5, 400000, 'OCCUPIED', TO_DATE('10-09-24','DD-MM-YY'), 80000000, 'Water,Electricity,Internet,Gas')
+    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P009', 9, 6, 120000, 'OCCUPIED', TO_DATE('01-08-24','DD-MM-YY'), 2000000, 'Electricity')
+    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P010', 10, 1, 300, 'OCCUPIED', TO_DATE('15-12-24','DD-MM-YY'), 5000, 'Water,Electricity,Internet')
+    INTO PLOT ("ID", POSITION, "TYPE" , AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE) VALUES ('P011', 11, 1, 350, 'OCCUPIED', TO_DATE('15-12-24','DD-MM-YY'), 5000, 'Water,Electricity,Internet')
 SELECT 1 FROM DUAL;
 
 -- MISSING HANGAR
 INSERT ALL
-    INTO HANGAR ("ID", PLOT, CAPACITY, SIZE_SQFT, STATUS, LAST_INSPECTION, NEXT_INSPECTION) VALUES ('H001', 'P003', 10, 1200, 'MAINTENANCE', TO_DATE('12-01-20','DD-MM-YY'), TO_DATE('01-01-25','DD-MM-YY'))
-    INTO HANGAR ("ID", PLOT, CAPACITY, SIZE_SQFT, STATUS, LAST_INSPECTION, NEXT_INSPECTION) VALUES ('H002', 'P005', 10, 800, 'ACTIVE', TO_DATE('5-03-22','DD-MM-YY'), TO_DATE('10-03-27','DD-MM-YY'))
+    INTO HANGAR ("ID", PLOT, CAPACITY, SIZE_SQFT, STATUS, LAST_INSPECTION, NEXT_INSPECTION) VALUES ('H001', 'P003', 10, 150000, 'MAINTENANCE', TO_DATE('12-01-20','DD-MM-YY'), TO_DATE('01-01-25','DD-MM-YY'))
+    INTO HANGAR ("ID", PLOT, CAPACITY, SIZE_SQFT, STATUS, LAST_INSPECTION, NEXT_INSPECTION) VALUES ('H002', 'P005', 6, 90000, 'ACTIVE', TO_DATE('5-03-22','DD-MM-YY'), TO_DATE('10-03-27','DD-MM-YY'))
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle PILOT
 INSERT ALL
-    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY) VALUES ('PIL001', 'James', 'Anderson', 1450, 'ATPL-A', '123', TO_DATE('31-12-2030', 'dd-mm-yyyy'))
-    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY) VALUES ('PIL002', 'Emily', 'Davis', 430, 'ATPL-A', '456', TO_DATE('31-12-2030', 'dd-mm-yyyy'))
-    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY) VALUES ('PIL003', 'William', 'Taylor', 1540, 'ATPL-A', '789', TO_DATE('31-12-2030', 'dd-mm-yyyy'))
-    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY) VALUES ('PIL004', 'Sophia', 'Wilson', 2430, 'ATPL-A', '101', TO_DATE('31-12-2030', 'dd-mm-yyyy'))
-    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY) VALUES ('PIL005', 'Daniel', 'Martin', 270, 'CPL', '112', TO_DATE('31-12-2030', 'dd-mm-yyyy'))
+    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, MEDICAL_CHECK_DATE) VALUES ('PIL001', 'James', 'Anderson', 1450, 'ATPL-A', '123', TO_DATE('31-12-2030', 'dd-mm-yyyy'), TO_DATE('15-09-2024', 'dd-mm-yyyy'))
+    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, MEDICAL_CHECK_DATE) VALUES ('PIL002', 'Emily', 'Davis', 430, 'ATPL-A', '456', TO_DATE('31-12-2030', 'dd-mm-yyyy'), TO_DATE('03-11-2024', 'dd-mm-yyyy'))
+    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, MEDICAL_CHECK_DATE) VALUES ('PIL003', 'William', 'Taylor', 1540, 'ATPL-A', '789', TO_DATE('31-12-2030', 'dd-mm-yyyy'), TO_DATE('20-06-2024', 'dd-mm-yyyy'))
+    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, MEDICAL_CHECK_DATE) VALUES ('PIL004', 'Sophia', 'Wilson', 2430, 'ATPL-A', '101', TO_DATE('31-12-2030', 'dd-mm-yyyy'), TO_DATE('10-10-2024', 'dd-mm-yyyy'))
+    INTO PILOT ("ID", FIRSTNAME, LASTNAME, FLIGHT_HOURS, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, MEDICAL_CHECK_DATE) VALUES ('PIL005', 'Daniel', 'Martin', 270, 'CPL', '112', TO_DATE('31-12-2030', 'dd-mm-yyyy'), TO_DATE('28-08-2024', 'dd-mm-yyyy'))
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle PLANE
@@ -104,14 +111,20 @@ INSERT ALL
     INTO MAINTENANCE_LOG ("ID", PLANE, MAINTENANCE_DATE, TECHNICIAN, DESCRIPTION, NEXT_MAINTENANCE) VALUES ('M005', 'P005', TO_DATE('26-07-24', 'DD-MM-YY'), 'Tech005', 'Wing structure inspection', TO_DATE('28-03-25', 'DD-MM-YY'))
 SELECT 1 FROM DUAL;
 
--- Beispiel-Datensätze für die Tabelle FLIGHT
+-- Beispiel-Datensätze für die Tabelle SCHEDULE_TEMPLATE (Ortszeiten, Winterflugplan)
 INSERT ALL
-    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F001', 'CDG', 'MIN', 'PIL001', 'P003', TO_TIMESTAMP('01-JAN-25 10:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 10:05:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 13:45:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 13:50:00', 'DD-MON-YY HH24:MI:SS'), 1)
-    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F002', 'MUC', 'MIN', 'PIL002', 'P002', TO_TIMESTAMP('02-JAN-25 08:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 09:15:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 10:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('16-JAN-25 11:30:00', 'DD-MON-YY HH24:MI:SS'), 5)
-    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F003', 'MIN', 'MUC', 'PIL002', 'P002', TO_TIMESTAMP('02-JAN-25 13:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 12:55:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 15:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 15:00:00', 'DD-MON-YY HH24:MI:SS'), 1)
-    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F004', 'MIN', 'JFK', 'PIL004', 'P004', TO_TIMESTAMP('02-JAN-25 23:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 23:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 10:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 10:10:00', 'DD-MON-YY HH24:MI:SS'), 1)
-    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F005', 'CDG', 'MIN', 'PIL003', 'P004', TO_TIMESTAMP('01-JAN-25 13:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 14:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 14:35:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 15:35:00', 'DD-MON-YY HH24:MI:SS'), 5)
-    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F006', 'MIN', 'CDG', 'PIL003', 'P004', TO_TIMESTAMP('01-JAN-25 15:30:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 16:15:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 16:45:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 17:30:00', 'DD-MON-YY HH24:MI:SS'), 5)
+    INTO SCHEDULE_TEMPLATE ("ID", FLIGHT_NUMBER, "FROM", "TO", DEPARTURE_TIME, ARRIVAL_TIME, ARRIVAL_DAY_OFFSET, DAYS_OF_WEEK, VALID_FROM, VALID_TO, PLANE, REVISION) VALUES ('ST001', 'LH100', 'MIN', 'FRA', '07:15', '08:20', 0, '1234567', TO_DATE('26-10-25', 'DD-MM-YY'), TO_DATE('28-03-26', 'DD-MM-YY'), 'P002', 1)
+    INTO SCHEDULE_TEMPLATE ("ID", FLIGHT_NUMBER, "FROM", "TO", DEPARTURE_TIME, ARRIVAL_TIME, ARRIVAL_DAY_OFFSET, DAYS_OF_WEEK, VALID_FROM, VALID_TO, PLANE, REVISION) VALUES ('ST002', 'LH101', 'FRA', 'MIN', '18:40', '19:45', 0, '12345', TO_DATE('26-10-25', 'DD-MM-YY'), TO_DATE('28-03-26', 'DD-MM-YY'), 'P002', 1)
+SELECT 1 FROM DUAL;
+
+-- Beispiel-Datensätze für die Tabelle FLIGHT (alle Zeiten in UTC)
+INSERT ALL
+    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F001', 'CDG', 'MIN', 'PIL001', 'P003', TO_TIMESTAMP('01-JAN-25 09:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 09:05:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 12:45:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 12:50:00', 'DD-MON-YY HH24:MI:SS'), 1)
+    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F002', 'MUC', 'MIN', 'PIL002', 'P002', TO_TIMESTAMP('02-JAN-25 07:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 08:15:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 09:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('16-JAN-25 10:30:00', 'DD-MON-YY HH24:MI:SS'), 5)
+    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F003', 'MIN', 'MUC', 'PIL002', 'P002', TO_TIMESTAMP('02-JAN-25 12:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 11:55:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 14:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 14:00:00', 'DD-MON-YY HH24:MI:SS'), 1)
+    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F004', 'MIN', 'JFK', 'PIL004', 'P004', TO_TIMESTAMP('02-JAN-25 22:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('02-JAN-25 22:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 15:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 15:10:00', 'DD-MON-YY HH24:MI:SS'), 1)
+    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F005', 'CDG', 'MIN', 'PIL003', 'P004', TO_TIMESTAMP('01-JAN-25 12:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 13:00:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 13:35:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 14:35:00', 'DD-MON-YY HH24:MI:SS'), 5)
+    INTO FLIGHT ("ID", "FROM", "TO", PILOT, PLANE, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, STATUS) VALUES ('F006', 'MIN', 'CDG', 'PIL003', 'P004', TO_TIMESTAMP('01-JAN-25 14:30:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('01-JAN-25 15:15:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 15:45:00', 'DD-MON-YY HH24:MI:SS'), TO_TIMESTAMP('03-JAN-25 16:30:00', 'DD-MON-YY HH24:MI:SS'), 5)
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle FLIGHT_CREW
@@ -137,12 +150,45 @@ SELECT 1 FROM DUAL;
 -- Beispiel-Datensätze für die Tabelle SHOP
 INSERT ALL
     INTO SHOP ("ID", "NAME", "TYPE", PLOT, IS_DUTY_FREE) VALUES ('S001', 'Duty Free Shop', 'PT008', 'P001', 1)
+    INTO SHOP ("ID", "NAME", "TYPE", PLOT, OPENING_TIME, CLOSING_TIME, IS_DUTY_FREE) VALUES ('S002', 'Coffee House Express', 'PT001', 'P010', '05:00', '21:00', 0)
+    INTO SHOP ("ID", "NAME", "TYPE", PLOT, OPENING_TIME, CLOSING_TIME, IS_DUTY_FREE) VALUES ('S003', 'Quick Bites Deli', 'PT004', 'P011', '06:00', '21:00', 0)
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle TERMINAL
 INSERT ALL
-    INTO TERMINAL ("ID", "NAME", CAPACITY, FLOOR_COUNT, SERVICES) VALUES ('T001', 'Terminal 1', 200, 1, 'Baggage Handling, Fueling')
-    INTO TERMINAL ("ID", "NAME", CAPACITY, FLOOR_COUNT, SERVICES) VALUES ('T002', 'Terminal 2', 400, 1, 'Baggage Handling, Fueling, Line Maintenance')
+    INTO TERMINAL ("ID", "NAME", CAPACITY, FLOOR_COUNT, SERVICES, PLOT) VALUES ('T001', 'Terminal 1', 200, 1, 'Baggage Handling, Fueling', 'P007')
+    INTO TERMINAL ("ID", "NAME", CAPACITY, FLOOR_COUNT, SERVICES, PLOT) VALUES ('T002', 'Terminal 2', 400, 1, 'Baggage Handling, Fueling, Line Maintenance', 'P008')
+SELECT 1 FROM DUAL;
+
+-- Lage der Shop-Flächen in den Terminals
+UPDATE PLOT SET TERMINAL = 'T002' WHERE ID IN ('P001', 'P011');
+UPDATE PLOT SET TERMINAL = 'T001' WHERE ID = 'P010';
+
+-- Beispiel-Datensätze für die Tabelle PARKING
+INSERT ALL
+    INTO PARKING ("ID", "NAME", PLOT, SPACES, STATUS) VALUES ('PK01', 'P1 Parkhaus', 'P009', 350, 'ACTIVE')
+SELECT 1 FROM DUAL;
+
+-- Beispiel-Datensätze für die Tabelle LEASE (Miete pro sq ft und Monat, Umsatzbeteiligung in Prozent)
+INSERT ALL
+    INTO LEASE ("ID", SHOP, PLOT, START_DATE, END_DATE, RENT_PER_SQFT, TURNOVER_PERCENT) VALUES ('L001', 'S001', 'P001', TO_DATE('01-01-2025', 'dd-mm-yyyy'), TO_DATE('31-12-2029', 'dd-mm-yyyy'), 12.50, 8)
+    INTO LEASE ("ID", SHOP, PLOT, START_DATE, END_DATE, RENT_PER_SQFT, TURNOVER_PERCENT) VALUES ('L002', 'S002', 'P010', TO_DATE('01-03-2025', 'dd-mm-yyyy'), NULL, 9.75, 10)
+    INTO LEASE ("ID", SHOP, PLOT, START_DATE, END_DATE, RENT_PER_SQFT, TURNOVER_PERCENT) VALUES ('L003', 'S003', 'P011', TO_DATE('15-06-2025', 'dd-mm-yyyy'), TO_DATE('14-06-2028', 'dd-mm-yyyy'), 9.75, 0)
+SELECT 1 FROM DUAL;
+
+-- Beispiel-Datensätze für die Tabelle LEASE_TURNOVER (gemeldete Monatsumsätze)
+INSERT ALL
+    INTO LEASE_TURNOVER (LEASE, PERIOD, AMOUNT) VALUES ('L001', TO_DATE('01-09-2025', 'dd-mm-yyyy'), 84250)
+    INTO LEASE_TURNOVER (LEASE, PERIOD, AMOUNT) VALUES ('L002', TO_DATE('01-09-2025', 'dd-mm-yyyy'), 31780.40)
+SELECT 1 FROM DUAL;
+
+-- Beispiel-Datensätze für die Tabelle GATE
+INSERT ALL
+    INTO GATE ("ID", TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS) VALUES ('A1', 'T001', 'C', 'CONTACT', 'SCHENGEN', 'ACTIVE')
+    INTO GATE ("ID", TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS) VALUES ('A2', 'T001', 'D', 'CONTACT', 'SCHENGEN', 'ACTIVE')
+    INTO GATE ("ID", TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS) VALUES ('B1', 'T002', 'E', 'CONTACT', 'NON_SCHENGEN', 'ACTIVE')
+    INTO GATE ("ID", TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS) VALUES ('B2', 'T002', 'F', 'CONTACT', 'NON_SCHENGEN', 'ACTIVE')
+    INTO GATE ("ID", TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS) VALUES ('R1', 'T002', 'E', 'REMOTE', 'SCHENGEN', 'ACTIVE')
 SELECT 1 FROM DUAL;
 
 -- Beispiel-Datensätze für die Tabelle AIRPORTUSER
@@ -170,4 +216,57 @@ INSERT ALL
     INTO TICKET ("ID", AIRPORTUSER, FLIGHT, SEAT_NUMBER, TRAVEL_CLASS, BOOKING_DATE, STATUS, PRICE, BOOKING_DATE) VALUES ('T003', '1f74c4b9-2d46-452b-8463-c8cce3d20abe', 'F003', '20C', '1', '2024-12-01', 'CONFIRMED', 130, TO_DATE('13-01-25','dd-mm-yy'))
     INTO TICKET ("ID", AIRPORTUSER, FLIGHT, SEAT_NUMBER, TRAVEL_CLASS, BOOKING_DATE, STATUS, PRICE, BOOKING_DATE) VALUES ('T004', '025a9b69-7f87-47ea-90b0-0c1fa4b23dd7', 'F004', '3D', '4', '2025-01-13', 'CONFIRMED', 250, TO_DATE('01-12-24','dd-mm-yy'))
     INTO TICKET ("ID", AIRPORTUSER, FLIGHT, SEAT_NUMBER, TRAVEL_CLASS, BOOKING_DATE, STATUS, PRICE, BOOKING_DATE) VALUES ('T005', '62620fcc-cf34-46b2-9e62-0c175deb9574', 'F005', '15E', '4', '2025-01-14', 'CONFIRMED', 100, TO_DATE('14-01-25','dd-mm-yy'))
-SELECT 1 FROM DUAL;
\ No newline at end of file
+SELECT 1 FROM DUAL;
+-- Beispiel-Buchung mit einem registrierten und einem Gast-Passagier
+INSERT INTO BOOKING ("ID", LOCATOR, AIRPORTUSER, STATUS, CREATED_AT) VALUES ('BK001', 'K7PX2M', 'db6417cd-03f2-4578-bf0b-b72c20528c47', 'CONFIRMED', TO_TIMESTAMP('2024-11-24 10:15:00', 'YYYY-MM-DD HH24:MI:SS'));
+
+INSERT ALL
+    INTO BOOKING_PASSENGER ("ID", BOOKING, POSITION, FIRSTNAME, LASTNAME, BIRTHDATE, AIRPORTUSER) VALUES ('BP001', 'BK001', 1, 'Jane', 'Smith', TO_DATE('25-09-90','dd-mm-yy'), 'db6417cd-03f2-4578-bf0b-b72c20528c47')
+    INTO BOOKING_PASSENGER ("ID", BOOKING, POSITION, FIRSTNAME, LASTNAME, BIRTHDATE, AIRPORTUSER) VALUES ('BP002', 'BK001', 2, 'Tom', 'Smith', TO_DATE('02-04-88','dd-mm-yy'), NULL)
+SELECT 1 FROM DUAL;
+
+INSERT ALL
+    INTO TICKET ("ID", AIRPORTUSER, FLIGHT, SEAT_NUMBER, TRAVEL_CLASS, STATUS, PRICE, BOOKING_DATE, BOOKING, PASSENGER) VALUES ('T006', 'db6417cd-03f2-4578-bf0b-b72c20528c47', 'F004', '7A', '4', 'CONFIRMED', 180, TO_DATE('24-11-24','dd-mm-yy'), 'BK001', 'BP001')
+    INTO TICKET ("ID", AIRPORTUSER, FLIGHT, SEAT_NUMBER, TRAVEL_CLASS, STATUS, PRICE, BOOKING_DATE, BOOKING, PASSENGER) VALUES ('T007', 'db6417cd-03f2-4578-bf0b-b72c20528c47', 'F004', '7B', '4', 'CONFIRMED', 180, TO_DATE('24-11-24','dd-mm-yy'), 'BK001', 'BP002')
+SELECT 1 FROM DUAL;
+
+-- Beispiel-Mitreisende, die über das Konto von Jane Smith verwaltet werden
+INSERT INTO COMPANION ("ID", AIRPORTUSER, FIRSTNAME, LASTNAME, BIRTHDATE, CREATED_AT) VALUES ('C001', 'db6417cd-03f2-4578-bf0b-b72c20528c47', 'Lena', 'Smith', TO_DATE('12-06-2016','dd-mm-yyyy'), TO_TIMESTAMP('2024-11-20 18:30:00', 'YYYY-MM-DD HH24:MI:SS'));
+
+-- Beispiel-Tarife je Flug und Reiseklasse
+INSERT ALL
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F001', 4, 120)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F001', 2, 420)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F002', 4, 95)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F002', 2, 200)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F003', 4, 95)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F003', 1, 130)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F004', 4, 180)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F004', 2, 1450)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F005', 4, 100)
+    INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE) VALUES ('F006', 4, 100)
+SELECT 1 FROM DUAL;
+
+-- Beispiel-Zahlung der Buchung K7PX2M über den Mock-Zahlungsanbieter
+INSERT INTO PAYMENT ("ID", BOOKING, AIRPORTUSER, PROVIDER, REFERENCE, STATUS, AMOUNT, REFUNDED_AMOUNT, CURRENCY, CARD_BRAND, CARD_LAST4, CREATED_AT, UPDATED_AT, CAPTURED_AT) VALUES ('PAY001', 'BK001', 'db6417cd-03f2-4578-bf0b-b72c20528c47', 'mock', 'mock_3f1c2a9e7b5d4e60', 'CAPTURED', 360, 0, 'EUR', 'VISA', '4242', TO_TIMESTAMP('2024-11-24 10:17:00', 'YYYY-MM-DD HH24:MI:SS'), TO_TIMESTAMP('2024-11-24 10:17:02', 'YYYY-MM-DD HH24:MI:SS'), TO_TIMESTAMP('2024-11-24 10:17:02', 'YYYY-MM-DD HH24:MI:SS'));
+
+INSERT ALL
+    INTO PAYMENT_TICKET (PAYMENT, TICKET, AMOUNT) VALUES ('PAY001', 'T006', 180)
+    INTO PAYMENT_TICKET (PAYMENT, TICKET, AMOUNT) VALUES ('PAY001', 'T007', 180)
+SELECT 1 FROM DUAL;
+
+-- Rechnungsangaben des Flughafens (neue Versionen gelten ab VALID_FROM)
+INSERT INTO INVOICE_ISSUER ("ID", NAME, STREET, POSTCODE, CITY, COUNTRY, VAT_ID, TAX_NUMBER, REGISTER_COURT, REGISTER_NUMBER, MANAGING_DIRECTORS, CONTACT_NAME, EMAIL, PHONE, IBAN, BIC, BANK_NAME, VALID_FROM) VALUES ('ISS001', 'Minden Airport GmbH', 'Flughafenstraße 1', '32423', 'Minden', 'DE', 'DE123456789', '335/5712/0815', 'Amtsgericht Bad Oeynhausen', 'HRB 12345', 'Erika Mustermann', 'Buchhaltung', 'rechnung@minden-airport.de', '+49 571 123450', 'DE02120300000000202051', 'BYLADEM1001', 'Deutsche Kreditbank Berlin', TO_TIMESTAMP('2024-01-01 00:00:00', 'YYYY-MM-DD HH24:MI:SS'));
+
+-- Rechnungsadresse für Geschäftsreisen
+INSERT INTO BILLING_ADDRESS (AIRPORTUSER, COMPANY, NAME, STREET, POSTCODE, CITY, COUNTRY, VAT_ID, BUYER_REFERENCE, EMAIL, UPDATED_AT) VALUES ('db6417cd-03f2-4578-bf0b-b72c20528c47', 'Smith Consulting GmbH', 'Jane Smith', 'Marktplatz 5', '32423', 'Minden', 'DE', 'DE987654321', '04011000-12345-67', 'janesmith@example.com', TO_TIMESTAMP('2024-11-20 09:00:00', 'YYYY-MM-DD HH24:MI:SS'));
+
+-- Rechnung zur Zahlung PAY001 (internationaler Flug, daher steuerfrei)
+INSERT INTO INVOICE_SEQUENCE (INVOICE_YEAR, LAST_NUMBER) VALUES (2024, 1);
+
+INSERT INTO INVOICE ("ID", INVOICE_NUMBER, INVOICE_YEAR, SEQUENCE_NUMBER, TYPE, ISSUER, AIRPORTUSER, BOOKING, PAYMENT, ISSUED_AT, SERVICE_DATE, CURRENCY, NET_AMOUNT, VAT_AMOUNT, GROSS_AMOUNT, PAYMENT_METHOD, CARD_LAST4, BUYER_NAME, BUYER_COMPANY, BUYER_STREET, BUYER_POSTCODE, BUYER_CITY, BUYER_COUNTRY, BUYER_VAT_ID, BUYER_REFERENCE, BUYER_EMAIL) VALUES ('INV001', 'MA-2024-000001', 2024, 1, 'INVOICE', 'ISS001', 'db6417cd-03f2-4578-bf0b-b72c20528c47', 'BK001', 'PAY001', TO_TIMESTAMP('2024-11-24 10:17:02', 'YYYY-MM-DD HH24:MI:SS'), TO_DATE('2025-01-02', 'YYYY-MM-DD'), 'EUR', 360, 0, 360, 'CARD', '4242', 'Jane Smith', 'Smith Consulting GmbH', 'Marktplatz 5', '32423', 'Minden', 'DE', 'DE987654321', '04011000-12345-67', 'janesmith@example.com');
+
+INSERT ALL
+    INTO INVOICE_LINE (INVOICE, LINE_NO, DESCRIPTION, QUANTITY, NET_AMOUNT, VAT_CATEGORY, VAT_RATE, VAT_AMOUNT, GROSS_AMOUNT, TICKET) VALUES ('INV001', 1, 'Flight MIN–JFK 02.01.2025, Jane Smith, Economy', 1, 180, 'E', 0, 0, 180, 'T006')
+    INTO INVOICE_LINE (INVOICE, LINE_NO, DESCRIPTION, QUANTITY, NET_AMOUNT, VAT_CATEGORY, VAT_RATE, VAT_AMOUNT, GROSS_AMOUNT, TICKET) VALUES ('INV001', 2, 'Flight MIN–JFK 02.01.2025, Tom Smith, Economy', 1, 180, 'E', 0, 0, 180, 'T007')
+SELECT 1 FROM DUAL;
@@ -13,7 +13,11 @@ create table AIRLINE (
    COUNTRY             VARCHAR2(255),
    LOGO_URL            VARCHAR2(255),
    ACTIVE              NUMBER(1) default 1,
-   constraint CK_AIRLINE_ACTIVE check (ACTIVE in (0,1))
+   ICAO                VARCHAR2(3),
+   OVERBOOKING_PERCENT NUMBER(5,2),
+   constraint CK_AIRLINE_ACTIVE check (ACTIVE in (0,1)),
+   constraint CK_AIRLINE_OVERBOOKING check (OVERBOOKING_PERCENT between 0 and 50),
+   constraint UQ_AIRLINE_ICAO unique (ICAO)
 );
 
 /*==============================================================*/
@@ -29,7 +33,9 @@ create table AIRPORT (
    ELEVATION           NUMBER,
    NUMBER_OF_TERMINALS NUMBER,
    LATITUDE            NUMBER(10,6),
-   LONGITUDE           NUMBER(10,6)
+   LONGITUDE           NUMBER(10,6),
+   ICAO                VARCHAR2(4),
+   constraint UQ_AIRPORT_ICAO unique (ICAO)
 );
 
 /*==============================================================*/
@@ -64,6 +70,7 @@ create table BAGGAGE (
    TRACKING_NUMBER     VARCHAR2(20)          not null,
    STATUS              VARCHAR2(20)          default 'CHECKED',
    SPECIAL_HANDLING    VARCHAR2(255),
+   COMPANION           VARCHAR2(36),
    constraint PK_BAGGAGE primary key (ID),
    constraint CK_BAGGAGE_STATUS check (STATUS in ('CHECKED','IN_TRANSIT','DELIVERED','LOST'))
 );
@@ -75,7 +82,7 @@ create table FLIGHT (
    ID                   VARCHAR2(36)          not null,
    "FROM"               VARCHAR2(3)          not null,
    "TO"                 VARCHAR2(3)          not null,
-   PILOT                VARCHAR2(36)          not null,
+   PILOT                VARCHAR2(36),
    PLANE                VARCHAR2(36)          not null,
    TERMINAL             VARCHAR2(36),
    STATUS               NUMBER,
@@ -85,7 +92,12 @@ create table FLIGHT (
    ACTUAL_ARRIVAL       TIMESTAMP,
    GATE                 VARCHAR2(10),
    BAGGAGE_CLAIM        VARCHAR2(10),
-   constraint PK_FLIGHT primary key (ID)
+   FLIGHT_NUMBER        VARCHAR2(8),
+   TEMPLATE             VARCHAR2(36),
+   TEMPLATE_REVISION    NUMBER,
+   OVERBOOKING_PERCENT  NUMBER(5,2),
+   constraint PK_FLIGHT primary key (ID),
+   constraint CK_FLIGHT_OVERBOOKING check (OVERBOOKING_PERCENT between 0 and 50)
 );
 
 /*==============================================================*/
@@ -187,6 +199,7 @@ create table PLOT (
    LAST_MAINTENANCE    DATE,
    MAX_WEIGHT_CAPACITY NUMBER,
    UTILITIES_AVAILABLE VARCHAR2(255),
+   TERMINAL            VARCHAR2(36),
    constraint CK_PLOT_STATUS check (STATUS in ('AVAILABLE','OCCUPIED','MAINTENANCE'))
 );
 
@@ -243,6 +256,7 @@ create table TERMINAL (
    FLOOR_COUNT         NUMBER,
    SERVICES            VARCHAR2(1000),
    OPENING_HOURS       VARCHAR2(255),
+   PLOT                VARCHAR2(36),
    constraint CK_TERMINAL_STATUS check (STATUS in ('ACTIVE','MAINTENANCE','CLOSED'))
 );
 
@@ -258,8 +272,11 @@ create table TICKET (
    PRICE              NUMBER(10,2),
    BOOKING_DATE       TIMESTAMP             default CURRENT_TIMESTAMP,
    STATUS             VARCHAR2(20)          default 'CONFIRMED',
+   TRAVEL_DOCUMENT    VARCHAR2(36),
+   BOOKING            VARCHAR2(36),
+   PASSENGER          VARCHAR2(36),
    constraint PK_TICKET primary key (ID),
-   constraint CK_TICKET_STATUS check (STATUS in ('CONFIRMED','CANCELLED','CHECKED_IN'))
+   constraint CK_TICKET_STATUS check (STATUS in ('HELD','CONFIRMED','CANCELLED','CHECKED_IN','DENIED_BOARDING'))
 );
 
 /*==============================================================*/
@@ -281,6 +298,497 @@ create table AIRPORTUSER (
 );
 
 
+/*==============================================================*/
+/* Table: NOTIFICATION_PREFERENCE                               */
+/*==============================================================*/
+create table NOTIFICATION_PREFERENCE (
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   LANGUAGE             VARCHAR2(2)           default 'en' not null,
+   EMAIL                NUMBER(1)             default 1 not null,
+   SMS                  NUMBER(1)             default 0 not null,
+   PUSH                 NUMBER(1)             default 0 not null,
+   PUSH_ENDPOINT        VARCHAR2(1000),
+   constraint PK_NOTIFICATION_PREFERENCE primary key (AIRPORTUSER),
+   constraint CK_NOTIFICATION_LANGUAGE check (LANGUAGE in ('de','en')),
+   constraint CK_NOTIFICATION_EMAIL check (EMAIL in (0,1)),
+   constraint CK_NOTIFICATION_SMS check (SMS in (0,1)),
+   constraint CK_NOTIFICATION_PUSH check (PUSH in (0,1))
+);
+
+/*==============================================================*/
+/* Table: GATE                                                  */
+/*==============================================================*/
+create table GATE (
+   ID                   VARCHAR2(10)          not null,
+   TERMINAL             VARCHAR2(36)          not null,
+   MAX_AIRCRAFT_CODE    CHAR(1)               not null,
+   STAND_TYPE           VARCHAR2(10)          not null,
+   AREA                 VARCHAR2(20)          not null,
+   STATUS               VARCHAR2(20)          default 'ACTIVE',
+   constraint PK_GATE primary key (ID),
+   constraint CK_GATE_AIRCRAFT_CODE check (MAX_AIRCRAFT_CODE in ('A','B','C','D','E','F')),
+   constraint CK_GATE_STAND_TYPE check (STAND_TYPE in ('CONTACT','REMOTE')),
+   constraint CK_GATE_AREA check (AREA in ('SCHENGEN','NON_SCHENGEN')),
+   constraint CK_GATE_STATUS check (STATUS in ('ACTIVE','MAINTENANCE','CLOSED'))
+);
+
+/*==============================================================*/
+/* Table: PARKING                                               */
+/*==============================================================*/
+create table PARKING (
+   ID                   VARCHAR2(36)          not null,
+   NAME                 VARCHAR2(255)         not null,
+   PLOT                 VARCHAR2(36)          not null,
+   SPACES               NUMBER,
+   STATUS               VARCHAR2(20) default 'ACTIVE',
+   constraint PK_PARKING primary key (ID),
+   constraint CK_PARKING_STATUS check (STATUS in ('ACTIVE','CLOSED'))
+);
+
+/*==============================================================*/
+/* Table: LEASE                                                 */
+/*==============================================================*/
+create table LEASE (
+   ID                   VARCHAR2(36)          not null,
+   SHOP                 VARCHAR2(36)          not null,
+   PLOT                 VARCHAR2(36)          not null,
+   START_DATE           DATE                  not null,
+   END_DATE             DATE,
+   RENT_PER_SQFT        NUMBER(10,2)          not null,
+   TURNOVER_PERCENT     NUMBER(5,2) default 0 not null,
+   constraint PK_LEASE primary key (ID),
+   constraint CK_LEASE_DATES check (END_DATE is null or END_DATE >= START_DATE),
+   constraint CK_LEASE_RENT check (RENT_PER_SQFT >= 0),
+   constraint CK_LEASE_TURNOVER check (TURNOVER_PERCENT between 0 and 100)
+);
+
+/*==============================================================*/
+/* Table: LEASE_TURNOVER                                        */
+/*==============================================================*/
+create table LEASE_TURNOVER (
+   LEASE                VARCHAR2(36)          not null,
+   PERIOD               DATE                  not null,
+   AMOUNT               NUMBER(12,2)          not null,
+   constraint PK_LEASE_TURNOVER primary key (LEASE, PERIOD),
+   constraint CK_LEASE_TURNOVER_AMOUNT check (AMOUNT >= 0)
+);
+
+/*==============================================================*/
+/* Table: LEASE_INVOICE                                         */
+/*==============================================================*/
+create table LEASE_INVOICE (
+   ID                   VARCHAR2(36)          not null,
+   LEASE                VARCHAR2(36)          not null,
+   PERIOD               DATE                  not null,
+   AREA_SQFT            NUMBER(10,2)          not null,
+   FIXED_RENT           NUMBER(12,2)          not null,
+   TURNOVER             NUMBER(12,2),
+   TURNOVER_RENT        NUMBER(12,2) default 0 not null,
+   TOTAL                NUMBER(12,2)          not null,
+   ISSUED_AT            DATE                  not null,
+   constraint PK_LEASE_INVOICE primary key (ID),
+   constraint UQ_LEASE_INVOICE_PERIOD unique (LEASE, PERIOD)
+);
+
+/*==============================================================*/
+/* Table: SCHEDULE_TEMPLATE                                     */
+/*==============================================================*/
+create table SCHEDULE_TEMPLATE (
+   ID                   VARCHAR2(36)          not null,
+   FLIGHT_NUMBER        VARCHAR2(8)           not null,
+   "FROM"               VARCHAR2(3)           not null,
+   "TO"                 VARCHAR2(3)           not null,
+   DEPARTURE_TIME       VARCHAR2(5)           not null,
+   ARRIVAL_TIME         VARCHAR2(5)           not null,
+   ARRIVAL_DAY_OFFSET   NUMBER(1) default 0   not null,
+   DAYS_OF_WEEK         VARCHAR2(7)           not null,
+   VALID_FROM           DATE                  not null,
+   VALID_TO             DATE                  not null,
+   PLANE                VARCHAR2(36)          not null,
+   TERMINAL             VARCHAR2(36),
+   GATE                 VARCHAR2(10),
+   REVISION             NUMBER default 1      not null,
+   constraint PK_SCHEDULE_TEMPLATE primary key (ID),
+   constraint CK_SCHEDULE_TEMPLATE_DATES check (VALID_TO >= VALID_FROM),
+   constraint CK_SCHEDULE_TEMPLATE_OFFSET check (ARRIVAL_DAY_OFFSET between 0 and 2)
+);
+
+/*==============================================================*/
+/* Table: CALENDAR_SUBSCRIPTION                                 */
+/*==============================================================*/
+create table CALENDAR_SUBSCRIPTION (
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   TOKEN_HASH           VARCHAR2(64)          not null,
+   CREATED_AT           TIMESTAMP             not null,
+   constraint PK_CALENDAR_SUBSCRIPTION primary key (AIRPORTUSER),
+   constraint UQ_CALENDAR_TOKEN_HASH unique (TOKEN_HASH)
+);
+
+/*==============================================================*/
+/* Table: PASSENGER_LIST_MESSAGE                                */
+/*==============================================================*/
+create table PASSENGER_LIST_MESSAGE (
+   ID                   VARCHAR2(36)          not null,
+   FLIGHT               VARCHAR2(36)          not null,
+   TYPE                 VARCHAR2(3)           not null,
+   SEQUENCE             NUMBER                not null,
+   SENT_AT              TIMESTAMP             not null,
+   PARTS                NUMBER                not null,
+   PASSENGERS           NUMBER                not null,
+   constraint PK_PASSENGER_LIST_MESSAGE primary key (ID),
+   constraint CK_PASSENGER_LIST_TYPE check (TYPE in ('PNL','ADL')),
+   constraint UQ_PASSENGER_LIST_SEQUENCE unique (FLIGHT, TYPE, SEQUENCE)
+);
+
+/*==============================================================*/
+/* Table: PASSENGER_LIST_ENTRY                                  */
+/*==============================================================*/
+create table PASSENGER_LIST_ENTRY (
+   MESSAGE              VARCHAR2(36)          not null,
+   TICKET               VARCHAR2(36)          not null,
+   SURNAME              VARCHAR2(64)          not null,
+   GIVEN_NAME           VARCHAR2(64),
+   BOOKING_CLASS        CHAR(1)               not null,
+   SEAT                 VARCHAR2(10),
+   LOCATOR              CHAR(6),
+   constraint PK_PASSENGER_LIST_ENTRY primary key (MESSAGE, TICKET)
+);
+
+/*==============================================================*/
+/* Table: TRAVEL_DOCUMENT                                       */
+/*==============================================================*/
+create table TRAVEL_DOCUMENT (
+   ID                   VARCHAR2(36)          not null,
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   TYPE                 CHAR(1)               not null,
+   DOCUMENT_NUMBER      VARCHAR2(255)         not null,
+   NATIONALITY          VARCHAR2(255)         not null,
+   ISSUING_COUNTRY      VARCHAR2(255)         not null,
+   EXPIRY_DATE          VARCHAR2(255)         not null,
+   SEX                  VARCHAR2(255)         not null,
+   CREATED_AT           TIMESTAMP             not null,
+   COMPANION            VARCHAR2(36),
+   constraint PK_TRAVEL_DOCUMENT primary key (ID),
+   constraint CK_TRAVEL_DOCUMENT_TYPE check (TYPE in ('P','I'))
+);
+
+/*==============================================================*/
+/* Table: BOOKING                                               */
+/*==============================================================*/
+create table BOOKING (
+   ID                   VARCHAR2(36)          not null,
+   LOCATOR              CHAR(6)               not null,
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   STATUS               VARCHAR2(20)          default 'CONFIRMED' not null,
+   CREATED_AT           TIMESTAMP             not null,
+   HOLD_EXPIRES_AT      TIMESTAMP,
+   constraint PK_BOOKING primary key (ID),
+   constraint UQ_BOOKING_LOCATOR unique (LOCATOR),
+   constraint CK_BOOKING_STATUS check (STATUS in ('HELD','CONFIRMED','CANCELLED'))
+);
+
+/*==============================================================*/
+/* Table: BOOKING_PASSENGER                                     */
+/*==============================================================*/
+create table BOOKING_PASSENGER (
+   ID                   VARCHAR2(36)          not null,
+   BOOKING              VARCHAR2(36)          not null,
+   POSITION             NUMBER                not null,
+   FIRSTNAME            VARCHAR2(255)         not null,
+   LASTNAME             VARCHAR2(255)         not null,
+   BIRTHDATE            DATE,
+   AIRPORTUSER          VARCHAR2(36),
+   COMPANION            VARCHAR2(36),
+   constraint PK_BOOKING_PASSENGER primary key (ID)
+);
+
+/*==============================================================*/
+/* Table: COMPANION                                             */
+/*==============================================================*/
+create table COMPANION (
+   ID                   VARCHAR2(36)          not null,
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   FIRSTNAME            VARCHAR2(255)         not null,
+   LASTNAME             VARCHAR2(255)         not null,
+   BIRTHDATE            DATE                  not null,
+   CREATED_AT           TIMESTAMP             not null,
+   constraint PK_COMPANION primary key (ID)
+);
+
+/*==============================================================*/
+/* Table: WAITLIST_ENTRY                                        */
+/*==============================================================*/
+create table WAITLIST_ENTRY (
+   ID                   VARCHAR2(36)          not null,
+   FLIGHT               VARCHAR2(36)          not null,
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   TRAVEL_CLASS         NUMBER                not null,
+   SEATS                NUMBER(2)             not null,
+   STATUS               VARCHAR2(20)          default 'WAITING' not null,
+   CREATED_AT           TIMESTAMP             not null,
+   OFFERED_AT           TIMESTAMP,
+   OFFER_EXPIRES_AT     TIMESTAMP,
+   BOOKING              VARCHAR2(36),
+   constraint PK_WAITLIST_ENTRY primary key (ID),
+   constraint CK_WAITLIST_ENTRY_STATUS check (STATUS in ('WAITING','OFFERED','ACCEPTED','DECLINED','EXPIRED','CANCELLED'))
+);
+
+/*==============================================================*/
+/* Table: WAITLIST_PASSENGER                                    */
+/*==============================================================*/
+create table WAITLIST_PASSENGER (
+   ID                   VARCHAR2(36)          not null,
+   ENTRY                VARCHAR2(36)          not null,
+   POSITION             NUMBER(2)             not null,
+   SELF                 NUMBER(1)             default 0 not null,
+   COMPANION            VARCHAR2(36),
+   FIRSTNAME            VARCHAR2(255),
+   LASTNAME             VARCHAR2(255),
+   BIRTHDATE            DATE,
+   constraint PK_WAITLIST_PASSENGER primary key (ID),
+   constraint CK_WAITLIST_PASSENGER_SELF check (SELF in (0,1))
+);
+
+/*==============================================================*/
+/* Table: DENIED_BOARDING                                       */
+/*==============================================================*/
+create table DENIED_BOARDING (
+   ID                   VARCHAR2(36)          not null,
+   TICKET               VARCHAR2(36)          not null,
+   FLIGHT               VARCHAR2(36)          not null,
+   STATUS               VARCHAR2(20)          default 'VOLUNTEER' not null,
+   VOLUNTARY            NUMBER(1)             not null,
+   REQUESTED_COMPENSATION NUMBER(10,2),
+   COMPENSATION         NUMBER(10,2),
+   CURRENCY             VARCHAR2(3),
+   NOTE                 VARCHAR2(500),
+   CREATED_AT           TIMESTAMP             not null,
+   DECIDED_AT           TIMESTAMP,
+   DECIDED_BY           VARCHAR2(36),
+   constraint PK_DENIED_BOARDING primary key (ID),
+   constraint UQ_DENIED_BOARDING_TICKET unique (TICKET),
+   constraint CK_DENIED_BOARDING_STATUS check (STATUS in ('VOLUNTEER','DENIED')),
+   constraint CK_DENIED_BOARDING_VOLUNTARY check (VOLUNTARY in (0,1))
+);
+
+/*==============================================================*/
+/* Table: FLIGHT_FARE                                           */
+/*==============================================================*/
+create table FLIGHT_FARE (
+   FLIGHT               VARCHAR2(36)          not null,
+   TRAVEL_CLASS         NUMBER                not null,
+   PRICE                NUMBER(10,2)          not null,
+   constraint PK_FLIGHT_FARE primary key (FLIGHT, TRAVEL_CLASS),
+   constraint CK_FLIGHT_FARE_PRICE check (PRICE >= 0)
+);
+
+/*==============================================================*/
+/* Table: PAYMENT                                               */
+/*==============================================================*/
+create table PAYMENT (
+   ID                   VARCHAR2(36)          not null,
+   BOOKING              VARCHAR2(36)          not null,
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   PROVIDER             VARCHAR2(20)          not null,
+   REFERENCE            VARCHAR2(100),
+   STATUS               VARCHAR2(20)          default 'PENDING' not null,
+   AMOUNT               NUMBER(10,2)          not null,
+   REFUNDED_AMOUNT      NUMBER(10,2) default 0 not null,
+   CURRENCY             VARCHAR2(3)           not null,
+   CARD_BRAND           VARCHAR2(20),
+   CARD_LAST4           VARCHAR2(4),
+   FAILURE_CODE         VARCHAR2(50),
+   FAILURE_MESSAGE      VARCHAR2(255),
+   CREATED_AT           TIMESTAMP             not null,
+   UPDATED_AT           TIMESTAMP             not null,
+   CAPTURED_AT          TIMESTAMP,
+   constraint PK_PAYMENT primary key (ID),
+   constraint UQ_PAYMENT_REFERENCE unique (PROVIDER, REFERENCE),
+   constraint CK_PAYMENT_STATUS check (STATUS in ('PENDING','AUTHORIZED','CAPTURED','DECLINED','FAILED','VOIDED','REFUNDED')),
+   constraint CK_PAYMENT_AMOUNT check (AMOUNT >= 0 and REFUNDED_AMOUNT between 0 and AMOUNT)
+);
+
+/*==============================================================*/
+/* Table: PAYMENT_REFUND                                        */
+/*==============================================================*/
+create table PAYMENT_REFUND (
+   ID                   VARCHAR2(36)          not null,
+   PAYMENT              VARCHAR2(36)          not null,
+   REFERENCE            VARCHAR2(100),
+   AMOUNT               NUMBER(10,2)          not null,
+   STATUS               VARCHAR2(20)          default 'PENDING' not null,
+   REASON               VARCHAR2(255),
+   CREATED_AT           TIMESTAMP             not null,
+   CREATED_BY           VARCHAR2(36),
+   constraint PK_PAYMENT_REFUND primary key (ID),
+   constraint CK_PAYMENT_REFUND_STATUS check (STATUS in ('PENDING','SUCCEEDED','FAILED')),
+   constraint CK_PAYMENT_REFUND_AMOUNT check (AMOUNT >= 0)
+);
+
+/*==============================================================*/
+/* Table: PAYMENT_TICKET                                        */
+/*==============================================================*/
+create table PAYMENT_TICKET (
+   PAYMENT              VARCHAR2(36)          not null,
+   TICKET               VARCHAR2(36)          not null,
+   AMOUNT               NUMBER(10,2)          not null,
+   REFUND               VARCHAR2(36),
+   constraint PK_PAYMENT_TICKET primary key (PAYMENT, TICKET)
+);
+
+/*==============================================================*/
+/* Table: IDEMPOTENCY_KEY                                       */
+/*==============================================================*/
+create table IDEMPOTENCY_KEY (
+   SCOPE                VARCHAR2(64)          not null,
+   REQUEST_KEY          VARCHAR2(255)         not null,
+   REQUEST_HASH         VARCHAR2(64)          not null,
+   STATUS_CODE          NUMBER(3),
+   RESPONSE             CLOB,
+   CREATED_AT           TIMESTAMP             not null,
+   constraint PK_IDEMPOTENCY_KEY primary key (SCOPE, REQUEST_KEY)
+);
+
+/*==============================================================*/
+/* Table: INVOICE_ISSUER                                        */
+/*==============================================================*/
+create table INVOICE_ISSUER (
+   ID                   VARCHAR2(36)          not null,
+   NAME                 VARCHAR2(255)         not null,
+   STREET               VARCHAR2(255)         not null,
+   POSTCODE             VARCHAR2(20)          not null,
+   CITY                 VARCHAR2(100)         not null,
+   COUNTRY              VARCHAR2(2)           not null,
+   VAT_ID               VARCHAR2(20),
+   TAX_NUMBER           VARCHAR2(30),
+   REGISTER_COURT       VARCHAR2(100),
+   REGISTER_NUMBER      VARCHAR2(30),
+   MANAGING_DIRECTORS   VARCHAR2(255),
+   CONTACT_NAME         VARCHAR2(100)         not null,
+   EMAIL                VARCHAR2(255)         not null,
+   PHONE                VARCHAR2(50)          not null,
+   IBAN                 VARCHAR2(34),
+   BIC                  VARCHAR2(11),
+   BANK_NAME            VARCHAR2(100),
+   VALID_FROM           TIMESTAMP             not null,
+   CREATED_BY           VARCHAR2(36),
+   constraint PK_INVOICE_ISSUER primary key (ID),
+   constraint CK_INVOICE_ISSUER_TAX check (VAT_ID is not null or TAX_NUMBER is not null)
+);
+
+/*==============================================================*/
+/* Table: BILLING_ADDRESS                                       */
+/*==============================================================*/
+create table BILLING_ADDRESS (
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   COMPANY              VARCHAR2(255),
+   NAME                 VARCHAR2(255)         not null,
+   STREET               VARCHAR2(255),
+   POSTCODE             VARCHAR2(20),
+   CITY                 VARCHAR2(100),
+   COUNTRY              VARCHAR2(2),
+   VAT_ID               VARCHAR2(20),
+   BUYER_REFERENCE      VARCHAR2(100),
+   EMAIL                VARCHAR2(255),
+   UPDATED_AT           TIMESTAMP             not null,
+   constraint PK_BILLING_ADDRESS primary key (AIRPORTUSER)
+);
+
+/*==============================================================*/
+/* Table: BAGGAGE_FEE                                           */
+/*==============================================================*/
+create table BAGGAGE_FEE (
+   ID                   VARCHAR2(36)          not null,
+   BAGGAGE              VARCHAR2(36)          not null,
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   FLIGHT               VARCHAR2(36)          not null,
+   REASON               VARCHAR2(20)          not null,
+   WEIGHT               NUMBER(10,2)          not null,
+   AMOUNT               NUMBER(10,2)          not null,
+   CURRENCY             VARCHAR2(3)           not null,
+   PAYMENT_METHOD       VARCHAR2(10)          not null,
+   CARD_LAST4           VARCHAR2(4),
+   PAID_AT              TIMESTAMP             not null,
+   CREATED_BY           VARCHAR2(36),
+   constraint PK_BAGGAGE_FEE primary key (ID),
+   constraint UQ_BAGGAGE_FEE_BAGGAGE unique (BAGGAGE),
+   constraint CK_BAGGAGE_FEE_REASON check (REASON in ('OVERWEIGHT','OVERSIZE')),
+   constraint CK_BAGGAGE_FEE_METHOD check (PAYMENT_METHOD in ('CARD','CASH')),
+   constraint CK_BAGGAGE_FEE_AMOUNT check (AMOUNT > 0)
+);
+
+/*==============================================================*/
+/* Table: INVOICE_SEQUENCE                                      */
+/*==============================================================*/
+create table INVOICE_SEQUENCE (
+   INVOICE_YEAR         NUMBER(4)             not null,
+   LAST_NUMBER          NUMBER                not null,
+   constraint PK_INVOICE_SEQUENCE primary key (INVOICE_YEAR)
+);
+
+/*==============================================================*/
+/* Table: INVOICE                                               */
+/*==============================================================*/
+create table INVOICE (
+   ID                   VARCHAR2(36)          not null,
+   INVOICE_NUMBER       VARCHAR2(30)          not null,
+   INVOICE_YEAR         NUMBER(4)             not null,
+   SEQUENCE_NUMBER      NUMBER                not null,
+   TYPE                 VARCHAR2(20)          not null,
+   ISSUER               VARCHAR2(36)          not null,
+   AIRPORTUSER          VARCHAR2(36)          not null,
+   BOOKING              VARCHAR2(36),
+   PAYMENT              VARCHAR2(36),
+   REFUND               VARCHAR2(36),
+   BAGGAGE_FEE          VARCHAR2(36),
+   CORRECTS             VARCHAR2(36),
+   ISSUED_AT            TIMESTAMP             not null,
+   SERVICE_DATE         DATE                  not null,
+   CURRENCY             VARCHAR2(3)           not null,
+   NET_AMOUNT           NUMBER(10,2)          not null,
+   VAT_AMOUNT           NUMBER(10,2)          not null,
+   GROSS_AMOUNT         NUMBER(10,2)          not null,
+   PAYMENT_METHOD       VARCHAR2(10)          not null,
+   CARD_LAST4           VARCHAR2(4),
+   BUYER_NAME           VARCHAR2(255)         not null,
+   BUYER_COMPANY        VARCHAR2(255),
+   BUYER_STREET         VARCHAR2(255),
+   BUYER_POSTCODE       VARCHAR2(20),
+   BUYER_CITY           VARCHAR2(100),
+   BUYER_COUNTRY        VARCHAR2(2),
+   BUYER_VAT_ID         VARCHAR2(20),
+   BUYER_REFERENCE      VARCHAR2(100),
+   BUYER_EMAIL          VARCHAR2(255),
+   NOTE                 VARCHAR2(500),
+   CREATED_BY           VARCHAR2(36),
+   constraint PK_INVOICE primary key (ID),
+   constraint UQ_INVOICE_NUMBER unique (INVOICE_NUMBER),
+   constraint UQ_INVOICE_SEQUENCE unique (INVOICE_YEAR, SEQUENCE_NUMBER),
+   constraint CK_INVOICE_TYPE check (TYPE in ('INVOICE','CREDIT_NOTE')),
+   constraint CK_INVOICE_METHOD check (PAYMENT_METHOD in ('CARD','CASH')),
+   constraint CK_INVOICE_CORRECTS check ((TYPE = 'INVOICE' and CORRECTS is null) or (TYPE = 'CREDIT_NOTE' and CORRECTS is not null))
+);
+
+/*==============================================================*/
+/* Table: INVOICE_LINE                                          */
+/*==============================================================*/
+create table INVOICE_LINE (
+   INVOICE              VARCHAR2(36)          not null,
+   LINE_NO              NUMBER                not null,
+   DESCRIPTION          VARCHAR2(255)         not null,
+   QUANTITY             NUMBER(10,2)          not null,
+   NET_AMOUNT           NUMBER(10,2)          not null,
+   VAT_CATEGORY         VARCHAR2(2)           not null,
+   VAT_RATE             NUMBER(5,2)           not null,
+   VAT_AMOUNT           NUMBER(10,2)          not null,
+   GROSS_AMOUNT         NUMBER(10,2)          not null,
+   TICKET               VARCHAR2(36),
+   BAGGAGE_FEE          VARCHAR2(36),
+   CORRECTS_LINE        NUMBER,
+   constraint PK_INVOICE_LINE primary key (INVOICE, LINE_NO),
+   constraint CK_INVOICE_LINE_CATEGORY check (VAT_CATEGORY in ('S','E'))
+);
+
 /*==============================================================*/
 /* Add Foreign Keys                                             */
 /*==============================================================*/
@@ -365,6 +873,254 @@ alter table TICKET
    add constraint FK_TICKET_TRAVEL_CLASS foreign key (TRAVEL_CLASS)
       references TRAVEL_CLASS (ID);
 
+alter table NOTIFICATION_PREFERENCE
+   add constraint FK_NOTIFICATION_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table GATE
+   add constraint FK_GATE_TERMINAL foreign key (TERMINAL)
+      references TERMINAL (ID);
+
+alter table FLIGHT
+   add constraint FK_FLIGHT_GATE foreign key (GATE)
+      references GATE (ID);
+
+alter table TERMINAL
+   add constraint FK_TERMINAL_PLOT foreign key (PLOT)
+      references PLOT (ID);
+
+alter table PARKING
+   add constraint FK_PARKING_PLOT foreign key (PLOT)
+      references PLOT (ID);
+
+alter table PLOT
+   add constraint FK_PLOT_TERMINAL foreign key (TERMINAL)
+      references TERMINAL (ID);
+
+alter table LEASE
+   add constraint FK_LEASE_SHOP foreign key (SHOP)
+      references SHOP (ID);
+
+alter table LEASE
+   add constraint FK_LEASE_PLOT foreign key (PLOT)
+      references PLOT (ID);
+
+alter table LEASE_TURNOVER
+   add constraint FK_LEASE_TURNOVER_LEASE foreign key (LEASE)
+      references LEASE (ID) on delete cascade;
+
+alter table LEASE_INVOICE
+   add constraint FK_LEASE_INVOICE_LEASE foreign key (LEASE)
+      references LEASE (ID);
+
+alter table SCHEDULE_TEMPLATE
+   add constraint FK_SCHEDULE_TEMPLATE_FROM foreign key ("FROM")
+      references AIRPORT (ID);
+
+alter table SCHEDULE_TEMPLATE
+   add constraint FK_SCHEDULE_TEMPLATE_TO foreign key ("TO")
+      references AIRPORT (ID);
+
+alter table SCHEDULE_TEMPLATE
+   add constraint FK_SCHEDULE_TEMPLATE_PLANE foreign key (PLANE)
+      references PLANE (ID);
+
+alter table SCHEDULE_TEMPLATE
+   add constraint FK_SCHEDULE_TEMPLATE_TERMINAL foreign key (TERMINAL)
+      references TERMINAL (ID);
+
+alter table SCHEDULE_TEMPLATE
+   add constraint FK_SCHEDULE_TEMPLATE_GATE foreign key (GATE)
+      references GATE (ID);
+
+alter table FLIGHT
+   add constraint FK_FLIGHT_TEMPLATE foreign key (TEMPLATE)
+      references SCHEDULE_TEMPLATE (ID) on delete set null;
+
+alter table CALENDAR_SUBSCRIPTION
+   add constraint FK_CALENDAR_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table PASSENGER_LIST_MESSAGE
+   add constraint FK_PASSENGER_LIST_FLIGHT foreign key (FLIGHT)
+      references FLIGHT (ID);
+
+alter table PASSENGER_LIST_ENTRY
+   add constraint FK_PASSENGER_ENTRY_MESSAGE foreign key (MESSAGE)
+      references PASSENGER_LIST_MESSAGE (ID) on delete cascade;
+
+alter table TRAVEL_DOCUMENT
+   add constraint FK_TRAVEL_DOCUMENT_USER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table TICKET
+   add constraint FK_TICKET_TRAVEL_DOCUMENT foreign key (TRAVEL_DOCUMENT)
+      references TRAVEL_DOCUMENT (ID) on delete set null;
+
+alter table BOOKING
+   add constraint FK_BOOKING_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table BOOKING_PASSENGER
+   add constraint FK_BOOKING_PASSENGER_BOOKING foreign key (BOOKING)
+      references BOOKING (ID) on delete cascade;
+
+alter table BOOKING_PASSENGER
+   add constraint FK_BOOKING_PASSENGER_USER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table TICKET
+   add constraint FK_TICKET_BOOKING foreign key (BOOKING)
+      references BOOKING (ID);
+
+alter table TICKET
+   add constraint FK_TICKET_PASSENGER foreign key (PASSENGER)
+      references BOOKING_PASSENGER (ID);
+
+alter table COMPANION
+   add constraint FK_COMPANION_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table TRAVEL_DOCUMENT
+   add constraint FK_TRAVEL_DOCUMENT_COMPANION foreign key (COMPANION)
+      references COMPANION (ID) on delete cascade;
+
+alter table BOOKING_PASSENGER
+   add constraint FK_BOOKING_PASSENGER_COMPANION foreign key (COMPANION)
+      references COMPANION (ID) on delete set null;
+
+alter table BAGGAGE
+   add constraint FK_BAGGAGE_COMPANION foreign key (COMPANION)
+      references COMPANION (ID) on delete set null;
+
+alter table WAITLIST_ENTRY
+   add constraint FK_WAITLIST_ENTRY_FLIGHT foreign key (FLIGHT)
+      references FLIGHT (ID);
+
+alter table WAITLIST_ENTRY
+   add constraint FK_WAITLIST_ENTRY_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table WAITLIST_ENTRY
+   add constraint FK_WAITLIST_ENTRY_BOOKING foreign key (BOOKING)
+      references BOOKING (ID);
+
+alter table WAITLIST_PASSENGER
+   add constraint FK_WAITLIST_PASSENGER_ENTRY foreign key (ENTRY)
+      references WAITLIST_ENTRY (ID) on delete cascade;
+
+alter table WAITLIST_PASSENGER
+   add constraint FK_WAITLIST_PASSENGER_COMPANION foreign key (COMPANION)
+      references COMPANION (ID) on delete set null;
+
+alter table DENIED_BOARDING
+   add constraint FK_DENIED_BOARDING_TICKET foreign key (TICKET)
+      references TICKET (ID);
+
+alter table DENIED_BOARDING
+   add constraint FK_DENIED_BOARDING_FLIGHT foreign key (FLIGHT)
+      references FLIGHT (ID);
+
+alter table DENIED_BOARDING
+   add constraint FK_DENIED_BOARDING_DECIDED_BY foreign key (DECIDED_BY)
+      references AIRPORTUSER (ID);
+
+alter table FLIGHT_FARE
+   add constraint FK_FLIGHT_FARE_FLIGHT foreign key (FLIGHT)
+      references FLIGHT (ID) on delete cascade;
+
+alter table FLIGHT_FARE
+   add constraint FK_FLIGHT_FARE_TRAVEL_CLASS foreign key (TRAVEL_CLASS)
+      references TRAVEL_CLASS (ID);
+
+alter table PAYMENT
+   add constraint FK_PAYMENT_BOOKING foreign key (BOOKING)
+      references BOOKING (ID);
+
+alter table PAYMENT
+   add constraint FK_PAYMENT_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table PAYMENT_REFUND
+   add constraint FK_PAYMENT_REFUND_PAYMENT foreign key (PAYMENT)
+      references PAYMENT (ID);
+
+alter table PAYMENT_REFUND
+   add constraint FK_PAYMENT_REFUND_CREATED_BY foreign key (CREATED_BY)
+      references AIRPORTUSER (ID) on delete set null;
+
+alter table PAYMENT_TICKET
+   add constraint FK_PAYMENT_TICKET_PAYMENT foreign key (PAYMENT)
+      references PAYMENT (ID) on delete cascade;
+
+alter table PAYMENT_TICKET
+   add constraint FK_PAYMENT_TICKET_TICKET foreign key (TICKET)
+      references TICKET (ID);
+
+alter table PAYMENT_TICKET
+   add constraint FK_PAYMENT_TICKET_REFUND foreign key (REFUND)
+      references PAYMENT_REFUND (ID) on delete set null;
+
+alter table INVOICE_ISSUER
+   add constraint FK_INVOICE_ISSUER_CREATED_BY foreign key (CREATED_BY)
+      references AIRPORTUSER (ID) on delete set null;
+
+alter table BILLING_ADDRESS
+   add constraint FK_BILLING_ADDRESS_USER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID) on delete cascade;
+
+alter table BAGGAGE_FEE
+   add constraint FK_BAGGAGE_FEE_BAGGAGE foreign key (BAGGAGE)
+      references BAGGAGE (ID);
+
+alter table BAGGAGE_FEE
+   add constraint FK_BAGGAGE_FEE_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table BAGGAGE_FEE
+   add constraint FK_BAGGAGE_FEE_FLIGHT foreign key (FLIGHT)
+      references FLIGHT (ID);
+
+alter table BAGGAGE_FEE
+   add constraint FK_BAGGAGE_FEE_CREATED_BY foreign key (CREATED_BY)
+      references AIRPORTUSER (ID) on delete set null;
+
+alter table INVOICE
+   add constraint FK_INVOICE_ISSUER foreign key (ISSUER)
+      references INVOICE_ISSUER (ID);
+
+alter table INVOICE
+   add constraint FK_INVOICE_AIRPORTUSER foreign key (AIRPORTUSER)
+      references AIRPORTUSER (ID);
+
+alter table INVOICE
+   add constraint FK_INVOICE_BOOKING foreign key (BOOKING)
+      references BOOKING (ID);
+
+alter table INVOICE
+   add constraint FK_INVOICE_PAYMENT foreign key (PAYMENT)
+      references PAYMENT (ID);
+
+alter table INVOICE
+   add constraint FK_INVOICE_REFUND foreign key (REFUND)
+      references PAYMENT_REFUND (ID);
+
+alter table INVOICE
+   add constraint FK_INVOICE_BAGGAGE_FEE foreign key (BAGGAGE_FEE)
+      references BAGGAGE_FEE (ID);
+
+alter table INVOICE
+   add constraint FK_INVOICE_CORRECTS foreign key (CORRECTS)
+      references INVOICE (ID);
+
+alter table INVOICE
+   add constraint FK_INVOICE_CREATED_BY foreign key (CREATED_BY)
+      references AIRPORTUSER (ID) on delete set null;
+
+alter table INVOICE_LINE
+   add constraint FK_INVOICE_LINE_INVOICE foreign key (INVOICE)
+      references INVOICE (ID);
+
 /*==============================================================*/
 /* Create Indexes                                               */
 /*==============================================================*/
@@ -373,6 +1129,35 @@ create index IDX_FLIGHT_DATES on FLIGHT (SCHEDULED_DEPARTURE, SCHEDULED_ARRIVAL)
 create index IDX_BAGGAGE_TRACKING on BAGGAGE (TRACKING_NUMBER);
 create index IDX_AIRPORTUSER_EMAIL on AIRPORTUSER (EMAIL);
 create index IDX_TICKET_BOOKING on TICKET (BOOKING_DATE);
+create index IDX_FLIGHT_CREW_MEMBER on FLIGHT_CREW (CREW_MEMBER);
+create index IDX_FLIGHT_PLANE on FLIGHT (PLANE, SCHEDULED_DEPARTURE);
+create index IDX_PLANE_HANGAR on PLANE (HANGAR);
+create index IDX_HANGAR_PLOT on HANGAR (PLOT);
+create index IDX_PLOT_TERMINAL on PLOT (TERMINAL);
+create index IDX_LEASE_SHOP on LEASE (SHOP);
+create index IDX_LEASE_INVOICE_PERIOD on LEASE_INVOICE (PERIOD);
+create index IDX_FLIGHT_NUMBER on FLIGHT (FLIGHT_NUMBER, SCHEDULED_DEPARTURE);
+create index IDX_FLIGHT_TEMPLATE on FLIGHT (TEMPLATE, SCHEDULED_DEPARTURE);
+create index IDX_TICKET_BOOKING_PASSENGER on TICKET (BOOKING, PASSENGER);
+create index IDX_BOOKING_PASSENGER_USER on BOOKING_PASSENGER (AIRPORTUSER);
+create index IDX_COMPANION_USER on COMPANION (AIRPORTUSER);
+create index IDX_WAITLIST_ENTRY_FLIGHT on WAITLIST_ENTRY (FLIGHT, STATUS, CREATED_AT);
+create index IDX_WAITLIST_ENTRY_USER on WAITLIST_ENTRY (AIRPORTUSER);
+create index IDX_DENIED_BOARDING_FLIGHT on DENIED_BOARDING (FLIGHT);
+create index IDX_BOOKING_HOLD on BOOKING (STATUS, HOLD_EXPIRES_AT);
+create index IDX_PAYMENT_BOOKING on PAYMENT (BOOKING);
+create index IDX_PAYMENT_REFUND_PAYMENT on PAYMENT_REFUND (PAYMENT);
+create index IDX_PAYMENT_TICKET_TICKET on PAYMENT_TICKET (TICKET);
+create index IDX_IDEMPOTENCY_KEY_CREATED on IDEMPOTENCY_KEY (CREATED_AT);
+create index IDX_BAGGAGE_FEE_USER on BAGGAGE_FEE (AIRPORTUSER);
+create index IDX_INVOICE_ISSUER_VALID on INVOICE_ISSUER (VALID_FROM);
+create index IDX_INVOICE_USER on INVOICE (AIRPORTUSER, ISSUED_AT);
+create index IDX_INVOICE_ISSUED on INVOICE (ISSUED_AT);
+create index IDX_INVOICE_BOOKING on INVOICE (BOOKING);
+create index IDX_INVOICE_PAYMENT on INVOICE (PAYMENT);
+create index IDX_INVOICE_REFUND on INVOICE (REFUND);
+create index IDX_INVOICE_BAGGAGE_FEE on INVOICE (BAGGAGE_FEE);
+create index IDX_INVOICE_CORRECTS on INVOICE (CORRECTS);
 
 CREATE SEQUENCE travel_class_seq START WITH 1;
 
@@ -66,6 +66,34 @@ drop table TRAVEL_CLASS cascade constraints;
 drop table MAINTENANCE_LOG cascade constraints;
 drop table CREW_MEMBER cascade constraints;
 drop table FLIGHT_CREW cascade constraints;
+drop table NOTIFICATION_PREFERENCE cascade constraints;
+drop table GATE cascade constraints;
+drop table PARKING cascade constraints;
+drop table LEASE_INVOICE cascade constraints;
+drop table LEASE_TURNOVER cascade constraints;
+drop table LEASE cascade constraints;
+drop table SCHEDULE_TEMPLATE cascade constraints;
+drop table CALENDAR_SUBSCRIPTION cascade constraints;
+drop table PASSENGER_LIST_ENTRY cascade constraints;
+drop table PASSENGER_LIST_MESSAGE cascade constraints;
+drop table TRAVEL_DOCUMENT cascade constraints;
+drop table BOOKING_PASSENGER cascade constraints;
+drop table BOOKING cascade constraints;
+drop table COMPANION cascade constraints;
+drop table DENIED_BOARDING cascade constraints;
+drop table WAITLIST_PASSENGER cascade constraints;
+drop table WAITLIST_ENTRY cascade constraints;
+drop table IDEMPOTENCY_KEY cascade constraints;
+drop table PAYMENT_TICKET cascade constraints;
+drop table PAYMENT_REFUND cascade constraints;
+drop table PAYMENT cascade constraints;
+drop table FLIGHT_FARE cascade constraints;
+drop table INVOICE_LINE cascade constraints;
+drop table INVOICE cascade constraints;
+drop table INVOICE_SEQUENCE cascade constraints;
+drop table BAGGAGE_FEE cascade constraints;
+drop table BILLING_ADDRESS cascade constraints;
+drop table INVOICE_ISSUER cascade constraints;
 
 /* Drop Sequences */
 drop sequence travel_class_seq;
@@ -99,5 +127,188 @@ drop procedure UpdateFlight;
 drop procedure DeleteFlight;
 drop procedure GetFlightStatusesByID;
 drop procedure GetMaintenanceLogByID;
-drop procedure GetTicketByID
-drop procedure GetTicketByUserID
\ No newline at end of file
+drop procedure GetTicketByID;
+drop procedure GetTicketByUserID;
+drop procedure GetTicketsByFlightID;
+drop procedure GetNotificationPreference;
+drop procedure SaveNotificationPreference;
+drop procedure GetAllGates;
+drop procedure GetGateByID;
+drop procedure CreateGate;
+drop procedure UpdateGate;
+drop procedure DeleteGate;
+drop procedure GetPlaneByID;
+drop procedure GetFlightsByDepartureWindow;
+drop procedure GetAllTerminals;
+drop procedure GetTerminalByID;
+drop procedure CreateTerminal;
+drop procedure UpdateTerminal;
+drop procedure DeleteTerminal;
+drop procedure GetTerminalLoad;
+drop procedure GetAllCrewMembers;
+drop procedure GetCrewMemberByID;
+drop procedure CreateCrewMember;
+drop procedure UpdateCrewMember;
+drop procedure DeleteCrewMember;
+drop procedure GetFlightCrew;
+drop procedure CreateFlightCrew;
+drop procedure DeleteFlightCrew;
+drop procedure GetCrewDuties;
+drop procedure GetAllPilots;
+drop procedure GetPilotByID;
+drop procedure CreatePilot;
+drop procedure UpdatePilot;
+drop procedure DeletePilot;
+drop procedure AddPilotFlightHours;
+drop procedure GetAllPlanes;
+drop procedure CreatePlane;
+drop procedure UpdatePlane;
+drop procedure DeletePlane;
+drop procedure GetFleetOverview;
+drop procedure GetFlightsByPlane;
+drop procedure GetMaintenanceLogsByPlane;
+drop procedure CreateMaintenanceLog;
+drop procedure GetMaintenanceSchedule;
+drop procedure GetAllHangars;
+drop procedure GetHangarByID;
+drop procedure CreateHangar;
+drop procedure UpdateHangar;
+drop procedure DeleteHangar;
+drop procedure GetPlanesByHangar;
+drop procedure GetAllPlots;
+drop procedure GetPlotByID;
+drop procedure GetAllPlotTypes;
+drop procedure CreatePlot;
+drop procedure UpdatePlot;
+drop procedure DeletePlot;
+drop procedure GetPlotOccupants;
+drop procedure GetPlotLoad;
+drop procedure RefreshPlotStatus;
+drop procedure GetAllParkings;
+drop procedure GetParkingByID;
+drop procedure CreateParking;
+drop procedure UpdateParking;
+drop procedure DeleteParking;
+drop procedure GetAllShops;
+drop procedure GetShopByID;
+drop procedure GetAllShopTypes;
+drop procedure CreateShop;
+drop procedure UpdateShop;
+drop procedure DeleteShop;
+drop procedure GetAllLeases;
+drop procedure GetLeaseByID;
+drop procedure GetLeasesInPeriod;
+drop procedure CreateLease;
+drop procedure UpdateLease;
+drop procedure DeleteLease;
+drop procedure SetLeaseTurnover;
+drop procedure GetLeaseTurnovers;
+drop procedure SaveLeaseInvoice;
+drop procedure GetLeaseInvoices;
+drop procedure CalculateConcessionRevenue;
+drop procedure CreateAirport;
+drop procedure UpdateAirport;
+drop procedure DeleteAirport;
+drop procedure GetAirportUsage;
+drop procedure CreateAirline;
+drop procedure UpdateAirline;
+drop procedure DeleteAirline;
+drop procedure GetAirlineUsage;
+drop procedure GetScheduleTemplates;
+drop procedure GetScheduleTemplateByID;
+drop procedure CreateScheduleTemplate;
+drop procedure UpdateScheduleTemplate;
+drop procedure DeleteScheduleTemplate;
+drop procedure GetTemplateFlights;
+drop procedure SetFlightTemplateRevision;
+drop procedure DeleteUnbookedFlight;
+drop procedure GetCalendarSubscription;
+drop procedure GetCalendarSubscriber;
+drop procedure SaveCalendarSubscription;
+drop procedure DeleteCalendarSubscription;
+drop procedure GetFlightManifest;
+drop procedure GetPassengerListMessages;
+drop procedure GetPassengerListEntries;
+drop procedure CreatePassengerListMessage;
+drop procedure AddPassengerListEntry;
+drop procedure GetTravelDocuments;
+drop procedure GetTravelDocumentByID;
+drop procedure CreateTravelDocument;
+drop procedure UpdateTravelDocument;
+drop procedure DeleteTravelDocument;
+drop procedure CheckInTicket;
+drop procedure GetBookingByLocator;
+drop procedure GetBookingsByUserID;
+drop procedure GetBookingPassengers;
+drop procedure GetBookingTickets;
+drop procedure GetFlightTicketCount;
+drop procedure CreateBooking;
+drop procedure AddBookingPassenger;
+drop procedure AddBookingTicket;
+drop procedure CancelTicket;
+drop procedure UpdateBookingStatus;
+drop procedure GetCompanions;
+drop procedure GetCompanionByID;
+drop procedure CreateCompanion;
+drop procedure UpdateCompanion;
+drop procedure DeleteCompanion;
+drop procedure GetFlightOverbooking;
+drop procedure SetFlightOverbooking;
+drop procedure SetAirlineOverbooking;
+drop procedure GetFlightCheckedInCount;
+drop procedure GetWaitlistEntryByID;
+drop procedure GetWaitlistByUserID;
+drop procedure GetFlightWaitlist;
+drop procedure GetStaleWaitlistEntries;
+drop procedure GetWaitlistPassengers;
+drop procedure GetFlightHeldSeats;
+drop procedure CreateWaitlistEntry;
+drop procedure AddWaitlistPassenger;
+drop procedure UpdateWaitlistEntry;
+drop procedure GetDeniedBoardingByFlight;
+drop procedure GetDeniedBoardingByTicket;
+drop procedure CreateBoardingVolunteer;
+drop procedure DeleteBoardingVolunteer;
+drop procedure DenyBoarding;
+drop procedure ConfirmBooking;
+drop procedure GetExpiredHolds;
+drop procedure GetFlightFares;
+drop procedure DeleteFlightFares;
+drop procedure AddFlightFare;
+drop procedure GetPaymentByID;
+drop procedure GetPaymentByReference;
+drop procedure GetPaymentsByBooking;
+drop procedure CreatePayment;
+drop procedure AddPaymentTicket;
+drop procedure UpdatePayment;
+drop procedure GetPaymentTickets;
+drop procedure GetPaymentRefunds;
+drop procedure GetRefundByReference;
+drop procedure CreatePaymentRefund;
+drop procedure SetPaymentTicketRefund;
+drop procedure CompletePaymentRefund;
+drop procedure ClaimIdempotencyKey;
+drop procedure GetIdempotencyKey;
+drop procedure SaveIdempotencyResponse;
+drop procedure DeleteIdempotencyKey;
+drop procedure DeleteIdempotencyKeysBefore;
+drop procedure GetInvoiceIssuer;
+drop procedure GetInvoiceIssuerByID;
+drop procedure CreateInvoiceIssuer;
+drop procedure GetBillingAddress;
+drop procedure SaveBillingAddress;
+drop procedure DeleteBillingAddress;
+drop procedure GetBaggageFeeByID;
+drop procedure GetBaggageFeeByBaggage;
+drop procedure CreateBaggageFee;
+drop procedure CalculateBaggageFeeRevenue;
+drop procedure NextInvoiceNumber;
+drop procedure CreateInvoice;
+drop procedure AddInvoiceLine;
+drop procedure GetInvoiceByID;
+drop procedure GetInvoicesByUser;
+drop procedure GetInvoicesBySource;
+drop procedure GetCreditNotes;
+drop procedure SearchInvoices;
+drop procedure GetInvoiceLines;
+drop procedure GetUninvoicedSources;
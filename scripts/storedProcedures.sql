@@ -104,17 +104,21 @@ CREATE OR REPLACE PROCEDURE CreateFlight(
     plane VARCHAR2,
     terminal VARCHAR2,
     status NUMBER,
-    scheduled_departure TIMESTAMP,
-    actual_departure TIMESTAMP,
-    scheduled_arrival TIMESTAMP,
-    actual_arrival TIMESTAMP,
+    scheduled_departure TIMESTAMP WITH TIME ZONE,
+    actual_departure TIMESTAMP WITH TIME ZONE,
+    scheduled_arrival TIMESTAMP WITH TIME ZONE,
+    actual_arrival TIMESTAMP WITH TIME ZONE,
     gate VARCHAR2,
-    baggage_claim VARCHAR2
+    baggage_claim VARCHAR2,
+    flight_number VARCHAR2,
+    template VARCHAR2,
+    template_revision NUMBER
 )
 AS
 BEGIN
-    INSERT INTO FLIGHT (ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM) 
-    VALUES (id, flight_from, flight_to, pilot, plane, terminal, status, scheduled_departure, actual_departure, scheduled_arrival, actual_arrival, gate, baggage_claim);
+    INSERT INTO FLIGHT (ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM, FLIGHT_NUMBER, TEMPLATE, TEMPLATE_REVISION) 
+    VALUES (id, flight_from, flight_to, pilot, plane, terminal, status, SYS_EXTRACT_UTC(scheduled_departure), SYS_EXTRACT_UTC(actual_departure), 
+        SYS_EXTRACT_UTC(scheduled_arrival), SYS_EXTRACT_UTC(actual_arrival), gate, baggage_claim, flight_number, template, template_revision);
 END;
 /
 
@@ -127,7 +131,7 @@ CREATE OR REPLACE PROCEDURE GetAllFlights(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM 
+    SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM, FLIGHT_NUMBER, TEMPLATE, TEMPLATE_REVISION 
     FROM FLIGHT 
     ORDER BY ID DESC 
     OFFSET page_offset ROWS FETCH NEXT page_limit ROWS ONLY;
@@ -142,7 +146,7 @@ CREATE OR REPLACE PROCEDURE GetFlightByID(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM 
+    SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM, FLIGHT_NUMBER, TEMPLATE, TEMPLATE_REVISION 
     FROM FLIGHT 
     WHERE ID = p_id;
 END;
@@ -167,12 +171,13 @@ CREATE OR REPLACE PROCEDURE UpdateFlight(
     plane VARCHAR2,
     terminal VARCHAR2,
     status NUMBER,
-    scheduled_departure TIMESTAMP,
-    actual_departure TIMESTAMP,
-    scheduled_arrival TIMESTAMP,
-    actual_arrival TIMESTAMP,
+    scheduled_departure TIMESTAMP WITH TIME ZONE,
+    actual_departure TIMESTAMP WITH TIME ZONE,
+    scheduled_arrival TIMESTAMP WITH TIME ZONE,
+    actual_arrival TIMESTAMP WITH TIME ZONE,
     gate VARCHAR2,
-    baggage_claim VARCHAR2
+    baggage_claim VARCHAR2,
+    flight_number VARCHAR2
 )
 AS
 BEGIN
@@ -183,12 +188,13 @@ BEGIN
         PLANE = plane,
         TERMINAL = terminal,
         STATUS = status,
-        SCHEDULED_DEPARTURE = scheduled_departure,
-        ACTUAL_DEPARTURE = actual_departure,
-        SCHEDULED_ARRIVAL = scheduled_arrival,
-        ACTUAL_ARRIVAL = actual_arrival,
+        SCHEDULED_DEPARTURE = SYS_EXTRACT_UTC(scheduled_departure),
+        ACTUAL_DEPARTURE = SYS_EXTRACT_UTC(actual_departure),
+        SCHEDULED_ARRIVAL = SYS_EXTRACT_UTC(scheduled_arrival),
+        ACTUAL_ARRIVAL = SYS_EXTRACT_UTC(actual_arrival),
         GATE = gate,
-        BAGGAGE_CLAIM = baggage_claim
+        BAGGAGE_CLAIM = baggage_claim,
+        FLIGHT_NUMBER = flight_number
     WHERE ID = p_id;
 END;
 /
@@ -218,21 +224,28 @@ BEGIN
         FLIGHT."FROM",
         FLIGHT."TO",
         TICKET.BOOKING_DATE,
-        CASE 
-            WHEN FLIGHT.SCHEDULED_DEPARTURE = FLIGHT.ACTUAL_DEPARTURE 
-            THEN TO_CHAR(FLIGHT.SCHEDULED_DEPARTURE, 'dd.mm.yyyy HH24:MI') 
-            ELSE TO_CHAR(FLIGHT.ACTUAL_DEPARTURE, 'dd.mm.yyyy HH24:MI') 
-        END AS DEPARTURE_TIME,
+        COALESCE(FLIGHT.ACTUAL_DEPARTURE, FLIGHT.SCHEDULED_DEPARTURE) AS DEPARTURE_TIME,
         TRAVEL_CLASS.NAME AS TRAVEL_CLASS,
         TICKET.PRICE,
         FLIGHT.GATE,
         FLIGHT.BAGGAGE_CLAIM,
         TICKET.STATUS,
         TICKET.AIRPORTUSER,
-        TICKET.FLIGHT
+        TICKET.FLIGHT,
+        PLANE.MODEL,
+        TICKET.BOOKING,
+        BOOKING.LOCATOR,
+        TICKET.PASSENGER,
+        BOOKING_PASSENGER.FIRSTNAME,
+        BOOKING_PASSENGER.LASTNAME,
+        BOOKING_PASSENGER.AIRPORTUSER,
+        BOOKING_PASSENGER.COMPANION
     FROM TICKET 
     LEFT JOIN FLIGHT ON TICKET.FLIGHT = FLIGHT.ID 
     LEFT JOIN TRAVEL_CLASS ON TICKET.TRAVEL_CLASS = TRAVEL_CLASS.ID 
+    LEFT JOIN PLANE ON FLIGHT.PLANE = PLANE.ID 
+    LEFT JOIN BOOKING ON TICKET.BOOKING = BOOKING.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
     ORDER BY TICKET.BOOKING_DATE DESC
     OFFSET page_offset ROWS FETCH NEXT page_limit ROWS ONLY;
 END;
@@ -272,24 +285,33 @@ BEGIN
         FLIGHT."FROM",
         FLIGHT."TO",
         TICKET.BOOKING_DATE,
-        CASE 
-            WHEN FLIGHT.SCHEDULED_DEPARTURE = FLIGHT.ACTUAL_DEPARTURE 
-            THEN TO_CHAR(FLIGHT.SCHEDULED_DEPARTURE, 'dd.mm.yyyy HH24:MI') 
-            ELSE TO_CHAR(FLIGHT.ACTUAL_DEPARTURE, 'dd.mm.yyyy HH24:MI') 
-        END AS DEPARTURE_TIME,
+        COALESCE(FLIGHT.ACTUAL_DEPARTURE, FLIGHT.SCHEDULED_DEPARTURE) AS DEPARTURE_TIME,
         TRAVEL_CLASS.NAME AS TRAVEL_CLASS,
         TICKET.PRICE,
         FLIGHT.GATE,
         FLIGHT.BAGGAGE_CLAIM,
-        TICKET.STATUS
+        TICKET.STATUS,
+        TICKET.AIRPORTUSER,
+        TICKET.FLIGHT,
+        PLANE.MODEL,
+        TICKET.BOOKING,
+        BOOKING.LOCATOR,
+        TICKET.PASSENGER,
+        BOOKING_PASSENGER.FIRSTNAME,
+        BOOKING_PASSENGER.LASTNAME,
+        BOOKING_PASSENGER.AIRPORTUSER,
+        BOOKING_PASSENGER.COMPANION
     FROM TICKET 
     LEFT JOIN FLIGHT ON TICKET.FLIGHT = FLIGHT.ID 
     LEFT JOIN TRAVEL_CLASS ON TICKET.TRAVEL_CLASS = TRAVEL_CLASS.ID 
+    LEFT JOIN PLANE ON FLIGHT.PLANE = PLANE.ID 
+    LEFT JOIN BOOKING ON TICKET.BOOKING = BOOKING.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
     WHERE TICKET.ID = p_id;
 END;
 /
 
--- Get tickets by user ID procedure
+-- Get the tickets a user holds or travels on as booking passenger
 CREATE OR REPLACE PROCEDURE GetTicketsByUserID(
     p_user_id VARCHAR2,
     result_cursor OUT SYS_REFCURSOR
@@ -303,22 +325,30 @@ BEGIN
         FLIGHT."FROM",
         FLIGHT."TO",
         TICKET.BOOKING_DATE,
-        CASE 
-            WHEN FLIGHT.SCHEDULED_DEPARTURE = FLIGHT.ACTUAL_DEPARTURE 
-            THEN TO_CHAR(FLIGHT.SCHEDULED_DEPARTURE, 'dd.mm.yyyy HH24:MI') 
-            ELSE TO_CHAR(FLIGHT.ACTUAL_DEPARTURE, 'dd.mm.yyyy HH24:MI') 
-        END AS DEPARTURE_TIME,
+        COALESCE(FLIGHT.ACTUAL_DEPARTURE, FLIGHT.SCHEDULED_DEPARTURE) AS DEPARTURE_TIME,
         TRAVEL_CLASS.NAME AS TRAVEL_CLASS,
         TICKET.PRICE,
         FLIGHT.GATE,
         FLIGHT.BAGGAGE_CLAIM,
         TICKET.STATUS,
         TICKET.AIRPORTUSER,
-        TICKET.FLIGHT
+        TICKET.FLIGHT,
+        PLANE.MODEL,
+        TICKET.BOOKING,
+        BOOKING.LOCATOR,
+        TICKET.PASSENGER,
+        BOOKING_PASSENGER.FIRSTNAME,
+        BOOKING_PASSENGER.LASTNAME,
+        BOOKING_PASSENGER.AIRPORTUSER,
+        BOOKING_PASSENGER.COMPANION
     FROM TICKET 
     LEFT JOIN FLIGHT ON TICKET.FLIGHT = FLIGHT.ID 
     LEFT JOIN TRAVEL_CLASS ON TICKET.TRAVEL_CLASS = TRAVEL_CLASS.ID 
+    LEFT JOIN PLANE ON FLIGHT.PLANE = PLANE.ID 
+    LEFT JOIN BOOKING ON TICKET.BOOKING = BOOKING.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
     WHERE TICKET.AIRPORTUSER = p_user_id
+       OR BOOKING_PASSENGER.AIRPORTUSER = p_user_id
     ORDER BY TICKET.BOOKING_DATE DESC;
 END;
 /
@@ -342,7 +372,7 @@ CREATE OR REPLACE PROCEDURE GetAllBaggage(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING 
+    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING, COMPANION
     FROM BAGGAGE 
     ORDER BY ID DESC
     OFFSET page_offset ROWS FETCH NEXT page_limit ROWS ONLY;
@@ -367,7 +397,7 @@ CREATE OR REPLACE PROCEDURE GetBaggageByID(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING 
+    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING, COMPANION
     FROM BAGGAGE 
     WHERE ID = p_id;
 END;
@@ -381,7 +411,7 @@ CREATE OR REPLACE PROCEDURE GetBaggageByUserID(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING 
+    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING, COMPANION
     FROM BAGGAGE 
     WHERE AIRPORTUSER = p_user_id 
     ORDER BY ID DESC;
@@ -396,7 +426,7 @@ CREATE OR REPLACE PROCEDURE GetBaggageByFlightID(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING 
+    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING, COMPANION
     FROM BAGGAGE 
     WHERE FLIGHT = p_flight_id 
     ORDER BY ID DESC;
@@ -411,7 +441,7 @@ CREATE OR REPLACE PROCEDURE GetBaggageByTrackingNumber(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING 
+    SELECT ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING, COMPANION
     FROM BAGGAGE 
     WHERE TRACKING_NUMBER = p_tracking_number;
 END;
@@ -426,12 +456,13 @@ CREATE OR REPLACE PROCEDURE CreateBaggage(
     p_weight NUMBER,
     p_tracking_number VARCHAR2,
     p_status VARCHAR2,
-    p_special_handling VARCHAR2
+    p_special_handling VARCHAR2,
+    p_companion VARCHAR2
 )
 AS
 BEGIN
-    INSERT INTO BAGGAGE (ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING)
-    VALUES (p_id, p_airportuser, p_flight, p_size, p_weight, p_tracking_number, p_status, p_special_handling);
+    INSERT INTO BAGGAGE (ID, AIRPORTUSER, FLIGHT, "SIZE", WEIGHT, TRACKING_NUMBER, STATUS, SPECIAL_HANDLING, COMPANION)
+    VALUES (p_id, p_airportuser, p_flight, p_size, p_weight, p_tracking_number, p_status, p_special_handling, p_companion);
 END;
 /
 
@@ -444,7 +475,8 @@ CREATE OR REPLACE PROCEDURE UpdateBaggage(
     p_weight NUMBER,
     p_tracking_number VARCHAR2,
     p_status VARCHAR2,
-    p_special_handling VARCHAR2
+    p_special_handling VARCHAR2,
+    p_companion VARCHAR2
 )
 AS
 BEGIN
@@ -455,7 +487,8 @@ BEGIN
         WEIGHT = p_weight,
         TRACKING_NUMBER = p_tracking_number,
         STATUS = p_status,
-        SPECIAL_HANDLING = p_special_handling
+        SPECIAL_HANDLING = p_special_handling,
+        COMPANION = p_companion
     WHERE ID = p_id;
 END;
 /
@@ -467,7 +500,7 @@ CREATE OR REPLACE PROCEDURE GetAllAirports(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, NAME, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE 
+    SELECT ID, NAME, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE, ICAO 
     FROM AIRPORT 
     ORDER BY NAME;
 END;
@@ -481,7 +514,7 @@ CREATE OR REPLACE PROCEDURE GetAirportByID(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, NAME, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE 
+    SELECT ID, NAME, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE, ICAO 
     FROM AIRPORT 
     WHERE ID = p_id;
 END;
@@ -494,7 +527,7 @@ CREATE OR REPLACE PROCEDURE GetAllAirlines(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, NAME, COUNTRY, LOGO_URL, ACTIVE 
+    SELECT ID, NAME, COUNTRY, LOGO_URL, ACTIVE, ICAO 
     FROM AIRLINE 
     ORDER BY NAME;
 END;
@@ -508,7 +541,7 @@ CREATE OR REPLACE PROCEDURE GetAirlineByID(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT ID, NAME, COUNTRY, LOGO_URL, ACTIVE 
+    SELECT ID, NAME, COUNTRY, LOGO_URL, ACTIVE, ICAO 
     FROM AIRLINE 
     WHERE ID = p_id;
 END;
@@ -521,7 +554,8 @@ CREATE OR REPLACE PROCEDURE GetMaintenanceLogs(
 AS
 BEGIN
     OPEN result_cursor FOR
-    SELECT * FROM MAINTENANCE_LOG;
+    SELECT * FROM MAINTENANCE_LOG 
+    ORDER BY MAINTENANCE_DATE DESC;
 END;
 /
 
@@ -571,12 +605,13 @@ CREATE OR REPLACE PROCEDURE UpdateFlight (
    planeID VARCHAR2,
    terminalID VARCHAR2,
    statusID NUMBER,
-   scheduledDeparture TIMESTAMP,
-   actualDeparture TIMESTAMP,
-   scheduledArrival TIMESTAMP,
-   actualArrival TIMESTAMP,
+   scheduledDeparture TIMESTAMP WITH TIME ZONE,
+   actualDeparture TIMESTAMP WITH TIME ZONE,
+   scheduledArrival TIMESTAMP WITH TIME ZONE,
+   actualArrival TIMESTAMP WITH TIME ZONE,
    gate VARCHAR2,
-   baggageClaim VARCHAR2
+   baggageClaim VARCHAR2,
+   flightNumber VARCHAR2
 )
 AS
 BEGIN
@@ -587,12 +622,13 @@ BEGIN
       PLANE = planeID,
       TERMINAL = terminalID,
       STATUS = statusID,
-      SCHEDULED_DEPARTURE = scheduledDeparture,
-      ACTUAL_DEPARTURE = actualDeparture,
-      SCHEDULED_ARRIVAL = scheduledArrival,
-      ACTUAL_ARRIVAL = actualArrival,
+      SCHEDULED_DEPARTURE = SYS_EXTRACT_UTC(scheduledDeparture),
+      ACTUAL_DEPARTURE = SYS_EXTRACT_UTC(actualDeparture),
+      SCHEDULED_ARRIVAL = SYS_EXTRACT_UTC(scheduledArrival),
+      ACTUAL_ARRIVAL = SYS_EXTRACT_UTC(actualArrival),
       GATE = gate,
-      BAGGAGE_CLAIM = baggageClaim
+      BAGGAGE_CLAIM = baggageClaim,
+      FLIGHT_NUMBER = flightNumber
    WHERE ID = flight_id;
 END;
 /
@@ -619,7 +655,7 @@ CREATE OR REPLACE PROCEDURE GetFlightByIDFixed (
 AS
 BEGIN
    OPEN result_cursor FOR
-   SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM 
+   SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM, FLIGHT_NUMBER, TEMPLATE, TEMPLATE_REVISION 
    FROM FLIGHT WHERE ID = flight_id;
 END;
 /
@@ -701,4 +737,3673 @@ AS
 BEGIN
     UPDATE AIRPORTUSER SET ACTIVE = active_status WHERE ID = user_id;
 END;
-/
\ No newline at end of file
+/
+
+/*==============================================================*/
+/* Notification Procedures                                      */
+/*==============================================================*/
+
+-- Get tickets by flight ID procedure
+CREATE OR REPLACE PROCEDURE GetTicketsByFlightID(
+    p_flight_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        TICKET.ID,
+        TICKET.SEAT_NUMBER,
+        FLIGHT."FROM",
+        FLIGHT."TO",
+        TICKET.BOOKING_DATE,
+        COALESCE(FLIGHT.ACTUAL_DEPARTURE, FLIGHT.SCHEDULED_DEPARTURE) AS DEPARTURE_TIME,
+        TRAVEL_CLASS.NAME AS TRAVEL_CLASS,
+        TICKET.PRICE,
+        FLIGHT.GATE,
+        FLIGHT.BAGGAGE_CLAIM,
+        TICKET.STATUS,
+        TICKET.AIRPORTUSER,
+        TICKET.FLIGHT,
+        PLANE.MODEL,
+        TICKET.BOOKING,
+        BOOKING.LOCATOR,
+        TICKET.PASSENGER,
+        BOOKING_PASSENGER.FIRSTNAME,
+        BOOKING_PASSENGER.LASTNAME,
+        BOOKING_PASSENGER.AIRPORTUSER,
+        BOOKING_PASSENGER.COMPANION
+    FROM TICKET 
+    LEFT JOIN FLIGHT ON TICKET.FLIGHT = FLIGHT.ID 
+    LEFT JOIN TRAVEL_CLASS ON TICKET.TRAVEL_CLASS = TRAVEL_CLASS.ID 
+    LEFT JOIN PLANE ON FLIGHT.PLANE = PLANE.ID 
+    LEFT JOIN BOOKING ON TICKET.BOOKING = BOOKING.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
+    WHERE TICKET.FLIGHT = p_flight_id
+    ORDER BY TICKET.BOOKING_DATE;
+END;
+/
+
+-- Get notification preference of a user
+CREATE OR REPLACE PROCEDURE GetNotificationPreference(
+    p_user_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT AIRPORTUSER, LANGUAGE, EMAIL, SMS, PUSH, PUSH_ENDPOINT 
+    FROM NOTIFICATION_PREFERENCE 
+    WHERE AIRPORTUSER = p_user_id;
+END;
+/
+
+-- Create or replace notification preference of a user
+CREATE OR REPLACE PROCEDURE SaveNotificationPreference(
+    p_user_id VARCHAR2,
+    p_language VARCHAR2,
+    p_email NUMBER,
+    p_sms NUMBER,
+    p_push NUMBER,
+    p_push_endpoint VARCHAR2
+)
+AS
+BEGIN
+    MERGE INTO NOTIFICATION_PREFERENCE np
+    USING (SELECT p_user_id AS AIRPORTUSER FROM DUAL) src
+    ON (np.AIRPORTUSER = src.AIRPORTUSER)
+    WHEN MATCHED THEN UPDATE SET 
+        LANGUAGE = p_language,
+        EMAIL = p_email,
+        SMS = p_sms,
+        PUSH = p_push,
+        PUSH_ENDPOINT = p_push_endpoint
+    WHEN NOT MATCHED THEN INSERT (AIRPORTUSER, LANGUAGE, EMAIL, SMS, PUSH, PUSH_ENDPOINT)
+        VALUES (p_user_id, p_language, p_email, p_sms, p_push, p_push_endpoint);
+END;
+/
+
+/*==============================================================*/
+/* Gate Planning Procedures                                     */
+/*==============================================================*/
+
+-- Get all gates
+CREATE OR REPLACE PROCEDURE GetAllGates(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS 
+    FROM GATE 
+    ORDER BY TERMINAL, ID;
+END;
+/
+
+-- Get gate by ID
+CREATE OR REPLACE PROCEDURE GetGateByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS 
+    FROM GATE 
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a gate
+CREATE OR REPLACE PROCEDURE CreateGate(
+    p_id VARCHAR2,
+    p_terminal VARCHAR2,
+    p_max_aircraft_code VARCHAR2,
+    p_stand_type VARCHAR2,
+    p_area VARCHAR2,
+    p_status VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO GATE (ID, TERMINAL, MAX_AIRCRAFT_CODE, STAND_TYPE, AREA, STATUS) 
+    VALUES (p_id, p_terminal, p_max_aircraft_code, p_stand_type, p_area, p_status);
+END;
+/
+
+-- Update a gate
+CREATE OR REPLACE PROCEDURE UpdateGate(
+    p_id VARCHAR2,
+    p_terminal VARCHAR2,
+    p_max_aircraft_code VARCHAR2,
+    p_stand_type VARCHAR2,
+    p_area VARCHAR2,
+    p_status VARCHAR2
+)
+AS
+BEGIN
+    UPDATE GATE SET 
+        TERMINAL = p_terminal,
+        MAX_AIRCRAFT_CODE = p_max_aircraft_code,
+        STAND_TYPE = p_stand_type,
+        AREA = p_area,
+        STATUS = p_status
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a gate
+CREATE OR REPLACE PROCEDURE DeleteGate(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM GATE WHERE ID = p_id;
+END;
+/
+
+-- Get plane by ID
+CREATE OR REPLACE PROCEDURE GetPlaneByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, MODEL, SEATS, AIRLINE, HANGAR, MANUFACTURING_YEAR, MAX_TAKEOFF_WEIGHT, FUEL_CAPACITY, STATUS 
+    FROM PLANE 
+    WHERE ID = p_id;
+END;
+/
+
+-- Get flights departing within a time window
+CREATE OR REPLACE PROCEDURE GetFlightsByDepartureWindow(
+    p_start TIMESTAMP WITH TIME ZONE,
+    p_end TIMESTAMP WITH TIME ZONE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM, FLIGHT_NUMBER, TEMPLATE, TEMPLATE_REVISION 
+    FROM FLIGHT 
+    WHERE SCHEDULED_DEPARTURE >= SYS_EXTRACT_UTC(p_start) AND SCHEDULED_DEPARTURE < SYS_EXTRACT_UTC(p_end)
+    ORDER BY SCHEDULED_DEPARTURE;
+END;
+/
+
+/*==============================================================*/
+/* Terminal Procedures                                          */
+/*==============================================================*/
+
+-- Get all terminals
+CREATE OR REPLACE PROCEDURE GetAllTerminals(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, CAPACITY, STATUS, FLOOR_COUNT, SERVICES, OPENING_HOURS, PLOT 
+    FROM TERMINAL 
+    ORDER BY ID;
+END;
+/
+
+-- Get terminal by ID
+CREATE OR REPLACE PROCEDURE GetTerminalByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, CAPACITY, STATUS, FLOOR_COUNT, SERVICES, OPENING_HOURS, PLOT 
+    FROM TERMINAL 
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a terminal
+CREATE OR REPLACE PROCEDURE CreateTerminal(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_capacity NUMBER,
+    p_status VARCHAR2,
+    p_floor_count NUMBER,
+    p_services VARCHAR2,
+    p_opening_hours VARCHAR2,
+    p_plot VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO TERMINAL (ID, NAME, CAPACITY, STATUS, FLOOR_COUNT, SERVICES, OPENING_HOURS, PLOT) 
+    VALUES (p_id, p_name, p_capacity, p_status, p_floor_count, p_services, p_opening_hours, p_plot);
+END;
+/
+
+-- Update a terminal
+CREATE OR REPLACE PROCEDURE UpdateTerminal(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_capacity NUMBER,
+    p_status VARCHAR2,
+    p_floor_count NUMBER,
+    p_services VARCHAR2,
+    p_opening_hours VARCHAR2,
+    p_plot VARCHAR2
+)
+AS
+BEGIN
+    UPDATE TERMINAL SET 
+        NAME = p_name,
+        CAPACITY = p_capacity,
+        STATUS = p_status,
+        FLOOR_COUNT = p_floor_count,
+        SERVICES = p_services,
+        OPENING_HOURS = p_opening_hours,
+        PLOT = p_plot
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a terminal
+CREATE OR REPLACE PROCEDURE DeleteTerminal(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM TERMINAL WHERE ID = p_id;
+END;
+/
+
+-- Get departures and booked passengers per terminal within a time window
+CREATE OR REPLACE PROCEDURE GetTerminalLoad(
+    p_start TIMESTAMP WITH TIME ZONE,
+    p_end TIMESTAMP WITH TIME ZONE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        TERMINAL.ID,
+        TERMINAL.NAME,
+        TERMINAL.STATUS,
+        TERMINAL.CAPACITY,
+        COUNT(DISTINCT FLIGHT.ID) AS FLIGHTS,
+        COUNT(TICKET.ID) AS PASSENGERS
+    FROM TERMINAL 
+    LEFT JOIN FLIGHT ON FLIGHT.TERMINAL = TERMINAL.ID 
+        AND FLIGHT.SCHEDULED_DEPARTURE >= SYS_EXTRACT_UTC(p_start) 
+        AND FLIGHT.SCHEDULED_DEPARTURE < SYS_EXTRACT_UTC(p_end)
+    LEFT JOIN TICKET ON TICKET.FLIGHT = FLIGHT.ID 
+        AND TICKET.STATUS NOT IN ('CANCELLED', 'DENIED_BOARDING')
+    GROUP BY TERMINAL.ID, TERMINAL.NAME, TERMINAL.STATUS, TERMINAL.CAPACITY
+    ORDER BY TERMINAL.ID;
+END;
+/
+
+/*==============================================================*/
+/* Crew Procedures                                              */
+/*==============================================================*/
+
+-- Get all crew members
+CREATE OR REPLACE PROCEDURE GetAllCrewMembers(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FIRSTNAME, LASTNAME, "ROLE", LICENSE_NUMBER, LICENSE_EXPIRY 
+    FROM CREW_MEMBER 
+    ORDER BY LASTNAME, FIRSTNAME;
+END;
+/
+
+-- Get crew member by ID
+CREATE OR REPLACE PROCEDURE GetCrewMemberByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FIRSTNAME, LASTNAME, "ROLE", LICENSE_NUMBER, LICENSE_EXPIRY 
+    FROM CREW_MEMBER 
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a crew member
+CREATE OR REPLACE PROCEDURE CreateCrewMember(
+    p_id VARCHAR2,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_role VARCHAR2,
+    p_license_number VARCHAR2,
+    p_license_expiry DATE
+)
+AS
+BEGIN
+    INSERT INTO CREW_MEMBER (ID, FIRSTNAME, LASTNAME, "ROLE", LICENSE_NUMBER, LICENSE_EXPIRY) 
+    VALUES (p_id, p_firstname, p_lastname, p_role, p_license_number, p_license_expiry);
+END;
+/
+
+-- Update a crew member
+CREATE OR REPLACE PROCEDURE UpdateCrewMember(
+    p_id VARCHAR2,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_role VARCHAR2,
+    p_license_number VARCHAR2,
+    p_license_expiry DATE
+)
+AS
+BEGIN
+    UPDATE CREW_MEMBER SET 
+        FIRSTNAME = p_firstname,
+        LASTNAME = p_lastname,
+        "ROLE" = p_role,
+        LICENSE_NUMBER = p_license_number,
+        LICENSE_EXPIRY = p_license_expiry
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a crew member that is not assigned to any flight
+CREATE OR REPLACE PROCEDURE DeleteCrewMember(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM CREW_MEMBER WHERE ID = p_id;
+END;
+/
+
+-- Get crew assigned to a flight
+CREATE OR REPLACE PROCEDURE GetFlightCrew(
+    p_flight_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FLIGHT, CREW_MEMBER, "ROLE" 
+    FROM FLIGHT_CREW 
+    WHERE FLIGHT = p_flight_id
+    ORDER BY "ROLE";
+END;
+/
+
+-- Assign a crew member to a flight
+CREATE OR REPLACE PROCEDURE CreateFlightCrew(
+    p_id VARCHAR2,
+    p_flight_id VARCHAR2,
+    p_crew_member_id VARCHAR2,
+    p_role VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO FLIGHT_CREW (ID, FLIGHT, CREW_MEMBER, "ROLE") 
+    VALUES (p_id, p_flight_id, p_crew_member_id, p_role);
+END;
+/
+
+-- Remove a crew assignment from a flight
+CREATE OR REPLACE PROCEDURE DeleteFlightCrew(
+    p_flight_id VARCHAR2,
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM FLIGHT_CREW WHERE ID = p_id AND FLIGHT = p_flight_id;
+END;
+/
+
+-- Get the flights of a crew member departing within a time window
+CREATE OR REPLACE PROCEDURE GetCrewDuties(
+    p_crew_member_id VARCHAR2,
+    p_start TIMESTAMP WITH TIME ZONE,
+    p_end TIMESTAMP WITH TIME ZONE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        FLIGHT_CREW.ID,
+        FLIGHT_CREW.CREW_MEMBER,
+        FLIGHT.ID,
+        FLIGHT_CREW."ROLE",
+        FLIGHT."FROM",
+        FLIGHT."TO",
+        FLIGHT.SCHEDULED_DEPARTURE,
+        FLIGHT.SCHEDULED_ARRIVAL
+    FROM FLIGHT_CREW 
+    JOIN FLIGHT ON FLIGHT_CREW.FLIGHT = FLIGHT.ID 
+    WHERE FLIGHT_CREW.CREW_MEMBER = p_crew_member_id 
+        AND FLIGHT.SCHEDULED_DEPARTURE >= SYS_EXTRACT_UTC(p_start) 
+        AND FLIGHT.SCHEDULED_DEPARTURE < SYS_EXTRACT_UTC(p_end)
+    ORDER BY FLIGHT.SCHEDULED_DEPARTURE;
+END;
+/
+
+/*==============================================================*/
+/* Pilot Procedures                                             */
+/*==============================================================*/
+
+-- Get all pilots
+CREATE OR REPLACE PROCEDURE GetAllPilots(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FIRSTNAME, LASTNAME, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, FLIGHT_HOURS, MEDICAL_CHECK_DATE 
+    FROM PILOT 
+    ORDER BY LASTNAME, FIRSTNAME;
+END;
+/
+
+-- Get pilot by ID
+CREATE OR REPLACE PROCEDURE GetPilotByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FIRSTNAME, LASTNAME, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, FLIGHT_HOURS, MEDICAL_CHECK_DATE 
+    FROM PILOT 
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a pilot
+CREATE OR REPLACE PROCEDURE CreatePilot(
+    p_id VARCHAR2,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_license_type VARCHAR2,
+    p_license_number VARCHAR2,
+    p_license_expiry DATE,
+    p_flight_hours NUMBER,
+    p_medical_check_date DATE
+)
+AS
+BEGIN
+    INSERT INTO PILOT (ID, FIRSTNAME, LASTNAME, LICENSE_TYPE, LICENSE_NUMBER, LICENSE_EXPIRY, FLIGHT_HOURS, MEDICAL_CHECK_DATE) 
+    VALUES (p_id, p_firstname, p_lastname, p_license_type, p_license_number, p_license_expiry, p_flight_hours, p_medical_check_date);
+END;
+/
+
+-- Update a pilot
+CREATE OR REPLACE PROCEDURE UpdatePilot(
+    p_id VARCHAR2,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_license_type VARCHAR2,
+    p_license_number VARCHAR2,
+    p_license_expiry DATE,
+    p_flight_hours NUMBER,
+    p_medical_check_date DATE
+)
+AS
+BEGIN
+    UPDATE PILOT SET 
+        FIRSTNAME = p_firstname,
+        LASTNAME = p_lastname,
+        LICENSE_TYPE = p_license_type,
+        LICENSE_NUMBER = p_license_number,
+        LICENSE_EXPIRY = p_license_expiry,
+        FLIGHT_HOURS = p_flight_hours,
+        MEDICAL_CHECK_DATE = p_medical_check_date
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a pilot
+CREATE OR REPLACE PROCEDURE DeletePilot(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM PILOT WHERE ID = p_id;
+END;
+/
+
+-- Add logged flight hours of a pilot
+CREATE OR REPLACE PROCEDURE AddPilotFlightHours(
+    p_id VARCHAR2,
+    p_hours NUMBER
+)
+AS
+BEGIN
+    UPDATE PILOT SET 
+        FLIGHT_HOURS = GREATEST(NVL(FLIGHT_HOURS, 0) + p_hours, 0)
+    WHERE ID = p_id;
+END;
+/
+
+/*==============================================================*/
+/* Fleet Procedures                                             */
+/*==============================================================*/
+
+-- Get all planes, optionally of one airline
+CREATE OR REPLACE PROCEDURE GetAllPlanes(
+    p_airline VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, MODEL, SEATS, AIRLINE, HANGAR, MANUFACTURING_YEAR, MAX_TAKEOFF_WEIGHT, FUEL_CAPACITY, STATUS 
+    FROM PLANE 
+    WHERE p_airline IS NULL OR AIRLINE = p_airline
+    ORDER BY AIRLINE, ID;
+END;
+/
+
+-- Create a plane
+CREATE OR REPLACE PROCEDURE CreatePlane(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_model VARCHAR2,
+    p_seats NUMBER,
+    p_airline VARCHAR2,
+    p_hangar VARCHAR2,
+    p_manufacturing_year NUMBER,
+    p_max_takeoff_weight NUMBER,
+    p_fuel_capacity NUMBER,
+    p_status VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO PLANE (ID, NAME, MODEL, SEATS, AIRLINE, HANGAR, MANUFACTURING_YEAR, MAX_TAKEOFF_WEIGHT, FUEL_CAPACITY, STATUS) 
+    VALUES (p_id, p_name, p_model, p_seats, p_airline, p_hangar, p_manufacturing_year, p_max_takeoff_weight, p_fuel_capacity, p_status);
+END;
+/
+
+-- Update a plane
+CREATE OR REPLACE PROCEDURE UpdatePlane(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_model VARCHAR2,
+    p_seats NUMBER,
+    p_airline VARCHAR2,
+    p_hangar VARCHAR2,
+    p_manufacturing_year NUMBER,
+    p_max_takeoff_weight NUMBER,
+    p_fuel_capacity NUMBER,
+    p_status VARCHAR2
+)
+AS
+BEGIN
+    UPDATE PLANE SET 
+        NAME = p_name,
+        MODEL = p_model,
+        SEATS = p_seats,
+        AIRLINE = p_airline,
+        HANGAR = p_hangar,
+        MANUFACTURING_YEAR = p_manufacturing_year,
+        MAX_TAKEOFF_WEIGHT = p_max_takeoff_weight,
+        FUEL_CAPACITY = p_fuel_capacity,
+        STATUS = p_status
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a plane
+CREATE OR REPLACE PROCEDURE DeletePlane(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM PLANE WHERE ID = p_id;
+END;
+/
+
+-- Count planes and seats per airline and status
+CREATE OR REPLACE PROCEDURE GetFleetOverview(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        AIRLINE.ID,
+        AIRLINE.NAME,
+        COUNT(PLANE.ID) AS PLANES,
+        COUNT(CASE WHEN PLANE.STATUS = 'ACTIVE' THEN 1 END) AS ACTIVE,
+        COUNT(CASE WHEN PLANE.STATUS = 'MAINTENANCE' THEN 1 END) AS MAINTENANCE,
+        COUNT(CASE WHEN PLANE.STATUS = 'INACTIVE' THEN 1 END) AS INACTIVE,
+        NVL(SUM(CASE WHEN PLANE.STATUS = 'ACTIVE' THEN PLANE.SEATS END), 0) AS ACTIVE_SEATS
+    FROM AIRLINE 
+    LEFT JOIN PLANE ON PLANE.AIRLINE = AIRLINE.ID 
+    GROUP BY AIRLINE.ID, AIRLINE.NAME
+    ORDER BY AIRLINE.ID;
+END;
+/
+
+-- Get the flights of a plane departing within a time window
+CREATE OR REPLACE PROCEDURE GetFlightsByPlane(
+    p_plane_id VARCHAR2,
+    p_start TIMESTAMP WITH TIME ZONE,
+    p_end TIMESTAMP WITH TIME ZONE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM, FLIGHT_NUMBER, TEMPLATE, TEMPLATE_REVISION 
+    FROM FLIGHT 
+    WHERE PLANE = p_plane_id 
+        AND SCHEDULED_DEPARTURE >= SYS_EXTRACT_UTC(p_start) 
+        AND SCHEDULED_DEPARTURE < SYS_EXTRACT_UTC(p_end)
+    ORDER BY SCHEDULED_DEPARTURE;
+END;
+/
+
+/*==============================================================*/
+/* Maintenance Procedures                                       */
+/*==============================================================*/
+
+-- Get the maintenance history of a plane
+CREATE OR REPLACE PROCEDURE GetMaintenanceLogsByPlane(
+    p_plane_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, PLANE, MAINTENANCE_DATE, DESCRIPTION, TECHNICIAN, NEXT_MAINTENANCE 
+    FROM MAINTENANCE_LOG 
+    WHERE PLANE = p_plane_id
+    ORDER BY MAINTENANCE_DATE DESC;
+END;
+/
+
+-- Record a maintenance activity
+CREATE OR REPLACE PROCEDURE CreateMaintenanceLog(
+    p_id VARCHAR2,
+    p_plane_id VARCHAR2,
+    p_maintenance_date DATE,
+    p_description VARCHAR2,
+    p_technician VARCHAR2,
+    p_next_maintenance DATE
+)
+AS
+BEGIN
+    INSERT INTO MAINTENANCE_LOG (ID, PLANE, MAINTENANCE_DATE, DESCRIPTION, TECHNICIAN, NEXT_MAINTENANCE) 
+    VALUES (p_id, p_plane_id, p_maintenance_date, p_description, p_technician, p_next_maintenance);
+END;
+/
+
+-- Get the next maintenance deadline of every plane from its latest maintenance record
+CREATE OR REPLACE PROCEDURE GetMaintenanceSchedule(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        PLANE.ID,
+        PLANE.NAME,
+        PLANE.MODEL,
+        PLANE.STATUS,
+        MAX(MAINTENANCE_LOG.NEXT_MAINTENANCE) KEEP (DENSE_RANK LAST ORDER BY MAINTENANCE_LOG.MAINTENANCE_DATE) AS NEXT_MAINTENANCE
+    FROM PLANE 
+    JOIN MAINTENANCE_LOG ON MAINTENANCE_LOG.PLANE = PLANE.ID 
+    GROUP BY PLANE.ID, PLANE.NAME, PLANE.MODEL, PLANE.STATUS
+    ORDER BY NEXT_MAINTENANCE;
+END;
+/
+
+/*==============================================================*/
+/* Hangar Procedures                                            */
+/*==============================================================*/
+
+-- Get all hangars
+CREATE OR REPLACE PROCEDURE GetAllHangars(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, PLOT, CAPACITY, SIZE_SQFT, STATUS, LAST_INSPECTION, NEXT_INSPECTION 
+    FROM HANGAR 
+    ORDER BY ID;
+END;
+/
+
+-- Get hangar by ID
+CREATE OR REPLACE PROCEDURE GetHangarByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, PLOT, CAPACITY, SIZE_SQFT, STATUS, LAST_INSPECTION, NEXT_INSPECTION 
+    FROM HANGAR 
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a hangar
+CREATE OR REPLACE PROCEDURE CreateHangar(
+    p_id VARCHAR2,
+    p_plot VARCHAR2,
+    p_capacity NUMBER,
+    p_size_sqft NUMBER,
+    p_status VARCHAR2,
+    p_last_inspection DATE,
+    p_next_inspection DATE
+)
+AS
+BEGIN
+    INSERT INTO HANGAR (ID, PLOT, CAPACITY, SIZE_SQFT, STATUS, LAST_INSPECTION, NEXT_INSPECTION) 
+    VALUES (p_id, p_plot, p_capacity, p_size_sqft, p_status, p_last_inspection, p_next_inspection);
+END;
+/
+
+-- Update a hangar
+CREATE OR REPLACE PROCEDURE UpdateHangar(
+    p_id VARCHAR2,
+    p_plot VARCHAR2,
+    p_capacity NUMBER,
+    p_size_sqft NUMBER,
+    p_status VARCHAR2,
+    p_last_inspection DATE,
+    p_next_inspection DATE
+)
+AS
+BEGIN
+    UPDATE HANGAR SET 
+        PLOT = p_plot,
+        CAPACITY = p_capacity,
+        SIZE_SQFT = p_size_sqft,
+        STATUS = p_status,
+        LAST_INSPECTION = p_last_inspection,
+        NEXT_INSPECTION = p_next_inspection
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a hangar
+CREATE OR REPLACE PROCEDURE DeleteHangar(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM HANGAR WHERE ID = p_id;
+END;
+/
+
+-- Get the planes parked in a hangar
+CREATE OR REPLACE PROCEDURE GetPlanesByHangar(
+    p_hangar_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, MODEL, SEATS, AIRLINE, HANGAR, MANUFACTURING_YEAR, MAX_TAKEOFF_WEIGHT, FUEL_CAPACITY, STATUS 
+    FROM PLANE 
+    WHERE HANGAR = p_hangar_id
+    ORDER BY ID;
+END;
+/
+
+/*==============================================================*/
+/* Plot Procedures                                              */
+/*==============================================================*/
+
+-- Get all plots with their type and the weight of the aircraft parked on them,
+-- optionally filtered by plot type (ID or name) and status
+CREATE OR REPLACE PROCEDURE GetAllPlots(
+    p_type VARCHAR2,
+    p_status VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        PLOT.ID, PLOT.POSITION, PLOT.TYPE, PLOT.AREA_SQFT, PLOT.STATUS, PLOT.LAST_MAINTENANCE, PLOT.MAX_WEIGHT_CAPACITY, PLOT.UTILITIES_AVAILABLE, PLOT.TERMINAL, 
+        PLOTTYPE.NAME, PLOTTYPE.LABEL,
+        (SELECT NVL(SUM(PLANE.MAX_TAKEOFF_WEIGHT), 0) FROM HANGAR JOIN PLANE ON PLANE.HANGAR = HANGAR.ID WHERE HANGAR.PLOT = PLOT.ID) AS CARRIED_LOAD
+    FROM PLOT 
+    JOIN PLOTTYPE ON PLOTTYPE.ID = PLOT.TYPE 
+    WHERE (p_type IS NULL OR TO_CHAR(PLOTTYPE.ID) = p_type OR PLOTTYPE.NAME = p_type)
+        AND (p_status IS NULL OR PLOT.STATUS = p_status)
+    ORDER BY PLOT.POSITION;
+END;
+/
+
+-- Get plot by ID
+CREATE OR REPLACE PROCEDURE GetPlotByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        PLOT.ID, PLOT.POSITION, PLOT.TYPE, PLOT.AREA_SQFT, PLOT.STATUS, PLOT.LAST_MAINTENANCE, PLOT.MAX_WEIGHT_CAPACITY, PLOT.UTILITIES_AVAILABLE, PLOT.TERMINAL, 
+        PLOTTYPE.NAME, PLOTTYPE.LABEL,
+        (SELECT NVL(SUM(PLANE.MAX_TAKEOFF_WEIGHT), 0) FROM HANGAR JOIN PLANE ON PLANE.HANGAR = HANGAR.ID WHERE HANGAR.PLOT = PLOT.ID) AS CARRIED_LOAD
+    FROM PLOT 
+    JOIN PLOTTYPE ON PLOTTYPE.ID = PLOT.TYPE 
+    WHERE PLOT.ID = p_id;
+END;
+/
+
+-- Get all plot types
+CREATE OR REPLACE PROCEDURE GetAllPlotTypes(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, LABEL 
+    FROM PLOTTYPE 
+    ORDER BY ID;
+END;
+/
+
+-- Create a plot
+CREATE OR REPLACE PROCEDURE CreatePlot(
+    p_id VARCHAR2,
+    p_position NUMBER,
+    p_type NUMBER,
+    p_area_sqft NUMBER,
+    p_status VARCHAR2,
+    p_last_maintenance DATE,
+    p_max_weight_capacity NUMBER,
+    p_utilities_available VARCHAR2,
+    p_terminal VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO PLOT (ID, POSITION, TYPE, AREA_SQFT, STATUS, LAST_MAINTENANCE, MAX_WEIGHT_CAPACITY, UTILITIES_AVAILABLE, TERMINAL) 
+    VALUES (p_id, p_position, p_type, p_area_sqft, p_status, p_last_maintenance, p_max_weight_capacity, p_utilities_available, p_terminal);
+END;
+/
+
+-- Update a plot
+CREATE OR REPLACE PROCEDURE UpdatePlot(
+    p_id VARCHAR2,
+    p_position NUMBER,
+    p_type NUMBER,
+    p_area_sqft NUMBER,
+    p_status VARCHAR2,
+    p_last_maintenance DATE,
+    p_max_weight_capacity NUMBER,
+    p_utilities_available VARCHAR2,
+    p_terminal VARCHAR2
+)
+AS
+BEGIN
+    UPDATE PLOT SET 
+        POSITION = p_position,
+        TYPE = p_type,
+        AREA_SQFT = p_area_sqft,
+        STATUS = p_status,
+        LAST_MAINTENANCE = p_last_maintenance,
+        MAX_WEIGHT_CAPACITY = p_max_weight_capacity,
+        UTILITIES_AVAILABLE = p_utilities_available,
+        TERMINAL = p_terminal
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a plot
+CREATE OR REPLACE PROCEDURE DeletePlot(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM PLOT WHERE ID = p_id;
+END;
+/
+
+-- Get the facilities built on a plot, or on all plots if no plot is given
+CREATE OR REPLACE PROCEDURE GetPlotOccupants(
+    p_plot VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT PLOT, KIND, ID, NAME FROM (
+        SELECT PLOT, 'hangar' AS KIND, ID, ID AS NAME FROM HANGAR
+        UNION ALL
+        SELECT PLOT, 'shop', ID, NAME FROM SHOP
+        UNION ALL
+        SELECT PLOT, 'terminal', ID, NAME FROM TERMINAL WHERE PLOT IS NOT NULL
+        UNION ALL
+        SELECT PLOT, 'parking', ID, NAME FROM PARKING
+    )
+    WHERE p_plot IS NULL OR PLOT = p_plot
+    ORDER BY PLOT, KIND, ID;
+END;
+/
+
+-- Get the weight of the aircraft parked in the hangars on a plot,
+-- leaving out one plane (e.g. the plane about to be moved)
+CREATE OR REPLACE PROCEDURE GetPlotLoad(
+    p_plot VARCHAR2,
+    p_exclude_plane VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT NVL(SUM(PLANE.MAX_TAKEOFF_WEIGHT), 0) 
+    FROM HANGAR 
+    JOIN PLANE ON PLANE.HANGAR = HANGAR.ID 
+    WHERE HANGAR.PLOT = p_plot
+        AND (p_exclude_plane IS NULL OR PLANE.ID <> p_exclude_plane);
+END;
+/
+
+-- Set a plot to OCCUPIED or AVAILABLE depending on whether facilities are
+-- built on it. Plots under maintenance keep their status.
+CREATE OR REPLACE PROCEDURE RefreshPlotStatus(
+    p_id VARCHAR2
+)
+AS
+    v_occupants NUMBER;
+BEGIN
+    SELECT 
+        (SELECT COUNT(*) FROM HANGAR WHERE PLOT = p_id) +
+        (SELECT COUNT(*) FROM SHOP WHERE PLOT = p_id) +
+        (SELECT COUNT(*) FROM TERMINAL WHERE PLOT = p_id) +
+        (SELECT COUNT(*) FROM PARKING WHERE PLOT = p_id)
+    INTO v_occupants
+    FROM DUAL;
+
+    UPDATE PLOT SET 
+        STATUS = CASE WHEN v_occupants > 0 THEN 'OCCUPIED' ELSE 'AVAILABLE' END
+    WHERE ID = p_id AND STATUS <> 'MAINTENANCE';
+END;
+/
+
+-- Get all car parks
+CREATE OR REPLACE PROCEDURE GetAllParkings(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, PLOT, SPACES, STATUS 
+    FROM PARKING 
+    ORDER BY NAME;
+END;
+/
+
+-- Get car park by ID
+CREATE OR REPLACE PROCEDURE GetParkingByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, PLOT, SPACES, STATUS 
+    FROM PARKING 
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a car park
+CREATE OR REPLACE PROCEDURE CreateParking(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_plot VARCHAR2,
+    p_spaces NUMBER,
+    p_status VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO PARKING (ID, NAME, PLOT, SPACES, STATUS) 
+    VALUES (p_id, p_name, p_plot, p_spaces, p_status);
+END;
+/
+
+-- Update a car park
+CREATE OR REPLACE PROCEDURE UpdateParking(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_plot VARCHAR2,
+    p_spaces NUMBER,
+    p_status VARCHAR2
+)
+AS
+BEGIN
+    UPDATE PARKING SET 
+        NAME = p_name,
+        PLOT = p_plot,
+        SPACES = p_spaces,
+        STATUS = p_status
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a car park
+CREATE OR REPLACE PROCEDURE DeleteParking(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM PARKING WHERE ID = p_id;
+END;
+/
+
+/*==============================================================*/
+/* Shop Procedures                                              */
+/*==============================================================*/
+
+-- Get all shops with their type and terminal, optionally filtered by
+-- category, terminal and duty-free flag
+CREATE OR REPLACE PROCEDURE GetAllShops(
+    p_category VARCHAR2,
+    p_terminal VARCHAR2,
+    p_duty_free NUMBER,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        SHOP.ID, SHOP.NAME, SHOP.TYPE, SHOP.PLOT, SHOP.OPENING_TIME, SHOP.CLOSING_TIME, SHOP.DESCRIPTION, SHOP.IS_DUTY_FREE, 
+        SHOPTYPE.NAME, TRIM(SHOPTYPE.CATEGORY), SHOPTYPE.SECURITY_LEVEL, SHOPTYPE.TYPICAL_HOURS, PLOT.TERMINAL
+    FROM SHOP 
+    JOIN SHOPTYPE ON SHOPTYPE.ID = SHOP.TYPE 
+    JOIN PLOT ON PLOT.ID = SHOP.PLOT 
+    WHERE (p_category IS NULL OR UPPER(TRIM(SHOPTYPE.CATEGORY)) = UPPER(p_category))
+        AND (p_terminal IS NULL OR PLOT.TERMINAL = p_terminal)
+        AND (p_duty_free IS NULL OR SHOP.IS_DUTY_FREE = p_duty_free)
+    ORDER BY SHOP.NAME;
+END;
+/
+
+-- Get shop by ID
+CREATE OR REPLACE PROCEDURE GetShopByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        SHOP.ID, SHOP.NAME, SHOP.TYPE, SHOP.PLOT, SHOP.OPENING_TIME, SHOP.CLOSING_TIME, SHOP.DESCRIPTION, SHOP.IS_DUTY_FREE, 
+        SHOPTYPE.NAME, TRIM(SHOPTYPE.CATEGORY), SHOPTYPE.SECURITY_LEVEL, SHOPTYPE.TYPICAL_HOURS, PLOT.TERMINAL
+    FROM SHOP 
+    JOIN SHOPTYPE ON SHOPTYPE.ID = SHOP.TYPE 
+    JOIN PLOT ON PLOT.ID = SHOP.PLOT 
+    WHERE SHOP.ID = p_id;
+END;
+/
+
+-- Get all shop types
+CREATE OR REPLACE PROCEDURE GetAllShopTypes(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, LABEL, TRIM(CATEGORY), SECURITY_LEVEL, DESCRIPTION, TYPICAL_HOURS 
+    FROM SHOPTYPE 
+    ORDER BY NAME;
+END;
+/
+
+-- Create a shop
+CREATE OR REPLACE PROCEDURE CreateShop(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_type VARCHAR2,
+    p_plot VARCHAR2,
+    p_opening_time VARCHAR2,
+    p_closing_time VARCHAR2,
+    p_description VARCHAR2,
+    p_is_duty_free NUMBER
+)
+AS
+BEGIN
+    INSERT INTO SHOP (ID, NAME, TYPE, PLOT, OPENING_TIME, CLOSING_TIME, DESCRIPTION, IS_DUTY_FREE) 
+    VALUES (p_id, p_name, p_type, p_plot, p_opening_time, p_closing_time, p_description, p_is_duty_free);
+END;
+/
+
+-- Update a shop
+CREATE OR REPLACE PROCEDURE UpdateShop(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_type VARCHAR2,
+    p_plot VARCHAR2,
+    p_opening_time VARCHAR2,
+    p_closing_time VARCHAR2,
+    p_description VARCHAR2,
+    p_is_duty_free NUMBER
+)
+AS
+BEGIN
+    UPDATE SHOP SET 
+        NAME = p_name,
+        TYPE = p_type,
+        PLOT = p_plot,
+        OPENING_TIME = p_opening_time,
+        CLOSING_TIME = p_closing_time,
+        DESCRIPTION = p_description,
+        IS_DUTY_FREE = p_is_duty_free
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a shop
+CREATE OR REPLACE PROCEDURE DeleteShop(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM SHOP WHERE ID = p_id;
+END;
+/
+
+/*==============================================================*/
+/* Lease Procedures                                             */
+/*==============================================================*/
+
+-- Get all lease contracts with shop name and plot area, optionally for one shop
+CREATE OR REPLACE PROCEDURE GetAllLeases(
+    p_shop VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        LEASE.ID, LEASE.SHOP, LEASE.PLOT, LEASE.START_DATE, LEASE.END_DATE, LEASE.RENT_PER_SQFT, LEASE.TURNOVER_PERCENT, 
+        SHOP.NAME, PLOT.AREA_SQFT
+    FROM LEASE 
+    JOIN SHOP ON LEASE.SHOP = SHOP.ID 
+    JOIN PLOT ON LEASE.PLOT = PLOT.ID 
+    WHERE p_shop IS NULL OR LEASE.SHOP = p_shop
+    ORDER BY SHOP.NAME, LEASE.START_DATE;
+END;
+/
+
+-- Get lease contract by ID
+CREATE OR REPLACE PROCEDURE GetLeaseByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        LEASE.ID, LEASE.SHOP, LEASE.PLOT, LEASE.START_DATE, LEASE.END_DATE, LEASE.RENT_PER_SQFT, LEASE.TURNOVER_PERCENT, 
+        SHOP.NAME, PLOT.AREA_SQFT
+    FROM LEASE 
+    JOIN SHOP ON LEASE.SHOP = SHOP.ID 
+    JOIN PLOT ON LEASE.PLOT = PLOT.ID 
+    WHERE LEASE.ID = p_id;
+END;
+/
+
+-- Get the lease contracts running at some time in [p_from, p_to)
+CREATE OR REPLACE PROCEDURE GetLeasesInPeriod(
+    p_from DATE,
+    p_to DATE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        LEASE.ID, LEASE.SHOP, LEASE.PLOT, LEASE.START_DATE, LEASE.END_DATE, LEASE.RENT_PER_SQFT, LEASE.TURNOVER_PERCENT, 
+        SHOP.NAME, PLOT.AREA_SQFT
+    FROM LEASE 
+    JOIN SHOP ON LEASE.SHOP = SHOP.ID 
+    JOIN PLOT ON LEASE.PLOT = PLOT.ID 
+    WHERE LEASE.START_DATE < p_to 
+      AND (LEASE.END_DATE IS NULL OR LEASE.END_DATE >= p_from)
+    ORDER BY SHOP.NAME;
+END;
+/
+
+-- Create a lease contract
+CREATE OR REPLACE PROCEDURE CreateLease(
+    p_id VARCHAR2,
+    p_shop VARCHAR2,
+    p_plot VARCHAR2,
+    p_start_date DATE,
+    p_end_date DATE,
+    p_rent_per_sqft NUMBER,
+    p_turnover_percent NUMBER
+)
+AS
+BEGIN
+    INSERT INTO LEASE (ID, SHOP, PLOT, START_DATE, END_DATE, RENT_PER_SQFT, TURNOVER_PERCENT) 
+    VALUES (p_id, p_shop, p_plot, p_start_date, p_end_date, p_rent_per_sqft, p_turnover_percent);
+END;
+/
+
+-- Update a lease contract
+CREATE OR REPLACE PROCEDURE UpdateLease(
+    p_id VARCHAR2,
+    p_shop VARCHAR2,
+    p_plot VARCHAR2,
+    p_start_date DATE,
+    p_end_date DATE,
+    p_rent_per_sqft NUMBER,
+    p_turnover_percent NUMBER
+)
+AS
+BEGIN
+    UPDATE LEASE SET 
+        SHOP = p_shop,
+        PLOT = p_plot,
+        START_DATE = p_start_date,
+        END_DATE = p_end_date,
+        RENT_PER_SQFT = p_rent_per_sqft,
+        TURNOVER_PERCENT = p_turnover_percent
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a lease contract; fails once invoices were issued for it
+CREATE OR REPLACE PROCEDURE DeleteLease(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM LEASE WHERE ID = p_id;
+END;
+/
+
+-- Record the turnover a shop reported for a month (replaces an earlier report)
+CREATE OR REPLACE PROCEDURE SetLeaseTurnover(
+    p_lease VARCHAR2,
+    p_period DATE,
+    p_amount NUMBER
+)
+AS
+BEGIN
+    MERGE INTO LEASE_TURNOVER t
+    USING (SELECT p_lease AS LEASE, p_period AS PERIOD FROM DUAL) s
+    ON (t.LEASE = s.LEASE AND t.PERIOD = s.PERIOD)
+    WHEN MATCHED THEN UPDATE SET t.AMOUNT = p_amount
+    WHEN NOT MATCHED THEN INSERT (LEASE, PERIOD, AMOUNT) VALUES (p_lease, p_period, p_amount);
+END;
+/
+
+-- Get the turnover reported by all shops for a month
+CREATE OR REPLACE PROCEDURE GetLeaseTurnovers(
+    p_period DATE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT LEASE, AMOUNT 
+    FROM LEASE_TURNOVER 
+    WHERE PERIOD = p_period;
+END;
+/
+
+-- Store the invoice of a lease for a month; an existing invoice for the
+-- same month is replaced and keeps its ID
+CREATE OR REPLACE PROCEDURE SaveLeaseInvoice(
+    p_id VARCHAR2,
+    p_lease VARCHAR2,
+    p_period DATE,
+    p_area_sqft NUMBER,
+    p_fixed_rent NUMBER,
+    p_turnover NUMBER,
+    p_turnover_rent NUMBER,
+    p_total NUMBER
+)
+AS
+BEGIN
+    MERGE INTO LEASE_INVOICE i
+    USING (SELECT p_lease AS LEASE, p_period AS PERIOD FROM DUAL) s
+    ON (i.LEASE = s.LEASE AND i.PERIOD = s.PERIOD)
+    WHEN MATCHED THEN UPDATE SET 
+        i.AREA_SQFT = p_area_sqft,
+        i.FIXED_RENT = p_fixed_rent,
+        i.TURNOVER = p_turnover,
+        i.TURNOVER_RENT = p_turnover_rent,
+        i.TOTAL = p_total,
+        i.ISSUED_AT = SYSDATE
+    WHEN NOT MATCHED THEN INSERT (ID, LEASE, PERIOD, AREA_SQFT, FIXED_RENT, TURNOVER, TURNOVER_RENT, TOTAL, ISSUED_AT) 
+        VALUES (p_id, p_lease, p_period, p_area_sqft, p_fixed_rent, p_turnover, p_turnover_rent, p_total, SYSDATE);
+END;
+/
+
+-- Get the lease invoices for the months in [p_from, p_to)
+CREATE OR REPLACE PROCEDURE GetLeaseInvoices(
+    p_from DATE,
+    p_to DATE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        LEASE_INVOICE.ID, LEASE_INVOICE.LEASE, LEASE.SHOP, SHOP.NAME, LEASE_INVOICE.PERIOD, LEASE_INVOICE.AREA_SQFT, 
+        LEASE_INVOICE.FIXED_RENT, LEASE_INVOICE.TURNOVER, LEASE_INVOICE.TURNOVER_RENT, LEASE_INVOICE.TOTAL, LEASE_INVOICE.ISSUED_AT
+    FROM LEASE_INVOICE 
+    JOIN LEASE ON LEASE_INVOICE.LEASE = LEASE.ID 
+    JOIN SHOP ON LEASE.SHOP = SHOP.ID 
+    WHERE LEASE_INVOICE.PERIOD >= p_from AND LEASE_INVOICE.PERIOD < p_to
+    ORDER BY LEASE_INVOICE.PERIOD, SHOP.NAME;
+END;
+/
+
+-- Calculate the total concession revenue invoiced to shops
+CREATE OR REPLACE PROCEDURE CalculateConcessionRevenue(
+    total_revenue OUT NUMBER
+)
+AS
+BEGIN
+    SELECT COALESCE(SUM(TOTAL), 0) INTO total_revenue FROM LEASE_INVOICE;
+END;
+/
+
+/*==============================================================*/
+/* Airport and Airline Management Procedures                    */
+/*==============================================================*/
+
+-- Create a new airport
+CREATE OR REPLACE PROCEDURE CreateAirport(
+    p_id VARCHAR2,
+    p_icao VARCHAR2,
+    p_name VARCHAR2,
+    p_country VARCHAR2,
+    p_city VARCHAR2,
+    p_timezone VARCHAR2,
+    p_elevation NUMBER,
+    p_number_of_terminals NUMBER,
+    p_latitude NUMBER,
+    p_longitude NUMBER
+)
+AS
+BEGIN
+    INSERT INTO AIRPORT (ID, ICAO, NAME, COUNTRY, CITY, TIMEZONE, ELEVATION, NUMBER_OF_TERMINALS, LATITUDE, LONGITUDE) 
+    VALUES (p_id, p_icao, p_name, p_country, p_city, p_timezone, p_elevation, p_number_of_terminals, p_latitude, p_longitude);
+END;
+/
+
+-- Update an existing airport
+CREATE OR REPLACE PROCEDURE UpdateAirport(
+    p_id VARCHAR2,
+    p_icao VARCHAR2,
+    p_name VARCHAR2,
+    p_country VARCHAR2,
+    p_city VARCHAR2,
+    p_timezone VARCHAR2,
+    p_elevation NUMBER,
+    p_number_of_terminals NUMBER,
+    p_latitude NUMBER,
+    p_longitude NUMBER
+)
+AS
+BEGIN
+    UPDATE AIRPORT 
+    SET ICAO = p_icao,
+        NAME = p_name,
+        COUNTRY = p_country,
+        CITY = p_city,
+        TIMEZONE = p_timezone,
+        ELEVATION = p_elevation,
+        NUMBER_OF_TERMINALS = p_number_of_terminals,
+        LATITUDE = p_latitude,
+        LONGITUDE = p_longitude
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete an airport
+CREATE OR REPLACE PROCEDURE DeleteAirport(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM AIRPORT WHERE ID = p_id;
+END;
+/
+
+-- Count the flights departing from or arriving at an airport
+CREATE OR REPLACE PROCEDURE GetAirportUsage(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT COUNT(*) AS FLIGHTS
+    FROM FLIGHT 
+    WHERE "FROM" = p_id OR "TO" = p_id;
+END;
+/
+
+-- Create a new airline
+CREATE OR REPLACE PROCEDURE CreateAirline(
+    p_id VARCHAR2,
+    p_icao VARCHAR2,
+    p_name VARCHAR2,
+    p_country VARCHAR2,
+    p_logo_url VARCHAR2,
+    p_active NUMBER
+)
+AS
+BEGIN
+    INSERT INTO AIRLINE (ID, ICAO, NAME, COUNTRY, LOGO_URL, ACTIVE) 
+    VALUES (p_id, p_icao, p_name, p_country, p_logo_url, p_active);
+END;
+/
+
+-- Update an existing airline
+CREATE OR REPLACE PROCEDURE UpdateAirline(
+    p_id VARCHAR2,
+    p_icao VARCHAR2,
+    p_name VARCHAR2,
+    p_country VARCHAR2,
+    p_logo_url VARCHAR2,
+    p_active NUMBER
+)
+AS
+BEGIN
+    UPDATE AIRLINE 
+    SET ICAO = p_icao,
+        NAME = p_name,
+        COUNTRY = p_country,
+        LOGO_URL = p_logo_url,
+        ACTIVE = p_active
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete an airline
+CREATE OR REPLACE PROCEDURE DeleteAirline(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM AIRLINE WHERE ID = p_id;
+END;
+/
+
+-- Count the planes of an airline and the flights operated with them
+CREATE OR REPLACE PROCEDURE GetAirlineUsage(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        (SELECT COUNT(*) FROM PLANE WHERE AIRLINE = p_id) AS PLANES,
+        (SELECT COUNT(*) FROM FLIGHT JOIN PLANE ON FLIGHT.PLANE = PLANE.ID WHERE PLANE.AIRLINE = p_id) AS FLIGHTS
+    FROM DUAL;
+END;
+/
+
+/*==============================================================*/
+/* Schedule Template Procedures                                 */
+/*==============================================================*/
+
+-- Get all schedule templates
+CREATE OR REPLACE PROCEDURE GetScheduleTemplates(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FLIGHT_NUMBER, "FROM", "TO", DEPARTURE_TIME, ARRIVAL_TIME, ARRIVAL_DAY_OFFSET, DAYS_OF_WEEK, 
+        VALID_FROM, VALID_TO, PLANE, TERMINAL, GATE, REVISION
+    FROM SCHEDULE_TEMPLATE 
+    ORDER BY FLIGHT_NUMBER, VALID_FROM;
+END;
+/
+
+-- Get schedule template by ID
+CREATE OR REPLACE PROCEDURE GetScheduleTemplateByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FLIGHT_NUMBER, "FROM", "TO", DEPARTURE_TIME, ARRIVAL_TIME, ARRIVAL_DAY_OFFSET, DAYS_OF_WEEK, 
+        VALID_FROM, VALID_TO, PLANE, TERMINAL, GATE, REVISION
+    FROM SCHEDULE_TEMPLATE 
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a schedule template
+CREATE OR REPLACE PROCEDURE CreateScheduleTemplate(
+    p_id VARCHAR2,
+    p_flight_number VARCHAR2,
+    p_from VARCHAR2,
+    p_to VARCHAR2,
+    p_departure_time VARCHAR2,
+    p_arrival_time VARCHAR2,
+    p_arrival_day_offset NUMBER,
+    p_days_of_week VARCHAR2,
+    p_valid_from DATE,
+    p_valid_to DATE,
+    p_plane VARCHAR2,
+    p_terminal VARCHAR2,
+    p_gate VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO SCHEDULE_TEMPLATE (ID, FLIGHT_NUMBER, "FROM", "TO", DEPARTURE_TIME, ARRIVAL_TIME, ARRIVAL_DAY_OFFSET, 
+        DAYS_OF_WEEK, VALID_FROM, VALID_TO, PLANE, TERMINAL, GATE, REVISION) 
+    VALUES (p_id, p_flight_number, p_from, p_to, p_departure_time, p_arrival_time, p_arrival_day_offset, 
+        p_days_of_week, p_valid_from, p_valid_to, p_plane, p_terminal, p_gate, 1);
+END;
+/
+
+-- Update a schedule template; every update starts a new revision
+CREATE OR REPLACE PROCEDURE UpdateScheduleTemplate(
+    p_id VARCHAR2,
+    p_flight_number VARCHAR2,
+    p_from VARCHAR2,
+    p_to VARCHAR2,
+    p_departure_time VARCHAR2,
+    p_arrival_time VARCHAR2,
+    p_arrival_day_offset NUMBER,
+    p_days_of_week VARCHAR2,
+    p_valid_from DATE,
+    p_valid_to DATE,
+    p_plane VARCHAR2,
+    p_terminal VARCHAR2,
+    p_gate VARCHAR2,
+    p_revision OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE SCHEDULE_TEMPLATE SET 
+        FLIGHT_NUMBER = p_flight_number,
+        "FROM" = p_from,
+        "TO" = p_to,
+        DEPARTURE_TIME = p_departure_time,
+        ARRIVAL_TIME = p_arrival_time,
+        ARRIVAL_DAY_OFFSET = p_arrival_day_offset,
+        DAYS_OF_WEEK = p_days_of_week,
+        VALID_FROM = p_valid_from,
+        VALID_TO = p_valid_to,
+        PLANE = p_plane,
+        TERMINAL = p_terminal,
+        GATE = p_gate,
+        REVISION = REVISION + 1
+    WHERE ID = p_id
+    RETURNING REVISION INTO p_revision;
+END;
+/
+
+-- Delete a schedule template; its flights are kept without template
+CREATE OR REPLACE PROCEDURE DeleteScheduleTemplate(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM SCHEDULE_TEMPLATE WHERE ID = p_id;
+END;
+/
+
+-- Get the flights of a template departing at or after p_from with their number of valid tickets
+CREATE OR REPLACE PROCEDURE GetTemplateFlights(
+    p_template VARCHAR2,
+    p_from TIMESTAMP WITH TIME ZONE,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, "FROM", "TO", PILOT, PLANE, TERMINAL, STATUS, SCHEDULED_DEPARTURE, ACTUAL_DEPARTURE, SCHEDULED_ARRIVAL, ACTUAL_ARRIVAL, GATE, BAGGAGE_CLAIM, FLIGHT_NUMBER, TEMPLATE, TEMPLATE_REVISION, 
+        (SELECT COUNT(*) FROM TICKET WHERE TICKET.FLIGHT = FLIGHT.ID AND TICKET.STATUS <> 'CANCELLED')
+    FROM FLIGHT 
+    WHERE TEMPLATE = p_template 
+      AND SCHEDULED_DEPARTURE >= SYS_EXTRACT_UTC(p_from)
+    ORDER BY SCHEDULED_DEPARTURE;
+END;
+/
+
+-- Record the template revision a flight follows
+CREATE OR REPLACE PROCEDURE SetFlightTemplateRevision(
+    p_id VARCHAR2,
+    p_revision NUMBER
+)
+AS
+BEGIN
+    UPDATE FLIGHT SET TEMPLATE_REVISION = p_revision WHERE ID = p_id;
+END;
+/
+
+-- Delete a flight together with its crew assignments and passenger lists unless tickets or baggage refer to it
+CREATE OR REPLACE PROCEDURE DeleteUnbookedFlight(
+    p_id VARCHAR2,
+    p_deleted OUT NUMBER
+)
+AS
+    v_refs NUMBER;
+BEGIN
+    SELECT (SELECT COUNT(*) FROM TICKET WHERE FLIGHT = p_id) + (SELECT COUNT(*) FROM BAGGAGE WHERE FLIGHT = p_id)
+    INTO v_refs FROM DUAL;
+
+    IF v_refs > 0 THEN
+        p_deleted := 0;
+        RETURN;
+    END IF;
+
+    DELETE FROM FLIGHT_CREW WHERE FLIGHT = p_id;
+    DELETE FROM PASSENGER_LIST_MESSAGE WHERE FLIGHT = p_id;
+    DELETE FROM FLIGHT WHERE ID = p_id;
+    p_deleted := 1;
+END;
+/
+
+/*==============================================================*/
+/* Calendar Subscription Procedures                             */
+/*==============================================================*/
+
+-- Get the calendar subscription of a user
+CREATE OR REPLACE PROCEDURE GetCalendarSubscription(
+    p_user_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT AIRPORTUSER, CREATED_AT
+    FROM CALENDAR_SUBSCRIPTION
+    WHERE AIRPORTUSER = p_user_id;
+END;
+/
+
+-- Get the user a calendar subscription token belongs to
+CREATE OR REPLACE PROCEDURE GetCalendarSubscriber(
+    p_token_hash VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT AIRPORTUSER
+    FROM CALENDAR_SUBSCRIPTION
+    WHERE TOKEN_HASH = p_token_hash;
+END;
+/
+
+-- Create or replace the calendar subscription token of a user
+CREATE OR REPLACE PROCEDURE SaveCalendarSubscription(
+    p_user_id VARCHAR2,
+    p_token_hash VARCHAR2,
+    p_created_at TIMESTAMP
+)
+AS
+BEGIN
+    MERGE INTO CALENDAR_SUBSCRIPTION cs
+    USING (SELECT p_user_id AS AIRPORTUSER FROM DUAL) src
+    ON (cs.AIRPORTUSER = src.AIRPORTUSER)
+    WHEN MATCHED THEN UPDATE SET
+        TOKEN_HASH = p_token_hash,
+        CREATED_AT = p_created_at
+    WHEN NOT MATCHED THEN INSERT (AIRPORTUSER, TOKEN_HASH, CREATED_AT)
+        VALUES (p_user_id, p_token_hash, p_created_at);
+END;
+/
+
+-- Revoke the calendar subscription of a user
+CREATE OR REPLACE PROCEDURE DeleteCalendarSubscription(
+    p_user_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM CALENDAR_SUBSCRIPTION WHERE AIRPORTUSER = p_user_id;
+END;
+/
+
+/*==============================================================*/
+/* Passenger Manifest Procedures                                */
+/*==============================================================*/
+
+-- Get the passengers of a flight with their baggage, excluding cancelled tickets
+CREATE OR REPLACE PROCEDURE GetFlightManifest(
+    p_flight VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        T.ID,
+        T.TRAVELLER,
+        T.FIRSTNAME,
+        T.LASTNAME,
+        T.BIRTHDATE,
+        T.SEAT_NUMBER,
+        T.TRAVEL_CLASS,
+        T.TRAVEL_CLASS_NAME,
+        T.STATUS,
+        (SELECT COUNT(*) FROM BAGGAGE
+         WHERE BAGGAGE.FLIGHT = p_flight AND BAGGAGE.AIRPORTUSER = T.TRAVELLER
+           AND NVL(BAGGAGE.COMPANION, '-') = NVL(T.COMPANION, '-')),
+        (SELECT NVL(SUM(BAGGAGE.WEIGHT), 0) FROM BAGGAGE
+         WHERE BAGGAGE.FLIGHT = p_flight AND BAGGAGE.AIRPORTUSER = T.TRAVELLER
+           AND NVL(BAGGAGE.COMPANION, '-') = NVL(T.COMPANION, '-')),
+        T.TRAVEL_DOCUMENT,
+        T.LOCATOR,
+        T.COMPANION
+    FROM (
+        -- The traveller is the booking passenger, or the ticket holder for
+        -- tickets without one. Companions are represented by the account
+        -- managing them, guest passengers have no account.
+        SELECT
+            TICKET.ID,
+            CASE
+                WHEN TICKET.PASSENGER IS NULL THEN AIRPORTUSER.ID
+                WHEN BOOKING_PASSENGER.COMPANION IS NOT NULL THEN TICKET.AIRPORTUSER
+                ELSE BOOKING_PASSENGER.AIRPORTUSER
+            END AS TRAVELLER,
+            BOOKING_PASSENGER.COMPANION,
+            NVL2(TICKET.PASSENGER, BOOKING_PASSENGER.FIRSTNAME, AIRPORTUSER.FIRSTNAME) AS FIRSTNAME,
+            NVL2(TICKET.PASSENGER, BOOKING_PASSENGER.LASTNAME, AIRPORTUSER.LASTNAME) AS LASTNAME,
+            NVL2(TICKET.PASSENGER, BOOKING_PASSENGER.BIRTHDATE, AIRPORTUSER.BIRTHDATE) AS BIRTHDATE,
+            TICKET.SEAT_NUMBER,
+            TICKET.TRAVEL_CLASS,
+            TRAVEL_CLASS.NAME AS TRAVEL_CLASS_NAME,
+            TICKET.STATUS,
+            TICKET.TRAVEL_DOCUMENT,
+            BOOKING.LOCATOR
+        FROM TICKET
+        JOIN AIRPORTUSER ON TICKET.AIRPORTUSER = AIRPORTUSER.ID
+        LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
+        LEFT JOIN BOOKING ON TICKET.BOOKING = BOOKING.ID
+        LEFT JOIN TRAVEL_CLASS ON TICKET.TRAVEL_CLASS = TRAVEL_CLASS.ID
+        WHERE TICKET.FLIGHT = p_flight
+          AND TICKET.STATUS NOT IN ('CANCELLED', 'DENIED_BOARDING')
+    ) T
+    ORDER BY T.LASTNAME, T.FIRSTNAME, T.ID;
+END;
+/
+
+-- Get the PNL and ADL messages sent for a flight, oldest first
+CREATE OR REPLACE PROCEDURE GetPassengerListMessages(
+    p_flight VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FLIGHT, TYPE, SEQUENCE, SENT_AT, PARTS, PASSENGERS
+    FROM PASSENGER_LIST_MESSAGE
+    WHERE FLIGHT = p_flight
+    ORDER BY SENT_AT, SEQUENCE;
+END;
+/
+
+-- Get the passenger list as it was known after a message
+CREATE OR REPLACE PROCEDURE GetPassengerListEntries(
+    p_message VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT TICKET, SURNAME, GIVEN_NAME, BOOKING_CLASS, SEAT, LOCATOR
+    FROM PASSENGER_LIST_ENTRY
+    WHERE MESSAGE = p_message
+    ORDER BY SURNAME, GIVEN_NAME, TICKET;
+END;
+/
+
+-- Record a sent PNL or ADL message
+CREATE OR REPLACE PROCEDURE CreatePassengerListMessage(
+    p_id VARCHAR2,
+    p_flight VARCHAR2,
+    p_type VARCHAR2,
+    p_sequence NUMBER,
+    p_sent_at TIMESTAMP,
+    p_parts NUMBER,
+    p_passengers NUMBER
+)
+AS
+BEGIN
+    INSERT INTO PASSENGER_LIST_MESSAGE (ID, FLIGHT, TYPE, SEQUENCE, SENT_AT, PARTS, PASSENGERS)
+    VALUES (p_id, p_flight, p_type, p_sequence, p_sent_at, p_parts, p_passengers);
+END;
+/
+
+-- Record a passenger of the list sent with a message
+CREATE OR REPLACE PROCEDURE AddPassengerListEntry(
+    p_message VARCHAR2,
+    p_ticket VARCHAR2,
+    p_surname VARCHAR2,
+    p_given_name VARCHAR2,
+    p_booking_class VARCHAR2,
+    p_seat VARCHAR2,
+    p_locator VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO PASSENGER_LIST_ENTRY (MESSAGE, TICKET, SURNAME, GIVEN_NAME, BOOKING_CLASS, SEAT, LOCATOR)
+    VALUES (p_message, p_ticket, p_surname, p_given_name, p_booking_class, p_seat, p_locator);
+END;
+/
+
+/*==============================================================*/
+/* Travel Document Procedures                                   */
+/*==============================================================*/
+
+-- Get the travel documents of a user and their companions. Number,
+-- nationality, issuing country, expiry date and sex are encrypted by the
+-- application.
+CREATE OR REPLACE PROCEDURE GetTravelDocuments(
+    p_user_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, AIRPORTUSER, TYPE, DOCUMENT_NUMBER, NATIONALITY, ISSUING_COUNTRY, EXPIRY_DATE, SEX, CREATED_AT, COMPANION
+    FROM TRAVEL_DOCUMENT
+    WHERE AIRPORTUSER = p_user_id
+    ORDER BY CREATED_AT;
+END;
+/
+
+-- Get a travel document by ID
+CREATE OR REPLACE PROCEDURE GetTravelDocumentByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, AIRPORTUSER, TYPE, DOCUMENT_NUMBER, NATIONALITY, ISSUING_COUNTRY, EXPIRY_DATE, SEX, CREATED_AT, COMPANION
+    FROM TRAVEL_DOCUMENT
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a travel document
+CREATE OR REPLACE PROCEDURE CreateTravelDocument(
+    p_id VARCHAR2,
+    p_user_id VARCHAR2,
+    p_type VARCHAR2,
+    p_number VARCHAR2,
+    p_nationality VARCHAR2,
+    p_issuing_country VARCHAR2,
+    p_expiry_date VARCHAR2,
+    p_sex VARCHAR2,
+    p_created_at TIMESTAMP,
+    p_companion VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO TRAVEL_DOCUMENT (ID, AIRPORTUSER, TYPE, DOCUMENT_NUMBER, NATIONALITY, ISSUING_COUNTRY, EXPIRY_DATE, SEX, CREATED_AT, COMPANION)
+    VALUES (p_id, p_user_id, p_type, p_number, p_nationality, p_issuing_country, p_expiry_date, p_sex, p_created_at, p_companion);
+END;
+/
+
+-- Replace the attributes of a travel document
+CREATE OR REPLACE PROCEDURE UpdateTravelDocument(
+    p_id VARCHAR2,
+    p_type VARCHAR2,
+    p_number VARCHAR2,
+    p_nationality VARCHAR2,
+    p_issuing_country VARCHAR2,
+    p_expiry_date VARCHAR2,
+    p_sex VARCHAR2
+)
+AS
+BEGIN
+    UPDATE TRAVEL_DOCUMENT SET
+        TYPE = p_type,
+        DOCUMENT_NUMBER = p_number,
+        NATIONALITY = p_nationality,
+        ISSUING_COUNTRY = p_issuing_country,
+        EXPIRY_DATE = p_expiry_date,
+        SEX = p_sex
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a travel document; checked-in tickets lose the reference
+CREATE OR REPLACE PROCEDURE DeleteTravelDocument(
+    p_id VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM TRAVEL_DOCUMENT WHERE ID = p_id;
+END;
+/
+
+-- Check in a confirmed ticket with the travel document shown
+CREATE OR REPLACE PROCEDURE CheckInTicket(
+    p_id VARCHAR2,
+    p_document VARCHAR2,
+    p_updated OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE TICKET SET STATUS = 'CHECKED_IN', TRAVEL_DOCUMENT = p_document
+    WHERE ID = p_id AND STATUS = 'CONFIRMED';
+    p_updated := SQL%ROWCOUNT;
+END;
+/
+
+/*==============================================================*/
+/* Booking Procedures                                           */
+/*==============================================================*/
+
+-- Get a booking by its locator
+CREATE OR REPLACE PROCEDURE GetBookingByLocator(
+    p_locator VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, LOCATOR, AIRPORTUSER, STATUS, CREATED_AT, HOLD_EXPIRES_AT
+    FROM BOOKING
+    WHERE LOCATOR = p_locator;
+END;
+/
+
+-- Get the bookings a user made or travels on, newest first
+CREATE OR REPLACE PROCEDURE GetBookingsByUserID(
+    p_user VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, LOCATOR, AIRPORTUSER, STATUS, CREATED_AT, HOLD_EXPIRES_AT
+    FROM BOOKING
+    WHERE AIRPORTUSER = p_user
+       OR ID IN (SELECT BOOKING FROM BOOKING_PASSENGER WHERE AIRPORTUSER = p_user)
+    ORDER BY CREATED_AT DESC;
+END;
+/
+
+-- Get the passengers of a booking in the order they were added
+CREATE OR REPLACE PROCEDURE GetBookingPassengers(
+    p_booking VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FIRSTNAME, LASTNAME, BIRTHDATE, AIRPORTUSER, COMPANION
+    FROM BOOKING_PASSENGER
+    WHERE BOOKING = p_booking
+    ORDER BY POSITION;
+END;
+/
+
+-- Get the tickets of a booking by departure, with the same columns as GetTicketByID
+CREATE OR REPLACE PROCEDURE GetBookingTickets(
+    p_booking VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT 
+        TICKET.ID,
+        TICKET.SEAT_NUMBER,
+        FLIGHT."FROM",
+        FLIGHT."TO",
+        TICKET.BOOKING_DATE,
+        COALESCE(FLIGHT.ACTUAL_DEPARTURE, FLIGHT.SCHEDULED_DEPARTURE) AS DEPARTURE_TIME,
+        TRAVEL_CLASS.NAME AS TRAVEL_CLASS,
+        TICKET.PRICE,
+        FLIGHT.GATE,
+        FLIGHT.BAGGAGE_CLAIM,
+        TICKET.STATUS,
+        TICKET.AIRPORTUSER,
+        TICKET.FLIGHT,
+        PLANE.MODEL,
+        TICKET.BOOKING,
+        BOOKING.LOCATOR,
+        TICKET.PASSENGER,
+        BOOKING_PASSENGER.FIRSTNAME,
+        BOOKING_PASSENGER.LASTNAME,
+        BOOKING_PASSENGER.AIRPORTUSER,
+        BOOKING_PASSENGER.COMPANION
+    FROM TICKET 
+    LEFT JOIN FLIGHT ON TICKET.FLIGHT = FLIGHT.ID 
+    LEFT JOIN TRAVEL_CLASS ON TICKET.TRAVEL_CLASS = TRAVEL_CLASS.ID 
+    LEFT JOIN PLANE ON FLIGHT.PLANE = PLANE.ID 
+    LEFT JOIN BOOKING ON TICKET.BOOKING = BOOKING.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
+    WHERE TICKET.BOOKING = p_booking
+    ORDER BY FLIGHT.SCHEDULED_DEPARTURE, BOOKING_PASSENGER.POSITION;
+END;
+/
+
+-- Count the tickets of a flight that still hold a seat
+CREATE OR REPLACE PROCEDURE GetFlightTicketCount(
+    p_flight VARCHAR2,
+    p_count OUT NUMBER
+)
+AS
+BEGIN
+    SELECT COUNT(*) INTO p_count
+    FROM TICKET
+    WHERE FLIGHT = p_flight
+      AND STATUS NOT IN ('CANCELLED', 'DENIED_BOARDING');
+END;
+/
+
+-- Create a booking
+CREATE OR REPLACE PROCEDURE CreateBooking(
+    p_id VARCHAR2,
+    p_locator VARCHAR2,
+    p_user VARCHAR2,
+    p_created_at TIMESTAMP,
+    p_hold_expires_at TIMESTAMP
+)
+AS
+BEGIN
+    INSERT INTO BOOKING (ID, LOCATOR, AIRPORTUSER, STATUS, CREATED_AT, HOLD_EXPIRES_AT)
+    VALUES (p_id, p_locator, p_user, 'HELD', p_created_at, p_hold_expires_at);
+END;
+/
+
+-- Add a passenger to a booking. The account is null for guests and
+-- companions, the companion is null for everyone else.
+CREATE OR REPLACE PROCEDURE AddBookingPassenger(
+    p_id VARCHAR2,
+    p_booking VARCHAR2,
+    p_position NUMBER,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_birthdate DATE,
+    p_user VARCHAR2,
+    p_companion VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO BOOKING_PASSENGER (ID, BOOKING, POSITION, FIRSTNAME, LASTNAME, BIRTHDATE, AIRPORTUSER, COMPANION)
+    VALUES (p_id, p_booking, p_position, p_firstname, p_lastname, p_birthdate, p_user, p_companion);
+END;
+/
+
+-- Issue the ticket of a booking passenger for one flight, held until the
+-- booking is paid
+CREATE OR REPLACE PROCEDURE AddBookingTicket(
+    p_id VARCHAR2,
+    p_booking VARCHAR2,
+    p_passenger VARCHAR2,
+    p_user VARCHAR2,
+    p_flight VARCHAR2,
+    p_travel_class NUMBER,
+    p_price NUMBER,
+    p_booking_date TIMESTAMP
+)
+AS
+BEGIN
+    INSERT INTO TICKET (ID, AIRPORTUSER, FLIGHT, TRAVEL_CLASS, PRICE, BOOKING_DATE, STATUS, BOOKING, PASSENGER)
+    VALUES (p_id, p_user, p_flight, p_travel_class, p_price, p_booking_date, 'HELD', p_booking, p_passenger);
+END;
+/
+
+-- Cancel a held or confirmed ticket. p_updated is 0 if the ticket was
+-- neither.
+CREATE OR REPLACE PROCEDURE CancelTicket(
+    p_id VARCHAR2,
+    p_updated OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE TICKET SET STATUS = 'CANCELLED'
+    WHERE ID = p_id AND STATUS IN ('HELD', 'CONFIRMED');
+    p_updated := SQL%ROWCOUNT;
+END;
+/
+
+-- Set the status of a booking
+CREATE OR REPLACE PROCEDURE UpdateBookingStatus(
+    p_id VARCHAR2,
+    p_status VARCHAR2
+)
+AS
+BEGIN
+    UPDATE BOOKING SET STATUS = p_status WHERE ID = p_id;
+END;
+/
+
+-- Confirm a held booking and its held tickets once it is paid. p_updated is
+-- 0 if the booking was not held.
+CREATE OR REPLACE PROCEDURE ConfirmBooking(
+    p_id VARCHAR2,
+    p_updated OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE BOOKING SET STATUS = 'CONFIRMED', HOLD_EXPIRES_AT = NULL
+    WHERE ID = p_id AND STATUS = 'HELD';
+    p_updated := SQL%ROWCOUNT;
+
+    IF p_updated > 0 THEN
+        UPDATE TICKET SET STATUS = 'CONFIRMED'
+        WHERE BOOKING = p_id AND STATUS = 'HELD';
+    END IF;
+END;
+/
+
+-- Get the held bookings whose hold has expired at p_now
+CREATE OR REPLACE PROCEDURE GetExpiredHolds(
+    p_now TIMESTAMP,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, LOCATOR, AIRPORTUSER, STATUS, CREATED_AT, HOLD_EXPIRES_AT
+    FROM BOOKING
+    WHERE STATUS = 'HELD' AND HOLD_EXPIRES_AT <= p_now
+    ORDER BY HOLD_EXPIRES_AT;
+END;
+/
+
+/*==============================================================*/
+/* Companion Procedures                                         */
+/*==============================================================*/
+
+-- Get the companions of a user by name
+CREATE OR REPLACE PROCEDURE GetCompanions(
+    p_user VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, AIRPORTUSER, FIRSTNAME, LASTNAME, BIRTHDATE, CREATED_AT
+    FROM COMPANION
+    WHERE AIRPORTUSER = p_user
+    ORDER BY FIRSTNAME, LASTNAME;
+END;
+/
+
+-- Get a companion by ID
+CREATE OR REPLACE PROCEDURE GetCompanionByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, AIRPORTUSER, FIRSTNAME, LASTNAME, BIRTHDATE, CREATED_AT
+    FROM COMPANION
+    WHERE ID = p_id;
+END;
+/
+
+-- Create a companion
+CREATE OR REPLACE PROCEDURE CreateCompanion(
+    p_id VARCHAR2,
+    p_user VARCHAR2,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_birthdate DATE,
+    p_created_at TIMESTAMP
+)
+AS
+BEGIN
+    INSERT INTO COMPANION (ID, AIRPORTUSER, FIRSTNAME, LASTNAME, BIRTHDATE, CREATED_AT)
+    VALUES (p_id, p_user, p_firstname, p_lastname, p_birthdate, p_created_at);
+END;
+/
+
+-- Update the name and birth date of a companion
+CREATE OR REPLACE PROCEDURE UpdateCompanion(
+    p_id VARCHAR2,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_birthdate DATE
+)
+AS
+BEGIN
+    UPDATE COMPANION SET
+        FIRSTNAME = p_firstname,
+        LASTNAME = p_lastname,
+        BIRTHDATE = p_birthdate
+    WHERE ID = p_id;
+END;
+/
+
+-- Delete a companion together with their travel documents, unless they hold
+-- a ticket for a flight that has not departed by p_now or wait for a seat.
+-- Past bookings and baggage keep the names they were made with.
+CREATE OR REPLACE PROCEDURE DeleteCompanion(
+    p_id VARCHAR2,
+    p_now TIMESTAMP,
+    p_deleted OUT NUMBER
+)
+AS
+    v_upcoming NUMBER;
+BEGIN
+    SELECT COUNT(*) INTO v_upcoming
+    FROM TICKET
+    JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
+    JOIN FLIGHT ON TICKET.FLIGHT = FLIGHT.ID
+    WHERE BOOKING_PASSENGER.COMPANION = p_id
+      AND TICKET.STATUS NOT IN ('CANCELLED', 'DENIED_BOARDING')
+      AND FLIGHT.ACTUAL_DEPARTURE IS NULL
+      AND FLIGHT.SCHEDULED_DEPARTURE > p_now;
+
+    SELECT v_upcoming + COUNT(*) INTO v_upcoming
+    FROM WAITLIST_PASSENGER
+    JOIN WAITLIST_ENTRY ON WAITLIST_PASSENGER.ENTRY = WAITLIST_ENTRY.ID
+    WHERE WAITLIST_PASSENGER.COMPANION = p_id
+      AND WAITLIST_ENTRY.STATUS IN ('WAITING', 'OFFERED');
+
+    IF v_upcoming > 0 THEN
+        p_deleted := 0;
+        RETURN;
+    END IF;
+
+    DELETE FROM COMPANION WHERE ID = p_id;
+    p_deleted := 1;
+END;
+/
+
+/*==============================================================*/
+/* Overbooking Procedures                                       */
+/*==============================================================*/
+
+-- Get the overbooking percentages of a flight and its airline with the seats of its plane
+CREATE OR REPLACE PROCEDURE GetFlightOverbooking(
+    p_flight VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        FLIGHT.ID,
+        FLIGHT.OVERBOOKING_PERCENT,
+        PLANE.AIRLINE,
+        AIRLINE.OVERBOOKING_PERCENT,
+        PLANE.SEATS
+    FROM FLIGHT
+    JOIN PLANE ON FLIGHT.PLANE = PLANE.ID
+    LEFT JOIN AIRLINE ON PLANE.AIRLINE = AIRLINE.ID
+    WHERE FLIGHT.ID = p_flight;
+END;
+/
+
+-- Set or clear (null) the overbooking percentage of a flight
+CREATE OR REPLACE PROCEDURE SetFlightOverbooking(
+    p_flight VARCHAR2,
+    p_percent NUMBER,
+    p_updated OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE FLIGHT SET OVERBOOKING_PERCENT = p_percent WHERE ID = p_flight;
+    p_updated := SQL%ROWCOUNT;
+END;
+/
+
+-- Set or clear (null) the overbooking percentage of an airline
+CREATE OR REPLACE PROCEDURE SetAirlineOverbooking(
+    p_airline VARCHAR2,
+    p_percent NUMBER,
+    p_updated OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE AIRLINE SET OVERBOOKING_PERCENT = p_percent WHERE ID = p_airline;
+    p_updated := SQL%ROWCOUNT;
+END;
+/
+
+-- Count the checked-in tickets of a flight
+CREATE OR REPLACE PROCEDURE GetFlightCheckedInCount(
+    p_flight VARCHAR2,
+    p_count OUT NUMBER
+)
+AS
+BEGIN
+    SELECT COUNT(*) INTO p_count
+    FROM TICKET
+    WHERE FLIGHT = p_flight
+      AND STATUS = 'CHECKED_IN';
+END;
+/
+
+/*==============================================================*/
+/* Waitlist Procedures                                          */
+/*==============================================================*/
+
+-- Get a waitlist entry by ID
+CREATE OR REPLACE PROCEDURE GetWaitlistEntryByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FLIGHT, AIRPORTUSER, TRAVEL_CLASS, SEATS, STATUS, CREATED_AT, OFFERED_AT, OFFER_EXPIRES_AT, BOOKING
+    FROM WAITLIST_ENTRY
+    WHERE ID = p_id;
+END;
+/
+
+-- Get the waitlist entries of a user, newest first
+CREATE OR REPLACE PROCEDURE GetWaitlistByUserID(
+    p_user VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FLIGHT, AIRPORTUSER, TRAVEL_CLASS, SEATS, STATUS, CREATED_AT, OFFERED_AT, OFFER_EXPIRES_AT, BOOKING
+    FROM WAITLIST_ENTRY
+    WHERE AIRPORTUSER = p_user
+    ORDER BY CREATED_AT DESC;
+END;
+/
+
+-- Get the waitlist of a flight in the order the entries were made
+CREATE OR REPLACE PROCEDURE GetFlightWaitlist(
+    p_flight VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, FLIGHT, AIRPORTUSER, TRAVEL_CLASS, SEATS, STATUS, CREATED_AT, OFFERED_AT, OFFER_EXPIRES_AT, BOOKING
+    FROM WAITLIST_ENTRY
+    WHERE FLIGHT = p_flight
+    ORDER BY CREATED_AT, ID;
+END;
+/
+
+-- Get the open entries whose offer expired by p_now, or whose flight has
+-- departed or was cancelled
+CREATE OR REPLACE PROCEDURE GetStaleWaitlistEntries(
+    p_now TIMESTAMP,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        WAITLIST_ENTRY.ID,
+        WAITLIST_ENTRY.FLIGHT,
+        WAITLIST_ENTRY.AIRPORTUSER,
+        WAITLIST_ENTRY.TRAVEL_CLASS,
+        WAITLIST_ENTRY.SEATS,
+        WAITLIST_ENTRY.STATUS,
+        WAITLIST_ENTRY.CREATED_AT,
+        WAITLIST_ENTRY.OFFERED_AT,
+        WAITLIST_ENTRY.OFFER_EXPIRES_AT,
+        WAITLIST_ENTRY.BOOKING
+    FROM WAITLIST_ENTRY
+    JOIN FLIGHT ON WAITLIST_ENTRY.FLIGHT = FLIGHT.ID
+    WHERE WAITLIST_ENTRY.STATUS IN ('WAITING', 'OFFERED')
+      AND ((WAITLIST_ENTRY.STATUS = 'OFFERED' AND WAITLIST_ENTRY.OFFER_EXPIRES_AT <= p_now)
+        OR FLIGHT.ACTUAL_DEPARTURE IS NOT NULL
+        OR FLIGHT.SCHEDULED_DEPARTURE <= p_now
+        OR FLIGHT.STATUS = 6)
+    ORDER BY WAITLIST_ENTRY.FLIGHT, WAITLIST_ENTRY.CREATED_AT;
+END;
+/
+
+-- Get the passengers of a waitlist entry in the order they were added
+CREATE OR REPLACE PROCEDURE GetWaitlistPassengers(
+    p_entry VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT SELF, COMPANION, FIRSTNAME, LASTNAME, BIRTHDATE
+    FROM WAITLIST_PASSENGER
+    WHERE ENTRY = p_entry
+    ORDER BY POSITION;
+END;
+/
+
+-- Sum the seats held by unexpired offers on a flight, except for the entry p_exclude
+CREATE OR REPLACE PROCEDURE GetFlightHeldSeats(
+    p_flight VARCHAR2,
+    p_now TIMESTAMP,
+    p_exclude VARCHAR2,
+    p_count OUT NUMBER
+)
+AS
+BEGIN
+    SELECT NVL(SUM(SEATS), 0) INTO p_count
+    FROM WAITLIST_ENTRY
+    WHERE FLIGHT = p_flight
+      AND STATUS = 'OFFERED'
+      AND OFFER_EXPIRES_AT > p_now
+      AND ID <> NVL(p_exclude, '-');
+END;
+/
+
+-- Create a waitlist entry
+CREATE OR REPLACE PROCEDURE CreateWaitlistEntry(
+    p_id VARCHAR2,
+    p_flight VARCHAR2,
+    p_user VARCHAR2,
+    p_travel_class NUMBER,
+    p_seats NUMBER,
+    p_created_at TIMESTAMP
+)
+AS
+BEGIN
+    INSERT INTO WAITLIST_ENTRY (ID, FLIGHT, AIRPORTUSER, TRAVEL_CLASS, SEATS, STATUS, CREATED_AT)
+    VALUES (p_id, p_flight, p_user, p_travel_class, p_seats, 'WAITING', p_created_at);
+END;
+/
+
+-- Add a passenger to a waitlist entry
+CREATE OR REPLACE PROCEDURE AddWaitlistPassenger(
+    p_id VARCHAR2,
+    p_entry VARCHAR2,
+    p_position NUMBER,
+    p_self NUMBER,
+    p_companion VARCHAR2,
+    p_firstname VARCHAR2,
+    p_lastname VARCHAR2,
+    p_birthdate DATE
+)
+AS
+BEGIN
+    INSERT INTO WAITLIST_PASSENGER (ID, ENTRY, POSITION, SELF, COMPANION, FIRSTNAME, LASTNAME, BIRTHDATE)
+    VALUES (p_id, p_entry, p_position, p_self, p_companion, p_firstname, p_lastname, p_birthdate);
+END;
+/
+
+-- Set the status, offer and booking of a waitlist entry
+CREATE OR REPLACE PROCEDURE UpdateWaitlistEntry(
+    p_id VARCHAR2,
+    p_status VARCHAR2,
+    p_offered_at TIMESTAMP,
+    p_expires_at TIMESTAMP,
+    p_booking VARCHAR2
+)
+AS
+BEGIN
+    UPDATE WAITLIST_ENTRY SET
+        STATUS = p_status,
+        OFFERED_AT = p_offered_at,
+        OFFER_EXPIRES_AT = p_expires_at,
+        BOOKING = p_booking
+    WHERE ID = p_id;
+END;
+/
+
+/*==============================================================*/
+/* Denied Boarding Procedures                                   */
+/*==============================================================*/
+
+-- Get the volunteers and denied boardings of a flight with the passenger names
+CREATE OR REPLACE PROCEDURE GetDeniedBoardingByFlight(
+    p_flight VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        DENIED_BOARDING.ID,
+        DENIED_BOARDING.TICKET,
+        DENIED_BOARDING.FLIGHT,
+        DENIED_BOARDING.STATUS,
+        DENIED_BOARDING.VOLUNTARY,
+        DENIED_BOARDING.REQUESTED_COMPENSATION,
+        DENIED_BOARDING.COMPENSATION,
+        DENIED_BOARDING.CURRENCY,
+        DENIED_BOARDING.NOTE,
+        DENIED_BOARDING.CREATED_AT,
+        DENIED_BOARDING.DECIDED_AT,
+        DENIED_BOARDING.DECIDED_BY,
+        NVL2(TICKET.PASSENGER,
+             BOOKING_PASSENGER.FIRSTNAME || ' ' || BOOKING_PASSENGER.LASTNAME,
+             AIRPORTUSER.FIRSTNAME || ' ' || AIRPORTUSER.LASTNAME) AS PASSENGER_NAME,
+        TICKET.STATUS
+    FROM DENIED_BOARDING
+    JOIN TICKET ON DENIED_BOARDING.TICKET = TICKET.ID
+    JOIN AIRPORTUSER ON TICKET.AIRPORTUSER = AIRPORTUSER.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
+    WHERE DENIED_BOARDING.FLIGHT = p_flight
+    ORDER BY DENIED_BOARDING.CREATED_AT, DENIED_BOARDING.ID;
+END;
+/
+
+-- Get the volunteer or denied boarding record of a ticket
+CREATE OR REPLACE PROCEDURE GetDeniedBoardingByTicket(
+    p_ticket VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        DENIED_BOARDING.ID,
+        DENIED_BOARDING.TICKET,
+        DENIED_BOARDING.FLIGHT,
+        DENIED_BOARDING.STATUS,
+        DENIED_BOARDING.VOLUNTARY,
+        DENIED_BOARDING.REQUESTED_COMPENSATION,
+        DENIED_BOARDING.COMPENSATION,
+        DENIED_BOARDING.CURRENCY,
+        DENIED_BOARDING.NOTE,
+        DENIED_BOARDING.CREATED_AT,
+        DENIED_BOARDING.DECIDED_AT,
+        DENIED_BOARDING.DECIDED_BY,
+        NVL2(TICKET.PASSENGER,
+             BOOKING_PASSENGER.FIRSTNAME || ' ' || BOOKING_PASSENGER.LASTNAME,
+             AIRPORTUSER.FIRSTNAME || ' ' || AIRPORTUSER.LASTNAME) AS PASSENGER_NAME,
+        TICKET.STATUS
+    FROM DENIED_BOARDING
+    JOIN TICKET ON DENIED_BOARDING.TICKET = TICKET.ID
+    JOIN AIRPORTUSER ON TICKET.AIRPORTUSER = AIRPORTUSER.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
+    WHERE DENIED_BOARDING.TICKET = p_ticket;
+END;
+/
+
+-- Register a ticket as volunteer to give up its seat
+CREATE OR REPLACE PROCEDURE CreateBoardingVolunteer(
+    p_id VARCHAR2,
+    p_ticket VARCHAR2,
+    p_flight VARCHAR2,
+    p_requested NUMBER,
+    p_currency VARCHAR2,
+    p_created_at TIMESTAMP
+)
+AS
+BEGIN
+    INSERT INTO DENIED_BOARDING (ID, TICKET, FLIGHT, STATUS, VOLUNTARY, REQUESTED_COMPENSATION, CURRENCY, CREATED_AT)
+    VALUES (p_id, p_ticket, p_flight, 'VOLUNTEER', 1, p_requested, p_currency, p_created_at);
+END;
+/
+
+-- Withdraw a volunteer that has not been denied boarding yet
+CREATE OR REPLACE PROCEDURE DeleteBoardingVolunteer(
+    p_ticket VARCHAR2,
+    p_deleted OUT NUMBER
+)
+AS
+BEGIN
+    DELETE FROM DENIED_BOARDING WHERE TICKET = p_ticket AND STATUS = 'VOLUNTEER';
+    p_deleted := SQL%ROWCOUNT;
+END;
+/
+
+-- Deny boarding to a confirmed or checked-in ticket and record the
+-- compensation. The volunteer record of the ticket is completed, or a record
+-- for an involuntary denial is created. p_updated is 0 if the ticket holds
+-- no seat.
+CREATE OR REPLACE PROCEDURE DenyBoarding(
+    p_id VARCHAR2,
+    p_ticket VARCHAR2,
+    p_flight VARCHAR2,
+    p_compensation NUMBER,
+    p_currency VARCHAR2,
+    p_note VARCHAR2,
+    p_now TIMESTAMP,
+    p_staff VARCHAR2,
+    p_updated OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE TICKET SET STATUS = 'DENIED_BOARDING'
+    WHERE ID = p_ticket AND STATUS IN ('CONFIRMED', 'CHECKED_IN');
+    p_updated := SQL%ROWCOUNT;
+    IF p_updated = 0 THEN
+        RETURN;
+    END IF;
+
+    MERGE INTO DENIED_BOARDING D
+    USING (SELECT p_ticket AS TICKET FROM DUAL) S
+    ON (D.TICKET = S.TICKET)
+    WHEN MATCHED THEN UPDATE SET
+        D.STATUS = 'DENIED',
+        D.COMPENSATION = p_compensation,
+        D.CURRENCY = p_currency,
+        D.NOTE = p_note,
+        D.DECIDED_AT = p_now,
+        D.DECIDED_BY = p_staff
+    WHEN NOT MATCHED THEN INSERT
+        (ID, TICKET, FLIGHT, STATUS, VOLUNTARY, COMPENSATION, CURRENCY, NOTE, CREATED_AT, DECIDED_AT, DECIDED_BY)
+        VALUES (p_id, p_ticket, p_flight, 'DENIED', 0, p_compensation, p_currency, p_note, p_now, p_now, p_staff);
+END;
+/
+
+/*==============================================================*/
+/* Fare Procedures                                              */
+/*==============================================================*/
+
+-- Get the fares of a flight by travel class
+CREATE OR REPLACE PROCEDURE GetFlightFares(
+    p_flight VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT FLIGHT_FARE.TRAVEL_CLASS, TRAVEL_CLASS.NAME, FLIGHT_FARE.PRICE
+    FROM FLIGHT_FARE
+    LEFT JOIN TRAVEL_CLASS ON FLIGHT_FARE.TRAVEL_CLASS = TRAVEL_CLASS.ID
+    WHERE FLIGHT_FARE.FLIGHT = p_flight
+    ORDER BY FLIGHT_FARE.TRAVEL_CLASS;
+END;
+/
+
+-- Remove all fares of a flight
+CREATE OR REPLACE PROCEDURE DeleteFlightFares(
+    p_flight VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM FLIGHT_FARE WHERE FLIGHT = p_flight;
+END;
+/
+
+-- Add the fare of a flight for one travel class
+CREATE OR REPLACE PROCEDURE AddFlightFare(
+    p_flight VARCHAR2,
+    p_travel_class NUMBER,
+    p_price NUMBER
+)
+AS
+BEGIN
+    INSERT INTO FLIGHT_FARE (FLIGHT, TRAVEL_CLASS, PRICE)
+    VALUES (p_flight, p_travel_class, p_price);
+END;
+/
+
+/*==============================================================*/
+/* Payment Procedures                                           */
+/*==============================================================*/
+
+-- Get a payment by ID
+CREATE OR REPLACE PROCEDURE GetPaymentByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        PAYMENT.ID,
+        PAYMENT.BOOKING,
+        BOOKING.LOCATOR,
+        PAYMENT.AIRPORTUSER,
+        PAYMENT.PROVIDER,
+        PAYMENT.REFERENCE,
+        PAYMENT.STATUS,
+        PAYMENT.AMOUNT,
+        PAYMENT.REFUNDED_AMOUNT,
+        PAYMENT.CURRENCY,
+        PAYMENT.CARD_BRAND,
+        PAYMENT.CARD_LAST4,
+        PAYMENT.FAILURE_CODE,
+        PAYMENT.FAILURE_MESSAGE,
+        PAYMENT.CREATED_AT,
+        PAYMENT.UPDATED_AT,
+        PAYMENT.CAPTURED_AT
+    FROM PAYMENT
+    LEFT JOIN BOOKING ON PAYMENT.BOOKING = BOOKING.ID
+    WHERE PAYMENT.ID = p_id;
+END;
+/
+
+-- Get a payment by the reference of its provider
+CREATE OR REPLACE PROCEDURE GetPaymentByReference(
+    p_provider VARCHAR2,
+    p_reference VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        PAYMENT.ID,
+        PAYMENT.BOOKING,
+        BOOKING.LOCATOR,
+        PAYMENT.AIRPORTUSER,
+        PAYMENT.PROVIDER,
+        PAYMENT.REFERENCE,
+        PAYMENT.STATUS,
+        PAYMENT.AMOUNT,
+        PAYMENT.REFUNDED_AMOUNT,
+        PAYMENT.CURRENCY,
+        PAYMENT.CARD_BRAND,
+        PAYMENT.CARD_LAST4,
+        PAYMENT.FAILURE_CODE,
+        PAYMENT.FAILURE_MESSAGE,
+        PAYMENT.CREATED_AT,
+        PAYMENT.UPDATED_AT,
+        PAYMENT.CAPTURED_AT
+    FROM PAYMENT
+    LEFT JOIN BOOKING ON PAYMENT.BOOKING = BOOKING.ID
+    WHERE PAYMENT.PROVIDER = p_provider AND PAYMENT.REFERENCE = p_reference;
+END;
+/
+
+-- Get the payments of a booking, oldest first
+CREATE OR REPLACE PROCEDURE GetPaymentsByBooking(
+    p_booking VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        PAYMENT.ID,
+        PAYMENT.BOOKING,
+        BOOKING.LOCATOR,
+        PAYMENT.AIRPORTUSER,
+        PAYMENT.PROVIDER,
+        PAYMENT.REFERENCE,
+        PAYMENT.STATUS,
+        PAYMENT.AMOUNT,
+        PAYMENT.REFUNDED_AMOUNT,
+        PAYMENT.CURRENCY,
+        PAYMENT.CARD_BRAND,
+        PAYMENT.CARD_LAST4,
+        PAYMENT.FAILURE_CODE,
+        PAYMENT.FAILURE_MESSAGE,
+        PAYMENT.CREATED_AT,
+        PAYMENT.UPDATED_AT,
+        PAYMENT.CAPTURED_AT
+    FROM PAYMENT
+    LEFT JOIN BOOKING ON PAYMENT.BOOKING = BOOKING.ID
+    WHERE PAYMENT.BOOKING = p_booking
+    ORDER BY PAYMENT.CREATED_AT;
+END;
+/
+
+-- Create a payment before it is sent to the provider
+CREATE OR REPLACE PROCEDURE CreatePayment(
+    p_id VARCHAR2,
+    p_booking VARCHAR2,
+    p_user VARCHAR2,
+    p_provider VARCHAR2,
+    p_amount NUMBER,
+    p_currency VARCHAR2,
+    p_card_brand VARCHAR2,
+    p_card_last4 VARCHAR2,
+    p_created_at TIMESTAMP
+)
+AS
+BEGIN
+    INSERT INTO PAYMENT (ID, BOOKING, AIRPORTUSER, PROVIDER, STATUS, AMOUNT, CURRENCY, CARD_BRAND, CARD_LAST4, CREATED_AT, UPDATED_AT)
+    VALUES (p_id, p_booking, p_user, p_provider, 'PENDING', p_amount, p_currency, p_card_brand, p_card_last4, p_created_at, p_created_at);
+END;
+/
+
+-- Link a ticket to the payment that pays for it
+CREATE OR REPLACE PROCEDURE AddPaymentTicket(
+    p_payment VARCHAR2,
+    p_ticket VARCHAR2,
+    p_amount NUMBER
+)
+AS
+BEGIN
+    INSERT INTO PAYMENT_TICKET (PAYMENT, TICKET, AMOUNT)
+    VALUES (p_payment, p_ticket, p_amount);
+END;
+/
+
+-- Store the provider reference, status and failure of a payment
+CREATE OR REPLACE PROCEDURE UpdatePayment(
+    p_id VARCHAR2,
+    p_reference VARCHAR2,
+    p_status VARCHAR2,
+    p_failure_code VARCHAR2,
+    p_failure_message VARCHAR2,
+    p_updated_at TIMESTAMP,
+    p_captured_at TIMESTAMP
+)
+AS
+BEGIN
+    UPDATE PAYMENT SET
+        REFERENCE = p_reference,
+        STATUS = p_status,
+        FAILURE_CODE = p_failure_code,
+        FAILURE_MESSAGE = p_failure_message,
+        UPDATED_AT = p_updated_at,
+        CAPTURED_AT = p_captured_at
+    WHERE ID = p_id;
+END;
+/
+
+-- Get the tickets paid by a payment with their refund
+CREATE OR REPLACE PROCEDURE GetPaymentTickets(
+    p_payment VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT PAYMENT_TICKET.TICKET, PAYMENT_TICKET.AMOUNT, PAYMENT_TICKET.REFUND
+    FROM PAYMENT_TICKET
+    LEFT JOIN TICKET ON PAYMENT_TICKET.TICKET = TICKET.ID
+    LEFT JOIN FLIGHT ON TICKET.FLIGHT = FLIGHT.ID
+    LEFT JOIN BOOKING_PASSENGER ON TICKET.PASSENGER = BOOKING_PASSENGER.ID
+    WHERE PAYMENT_TICKET.PAYMENT = p_payment
+    ORDER BY FLIGHT.SCHEDULED_DEPARTURE, BOOKING_PASSENGER.POSITION;
+END;
+/
+
+-- Get the refunds of a payment, oldest first
+CREATE OR REPLACE PROCEDURE GetPaymentRefunds(
+    p_payment VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, PAYMENT, REFERENCE, AMOUNT, STATUS, REASON, CREATED_AT, CREATED_BY
+    FROM PAYMENT_REFUND
+    WHERE PAYMENT = p_payment
+    ORDER BY CREATED_AT;
+END;
+/
+
+-- Get a refund by the reference of the provider of its payment
+CREATE OR REPLACE PROCEDURE GetRefundByReference(
+    p_provider VARCHAR2,
+    p_reference VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT PAYMENT_REFUND.ID, PAYMENT_REFUND.PAYMENT, PAYMENT_REFUND.REFERENCE, PAYMENT_REFUND.AMOUNT,
+           PAYMENT_REFUND.STATUS, PAYMENT_REFUND.REASON, PAYMENT_REFUND.CREATED_AT, PAYMENT_REFUND.CREATED_BY
+    FROM PAYMENT_REFUND
+    JOIN PAYMENT ON PAYMENT_REFUND.PAYMENT = PAYMENT.ID
+    WHERE PAYMENT.PROVIDER = p_provider AND PAYMENT_REFUND.REFERENCE = p_reference;
+END;
+/
+
+-- Create a pending refund
+CREATE OR REPLACE PROCEDURE CreatePaymentRefund(
+    p_id VARCHAR2,
+    p_payment VARCHAR2,
+    p_amount NUMBER,
+    p_reason VARCHAR2,
+    p_created_at TIMESTAMP,
+    p_created_by VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO PAYMENT_REFUND (ID, PAYMENT, AMOUNT, STATUS, REASON, CREATED_AT, CREATED_BY)
+    VALUES (p_id, p_payment, p_amount, 'PENDING', p_reason, p_created_at, p_created_by);
+END;
+/
+
+-- Mark a ticket of a payment as refunded by a refund. p_updated is 0 if the
+-- ticket is not part of the payment or was already refunded.
+CREATE OR REPLACE PROCEDURE SetPaymentTicketRefund(
+    p_payment VARCHAR2,
+    p_ticket VARCHAR2,
+    p_refund VARCHAR2,
+    p_updated OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE PAYMENT_TICKET SET REFUND = p_refund
+    WHERE PAYMENT = p_payment AND TICKET = p_ticket AND REFUND IS NULL;
+    p_updated := SQL%ROWCOUNT;
+END;
+/
+
+-- Complete a pending refund. A successful refund is added to the refunded
+-- amount of its payment, which becomes REFUNDED once fully refunded; a
+-- failed refund releases its tickets so they can be refunded again.
+CREATE OR REPLACE PROCEDURE CompletePaymentRefund(
+    p_id VARCHAR2,
+    p_reference VARCHAR2,
+    p_status VARCHAR2,
+    p_now TIMESTAMP
+)
+AS
+    v_payment VARCHAR2(36);
+    v_amount NUMBER;
+BEGIN
+    UPDATE PAYMENT_REFUND SET STATUS = p_status, REFERENCE = COALESCE(p_reference, REFERENCE)
+    WHERE ID = p_id AND STATUS = 'PENDING'
+    RETURNING PAYMENT, AMOUNT INTO v_payment, v_amount;
+
+    IF SQL%ROWCOUNT = 0 THEN
+        RETURN;
+    END IF;
+
+    IF p_status = 'SUCCEEDED' THEN
+        UPDATE PAYMENT SET
+            REFUNDED_AMOUNT = REFUNDED_AMOUNT + v_amount,
+            STATUS = CASE WHEN REFUNDED_AMOUNT + v_amount >= AMOUNT THEN 'REFUNDED' ELSE STATUS END,
+            UPDATED_AT = p_now
+        WHERE ID = v_payment;
+    ELSIF p_status = 'FAILED' THEN
+        UPDATE PAYMENT_TICKET SET REFUND = NULL WHERE REFUND = p_id;
+    END IF;
+END;
+/
+
+-- Claim an idempotency key for a request. p_claimed is 0 if the key was
+-- already used in the scope, unless its request was claimed before
+-- p_stale_before and never finished.
+CREATE OR REPLACE PROCEDURE ClaimIdempotencyKey(
+    p_scope VARCHAR2,
+    p_key VARCHAR2,
+    p_hash VARCHAR2,
+    p_now TIMESTAMP,
+    p_stale_before TIMESTAMP,
+    p_claimed OUT NUMBER
+)
+AS
+BEGIN
+    INSERT INTO IDEMPOTENCY_KEY (SCOPE, REQUEST_KEY, REQUEST_HASH, CREATED_AT)
+    VALUES (p_scope, p_key, p_hash, p_now);
+    p_claimed := 1;
+EXCEPTION
+    WHEN DUP_VAL_ON_INDEX THEN
+        -- Take over a claim whose request never finished, e.g. after a crash
+        UPDATE IDEMPOTENCY_KEY SET REQUEST_HASH = p_hash, CREATED_AT = p_now
+        WHERE SCOPE = p_scope AND REQUEST_KEY = p_key
+          AND STATUS_CODE IS NULL AND CREATED_AT < p_stale_before;
+        p_claimed := SQL%ROWCOUNT;
+END;
+/
+
+-- Get a used idempotency key with the stored response
+CREATE OR REPLACE PROCEDURE GetIdempotencyKey(
+    p_scope VARCHAR2,
+    p_key VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT SCOPE, REQUEST_KEY, REQUEST_HASH, STATUS_CODE, RESPONSE, CREATED_AT
+    FROM IDEMPOTENCY_KEY
+    WHERE SCOPE = p_scope AND REQUEST_KEY = p_key;
+END;
+/
+
+-- Store the response of the request that claimed an idempotency key
+CREATE OR REPLACE PROCEDURE SaveIdempotencyResponse(
+    p_scope VARCHAR2,
+    p_key VARCHAR2,
+    p_status_code NUMBER,
+    p_response CLOB
+)
+AS
+BEGIN
+    UPDATE IDEMPOTENCY_KEY SET STATUS_CODE = p_status_code, RESPONSE = p_response
+    WHERE SCOPE = p_scope AND REQUEST_KEY = p_key;
+END;
+/
+
+-- Release an idempotency key, so the request can be retried
+CREATE OR REPLACE PROCEDURE DeleteIdempotencyKey(
+    p_scope VARCHAR2,
+    p_key VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM IDEMPOTENCY_KEY WHERE SCOPE = p_scope AND REQUEST_KEY = p_key;
+END;
+/
+
+-- Remove idempotency keys used before p_before
+CREATE OR REPLACE PROCEDURE DeleteIdempotencyKeysBefore(
+    p_before TIMESTAMP,
+    p_deleted OUT NUMBER
+)
+AS
+BEGIN
+    DELETE FROM IDEMPOTENCY_KEY WHERE CREATED_AT < p_before;
+    p_deleted := SQL%ROWCOUNT;
+END;
+/
+
+/*==============================================================*/
+/* Invoice Issuer Procedures                                    */
+/*==============================================================*/
+
+-- Get the legal details of the airport valid at p_at
+CREATE OR REPLACE PROCEDURE GetInvoiceIssuer(
+    p_at TIMESTAMP,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, STREET, POSTCODE, CITY, COUNTRY, VAT_ID, TAX_NUMBER, REGISTER_COURT, REGISTER_NUMBER,
+        MANAGING_DIRECTORS, CONTACT_NAME, EMAIL, PHONE, IBAN, BIC, BANK_NAME, VALID_FROM, CREATED_BY
+    FROM INVOICE_ISSUER
+    WHERE VALID_FROM = (SELECT MAX(VALID_FROM) FROM INVOICE_ISSUER WHERE VALID_FROM <= p_at);
+END;
+/
+
+-- Get a version of the legal details of the airport by ID
+CREATE OR REPLACE PROCEDURE GetInvoiceIssuerByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT ID, NAME, STREET, POSTCODE, CITY, COUNTRY, VAT_ID, TAX_NUMBER, REGISTER_COURT, REGISTER_NUMBER,
+        MANAGING_DIRECTORS, CONTACT_NAME, EMAIL, PHONE, IBAN, BIC, BANK_NAME, VALID_FROM, CREATED_BY
+    FROM INVOICE_ISSUER
+    WHERE ID = p_id;
+END;
+/
+
+-- Add a new version of the legal details of the airport
+CREATE OR REPLACE PROCEDURE CreateInvoiceIssuer(
+    p_id VARCHAR2,
+    p_name VARCHAR2,
+    p_street VARCHAR2,
+    p_postcode VARCHAR2,
+    p_city VARCHAR2,
+    p_country VARCHAR2,
+    p_vat_id VARCHAR2,
+    p_tax_number VARCHAR2,
+    p_register_court VARCHAR2,
+    p_register_number VARCHAR2,
+    p_managing_directors VARCHAR2,
+    p_contact_name VARCHAR2,
+    p_email VARCHAR2,
+    p_phone VARCHAR2,
+    p_iban VARCHAR2,
+    p_bic VARCHAR2,
+    p_bank_name VARCHAR2,
+    p_valid_from TIMESTAMP,
+    p_created_by VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO INVOICE_ISSUER (ID, NAME, STREET, POSTCODE, CITY, COUNTRY, VAT_ID, TAX_NUMBER, REGISTER_COURT, REGISTER_NUMBER,
+        MANAGING_DIRECTORS, CONTACT_NAME, EMAIL, PHONE, IBAN, BIC, BANK_NAME, VALID_FROM, CREATED_BY)
+    VALUES (p_id, p_name, p_street, p_postcode, p_city, p_country, p_vat_id, p_tax_number, p_register_court, p_register_number,
+        p_managing_directors, p_contact_name, p_email, p_phone, p_iban, p_bic, p_bank_name, p_valid_from, p_created_by);
+END;
+/
+
+/*==============================================================*/
+/* Billing Address Procedures                                   */
+/*==============================================================*/
+
+-- Get the billing address of a user
+CREATE OR REPLACE PROCEDURE GetBillingAddress(
+    p_user VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT AIRPORTUSER, COMPANY, NAME, STREET, POSTCODE, CITY, COUNTRY, VAT_ID, BUYER_REFERENCE, EMAIL, UPDATED_AT
+    FROM BILLING_ADDRESS
+    WHERE AIRPORTUSER = p_user;
+END;
+/
+
+-- Create or replace the billing address of a user
+CREATE OR REPLACE PROCEDURE SaveBillingAddress(
+    p_user VARCHAR2,
+    p_company VARCHAR2,
+    p_name VARCHAR2,
+    p_street VARCHAR2,
+    p_postcode VARCHAR2,
+    p_city VARCHAR2,
+    p_country VARCHAR2,
+    p_vat_id VARCHAR2,
+    p_buyer_reference VARCHAR2,
+    p_email VARCHAR2,
+    p_updated_at TIMESTAMP
+)
+AS
+BEGIN
+    MERGE INTO BILLING_ADDRESS B
+    USING (SELECT p_user AS AIRPORTUSER FROM DUAL) S
+    ON (B.AIRPORTUSER = S.AIRPORTUSER)
+    WHEN MATCHED THEN UPDATE SET
+        COMPANY = p_company, NAME = p_name, STREET = p_street, POSTCODE = p_postcode, CITY = p_city,
+        COUNTRY = p_country, VAT_ID = p_vat_id, BUYER_REFERENCE = p_buyer_reference, EMAIL = p_email,
+        UPDATED_AT = p_updated_at
+    WHEN NOT MATCHED THEN
+        INSERT (AIRPORTUSER, COMPANY, NAME, STREET, POSTCODE, CITY, COUNTRY, VAT_ID, BUYER_REFERENCE, EMAIL, UPDATED_AT)
+        VALUES (p_user, p_company, p_name, p_street, p_postcode, p_city, p_country, p_vat_id, p_buyer_reference, p_email, p_updated_at);
+END;
+/
+
+-- Delete the billing address of a user
+CREATE OR REPLACE PROCEDURE DeleteBillingAddress(
+    p_user VARCHAR2
+)
+AS
+BEGIN
+    DELETE FROM BILLING_ADDRESS WHERE AIRPORTUSER = p_user;
+END;
+/
+
+/*==============================================================*/
+/* Baggage Fee Procedures                                       */
+/*==============================================================*/
+
+-- Get an excess baggage fee by ID
+CREATE OR REPLACE PROCEDURE GetBaggageFeeByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        BAGGAGE_FEE.ID,
+        BAGGAGE_FEE.BAGGAGE,
+        BAGGAGE.TRACKING_NUMBER,
+        BAGGAGE_FEE.AIRPORTUSER,
+        BAGGAGE_FEE.FLIGHT,
+        BAGGAGE_FEE.REASON,
+        BAGGAGE_FEE.WEIGHT,
+        BAGGAGE_FEE.AMOUNT,
+        BAGGAGE_FEE.CURRENCY,
+        BAGGAGE_FEE.PAYMENT_METHOD,
+        BAGGAGE_FEE.CARD_LAST4,
+        BAGGAGE_FEE.PAID_AT,
+        BAGGAGE_FEE.CREATED_BY
+    FROM BAGGAGE_FEE
+    LEFT JOIN BAGGAGE ON BAGGAGE_FEE.BAGGAGE = BAGGAGE.ID
+    WHERE BAGGAGE_FEE.ID = p_id;
+END;
+/
+
+-- Get the excess baggage fee charged for a bag
+CREATE OR REPLACE PROCEDURE GetBaggageFeeByBaggage(
+    p_baggage VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        BAGGAGE_FEE.ID,
+        BAGGAGE_FEE.BAGGAGE,
+        BAGGAGE.TRACKING_NUMBER,
+        BAGGAGE_FEE.AIRPORTUSER,
+        BAGGAGE_FEE.FLIGHT,
+        BAGGAGE_FEE.REASON,
+        BAGGAGE_FEE.WEIGHT,
+        BAGGAGE_FEE.AMOUNT,
+        BAGGAGE_FEE.CURRENCY,
+        BAGGAGE_FEE.PAYMENT_METHOD,
+        BAGGAGE_FEE.CARD_LAST4,
+        BAGGAGE_FEE.PAID_AT,
+        BAGGAGE_FEE.CREATED_BY
+    FROM BAGGAGE_FEE
+    LEFT JOIN BAGGAGE ON BAGGAGE_FEE.BAGGAGE = BAGGAGE.ID
+    WHERE BAGGAGE_FEE.BAGGAGE = p_baggage;
+END;
+/
+
+-- Record a paid excess baggage fee
+CREATE OR REPLACE PROCEDURE CreateBaggageFee(
+    p_id VARCHAR2,
+    p_baggage VARCHAR2,
+    p_user VARCHAR2,
+    p_flight VARCHAR2,
+    p_reason VARCHAR2,
+    p_weight NUMBER,
+    p_amount NUMBER,
+    p_currency VARCHAR2,
+    p_payment_method VARCHAR2,
+    p_card_last4 VARCHAR2,
+    p_paid_at TIMESTAMP,
+    p_created_by VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO BAGGAGE_FEE ("ID", BAGGAGE, AIRPORTUSER, FLIGHT, REASON, WEIGHT, AMOUNT, CURRENCY, PAYMENT_METHOD,
+        CARD_LAST4, PAID_AT, CREATED_BY)
+    VALUES (p_id, p_baggage, p_user, p_flight, p_reason, p_weight, p_amount, p_currency, p_payment_method,
+        p_card_last4, p_paid_at, p_created_by);
+END;
+/
+
+-- Sum of all paid excess baggage fees
+CREATE OR REPLACE PROCEDURE CalculateBaggageFeeRevenue(
+    total_revenue OUT NUMBER
+)
+AS
+BEGIN
+    SELECT COALESCE(SUM(AMOUNT), 0) INTO total_revenue FROM BAGGAGE_FEE;
+END;
+/
+
+/*==============================================================*/
+/* Invoice Procedures                                           */
+/*==============================================================*/
+
+-- Draw the next invoice number of a year. The sequence row stays locked
+-- until the caller's transaction ends, so numbers are gap-free as long as
+-- the invoice is created in the same transaction.
+CREATE OR REPLACE PROCEDURE NextInvoiceNumber(
+    p_year NUMBER,
+    p_number OUT NUMBER
+)
+AS
+BEGIN
+    UPDATE INVOICE_SEQUENCE SET LAST_NUMBER = LAST_NUMBER + 1
+    WHERE INVOICE_YEAR = p_year
+    RETURNING LAST_NUMBER INTO p_number;
+
+    IF SQL%ROWCOUNT = 0 THEN
+        BEGIN
+            INSERT INTO INVOICE_SEQUENCE (INVOICE_YEAR, LAST_NUMBER) VALUES (p_year, 1);
+            p_number := 1;
+        EXCEPTION
+            WHEN DUP_VAL_ON_INDEX THEN
+                UPDATE INVOICE_SEQUENCE SET LAST_NUMBER = LAST_NUMBER + 1
+                WHERE INVOICE_YEAR = p_year
+                RETURNING LAST_NUMBER INTO p_number;
+        END;
+    END IF;
+END;
+/
+
+-- Insert an invoice or credit note without its lines
+CREATE OR REPLACE PROCEDURE CreateInvoice(
+    p_id VARCHAR2,
+    p_invoice_number VARCHAR2,
+    p_year NUMBER,
+    p_sequence_number NUMBER,
+    p_type VARCHAR2,
+    p_issuer VARCHAR2,
+    p_user VARCHAR2,
+    p_booking VARCHAR2,
+    p_payment VARCHAR2,
+    p_refund VARCHAR2,
+    p_baggage_fee VARCHAR2,
+    p_corrects VARCHAR2,
+    p_issued_at TIMESTAMP,
+    p_service_date DATE,
+    p_currency VARCHAR2,
+    p_net_amount NUMBER,
+    p_vat_amount NUMBER,
+    p_gross_amount NUMBER,
+    p_payment_method VARCHAR2,
+    p_card_last4 VARCHAR2,
+    p_buyer_name VARCHAR2,
+    p_buyer_company VARCHAR2,
+    p_buyer_street VARCHAR2,
+    p_buyer_postcode VARCHAR2,
+    p_buyer_city VARCHAR2,
+    p_buyer_country VARCHAR2,
+    p_buyer_vat_id VARCHAR2,
+    p_buyer_reference VARCHAR2,
+    p_buyer_email VARCHAR2,
+    p_note VARCHAR2,
+    p_created_by VARCHAR2
+)
+AS
+BEGIN
+    INSERT INTO INVOICE ("ID", INVOICE_NUMBER, INVOICE_YEAR, SEQUENCE_NUMBER, TYPE, ISSUER, AIRPORTUSER, BOOKING,
+        PAYMENT, REFUND, BAGGAGE_FEE, CORRECTS, ISSUED_AT, SERVICE_DATE, CURRENCY, NET_AMOUNT, VAT_AMOUNT,
+        GROSS_AMOUNT, PAYMENT_METHOD, CARD_LAST4, BUYER_NAME, BUYER_COMPANY, BUYER_STREET, BUYER_POSTCODE,
+        BUYER_CITY, BUYER_COUNTRY, BUYER_VAT_ID, BUYER_REFERENCE, BUYER_EMAIL, NOTE, CREATED_BY)
+    VALUES (p_id, p_invoice_number, p_year, p_sequence_number, p_type, p_issuer, p_user, p_booking,
+        p_payment, p_refund, p_baggage_fee, p_corrects, p_issued_at, p_service_date, p_currency, p_net_amount, p_vat_amount,
+        p_gross_amount, p_payment_method, p_card_last4, p_buyer_name, p_buyer_company, p_buyer_street, p_buyer_postcode,
+        p_buyer_city, p_buyer_country, p_buyer_vat_id, p_buyer_reference, p_buyer_email, p_note, p_created_by);
+END;
+/
+
+-- Add a line to an invoice or credit note
+CREATE OR REPLACE PROCEDURE AddInvoiceLine(
+    p_invoice VARCHAR2,
+    p_line_no NUMBER,
+    p_description VARCHAR2,
+    p_quantity NUMBER,
+    p_net_amount NUMBER,
+    p_vat_category VARCHAR2,
+    p_vat_rate NUMBER,
+    p_vat_amount NUMBER,
+    p_gross_amount NUMBER,
+    p_ticket VARCHAR2,
+    p_baggage_fee VARCHAR2,
+    p_corrects_line NUMBER
+)
+AS
+BEGIN
+    INSERT INTO INVOICE_LINE (INVOICE, LINE_NO, DESCRIPTION, QUANTITY, NET_AMOUNT, VAT_CATEGORY, VAT_RATE,
+        VAT_AMOUNT, GROSS_AMOUNT, TICKET, BAGGAGE_FEE, CORRECTS_LINE)
+    VALUES (p_invoice, p_line_no, p_description, p_quantity, p_net_amount, p_vat_category, p_vat_rate,
+        p_vat_amount, p_gross_amount, p_ticket, p_baggage_fee, p_corrects_line);
+END;
+/
+
+-- Get an invoice or credit note by ID
+CREATE OR REPLACE PROCEDURE GetInvoiceByID(
+    p_id VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        I.ID,
+        I.INVOICE_NUMBER,
+        I.TYPE,
+        I.ISSUER,
+        I.AIRPORTUSER,
+        I.BOOKING,
+        BOOKING.LOCATOR,
+        I.PAYMENT,
+        I.REFUND,
+        I.BAGGAGE_FEE,
+        I.CORRECTS,
+        C.INVOICE_NUMBER AS CORRECTS_NUMBER,
+        I.ISSUED_AT,
+        I.SERVICE_DATE,
+        I.CURRENCY,
+        I.NET_AMOUNT,
+        I.VAT_AMOUNT,
+        I.GROSS_AMOUNT,
+        (SELECT COALESCE(SUM(CN.GROSS_AMOUNT), 0) FROM INVOICE CN WHERE CN.CORRECTS = I.ID) AS CREDITED_AMOUNT,
+        I.PAYMENT_METHOD,
+        I.CARD_LAST4,
+        I.BUYER_NAME,
+        I.BUYER_COMPANY,
+        I.BUYER_STREET,
+        I.BUYER_POSTCODE,
+        I.BUYER_CITY,
+        I.BUYER_COUNTRY,
+        I.BUYER_VAT_ID,
+        I.BUYER_REFERENCE,
+        I.BUYER_EMAIL,
+        I.NOTE,
+        I.CREATED_BY
+    FROM INVOICE I
+    LEFT JOIN BOOKING ON I.BOOKING = BOOKING.ID
+    LEFT JOIN INVOICE C ON I.CORRECTS = C.ID
+    WHERE I.ID = p_id;
+END;
+/
+
+-- Get the invoices and credit notes of a user, newest first
+CREATE OR REPLACE PROCEDURE GetInvoicesByUser(
+    p_user VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        I.ID,
+        I.INVOICE_NUMBER,
+        I.TYPE,
+        I.ISSUER,
+        I.AIRPORTUSER,
+        I.BOOKING,
+        BOOKING.LOCATOR,
+        I.PAYMENT,
+        I.REFUND,
+        I.BAGGAGE_FEE,
+        I.CORRECTS,
+        C.INVOICE_NUMBER AS CORRECTS_NUMBER,
+        I.ISSUED_AT,
+        I.SERVICE_DATE,
+        I.CURRENCY,
+        I.NET_AMOUNT,
+        I.VAT_AMOUNT,
+        I.GROSS_AMOUNT,
+        (SELECT COALESCE(SUM(CN.GROSS_AMOUNT), 0) FROM INVOICE CN WHERE CN.CORRECTS = I.ID) AS CREDITED_AMOUNT,
+        I.PAYMENT_METHOD,
+        I.CARD_LAST4,
+        I.BUYER_NAME,
+        I.BUYER_COMPANY,
+        I.BUYER_STREET,
+        I.BUYER_POSTCODE,
+        I.BUYER_CITY,
+        I.BUYER_COUNTRY,
+        I.BUYER_VAT_ID,
+        I.BUYER_REFERENCE,
+        I.BUYER_EMAIL,
+        I.NOTE,
+        I.CREATED_BY
+    FROM INVOICE I
+    LEFT JOIN BOOKING ON I.BOOKING = BOOKING.ID
+    LEFT JOIN INVOICE C ON I.CORRECTS = C.ID
+    WHERE I.AIRPORTUSER = p_user
+    ORDER BY I.ISSUED_AT DESC, I.INVOICE_NUMBER DESC;
+END;
+/
+
+-- Get the invoices and credit notes issued for a payment, refund or
+-- excess baggage fee, oldest first
+CREATE OR REPLACE PROCEDURE GetInvoicesBySource(
+    p_payment VARCHAR2,
+    p_refund VARCHAR2,
+    p_baggage_fee VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        I.ID,
+        I.INVOICE_NUMBER,
+        I.TYPE,
+        I.ISSUER,
+        I.AIRPORTUSER,
+        I.BOOKING,
+        BOOKING.LOCATOR,
+        I.PAYMENT,
+        I.REFUND,
+        I.BAGGAGE_FEE,
+        I.CORRECTS,
+        C.INVOICE_NUMBER AS CORRECTS_NUMBER,
+        I.ISSUED_AT,
+        I.SERVICE_DATE,
+        I.CURRENCY,
+        I.NET_AMOUNT,
+        I.VAT_AMOUNT,
+        I.GROSS_AMOUNT,
+        (SELECT COALESCE(SUM(CN.GROSS_AMOUNT), 0) FROM INVOICE CN WHERE CN.CORRECTS = I.ID) AS CREDITED_AMOUNT,
+        I.PAYMENT_METHOD,
+        I.CARD_LAST4,
+        I.BUYER_NAME,
+        I.BUYER_COMPANY,
+        I.BUYER_STREET,
+        I.BUYER_POSTCODE,
+        I.BUYER_CITY,
+        I.BUYER_COUNTRY,
+        I.BUYER_VAT_ID,
+        I.BUYER_REFERENCE,
+        I.BUYER_EMAIL,
+        I.NOTE,
+        I.CREATED_BY
+    FROM INVOICE I
+    LEFT JOIN BOOKING ON I.BOOKING = BOOKING.ID
+    LEFT JOIN INVOICE C ON I.CORRECTS = C.ID
+    WHERE (p_payment IS NOT NULL AND I.PAYMENT = p_payment)
+       OR (p_refund IS NOT NULL AND I.REFUND = p_refund)
+       OR (p_baggage_fee IS NOT NULL AND I.BAGGAGE_FEE = p_baggage_fee)
+    ORDER BY I.ISSUED_AT, I.INVOICE_NUMBER;
+END;
+/
+
+-- Get the credit notes correcting an invoice, oldest first
+CREATE OR REPLACE PROCEDURE GetCreditNotes(
+    p_invoice VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        I.ID,
+        I.INVOICE_NUMBER,
+        I.TYPE,
+        I.ISSUER,
+        I.AIRPORTUSER,
+        I.BOOKING,
+        BOOKING.LOCATOR,
+        I.PAYMENT,
+        I.REFUND,
+        I.BAGGAGE_FEE,
+        I.CORRECTS,
+        C.INVOICE_NUMBER AS CORRECTS_NUMBER,
+        I.ISSUED_AT,
+        I.SERVICE_DATE,
+        I.CURRENCY,
+        I.NET_AMOUNT,
+        I.VAT_AMOUNT,
+        I.GROSS_AMOUNT,
+        (SELECT COALESCE(SUM(CN.GROSS_AMOUNT), 0) FROM INVOICE CN WHERE CN.CORRECTS = I.ID) AS CREDITED_AMOUNT,
+        I.PAYMENT_METHOD,
+        I.CARD_LAST4,
+        I.BUYER_NAME,
+        I.BUYER_COMPANY,
+        I.BUYER_STREET,
+        I.BUYER_POSTCODE,
+        I.BUYER_CITY,
+        I.BUYER_COUNTRY,
+        I.BUYER_VAT_ID,
+        I.BUYER_REFERENCE,
+        I.BUYER_EMAIL,
+        I.NOTE,
+        I.CREATED_BY
+    FROM INVOICE I
+    LEFT JOIN BOOKING ON I.BOOKING = BOOKING.ID
+    LEFT JOIN INVOICE C ON I.CORRECTS = C.ID
+    WHERE I.CORRECTS = p_invoice
+    ORDER BY I.ISSUED_AT, I.INVOICE_NUMBER;
+END;
+/
+
+-- Search invoices and credit notes; filters left NULL are ignored. The
+-- customer matches name, company or email of the buyer in part.
+CREATE OR REPLACE PROCEDURE SearchInvoices(
+    p_number VARCHAR2,
+    p_locator VARCHAR2,
+    p_user VARCHAR2,
+    p_customer VARCHAR2,
+    p_type VARCHAR2,
+    p_from TIMESTAMP,
+    p_to TIMESTAMP,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT
+        I.ID,
+        I.INVOICE_NUMBER,
+        I.TYPE,
+        I.ISSUER,
+        I.AIRPORTUSER,
+        I.BOOKING,
+        BOOKING.LOCATOR,
+        I.PAYMENT,
+        I.REFUND,
+        I.BAGGAGE_FEE,
+        I.CORRECTS,
+        C.INVOICE_NUMBER AS CORRECTS_NUMBER,
+        I.ISSUED_AT,
+        I.SERVICE_DATE,
+        I.CURRENCY,
+        I.NET_AMOUNT,
+        I.VAT_AMOUNT,
+        I.GROSS_AMOUNT,
+        (SELECT COALESCE(SUM(CN.GROSS_AMOUNT), 0) FROM INVOICE CN WHERE CN.CORRECTS = I.ID) AS CREDITED_AMOUNT,
+        I.PAYMENT_METHOD,
+        I.CARD_LAST4,
+        I.BUYER_NAME,
+        I.BUYER_COMPANY,
+        I.BUYER_STREET,
+        I.BUYER_POSTCODE,
+        I.BUYER_CITY,
+        I.BUYER_COUNTRY,
+        I.BUYER_VAT_ID,
+        I.BUYER_REFERENCE,
+        I.BUYER_EMAIL,
+        I.NOTE,
+        I.CREATED_BY
+    FROM INVOICE I
+    LEFT JOIN BOOKING ON I.BOOKING = BOOKING.ID
+    LEFT JOIN INVOICE C ON I.CORRECTS = C.ID
+    WHERE (p_number IS NULL OR I.INVOICE_NUMBER = UPPER(p_number))
+      AND (p_locator IS NULL OR BOOKING.LOCATOR = UPPER(p_locator))
+      AND (p_user IS NULL OR I.AIRPORTUSER = p_user)
+      AND (p_customer IS NULL
+           OR UPPER(I.BUYER_NAME) LIKE '%' || UPPER(p_customer) || '%'
+           OR UPPER(I.BUYER_COMPANY) LIKE '%' || UPPER(p_customer) || '%'
+           OR UPPER(I.BUYER_EMAIL) LIKE '%' || UPPER(p_customer) || '%')
+      AND (p_type IS NULL OR I.TYPE = p_type)
+      AND (p_from IS NULL OR I.ISSUED_AT >= p_from)
+      AND (p_to IS NULL OR I.ISSUED_AT < p_to)
+    ORDER BY I.ISSUED_AT DESC, I.INVOICE_NUMBER DESC;
+END;
+/
+
+-- Get the lines of an invoice or credit note
+CREATE OR REPLACE PROCEDURE GetInvoiceLines(
+    p_invoice VARCHAR2,
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT LINE_NO, DESCRIPTION, QUANTITY, NET_AMOUNT, VAT_CATEGORY, VAT_RATE, VAT_AMOUNT, GROSS_AMOUNT,
+        TICKET, BAGGAGE_FEE, CORRECTS_LINE
+    FROM INVOICE_LINE
+    WHERE INVOICE = p_invoice
+    ORDER BY LINE_NO;
+END;
+/
+
+-- Get captured payments, succeeded refunds and excess baggage fees that no
+-- invoice or credit note was issued for yet, oldest first
+CREATE OR REPLACE PROCEDURE GetUninvoicedSources(
+    result_cursor OUT SYS_REFCURSOR
+)
+AS
+BEGIN
+    OPEN result_cursor FOR
+    SELECT SOURCE, ID, PAYMENT FROM (
+        SELECT 'PAYMENT' AS SOURCE, PAYMENT.ID, PAYMENT.ID AS PAYMENT, PAYMENT.CAPTURED_AT AS OCCURRED_AT
+        FROM PAYMENT
+        WHERE PAYMENT.CAPTURED_AT IS NOT NULL
+          AND NOT EXISTS (SELECT 1 FROM INVOICE WHERE INVOICE.PAYMENT = PAYMENT.ID)
+        UNION ALL
+        SELECT 'REFUND', PAYMENT_REFUND.ID, PAYMENT_REFUND.PAYMENT, PAYMENT_REFUND.CREATED_AT
+        FROM PAYMENT_REFUND
+        WHERE PAYMENT_REFUND.STATUS = 'SUCCEEDED' AND PAYMENT_REFUND.AMOUNT > 0
+          AND NOT EXISTS (SELECT 1 FROM INVOICE WHERE INVOICE.REFUND = PAYMENT_REFUND.ID)
+        UNION ALL
+        SELECT 'BAGGAGE_FEE', BAGGAGE_FEE.ID, NULL, BAGGAGE_FEE.PAID_AT
+        FROM BAGGAGE_FEE
+        WHERE NOT EXISTS (SELECT 1 FROM INVOICE WHERE INVOICE.BAGGAGE_FEE = BAGGAGE_FEE.ID)
+    )
+    ORDER BY OCCURRED_AT;
+END;
+/